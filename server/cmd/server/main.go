@@ -48,6 +48,7 @@ import (
 	"llm-router-platform/internal/service/proxy"
 	"llm-router-platform/internal/service/redeem"
 	"llm-router-platform/internal/service/router"
+	"llm-router-platform/internal/service/shadow"
 	"llm-router-platform/internal/service/task"
 	"llm-router-platform/internal/service/turnstile"
 	"llm-router-platform/internal/service/user"
@@ -197,7 +198,9 @@ func NewApplication() (*Application, error) {
 // seeds default data (respecting release-mode guards).
 func (app *Application) InitInfrastructure() error {
 	// ── Database ─────────────────────────────────────────────────────────
-	db, err := database.New(&app.cfg.Database, app.cfg.Server.Mode, app.logger)
+	// Retry the connection so a database that is still starting does not
+	// fail the boot, and nothing below runs against an unreachable DB.
+	db, err := database.Connect(context.Background(), &app.cfg.Database, app.cfg.Server.Mode, app.logger)
 	if err != nil {
 		return err
 	}
@@ -226,9 +229,10 @@ func (app *Application) InitInfrastructure() error {
 	return nil
 }
 
-// seedData populates default data.  Only the admin account is ensured on
-// startup.  Providers, models, and plans must be configured manually by the
-// administrator through the management UI.
+// seedData populates default data.  The admin account is always ensured on
+// startup.  Default providers and models are seeded only when SEED_DEFAULTS is
+// on (the default outside release mode); otherwise they, like plans, must be
+// configured by the administrator through the management UI.
 func (app *Application) seedData() {
 	if app.cfg.Server.Mode == "release" {
 		if err := app.db.SeedDefaultAdminOnly(&app.cfg.Admin); err != nil {
@@ -239,11 +243,26 @@ func (app *Application) seedData() {
 			app.logger.Error("failed to seed admin user", zap.Error(err))
 		}
 	}
-	app.logger.Info("provider/model/plan seed data skipped: configure via admin UI")
+	if !app.cfg.Server.SeedDefaults {
+		app.logger.Info("default provider/model seed data skipped (SEED_DEFAULTS=false): configure via admin UI or API",
+			zap.String("mode", app.cfg.Server.Mode))
+		return
+	}
+	if err := app.db.SeedDefaultProviders(); err != nil {
+		app.logger.Error("failed to seed default providers", zap.Error(err))
+		return
+	}
+	if err := app.db.SeedDefaultModels(); err != nil {
+		app.logger.Error("failed to seed default models", zap.Error(err))
+		return
+	}
+	app.logger.Info("default providers and models seeded (SEED_DEFAULTS=true)")
 }
 
 // connectRedis creates and tests a Redis connection.  Returns nil (with a
 // warning) if the connection fails — downstream code treats nil as "disabled".
+// The one client is shared by rate limiting, the conversation memory cache,
+// provider key state, the model list cache and feature gate sync.
 func (app *Application) connectRedis() *redis.Client {
 	opts := &redis.Options{
 		Addr:     app.cfg.Redis.GetRedisAddr(),
@@ -257,10 +276,10 @@ func (app *Application) connectRedis() *redis.Client {
 
 	client := redis.NewClient(opts)
 	if err := client.Ping(context.Background()).Err(); err != nil {
-		app.logger.Warn("redis connection failed, rate limiting will be disabled", zap.Error(err))
+		app.logger.Warn("redis connection failed, running without redis: rate limiting, conversation caching and cross-instance sync are disabled", zap.Error(err))
 		return nil
 	}
-	app.logger.Info("redis connected for rate limiting")
+	app.logger.Info("redis connected", zap.String("addr", opts.Addr))
 	return client
 }
 
@@ -289,6 +308,11 @@ func (app *Application) InitServices() {
 func (app *Application) Start() {
 	gin.SetMode(app.cfg.Server.Mode)
 	engine := gin.New()
+	// Only listed proxies may set X-Forwarded-For; nil trusts none, so
+	// ClientIP falls back to the TCP peer address.
+	if err := engine.SetTrustedProxies(app.cfg.Server.TrustedProxies); err != nil {
+		app.logger.Fatal("invalid trusted proxies", zap.Error(err))
+	}
 
 	// Sentry must be initialized before middleware registration
 	if err := observability.InitSentry(app.cfg.Observability, app.logger); err != nil {
@@ -327,8 +351,9 @@ func (app *Application) startBackgroundJobs() {
 
 	// Health check scheduler
 	if app.cfg.HealthCheck.Enabled {
-		alertNotifier := health.NewAlertNotifier(app.repos.Alert, app.repos.AlertConfig, app.logger, app.cfg.Server.AllowLocalProviders)
+		alertNotifier := newAlertNotifier(app.repos, app.cfg, app.logger)
 		scheduler := health.NewScheduler(app.services.Health, alertNotifier, app.cfg.HealthCheck.Interval, app.logger)
+		scheduler.SetLatencyPenalizer(app.services.Router, app.cfg.HealthCheck.LatencySLAWeightFactor)
 		go scheduler.Start(lifecycleCtx)
 	}
 
@@ -351,6 +376,20 @@ func (app *Application) startBackgroundJobs() {
 		}
 	}()
 
+	// Retry failed alert webhooks (every 15s; each delivery waits out its own backoff)
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				app.services.Health.RetryAlertDeliveries(lifecycleCtx)
+			case <-lifecycleCtx.Done():
+				return
+			}
+		}
+	}()
+
 	// Periodic data cleanup (daily)
 	go func() {
 		ticker := time.NewTicker(24 * time.Hour)
@@ -366,7 +405,8 @@ func (app *Application) startBackgroundJobs() {
 	}()
 }
 
-// runDataCleanup purges old health history, alerts, and audit logs based on
+// runDataCleanup purges old health history, alerts, audit logs, failed
+// request records, shadow logs and finished alert deliveries based on
 // configurable retention periods.
 func (app *Application) runDataCleanup() {
 	if n, err := app.db.CleanupOldHealthHistory(app.cfg.Cleanup.HealthRetentionDays); err != nil {
@@ -385,6 +425,30 @@ func (app *Application) runDataCleanup() {
 	} else if n > 0 {
 		app.logger.Info("audit log cleanup completed", zap.Int64("deleted", n))
 	}
+	cutoff := time.Now().AddDate(0, 0, -app.cfg.Cleanup.FailedRequestRetentionDays)
+	if n, err := app.repos.FailedRequest.DeleteOlderThan(context.Background(), cutoff); err != nil {
+		app.logger.Error("failed request cleanup failed", zap.Error(err))
+	} else if n > 0 {
+		app.logger.Info("failed request cleanup completed", zap.Int64("deleted", n))
+	}
+	cutoff = time.Now().AddDate(0, 0, -app.cfg.Cleanup.FailedRequestCaptureDays)
+	if n, err := app.repos.FailedRequest.ClearCapturesOlderThan(context.Background(), cutoff); err != nil {
+		app.logger.Error("failed request capture cleanup failed", zap.Error(err))
+	} else if n > 0 {
+		app.logger.Info("failed request capture cleanup completed", zap.Int64("cleared", n))
+	}
+	cutoff = time.Now().AddDate(0, 0, -app.cfg.Cleanup.ShadowLogRetentionDays)
+	if n, err := app.repos.ShadowLog.DeleteOlderThan(context.Background(), cutoff); err != nil {
+		app.logger.Error("shadow log cleanup failed", zap.Error(err))
+	} else if n > 0 {
+		app.logger.Info("shadow log cleanup completed", zap.Int64("deleted", n))
+	}
+	cutoff = time.Now().AddDate(0, 0, -app.cfg.Cleanup.AlertDeliveryRetentionDays)
+	if n, err := app.repos.AlertDelivery.DeleteFinishedBefore(context.Background(), cutoff); err != nil {
+		app.logger.Error("alert delivery cleanup failed", zap.Error(err))
+	} else if n > 0 {
+		app.logger.Info("alert delivery cleanup completed", zap.Int64("deleted", n))
+	}
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -459,6 +523,7 @@ type Repositories struct {
 	Alert          *repository.AlertRepository
 	AlertConfig    *repository.AlertConfigRepository
 	Budget         *repository.BudgetRepository
+	SpendAlert     *repository.SpendAlertRepository
 	Task           *repository.TaskRepository
 	AuditLog       *repository.AuditLogRepository
 	MCP            *repository.MCPRepository
@@ -467,7 +532,12 @@ type Repositories struct {
 	Transaction    *repository.TransactionRepository
 	Config         *repository.ConfigRepository
 	RoutingRule    repository.RoutingRuleRepo
+	FallbackChain  repository.FallbackChainRepo
+	RouteOverride  repository.ModelRouteOverrideRepo
+	AlertDelivery  repository.AlertDeliveryRepo
 	Webhook        repository.WebhookRepository
+	ShadowLog      *repository.ShadowLogRepository
+	FailedRequest  *repository.FailedRequestRepository
 }
 
 func initRepositories(db *database.Database, cfg *config.Config) *Repositories {
@@ -486,6 +556,7 @@ func initRepositories(db *database.Database, cfg *config.Config) *Repositories {
 		Alert:          repository.NewAlertRepository(db.DB),
 		AlertConfig:    repository.NewAlertConfigRepository(db.DB),
 		Budget:         repository.NewBudgetRepository(db.DB),
+		SpendAlert:     repository.NewSpendAlertRepository(db.DB),
 		Task:           repository.NewTaskRepository(db.DB),
 		AuditLog:       repository.NewAuditLogRepository(db.DB, cfg.Encryption.Key),
 		MCP:            repository.NewMCPRepository(db.DB),
@@ -494,10 +565,28 @@ func initRepositories(db *database.Database, cfg *config.Config) *Repositories {
 		Transaction:    repository.NewTransactionRepository(db.DB),
 		Config:         repository.NewConfigRepository(db.DB),
 		RoutingRule:    repository.NewRoutingRuleRepository(db.DB),
+		FallbackChain:  repository.NewFallbackChainRepository(db.DB),
+		RouteOverride:  repository.NewModelRouteOverrideRepository(db.DB),
+		AlertDelivery:  repository.NewAlertDeliveryRepository(db.DB),
 		Webhook:        repository.NewWebhookRepository(db.DB),
+		ShadowLog:      repository.NewShadowLogRepository(db.DB),
+		FailedRequest:  repository.NewFailedRequestRepository(db.DB),
 	}
 }
 
+// newAlertNotifier builds an alert notifier that signs its webhooks and queues
+// failed deliveries for retry.
+func newAlertNotifier(repos *Repositories, cfg *config.Config, logger *zap.Logger) *health.AlertNotifier {
+	n := health.NewAlertNotifier(repos.Alert, repos.AlertConfig, logger, cfg.Server.AllowLocalProviders)
+	n.SetWebhookSecret(cfg.Alert.WebhookSecret)
+	n.SetDeliveryQueue(repos.AlertDelivery, health.DeliveryRetryConfig{
+		MaxAttempts: cfg.Alert.RetryMaxAttempts,
+		BaseDelay:   cfg.Alert.RetryBaseDelay,
+		MaxDelay:    cfg.Alert.RetryMaxDelay,
+	})
+	return n
+}
+
 func initServices(repos *Repositories, cfg *config.Config, logger *zap.Logger, redisClient *redis.Client, gormDB *gorm.DB) *routes.Services {
 	userService := user.NewService(repos.User, repos.APIKey, repos.Project, repos.Organization, logger)
 
@@ -514,10 +603,29 @@ func initServices(repos *Repositories, cfg *config.Config, logger *zap.Logger, r
 		cfgService.SetRedis(redisClient)
 		cfgService.StartFGSubscriber(context.Background(), cfg.FeatureGates)
 	}
-	routerService := router.NewRouter(repos.Provider, repos.ProviderAPIKey, repos.Proxy, repos.Model, repos.RoutingRule, providerRegistry, mcpService, logger, cfg.Server.AllowLocalProviders)
+	routerService := router.NewRouter(repos.Provider, repos.ProviderAPIKey, repos.Proxy, repos.Model, repos.RoutingRule, repos.FallbackChain, providerRegistry, mcpService, logger, cfg.Server.AllowLocalProviders)
 	if redisClient != nil {
 		routerService.SetRedisClient(redisClient)
 	}
+	routerService.SetQuotaKeywords(cfg.Router.QuotaKeywords, cfg.Router.ProviderQuotaKeywords)
+	routerService.SetUnknownModelPolicy(router.UnknownModelPolicy(cfg.Router.UnknownModelPolicy), cfg.Router.CatchAllProvider)
+	routerService.SetHTTPPoolLimits(cfg.Router.MaxIdleConnsPerHost, time.Duration(cfg.Router.IdleConnTimeoutSecs)*time.Second)
+	routerService.SetModelListCache(time.Duration(cfg.Router.ModelListCacheTTLSecs)*time.Second, cfg.Router.ModelListCacheRedis)
+	routerService.SetKeyRetryBackoff(router.KeyRetryBackoff{
+		Initial: time.Duration(cfg.Router.KeyRetryBackoffMs) * time.Millisecond,
+		Max:     time.Duration(cfg.Router.KeyRetryBackoffMaxMs) * time.Millisecond,
+		Budget:  time.Duration(cfg.Router.KeyRetryBudgetMs) * time.Millisecond,
+	})
+	routerService.SetKeySelection(router.KeySelection(cfg.Router.KeySelection))
+	routerService.SetProviderQueue(router.ProviderQueueConfig{
+		MaxConcurrent: cfg.Router.ProviderMaxConcurrent,
+		Depth:         cfg.Router.ProviderQueueDepth,
+		MaxWait:       time.Duration(cfg.Router.ProviderQueueMaxWaitMs) * time.Millisecond,
+	})
+	routerService.SetUsageRepo(repos.UsageLog)
+	routerService.SetRouteOverrideRepo(repos.RouteOverride)
+	routerService.SetModelTiers(cfg.Router.ModelTierAliases, cfgService)
+	shadowService := shadow.NewService(routerService, repos.ShadowLog, repos.Model, cfg.Shadow, logger)
 	billingService := billing.NewService(repos.UsageLog, repos.Model, redisClient, logger)
 	budgetService := billing.NewBudgetService(repos.UsageLog, repos.Budget, logger)
 	subscriptionService := billing.NewSubscriptionService(repos.Plan, repos.Subscription, repos.UsageLog, logger)
@@ -532,6 +640,7 @@ func initServices(repos *Repositories, cfg *config.Config, logger *zap.Logger, r
 	alipayService := billing.NewAlipayService(cfg.Alipay, cfg.Frontend.URL, repos.Subscription, repos.Transaction, logger)
 
 	memoryService := memory.NewService(repos.Memory, redisClient, logger)
+	memoryService.SetMaxMessages(cfg.Memory.MaxMessages)
 	proxyService := proxy.NewService(repos.Proxy, logger)
 	obsService := observability.NewCompositeService(
 		observability.NewLangfuseService(cfg.Observability, logger),
@@ -539,12 +648,15 @@ func initServices(repos *Repositories, cfg *config.Config, logger *zap.Logger, r
 	)
 	auditService := audit.NewService(repos.AuditLog, logger)
 
-	alertNotifier := health.NewAlertNotifier(repos.Alert, repos.AlertConfig, logger, cfg.Server.AllowLocalProviders)
+	alertNotifier := newAlertNotifier(repos, cfg, logger)
+	billingService.SetKeySpendNotifier(billing.NewKeySpendNotifier(repos.APIKey, repos.UsageLog, repos.SpendAlert, alertNotifier, logger))
+	routerService.SetFailoverAlerter(alertNotifier)
 	healthService := health.NewService(
 		repos.APIKey, repos.ProviderAPIKey, repos.Proxy, repos.Provider,
 		repos.HealthHistory, alertNotifier, providerRegistry, proxyService, logger,
 		cfg.Server.AllowLocalProviders,
 	)
+	healthService.SetClientBuilder(routerService)
 
 	taskService := task.NewService(repos.Task, logger, cfg.Server.AllowLocalProviders)
 	redeemService := redeem.NewService(gormDB, logger)
@@ -573,6 +685,7 @@ func initServices(repos *Repositories, cfg *config.Config, logger *zap.Logger, r
 		EmailVerifySvc:   emailVerifySvc,
 		LoginLimiter:     loginLimiter,
 		Router:           routerService,
+		Shadow:           shadowService,
 		Billing:          billingService,
 		BudgetService:    budgetService,
 		Subscription:     subscriptionService,
@@ -597,6 +710,7 @@ func initServices(repos *Repositories, cfg *config.Config, logger *zap.Logger, r
 		MonitoringSvc:    monitoringSvc,
 		TurnstileSvc:     turnstileSvc,
 		SemanticCache:    cacheSvc,
+		FailedRequests:   repos.FailedRequest,
 		MCP:              mcpService,
 		RedisClient:      redisClient,
 		DB:               gormDB,
@@ -0,0 +1,222 @@
+// Package handlers provides HTTP request handlers.
+// This file contains admin read-only views of another user's dashboard data.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/audit"
+	"llm-router-platform/internal/service/billing"
+	"llm-router-platform/internal/service/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AdminDashboardHandler lets admins read a customer's dashboard data for
+// support. Every read is recorded in the audit trail.
+type AdminDashboardHandler struct {
+	userSvc      *user.Service
+	billing      *billing.Service
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewAdminDashboardHandler creates a new admin dashboard handler.
+func NewAdminDashboardHandler(userSvc *user.Service, billingSvc *billing.Service, auditService *audit.Service, logger *zap.Logger) *AdminDashboardHandler {
+	return &AdminDashboardHandler{userSvc: userSvc, billing: billingSvc, auditService: auditService, logger: logger}
+}
+
+// target resolves the user_id (and optional org_id) query parameters to the
+// organization whose usage is shown, mirroring how the user's own dashboard
+// picks it, and records the read. It writes the error response itself and
+// returns ok=false on failure.
+func (h *AdminDashboardHandler) target(c *gin.Context) (orgID uuid.UUID, ok bool) {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid user_id is required"})
+		return uuid.Nil, false
+	}
+	var wantOrg uuid.UUID
+	if raw := c.Query("org_id"); raw != "" {
+		if wantOrg, err = uuid.Parse(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid org_id"})
+			return uuid.Nil, false
+		}
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.userSvc.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		} else {
+			h.logger.Error("failed to load user", zap.String("user_id", userID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load user"})
+		}
+		return uuid.Nil, false
+	}
+	orgs, err := h.userSvc.GetOrganizations(ctx, userID)
+	if err != nil || len(orgs) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no organization found for user"})
+		return uuid.Nil, false
+	}
+	orgID = orgs[0].ID
+	if wantOrg != uuid.Nil {
+		orgID = uuid.Nil
+		for _, o := range orgs {
+			if o.ID == wantOrg {
+				orgID = o.ID
+				break
+			}
+		}
+		if orgID == uuid.Nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user is not a member of org_id"})
+			return uuid.Nil, false
+		}
+	}
+
+	if h.auditService != nil {
+		actorID, _ := uuid.Parse(c.GetString("user_id"))
+		h.auditService.Log(ctx, audit.ActionImpersonateRead, actorID, userID, c.ClientIP(), c.Request.UserAgent(), map[string]interface{}{
+			"endpoint": c.FullPath(),
+			"org_id":   orgID.String(),
+		})
+	}
+	return orgID, true
+}
+
+// Stats godoc
+// @Summary View a user's dashboard summary
+// @Description Month-to-date and today's usage for the user's organization (or org_id). Audited.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string true "User ID"
+// @Param org_id query string false "Organization ID (defaults to the user's first organization)"
+// @Router /api/v1/admin/dashboard/stats [get]
+func (h *AdminDashboardHandler) Stats(c *gin.Context) {
+	orgID, ok := h.target(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	month, err := h.billing.GetUsageSummary(ctx, orgID, nil, nil, monthStart, now)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	today, err := h.billing.GetUsageSummary(ctx, orgID, nil, nil, todayStart, now)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"org_id": orgID, "month": month, "today": today})
+}
+
+// DailyUsage godoc
+// @Summary View a user's daily usage
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string true "User ID"
+// @Param org_id query string false "Organization ID"
+// @Param period query string false "Shorthand window ending now: 7d, 30d, mtd, ... (default 30d)"
+// @Param start query string false "Start (RFC 3339 or YYYY-MM-DD)"
+// @Param end query string false "End (RFC 3339, or YYYY-MM-DD inclusive)"
+// @Param days query int false "Deprecated: days of history, same as period=<days>d"
+// @Router /api/v1/admin/dashboard/usage/daily [get]
+func (h *AdminDashboardHandler) DailyUsage(c *gin.Context) {
+	defaultPeriod := "30d"
+	if days, err := strconv.Atoi(c.Query("days")); err == nil && days > 0 && days <= 365 {
+		defaultPeriod = strconv.Itoa(days) + "d"
+	}
+	start, end, err := usageRange(c, time.Now(), defaultPeriod)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	orgID, ok := h.target(c)
+	if !ok {
+		return
+	}
+	usage, err := h.billing.GetDailyUsageRange(c.Request.Context(), orgID, nil, nil, start, end)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"org_id": orgID, "start": start, "end": end, "data": usage})
+}
+
+// UsageByProvider godoc
+// @Summary View a user's usage by provider
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string true "User ID"
+// @Param org_id query string false "Organization ID"
+// @Param period query string false "Shorthand window ending now: 7d, 30d, mtd, ... (default mtd)"
+// @Param start query string false "Start (RFC 3339 or YYYY-MM-DD)"
+// @Param end query string false "End (RFC 3339, or YYYY-MM-DD inclusive)"
+// @Router /api/v1/admin/dashboard/usage/providers [get]
+func (h *AdminDashboardHandler) UsageByProvider(c *gin.Context) {
+	start, end, err := usageRange(c, time.Now(), periodMonthToDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	orgID, ok := h.target(c)
+	if !ok {
+		return
+	}
+	usage, err := h.billing.GetUsageByProvider(c.Request.Context(), orgID, nil, nil, start, end)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"org_id": orgID, "start": start, "end": end, "data": usage})
+}
+
+// RecentUsage godoc
+// @Summary View a user's recent requests
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string true "User ID"
+// @Param org_id query string false "Organization ID"
+// @Param page query int false "Page (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Router /api/v1/admin/dashboard/usage/recent [get]
+func (h *AdminDashboardHandler) RecentUsage(c *gin.Context) {
+	orgID, ok := h.target(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	logs, total, err := h.billing.GetRecentUsage(c.Request.Context(), orgID, nil, page, pageSize)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"org_id": orgID, "data": logs, "total": total, "page": page, "page_size": pageSize})
+}
+
+func (h *AdminDashboardHandler) internalError(c *gin.Context, err error) {
+	h.logger.Error("admin dashboard read failed", zap.String("path", c.FullPath()), zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load usage"})
+}
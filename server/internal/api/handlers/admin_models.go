@@ -0,0 +1,130 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin endpoints for managing model pricing and capabilities.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"llm-router-platform/internal/service/admin"
+	"llm-router-platform/internal/service/audit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AdminModelHandler lets admins keep model pricing, context windows and
+// capability flags current without a redeploy. Every change is audited.
+type AdminModelHandler struct {
+	adminSvc     *admin.Service
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewAdminModelHandler creates a new admin model handler.
+func NewAdminModelHandler(adminSvc *admin.Service, auditService *audit.Service, logger *zap.Logger) *AdminModelHandler {
+	return &AdminModelHandler{adminSvc: adminSvc, auditService: auditService, logger: logger}
+}
+
+// ModelImportRequest is the body of POST /api/v1/admin/models/import.
+type ModelImportRequest struct {
+	Models []admin.ModelSpec `json:"models" binding:"required"`
+}
+
+// Create godoc
+// @Summary Create a model
+// @Description Registers a model under a provider (provider_id or provider name) with pricing, context window and capability flags.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/models [post]
+func (h *AdminModelHandler) Create(c *gin.Context) {
+	var spec admin.ModelSpec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid model definition"})
+		return
+	}
+	m, err := h.adminSvc.CreateModelFromSpec(c.Request.Context(), spec)
+	if err != nil {
+		h.modelError(c, err)
+		return
+	}
+	h.audit(c, m.ID, map[string]interface{}{"op": "create", "model": m.Name})
+	c.JSON(http.StatusCreated, m)
+}
+
+// Update godoc
+// @Summary Update a model
+// @Description Updates the fields present in the body; omitted fields keep their current values.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Model ID"
+// @Router /api/v1/admin/models/{id} [put]
+func (h *AdminModelHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid model id"})
+		return
+	}
+	var spec admin.ModelSpec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid model definition"})
+		return
+	}
+	m, err := h.adminSvc.UpdateModelFromSpec(c.Request.Context(), id, spec)
+	if err != nil {
+		h.modelError(c, err)
+		return
+	}
+	h.audit(c, m.ID, map[string]interface{}{"op": "update", "model": m.Name})
+	c.JSON(http.StatusOK, m)
+}
+
+// Import godoc
+// @Summary Bulk import a model pricing table
+// @Description Upserts models matched by provider and name. All-or-nothing: one invalid entry rejects the whole import.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/models/import [post]
+func (h *AdminModelHandler) Import(c *gin.Context) {
+	var req ModelImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "models array is required"})
+		return
+	}
+	result, err := h.adminSvc.ImportModels(c.Request.Context(), req.Models)
+	if err != nil {
+		h.modelError(c, err)
+		return
+	}
+	h.audit(c, uuid.Nil, map[string]interface{}{"op": "import", "created": result.Created, "updated": result.Updated})
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AdminModelHandler) modelError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, admin.ErrInvalidModel):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, admin.ErrModelNotFound), errors.Is(err, admin.ErrModelProviderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, admin.ErrModelExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("model update failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save model"})
+	}
+}
+
+func (h *AdminModelHandler) audit(c *gin.Context, modelID uuid.UUID, details map[string]interface{}) {
+	if h.auditService == nil {
+		return
+	}
+	actorID, _ := uuid.Parse(c.GetString("user_id"))
+	h.auditService.Log(c.Request.Context(), audit.ActionModelUpdate, actorID, modelID, c.ClientIP(), c.Request.UserAgent(), details)
+}
@@ -0,0 +1,109 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin endpoints for testing alert webhooks and
+// monitoring their retry queue.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"llm-router-platform/internal/service/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// alertWebhookTestTimeout bounds a single test delivery.
+const alertWebhookTestTimeout = 15 * time.Second
+
+// AlertHandler exposes alert maintenance operations to admins.
+type AlertHandler struct {
+	health *health.Service
+	logger *zap.Logger
+}
+
+// NewAlertHandler creates a new alert handler.
+func NewAlertHandler(h *health.Service, logger *zap.Logger) *AlertHandler {
+	return &AlertHandler{health: h, logger: logger}
+}
+
+// AlertWebhookTestRequest is the body of POST /api/v1/alerts/config/test.
+// Either URL or TargetType+TargetID must be set; URL wins when both are.
+type AlertWebhookTestRequest struct {
+	URL        string `json:"url"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+}
+
+// TestWebhook godoc
+// @Summary Send a test alert to a webhook
+// @Description Posts a sample alert, signed like real alerts, to url or to the webhook configured for target_type/target_id, and reports delivery, response code and latency.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/alerts/config/test [post]
+func (h *AlertHandler) TestWebhook(c *gin.Context) {
+	var req AlertWebhookTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	var targetID uuid.UUID
+	if req.URL == "" {
+		id, err := uuid.Parse(req.TargetID)
+		if err != nil || req.TargetType == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "url or target_type and a valid target_id are required"})
+			return
+		}
+		targetID = id
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), alertWebhookTestTimeout)
+	defer cancel()
+
+	result, err := h.health.TestAlertWebhook(ctx, req.TargetType, targetID, req.URL)
+	switch {
+	case errors.Is(err, health.ErrAlertingDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, health.ErrNoAlertWebhook):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, health.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.logger.Error("failed to test alert webhook", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to test alert webhook"})
+		return
+	}
+
+	h.logger.Info("alert webhook tested",
+		zap.String("actor_id", c.GetString("user_id")),
+		zap.Bool("delivered", result.Delivered),
+		zap.Int("status_code", result.StatusCode),
+	)
+	c.JSON(http.StatusOK, result)
+}
+
+// DeliveryStats godoc
+// @Summary Alert webhook retry queue counts
+// @Description Counts failed alert webhooks that are still pending a retry, were delivered on a retry, or were dead-lettered after running out of attempts.
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/alerts/deliveries/stats [get]
+func (h *AlertHandler) DeliveryStats(c *gin.Context) {
+	stats, err := h.health.AlertDeliveryStats(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to count alert deliveries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load alert delivery stats"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
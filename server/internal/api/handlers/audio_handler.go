@@ -114,6 +114,7 @@ func (h *ChatHandler) TranscribeAudio(c *gin.Context) {
 		ProjectID:   projectObj.ID,
 		APIKeyID:   userAPIKey.ID,
 		ProviderID: selectedProvider.ID,
+		ProviderKeyID: providerKeyID(result.UsedKey),
 		ModelName:  model,
 		Latency:    latency.Milliseconds(),
 		StatusCode: http.StatusOK,
@@ -3,12 +3,18 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"llm-router-platform/internal/api/middleware"
 	"llm-router-platform/internal/models"
 	"llm-router-platform/internal/repository"
 	"llm-router-platform/internal/service/billing"
@@ -18,6 +24,7 @@ import (
 	"llm-router-platform/internal/service/provider"
 	"llm-router-platform/internal/service/router"
 	"llm-router-platform/internal/service/safety"
+	"llm-router-platform/internal/service/shadow"
 	"llm-router-platform/internal/service/tracking"
 	router_errs "llm-router-platform/internal/errors"
 	"llm-router-platform/pkg/sanitize"
@@ -42,11 +49,27 @@ type ChatHandler struct {
 	obsInfo      observability.Service
 	usageRepo    *repository.UsageLogRepository
 	errorLogRepo *repository.ErrorLogRepository
+	userRepo     repository.UserRepo
 	logger       *zap.Logger
 	dispatcher   *tracking.Dispatcher
 	cache        *semantic.SemanticCacheService
 	redis        *redis.Client
 	safety       safety.Classifier
+	stats        *RealtimeStats
+	shadow       *shadow.Service        // nil = shadow traffic disabled
+	budgets      *billing.BudgetService // nil = streams are cut off at the key's MaxRequestCostUSD only
+
+	failedRequests repository.FailedRequestRepo
+	failedLimiter  *failedRequestLimiter // nil = dead-letter log disabled
+	captureFailed  bool                  // also log 4xx upstream failures with their encrypted payload and error bodies
+
+	streamFallback     bool          // serve stream requests via Chat when StreamChat fails to start
+	streamWriteTimeout time.Duration // write deadline applied to SSE responses; 0 = none
+	requestDeadline    time.Duration // total budget for a chat request across retries and fallbacks; 0 = none
+	injectEndUser      bool          // send a hashed API key ID as "user" when the client omits it
+	routingHeaders     bool          // report the serving provider, model and attempts in response headers
+	maxMessages        int           // messages allowed per request; 0 = unlimited, API keys may override
+	maxMessageChars    int           // characters allowed per message; 0 = unlimited, API keys may override
 }
 
 // NewChatHandler creates a new chat handler.
@@ -63,14 +86,53 @@ func NewChatHandler(r *router.Router, b *billing.Service, m *memory.Service, sub
 		obsInfo:      obs,
 		usageRepo:    repository.NewUsageLogRepository(db),
 		errorLogRepo: repository.NewErrorLogRepository(db),
+		userRepo:     repository.NewUserRepository(db),
 		logger:       logger,
 		dispatcher:   tracking.NewDispatcher(db, logger),
 		cache:        cacheService,
 		redis:        redisClient,
 		safety:       safetyClassifier,
+		stats:        NewRealtimeStats(),
 	}
 }
 
+// Stats returns the live load counters maintained by this handler.
+func (h *ChatHandler) Stats() *RealtimeStats {
+	return h.stats
+}
+
+// SetStreamFallback enables degrading a streaming request to a single-chunk
+// SSE response when the provider fails to establish a stream.
+func (h *ChatHandler) SetStreamFallback(enabled bool) {
+	h.streamFallback = enabled
+}
+
+// SetStreamWriteTimeout sets the write deadline for SSE responses, which
+// replaces the server-wide WriteTimeout so long generations are not cut off.
+// Zero removes the deadline entirely.
+func (h *ChatHandler) SetStreamWriteTimeout(d time.Duration) {
+	h.streamWriteTimeout = d
+}
+
+// SetRequestDeadline bounds the total time a chat request may take, including
+// key rotation, retries and provider fallback. Zero disables the deadline.
+func (h *ChatHandler) SetRequestDeadline(d time.Duration) {
+	h.requestDeadline = d
+}
+
+// SetEndUserInjection controls whether requests without a "user" field are
+// sent upstream with a stable hash of the caller's API key ID instead, so
+// providers can attribute abuse to a tenant without learning who it is.
+func (h *ChatHandler) SetEndUserInjection(enabled bool) {
+	h.injectEndUser = enabled
+}
+
+// SetShadow enables mirroring a sample of chat requests to a shadow provider
+// after the client has been answered. nil disables it.
+func (h *ChatHandler) SetShadow(s *shadow.Service) {
+	h.shadow = s
+}
+
 // checkProjectQuota verifies the project's organization hasn't exceeded their quota.
 // Returns nil if within quota, or an error message if exceeded.
 func (h *ChatHandler) checkProjectQuota(c *gin.Context, projectObj *models.Project) *string {
@@ -94,6 +156,8 @@ type AnthropicMessagesRequest struct {
 	Messages    []AnthropicMessage      `json:"messages" binding:"required"`
 	MaxTokens   int                     `json:"max_tokens" binding:"required"`
 	Temperature *float64                `json:"temperature,omitempty"`
+	TopP        *float64                `json:"top_p,omitempty"`
+	StopSequences []string                `json:"stop_sequences,omitempty"`
 	System      string                  `json:"system,omitempty"`
 	Stream      bool                    `json:"stream,omitempty"`
 	Tools       []AnthropicTool         `json:"tools,omitempty"`
@@ -117,9 +181,23 @@ func (h *ChatHandler) AnthropicMessages(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := setUsageTags(c, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Map Anthropic request to internal ChatRequest
 	internalMessages := mapAnthropicMessages(anthroReq)
+	contents := make([]provider.FlexibleContent, len(internalMessages))
+	for i, m := range internalMessages {
+		contents[i] = m.Content
+	}
+	if err := h.checkMessageLimits(c, contents); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	anthroReq.Model = h.router.ResolveModelTier(c.Request.Context(), anthroReq.Model)
 
 	var temp float64
 	if anthroReq.Temperature != nil {
@@ -131,6 +209,8 @@ func (h *ChatHandler) AnthropicMessages(c *gin.Context) {
 		Messages:    internalMessages,
 		MaxTokens:   anthroReq.MaxTokens,
 		Temperature: temp,
+		TopP:        anthroReq.TopP,
+		Stop:        anthroReq.StopSequences,
 		Stream:      anthroReq.Stream,
 	}
 
@@ -140,15 +220,23 @@ func (h *ChatHandler) AnthropicMessages(c *gin.Context) {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "no providers available"})
 		return
 	}
+	applyOutputTokenLimit(c, selectedProvider, providerReq)
 
 	projectObj := c.MustGet("project").(*models.Project)
 	if quotaErr := h.checkProjectQuota(c, projectObj); quotaErr != nil {
 		c.JSON(http.StatusTooManyRequests, gin.H{"error": *quotaErr})
 		return
 	}
+	release, ok := h.acquireProviderSlot(c, selectedProvider)
+	if !ok {
+		return
+	}
+	defer release()
+	defer h.stats.begin(selectedProvider.Name, anthroReq.Stream)()
 
 	start := time.Now()
 	userAPIKey := c.MustGet("api_key").(*models.APIKey)
+	providerReq.User = h.endUser("", userAPIKey)
 
 	// Handle streaming via existing infrastructure
 	if anthroReq.Stream {
@@ -178,28 +266,39 @@ func (h *ChatHandler) AnthropicMessages(c *gin.Context) {
 			},
 		},
 		"usage": gin.H{
-			"input_tokens":  resp.Usage.PromptTokens,
-			"output_tokens": resp.Usage.CompletionTokens,
+			// Anthropic's input_tokens excludes prompt-cache tokens.
+			"input_tokens":                resp.Usage.PromptTokens - resp.Usage.CacheCreationTokens - resp.Usage.CacheReadTokens,
+			"output_tokens":               resp.Usage.CompletionTokens,
+			"cache_creation_input_tokens": resp.Usage.CacheCreationTokens,
+			"cache_read_input_tokens":     resp.Usage.CacheReadTokens,
 		},
 	}
 
 	// Record usage
 	usageLog := &models.UsageLog{
-		UserID:         userAPIKey.UserID,
-		ProjectID:      projectObj.ID,
-		Channel:        userAPIKey.Channel,
-		APIKeyID:       userAPIKey.ID,
-		ProviderID:     selectedProvider.ID,
-		ModelName:      anthroReq.Model,
-		Latency:        latency.Milliseconds(),
-		StatusCode:     http.StatusOK,
-		RequestTokens:  resp.Usage.PromptTokens,
-		ResponseTokens: resp.Usage.CompletionTokens,
-		TotalTokens:    resp.Usage.TotalTokens,
-	}
-	if err := h.billing.RecordUsageAndDeduct(c.Request.Context(), usageLog, h.balance, projectObj.ID, "Anthropic API: "+anthroReq.Model); err != nil {
+		UserID:              userAPIKey.UserID,
+		ProjectID:           projectObj.ID,
+		Channel:             userAPIKey.Channel,
+		APIKeyID:            userAPIKey.ID,
+		ProviderID:          selectedProvider.ID,
+		ProviderKeyID:       providerKeyID(result.UsedKey),
+		ModelName:           anthroReq.Model,
+		Latency:             latency.Milliseconds(),
+		StatusCode:          http.StatusOK,
+		RequestTokens:       resp.Usage.PromptTokens,
+		ResponseTokens:      resp.Usage.CompletionTokens,
+		TotalTokens:         resp.Usage.TotalTokens,
+		CacheCreationTokens: resp.Usage.CacheCreationTokens,
+		CacheReadTokens:     resp.Usage.CacheReadTokens,
+		Tags:                usageTags(c),
+	}
+	if err := h.billing.RecordUsageAndDeduct(context.WithoutCancel(c.Request.Context()), usageLog, h.balance, projectObj.ID, "Anthropic API: "+anthroReq.Model); err != nil {
 		h.logger.Warn("billing deduction failed", zap.Error(err), zap.String("model", sanitize.LogValue(anthroReq.Model)))
 	}
+	middleware.SetRequestLogFields(c, middleware.RequestLogFields{
+		Provider: selectedProvider.Name, Model: anthroReq.Model,
+		PromptTokens: resp.Usage.PromptTokens, CompletionTokens: resp.Usage.CompletionTokens,
+	})
 
 	c.JSON(http.StatusOK, anthroResp)
 }
@@ -244,21 +343,28 @@ func (h *ChatHandler) handleAnthropicStream(c *gin.Context, anthroReq AnthropicM
 		ModelName:  anthroReq.Model,
 		Latency:    0,
 		StatusCode: http.StatusProcessing,
+		Tags:       usageTags(c),
 	}
 	if err := h.billing.RecordUsage(c.Request.Context(), usageLog); err != nil {
 		h.logger.Warn("billing pre-record failed", zap.Error(err), zap.String("model", sanitize.LogValue(anthroReq.Model)))
 	}
 
 	streamResult, err := h.router.ExecuteStreamChat(c.Request.Context(), selectedProvider, nil, providerReq, 3)
+	if isClientCanceled(c, err) {
+		h.finishCanceledStream(c, usageLog.ID, start)
+		return
+	}
 	if err != nil {
 		h.logger.Error("anthropic stream failed", zap.Error(err))
-		if billingErr := h.billing.UpdateUsageTokens(c.Request.Context(), usageLog.ID, 0, 0, http.StatusBadGateway, time.Since(start).Milliseconds(), err.Error()); billingErr != nil {
+		if billingErr := h.billing.UpdateUsageTokens(c.Request.Context(), usageLog.ID, 0, 0, 0, 0, http.StatusBadGateway, time.Since(start).Milliseconds(), err.Error()); billingErr != nil {
 			h.logger.Warn("billing update failed", zap.Error(billingErr))
 		}
 		c.JSON(http.StatusBadGateway, gin.H{"type": "error", "error": gin.H{"type": "api_error", "message": "upstream stream failed"}})
 		return
 	}
+	h.attributeProviderKey(c.Request.Context(), usageLog.ID, streamResult.UsedKey)
 
+	h.setStreamWriteDeadline(c)
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
 	c.Writer.Header().Set("Cache-Control", "no-cache")
 	c.Writer.Header().Set("Connection", "keep-alive")
@@ -326,23 +432,35 @@ func (h *ChatHandler) handleAnthropicStream(c *gin.Context, anthroReq AnthropicM
 	c.Writer.Flush()
 
 	latency := time.Since(start)
-	if err := h.billing.UpdateUsageTokens(c.Request.Context(), usageLog.ID, 0, totalOutput, http.StatusOK, latency.Milliseconds(), ""); err != nil {
+	if err := h.billing.UpdateUsageTokens(c.Request.Context(), usageLog.ID, 0, totalOutput, 0, 0, http.StatusOK, latency.Milliseconds(), ""); err != nil {
 		h.logger.Warn("billing update failed", zap.Error(err))
 	}
+	middleware.SetRequestLogFields(c, middleware.RequestLogFields{
+		Provider: selectedProvider.Name, Model: anthroReq.Model, Stream: true, CompletionTokens: totalOutput,
+	})
 }
 
 // ChatCompletionRequest represents a chat completion request.
 type ChatCompletionRequest struct {
-	Model              string           `json:"model" binding:"required"`
-	Messages           []MessageRequest `json:"messages" binding:"required,min=1"`
-	MaxTokens          int              `json:"max_tokens,omitempty"`
-	Temperature        float64          `json:"temperature,omitempty"`
-	Stream             bool             `json:"stream,omitempty"`
-	Tools              json.RawMessage  `json:"tools,omitempty"`
-	ToolChoice         json.RawMessage  `json:"tool_choice,omitempty"`
-	TrajectoryID       string           `json:"trajectory_id,omitempty"`
-	ConversationID     string           `json:"conversation_id,omitempty"`
-	ResumeFromStreamID string           `json:"resume_from_stream_id,omitempty"` // For resuming broken streams
+	Model              string                   `json:"model" binding:"required"`
+	Messages           []MessageRequest         `json:"messages" binding:"required,min=1"`
+	MaxTokens          int                      `json:"max_tokens,omitempty"`
+	Temperature        float64                  `json:"temperature,omitempty"`
+	TopP               *float64                 `json:"top_p,omitempty"`
+	FrequencyPenalty   *float64                 `json:"frequency_penalty,omitempty"`
+	PresencePenalty    *float64                 `json:"presence_penalty,omitempty"`
+	N                  int                      `json:"n,omitempty"`
+	Stop               provider.StopSequences   `json:"stop,omitempty"`
+	Stream             bool                     `json:"stream,omitempty"`
+	Tools              json.RawMessage          `json:"tools,omitempty"`
+	ToolChoice         json.RawMessage          `json:"tool_choice,omitempty"`
+	ResponseFormat     *provider.ResponseFormat `json:"response_format,omitempty"`
+	TrajectoryID       string                   `json:"trajectory_id,omitempty"`
+	ConversationID     string                   `json:"conversation_id,omitempty"`
+	ResumeFromStreamID string                   `json:"resume_from_stream_id,omitempty"` // For resuming broken streams
+	User               string                   `json:"user,omitempty"`                  // End-user identifier forwarded for provider abuse monitoring
+	Metadata           map[string]string        `json:"metadata,omitempty"`              // Attribution tags recorded on the usage log, not forwarded
+	SafePrompt         bool                     `json:"safe_prompt,omitempty"`           // Mistral guardrail prompt; ignored by other providers
 }
 
 // MessageRequest represents a message in the request.
@@ -351,6 +469,67 @@ type MessageRequest struct {
 	Content provider.FlexibleContent `json:"content" binding:"required"`
 }
 
+// validMessageRoles are the chat roles accepted from clients.
+var validMessageRoles = map[string]bool{"system": true, "user": true, "assistant": true, "tool": true}
+
+// validateMessages rejects message lists that upstream providers would refuse,
+// so clients get an actionable 400 instead of an opaque provider error.
+func validateMessages(messages []MessageRequest) error {
+	hasTurn := false
+	for i, m := range messages {
+		if !validMessageRoles[m.Role] {
+			return fmt.Errorf("messages[%d].role %q is invalid; must be one of system, user, assistant, tool", i, m.Role)
+		}
+		// Assistant turns may carry only tool calls, so their content can be null.
+		if m.Role != "assistant" && isEmptyContent(m.Content) {
+			return fmt.Errorf("messages[%d].content must not be empty", i)
+		}
+		if m.Role != "system" {
+			hasTurn = true
+		}
+	}
+	if !hasTurn {
+		return errors.New("messages must include at least one non-system message")
+	}
+	return nil
+}
+
+// maxStopSequences mirrors the OpenAI limit on the number of stop sequences.
+const maxStopSequences = 4
+
+// validateSamplingParams rejects sampling parameters outside the ranges the
+// OpenAI API documents, and malformed tools, before they reach a provider.
+func validateSamplingParams(req *ChatCompletionRequest) error {
+	if req.Temperature < 0 || req.Temperature > 2 {
+		return errors.New("temperature must be between 0 and 2")
+	}
+	if req.TopP != nil && (*req.TopP < 0 || *req.TopP > 1) {
+		return errors.New("top_p must be between 0 and 1")
+	}
+	if req.FrequencyPenalty != nil && (*req.FrequencyPenalty < -2 || *req.FrequencyPenalty > 2) {
+		return errors.New("frequency_penalty must be between -2 and 2")
+	}
+	if req.PresencePenalty != nil && (*req.PresencePenalty < -2 || *req.PresencePenalty > 2) {
+		return errors.New("presence_penalty must be between -2 and 2")
+	}
+	if req.N < 0 {
+		return errors.New("n must be a positive integer")
+	}
+	if len(req.Stop) > maxStopSequences {
+		return fmt.Errorf("stop accepts at most %d sequences", maxStopSequences)
+	}
+	if err := provider.ValidateTools(req.Tools, req.ToolChoice); err != nil {
+		return err
+	}
+	return req.ResponseFormat.Validate()
+}
+
+// isEmptyContent reports whether content is missing, null or an empty string.
+func isEmptyContent(fc provider.FlexibleContent) bool {
+	raw := strings.TrimSpace(string(fc.Raw))
+	return fc.Text == "" && (raw == "" || raw == "null" || raw == `""` || raw == "[]")
+}
+
 // EmbeddingsRequest represents an embeddings request from the user.
 type EmbeddingsRequest struct {
 	Model          string      `json:"model" binding:"required"`
@@ -376,16 +555,78 @@ func (h *ChatHandler) ChatCompletion(c *gin.Context) {
 		).MapToOpenAIResponse())
 		return
 	}
+	if err := validateMessages(req.Messages); err != nil {
+		c.JSON(http.StatusBadRequest, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderParseFailed, http.StatusBadRequest, "invalid_request_error", err.Error(), err,
+		).MapToOpenAIResponse())
+		return
+	}
+	contents := make([]provider.FlexibleContent, len(req.Messages))
+	for i, m := range req.Messages {
+		contents[i] = m.Content
+	}
+	if err := h.checkMessageLimits(c, contents); err != nil {
+		c.JSON(http.StatusBadRequest, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderParseFailed, http.StatusBadRequest, "invalid_request_error", err.Error(), err,
+		).MapToOpenAIResponse())
+		return
+	}
+	if err := validateSamplingParams(&req); err != nil {
+		c.JSON(http.StatusBadRequest, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderParseFailed, http.StatusBadRequest, "invalid_request_error", err.Error(), err,
+		).MapToOpenAIResponse())
+		return
+	}
+	if err := setUsageTags(c, req.Metadata); err != nil {
+		c.JSON(http.StatusBadRequest, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderParseFailed, http.StatusBadRequest, "invalid_request_error", err.Error(), err,
+		).MapToOpenAIResponse())
+		return
+	}
 
-	start := time.Now()
+	idem, done := h.beginIdempotent(c, req)
+	if done {
+		return
+	}
+	if idem != nil {
+		c.Set(ctxKeyIdempotency, idem)
+		defer h.releaseIdempotent(idem)
+	}
+
+	// Tier aliases such as "fast" resolve to a concrete model before pricing
+	// and routing; the idempotency fingerprint keeps the name the client sent.
+	req.Model = h.router.ResolveModelTier(c.Request.Context(), req.Model)
 
-	selectedProvider, apiKey, err := h.router.Route(c.Request.Context(), req.Model)
+	if h.exceedsRequestCostLimit(c, req) {
+		return
+	}
+
+	deadline, err := h.deadlineFor(c)
 	if err != nil {
-		c.JSON(http.StatusNotFound, router_errs.NewRouterError(
-			router_errs.ErrCodeModelNotFound, http.StatusNotFound, "invalid_request_error", "no available providers for model: "+req.Model, err,
+		c.JSON(http.StatusBadRequest, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderParseFailed, http.StatusBadRequest, "invalid_request_error", err.Error(), err,
 		).MapToOpenAIResponse())
 		return
 	}
+	if deadline > 0 {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), deadline)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+	}
+
+	start := time.Now()
+
+	selectedProvider, apiKey, ok := h.routeChat(c, req.Model)
+	if !ok {
+		return
+	}
+	forced := isProviderForced(c)
+	defer h.stats.begin(selectedProvider.Name, req.Stream)()
+
+	ctx, attempts := router.WithAttempts(c.Request.Context())
+	c.Request = c.Request.WithContext(ctx)
+	defer h.logRoutingOutcome(c, req.Model, selectedProvider, attempts, start)
+	defer h.recordFailedRequest(c, &req, attempts)
 
 	h.logger.Info("model routed to provider",
 		zap.String("model", sanitize.LogValue(req.Model)),
@@ -393,6 +634,13 @@ func (h *ChatHandler) ChatCompletion(c *gin.Context) {
 		zap.String("base_url", selectedProvider.BaseURL),
 	)
 
+	if err := provider.CheckResponseFormat(selectedProvider.ClientType(), req.ResponseFormat); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, router_errs.NewRouterError(
+			router_errs.ErrCodeUnsupportedParameter, http.StatusUnprocessableEntity, "invalid_request_error", err.Error(), err,
+		).MapToOpenAIResponse())
+		return
+	}
+
 	projectObj := c.MustGet("project").(*models.Project)
 	userAPIKey := c.MustGet("api_key").(*models.APIKey)
 
@@ -415,21 +663,26 @@ func (h *ChatHandler) ChatCompletion(c *gin.Context) {
 	}
 
 	providerReq := &provider.ChatRequest{
-		Model:       req.Model,
-		Messages:    messages,
-		MaxTokens:   req.MaxTokens,
-		Temperature: req.Temperature,
-		Stream:      req.Stream,
-		Tools:       req.Tools,
-		ToolChoice:  req.ToolChoice,
-	}
+		Model:            req.Model,
+		Messages:         messages,
+		MaxTokens:        req.MaxTokens,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+		N:                req.N,
+		Stop:             req.Stop,
+		Stream:           req.Stream,
+		Tools:            req.Tools,
+		ToolChoice:       req.ToolChoice,
+		ResponseFormat:   req.ResponseFormat,
+		User:             h.endUser(req.User, userAPIKey),
+		SafePrompt:       req.SafePrompt,
+	}
+	applyOutputTokenLimit(c, selectedProvider, providerReq)
 
 	// Observability: Start Trace
-	reqID := c.GetHeader("X-Request-ID")
-	if reqID == "" {
-		reqID = uuid.New().String()
-	}
-	trace := h.obsInfo.StartTrace(c.Request.Context(), reqID, "chat_completion", projectObj.ID.String(), req.ConversationID, map[string]interface{}{
+	trace := h.obsInfo.StartTrace(c.Request.Context(), requestID(c), "chat_completion", projectObj.ID.String(), req.ConversationID, map[string]interface{}{
 		"model":  req.Model,
 		"stream": req.Stream,
 	})
@@ -445,8 +698,16 @@ func (h *ChatHandler) ChatCompletion(c *gin.Context) {
 	}
 
 	// 6. Semantic cache lookup
+	// A pinned provider is a debugging aid, so it always reaches the provider.
+	// The cache is keyed on messages alone, so JSON-mode requests bypass it
+	// rather than risk being served a cached free-text answer.
 	msgBytes, _ := json.Marshal(messages)
-	promptHash, promptEmbedding, cacheHit := h.lookupSemanticCache(c, messages, msgBytes)
+	var promptHash string
+	var promptEmbedding []float32
+	var cacheHit *models.SemanticCache
+	if !forced && !req.ResponseFormat.Structured() {
+		promptHash, promptEmbedding, cacheHit = h.lookupSemanticCache(c, messages, msgBytes)
+	}
 
 	// 7. Cache hit response
 	if cacheHit != nil {
@@ -467,15 +728,21 @@ func (h *ChatHandler) ChatCompletion(c *gin.Context) {
 
 // ─── ChatCompletion Helpers ────────────────────────────────────────────────
 
+// memoryPromptTokenBudget caps the prompt tokens (history plus request
+// messages) assembled when a conversation ID is supplied.
+const memoryPromptTokenBudget = 8192
+
 // buildMessages constructs the message list from conversation history + request messages.
 func (h *ChatHandler) buildMessages(c *gin.Context, req ChatCompletionRequest, projectObj *models.Project, userAPIKey *models.APIKey) []provider.Message {
 	var historyMessages []provider.Message
 	if req.ConversationID != "" && h.memory != nil {
-		history, err := h.memory.GetConversationWithLimit(c.Request.Context(), projectObj.ID, &userAPIKey.ID, req.ConversationID, 20)
+		budget := memoryPromptTokenBudget
+		for _, m := range req.Messages {
+			budget -= tokencount.CountTokens(m.Content.Text, req.Model)
+		}
+		history, err := h.memory.BuildContext(c.Request.Context(), projectObj.ID, &userAPIKey.ID, req.ConversationID, req.Model, max(budget, 0))
 		if err == nil {
-			for _, hm := range history {
-				historyMessages = append(historyMessages, provider.Message{Role: hm.Role, Content: provider.StringContent(hm.Content)})
-			}
+			historyMessages = history
 		} else {
 			h.logger.Warn("failed to fetch conversation memory", zap.Error(err), zap.String("conversation_id", sanitize.LogValue(req.ConversationID)))
 		}
@@ -651,8 +918,9 @@ func (h *ChatHandler) handleCacheHit(c *gin.Context, cacheHit *models.SemanticCa
 		RequestTokens:  tokencount.CountTokens(req.Model, string(msgBytes)),
 		ResponseTokens: 0,
 		TotalTokens:    tokencount.CountTokens(req.Model, string(msgBytes)),
+		Tags:           usageTags(c),
 	}
-	if err := h.billing.RecordUsageAndDeduct(c.Request.Context(), usageLog, h.balance, userAPIKey.UserID, fmt.Sprintf("Cache hit: %s", req.Model)); err != nil {
+	if err := h.billing.RecordUsageAndDeduct(context.WithoutCancel(c.Request.Context()), usageLog, h.balance, userAPIKey.UserID, fmt.Sprintf("Cache hit: %s", req.Model)); err != nil {
 		h.logger.Warn("billing deduction failed (cache hit)", zap.Error(err), zap.String("model", sanitize.LogValue(req.Model)))
 	}
 
@@ -684,40 +952,87 @@ func (h *ChatHandler) handleCacheHit(c *gin.Context, cacheHit *models.SemanticCa
 	}
 
 	c.JSON(http.StatusOK, cachedResp)
+	h.storeIdempotent(c, cachedResp)
 	return true
 }
 
 // handleStreamPath handles the streaming chat path (pre-record, establish stream, delegate).
 func (h *ChatHandler) handleStreamPath(c *gin.Context, req ChatCompletionRequest, providerReq *provider.ChatRequest, selectedProvider *models.Provider, userAPIKey *models.APIKey, projectObj *models.Project, start time.Time, trace observability.Trace, promptHash string, promptEmbedding []float32) {
 	usageLog := &models.UsageLog{
-		UserID:     userAPIKey.UserID,
-		ProjectID:  projectObj.ID,
-		APIKeyID:   userAPIKey.ID,
-		ProviderID: selectedProvider.ID,
-		ModelName:  req.Model,
-		Latency:    0,
-		StatusCode: http.StatusProcessing,
+		UserID:         userAPIKey.UserID,
+		ProjectID:      projectObj.ID,
+		APIKeyID:       userAPIKey.ID,
+		ProviderID:     selectedProvider.ID,
+		ModelName:      req.Model,
+		Latency:        0,
+		StatusCode:     http.StatusProcessing,
+		ProviderForced: isProviderForced(c),
+		Tags:           usageTags(c),
 	}
 	if err := h.billing.RecordUsage(c.Request.Context(), usageLog); err != nil {
 		h.logger.Warn("billing pre-record failed", zap.Error(err), zap.String("model", sanitize.LogValue(req.Model)))
 	}
 
-	streamResult, err := h.router.ExecuteStreamChat(c.Request.Context(), selectedProvider, nil, providerReq, 3)
+	// Snapshot before the primary call, which may rewrite the request.
+	c.Set(ctxKeyShadowRequest, h.shadow.Sample(providerReq))
+
+	// The upstream stream gets its own cancel so a failed write to the client
+	// stops generation even while the request context is still live.
+	streamCtx, cancelStream := context.WithCancel(c.Request.Context())
+	defer cancelStream()
+
+	// A stream occupies its provider's slot until the last chunk is relayed.
+	var streamResult *router.StreamResult
+	var err error
+	if isProviderForced(c) {
+		streamResult, err = h.router.ExecuteStreamChatInSlot(streamCtx, selectedProvider, nil, providerReq, 3)
+	} else {
+		streamResult, err = h.router.ExecuteStreamChatWithFallback(streamCtx, selectedProvider, nil, providerReq, 3)
+	}
+	defer streamResult.Release()
+	if isClientCanceled(c, err) {
+		h.finishCanceledStream(c, usageLog.ID, start)
+		return
+	}
+	if deadlineExceeded(c, err) {
+		h.finishDeadlineExceededStream(c, usageLog.ID, start)
+		return
+	}
+	var queueErr *router.ProviderQueueError
+	if errors.As(err, &queueErr) {
+		if billingErr := h.billing.UpdateUsageTokens(context.WithoutCancel(c.Request.Context()), usageLog.ID, 0, 0, 0, 0, http.StatusServiceUnavailable, time.Since(start).Milliseconds(), sanitize.TruncateErrorMessage(err.Error())); billingErr != nil {
+			h.logger.Warn("billing update failed", zap.Error(billingErr))
+		}
+		writeProviderSaturated(c, queueErr)
+		return
+	}
+	if err != nil && h.streamFallback {
+		if h.handleStreamDowngrade(c, req, providerReq, selectedProvider, userAPIKey, projectObj, start, trace, usageLog.ID, promptHash, promptEmbedding, err) {
+			return
+		}
+	}
 	if err != nil {
 		h.saveErrorLog(c.Request.Context(), err, req.TrajectoryID, trace.GetID(), selectedProvider.Name, req.Model)
 		h.logger.Error("failed to establish stream", zap.Error(err))
 		usageLog.StatusCode = http.StatusBadGateway
 		usageLog.ErrorMessage = sanitize.TruncateErrorMessage(err.Error())
-		if billingErr := h.billing.UpdateUsageTokens(c.Request.Context(), usageLog.ID, 0, 0, http.StatusBadGateway, time.Since(start).Milliseconds(), sanitize.TruncateErrorMessage(err.Error())); billingErr != nil {
+		if billingErr := h.billing.UpdateUsageTokens(context.WithoutCancel(c.Request.Context()), usageLog.ID, 0, 0, 0, 0, http.StatusBadGateway, time.Since(start).Milliseconds(), sanitize.TruncateErrorMessage(err.Error())); billingErr != nil {
 			h.logger.Warn("billing update failed", zap.Error(billingErr))
 		}
 
-		c.JSON(http.StatusBadGateway, router_errs.NewRouterError(
+		c.JSON(http.StatusBadGateway, withSuggestions(router_errs.NewRouterError(
 			router_errs.ErrCodeInternalSystemError, http.StatusBadGateway, "server_error", "upstream provider error: stream failed to initialize", err,
-		).MapToOpenAIResponse())
+		).MapToOpenAIResponse(), h.modelSuggestions(c, req.Model, err)))
 		return
 	}
-	h.handleStreamingChat(c, streamResult.Stream, providerReq, selectedProvider, projectObj, userAPIKey, start, trace, req.ConversationID, req.Messages, usageLog.ID, promptHash, promptEmbedding)
+	// A fallback chain may have opened the stream on a different provider.
+	if streamResult.Provider != nil && streamResult.Provider.ID != selectedProvider.ID {
+		selectedProvider = streamResult.Provider
+		h.attributeProvider(c.Request.Context(), usageLog.ID, selectedProvider)
+	}
+	h.attributeProviderKey(c.Request.Context(), usageLog.ID, streamResult.UsedKey)
+	h.setRoutingHeaders(c, selectedProvider, providerReq.Model)
+	h.handleStreamingChat(c, streamResult.Stream, cancelStream, providerReq, selectedProvider, projectObj, userAPIKey, start, trace, req.ConversationID, req.Messages, usageLog.ID, promptHash, promptEmbedding)
 }
 
 // handleNonStreamResponse handles non-streaming chat completion, billing, memory save, and cache store.
@@ -727,7 +1042,38 @@ func (h *ChatHandler) handleNonStreamResponse(c *gin.Context, req ChatCompletion
 		"max_tokens":  req.MaxTokens,
 	}, req.Messages)
 
-	result, err := h.router.ExecuteChat(c.Request.Context(), selectedProvider, apiKey, providerReq, 3)
+	// Snapshot before the primary call, which may rewrite the request.
+	shadowReq := h.shadow.Sample(providerReq)
+
+	var result *router.ChatResult
+	var err error
+	if isProviderForced(c) {
+		result, err = h.router.ExecuteChatInSlot(c.Request.Context(), selectedProvider, apiKey, providerReq, 3)
+	} else {
+		result, err = h.router.ExecuteChatWithFallback(c.Request.Context(), selectedProvider, apiKey, providerReq, 3)
+	}
+
+	if deadlineExceeded(c, err) {
+		gen.EndWithError(err)
+		h.recordDeadlineExceeded(c, userAPIKey, projectObj, selectedProvider, req.Model, start)
+		return
+	}
+	if isClientCanceled(c, err) {
+		gen.EndWithError(err)
+		h.recordClientCanceled(c, userAPIKey, projectObj, selectedProvider, req.Model, start)
+		return
+	}
+	var queueErr *router.ProviderQueueError
+	if errors.As(err, &queueErr) {
+		gen.EndWithError(err)
+		writeProviderSaturated(c, queueErr)
+		return
+	}
+
+	// A fallback chain may have served the request from a different provider.
+	if result != nil && result.Provider != nil {
+		selectedProvider = result.Provider
+	}
 
 	if err != nil || result == nil {
 		if err != nil {
@@ -736,30 +1082,33 @@ func (h *ChatHandler) handleNonStreamResponse(c *gin.Context, req ChatCompletion
 		gen.EndWithError(err)
 		latency := time.Since(start)
 		usageLog := &models.UsageLog{
-			UserID:       userAPIKey.UserID,
-			ProjectID:    projectObj.ID,
-			APIKeyID:     userAPIKey.ID,
-			ProviderID:   selectedProvider.ID,
-			ModelName:    req.Model,
-			Latency:      latency.Milliseconds(),
-			StatusCode:   http.StatusBadGateway,
-			ErrorMessage: "all API keys failed",
+			UserID:         userAPIKey.UserID,
+			ProjectID:      projectObj.ID,
+			APIKeyID:       userAPIKey.ID,
+			ProviderID:     selectedProvider.ID,
+			ModelName:      req.Model,
+			Latency:        latency.Milliseconds(),
+			StatusCode:     http.StatusBadGateway,
+			ErrorMessage:   "all API keys failed",
+			ProviderForced: isProviderForced(c),
+			Tags:           usageTags(c),
 		}
 		if err != nil {
 			usageLog.ErrorMessage = sanitize.TruncateErrorMessage(err.Error())
 		}
-		if err := h.billing.RecordUsage(c.Request.Context(), usageLog); err != nil {
+		if err := h.billing.RecordUsage(context.WithoutCancel(c.Request.Context()), usageLog); err != nil {
 			h.logger.Warn("billing pre-record failed", zap.Error(err), zap.String("model", sanitize.LogValue(req.Model)))
 		}
+		middleware.SetRequestLogFields(c, middleware.RequestLogFields{Provider: selectedProvider.Name, Model: req.Model})
 
 		h.logger.Error("provider request failed",
 			zap.String("model", sanitize.LogValue(req.Model)),
 			zap.String("provider", selectedProvider.Name),
 			zap.Error(err),
 		)
-		c.JSON(http.StatusBadGateway, router_errs.NewRouterError(
+		c.JSON(http.StatusBadGateway, withSuggestions(router_errs.NewRouterError(
 			router_errs.ErrCodeInternalSystemError, http.StatusBadGateway, "server_error", "upstream provider error: request failed", err,
-		).MapToOpenAIResponse())
+		).MapToOpenAIResponse(), h.modelSuggestions(c, req.Model, err)))
 		return
 	}
 
@@ -770,6 +1119,10 @@ func (h *ChatHandler) handleNonStreamResponse(c *gin.Context, req ChatCompletion
 	}
 	gen.End(outText, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
 
+	// The upstream call may have used up most of the request deadline, so
+	// the writes that record its outcome must not inherit it.
+	writeCtx := context.WithoutCancel(c.Request.Context())
+
 	// Save conversation memory
 	if req.ConversationID != "" && h.memory != nil {
 		for _, m := range result.FinalMessages {
@@ -777,30 +1130,39 @@ func (h *ChatHandler) handleNonStreamResponse(c *gin.Context, req ChatCompletion
 			if content == "" && len(m.ToolCalls) > 0 {
 				content = "[Tool Call]"
 			}
-			_ = h.memory.AddMessage(c.Request.Context(), projectObj.ID, &userAPIKey.ID, req.ConversationID, m.Role, content, 0)
+			_ = h.memory.AddMessage(writeCtx, projectObj.ID, &userAPIKey.ID, req.ConversationID, m.Role, content, 0)
 		}
-		_ = h.memory.AddMessage(c.Request.Context(), projectObj.ID, &userAPIKey.ID, req.ConversationID, "assistant", outText, resp.Usage.CompletionTokens)
+		_ = h.memory.AddMessage(writeCtx, projectObj.ID, &userAPIKey.ID, req.ConversationID, "assistant", outText, resp.Usage.CompletionTokens)
 	}
 
 	latency := time.Since(start)
 	usageLog := &models.UsageLog{
-		UserID:         userAPIKey.UserID,
-		ProjectID:      projectObj.ID,
-		Channel:        userAPIKey.Channel,
-		APIKeyID:       userAPIKey.ID,
-		ProviderID:     selectedProvider.ID,
-		ModelName:      req.Model,
-		Latency:        latency.Milliseconds(),
-		StatusCode:     http.StatusOK,
-		RequestTokens:  resp.Usage.PromptTokens,
-		ResponseTokens: resp.Usage.CompletionTokens,
-		TotalTokens:    resp.Usage.TotalTokens,
-		MCPCallCount:   result.MCPCallCount,
-		MCPErrorCount:  result.MCPErrorCount,
-	}
-	if err := h.billing.RecordUsageAndDeduct(c.Request.Context(), usageLog, h.balance, projectObj.ID, "LLM Request: "+req.Model); err != nil {
+		UserID:              userAPIKey.UserID,
+		ProjectID:           projectObj.ID,
+		Channel:             userAPIKey.Channel,
+		APIKeyID:            userAPIKey.ID,
+		ProviderID:          selectedProvider.ID,
+		ProviderKeyID:       providerKeyID(result.UsedKey),
+		ModelName:           req.Model,
+		Latency:             latency.Milliseconds(),
+		StatusCode:          http.StatusOK,
+		RequestTokens:       resp.Usage.PromptTokens,
+		ResponseTokens:      resp.Usage.CompletionTokens,
+		TotalTokens:         resp.Usage.TotalTokens,
+		CacheCreationTokens: resp.Usage.CacheCreationTokens,
+		CacheReadTokens:     resp.Usage.CacheReadTokens,
+		MCPCallCount:        result.MCPCallCount,
+		MCPErrorCount:       result.MCPErrorCount,
+		ProviderForced:      isProviderForced(c),
+		Tags:                usageTags(c),
+	}
+	if err := h.billing.RecordUsageAndDeduct(writeCtx, usageLog, h.balance, projectObj.ID, "LLM Request: "+req.Model); err != nil {
 		h.logger.Warn("billing deduction failed", zap.Error(err), zap.String("model", sanitize.LogValue(req.Model)))
 	}
+	middleware.SetRequestLogFields(c, middleware.RequestLogFields{
+		Provider: selectedProvider.Name, Model: req.Model,
+		PromptTokens: resp.Usage.PromptTokens, CompletionTokens: resp.Usage.CompletionTokens,
+	})
 
 	// Save Semantic Cache (Async)
 	if promptHash != "" && len(resp.Choices) > 0 {
@@ -812,16 +1174,449 @@ func (h *ChatHandler) handleNonStreamResponse(c *gin.Context, req ChatCompletion
 		}(promptHash, promptEmbedding, resp, selectedProvider.Name, req.Model)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	body := gin.H{
 		"id":      resp.ID,
 		"object":  "chat.completion",
 		"created": time.Now().Unix(),
 		"model":   resp.Model,
 		"choices": resp.Choices,
 		"usage":   resp.Usage,
+	}
+	h.setRoutingHeaders(c, selectedProvider, upstreamModel(resp, providerReq.Model))
+	c.JSON(http.StatusOK, body)
+	h.storeIdempotent(c, body)
+
+	h.shadow.Mirror(shadowReq, shadow.Primary{
+		UsageLogID: usageLog.ID,
+		ProviderID: selectedProvider.ID,
+		Model:      req.Model,
+		Latency:    latency,
+		Response:   outText,
 	})
 }
 
+// providerOverrideHeader pins a chat request to a named provider.
+const providerOverrideHeader = "X-LLM-Provider"
+
+// ctxKeyProviderForced marks a request whose provider was pinned via
+// providerOverrideHeader or a fail-fast model route override.
+const ctxKeyProviderForced = "provider_forced"
+
+// ctxKeyShadowRequest holds the request copy to mirror once a stream completes.
+const ctxKeyShadowRequest = "shadow_request"
+
+// maxTokensClampedHeader carries the max_tokens actually sent upstream when
+// the request asked for more than the provider's MaxOutputTokens.
+const maxTokensClampedHeader = "X-LLM-Max-Tokens-Clamped"
+
+// applyOutputTokenLimit fills an omitted max_tokens with the provider default
+// and clamps it to the provider ceiling, flagging a clamp in the response.
+func applyOutputTokenLimit(c *gin.Context, p *models.Provider, req *provider.ChatRequest) {
+	maxTokens, clamped := p.OutputTokenLimit(req.MaxTokens)
+	if clamped {
+		c.Header(maxTokensClampedHeader, strconv.Itoa(maxTokens))
+	}
+	req.MaxTokens = maxTokens
+}
+
+// requestTimeoutHeader lets a client shorten the configured request deadline,
+// given in whole seconds. It can never extend it.
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// maxRequestTimeoutSecs keeps requestTimeoutHeader values within time.Duration.
+const maxRequestTimeoutSecs = int64(math.MaxInt64 / time.Second)
+
+// deadlineFor returns the total time budget for a chat request: the
+// configured deadline, or the requestTimeoutHeader value when that is
+// shorter. Zero means the request has no deadline.
+func (h *ChatHandler) deadlineFor(c *gin.Context) (time.Duration, error) {
+	raw := strings.TrimSpace(c.GetHeader(requestTimeoutHeader))
+	if raw == "" {
+		return h.requestDeadline, nil
+	}
+	secs, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || secs <= 0 {
+		return 0, fmt.Errorf("%s must be a positive number of seconds", requestTimeoutHeader)
+	}
+	d := time.Duration(min(secs, maxRequestTimeoutSecs)) * time.Second
+	if h.requestDeadline > 0 && h.requestDeadline < d {
+		return h.requestDeadline, nil
+	}
+	return d, nil
+}
+
+// modelSuggestions returns known models close to modelName when err is an
+// upstream 404, the usual answer of a provider that does not serve the model.
+func (h *ChatHandler) modelSuggestions(c *gin.Context, modelName string, err error) []string {
+	var providerErr *provider.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.StatusCode != http.StatusNotFound {
+		return nil
+	}
+	return h.router.SuggestModels(c.Request.Context(), modelName)
+}
+
+// withSuggestions adds suggested model names, if any, to an OpenAI-style
+// error response.
+func withSuggestions(resp map[string]interface{}, suggestions []string) map[string]interface{} {
+	if len(suggestions) > 0 {
+		resp["error"].(map[string]interface{})["suggestions"] = suggestions
+	}
+	return resp
+}
+
+// acquireProviderSlot waits for a free slot in the provider's request queue.
+// When the provider stays saturated it responds 503 with Retry-After and
+// returns false; the returned function frees the slot.
+func (h *ChatHandler) acquireProviderSlot(c *gin.Context, p *models.Provider) (func(), bool) {
+	release, err := h.router.AcquireProviderSlot(c.Request.Context(), p)
+	if err == nil {
+		return release, true
+	}
+	var queueErr *router.ProviderQueueError
+	if errors.As(err, &queueErr) {
+		writeProviderSaturated(c, queueErr)
+		return nil, false
+	}
+	// The client went away while queued; nobody reads the response.
+	c.Status(statusClientClosedRequest)
+	return nil, false
+}
+
+// writeProviderSaturated responds 503 with Retry-After for a request refused
+// a provider slot.
+func writeProviderSaturated(c *gin.Context, queueErr *router.ProviderQueueError) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(queueErr.RetryAfter.Seconds()))))
+	c.JSON(http.StatusServiceUnavailable, router_errs.NewRouterError(
+		router_errs.ErrCodeProviderSaturated, http.StatusServiceUnavailable, "server_error", queueErr.Error(), queueErr,
+	).MapToOpenAIResponse())
+}
+
+// exceedsRequestCostLimit rejects the request with 402 when its worst-case
+// cost exceeds the API key's MaxRequestCostUSD. The estimate counts only the
+// client's messages: conversation history is not loaded before routing.
+// Estimation failures are logged and let the request through.
+func (h *ChatHandler) exceedsRequestCostLimit(c *gin.Context, req ChatCompletionRequest) bool {
+	v, _ := c.Get("api_key")
+	userAPIKey, _ := v.(*models.APIKey)
+	if h.billing == nil || userAPIKey == nil || userAPIKey.MaxRequestCostUSD <= 0 {
+		return false
+	}
+
+	promptTokens := 0
+	for _, m := range req.Messages {
+		promptTokens += tokencount.CountTokens(m.Content.Text, req.Model)
+	}
+	estimated, err := h.billing.EstimateMaxCost(c.Request.Context(), req.Model, promptTokens, req.MaxTokens, req.N)
+	if err != nil {
+		h.logger.Warn("request cost estimate failed", zap.Error(err), zap.String("model", sanitize.LogValue(req.Model)))
+		return false
+	}
+	if estimated <= userAPIKey.MaxRequestCostUSD {
+		return false
+	}
+
+	msg := fmt.Sprintf("estimated worst-case request cost $%.6f exceeds this API key's per-request limit of $%.6f; lower max_tokens or n",
+		estimated, userAPIKey.MaxRequestCostUSD)
+	resp := router_errs.NewRouterError(
+		router_errs.ErrCodeRequestCostExceeded, http.StatusPaymentRequired, "insufficient_quota", msg, nil,
+	).MapToOpenAIResponse()
+	body := resp["error"].(map[string]interface{})
+	body["estimated_cost_usd"] = estimated
+	body["max_request_cost_usd"] = userAPIKey.MaxRequestCostUSD
+	c.JSON(http.StatusPaymentRequired, resp)
+	return true
+}
+
+// routeChat selects the provider and key for a chat request. When the
+// X-LLM-Provider header is set and the calling key belongs to an admin, routing
+// is bypassed and the named provider is used. Requests pinned by a model route
+// override are treated like header-forced ones and never fall back. On failure
+// the error response has already been written and ok is false.
+func (h *ChatHandler) routeChat(c *gin.Context, modelName string) (*models.Provider, *models.ProviderAPIKey, bool) {
+	name := strings.TrimSpace(c.GetHeader(providerOverrideHeader))
+	if name == "" {
+		selectedProvider, apiKey, pinned, err := h.router.RoutePinned(c.Request.Context(), modelName)
+		if errors.Is(err, router.ErrRouteOverrideUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, router_errs.NewRouterError(
+				router_errs.ErrCodeInternalSystemError, http.StatusServiceUnavailable, "server_error", "pinned provider for model "+modelName+" is unavailable", err,
+			).MapToOpenAIResponse())
+			return nil, nil, false
+		}
+		var unsupported *router.ModelNotSupportedError
+		if errors.As(err, &unsupported) {
+			c.JSON(http.StatusNotFound, withSuggestions(router_errs.NewRouterError(
+				router_errs.ErrCodeModelNotSupported, http.StatusNotFound, "invalid_request_error", unsupported.Error(), err,
+			).MapToOpenAIResponse(), unsupported.Suggestions))
+			return nil, nil, false
+		}
+		if router.IsKeysExhausted(err) {
+			writeKeysExhausted(c, err)
+			return nil, nil, false
+		}
+		if err != nil {
+			c.JSON(http.StatusNotFound, router_errs.NewRouterError(
+				router_errs.ErrCodeModelNotFound, http.StatusNotFound, "invalid_request_error", "no available providers for model: "+modelName, err,
+			).MapToOpenAIResponse())
+			return nil, nil, false
+		}
+		if pinned {
+			c.Set(ctxKeyProviderForced, true)
+		}
+		return selectedProvider, apiKey, true
+	}
+
+	userAPIKey := c.MustGet("api_key").(*models.APIKey)
+	if !h.canOverrideProvider(c.Request.Context(), userAPIKey) {
+		c.JSON(http.StatusForbidden, router_errs.NewRouterError(
+			router_errs.ErrCodeAuthenticationFailed, http.StatusForbidden, "permission_error", providerOverrideHeader+" is restricted to admin API keys", nil,
+		).MapToOpenAIResponse())
+		return nil, nil, false
+	}
+
+	selectedProvider, apiKey, err := h.router.RouteToProvider(c.Request.Context(), name)
+	if errors.Is(err, router.ErrProviderUnavailable) {
+		c.JSON(http.StatusBadRequest, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderNotFound, http.StatusBadRequest, "invalid_request_error", "provider not found, inactive or draining: "+name, err,
+		).MapToOpenAIResponse())
+		return nil, nil, false
+	}
+	if router.IsKeysExhausted(err) {
+		writeKeysExhausted(c, err)
+		return nil, nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, router_errs.NewRouterError(
+			router_errs.ErrCodeInternalSystemError, http.StatusServiceUnavailable, "server_error", "no available API keys for provider: "+name, err,
+		).MapToOpenAIResponse())
+		return nil, nil, false
+	}
+
+	h.logger.Info("provider pinned by request header",
+		zap.String("provider", selectedProvider.Name),
+		zap.String("api_key_id", userAPIKey.ID.String()),
+	)
+	c.Set(ctxKeyProviderForced, true)
+	return selectedProvider, apiKey, true
+}
+
+// writeKeysExhausted answers a request whose candidate providers all have
+// every key cooling down: 429 while keys are only rate limited, 503 when they
+// failed, both with Retry-After.
+func writeKeysExhausted(c *gin.Context, err error) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(router.KeysRetryAfter(err).Seconds()))))
+	if errors.Is(err, router.ErrAllKeysRateLimited) {
+		c.JSON(http.StatusTooManyRequests, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderQuotaExceeded, http.StatusTooManyRequests, "rate_limit_error", err.Error(), err,
+		).MapToOpenAIResponse())
+		return
+	}
+	c.JSON(http.StatusServiceUnavailable, router_errs.NewRouterError(
+		router_errs.ErrCodeProviderQuotaExceeded, http.StatusServiceUnavailable, "server_error", err.Error(), err,
+	).MapToOpenAIResponse())
+}
+
+// canOverrideProvider reports whether key may pin requests to a provider.
+// Only keys owned by admin users are trusted to bypass routing.
+func (h *ChatHandler) canOverrideProvider(ctx context.Context, key *models.APIKey) bool {
+	if h.userRepo == nil {
+		return false
+	}
+	u, err := h.userRepo.GetByID(ctx, key.UserID)
+	return err == nil && u.Role == "admin"
+}
+
+// endUser returns the end-user identifier sent upstream: the client's own
+// value, else a stable hash of its API key ID when injection is enabled.
+func (h *ChatHandler) endUser(clientUser string, key *models.APIKey) string {
+	if clientUser != "" || !h.injectEndUser || key == nil {
+		return clientUser
+	}
+	sum := sha256.Sum256([]byte("llm-router-end-user:" + key.ID.String()))
+	return "key-" + hex.EncodeToString(sum[:16])
+}
+
+// isProviderForced reports whether the request's provider was pinned via header.
+func isProviderForced(c *gin.Context) bool {
+	return c.GetBool(ctxKeyProviderForced)
+}
+
+// requestID returns the ID set by the request ID middleware. Without the
+// middleware it falls back to the client's X-Request-ID header or a fresh ID,
+// stored so later calls for the same request agree.
+func requestID(c *gin.Context) string {
+	if id := c.GetString("request_id"); id != "" {
+		return id
+	}
+	id := c.GetHeader("X-Request-ID")
+	if id == "" {
+		id = uuid.New().String()
+	}
+	c.Set("request_id", id)
+	return id
+}
+
+// logRoutingOutcome writes one structured line per chat request describing
+// which provider and key served it, how many upstream attempts that took and
+// whether it fell back to another provider. Requests answered without an
+// upstream call (cache hits, DLP rejections) report zero attempts and the
+// routed provider.
+func (h *ChatHandler) logRoutingOutcome(c *gin.Context, model string, routed *models.Provider, attempts *router.Attempts, start time.Time) {
+	served, keyAlias := routed.Name, ""
+	if last, ok := attempts.Last(); ok {
+		served, keyAlias = last.Provider, last.KeyAlias
+	}
+	strategy := string(h.router.Strategy())
+	if isProviderForced(c) {
+		strategy = "forced"
+	}
+	h.logger.Info("chat request served",
+		zap.String("request_id", requestID(c)),
+		zap.String("model", sanitize.LogValue(model)),
+		zap.String("provider", served),
+		zap.String("key_alias", keyAlias),
+		zap.String("strategy", strategy),
+		zap.Int("attempts", attempts.Count()),
+		zap.Bool("fallback", attempts.Fallback()),
+		zap.Int64("latency_ms", time.Since(start).Milliseconds()),
+		zap.Int("status", c.Writer.Status()),
+	)
+}
+
+// statusClientClosedRequest is the de-facto (nginx) status code recorded for
+// requests the client abandoned before a response could be written.
+const statusClientClosedRequest = 499
+
+// clientCanceledMessage is the usage-log error message for abandoned requests.
+const clientCanceledMessage = "client_canceled"
+
+// isClientCanceled reports whether err stems from the client disconnecting.
+func isClientCanceled(c *gin.Context, err error) bool {
+	return err != nil && errors.Is(err, context.Canceled) && c.Request.Context().Err() != nil
+}
+
+// deadlineExceeded reports whether err stems from the request deadline
+// expiring rather than from the provider or the client.
+func deadlineExceeded(c *gin.Context, err error) bool {
+	return err != nil && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+// deadlineExceededMessage is the client-facing and usage-log message for
+// requests that ran out of time.
+const deadlineExceededMessage = "request deadline exceeded before the upstream provider responded"
+
+// deadlineExceededError is returned to clients whose request ran out of time.
+func deadlineExceededError(err error) *router_errs.RouterError {
+	return router_errs.NewRouterError(
+		router_errs.ErrCodeProxyTimeout, http.StatusGatewayTimeout, "server_error", deadlineExceededMessage, err,
+	)
+}
+
+// recordDeadlineExceeded records a usage log for a non-streaming request whose
+// deadline expired and answers 504. The upstream call has already been
+// canceled through the request context.
+func (h *ChatHandler) recordDeadlineExceeded(c *gin.Context, userAPIKey *models.APIKey, projectObj *models.Project, selectedProvider *models.Provider, modelName string, start time.Time) {
+	usageLog := &models.UsageLog{
+		UserID:         userAPIKey.UserID,
+		ProjectID:      projectObj.ID,
+		Channel:        userAPIKey.Channel,
+		APIKeyID:       userAPIKey.ID,
+		ProviderID:     selectedProvider.ID,
+		ModelName:      modelName,
+		Latency:        time.Since(start).Milliseconds(),
+		StatusCode:     http.StatusGatewayTimeout,
+		ErrorMessage:   deadlineExceededMessage,
+		ProviderForced: isProviderForced(c),
+		Tags:           usageTags(c),
+	}
+	if err := h.billing.RecordUsage(context.WithoutCancel(c.Request.Context()), usageLog); err != nil {
+		h.logger.Warn("billing record failed", zap.Error(err), zap.String("model", sanitize.LogValue(modelName)))
+	}
+
+	h.logger.Warn("request deadline exceeded, aborting provider retries",
+		zap.String("model", sanitize.LogValue(modelName)),
+		zap.String("provider", selectedProvider.Name),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+	c.JSON(http.StatusGatewayTimeout, deadlineExceededError(context.DeadlineExceeded).MapToOpenAIResponse())
+}
+
+// finishDeadlineExceededStream marks a pre-recorded streaming usage log as
+// timed out and answers 504; the stream never started, so headers are unsent.
+func (h *ChatHandler) finishDeadlineExceededStream(c *gin.Context, logID uuid.UUID, start time.Time) {
+	if err := h.billing.UpdateUsageTokens(context.WithoutCancel(c.Request.Context()), logID, 0, 0, 0, 0, http.StatusGatewayTimeout, time.Since(start).Milliseconds(), deadlineExceededMessage); err != nil {
+		h.logger.Warn("billing update failed", zap.Error(err))
+	}
+	c.JSON(http.StatusGatewayTimeout, deadlineExceededError(context.DeadlineExceeded).MapToOpenAIResponse())
+}
+
+// recordClientCanceled records a usage log for a request the client abandoned
+// mid-retry. The request context is already canceled, so the write is detached
+// from it. No response body is written since nobody is listening.
+func (h *ChatHandler) recordClientCanceled(c *gin.Context, userAPIKey *models.APIKey, projectObj *models.Project, selectedProvider *models.Provider, modelName string, start time.Time) {
+	usageLog := &models.UsageLog{
+		UserID:         userAPIKey.UserID,
+		ProjectID:      projectObj.ID,
+		Channel:        userAPIKey.Channel,
+		APIKeyID:       userAPIKey.ID,
+		ProviderID:     selectedProvider.ID,
+		ModelName:      modelName,
+		Latency:        time.Since(start).Milliseconds(),
+		StatusCode:     statusClientClosedRequest,
+		ErrorMessage:   clientCanceledMessage,
+		ProviderForced: isProviderForced(c),
+		Tags:           usageTags(c),
+	}
+	if err := h.billing.RecordUsage(context.WithoutCancel(c.Request.Context()), usageLog); err != nil {
+		h.logger.Warn("billing record failed", zap.Error(err), zap.String("model", sanitize.LogValue(modelName)))
+	}
+
+	h.logger.Info("client canceled request, aborting provider retries",
+		zap.String("model", sanitize.LogValue(modelName)),
+		zap.String("provider", selectedProvider.Name),
+	)
+	c.Abort()
+}
+
+// finishCanceledStream marks a pre-recorded streaming usage log as abandoned
+// by the client and aborts without writing a body.
+func (h *ChatHandler) finishCanceledStream(c *gin.Context, logID uuid.UUID, start time.Time) {
+	if err := h.billing.UpdateUsageTokens(context.WithoutCancel(c.Request.Context()), logID, 0, 0, 0, 0, statusClientClosedRequest, time.Since(start).Milliseconds(), clientCanceledMessage); err != nil {
+		h.logger.Warn("billing update failed", zap.Error(err))
+	}
+	c.Abort()
+}
+
+// providerKeyID returns the ID of the provider key that served a request, or
+// uuid.Nil for providers that need no key.
+func providerKeyID(k *models.ProviderAPIKey) uuid.UUID {
+	if k == nil {
+		return uuid.Nil
+	}
+	return k.ID
+}
+
+// attributeProviderKey records the serving provider key on a pre-recorded
+// usage log so per-key monthly caps account for streamed requests.
+func (h *ChatHandler) attributeProviderKey(ctx context.Context, logID uuid.UUID, key *models.ProviderAPIKey) {
+	if key == nil {
+		return
+	}
+	if err := h.usageRepo.SetProviderKey(ctx, logID, key.ID); err != nil {
+		h.logger.Warn("failed to attribute provider key to usage log", zap.Error(err))
+	}
+}
+
+// attributeProvider records on a usage log the provider that served the
+// request.
+func (h *ChatHandler) attributeProvider(ctx context.Context, logID uuid.UUID, p *models.Provider) {
+	if h.usageRepo == nil {
+		return
+	}
+	if err := h.usageRepo.SetProvider(ctx, logID, p.ID); err != nil {
+		h.logger.Warn("failed to attribute provider to usage log", zap.Error(err))
+	}
+}
+
 // saveErrorLog extracts provider.ProviderError and saves an ErrorLog via the repository.
 func (h *ChatHandler) saveErrorLog(ctx context.Context, err error, trajectoryID, traceID, providerName, modelName string) {
 	if h.errorLogRepo == nil {
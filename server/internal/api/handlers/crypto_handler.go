@@ -0,0 +1,73 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin endpoint for re-encrypting stored secrets.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"llm-router-platform/internal/crypto"
+	"llm-router-platform/internal/service/admin"
+	"llm-router-platform/internal/service/audit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CryptoHandler exposes encryption-key maintenance operations to admins.
+type CryptoHandler struct {
+	adminSvc     *admin.Service
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewCryptoHandler creates a new crypto handler.
+func NewCryptoHandler(adminSvc *admin.Service, auditService *audit.Service, logger *zap.Logger) *CryptoHandler {
+	return &CryptoHandler{adminSvc: adminSvc, auditService: auditService, logger: logger}
+}
+
+// RekeyRequest is the body of POST /api/v1/admin/crypto/rekey.
+type RekeyRequest struct {
+	OldKey string `json:"old_key" binding:"required"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// Rekey godoc
+// @Summary Re-encrypt stored secrets after ENCRYPTION_KEY rotation
+// @Description Decrypts provider API keys and proxy passwords with old_key and re-encrypts them with the current key in one transaction. With dry_run, only reports what would change.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/crypto/rekey [post]
+func (h *CryptoHandler) Rekey(c *gin.Context) {
+	var req RekeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "old_key is required"})
+		return
+	}
+
+	result, err := h.adminSvc.RekeySecrets(c.Request.Context(), req.OldKey, req.DryRun)
+	switch {
+	case errors.Is(err, crypto.ErrInvalidKey):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, admin.ErrRekeyFailed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "result": result})
+		return
+	case err != nil:
+		h.logger.Error("secret re-encryption failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "re-encryption failed"})
+		return
+	}
+
+	if !req.DryRun && h.auditService != nil {
+		actorID, _ := uuid.Parse(c.GetString("user_id"))
+		h.auditService.Log(c.Request.Context(), audit.ActionSecretsRekey, actorID, uuid.Nil, c.ClientIP(), c.Request.UserAgent(), map[string]interface{}{
+			"provider_keys_migrated": result.ProviderKeys.Migrated,
+			"proxies_migrated":       result.Proxies.Migrated,
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
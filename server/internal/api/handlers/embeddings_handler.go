@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"llm-router-platform/internal/api/middleware"
 	"llm-router-platform/internal/models"
 	"llm-router-platform/internal/service/dlp"
 	"llm-router-platform/internal/service/provider"
@@ -106,6 +107,7 @@ func (h *ChatHandler) Embeddings(c *gin.Context) {
 		Channel:        userAPIKey.Channel,
 		APIKeyID:       userAPIKey.ID,
 		ProviderID:     selectedProvider.ID,
+		ProviderKeyID:  providerKeyID(result.UsedKey),
 		ModelName:      req.Model,
 		Latency:        latency.Milliseconds(),
 		StatusCode:     http.StatusOK,
@@ -116,6 +118,9 @@ func (h *ChatHandler) Embeddings(c *gin.Context) {
 	if err := h.billing.RecordUsage(c.Request.Context(), usageLog); err != nil {
 		h.logger.Warn("billing record failed", zap.Error(err))
 	}
+	middleware.SetRequestLogFields(c, middleware.RequestLogFields{
+		Provider: selectedProvider.Name, Model: req.Model, PromptTokens: resp.Usage.PromptTokens,
+	})
 
 	c.JSON(http.StatusOK, resp)
 }
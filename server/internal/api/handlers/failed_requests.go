@@ -0,0 +1,334 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the dead-letter log of chat requests that failed on
+// every provider and key, and its admin endpoints.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"llm-router-platform/internal/crypto"
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/audit"
+	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/internal/service/router"
+	"llm-router-platform/pkg/sanitize"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Size caps on what failed request capture stores per request.
+const (
+	maxCapturedPayload   = 64 << 10
+	maxCapturedErrorBody = 8 << 10
+)
+
+// failedRequestLimiter caps dead-letter writes per minute so a provider
+// outage, when every request fails, cannot flood the table.
+type failedRequestLimiter struct {
+	mu        sync.Mutex
+	perMinute int
+	window    time.Time
+	count     int
+}
+
+// allow reports whether another failed request may be recorded at now.
+func (l *failedRequestLimiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if window := now.Truncate(time.Minute); !window.Equal(l.window) {
+		l.window, l.count = window, 0
+	}
+	if l.count >= l.perMinute {
+		return false
+	}
+	l.count++
+	return true
+}
+
+// SetFailedRequestLog enables the dead-letter log of chat requests that
+// failed on every provider and key, writing at most perMinute records per
+// minute to repo. Zero or a nil repo disables it.
+func (h *ChatHandler) SetFailedRequestLog(repo repository.FailedRequestRepo, perMinute int) {
+	if perMinute <= 0 || repo == nil {
+		h.failedRequests, h.failedLimiter = nil, nil
+		return
+	}
+	h.failedRequests = repo
+	h.failedLimiter = &failedRequestLimiter{perMinute: perMinute}
+}
+
+// SetFailedRequestCapture turns on error-only capture: the dead-letter log
+// also records requests that failed with a 4xx upstream error, and keeps the
+// request payload and upstream error bodies of each record, encrypted.
+// Successful traffic is never captured. Payloads are only stored when an
+// encryption key is configured.
+func (h *ChatHandler) SetFailedRequestCapture(enabled bool) {
+	h.captureFailed = enabled
+	if enabled && !crypto.IsInitialized() {
+		h.logger.Warn("failed request capture needs an encryption key; payloads will not be stored")
+	}
+}
+
+// recordFailedRequest adds a chat request to the dead-letter log when it ended
+// in a server error (or, with capture, any error) after every upstream attempt
+// failed. Requests answered without an upstream call, client cancellations and
+// requests that succeeded after a retry are not recorded.
+func (h *ChatHandler) recordFailedRequest(c *gin.Context, req *ChatCompletionRequest, attempts *router.Attempts) {
+	status := c.Writer.Status()
+	minStatus := http.StatusInternalServerError
+	if h.captureFailed {
+		minStatus = http.StatusBadRequest
+	}
+	if h.failedLimiter == nil || h.failedRequests == nil || status < minStatus {
+		return
+	}
+	list := attempts.All()
+	if len(list) == 0 || list[len(list)-1].Err == nil {
+		return
+	}
+	if !h.failedLimiter.allow(time.Now()) {
+		return
+	}
+
+	failed := make([]models.FailedAttempt, len(list))
+	for i, at := range list {
+		failed[i] = models.FailedAttempt{ProviderID: at.ProviderID, Provider: at.Provider, KeyAlias: at.KeyAlias}
+		if at.Err != nil {
+			failed[i].Error = sanitize.TruncateErrorMessage(at.Err.Error())
+		}
+	}
+	attemptsJSON, _ := json.Marshal(failed)
+
+	rec := &models.FailedRequest{
+		RequestID:    requestID(c),
+		ModelName:    req.Model,
+		Stream:       req.Stream,
+		AttemptCount: len(list),
+		Attempts:     attemptsJSON,
+		StatusCode:   status,
+		ErrorMessage: failed[len(failed)-1].Error,
+	}
+	if v, ok := c.Get("project"); ok {
+		if p, ok := v.(*models.Project); ok {
+			rec.ProjectID = p.ID
+		}
+	}
+	if v, ok := c.Get("api_key"); ok {
+		if k, ok := v.(*models.APIKey); ok {
+			rec.APIKeyID = k.ID
+		}
+	}
+	if h.captureFailed && crypto.IsInitialized() {
+		if err := captureFailure(rec, req, list); err != nil {
+			h.logger.Warn("failed to capture failed request payload", zap.Error(err))
+		}
+	}
+	if err := h.failedRequests.Create(context.WithoutCancel(c.Request.Context()), rec); err != nil {
+		h.logger.Warn("failed to record failed request", zap.Error(err))
+	}
+}
+
+// captureFailure stores the encrypted request payload and upstream error
+// bodies on rec. Provider errors keep their raw response body; other errors
+// their message.
+func captureFailure(rec *models.FailedRequest, req *ChatCompletionRequest, list []router.Attempt) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var bodies []models.CapturedError
+	for _, at := range list {
+		if at.Err == nil {
+			continue
+		}
+		ce := models.CapturedError{Provider: at.Provider, KeyAlias: at.KeyAlias, Body: at.Err.Error()}
+		var pe *provider.ProviderError
+		if errors.As(at.Err, &pe) {
+			ce.StatusCode = pe.StatusCode
+			if len(pe.Body) > 0 {
+				ce.Body = string(pe.Body)
+			}
+		}
+		ce.Body = truncateCapture(ce.Body, maxCapturedErrorBody)
+		bodies = append(bodies, ce)
+	}
+	bodiesJSON, err := json.Marshal(bodies)
+	if err != nil {
+		return err
+	}
+
+	encPayload, err := crypto.Encrypt(truncateCapture(string(payload), maxCapturedPayload))
+	if err != nil {
+		return err
+	}
+	encBodies, err := crypto.Encrypt(string(bodiesJSON))
+	if err != nil {
+		return err
+	}
+	rec.Payload, rec.ErrorBodies, rec.Captured = encPayload, encBodies, true
+	return nil
+}
+
+// truncateCapture cuts s to at most limit bytes, marking the cut. The cut
+// backs off to a rune boundary so the stored text stays valid UTF-8.
+func truncateCapture(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "...[truncated]"
+}
+
+// FailedRequestHandler serves the dead-letter log to admins for triage.
+type FailedRequestHandler struct {
+	repo         repository.FailedRequestRepo
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewFailedRequestHandler creates a new failed request handler.
+func NewFailedRequestHandler(repo repository.FailedRequestRepo, auditService *audit.Service, logger *zap.Logger) *FailedRequestHandler {
+	return &FailedRequestHandler{repo: repo, auditService: auditService, logger: logger}
+}
+
+// failedRequestWindow parses the "hours" query parameter (default 24, at most
+// 30 days) into the start of the window.
+func failedRequestWindow(c *gin.Context) time.Time {
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if hours < 1 || hours > 720 {
+		hours = 24
+	}
+	return time.Now().Add(-time.Duration(hours) * time.Hour)
+}
+
+// List godoc
+// @Summary List failed chat requests
+// @Description Chat requests that failed on every provider and key they were tried on, newest first, with each upstream attempt and its error.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param model query string false "Requested model"
+// @Param provider query string false "Provider name appearing in any attempt"
+// @Param hours query int false "Look-back window in hours (default 24, max 720)"
+// @Param page query int false "Page (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Router /api/v1/admin/failed-requests [get]
+func (h *FailedRequestHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	filter := repository.FailedRequestFilter{
+		ModelName: c.Query("model"),
+		Provider:  c.Query("provider"),
+		Since:     failedRequestWindow(c),
+	}
+	reqs, total, err := h.repo.List(c.Request.Context(), filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reqs, "total": total, "page": page, "page_size": pageSize})
+}
+
+// Summary godoc
+// @Summary Summarize failed chat requests
+// @Description Failed request counts per model and per attempted provider. Failures spread across models on one provider point to a provider outage; failures of one model across providers point to the model.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param hours query int false "Look-back window in hours (default 24, max 720)"
+// @Router /api/v1/admin/failed-requests/summary [get]
+func (h *FailedRequestHandler) Summary(c *gin.Context) {
+	ctx := c.Request.Context()
+	since := failedRequestWindow(c)
+	byModel, err := h.repo.CountByModelSince(ctx, since)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	byProvider, err := h.repo.CountByProviderSince(ctx, since)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"since": since, "by_model": byModel, "by_provider": byProvider})
+}
+
+// Capture godoc
+// @Summary Get the captured payload of a failed chat request
+// @Description The request payload and upstream error bodies kept by FAILED_REQUEST_CAPTURE, decrypted. 404 when nothing was captured or the capture has expired.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Failed request ID"
+// @Router /api/v1/admin/failed-requests/{id}/capture [get]
+func (h *FailedRequestHandler) Capture(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid failed request ID"})
+		return
+	}
+	rec, err := h.repo.GetByID(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !rec.Captured) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no capture for this failed request"})
+		return
+	}
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+
+	payload, err := crypto.Decrypt(rec.Payload)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	bodiesJSON, err := crypto.Decrypt(rec.ErrorBodies)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	var bodies []models.CapturedError
+	if err := json.Unmarshal([]byte(bodiesJSON), &bodies); err != nil {
+		h.internalError(c, err)
+		return
+	}
+
+	// Captures hold user prompts in clear text once decrypted, so every read
+	// is audited with the admin who made it.
+	if h.auditService != nil {
+		actorID, _ := uuid.Parse(c.GetString("user_id"))
+		h.auditService.Log(c.Request.Context(), audit.ActionCaptureRead, actorID, rec.ID, c.ClientIP(), c.Request.UserAgent(),
+			map[string]interface{}{"request_id": rec.RequestID, "model": rec.ModelName})
+	}
+
+	// A payload cut at the size cap is no longer valid JSON; return it as text.
+	var body interface{} = payload
+	if json.Valid([]byte(payload)) {
+		body = json.RawMessage(payload)
+	}
+	c.JSON(http.StatusOK, gin.H{"id": rec.ID, "request_id": rec.RequestID, "payload": body, "upstream_errors": bodies})
+}
+
+func (h *FailedRequestHandler) internalError(c *gin.Context, err error) {
+	h.logger.Error("failed request log read failed", zap.String("path", c.FullPath()), zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load failed requests"})
+}
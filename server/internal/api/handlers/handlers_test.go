@@ -1,13 +1,43 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"llm-router-platform/internal/crypto"
+	router_errs "llm-router-platform/internal/errors"
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/admin"
+	"llm-router-platform/internal/service/audit"
+	"llm-router-platform/internal/service/billing"
+	semantic "llm-router-platform/internal/service/cache"
+	"llm-router-platform/internal/service/health"
+	"llm-router-platform/internal/service/observability"
+	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/internal/service/router"
+	"llm-router-platform/internal/service/user"
 )
 
 func init() {
@@ -168,6 +198,314 @@ func TestChatHandlerValidation(t *testing.T) {
 	}
 }
 
+func TestValidateMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"valid", `[{"role":"system","content":"be nice"},{"role":"user","content":"hi"}]`, ""},
+		{"assistant tool call with null content", `[{"role":"user","content":"hi"},{"role":"assistant","content":null},{"role":"tool","content":"42"}]`, ""},
+		{"image-only content", `[{"role":"user","content":[{"type":"image_url","image_url":{"url":"https://example.com/a.png"}}]}]`, ""},
+		{"typo in role", `[{"role":"user","content":"hi"},{"role":"users","content":"again"}]`, "messages[1].role"},
+		{"missing role", `[{"content":"hi"}]`, "messages[0].role"},
+		{"empty user content", `[{"role":"user","content":""}]`, "messages[0].content"},
+		{"only system", `[{"role":"system","content":"rules"}]`, "non-system"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msgs []MessageRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &msgs))
+			err := validateMessages(msgs)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateSamplingParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"defaults", `{}`, ""},
+		{"all in range", `{"temperature":1,"top_p":0.9,"frequency_penalty":-1,"presence_penalty":2,"n":2,"stop":"END"}`, ""},
+		{"top_p above one", `{"top_p":1.5}`, "top_p"},
+		{"negative temperature", `{"temperature":-0.1}`, "temperature"},
+		{"frequency penalty too low", `{"frequency_penalty":-3}`, "frequency_penalty"},
+		{"presence penalty too high", `{"presence_penalty":2.5}`, "presence_penalty"},
+		{"negative n", `{"n":-1}`, "n must"},
+		{"too many stops", `{"stop":["a","b","c","d","e"]}`, "stop"},
+		{"json mode", `{"response_format":{"type":"json_object"}}`, ""},
+		{"json schema", `{"response_format":{"type":"json_schema","json_schema":{"name":"x","schema":{"type":"object"}}}}`, ""},
+		{"json schema without schema", `{"response_format":{"type":"json_schema"}}`, "json_schema"},
+		{"unknown response format", `{"response_format":{"type":"yaml"}}`, "response_format.type"},
+		{"tools with forced function", `{"tools":[{"type":"function","function":{"name":"get_weather"}}],"tool_choice":{"type":"function","function":{"name":"get_weather"}}}`, ""},
+		{"tool without name", `{"tools":[{"type":"function","function":{}}]}`, "function.name"},
+		{"forced function not in tools", `{"tools":[{"type":"function","function":{"name":"a"}}],"tool_choice":{"type":"function","function":{"name":"b"}}}`, "not in tools"},
+		{"tool choice without tools", `{"tool_choice":"required"}`, "requires tools"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req ChatCompletionRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+			err := validateSamplingParams(&req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestChatHandlerMessageLimits(t *testing.T) {
+	h := &ChatHandler{logger: zap.NewNop()}
+	h.SetMessageLimits(2, 5)
+	msgs := func(texts ...string) []provider.FlexibleContent {
+		out := make([]provider.FlexibleContent, len(texts))
+		for i, text := range texts {
+			out[i] = provider.StringContent(text)
+		}
+		return out
+	}
+	tests := []struct {
+		name    string
+		key     *models.APIKey
+		msgs    []provider.FlexibleContent
+		wantErr string
+	}{
+		{"within limits", &models.APIKey{}, msgs("hi", "héllo"), ""},
+		{"too many messages", &models.APIKey{}, msgs("a", "b", "c"), "at most 2"},
+		{"message too long", &models.APIKey{}, msgs("hi", "hello!"), "messages[1].content"},
+		{"key raises the limits", &models.APIKey{MaxMessages: 3, MaxMessageChars: 6}, msgs("a", "b", "hello!"), ""},
+		{"key lowers the limits", &models.APIKey{MaxMessages: 1}, msgs("a", "b"), "at most 1"},
+		{"key lifts the limits", &models.APIKey{MaxMessages: -1, MaxMessageChars: -1}, msgs("a", "b", "a long message"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set("api_key", tt.key)
+			err := h.checkMessageLimits(c, tt.msgs)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	h.SetMessageLimits(0, 0)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.NoError(t, h.checkMessageLimits(c, msgs("a", "b", "c", "a long message")), "zero means unlimited")
+}
+
+func TestChatCompletionRejectsTooManyMessages(t *testing.T) {
+	h := &ChatHandler{logger: zap.NewNop()}
+	h.SetMessageLimits(1, 0)
+	router := gin.New()
+	router.POST("/v1/chat/completions", h.ChatCompletion)
+
+	w := httptest.NewRecorder()
+	body := `{"model":"gpt-4o","messages":[{"role":"system","content":"rules"},{"role":"user","content":"hi"}]}`
+	req, _ := http.NewRequest("POST", "/v1/chat/completions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "at most 1 are allowed")
+}
+
+func TestChatHandlerProviderOverrideRequiresAdmin(t *testing.T) {
+	h := &ChatHandler{logger: zap.NewNop()}
+	router := gin.New()
+	router.POST("/chat", func(c *gin.Context) {
+		c.Set("api_key", &models.APIKey{})
+		if _, _, ok := h.routeChat(c, "gpt-4"); ok {
+			c.Status(http.StatusOK)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/chat", nil)
+	req.Header.Set(providerOverrideHeader, "openai")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "permission_error")
+}
+
+type pricedModelRepo struct {
+	repository.ModelRepo
+	model *models.Model
+}
+
+func (r *pricedModelRepo) GetByName(_ context.Context, _ string) (*models.Model, error) {
+	return r.model, nil
+}
+
+func TestChatHandlerRequestCostLimit(t *testing.T) {
+	model := &models.Model{Name: "gpt-4o", InputPricePer1K: 0.005, OutputPricePer1K: 0.015, MaxTokens: 4096}
+	h := &ChatHandler{
+		billing: billing.NewService(nil, &pricedModelRepo{model: model}, nil, zap.NewNop()),
+		logger:  zap.NewNop(),
+	}
+	chatReq := ChatCompletionRequest{
+		Model:     "gpt-4o",
+		Messages:  []MessageRequest{{Role: "user", Content: provider.StringContent("hello")}},
+		MaxTokens: 2000,
+	}
+	run := func(limit float64) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.POST("/chat", func(c *gin.Context) {
+			c.Set("api_key", &models.APIKey{MaxRequestCostUSD: limit})
+			if !h.exceedsRequestCostLimit(c, chatReq) {
+				c.Status(http.StatusOK)
+			}
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/chat", nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, run(0).Code, "zero means unlimited")
+	assert.Equal(t, http.StatusOK, run(1).Code)
+
+	w := run(0.01)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	var body struct {
+		Error struct {
+			Code          string  `json:"code"`
+			EstimatedCost float64 `json:"estimated_cost_usd"`
+			Limit         float64 `json:"max_request_cost_usd"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "LLM_ROUTER_ERR_012", body.Error.Code)
+	assert.Greater(t, body.Error.EstimatedCost, 0.03)
+	assert.Equal(t, 0.01, body.Error.Limit)
+}
+
+func TestWriteKeysExhausted(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	writeKeysExhausted(c, router.ErrAllKeysRateLimited)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	writeKeysExhausted(c, fmt.Errorf("route: %w", router.ErrAllKeysFailed))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "300", w.Header().Get("Retry-After"))
+	assert.NotContains(t, w.Body.String(), "model_not_found")
+}
+
+func TestModelSuggestionsInErrorResponses(t *testing.T) {
+	resp := withSuggestions(map[string]interface{}{"error": map[string]interface{}{"code": "x"}}, []string{"gpt-4"})
+	assert.Equal(t, []string{"gpt-4"}, resp["error"].(map[string]interface{})["suggestions"])
+	resp = withSuggestions(map[string]interface{}{"error": map[string]interface{}{"code": "x"}}, nil)
+	assert.NotContains(t, resp["error"], "suggestions")
+
+	// Only an upstream 404 looks up suggestions; h has no router to consult.
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	h := &ChatHandler{}
+	assert.Nil(t, h.modelSuggestions(c, "gpt4", &provider.ProviderError{StatusCode: http.StatusTooManyRequests}))
+	assert.Nil(t, h.modelSuggestions(c, "gpt4", errors.New("connection refused")))
+}
+
+func TestChatHandlerDeadlineFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured time.Duration
+		header     string
+		want       time.Duration
+		wantErr    bool
+	}{
+		{"configured default", 600 * time.Second, "", 600 * time.Second, false},
+		{"no deadline", 0, "", 0, false},
+		{"header shortens", 600 * time.Second, "30", 30 * time.Second, false},
+		{"header cannot extend", 60 * time.Second, "120", 60 * time.Second, false},
+		{"header without configured deadline", 0, "45", 45 * time.Second, false},
+		{"zero rejected", 600 * time.Second, "0", 0, true},
+		{"negative rejected", 600 * time.Second, "-5", 0, true},
+		{"non-numeric rejected", 600 * time.Second, "soon", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &ChatHandler{requestDeadline: tt.configured}
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("POST", "/chat", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(requestTimeoutHeader, tt.header)
+			}
+			got, err := h.deadlineFor(c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDeadlineExceededIsNotClientCancel(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	c.Request, _ = http.NewRequestWithContext(ctx, "POST", "/chat", nil)
+
+	assert.True(t, deadlineExceeded(c, ctx.Err()))
+	assert.False(t, deadlineExceeded(c, nil), "a response that beat the deadline is served")
+	assert.False(t, isClientCanceled(c, ctx.Err()))
+
+	canceledCtx, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	c.Request = c.Request.WithContext(canceledCtx)
+	assert.False(t, deadlineExceeded(c, context.Canceled))
+	assert.True(t, isClientCanceled(c, context.Canceled))
+}
+
+func TestDeadlineExceededError(t *testing.T) {
+	e := deadlineExceededError(context.DeadlineExceeded)
+	assert.Equal(t, http.StatusGatewayTimeout, e.HTTPStatus)
+	body, _ := json.Marshal(e.MapToOpenAIResponse())
+	assert.Contains(t, string(body), "LLM_ROUTER_ERR_001")
+	assert.Contains(t, string(body), deadlineExceededMessage)
+}
+
+func TestApplyOutputTokenLimit(t *testing.T) {
+	p := &models.Provider{DefaultMaxTokens: 1024, MaxOutputTokens: 4096}
+	run := func(requested int) (int, string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req := &provider.ChatRequest{MaxTokens: requested}
+		applyOutputTokenLimit(c, p, req)
+		return req.MaxTokens, w.Header().Get(maxTokensClampedHeader)
+	}
+
+	got, header := run(0)
+	assert.Equal(t, 1024, got)
+	assert.Empty(t, header)
+
+	got, header = run(2000)
+	assert.Equal(t, 2000, got)
+	assert.Empty(t, header)
+
+	got, header = run(10000)
+	assert.Equal(t, 4096, got)
+	assert.Equal(t, "4096", header)
+}
 
 func TestAPIKeyHandlerValidation(t *testing.T) {
 	router := gin.New()
@@ -279,6 +617,31 @@ func TestProxyHandlerValidation(t *testing.T) {
 	}
 }
 
+func TestProxyHandlerResetStatsValidation(t *testing.T) {
+	h := NewProxyHandler(nil, nil, zap.NewNop())
+	router := gin.New()
+	router.POST("/proxies/reset-stats", h.ResetAllStats)
+	router.POST("/proxies/:id/reset-stats", h.ResetStats)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"invalid id", "/proxies/not-a-uuid/reset-stats", ""},
+		{"ids not a list", "/proxies/reset-stats", `{"ids":"all"}`},
+		{"malformed id in list", "/proxies/reset-stats", `{"ids":["nope"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
 func TestProviderHandlerValidation(t *testing.T) {
 	router := gin.New()
 	router.POST("/providers", func(c *gin.Context) {
@@ -385,3 +748,1120 @@ func TestTTSHandlerValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatOpenAIModel(t *testing.T) {
+	mi := provider.ModelInfo{
+		ID: "gpt-4o",
+		Extra: map[string]json.RawMessage{
+			"created":      json.RawMessage(`"not-a-timestamp"`),
+			"owned_by":     json.RawMessage(`"system"`),
+			"capabilities": json.RawMessage(`{"vision":true}`),
+		},
+	}
+
+	m := formatOpenAIModel(mi, "openai", 1700000000)
+
+	assert.Equal(t, "gpt-4o", m["id"])
+	assert.Equal(t, "model", m["object"])
+	assert.Equal(t, int64(1700000000), m["created"])
+	assert.Equal(t, "openai", m["owned_by"])
+	assert.Equal(t, json.RawMessage(`{"vision":true}`), m["capabilities"])
+}
+
+func TestDowngradedStreamChunk(t *testing.T) {
+	resp := &provider.ChatResponse{
+		ID:    "chatcmpl-1",
+		Model: "gpt-4o",
+		Choices: []provider.Choice{{
+			Index:        0,
+			Message:      provider.Message{Role: "assistant", Content: provider.FlexibleContent{Text: "hello world"}},
+			FinishReason: "stop",
+		}},
+		Usage: provider.Usage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7},
+	}
+
+	chunk := downgradedStreamChunk(resp)
+
+	assert.Equal(t, "chatcmpl-1", chunk.ID)
+	assert.Len(t, chunk.Choices, 1)
+	assert.Equal(t, "assistant", chunk.Choices[0].Delta.Role)
+	assert.Equal(t, "hello world", chunk.Choices[0].Delta.Content)
+	assert.Equal(t, "stop", chunk.Choices[0].FinishReason)
+	assert.Equal(t, 7, chunk.Usage.TotalTokens)
+}
+
+func TestRealtimeStatsCounters(t *testing.T) {
+	s := NewRealtimeStats()
+
+	endChat := s.begin("openai", false)
+	endStream := s.begin("openai", true)
+	endOther := s.begin("anthropic", true)
+
+	snap := s.Snapshot()
+	assert.Equal(t, int64(3), snap.InFlight)
+	assert.Equal(t, int64(2), snap.ActiveStreams)
+	assert.Equal(t, int64(3), snap.RequestsLastMinute)
+	assert.Equal(t, map[string]int64{"openai": 2, "anthropic": 1}, snap.InFlightByProvider)
+
+	endChat()
+	endStream()
+	endOther()
+
+	snap = s.Snapshot()
+	assert.Zero(t, snap.InFlight)
+	assert.Zero(t, snap.ActiveStreams)
+	assert.Empty(t, snap.InFlightByProvider)
+	assert.Equal(t, int64(3), snap.RequestsLastMinute, "finished requests still count toward the last minute")
+}
+
+func TestRealtimeStatsRequestsWindow(t *testing.T) {
+	s := NewRealtimeStats()
+	now := time.Now()
+	s.recordRequest(now.Add(-90 * time.Second))
+	s.recordRequest(now.Add(-30 * time.Second))
+	s.recordRequest(now)
+
+	assert.Equal(t, int64(2), s.requestsSince(now))
+}
+
+func TestSetStreamWriteDeadlineOutlivesServerWriteTimeout(t *testing.T) {
+	h := &ChatHandler{logger: zap.NewNop()}
+	router := gin.New()
+	router.GET("/stream", func(c *gin.Context) {
+		h.setStreamWriteDeadline(c)
+		c.Header("Content-Type", "text/event-stream")
+		_, _ = c.Writer.WriteString("data: one\n\n")
+		c.Writer.Flush()
+		time.Sleep(300 * time.Millisecond)
+		_, _ = c.Writer.WriteString("data: two\n\n")
+		c.Writer.Flush()
+	})
+
+	srv := httptest.NewUnstartedServer(router)
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/stream")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "data: one\n\ndata: two\n\n", string(body))
+}
+
+func TestCryptoHandlerRekeyValidation(t *testing.T) {
+	h := NewCryptoHandler(admin.NewService(nil, nil, nil, zap.NewNop()), nil, zap.NewNop())
+	router := gin.New()
+	router.POST("/rekey", h.Rekey)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing old key", `{"dry_run":true}`},
+		{"old key wrong length", `{"old_key":"too-short","dry_run":true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/rekey", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestAdminDashboardHandlerRequiresUserID(t *testing.T) {
+	h := NewAdminDashboardHandler(nil, nil, nil, zap.NewNop())
+	router := gin.New()
+	router.GET("/stats", h.Stats)
+
+	for _, query := range []string{"", "?user_id=not-a-uuid", "?user_id=" + uuid.NewString() + "&org_id=bad"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/stats"+query, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestStreamChoicesDemuxesByIndex(t *testing.T) {
+	delta := func(idx int, content string) provider.StreamChunk {
+		return provider.StreamChunk{Choices: []provider.DeltaChoice{{Index: idx, Delta: provider.Delta{Content: content}}}}
+	}
+	// Interleaved two-choice stream, as OpenAI sends it for n=2.
+	stream := []provider.StreamChunk{
+		delta(0, "Hello"),
+		delta(1, "Hi"),
+		delta(1, " there"),
+		delta(0, " world"),
+		{Choices: []provider.DeltaChoice{{Index: 0, FinishReason: "stop"}, {Index: 1, FinishReason: "stop"}}},
+	}
+
+	choices := streamChoices{}
+	for _, chunk := range stream {
+		choices.add(chunk)
+	}
+	texts := choices.texts()
+	assert.Equal(t, []string{"Hello world", "Hi there"}, texts)
+
+	req := &provider.ChatRequest{Model: "gpt-4", N: 2}
+	_, both := estimateStreamTokens(req, texts)
+	_, first := estimateStreamTokens(req, texts[:1])
+	assert.Greater(t, both, first, "completion tokens cover every choice")
+}
+
+func TestStreamBudgetCutsOffWhenEstimateExceedsLimit(t *testing.T) {
+	h := &ChatHandler{logger: zap.NewNop()}
+	req := &provider.ChatRequest{Model: "gpt-4"}
+	assert.Nil(t, h.newStreamBudget(context.Background(), req, &models.APIKey{StreamBudgetCutoff: true, MaxRequestCostUSD: 1}, nil), "no billing service, nothing to price")
+
+	// $1 per 1K output tokens with $0.10 spent on the prompt leaves room for
+	// about 900 completion tokens.
+	b := &streamBudget{model: "gpt-4", limitUSD: 1, promptCostUSD: 0.1, outputPer1K: 1}
+	delta := provider.StreamChunk{Choices: []provider.DeltaChoice{{Delta: provider.Delta{Content: strings.Repeat("hello ", 100)}}}}
+	exceeded := false
+	chunks := 0
+	for !exceeded && chunks < 100 {
+		exceeded = b.add(delta)
+		chunks++
+	}
+	assert.True(t, exceeded)
+	assert.Greater(t, chunks, 1, "the first chunks fit the budget")
+	assert.Greater(t, b.costUSD(), b.limitUSD)
+
+	body := b.exceededError()["error"].(map[string]interface{})
+	assert.Equal(t, "budget_exceeded", body["type"])
+	assert.Equal(t, string(router_errs.ErrCodeBudgetExceeded), body["code"])
+	assert.Equal(t, 1.0, body["budget_limit_usd"])
+
+	var unlimited *streamBudget
+	assert.False(t, unlimited.add(delta), "keys without the cutoff are never stopped")
+}
+
+func TestProviderHandlerTestConfigValidation(t *testing.T) {
+	h := NewProviderHandler(nil, nil, zap.NewNop())
+	router := gin.New()
+	router.POST("/providers/test", h.TestConfig)
+
+	for _, body := range []string{`{"base_url":"https://api.openai.com/v1"}`, `{"name":"openai","base_url":"not a url"}`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/providers/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestProviderHandlerCreateValidation(t *testing.T) {
+	h := NewProviderHandler(nil, nil, zap.NewNop())
+	router := gin.New()
+	router.POST("/providers", h.Create)
+
+	for _, body := range []string{`{"base_url":"https://api.openai.com/v1"}`, `{"name":"openai","base_url":"not a url"}`, `{"name":"openai","base_url":"https://x","priority":"high"}`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/providers", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestProviderHandlerBatchCreateKeysValidation(t *testing.T) {
+	h := NewProviderHandler(nil, nil, zap.NewNop())
+	r := gin.New()
+	r.POST("/providers/:id/keys/batch", h.BatchCreateKeys)
+
+	id := uuid.New().String()
+	tooMany := "[" + strings.TrimSuffix(strings.Repeat(`{"api_key":"sk"},`, router.MaxProviderKeyImport+1), ",") + "]"
+	for _, tc := range []struct{ path, body string }{
+		{"/providers/not-a-uuid/keys/batch", `[{"api_key":"sk"}]`},
+		{"/providers/" + id + "/keys/batch", `[]`},
+		{"/providers/" + id + "/keys/batch", `{"api_key":"sk"}`},
+		{"/providers/" + id + "/keys/batch", tooMany},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, tc.path)
+	}
+}
+
+func TestProviderHandlerCapabilitiesRejectsBadID(t *testing.T) {
+	h := NewProviderHandler(nil, nil, zap.NewNop())
+	router := gin.New()
+	router.GET("/providers/:id/capabilities", h.Capabilities)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/providers/not-a-uuid/capabilities", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	router.GET("/providers/:id", h.Get)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/providers/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	router.POST("/providers/:id/drain", h.Drain)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/providers/not-a-uuid/drain", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestChatHandlerEndUser(t *testing.T) {
+	key := &models.APIKey{}
+	key.ID = uuid.New()
+	other := &models.APIKey{}
+	other.ID = uuid.New()
+
+	h := &ChatHandler{}
+	assert.Empty(t, h.endUser("", key), "no injection unless enabled")
+	assert.Equal(t, "alice", h.endUser("alice", key))
+
+	h.SetEndUserInjection(true)
+	assert.Equal(t, "alice", h.endUser("alice", key), "client value wins")
+	injected := h.endUser("", key)
+	assert.True(t, strings.HasPrefix(injected, "key-"))
+	assert.NotContains(t, injected, key.ID.String(), "the key ID itself is not revealed")
+	assert.Equal(t, injected, h.endUser("", key), "stable per key")
+	assert.NotEqual(t, injected, h.endUser("", other))
+}
+
+func TestUsageRange(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	parse := func(query, defaultPeriod string) (time.Time, time.Time, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/usage?"+query, nil)
+		return usageRange(c, now, defaultPeriod)
+	}
+
+	start, end, err := parse("", periodMonthToDate)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), start, "defaults to month to date")
+	assert.Equal(t, now, end)
+
+	start, end, err = parse("", "30d")
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -30), start)
+	assert.Equal(t, now, end)
+
+	start, _, err = parse("period=7d", periodMonthToDate)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -7), start, "period overrides the default")
+
+	start, _, err = parse("period=mtd", "30d")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), start)
+
+	start, end, err = parse("start=2026-01-01&end=2026-01-31", "30d")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), end, "date-only end is inclusive")
+
+	start, end, err = parse("start=2026-02-10", "30d")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, now, end, "open end runs to now")
+
+	_, end, err = parse("start=2026-01-01T00:00:00Z&end=2026-01-02T06:30:00Z", periodMonthToDate)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 2, 6, 30, 0, 0, time.UTC), end)
+
+	for _, q := range []string{
+		"start=yesterday", "end=2026-13-01", "start=2026-02-01&end=2026-01-01", "start=2024-01-01&end=2026-01-01",
+		"period=week", "period=0d", "period=400d", "period=7d&start=2026-01-01",
+	} {
+		_, _, err := parse(q, periodMonthToDate)
+		assert.Error(t, err, q)
+	}
+}
+
+func TestUsageHandlerRejectsBadInput(t *testing.T) {
+	h := NewUsageHandler(nil, nil, zap.NewNop())
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", uuid.NewString()) })
+	r.GET("/usage/by-model", h.ByModel)
+
+	for _, q := range []string{"start=nope", "period=forever", "project_id=nope", "org_id=nope"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/usage/by-model?"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+
+	r.GET("/usage/by-tag", h.ByTag)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/usage/by-tag?key="+strings.Repeat("k", maxUsageTagKeyLen+1), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParseUsageTags(t *testing.T) {
+	tags, err := parseUsageTags("", nil)
+	require.NoError(t, err)
+	assert.Nil(t, tags, "no tags")
+
+	tags, err = parseUsageTags(" project=checkout, env = prod ,", map[string]string{"env": "staging", "team": "web"})
+	require.NoError(t, err)
+	assert.Equal(t, models.StringMap{"project": "checkout", "env": "staging", "team": "web"}, tags, "metadata wins over the header")
+
+	tooMany := map[string]string{}
+	for i := 0; i <= maxUsageTags; i++ {
+		tooMany[fmt.Sprintf("k%d", i)] = "v"
+	}
+	for name, tc := range map[string]struct {
+		header   string
+		metadata map[string]string
+	}{
+		"header without =": {header: "project"},
+		"empty key":        {header: "=x"},
+		"too many":         {metadata: tooMany},
+		"long key":         {metadata: map[string]string{strings.Repeat("k", maxUsageTagKeyLen+1): "v"}},
+		"long value":       {metadata: map[string]string{"k": strings.Repeat("v", maxUsageTagValueLen+1)}},
+	} {
+		_, err := parseUsageTags(tc.header, tc.metadata)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAlertHandlerTestWebhookValidation(t *testing.T) {
+	h := NewAlertHandler(nil, zap.NewNop())
+	router := gin.New()
+	router.POST("/alerts/config/test", h.TestWebhook)
+
+	for _, body := range []string{`{}`, `{"target_type":"provider","target_id":"nope"}`, `{"target_id":"` + uuid.NewString() + `"}`, `not json`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/alerts/config/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestAlertHandlerTestWebhookLookupFailureIsServerError(t *testing.T) {
+	db := newScriptedDB(t, nil)
+	notifier := health.NewAlertNotifier(repository.NewAlertRepository(db), repository.NewAlertConfigRepository(db), zap.NewNop(), false)
+	h := NewAlertHandler(health.NewService(nil, nil, nil, nil, nil, notifier, nil, nil, zap.NewNop(), false), zap.NewNop())
+	router := gin.New()
+	router.POST("/alerts/config/test", h.TestWebhook)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/alerts/config/test", strings.NewReader(`{"target_type":"provider","target_id":"`+uuid.NewString()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused", "database errors are not echoed to the client")
+}
+
+func TestHealthTrendWindow(t *testing.T) {
+	bucket, window, err := healthTrendWindow("1h", "7d")
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, bucket)
+	assert.Equal(t, 7*24*time.Hour, window)
+
+	_, window, err = healthTrendWindow("5m", "24h")
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, window)
+
+	for _, tc := range [][2]string{
+		{"2h", "7d"}, {"1h", "week"}, {"1h", "xd"}, {"1h", "91d"}, {"1d", "12h"}, {"5m", "30d"},
+	} {
+		_, _, err := healthTrendWindow(tc[0], tc[1])
+		assert.Error(t, err, tc)
+	}
+}
+
+func TestHealthTrendHandlerRejectsBadInput(t *testing.T) {
+	h := NewHealthTrendHandler(nil, zap.NewNop())
+	r := gin.New()
+	r.GET("/health/:target_type/:target_id/trend", h.Trend)
+
+	id := uuid.NewString()
+	for _, path := range []string{
+		"/health/user/" + id + "/trend",
+		"/health/provider/nope/trend",
+		"/health/proxy/" + id + "/trend?bucket=2h",
+		"/health/api_key/" + id + "/trend?range=1y",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+type failingWriter struct{ err error }
+
+func (f failingWriter) Write([]byte) (int, error) { return 0, f.err }
+
+func TestSSEWriterSurfacesWriteAndFlushErrors(t *testing.T) {
+	var buf strings.Builder
+	ok := sseWriter{w: &buf, flush: func() error { return nil }}
+	assert.NoError(t, ok.event([]byte(`{"id":"1"}`)))
+	assert.Equal(t, "data: {\"id\":\"1\"}\n\n", buf.String())
+
+	broken := errors.New("broken pipe")
+	assert.ErrorIs(t, sseWriter{w: failingWriter{err: broken}, flush: func() error { return nil }}.event([]byte("x")), broken)
+	assert.ErrorIs(t, sseWriter{w: &buf, flush: func() error { return broken }}.event([]byte("x")), broken)
+}
+
+func TestDrainStreamUnblocksProducer(t *testing.T) {
+	chunks := make(chan provider.StreamChunk)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(chunks)
+		for i := 0; i < 3; i++ {
+			chunks <- provider.StreamChunk{}
+		}
+	}()
+	drainStream(chunks)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("producer still blocked after drain")
+	}
+}
+
+func TestAdminModelHandlerRejectsBadInput(t *testing.T) {
+	h := NewAdminModelHandler(nil, nil, zap.NewNop())
+	r := gin.New()
+	r.PUT("/admin/models/:id", h.Update)
+	r.POST("/admin/models/import", h.Import)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/models/not-a-uuid", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/models/import", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestChatHandlerIdempotency(t *testing.T) {
+	mr := miniredis.RunT(t)
+	h := &ChatHandler{redis: redis.NewClient(&redis.Options{Addr: mr.Addr()}), logger: zap.NewNop()}
+	key := &models.APIKey{}
+	key.ID = uuid.New()
+	chatReq := ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []MessageRequest{{Role: "user", Content: provider.StringContent("hello")}},
+	}
+
+	calls := 0
+	succeed := true
+	router := gin.New()
+	router.POST("/chat", func(c *gin.Context) {
+		var req ChatCompletionRequest
+		require.NoError(t, c.ShouldBindJSON(&req))
+		c.Set("api_key", key)
+		idem, done := h.beginIdempotent(c, req)
+		if done {
+			return
+		}
+		if idem != nil {
+			c.Set(ctxKeyIdempotency, idem)
+			defer h.releaseIdempotent(idem)
+		}
+		calls++
+		if !succeed {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "upstream"})
+			return
+		}
+		body := gin.H{"id": "chatcmpl-1", "call": calls}
+		c.JSON(http.StatusOK, body)
+		h.storeIdempotent(c, body)
+	})
+	send := func(idemKey string, req ChatCompletionRequest) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(string(b)))
+		if idemKey != "" {
+			r.Header.Set(idempotencyKeyHeader, idemKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	first := send("retry-1", chatReq)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Empty(t, first.Header().Get(idempotentReplayHeader))
+
+	replay := send("retry-1", chatReq)
+	assert.Equal(t, http.StatusOK, replay.Code)
+	assert.Equal(t, "true", replay.Header().Get(idempotentReplayHeader))
+	assert.JSONEq(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, 1, calls, "a replay does not reach the provider")
+
+	other := chatReq
+	other.Model = "gpt-4o-mini"
+	assert.Equal(t, http.StatusUnprocessableEntity, send("retry-1", other).Code, "key reused with a different body")
+
+	require.NoError(t, mr.Set(idempotencyRedisKey(key, "in-flight"), `{"fingerprint":"`+requestFingerprint(chatReq)+`"}`))
+	assert.Equal(t, http.StatusConflict, send("in-flight", chatReq).Code)
+
+	succeed = false
+	assert.Equal(t, http.StatusBadGateway, send("retry-2", chatReq).Code)
+	assert.False(t, mr.Exists(idempotencyRedisKey(key, "retry-2")), "failures are not cached")
+	succeed = true
+	assert.Equal(t, http.StatusOK, send("retry-2", chatReq).Code)
+	assert.Equal(t, 3, calls, "a failed request can be retried")
+
+	stream := chatReq
+	stream.Stream = true
+	send("retry-3", stream)
+	assert.False(t, mr.Exists(idempotencyRedisKey(key, "retry-3")), "streaming requests are not cached")
+
+	assert.Equal(t, http.StatusBadRequest, send(strings.Repeat("k", maxIdempotencyKeyLen+1), chatReq).Code)
+}
+
+// routedProviderRepo serves a single active provider to the router.
+type routedProviderRepo struct {
+	repository.ProviderRepo
+	p models.Provider
+}
+
+func (r *routedProviderRepo) GetActive(context.Context) ([]models.Provider, error) {
+	return []models.Provider{r.p}, nil
+}
+
+func (r *routedProviderRepo) GetAll(context.Context) ([]models.Provider, error) {
+	return []models.Provider{r.p}, nil
+}
+
+func (r *routedProviderRepo) GetByID(context.Context, uuid.UUID) (*models.Provider, error) {
+	return &r.p, nil
+}
+
+func (r *routedProviderRepo) GetByName(context.Context, string) (*models.Provider, error) {
+	return &r.p, nil
+}
+
+// routedModelRepo lists the models served by routedProviderRepo.
+type routedModelRepo struct {
+	repository.ModelRepo
+	models []models.Model
+}
+
+func (r *routedModelRepo) GetByProvider(context.Context, uuid.UUID) ([]models.Model, error) {
+	return r.models, nil
+}
+
+// streamRecorder adds the CloseNotify that gin's Stream requires.
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (streamRecorder) CloseNotify() <-chan bool { return make(chan bool) }
+
+func TestChatHandlerRoutingHeaders(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"hi"}}]}`+"\n\n")
+			_, _ = io.WriteString(w, "data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"chatcmpl-1","model":"gpt-4o-2024-08-06","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer upstream.Close()
+
+	p := models.Provider{Name: "openai-eu", Type: "openai", BaseURL: upstream.URL, IsActive: true}
+	p.ID = uuid.New()
+	m := models.Model{ProviderID: p.ID, Name: "gpt-4o", IsActive: true}
+	m.ID = uuid.New()
+	db := newScriptedDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value) { return nil, nil })
+	rt := router.NewRouter(&routedProviderRepo{p: p}, repository.NewProviderAPIKeyRepository(db), repository.NewProxyRepository(db),
+		&routedModelRepo{models: []models.Model{m}}, repository.NewRoutingRuleRepository(db), repository.NewFallbackChainRepository(db),
+		provider.NewRegistry(zap.NewNop()), nil, zap.NewNop(), true)
+	h := NewChatHandler(rt, billing.NewService(repository.NewUsageLogRepository(db), &pricedModelRepo{model: &m}, nil, zap.NewNop()), nil, nil, nil, observability.NewNoopService(), db, semantic.NewSemanticCacheService(db, zap.NewNop(), 0), nil, nil, zap.NewNop())
+
+	project := &models.Project{}
+	project.ID = uuid.New()
+	key := &models.APIKey{ProjectID: project.ID}
+	key.ID = uuid.New()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("project", project)
+		c.Set("api_key", key)
+	})
+	r.POST("/v1/chat/completions", h.ChatCompletion)
+	send := func(stream bool) http.Header {
+		body := fmt.Sprintf(`{"model":"gpt-4o","stream":%t,"messages":[{"role":"user","content":"hello"}]}`, stream)
+		w := streamRecorder{httptest.NewRecorder()}
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		// Result reports the headers as they stood when the status was written.
+		return w.Result().Header
+	}
+
+	assert.Empty(t, send(false).Get(servedProviderHeader), "off by default")
+
+	h.SetRoutingHeaders(true)
+	hdr := send(false)
+	assert.Equal(t, "openai-eu", hdr.Get(servedProviderHeader))
+	assert.Equal(t, "gpt-4o-2024-08-06", hdr.Get(servedModelHeader), "the model the upstream reports")
+	assert.Equal(t, "1", hdr.Get(requestAttemptsHeader))
+
+	hdr = send(true)
+	assert.Equal(t, "openai-eu", hdr.Get(servedProviderHeader), "set before the first chunk")
+	assert.Equal(t, "gpt-4o", hdr.Get(servedModelHeader), "falls back to the model sent upstream")
+	assert.Equal(t, "1", hdr.Get(requestAttemptsHeader))
+}
+
+func TestFailedRequestLimiter(t *testing.T) {
+	l := &failedRequestLimiter{perMinute: 2}
+	now := time.Date(2026, 1, 1, 12, 0, 10, 0, time.UTC)
+	assert.True(t, l.allow(now))
+	assert.True(t, l.allow(now.Add(20*time.Second)))
+	assert.False(t, l.allow(now.Add(40*time.Second)), "cap reached within the minute")
+	assert.True(t, l.allow(now.Add(time.Minute)), "cap resets in the next minute")
+
+	h := &ChatHandler{}
+	repo := &failedRequestRepo{}
+	h.SetFailedRequestLog(repo, 0)
+	assert.Nil(t, h.failedLimiter, "zero disables the dead-letter log")
+	h.SetFailedRequestLog(nil, 5)
+	assert.Nil(t, h.failedLimiter, "no repository disables the dead-letter log")
+	h.SetFailedRequestLog(repo, 5)
+	require.NotNil(t, h.failedLimiter)
+	assert.Equal(t, 5, h.failedLimiter.perMinute)
+	assert.Same(t, repo, h.failedRequests)
+}
+
+// failedRequestRepo records the queries made by FailedRequestHandler.
+type failedRequestRepo struct {
+	repository.FailedRequestRepo
+	filter        repository.FailedRequestFilter
+	limit, offset int
+	err           error
+}
+
+func (r *failedRequestRepo) List(_ context.Context, filter repository.FailedRequestFilter, limit, offset int) ([]models.FailedRequest, int64, error) {
+	r.filter, r.limit, r.offset = filter, limit, offset
+	return []models.FailedRequest{{ModelName: filter.ModelName, StatusCode: 502}}, 41, r.err
+}
+
+func (r *failedRequestRepo) CountByModelSince(_ context.Context, since time.Time) ([]repository.FailedRequestCountRow, error) {
+	r.filter.Since = since
+	return []repository.FailedRequestCountRow{{Name: "gpt-4o", Requests: 3}}, r.err
+}
+
+func (r *failedRequestRepo) CountByProviderSince(context.Context, time.Time) ([]repository.FailedRequestCountRow, error) {
+	return []repository.FailedRequestCountRow{{Name: "openai", Requests: 2}, {Name: "azure", Requests: 1}}, r.err
+}
+
+func TestFailedRequestHandlerListAndSummary(t *testing.T) {
+	repo := &failedRequestRepo{}
+	h := NewFailedRequestHandler(repo, nil, zap.NewNop())
+	r := gin.New()
+	r.GET("/failed-requests", h.List)
+	r.GET("/failed-requests/summary", h.Summary)
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	w := get("/failed-requests?model=gpt-4o&provider=openai&hours=2&page=3&page_size=10")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gpt-4o", repo.filter.ModelName)
+	assert.Equal(t, "openai", repo.filter.Provider)
+	assert.WithinDuration(t, time.Now().Add(-2*time.Hour), repo.filter.Since, time.Minute)
+	assert.Equal(t, 10, repo.limit)
+	assert.Equal(t, 20, repo.offset)
+	var list struct {
+		Data     []models.FailedRequest `json:"data"`
+		Total    int64                  `json:"total"`
+		Page     int                    `json:"page"`
+		PageSize int                    `json:"page_size"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, int64(41), list.Total)
+	assert.Equal(t, 3, list.Page)
+	assert.Equal(t, 10, list.PageSize)
+
+	get("/failed-requests?hours=9999&page_size=500")
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), repo.filter.Since, time.Minute, "out-of-range window falls back to 24h")
+	assert.Equal(t, 20, repo.limit, "out-of-range page size falls back to 20")
+	assert.Equal(t, 0, repo.offset)
+
+	w = get("/failed-requests/summary?hours=6")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.WithinDuration(t, time.Now().Add(-6*time.Hour), repo.filter.Since, time.Minute)
+	var summary struct {
+		ByModel    []repository.FailedRequestCountRow `json:"by_model"`
+		ByProvider []repository.FailedRequestCountRow `json:"by_provider"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, []repository.FailedRequestCountRow{{Name: "gpt-4o", Requests: 3}}, summary.ByModel)
+	assert.Len(t, summary.ByProvider, 2)
+
+	repo.err = errors.New("connection refused")
+	assert.Equal(t, http.StatusInternalServerError, get("/failed-requests").Code)
+	w = get("/failed-requests/summary")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused", "database errors are not leaked")
+}
+
+func TestCaptureFailureEncryptsPayloadAndErrorBodies(t *testing.T) {
+	require.NoError(t, crypto.Initialize("0123456789abcdef0123456789abcdef"))
+	req := &ChatCompletionRequest{Model: "gpt-4", Messages: []MessageRequest{{Role: "user", Content: provider.FlexibleContent{Text: "secret prompt"}}}}
+	list := []router.Attempt{
+		{Provider: "openai", KeyAlias: "primary", Err: &provider.ProviderError{StatusCode: 400, Body: []byte(`{"error":"context_length_exceeded"}`)}},
+		{Provider: "azure", Err: errors.New("dial tcp: connection refused")},
+	}
+
+	rec := &models.FailedRequest{}
+	require.NoError(t, captureFailure(rec, req, list))
+	assert.True(t, rec.Captured)
+	assert.NotContains(t, rec.Payload, "secret prompt", "the payload is stored encrypted")
+
+	payload, err := crypto.Decrypt(rec.Payload)
+	require.NoError(t, err)
+	assert.Contains(t, payload, "secret prompt")
+	bodiesJSON, err := crypto.Decrypt(rec.ErrorBodies)
+	require.NoError(t, err)
+	var bodies []models.CapturedError
+	require.NoError(t, json.Unmarshal([]byte(bodiesJSON), &bodies))
+	require.Len(t, bodies, 2)
+	assert.Equal(t, models.CapturedError{Provider: "openai", KeyAlias: "primary", StatusCode: 400, Body: `{"error":"context_length_exceeded"}`}, bodies[0])
+	assert.Equal(t, "dial tcp: connection refused", bodies[1].Body)
+
+	assert.Equal(t, "abc...[truncated]", truncateCapture("abcdef", 3))
+	assert.Equal(t, "a...[truncated]", truncateCapture("a日本", 3), "the cut backs off to a rune boundary")
+
+	r := gin.New()
+	r.GET("/failed-requests/:id/capture", NewFailedRequestHandler(&failedRequestRepo{}, nil, zap.NewNop()).Capture)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/failed-requests/not-a-uuid/capture", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// captureRepo serves one captured failed request.
+type captureRepo struct {
+	repository.FailedRequestRepo
+	rec *models.FailedRequest
+}
+
+func (r *captureRepo) GetByID(context.Context, uuid.UUID) (*models.FailedRequest, error) {
+	return r.rec, nil
+}
+
+// auditLogRepo records the audit entries written through audit.Service.
+type auditLogRepo struct {
+	repository.AuditLogRepo
+	entries []models.AuditLog
+}
+
+func (r *auditLogRepo) Create(_ context.Context, entry *models.AuditLog) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func TestFailedRequestCaptureReadIsAudited(t *testing.T) {
+	require.NoError(t, crypto.Initialize("0123456789abcdef0123456789abcdef"))
+	rec := &models.FailedRequest{RequestID: "req-123", ModelName: "gpt-4"}
+	rec.ID = uuid.New()
+	require.NoError(t, captureFailure(rec, &ChatCompletionRequest{Model: "gpt-4"}, nil))
+
+	audits := &auditLogRepo{}
+	h := NewFailedRequestHandler(&captureRepo{rec: rec}, audit.NewService(audits, zap.NewNop()), zap.NewNop())
+	admin := uuid.New()
+	r := gin.New()
+	r.GET("/failed-requests/:id/capture", func(c *gin.Context) {
+		c.Set("user_id", admin.String())
+		h.Capture(c)
+	})
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/failed-requests/"+rec.ID.String()+"/capture", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	require.Len(t, audits.entries, 2, "every read is audited")
+	entry := audits.entries[0]
+	assert.Equal(t, audit.ActionCaptureRead, entry.Action)
+	assert.Equal(t, admin, entry.ActorID)
+	assert.Equal(t, rec.ID, entry.TargetID)
+	assert.Contains(t, entry.Detail, `"request_id":"req-123"`)
+}
+
+func TestProviderKeyStatus(t *testing.T) {
+	openai := models.ProviderAPIKey{Alias: "primary", KeyPrefix: "sk-abc", IsActive: true, UsageCount: 42}
+	openai.ID, openai.ProviderID = uuid.New(), uuid.New()
+	openai.Provider.Name = "openai"
+	anthropic := models.ProviderAPIKey{Alias: "backup"}
+	anthropic.ID, anthropic.ProviderID = uuid.New(), uuid.New()
+	anthropic.Provider.Name = "anthropic"
+
+	st := providerKeyStatus(openai, health.APIKeyHealthStatus{})
+	assert.Equal(t, keyHealthUnknown, st.Health, "never checked")
+	assert.Nil(t, st.LastUsedAt)
+	assert.Equal(t, int64(42), st.UsageCount)
+
+	checked := time.Now()
+	st = providerKeyStatus(openai, health.APIKeyHealthStatus{IsHealthy: false, LastCheck: checked, ResponseTime: 120})
+	assert.Equal(t, keyHealthUnhealthy, st.Health)
+	require.NotNil(t, st.LastCheck)
+	assert.Equal(t, int64(120), st.ResponseTimeMs)
+
+	b, _ := json.Marshal(st)
+	assert.NotContains(t, string(b), "encrypted", "the secret is never serialized")
+
+	keys := []models.ProviderAPIKey{openai, anthropic}
+	assert.Len(t, filterKeysByProvider(append([]models.ProviderAPIKey(nil), keys...), "OpenAI"), 1)
+	byID := filterKeysByProvider(append([]models.ProviderAPIKey(nil), keys...), anthropic.ProviderID.String())
+	require.Len(t, byID, 1)
+	assert.Equal(t, "backup", byID[0].Alias)
+
+	router := gin.New()
+	router.GET("/provider-keys", NewProviderKeyHandler(nil, nil, nil, zap.NewNop()).List)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/provider-keys?health=degraded", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestKeyCooldownStatuses(t *testing.T) {
+	key := func(alias string) models.ProviderAPIKey {
+		k := models.ProviderAPIKey{Alias: alias, KeyPrefix: "sk-" + alias, Provider: models.Provider{Name: "openai"}}
+		k.ID = uuid.New()
+		return k
+	}
+	keys := []models.ProviderAPIKey{key("healthy"), key("soon"), key("later")}
+	now := time.Now()
+
+	out := keyCooldownStatuses(keys, map[uuid.UUID]router.KeyCooldown{
+		keys[1].ID: {Reason: "rate limit", Remaining: 30 * time.Second},
+		keys[2].ID: {Reason: "insufficient_quota", Remaining: 4*time.Minute + 400*time.Millisecond},
+	}, now)
+
+	require.Len(t, out, 2)
+	assert.Equal(t, "later", out[0].Alias, "longest cooldown first")
+	assert.Equal(t, int64(240), out[0].RemainingSeconds)
+	assert.Equal(t, "insufficient_quota", out[0].Reason)
+	assert.Equal(t, "openai", out[1].ProviderName)
+	assert.Equal(t, now.Add(30*time.Second).UTC(), out[1].Until)
+
+	r := gin.New()
+	r.POST("/provider-keys/:id/clear-cooldown", NewProviderKeyHandler(nil, nil, nil, zap.NewNop()).ClearCooldown)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/provider-keys/not-a-uuid/clear-cooldown", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSummarizeSystemHealth(t *testing.T) {
+	provider := func(name string) models.Provider {
+		p := models.Provider{Name: name, IsActive: true, RequiresAPIKey: true}
+		p.ID = uuid.New()
+		return p
+	}
+	openai, anthropic, ollama := provider("openai"), provider("anthropic"), provider("ollama")
+	ollama.RequiresAPIKey = false
+	keys := []ProviderKeyStatus{
+		{ProviderID: openai.ID, IsActive: true, Health: keyHealthHealthy},
+		{ProviderID: anthropic.ID, IsActive: true, Health: keyHealthUnknown, InCooldown: true},
+		{ProviderID: anthropic.ID, IsActive: false, Health: keyHealthHealthy},
+	}
+	in := systemHealthInput{
+		providers: []models.Provider{openai, anthropic, ollama},
+		keys:      keys,
+		proxies:   []health.ProxyHealthStatus{{IsActive: true, IsHealthy: true}, {IsActive: true}},
+		database:  dependencyOK,
+		redis:     dependencyOK,
+	}
+
+	out := summarizeSystemHealth(in, time.Now())
+	assert.Equal(t, systemDegraded, out.Status)
+	assert.Equal(t, 3, out.Providers.Total)
+	assert.Equal(t, 2, out.Providers.Healthy)
+	require.Len(t, out.Providers.Unavailable, 1)
+	assert.Equal(t, "anthropic", out.Providers.Unavailable[0].Name)
+	assert.Equal(t, "no healthy API key", out.Providers.Unavailable[0].Reason)
+	assert.Equal(t, KeyHealthSummary{Total: 3, Active: 2, Healthy: 1, InCooldown: 1}, out.Keys)
+	assert.Equal(t, ProxyHealthSummary{Total: 2, Healthy: 1}, out.Proxies)
+	assert.Contains(t, out.Factors, "1 of 3 providers are not routable")
+
+	in.keys = keys[:2]
+	in.circuitOpen = map[uuid.UUID]bool{openai.ID: true}
+	in.providerHealth = map[uuid.UUID]health.ProviderHealthStatus{ollama.ID: {IsHealthy: false}}
+	out = summarizeSystemHealth(in, time.Now())
+	assert.Equal(t, systemDown, out.Status, "no provider is routable")
+	assert.Equal(t, "circuit open", out.Providers.Unavailable[0].Reason)
+	assert.Equal(t, "failing health checks", out.Providers.Unavailable[2].Reason)
+
+	in = systemHealthInput{providers: []models.Provider{ollama}, database: dependencyOK, redis: dependencyError}
+	out = summarizeSystemHealth(in, time.Now())
+	assert.Equal(t, systemDegraded, out.Status, "every provider routable but redis is down")
+	assert.Equal(t, []string{"redis unreachable"}, out.Factors)
+
+	in.redis = dependencyDisabled
+	assert.Equal(t, systemHealthy, summarizeSystemHealth(in, time.Now()).Status)
+	in.database = dependencyError
+	assert.Equal(t, systemDown, summarizeSystemHealth(in, time.Now()).Status)
+
+	proxied := ollama
+	proxied.UseProxy = true
+	in = systemHealthInput{
+		providers: []models.Provider{proxied},
+		proxies:   []health.ProxyHealthStatus{{IsActive: true}, {IsActive: false, IsHealthy: true}},
+		database:  dependencyOK,
+		redis:     dependencyOK,
+	}
+	out = summarizeSystemHealth(in, time.Now())
+	assert.Equal(t, systemDown, out.Status, "a proxied provider with no healthy active proxy is not routable")
+	require.Len(t, out.Providers.Unavailable, 1)
+	assert.Equal(t, "no healthy proxy", out.Providers.Unavailable[0].Reason)
+	in.proxies = append(in.proxies, health.ProxyHealthStatus{IsActive: true, IsHealthy: true})
+	assert.Equal(t, systemHealthy, summarizeSystemHealth(in, time.Now()).Status, "a healthy fallback proxy carries it")
+}
+
+// scriptedConn is a database/sql driver connection whose query results come
+// from a test function, so handlers built on the gorm-backed services can be
+// driven without Postgres. A nil respond fails every query, like an
+// unreachable database.
+type scriptedConn struct {
+	respond func(query string, args []driver.NamedValue) (columns []string, rows [][]driver.Value)
+}
+
+func (c *scriptedConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("scripted db: prepared statements are not supported")
+}
+func (c *scriptedConn) Close() error { return nil }
+func (c *scriptedConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("scripted db: no transactions")
+}
+func (c *scriptedConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *scriptedConn) Driver() driver.Driver                        { return nil }
+
+func (c *scriptedConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.respond == nil {
+		return nil, errors.New("scripted db: connection refused")
+	}
+	columns, rows := c.respond(query, args)
+	return &scriptedRows{columns: columns, rows: rows}, nil
+}
+
+func (c *scriptedConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+type scriptedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return r.columns }
+func (r *scriptedRows) Close() error      { return nil }
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newScriptedDB(t *testing.T, respond func(query string, args []driver.NamedValue) ([]string, [][]driver.Value)) *gorm.DB {
+	t.Helper()
+	sqlDB := sql.OpenDB(&scriptedConn{respond: respond})
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestUsageHandlerSummaryRangesDoNotShareCache(t *testing.T) {
+	db := newScriptedDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "organization_members"):
+			return []string{"role"}, [][]driver.Value{{"MEMBER"}}
+		case strings.Contains(query, "total_requests"):
+			// One request per minute of the window tells ranges apart.
+			start, end := args[0].Value.(time.Time), args[1].Value.(time.Time)
+			n := int64(end.Sub(start) / time.Minute)
+			return []string{"total_requests", "total_tokens", "total_cost", "avg_latency", "success_count", "error_count", "mcp_call_count", "mcp_error_count"},
+				[][]driver.Value{{n, n * 10, float64(n), 120.0, n, int64(0), int64(0), int64(0)}}
+		}
+		return nil, nil
+	})
+	mr := miniredis.RunT(t)
+	billingSvc := billing.NewService(repository.NewUsageLogRepository(db), nil, redis.NewClient(&redis.Options{Addr: mr.Addr()}), zap.NewNop())
+	userSvc := user.NewService(nil, nil, nil, repository.NewOrganizationRepository(db), zap.NewNop())
+	h := NewUsageHandler(userSvc, billingSvc, zap.NewNop())
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", uuid.NewString()) })
+	r.GET("/usage/summary", h.Summary)
+
+	orgID := uuid.NewString()
+	total := func(period string) int64 {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/usage/summary?org_id="+orgID+"&period="+period, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data billing.UsageSummary `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.TotalRequests
+	}
+
+	week := total("7d")
+	mtd := total("mtd")
+	assert.NotEqual(t, week, mtd, "month to date must not reuse the 7 day totals")
+	assert.InDelta(t, week, total("7d"), 1, "7 day totals must not come from the month cache")
+	assert.InDelta(t, mtd, total("mtd"), 1)
+	assert.Equal(t, "1", mr.HGet(fmt.Sprintf("billing:usage:org:%s:%s", orgID, time.Now().Format("2006-01")), "seeded"))
+}
+
+func TestAdminDashboardStatsTodayIsNotMonthToDate(t *testing.T) {
+	userID, orgID := uuid.New(), uuid.New()
+	db := newScriptedDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, `FROM "users"`):
+			return []string{"id", "email"}, [][]driver.Value{{userID.String(), "user@example.com"}}
+		case strings.Contains(query, `FROM "organizations"`):
+			return []string{"id", "name"}, [][]driver.Value{{orgID.String(), "acme"}}
+		case strings.Contains(query, "total_requests"):
+			start, end := args[0].Value.(time.Time), args[1].Value.(time.Time)
+			n := int64(end.Sub(start) / time.Minute)
+			return []string{"total_requests", "total_tokens", "total_cost", "avg_latency", "success_count", "error_count", "mcp_call_count", "mcp_error_count"},
+				[][]driver.Value{{n, n, float64(n), 100.0, n, int64(0), int64(0), int64(0)}}
+		}
+		return nil, nil
+	})
+	mr := miniredis.RunT(t)
+	billingSvc := billing.NewService(repository.NewUsageLogRepository(db), nil, redis.NewClient(&redis.Options{Addr: mr.Addr()}), zap.NewNop())
+	userSvc := user.NewService(repository.NewUserRepository(db), nil, nil, repository.NewOrganizationRepository(db), zap.NewNop())
+	h := NewAdminDashboardHandler(userSvc, billingSvc, nil, zap.NewNop())
+	r := gin.New()
+	r.GET("/stats", h.Stats)
+
+	// The month call seeds the cache right before today's summary is read.
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats?user_id="+userID.String(), nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Month billing.UsageSummary `json:"month"`
+			Today billing.UsageSummary `json:"today"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		now := time.Now()
+		todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		assert.InDelta(t, int64(now.Sub(todayStart)/time.Minute), resp.Today.TotalRequests, 1)
+		assert.InDelta(t, int64(now.Sub(monthStart)/time.Minute), resp.Month.TotalRequests, 1)
+	}
+}
@@ -0,0 +1,110 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the bucketed health history behind uptime charts.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router-platform/internal/service/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// healthTrendTargets are the target types that record health checks.
+var healthTrendTargets = map[string]bool{"provider": true, "api_key": true, "proxy": true}
+
+// healthTrendBuckets are the accepted bucket widths.
+var healthTrendBuckets = map[string]time.Duration{
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// Bounds on a health trend request.
+const (
+	maxHealthTrendRange   = 90 * 24 * time.Hour
+	maxHealthTrendBuckets = 1000
+)
+
+// HealthTrendHandler serves health check history bucketed over time.
+type HealthTrendHandler struct {
+	health *health.Service
+	logger *zap.Logger
+}
+
+// NewHealthTrendHandler creates a new health trend handler.
+func NewHealthTrendHandler(h *health.Service, logger *zap.Logger) *HealthTrendHandler {
+	return &HealthTrendHandler{health: h, logger: logger}
+}
+
+// Trend godoc
+// @Summary Get the health trend of a target
+// @Description Success rate and average latency of the health checks of a provider, API key or proxy, per time bucket, oldest first. Buckets without checks are omitted.
+// @Tags Health
+// @Produce json
+// @Security BearerAuth
+// @Param target_type path string true "provider, api_key or proxy"
+// @Param target_id path string true "Target ID"
+// @Param bucket query string false "Bucket width: 5m, 15m, 1h, 6h or 1d (default 1h)"
+// @Param range query string false "Look-back window, e.g. 24h or 7d (default 7d, max 90d)"
+// @Router /api/v1/health/{target_type}/{target_id}/trend [get]
+func (h *HealthTrendHandler) Trend(c *gin.Context) {
+	targetType := c.Param("target_type")
+	if !healthTrendTargets[targetType] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be provider, api_key or proxy"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("target_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target_id"})
+		return
+	}
+	bucket, window, err := healthTrendWindow(c.DefaultQuery("bucket", "1h"), c.DefaultQuery("range", "7d"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	since := time.Now().Add(-window)
+	points, err := h.health.HealthTrend(c.Request.Context(), targetType, id, bucket, since)
+	if err != nil {
+		h.logger.Error("failed to load health trend", zap.String("target_type", targetType), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load health trend"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"since": since, "bucket_seconds": int64(bucket / time.Second), "data": points})
+}
+
+// healthTrendWindow validates the bucket width and look-back window of a
+// trend request.
+func healthTrendWindow(rawBucket, rawRange string) (bucket, window time.Duration, err error) {
+	bucket, ok := healthTrendBuckets[rawBucket]
+	if !ok {
+		return 0, 0, errors.New("bucket must be 5m, 15m, 1h, 6h or 1d")
+	}
+	if days, found := strings.CutSuffix(rawRange, "d"); found {
+		n, convErr := strconv.Atoi(days)
+		if convErr != nil {
+			return 0, 0, errors.New("range must be a duration such as 24h or 7d")
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else if window, err = time.ParseDuration(rawRange); err != nil {
+		return 0, 0, errors.New("range must be a duration such as 24h or 7d")
+	}
+	if window < bucket || window > maxHealthTrendRange {
+		return 0, 0, errors.New("range must be at least one bucket and at most 90d")
+	}
+	if window/bucket > maxHealthTrendBuckets {
+		return 0, 0, fmt.Errorf("range spans more than %d buckets; use a wider bucket", maxHealthTrendBuckets)
+	}
+	return bucket, window, nil
+}
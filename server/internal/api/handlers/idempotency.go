@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	router_errs "llm-router-platform/internal/errors"
+	"llm-router-platform/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// idempotencyKeyHeader lets clients retry a chat request without it being
+	// sent to the provider or billed twice.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader is set to "true" on responses served from the
+	// idempotency cache.
+	idempotentReplayHeader = "X-LLM-Idempotent-Replay"
+	// idempotencyTTL is how long a key, and the response stored under it, is
+	// remembered. It also bounds how long an in-flight marker outlives a
+	// crashed request.
+	idempotencyTTL = 10 * time.Minute
+	// maxIdempotencyKeyLen bounds the header so keys stay cheap to store.
+	maxIdempotencyKeyLen = 255
+	// idempotencyKeyPrefix namespaces idempotency entries in Redis.
+	idempotencyKeyPrefix = "chat:idempotency:"
+)
+
+// ctxKeyIdempotency holds the *idempotentRequest claimed for a chat request.
+const ctxKeyIdempotency = "idempotency"
+
+// idempotencyRecord is what Redis holds under an idempotency key: a marker
+// while the first request is in flight, then its response.
+type idempotencyRecord struct {
+	Fingerprint string          `json:"fingerprint"`
+	Done        bool            `json:"done"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// idempotentRequest is a claimed idempotency key whose response has not been
+// stored yet.
+type idempotentRequest struct {
+	redis       *redis.Client
+	key         string
+	fingerprint string
+	stored      bool
+}
+
+// idempotencyRedisKey scopes the client's key to the calling API key so
+// tenants cannot read each other's responses.
+func idempotencyRedisKey(userAPIKey *models.APIKey, key string) string {
+	return idempotencyKeyPrefix + userAPIKey.ID.String() + ":" + key
+}
+
+// requestFingerprint identifies a request body so a key reused for a
+// different request is rejected instead of replaying the wrong response.
+func requestFingerprint(req ChatCompletionRequest) string {
+	b, _ := json.Marshal(req)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// beginIdempotent handles the Idempotency-Key header of a non-streaming chat
+// request. A key seen before with a stored response is replayed and done is
+// true; a key still in flight or reused with a different body is rejected. A
+// new key is claimed and returned for storeIdempotent and releaseIdempotent.
+// Without the header, without Redis, or on a Redis error it returns nil and the
+// request proceeds normally.
+func (h *ChatHandler) beginIdempotent(c *gin.Context, req ChatCompletionRequest) (idem *idempotentRequest, done bool) {
+	key := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+	if key == "" || req.Stream || h.redis == nil {
+		return nil, false
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		err := errors.New("idempotency key must be at most 255 characters")
+		c.JSON(http.StatusBadRequest, router_errs.NewRouterError(
+			router_errs.ErrCodeProviderParseFailed, http.StatusBadRequest, "invalid_request_error", err.Error(), err,
+		).MapToOpenAIResponse())
+		return nil, true
+	}
+	v, _ := c.Get("api_key")
+	userAPIKey, _ := v.(*models.APIKey)
+	if userAPIKey == nil {
+		return nil, false
+	}
+
+	ctx := c.Request.Context()
+	idem = &idempotentRequest{
+		redis:       h.redis,
+		key:         idempotencyRedisKey(userAPIKey, key),
+		fingerprint: requestFingerprint(req),
+	}
+	pending, _ := json.Marshal(idempotencyRecord{Fingerprint: idem.fingerprint})
+	claimed, err := h.redis.SetNX(ctx, idem.key, pending, idempotencyTTL).Result()
+	if err != nil {
+		h.logger.Warn("idempotency: redis write error, processing request", zap.Error(err))
+		return nil, false
+	}
+	if claimed {
+		return idem, false
+	}
+
+	raw, err := h.redis.Get(ctx, idem.key).Bytes()
+	if err != nil {
+		// The entry expired or was released between SetNX and Get.
+		h.logger.Warn("idempotency: redis read error, processing request", zap.Error(err))
+		return nil, false
+	}
+	var rec idempotencyRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		h.logger.Warn("idempotency: corrupt entry, processing request", zap.Error(err))
+		return nil, false
+	}
+	switch {
+	case rec.Fingerprint != idem.fingerprint:
+		c.JSON(http.StatusUnprocessableEntity, router_errs.NewRouterError(
+			router_errs.ErrCodeIdempotencyConflict, http.StatusUnprocessableEntity, "invalid_request_error",
+			"Idempotency-Key was already used with a different request body", nil,
+		).MapToOpenAIResponse())
+	case !rec.Done:
+		c.JSON(http.StatusConflict, router_errs.NewRouterError(
+			router_errs.ErrCodeIdempotencyConflict, http.StatusConflict, "invalid_request_error",
+			"a request with this Idempotency-Key is still in progress; retry later", nil,
+		).MapToOpenAIResponse())
+	default:
+		c.Header(idempotentReplayHeader, "true")
+		c.Data(http.StatusOK, "application/json; charset=utf-8", rec.Body)
+	}
+	return nil, true
+}
+
+// storeIdempotent saves a successful response under the idempotency key
+// claimed for this request, if any.
+func (h *ChatHandler) storeIdempotent(c *gin.Context, body interface{}) {
+	v, ok := c.Get(ctxKeyIdempotency)
+	if !ok {
+		return
+	}
+	idem := v.(*idempotentRequest)
+	b, err := json.Marshal(body)
+	if err != nil {
+		h.logger.Warn("idempotency: response not cacheable", zap.Error(err))
+		return
+	}
+	rec, _ := json.Marshal(idempotencyRecord{Fingerprint: idem.fingerprint, Done: true, Body: b})
+	// Detached from the request so a client disconnect does not drop the
+	// response a retry will ask for.
+	if err := idem.redis.Set(context.Background(), idem.key, rec, idempotencyTTL).Err(); err != nil {
+		h.logger.Warn("idempotency: redis write error", zap.Error(err))
+		return
+	}
+	idem.stored = true
+}
+
+// releaseIdempotent drops the in-flight marker of a request that did not
+// produce a cacheable response, so the client can retry it.
+func (h *ChatHandler) releaseIdempotent(idem *idempotentRequest) {
+	if idem.stored {
+		return
+	}
+	if err := idem.redis.Del(context.Background(), idem.key).Err(); err != nil {
+		h.logger.Warn("idempotency: redis delete error", zap.Error(err))
+	}
+}
@@ -120,6 +120,7 @@ func (h *ChatHandler) GenerateImage(c *gin.Context) {
 		ProjectID:   projectObj.ID,
 		APIKeyID:   userAPIKey.ID,
 		ProviderID: selectedProvider.ID,
+		ProviderKeyID: providerKeyID(result.UsedKey),
 		ModelName:  model,
 		Latency:    latency.Milliseconds(),
 		StatusCode: http.StatusOK,
@@ -0,0 +1,59 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the per-request limits on message count and length.
+package handlers
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetMessageLimits bounds the number of messages in one chat request and the
+// text length, in characters, of each message; 0 disables a limit. API keys
+// may override either limit with their MaxMessages and MaxMessageChars.
+func (h *ChatHandler) SetMessageLimits(maxMessages, maxMessageChars int) {
+	h.maxMessages = maxMessages
+	h.maxMessageChars = maxMessageChars
+}
+
+// checkMessageLimits rejects a request whose messages exceed the limits that
+// apply to the calling API key. Only the client's messages are counted, not
+// conversation history added from memory.
+func (h *ChatHandler) checkMessageLimits(c *gin.Context, contents []provider.FlexibleContent) error {
+	maxMessages, maxChars := h.maxMessages, h.maxMessageChars
+	if v, ok := c.Get("api_key"); ok {
+		if key, ok := v.(*models.APIKey); ok && key != nil {
+			maxMessages = messageLimitOverride(maxMessages, key.MaxMessages)
+			maxChars = messageLimitOverride(maxChars, key.MaxMessageChars)
+		}
+	}
+
+	if maxMessages > 0 && len(contents) > maxMessages {
+		return fmt.Errorf("messages has %d entries; at most %d are allowed per request", len(contents), maxMessages)
+	}
+	if maxChars > 0 {
+		for i, fc := range contents {
+			if n := utf8.RuneCountInString(fc.Text); n > maxChars {
+				return fmt.Errorf("messages[%d].content has %d characters; at most %d are allowed per message", i, n, maxChars)
+			}
+		}
+	}
+	return nil
+}
+
+// messageLimitOverride applies an API key's override to a server-wide limit:
+// 0 keeps the server limit, a negative value lifts it and a positive value
+// replaces it.
+func messageLimitOverride(limit, override int) int {
+	switch {
+	case override < 0:
+		return 0
+	case override > 0:
+		return override
+	}
+	return limit
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,28 +21,17 @@ import (
 
 // ModelHandler handles model listing endpoints.
 type ModelHandler struct {
-	router      *router.Router
-	registry    *provider.Registry
-	logger      *zap.Logger
-	modelCache  map[string]*modelCacheEntry
-	cacheMutex  sync.RWMutex
-	cacheExpiry time.Duration
-}
-
-// modelCacheEntry holds cached model data for a provider.
-type modelCacheEntry struct {
-	models    []provider.ModelInfo
-	fetchedAt time.Time
+	router   *router.Router
+	registry *provider.Registry
+	logger   *zap.Logger
 }
 
 // NewModelHandler creates a new model handler.
 func NewModelHandler(r *router.Router, registry *provider.Registry, logger *zap.Logger) *ModelHandler {
 	return &ModelHandler{
-		router:      r,
-		registry:    registry,
-		logger:      logger,
-		modelCache:  make(map[string]*modelCacheEntry),
-		cacheExpiry: 5 * time.Minute, // Cache models for 5 minutes
+		router:   r,
+		registry: registry,
+		logger:   logger,
 	}
 }
 
@@ -64,34 +54,6 @@ type fetchModelsResult struct {
 	err          error
 }
 
-// getCachedModels returns cached models for a provider if available and not expired.
-func (h *ModelHandler) getCachedModels(providerName string) ([]provider.ModelInfo, bool) {
-	h.cacheMutex.RLock()
-	defer h.cacheMutex.RUnlock()
-
-	entry, ok := h.modelCache[providerName]
-	if !ok {
-		return nil, false
-	}
-
-	if time.Since(entry.fetchedAt) > h.cacheExpiry {
-		return nil, false
-	}
-
-	return entry.models, true
-}
-
-// setCachedModels stores models in cache for a provider.
-func (h *ModelHandler) setCachedModels(providerName string, mdls []provider.ModelInfo) {
-	h.cacheMutex.Lock()
-	defer h.cacheMutex.Unlock()
-
-	h.modelCache[providerName] = &modelCacheEntry{
-		models:    mdls,
-		fetchedAt: time.Now(),
-	}
-}
-
 // fetchModelsForProvider fetches models for a single provider.
 func (h *ModelHandler) fetchModelsForProvider(ctx context.Context, p models.Provider) fetchModelsResult {
 	result := fetchModelsResult{
@@ -102,8 +64,8 @@ func (h *ModelHandler) fetchModelsForProvider(ctx context.Context, p models.Prov
 		models:       []provider.ModelInfo{},
 	}
 
-	// Check cache first
-	if cachedModels, ok := h.getCachedModels(p.Name); ok {
+	// Check cache first (memory, then Redis when shared)
+	if cachedModels, ok := h.router.CachedModelList(ctx, p.Name); ok {
 		result.models = cachedModels
 		return result
 	}
@@ -146,7 +108,7 @@ func (h *ModelHandler) fetchModelsForProvider(ctx context.Context, p models.Prov
 	}
 
 	// Cache the full model info (with extra upstream metadata)
-	h.setCachedModels(p.Name, fetchedModels)
+	h.router.CacheModelList(ctx, p.Name, fetchedModels)
 	result.models = fetchedModels
 	return result
 }
@@ -246,49 +208,66 @@ func (h *ModelHandler) List(c *gin.Context) {
 		close(resultChan)
 	}()
 
-	// Collect results in OpenAI format, preserving extra upstream fields
+	// Collect results in OpenAI format, preserving extra upstream fields.
+	// The same model ID may be served by several providers; strict SDKs expect
+	// unique IDs, so only the first occurrence is listed.
 	now := time.Now().Unix()
+	seen := make(map[string]struct{})
 	allModels := make([]map[string]interface{}, 0)
 	for r := range resultChan {
 		for _, mi := range r.models {
-			m := map[string]interface{}{
-				"id":       mi.ID,
-				"object":   "model",
-				"created":  mi.Created,
-				"owned_by": r.providerName,
-			}
-			if mi.Created == 0 {
-				m["created"] = now
+			if _, dup := seen[mi.ID]; dup {
+				continue
 			}
-			// Forward all extra upstream fields (type, capabilities,
-			// input_modalities, output_modalities, etc.)
-			for k, v := range mi.Extra {
-				// Don't overwrite our standard fields
-				if k == "id" || k == "object" || k == "owned_by" {
-					continue
-				}
-				var val json.RawMessage
-				if err := json.Unmarshal(v, &val); err == nil {
-					m[k] = val
-				}
-			}
-
-			// Infer capabilities from model name if upstream didn't
-			// provide them. This is essential for local providers like
-			// LM Studio that don't include capability metadata in
-			// their /v1/models responses.
-			inferModelCapabilities(mi.ID, m)
-
-			allModels = append(allModels, m)
+			seen[mi.ID] = struct{}{}
+			allModels = append(allModels, formatOpenAIModel(mi, r.providerName, now))
 		}
 	}
 
+	// Provider results arrive in completion order; sort for a stable response.
+	sort.Slice(allModels, func(i, j int) bool {
+		return allModels[i]["id"].(string) < allModels[j]["id"].(string)
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"object": "list",
 		"data":   allModels,
 	})
 }
 
+// formatOpenAIModel renders a model in the OpenAI model object schema
+// ({id, object, created, owned_by}), with owned_by set to the serving provider.
+// Extra upstream fields (e.g., type, capabilities, input_modalities) are
+// forwarded so clients can detect vision/multimodal support, but they never
+// override the standard fields.
+func formatOpenAIModel(mi provider.ModelInfo, ownedBy string, now int64) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":       mi.ID,
+		"object":   "model",
+		"created":  mi.Created,
+		"owned_by": ownedBy,
+	}
+	if mi.Created == 0 {
+		m["created"] = now
+	}
+	for k, v := range mi.Extra {
+		if _, standard := m[k]; standard {
+			continue
+		}
+		var val json.RawMessage
+		if err := json.Unmarshal(v, &val); err == nil {
+			m[k] = val
+		}
+	}
+
+	// Infer capabilities from model name if upstream didn't
+	// provide them. This is essential for local providers like
+	// LM Studio that don't include capability metadata in
+	// their /v1/models responses.
+	inferModelCapabilities(mi.ID, m)
+	return m
+}
+
 // Retrieve returns details for a specific model by ID.
 // Implements the standard OpenAI API: GET /v1/models/{model_id}
 // Route pattern: /models/:org/*name handles IDs like "qwen/qwen3-vl-8b"
@@ -353,7 +332,7 @@ func (h *ModelHandler) Retrieve(c *gin.Context) {
 
 func (h *ModelHandler) findAndFormatModel(ctx context.Context, modelID string, activeProviders []models.Provider) (map[string]interface{}, bool) {
 	for _, p := range activeProviders {
-		models, ok := h.getCachedModels(p.Name)
+		models, ok := h.router.CachedModelList(ctx, p.Name)
 		if !ok {
 			result := h.fetchModelsForProvider(ctx, p)
 			models = result.models
@@ -361,27 +340,7 @@ func (h *ModelHandler) findAndFormatModel(ctx context.Context, modelID string, a
 
 		for _, mi := range models {
 			if mi.ID == modelID {
-				now := time.Now().Unix()
-				m := map[string]interface{}{
-					"id":       mi.ID,
-					"object":   "model",
-					"created":  mi.Created,
-					"owned_by": p.Name,
-				}
-				if mi.Created == 0 {
-					m["created"] = now
-				}
-				for k, v := range mi.Extra {
-					if k == "id" || k == "object" || k == "owned_by" {
-						continue
-					}
-					var val json.RawMessage
-					if err := json.Unmarshal(v, &val); err == nil {
-						m[k] = val
-					}
-				}
-				inferModelCapabilities(mi.ID, m)
-				return m, true
+				return formatOpenAIModel(mi, p.Name, time.Now().Unix()), true
 			}
 		}
 	}
@@ -0,0 +1,77 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin endpoints for model tier aliases.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"llm-router-platform/internal/service/audit"
+	"llm-router-platform/internal/service/router"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ModelTierHandler lets admins map tier aliases such as "fast" and "smart"
+// to concrete models, so clients are insulated from model churn. Every change
+// is audited.
+type ModelTierHandler struct {
+	router       *router.Router
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewModelTierHandler creates a new model tier handler.
+func NewModelTierHandler(r *router.Router, auditService *audit.Service, logger *zap.Logger) *ModelTierHandler {
+	return &ModelTierHandler{router: r, auditService: auditService, logger: logger}
+}
+
+// ModelTiersRequest is the body of the model tier update endpoint.
+type ModelTiersRequest struct {
+	Tiers map[string]string `json:"tiers"` // tier alias → model; empty restores the server defaults
+}
+
+// Get godoc
+// @Summary Get model tier aliases
+// @Description Returns the tier alias to model mapping in effect and whether an admin configured it (custom) or it comes from MODEL_TIER_ALIASES.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/model-tiers [get]
+func (h *ModelTierHandler) Get(c *gin.Context) {
+	tiers, custom := h.router.ModelTiers(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{"tiers": tiers, "custom": custom})
+}
+
+// Update godoc
+// @Summary Replace model tier aliases
+// @Description Replaces the whole mapping on every instance within 30 seconds. An empty mapping restores the server defaults.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/model-tiers [put]
+func (h *ModelTierHandler) Update(c *gin.Context) {
+	var req ModelTiersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tiers must map tier names to models"})
+		return
+	}
+	if err := h.router.SaveModelTiers(c.Request.Context(), req.Tiers); err != nil {
+		if errors.Is(err, router.ErrInvalidModelTiers) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to save model tiers", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save model tiers"})
+		return
+	}
+
+	if h.auditService != nil {
+		actorID, _ := uuid.Parse(c.GetString("user_id"))
+		h.auditService.Log(c.Request.Context(), audit.ActionModelTiersUpdate, actorID, uuid.Nil, c.ClientIP(), c.Request.UserAgent(), map[string]interface{}{"tiers": req.Tiers})
+	}
+	h.Get(c)
+}
@@ -0,0 +1,309 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin endpoints for creating providers, viewing one
+// provider in detail, bulk-importing its keys, draining a provider for
+// maintenance and testing a provider configuration before saving it, and the
+// capabilities lookup used by front-ends.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/health"
+	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/internal/service/router"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// providerTestTimeout bounds the health check and model listing together.
+const providerTestTimeout = 30 * time.Second
+
+// providerDetailHistory is how many recent health checks the detail view shows.
+const providerDetailHistory = 20
+
+// ProviderHandler exposes provider maintenance operations to admins.
+type ProviderHandler struct {
+	router *router.Router
+	health *health.Service
+	logger *zap.Logger
+}
+
+// NewProviderHandler creates a new provider handler.
+func NewProviderHandler(r *router.Router, healthSvc *health.Service, logger *zap.Logger) *ProviderHandler {
+	return &ProviderHandler{router: r, health: healthSvc, logger: logger}
+}
+
+// ProviderDetail is the body of GET /api/v1/providers/:id.
+type ProviderDetail struct {
+	*models.Provider
+	Keys          []ProviderKeyStatus     `json:"keys"`
+	HealthHistory []models.HealthHistory  `json:"health_history"`
+	Routing       *router.ProviderRouting `json:"routing"`
+}
+
+// ProviderTestRequest is the body of POST /api/v1/providers/test.
+type ProviderTestRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Type    string `json:"type"` // required unless name is a provider type
+	BaseURL string `json:"base_url" binding:"required,url"`
+	APIKey  string `json:"api_key"` // used for this request only, never persisted
+}
+
+// ProviderCreateRequest is the body of POST /api/v1/providers. Unset optional
+// fields take the same defaults as the GraphQL createProvider mutation.
+type ProviderCreateRequest struct {
+	Name           string   `json:"name" binding:"required"`
+	Type           string   `json:"type"` // required unless name is a provider type, e.g. openai-compatible
+	BaseURL        string   `json:"base_url" binding:"required,url"`
+	IsActive       *bool    `json:"is_active"`
+	Priority       *int     `json:"priority"`
+	Weight         *float64 `json:"weight"`
+	RequiresAPIKey *bool    `json:"requires_api_key"`
+}
+
+// Create godoc
+// @Summary Create a provider
+// @Description Creates an inactive-by-default provider. type selects the client (openai, anthropic, google, ollama, lmstudio, deepseek, mistral, vllm or openai-compatible) and defaults to the name when the name is one of them. Returns 400 for an unknown type and 409 when the name is already taken.
+// @Tags Providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/providers [post]
+func (h *ProviderHandler) Create(c *gin.Context) {
+	var req ProviderCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and a valid base_url are required"})
+		return
+	}
+	if err := h.router.ValidateProviderBaseURL(req.BaseURL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	p := router.NewProvider(req.Name, req.Type, req.BaseURL)
+	if req.IsActive != nil {
+		p.IsActive = *req.IsActive
+	}
+	if req.Priority != nil {
+		p.Priority = *req.Priority
+	}
+	if req.Weight != nil {
+		p.Weight = *req.Weight
+	}
+	if req.RequiresAPIKey != nil {
+		p.RequiresAPIKey = *req.RequiresAPIKey
+	}
+
+	if err := h.router.CreateProvider(c.Request.Context(), p); err != nil {
+		if errors.Is(err, router.ErrProviderNameExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, provider.ErrUnknownProviderType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to create provider", zap.String("name", p.Name), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create provider"})
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// Get godoc
+// @Summary Get a provider with its keys and health
+// @Description The provider's configuration, all of its keys (inactive ones included) with usage, health and cooldown (never the secret), its latest health checks and the router's current view of it, including whether any key is routable.
+// @Tags Providers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Provider ID"
+// @Router /api/v1/providers/{id} [get]
+func (h *ProviderHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider id"})
+		return
+	}
+	ctx := c.Request.Context()
+	p, err := h.router.GetProviderByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, router.ErrProviderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to load provider", zap.String("provider_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load provider"})
+		return
+	}
+
+	keys, err := h.router.GetAllProviderAPIKeys(ctx, id)
+	if err != nil {
+		h.providerDetailError(c, id, err)
+		return
+	}
+	statuses := providerKeyStatuses(ctx, h.router, h.health, keys)
+	for i := range statuses {
+		statuses[i].ProviderName = p.Name
+	}
+	history, err := h.health.ProviderHealthHistory(ctx, id, providerDetailHistory)
+	if err != nil {
+		h.providerDetailError(c, id, err)
+		return
+	}
+	routing, err := h.router.ProviderRouting(ctx, p)
+	if err != nil {
+		h.providerDetailError(c, id, err)
+		return
+	}
+	c.JSON(http.StatusOK, ProviderDetail{Provider: p, Keys: statuses, HealthHistory: history, Routing: routing})
+}
+
+func (h *ProviderHandler) providerDetailError(c *gin.Context, id uuid.UUID, err error) {
+	h.logger.Error("failed to load provider detail", zap.String("provider_id", id.String()), zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load provider"})
+}
+
+// Drain godoc
+// @Summary Drain a provider for maintenance
+// @Description Stops routing new requests to the provider while requests already in flight finish. The provider stays active and health-checked, so its health shows when it is safe to bring back with DELETE.
+// @Tags Providers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Provider ID"
+// @Router /api/v1/providers/{id}/drain [post]
+func (h *ProviderHandler) Drain(c *gin.Context) {
+	h.setDraining(c, true)
+}
+
+// Resume godoc
+// @Summary End a provider drain
+// @Description Returns a drained provider to request routing.
+// @Tags Providers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Provider ID"
+// @Router /api/v1/providers/{id}/drain [delete]
+func (h *ProviderHandler) Resume(c *gin.Context) {
+	h.setDraining(c, false)
+}
+
+func (h *ProviderHandler) setDraining(c *gin.Context, draining bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider id"})
+		return
+	}
+	p, err := h.router.SetProviderDraining(c.Request.Context(), id, draining)
+	if err != nil {
+		if errors.Is(err, router.ErrProviderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to update provider drain state", zap.String("provider_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update provider"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": p.ID, "name": p.Name, "is_active": p.IsActive, "draining": p.Draining})
+}
+
+// BatchCreateKeys godoc
+// @Summary Bulk-import provider API keys
+// @Description Encrypts and stores an array of {api_key, alias, weight} for the provider. Keys it already has, or repeated in the batch, are skipped as duplicates. Returns one result per item; the secret is never echoed back.
+// @Tags Providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Provider ID"
+// @Router /api/v1/providers/{id}/keys/batch [post]
+func (h *ProviderHandler) BatchCreateKeys(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider id"})
+		return
+	}
+	var items []router.ProviderKeyImport
+	if err := c.ShouldBindJSON(&items); err != nil || len(items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be a non-empty array of {api_key, alias, weight}"})
+		return
+	}
+	if len(items) > router.MaxProviderKeyImport {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d keys can be imported at once", router.MaxProviderKeyImport)})
+		return
+	}
+
+	results, err := h.router.ImportProviderAPIKeys(c.Request.Context(), id, items)
+	if err != nil {
+		if errors.Is(err, router.ErrProviderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to import provider keys", zap.String("provider_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import provider keys"})
+		return
+	}
+	summary := map[string]int{router.KeyImportCreated: 0, router.KeyImportDuplicate: 0, router.KeyImportFailed: 0}
+	for _, res := range results {
+		summary[res.Status]++
+	}
+	c.JSON(http.StatusOK, gin.H{"summary": summary, "data": results})
+}
+
+// TestConfig godoc
+// @Summary Test a provider configuration before saving it
+// @Description Builds a temporary client from name, type, base_url and api_key, runs a health check and lists models. Nothing is persisted.
+// @Tags Providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/providers/test [post]
+func (h *ProviderHandler) TestConfig(c *gin.Context) {
+	var req ProviderTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and a valid base_url are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), providerTestTimeout)
+	defer cancel()
+
+	result, err := h.router.TestProviderConfig(ctx, req.Name, req.Type, req.BaseURL, req.APIKey)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Capabilities godoc
+// @Summary Get provider capabilities
+// @Description Reports whether the provider supports streaming, vision, tools and embeddings, and its largest context window, derived from its models' capability flags and its provider type. Capabilities nothing declares are false and listed in unverified.
+// @Tags Providers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Provider ID"
+// @Router /api/v1/providers/{id}/capabilities [get]
+func (h *ProviderHandler) Capabilities(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider id"})
+		return
+	}
+	caps, err := h.router.ProviderCapabilities(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, router.ErrProviderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to load provider capabilities", zap.String("provider_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load provider capabilities"})
+		return
+	}
+	c.JSON(http.StatusOK, caps)
+}
@@ -0,0 +1,259 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin view of the provider key fleet.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/audit"
+	"llm-router-platform/internal/service/health"
+	"llm-router-platform/internal/service/router"
+	"llm-router-platform/pkg/sanitize"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Key health states reported by the provider key view.
+const (
+	keyHealthHealthy   = "healthy"
+	keyHealthUnhealthy = "unhealthy"
+	keyHealthUnknown   = "unknown" // never health-checked
+)
+
+// ProviderKeyHandler gives operators one view of every provider key: its
+// metadata, usage, latest health check and router cooldown. Secrets are
+// never loaded into the response.
+type ProviderKeyHandler struct {
+	router       *router.Router
+	health       *health.Service
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewProviderKeyHandler creates a new provider key handler.
+func NewProviderKeyHandler(r *router.Router, healthSvc *health.Service, auditService *audit.Service, logger *zap.Logger) *ProviderKeyHandler {
+	return &ProviderKeyHandler{router: r, health: healthSvc, auditService: auditService, logger: logger}
+}
+
+// ProviderKeyStatus is one provider key in the fleet view.
+type ProviderKeyStatus struct {
+	ID           uuid.UUID  `json:"id"`
+	ProviderID   uuid.UUID  `json:"provider_id"`
+	ProviderName string     `json:"provider_name"`
+	Alias        string     `json:"alias"`
+	KeyPrefix    string     `json:"key_prefix"`
+	IsActive     bool       `json:"is_active"`
+	Priority     int        `json:"priority"`
+	Weight       float64    `json:"weight"`
+	UsageCount   int64      `json:"usage_count"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	// Health is healthy, unhealthy or unknown (never checked).
+	Health         string     `json:"health"`
+	LastCheck      *time.Time `json:"last_check"`
+	ResponseTimeMs int64      `json:"response_time_ms"`
+	SuccessRate    float64    `json:"success_rate"`
+	// InCooldown is set while the router skips the key after a quota or
+	// rate-limit failure.
+	InCooldown     bool   `json:"in_cooldown"`
+	CooldownReason string `json:"cooldown_reason,omitempty"`
+}
+
+// List godoc
+// @Summary List provider keys with health and cooldown
+// @Description Every provider key with its alias, prefix, usage, latest health check and whether the router currently skips it. Never includes the secret.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param provider query string false "Provider name or ID"
+// @Param health query string false "healthy, unhealthy or unknown"
+// @Router /api/v1/admin/provider-keys [get]
+func (h *ProviderKeyHandler) List(c *gin.Context) {
+	healthFilter := c.Query("health")
+	switch healthFilter {
+	case "", keyHealthHealthy, keyHealthUnhealthy, keyHealthUnknown:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "health must be healthy, unhealthy or unknown"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	keys, err := h.router.ListProviderAPIKeys(ctx)
+	if err != nil {
+		h.logger.Error("failed to list provider keys", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list provider keys"})
+		return
+	}
+	if p := c.Query("provider"); p != "" {
+		keys = filterKeysByProvider(keys, p)
+	}
+
+	out := make([]ProviderKeyStatus, 0, len(keys))
+	for _, st := range providerKeyStatuses(ctx, h.router, h.health, keys) {
+		if healthFilter != "" && st.Health != healthFilter {
+			continue
+		}
+		out = append(out, st)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out, "total": len(out)})
+}
+
+// providerKeyStatuses reports each key with its health and router cooldown.
+func providerKeyStatuses(ctx context.Context, r *router.Router, healthSvc *health.Service, keys []models.ProviderAPIKey) []ProviderKeyStatus {
+	ids := make([]uuid.UUID, len(keys))
+	for i, k := range keys {
+		ids[i] = k.ID
+	}
+	cooldowns := r.KeyCooldowns(ctx, ids)
+	statuses := healthSvc.APIKeysHealth(ctx, keys)
+
+	out := make([]ProviderKeyStatus, len(keys))
+	for i, k := range keys {
+		out[i] = providerKeyStatus(k, statuses[i])
+		if reason, ok := cooldowns[k.ID]; ok {
+			out[i].InCooldown = true
+			out[i].CooldownReason = sanitize.TruncateErrorMessage(reason)
+		}
+	}
+	return out
+}
+
+// filterKeysByProvider keeps the keys of the provider named or identified by p.
+func filterKeysByProvider(keys []models.ProviderAPIKey, p string) []models.ProviderAPIKey {
+	id, idErr := uuid.Parse(p)
+	out := keys[:0]
+	for _, k := range keys {
+		if (idErr == nil && k.ProviderID == id) || strings.EqualFold(k.Provider.Name, p) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// providerKeyStatus combines a key with its health summary.
+func providerKeyStatus(k models.ProviderAPIKey, hs health.APIKeyHealthStatus) ProviderKeyStatus {
+	st := ProviderKeyStatus{
+		ID:             k.ID,
+		ProviderID:     k.ProviderID,
+		ProviderName:   k.Provider.Name,
+		Alias:          k.Alias,
+		KeyPrefix:      k.KeyPrefix,
+		IsActive:       k.IsActive,
+		Priority:       k.Priority,
+		Weight:         k.Weight,
+		UsageCount:     k.UsageCount,
+		Health:         keyHealthUnknown,
+		ResponseTimeMs: hs.ResponseTime,
+		SuccessRate:    hs.SuccessRate,
+	}
+	if !k.LastUsedAt.IsZero() {
+		lastUsed := k.LastUsedAt
+		st.LastUsedAt = &lastUsed
+	}
+	if !hs.LastCheck.IsZero() {
+		lastCheck := hs.LastCheck
+		st.LastCheck = &lastCheck
+		st.Health = keyHealthUnhealthy
+		if hs.IsHealthy {
+			st.Health = keyHealthHealthy
+		}
+	}
+	return st
+}
+
+// KeyCooldownStatus is a provider key the router currently skips.
+type KeyCooldownStatus struct {
+	ID               uuid.UUID `json:"id"`
+	ProviderID       uuid.UUID `json:"provider_id"`
+	ProviderName     string    `json:"provider_name"`
+	Alias            string    `json:"alias"`
+	KeyPrefix        string    `json:"key_prefix"`
+	Reason           string    `json:"reason"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+	Until            time.Time `json:"until"`
+}
+
+// Cooldowns godoc
+// @Summary List provider keys in cooldown
+// @Description Provider keys the router skips after a quota or rate-limit failure, with the failure reason and the time left, longest first.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/provider-keys/cooldowns [get]
+func (h *ProviderKeyHandler) Cooldowns(c *gin.Context) {
+	ctx := c.Request.Context()
+	keys, err := h.router.ListProviderAPIKeys(ctx)
+	if err != nil {
+		h.logger.Error("failed to list provider keys", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list provider keys"})
+		return
+	}
+	ids := make([]uuid.UUID, len(keys))
+	for i, k := range keys {
+		ids[i] = k.ID
+	}
+	c.JSON(http.StatusOK, gin.H{"data": keyCooldownStatuses(keys, h.router.KeyCooldownDetails(ctx, ids), time.Now())})
+}
+
+// keyCooldownStatuses reports the keys in cooldown, longest remaining first.
+func keyCooldownStatuses(keys []models.ProviderAPIKey, cooldowns map[uuid.UUID]router.KeyCooldown, now time.Time) []KeyCooldownStatus {
+	out := make([]KeyCooldownStatus, 0, len(cooldowns))
+	for _, k := range keys {
+		cd, ok := cooldowns[k.ID]
+		if !ok {
+			continue
+		}
+		out = append(out, KeyCooldownStatus{
+			ID:               k.ID,
+			ProviderID:       k.ProviderID,
+			ProviderName:     k.Provider.Name,
+			Alias:            k.Alias,
+			KeyPrefix:        k.KeyPrefix,
+			Reason:           sanitize.TruncateErrorMessage(cd.Reason),
+			RemainingSeconds: int64(cd.Remaining.Round(time.Second) / time.Second),
+			Until:            now.Add(cd.Remaining).UTC(),
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Until.After(out[j].Until) })
+	return out
+}
+
+// ClearCooldown godoc
+// @Summary Clear a provider key's cooldown
+// @Description Makes a provider key that failed with a quota or rate-limit error eligible for selection again immediately, e.g. after its plan was upgraded.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Provider key ID"
+// @Router /api/v1/admin/provider-keys/{id}/clear-cooldown [post]
+func (h *ProviderKeyHandler) ClearCooldown(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider key ID"})
+		return
+	}
+	wasInCooldown, err := h.router.ClearKeyCooldown(c.Request.Context(), id)
+	if errors.Is(err, router.ErrProviderKeyNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to clear provider key cooldown", zap.String("key_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to clear provider key cooldown"})
+		return
+	}
+
+	if h.auditService != nil {
+		actorID, _ := uuid.Parse(c.GetString("user_id"))
+		h.auditService.Log(c.Request.Context(), audit.ActionKeyCooldownClear, actorID, id, c.ClientIP(), c.Request.UserAgent(),
+			map[string]interface{}{"was_in_cooldown": wasInCooldown})
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id, "was_in_cooldown": wasInCooldown})
+}
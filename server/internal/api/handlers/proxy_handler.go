@@ -0,0 +1,128 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin endpoints for proxy pool statistics.
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"llm-router-platform/internal/service/audit"
+	"llm-router-platform/internal/service/proxy"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ProxyHandler exposes proxy pool views and maintenance to admins.
+type ProxyHandler struct {
+	proxy        *proxy.Service
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewProxyHandler creates a new proxy handler.
+func NewProxyHandler(p *proxy.Service, auditService *audit.Service, logger *zap.Logger) *ProxyHandler {
+	return &ProxyHandler{proxy: p, auditService: auditService, logger: logger}
+}
+
+// ProxyResetStatsRequest is the optional body of POST /api/v1/proxies/reset-stats.
+type ProxyResetStatsRequest struct {
+	IDs []uuid.UUID `json:"ids"` // empty = every proxy in the pool
+}
+
+// Stats godoc
+// @Summary Proxy pool statistics
+// @Description Pool-wide totals (total, active, healthy), aggregate success rate and average latency, broken down by region and type. Computed from the stored per-proxy counters; no health checks are run.
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/proxies/stats [get]
+func (h *ProxyHandler) Stats(c *gin.Context) {
+	stats, err := h.proxy.Stats(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to compute proxy pool stats", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load proxies"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// ResetStats godoc
+// @Summary Reset one proxy's statistics
+// @Description Zeroes the success/failure counters and average latency of a proxy so it is re-baselined, e.g. after it has been fixed.
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/proxies/{id}/reset-stats [post]
+func (h *ProxyHandler) ResetStats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid proxy id"})
+		return
+	}
+	n, ok := h.resetStats(c, []uuid.UUID{id})
+	if !ok {
+		return
+	}
+	if n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "proxy not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reset": n})
+}
+
+// ResetAllStats godoc
+// @Summary Reset proxy statistics in bulk
+// @Description Zeroes the success/failure counters and average latency of the proxies listed in ids, or of every proxy when the body or ids is empty.
+// @Tags Proxies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/proxies/reset-stats [post]
+func (h *ProxyHandler) ResetAllStats(c *gin.Context) {
+	var req ProxyResetStatsRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a list of proxy ids"})
+			return
+		}
+	}
+	n, ok := h.resetStats(c, req.IDs)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reset": n})
+}
+
+// resetStats resets the counters of ids (all proxies when empty) and records
+// who did it. It writes the error response itself and reports false on failure.
+func (h *ProxyHandler) resetStats(c *gin.Context, ids []uuid.UUID) (int64, bool) {
+	n, err := h.proxy.ResetStats(c.Request.Context(), ids)
+	if err != nil {
+		h.logger.Error("failed to reset proxy stats", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset proxy stats"})
+		return 0, false
+	}
+
+	actorID, _ := uuid.Parse(c.GetString("user_id"))
+	h.logger.Info("proxy stats reset",
+		zap.String("actor_id", actorID.String()),
+		zap.Int("requested", len(ids)),
+		zap.Int64("reset", n),
+		zap.Time("at", time.Now()),
+	)
+	if n > 0 && h.auditService != nil {
+		targetID := uuid.Nil
+		if len(ids) == 1 {
+			targetID = ids[0]
+		}
+		details := map[string]interface{}{"reset": n, "scope": "all"}
+		if len(ids) > 0 {
+			details["scope"] = "selected"
+			details["proxy_ids"] = ids
+		}
+		h.auditService.Log(c.Request.Context(), audit.ActionProxyStatsReset, actorID, targetID, c.ClientIP(), c.Request.UserAgent(), details)
+	}
+	return n, true
+}
@@ -0,0 +1,133 @@
+// Package handlers provides HTTP request handlers.
+// This file contains live request-load counters and the realtime stats endpoint.
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"llm-router-platform/internal/service/router"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RealtimeStats tracks live chat load in memory. Counters are per-instance
+// and reset on restart; nothing here touches the database.
+type RealtimeStats struct {
+	inFlight      atomic.Int64
+	activeStreams atomic.Int64
+	perProvider   sync.Map // provider name → *atomic.Int64
+
+	mu      sync.Mutex
+	buckets [60]secondBucket // ring of per-second request counts for the last minute
+}
+
+// secondBucket counts requests started during one wall-clock second.
+type secondBucket struct {
+	second int64
+	count  int64
+}
+
+// NewRealtimeStats creates an empty set of live counters.
+func NewRealtimeStats() *RealtimeStats {
+	return &RealtimeStats{}
+}
+
+// begin records the start of a request to providerName and returns the
+// function that records its end.
+func (s *RealtimeStats) begin(providerName string, stream bool) func() {
+	s.inFlight.Add(1)
+	if stream {
+		s.activeStreams.Add(1)
+	}
+	counter, _ := s.perProvider.LoadOrStore(providerName, new(atomic.Int64))
+	counter.(*atomic.Int64).Add(1)
+	s.recordRequest(time.Now())
+
+	return func() {
+		s.inFlight.Add(-1)
+		if stream {
+			s.activeStreams.Add(-1)
+		}
+		counter.(*atomic.Int64).Add(-1)
+	}
+}
+
+func (s *RealtimeStats) recordRequest(now time.Time) {
+	sec := now.Unix()
+	s.mu.Lock()
+	b := &s.buckets[sec%int64(len(s.buckets))]
+	if b.second != sec {
+		b.second, b.count = sec, 0
+	}
+	b.count++
+	s.mu.Unlock()
+}
+
+func (s *RealtimeStats) requestsSince(now time.Time) int64 {
+	cutoff := now.Unix() - int64(len(s.buckets))
+	var total int64
+	s.mu.Lock()
+	for _, b := range s.buckets {
+		if b.second > cutoff {
+			total += b.count
+		}
+	}
+	s.mu.Unlock()
+	return total
+}
+
+// RealtimeSnapshot is a point-in-time view of RealtimeStats.
+type RealtimeSnapshot struct {
+	InFlight           int64            `json:"in_flight"`
+	ActiveStreams      int64            `json:"active_streams"`
+	RequestsLastMinute int64            `json:"requests_last_minute"`
+	InFlightByProvider map[string]int64 `json:"in_flight_by_provider"`
+}
+
+// Snapshot reads the current counter values. Providers with nothing in
+// flight are omitted.
+func (s *RealtimeStats) Snapshot() RealtimeSnapshot {
+	snap := RealtimeSnapshot{
+		InFlight:           s.inFlight.Load(),
+		ActiveStreams:      s.activeStreams.Load(),
+		RequestsLastMinute: s.requestsSince(time.Now()),
+		InFlightByProvider: make(map[string]int64),
+	}
+	s.perProvider.Range(func(k, v any) bool {
+		if n := v.(*atomic.Int64).Load(); n > 0 {
+			snap.InFlightByProvider[k.(string)] = n
+		}
+		return true
+	})
+	return snap
+}
+
+// StatsHandler serves live operational statistics for administrators.
+type StatsHandler struct {
+	stats  *RealtimeStats
+	router *router.Router
+}
+
+// NewStatsHandler creates a new stats handler.
+func NewStatsHandler(stats *RealtimeStats, r *router.Router) *StatsHandler {
+	return &StatsHandler{stats: stats, router: r}
+}
+
+// Realtime returns in-flight request counts, provider queues and
+// circuit-breaker states.
+// GET /api/v1/admin/stats/realtime
+func (h *StatsHandler) Realtime(c *gin.Context) {
+	circuits := h.router.CircuitStates()
+	sort.Slice(circuits, func(i, j int) bool { return circuits[i].ProviderName < circuits[j].ProviderName })
+
+	c.JSON(http.StatusOK, gin.H{
+		"timestamp":        time.Now().UTC(),
+		"requests":         h.stats.Snapshot(),
+		"provider_queues":  h.router.ProviderQueueStats(),
+		"circuit_breakers": circuits,
+	})
+}
@@ -0,0 +1,173 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the admin endpoints for per-model routing overrides.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/audit"
+	"llm-router-platform/internal/service/router"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RouteOverrideHandler lets admins pin a model to a provider and, optionally,
+// one of its keys, e.g. for dedicated capacity. Every change is audited.
+type RouteOverrideHandler struct {
+	router       *router.Router
+	auditService *audit.Service
+	logger       *zap.Logger
+}
+
+// NewRouteOverrideHandler creates a new route override handler.
+func NewRouteOverrideHandler(r *router.Router, auditService *audit.Service, logger *zap.Logger) *RouteOverrideHandler {
+	return &RouteOverrideHandler{router: r, auditService: auditService, logger: logger}
+}
+
+// RouteOverrideRequest is the body of the route override create and update
+// endpoints. On update, omitted optional fields keep their current values.
+type RouteOverrideRequest struct {
+	ModelName     string     `json:"model_name" binding:"required"`
+	ProviderID    uuid.UUID  `json:"provider_id" binding:"required"`
+	APIKeyID      *uuid.UUID `json:"api_key_id"` // null = any key of the provider
+	Description   *string    `json:"description"`
+	IsEnabled     *bool      `json:"is_enabled"`
+	AllowFallback *bool      `json:"allow_fallback"`
+}
+
+// apply copies the request onto o.
+func (req *RouteOverrideRequest) apply(o *models.ModelRouteOverride) {
+	o.ModelName = req.ModelName
+	o.ProviderID = req.ProviderID
+	o.APIKeyID = req.APIKeyID
+	if req.Description != nil {
+		o.Description = *req.Description
+	}
+	if req.IsEnabled != nil {
+		o.IsEnabled = *req.IsEnabled
+	}
+	if req.AllowFallback != nil {
+		o.AllowFallback = *req.AllowFallback
+	}
+}
+
+// List godoc
+// @Summary List model route overrides
+// @Description Returns every per-model override, enabled or not.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/route-overrides [get]
+func (h *RouteOverrideHandler) List(c *gin.Context) {
+	overrides, err := h.router.ListRouteOverrides(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list route overrides", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load route overrides"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": overrides, "total": len(overrides)})
+}
+
+// Create godoc
+// @Summary Create a model route override
+// @Description Pins model_name to provider_id and, when api_key_id is set, to that key. Requests fail fast while the target is unavailable unless allow_fallback is true.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/admin/route-overrides [post]
+func (h *RouteOverrideHandler) Create(c *gin.Context) {
+	var req RouteOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "model_name and provider_id are required"})
+		return
+	}
+	o := &models.ModelRouteOverride{IsEnabled: true}
+	req.apply(o)
+	if err := h.router.CreateRouteOverride(c.Request.Context(), o); err != nil {
+		h.overrideError(c, err)
+		return
+	}
+	h.audit(c, o.ID, map[string]interface{}{"op": "create", "model": o.ModelName, "provider_id": o.ProviderID, "api_key_id": o.APIKeyID})
+	c.JSON(http.StatusCreated, o)
+}
+
+// Update godoc
+// @Summary Update a model route override
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Override ID"
+// @Router /api/v1/admin/route-overrides/{id} [put]
+func (h *RouteOverrideHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid override id"})
+		return
+	}
+	var req RouteOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "model_name and provider_id are required"})
+		return
+	}
+	o, err := h.router.GetRouteOverride(c.Request.Context(), id)
+	if err != nil {
+		h.overrideError(c, err)
+		return
+	}
+	req.apply(o)
+	if err := h.router.UpdateRouteOverride(c.Request.Context(), o); err != nil {
+		h.overrideError(c, err)
+		return
+	}
+	h.audit(c, o.ID, map[string]interface{}{"op": "update", "model": o.ModelName, "provider_id": o.ProviderID, "api_key_id": o.APIKeyID})
+	c.JSON(http.StatusOK, o)
+}
+
+// Delete godoc
+// @Summary Delete a model route override
+// @Description The model routes normally again.
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "Override ID"
+// @Router /api/v1/admin/route-overrides/{id} [delete]
+func (h *RouteOverrideHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid override id"})
+		return
+	}
+	if err := h.router.DeleteRouteOverride(c.Request.Context(), id); err != nil {
+		h.overrideError(c, err)
+		return
+	}
+	h.audit(c, id, map[string]interface{}{"op": "delete"})
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RouteOverrideHandler) overrideError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, router.ErrInvalidRouteOverride):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, router.ErrRouteOverrideNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, router.ErrRouteOverrideExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("route override update failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save route override"})
+	}
+}
+
+func (h *RouteOverrideHandler) audit(c *gin.Context, overrideID uuid.UUID, details map[string]interface{}) {
+	if h.auditService == nil {
+		return
+	}
+	actorID, _ := uuid.Parse(c.GetString("user_id"))
+	h.auditService.Log(c.Request.Context(), audit.ActionRouteOverride, actorID, overrideID, c.ClientIP(), c.Request.UserAgent(), details)
+}
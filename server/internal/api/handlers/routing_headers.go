@@ -0,0 +1,53 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the optional response headers reporting how a chat
+// request was routed.
+package handlers
+
+import (
+	"strconv"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/internal/service/router"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response headers reporting how a chat request was served. The provider
+// header shares its name with the request header that pins a provider.
+const (
+	servedProviderHeader  = "X-LLM-Provider"
+	servedModelHeader     = "X-LLM-Model"
+	requestAttemptsHeader = "X-LLM-Request-Attempts"
+)
+
+// SetRoutingHeaders controls whether chat responses report the provider that
+// served them, the upstream model and the number of upstream attempts. They
+// reveal routing topology, so they are meant for trusted clients only.
+func (h *ChatHandler) SetRoutingHeaders(enabled bool) {
+	h.routingHeaders = enabled
+}
+
+// setRoutingHeaders adds the routing headers when enabled. It must run
+// before the response status is written, i.e. before the first stream chunk.
+func (h *ChatHandler) setRoutingHeaders(c *gin.Context, p *models.Provider, model string) {
+	if !h.routingHeaders {
+		return
+	}
+	c.Header(servedProviderHeader, p.Name)
+	if model != "" {
+		c.Header(servedModelHeader, model)
+	}
+	if attempts := router.AttemptsFrom(c.Request.Context()); attempts != nil {
+		c.Header(requestAttemptsHeader, strconv.Itoa(attempts.Count()))
+	}
+}
+
+// upstreamModel returns the model the provider reports having served, or
+// the model sent upstream when the response does not name one.
+func upstreamModel(resp *provider.ChatResponse, sent string) string {
+	if resp != nil && resp.Model != "" {
+		return resp.Model
+	}
+	return sent
+}
@@ -0,0 +1,120 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the opt-in mid-stream budget cutoff for streamed chat.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	router_errs "llm-router-platform/internal/errors"
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/billing"
+	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/pkg/sanitize"
+	"llm-router-platform/pkg/tokencount"
+
+	"go.uber.org/zap"
+)
+
+// SetBudgets lets a stream cut off by an API key's StreamBudgetCutoff also
+// stop at its organization's remaining monthly budget. Without it only the
+// key's MaxRequestCostUSD is enforced mid-stream.
+func (h *ChatHandler) SetBudgets(budgets *billing.BudgetService) {
+	h.budgets = budgets
+}
+
+// errStreamBudgetExceeded marks a stream that was cut off because its
+// estimated cost reached the caller's budget; the usage log records the
+// tokens produced up to then.
+var errStreamBudgetExceeded = errors.New("stream budget exceeded")
+
+// streamBudget tracks the estimated cost of a stream as chunks arrive. The
+// completion is estimated per delta because providers only report usage once
+// the stream ends.
+type streamBudget struct {
+	model            string
+	limitUSD         float64
+	promptCostUSD    float64
+	outputPer1K      float64
+	completionTokens int
+}
+
+// newStreamBudget returns the budget a stream is held to, or nil when the key
+// has not opted in, no limit applies or the model's output is free. The limit
+// is the lower of the key's MaxRequestCostUSD and the organization's remaining
+// monthly budget. Lookup failures are logged and leave the stream unchecked.
+func (h *ChatHandler) newStreamBudget(ctx context.Context, req *provider.ChatRequest, userAPIKey *models.APIKey, projectObj *models.Project) *streamBudget {
+	if h.billing == nil || userAPIKey == nil || !userAPIKey.StreamBudgetCutoff {
+		return nil
+	}
+
+	limit := math.Inf(1)
+	if userAPIKey.MaxRequestCostUSD > 0 {
+		limit = userAPIKey.MaxRequestCostUSD
+	}
+	if h.budgets != nil && projectObj != nil {
+		status, err := h.budgets.CheckBudget(ctx, projectObj.OrgID)
+		if err != nil {
+			h.logger.Warn("budget check failed, stream budget uses the key limit only", zap.Error(err))
+		} else if status != nil {
+			limit = math.Min(limit, status.RemainingUSD)
+		}
+	}
+	if math.IsInf(limit, 1) {
+		return nil
+	}
+
+	inputPer1K, outputPer1K, err := h.billing.TokenPrices(ctx, req.Model)
+	if err != nil {
+		h.logger.Warn("model price lookup failed, stream budget not enforced", zap.Error(err), zap.String("model", sanitize.LogValue(req.Model)))
+		return nil
+	}
+	if outputPer1K <= 0 {
+		return nil
+	}
+
+	promptTokens := 0
+	for _, m := range req.Messages {
+		promptTokens += tokencount.CountTokens(m.Content.Text, req.Model)
+	}
+	return &streamBudget{
+		model:         req.Model,
+		limitUSD:      limit,
+		promptCostUSD: float64(promptTokens) / 1000 * inputPer1K,
+		outputPer1K:   outputPer1K,
+	}
+}
+
+// add counts the chunk's generated text and reports whether the stream's
+// estimated cost now exceeds its limit. A nil budget never does.
+func (b *streamBudget) add(chunk provider.StreamChunk) bool {
+	if b == nil {
+		return false
+	}
+	for _, choice := range chunk.Choices {
+		if choice.Delta.Content != "" {
+			b.completionTokens += tokencount.CountTokens(choice.Delta.Content, b.model)
+		}
+	}
+	return b.costUSD() > b.limitUSD
+}
+
+func (b *streamBudget) costUSD() float64 {
+	return b.promptCostUSD + float64(b.completionTokens)/1000*b.outputPer1K
+}
+
+// exceededError is sent as the last event of a stream cut off by its budget.
+func (b *streamBudget) exceededError() map[string]interface{} {
+	msg := fmt.Sprintf("stream stopped: estimated cost $%.6f exceeds the remaining budget of $%.6f for this request",
+		b.costUSD(), b.limitUSD)
+	resp := router_errs.NewRouterError(
+		router_errs.ErrCodeBudgetExceeded, http.StatusPaymentRequired, "budget_exceeded", msg, errStreamBudgetExceeded,
+	).MapToOpenAIResponse()
+	body := resp["error"].(map[string]interface{})
+	body["estimated_cost_usd"] = b.costUSD()
+	body["budget_limit_usd"] = b.limitUSD
+	return resp
+}
@@ -5,13 +5,20 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
+	"llm-router-platform/internal/api/middleware"
+	router_errs "llm-router-platform/internal/errors"
 	"llm-router-platform/internal/models"
 	"llm-router-platform/internal/service/observability"
 	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/internal/service/shadow"
 	"llm-router-platform/pkg/sanitize"
 	"llm-router-platform/pkg/tokencount"
 
@@ -20,9 +27,25 @@ import (
 	"go.uber.org/zap"
 )
 
+// setStreamWriteDeadline replaces the server-wide WriteTimeout, which is
+// measured from the start of the request, with the stream write timeout so a
+// long generation is not cut off mid-response.
+func (h *ChatHandler) setStreamWriteDeadline(c *gin.Context) {
+	var deadline time.Time
+	if h.streamWriteTimeout > 0 {
+		deadline = time.Now().Add(h.streamWriteTimeout)
+	}
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err != nil {
+		h.logger.Debug("could not set stream write deadline", zap.Error(err))
+	}
+}
+
 // handleStreamingChat handles streaming chat completion requests.
 // It receives a pre-established stream channel (connection already opened with retry by Router).
-func (h *ChatHandler) handleStreamingChat(c *gin.Context, chunks <-chan provider.StreamChunk, req *provider.ChatRequest, selectedProvider *models.Provider, projectObj *models.Project, userAPIKey *models.APIKey, start time.Time, trace observability.Trace, conversationID string, originalMessages []MessageRequest, logID uuid.UUID, promptHash string, promptEmbedding []float32) {
+// cancelUpstream aborts that stream; it is called when the client can no longer
+// be written to or the key's stream budget is exceeded, and the usage log is
+// then finalized as partial.
+func (h *ChatHandler) handleStreamingChat(c *gin.Context, chunks <-chan provider.StreamChunk, cancelUpstream context.CancelFunc, req *provider.ChatRequest, selectedProvider *models.Provider, projectObj *models.Project, userAPIKey *models.APIKey, start time.Time, trace observability.Trace, conversationID string, originalMessages []MessageRequest, logID uuid.UUID, promptHash string, promptEmbedding []float32) {
 	gen := h.obsInfo.StartGeneration(c.Request.Context(), trace, "Provider: "+selectedProvider.Name, req.Model, map[string]interface{}{
 		"temperature": req.Temperature,
 		"max_tokens":  req.MaxTokens,
@@ -30,19 +53,28 @@ func (h *ChatHandler) handleStreamingChat(c *gin.Context, chunks <-chan provider
 	}, req.Messages)
 
 	// Set headers for SSE
+	h.setStreamWriteDeadline(c)
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
 	c.Header("Transfer-Encoding", "chunked")
 
-	var fullText string
-	var promptTokens, completionTokens int
+	choices := streamChoices{}
+	var usage provider.Usage
 	var streamErr error
+	budget := h.newStreamBudget(c.Request.Context(), req, userAPIKey, projectObj)
+	sse := sseWriter{w: c.Writer, flush: http.NewResponseController(c.Writer).Flush}
 
 	c.Stream(func(w io.Writer) bool {
 		select {
 		case <-c.Request.Context().Done():
 			streamErr = c.Request.Context().Err()
+			if errors.Is(streamErr, context.DeadlineExceeded) {
+				// Headers are already sent; tell the client why the stream stops.
+				if data, err := json.Marshal(deadlineExceededError(streamErr).MapToOpenAIResponse()); err == nil {
+					_ = sse.event(data)
+				}
+			}
 			return false
 		case chunk, ok := <-chunks:
 			if !ok {
@@ -52,21 +84,23 @@ func (h *ChatHandler) handleStreamingChat(c *gin.Context, chunks <-chan provider
 			if chunk.Error != nil {
 				streamErr = chunk.Error
 				gen.EndWithError(chunk.Error)
+				// End with an error event rather than a bare close, which a
+				// client cannot tell apart from a complete response.
+				if data, err := json.Marshal(streamInterruptedError(chunk.Error).MapToOpenAIResponse()); err == nil {
+					_ = sse.event(data)
+				}
 				return false
 			}
 
 			if chunk.Done {
-				_, _ = w.Write([]byte("data: [DONE]\n\n"))
+				_ = sse.event([]byte("[DONE]"))
 				return false
 			}
 
-			if len(chunk.Choices) > 0 {
-				fullText += chunk.Choices[0].Delta.Content
-			}
+			choices.add(chunk)
 
 			if chunk.Usage != nil {
-				promptTokens = chunk.Usage.PromptTokens
-				completionTokens = chunk.Usage.CompletionTokens
+				usage = *chunk.Usage
 			}
 
 			data, err := json.Marshal(chunk)
@@ -74,22 +108,248 @@ func (h *ChatHandler) handleStreamingChat(c *gin.Context, chunks <-chan provider
 				return false
 			}
 
-			_, _ = w.Write([]byte("data: "))
-			_, _ = w.Write(data)
-			_, _ = w.Write([]byte("\n\n"))
+			if err := sse.event(data); err != nil {
+				streamErr = fmt.Errorf("%w: %v", errStreamClientWrite, err)
+				h.logger.Info("stream write failed, cancelling upstream",
+					zap.String("provider", selectedProvider.Name),
+					zap.Error(err))
+				return false
+			}
+
+			if budget.add(chunk) {
+				streamErr = errStreamBudgetExceeded
+				h.logger.Info("stream budget exceeded, cancelling upstream",
+					zap.String("provider", selectedProvider.Name),
+					zap.String("api_key_id", userAPIKey.ID.String()))
+				if data, err := json.Marshal(budget.exceededError()); err == nil {
+					_ = sse.event(data)
+				}
+				return false
+			}
 			return true
 		}
 	})
 
-	h.finalizeStream(c.Request.Context(), req, selectedProvider, projectObj, userAPIKey, start, conversationID, originalMessages, logID, promptHash, promptEmbedding, fullText, promptTokens, completionTokens, streamErr, gen)
+	if streamErr != nil {
+		// Stop the upstream generation and drain what it already produced so
+		// the provider goroutine can exit instead of blocking on a send.
+		cancelUpstream()
+		go drainStream(chunks)
+	}
+
+	texts := choices.texts()
+	promptTokens, completionTokens := h.finalizeStream(c.Request.Context(), req, selectedProvider, projectObj, userAPIKey, start, conversationID, originalMessages, logID, promptHash, promptEmbedding, texts, usage, streamErr, gen)
+	middleware.SetRequestLogFields(c, middleware.RequestLogFields{
+		Provider: selectedProvider.Name, Model: req.Model, Stream: true,
+		PromptTokens: promptTokens, CompletionTokens: completionTokens,
+	})
+
+	if streamErr == nil && len(texts) > 0 {
+		v, _ := c.Get(ctxKeyShadowRequest)
+		shadowReq, _ := v.(*provider.ChatRequest)
+		h.shadow.Mirror(shadowReq, shadow.Primary{
+			UsageLogID: logID,
+			ProviderID: selectedProvider.ID,
+			Model:      req.Model,
+			Streamed:   true,
+			Latency:    time.Since(start),
+			Response:   texts[0],
+		})
+	}
 }
 
-func (h *ChatHandler) finalizeStream(ctx context.Context, req *provider.ChatRequest, selectedProvider *models.Provider, projectObj *models.Project, userAPIKey *models.APIKey, start time.Time, conversationID string, originalMessages []MessageRequest, logID uuid.UUID, promptHash string, promptEmbedding []float32, fullText string, promptTokens int, completionTokens int, streamErr error, gen observability.Generation) {
-	if promptTokens == 0 && completionTokens == 0 && fullText != "" {
-		completionTokens = tokencount.CountTokens(fullText, req.Model)
-		for _, m := range req.Messages {
-			promptTokens += tokencount.CountTokens(m.Content.Text, req.Model)
+// errStreamClientWrite marks a stream that ended because the client could no
+// longer be written to; the usage log records the tokens produced up to then.
+var errStreamClientWrite = errors.New("client write failed")
+
+// sseWriter writes one SSE event per call and flushes it, so a client that
+// went away surfaces as an error on the event that could not be delivered
+// rather than being buffered and silently dropped.
+type sseWriter struct {
+	w     io.Writer
+	flush func() error
+}
+
+func (s sseWriter) event(data []byte) error {
+	buf := make([]byte, 0, len(data)+8)
+	buf = append(buf, "data: "...)
+	buf = append(buf, data...)
+	buf = append(buf, "\n\n"...)
+	if _, err := s.w.Write(buf); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// drainStream discards the remaining chunks until the producer closes the
+// channel.
+func drainStream(chunks <-chan provider.StreamChunk) {
+	for range chunks {
+	}
+}
+
+// streamInterruptedError is sent to a client whose stream failed after the
+// headers were written, e.g. when the upstream connection was reset.
+func streamInterruptedError(err error) *router_errs.RouterError {
+	return router_errs.NewRouterError(
+		router_errs.ErrCodeProviderParseFailed, http.StatusBadGateway, "server_error",
+		"upstream stream ended before the response was complete", err,
+	)
+}
+
+// streamChoices accumulates streamed deltas per choice index, so with n > 1
+// each choice's text is assembled separately, in the order its deltas arrive.
+type streamChoices map[int]*strings.Builder
+
+func (s streamChoices) add(chunk provider.StreamChunk) {
+	for _, choice := range chunk.Choices {
+		b, ok := s[choice.Index]
+		if !ok {
+			b = &strings.Builder{}
+			s[choice.Index] = b
 		}
+		b.WriteString(choice.Delta.Content)
+	}
+}
+
+// texts returns each choice's full text ordered by choice index.
+func (s streamChoices) texts() []string {
+	indices := make([]int, 0, len(s))
+	for i := range s {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	out := make([]string, len(indices))
+	for i, idx := range indices {
+		out[i] = s[idx].String()
+	}
+	return out
+}
+
+// estimateStreamTokens counts tokens locally when the upstream sent no usage.
+// Completion tokens cover every choice, since each one is billed.
+func estimateStreamTokens(req *provider.ChatRequest, texts []string) (promptTokens, completionTokens int) {
+	for _, text := range texts {
+		completionTokens += tokencount.CountTokens(text, req.Model)
+	}
+	for _, m := range req.Messages {
+		promptTokens += tokencount.CountTokens(m.Content.Text, req.Model)
+	}
+	return promptTokens, completionTokens
+}
+
+// handleStreamDowngrade retries a failed stream setup as a non-streaming Chat
+// call and replays the result as a single SSE chunk followed by [DONE].
+// It returns false when the fallback also fails, leaving the caller to report
+// the original stream error.
+func (h *ChatHandler) handleStreamDowngrade(c *gin.Context, req ChatCompletionRequest, providerReq *provider.ChatRequest, selectedProvider *models.Provider, userAPIKey *models.APIKey, projectObj *models.Project, start time.Time, trace observability.Trace, logID uuid.UUID, promptHash string, promptEmbedding []float32, streamErr error) bool {
+	h.logger.Warn("stream failed to initialize, downgrading to non-streaming chat",
+		zap.String("provider", selectedProvider.Name),
+		zap.String("model", sanitize.LogValue(req.Model)),
+		zap.Error(streamErr))
+
+	chatReq := *providerReq
+	chatReq.Stream = false
+	chatReq.StreamOptions = nil
+
+	gen := h.obsInfo.StartGeneration(c.Request.Context(), trace, "Provider: "+selectedProvider.Name, req.Model, map[string]interface{}{
+		"temperature":       req.Temperature,
+		"max_tokens":        req.MaxTokens,
+		"stream":            true,
+		"stream_downgraded": true,
+	}, chatReq.Messages)
+
+	result, err := h.router.ExecuteChatInSlot(c.Request.Context(), selectedProvider, nil, &chatReq, 3)
+	if isClientCanceled(c, err) {
+		gen.EndWithError(err)
+		h.finishCanceledStream(c, logID, start)
+		return true
+	}
+	if deadlineExceeded(c, err) {
+		gen.EndWithError(err)
+		h.finishDeadlineExceededStream(c, logID, start)
+		return true
+	}
+	if err != nil {
+		gen.EndWithError(err)
+		h.logger.Warn("non-streaming fallback failed", zap.Error(err))
+		return false
+	}
+
+	h.attributeProviderKey(c.Request.Context(), logID, result.UsedKey)
+
+	data, err := json.Marshal(downgradedStreamChunk(result.Response))
+	if err != nil {
+		gen.EndWithError(err)
+		return false
+	}
+
+	h.setStreamWriteDeadline(c)
+	h.setRoutingHeaders(c, selectedProvider, upstreamModel(result.Response, chatReq.Model))
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	_, _ = c.Writer.Write([]byte("data: "))
+	_, _ = c.Writer.Write(data)
+	_, _ = c.Writer.Write([]byte("\n\ndata: [DONE]\n\n"))
+	c.Writer.Flush()
+
+	texts := make([]string, len(result.Response.Choices))
+	for i, choice := range result.Response.Choices {
+		texts[i] = choice.Message.Content.Text
+	}
+	usage := result.Response.Usage
+	h.finalizeStream(c.Request.Context(), providerReq, selectedProvider, projectObj, userAPIKey, start, req.ConversationID, req.Messages, logID, promptHash, promptEmbedding, texts, usage, nil, gen)
+	middleware.SetRequestLogFields(c, middleware.RequestLogFields{
+		Provider: selectedProvider.Name, Model: req.Model, Stream: true,
+		PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens,
+	})
+
+	// finalizeStream saves the whole row, so the flag must be written afterwards.
+	if err := h.usageRepo.MarkStreamDowngraded(context.Background(), logID); err != nil {
+		h.logger.Warn("failed to mark usage log as stream downgraded", zap.Error(err))
+	}
+	return true
+}
+
+// downgradedStreamChunk converts a complete chat response into one stream
+// chunk carrying the full content of every choice.
+func downgradedStreamChunk(resp *provider.ChatResponse) provider.StreamChunk {
+	usage := resp.Usage
+	chunk := provider.StreamChunk{
+		ID:      resp.ID,
+		Model:   resp.Model,
+		Choices: make([]provider.DeltaChoice, 0, len(resp.Choices)),
+		Usage:   &usage,
+	}
+	for _, choice := range resp.Choices {
+		chunk.Choices = append(chunk.Choices, provider.DeltaChoice{
+			Index: choice.Index,
+			Delta: provider.Delta{
+				Role:      choice.Message.Role,
+				Content:   choice.Message.Content.Text,
+				ToolCalls: choice.Message.ToolCalls,
+			},
+			FinishReason: choice.FinishReason,
+		})
+	}
+	return chunk
+}
+
+// finalizeStream records usage for a finished stream, plus memory and cache
+// when it completed, and returns the prompt and completion tokens it
+// recorded, estimated when the provider sent none. Prompt-cache tokens are
+// billed at their own rates.
+func (h *ChatHandler) finalizeStream(ctx context.Context, req *provider.ChatRequest, selectedProvider *models.Provider, projectObj *models.Project, userAPIKey *models.APIKey, start time.Time, conversationID string, originalMessages []MessageRequest, logID uuid.UUID, promptHash string, promptEmbedding []float32, texts []string, usage provider.Usage, streamErr error, gen observability.Generation) (int, int) {
+	promptTokens, completionTokens := usage.PromptTokens, usage.CompletionTokens
+	// Choice 0 is the reply kept in conversation memory and traces.
+	var fullText string
+	if len(texts) > 0 {
+		fullText = texts[0]
+	}
+	if promptTokens == 0 && completionTokens == 0 && strings.Join(texts, "") != "" {
+		promptTokens, completionTokens = estimateStreamTokens(req, texts)
 	}
 	gen.End(fullText, promptTokens, completionTokens)
 
@@ -100,10 +360,17 @@ func (h *ChatHandler) finalizeStream(ctx context.Context, req *provider.ChatRequ
 		errStr = sanitize.TruncateErrorMessage(streamErr.Error())
 	}
 
-	if err := h.billing.UpdateUsageTokens(context.Background(), logID, promptTokens, completionTokens, statusCode, time.Since(start).Milliseconds(), errStr); err != nil {
+	if err := h.billing.UpdateUsageTokens(context.Background(), logID, promptTokens, completionTokens, usage.CacheCreationTokens, usage.CacheReadTokens, statusCode, time.Since(start).Milliseconds(), errStr); err != nil {
 		h.logger.Warn("billing update failed after stream", zap.Error(err))
 	}
 
+	// A stream cut short by an upstream error, a failed client write, the
+	// cost budget or the deadline holds a partial reply: it is billed, but
+	// neither remembered nor cached as if it were complete.
+	if streamErr != nil {
+		return promptTokens, completionTokens
+	}
+
 	if conversationID != "" && h.memory != nil {
 		for _, m := range originalMessages {
 			_ = h.memory.AddMessage(ctx, projectObj.ID, &userAPIKey.ID, conversationID, m.Role, m.Content.Text, 0)
@@ -113,25 +380,29 @@ func (h *ChatHandler) finalizeStream(ctx context.Context, req *provider.ChatRequ
 
 	if h.cache != nil && promptHash != "" && fullText != "" {
 		// Cache store runs after HTTP response is sent — intentionally detached from request context.
-		go h.storeInCache(promptHash, promptEmbedding, fullText, selectedProvider.Name, req.Model, promptTokens, completionTokens) // #nosec G118 -- fire-and-forget cache write after response
+		go h.storeInCache(promptHash, promptEmbedding, texts, selectedProvider.Name, req.Model, promptTokens, completionTokens) // #nosec G118 -- fire-and-forget cache write after response
 	}
+	return promptTokens, completionTokens
 }
 
-func (h *ChatHandler) storeInCache(hash string, emb []float32, text string, pid string, m string, promptTokens int, completionTokens int) {
+func (h *ChatHandler) storeInCache(hash string, emb []float32, texts []string, pid string, m string, promptTokens int, completionTokens int) {
 	if len(emb) == 0 {
 		emb = make([]float32, 1536)
 	}
-	cachedResp := provider.ChatResponse{
-		ID:    uuid.New().String(),
-		Model: m,
-		Choices: []provider.Choice{
-			{
-				Message: provider.Message{
-					Role:    "assistant",
-					Content: provider.FlexibleContent{Text: text},
-				},
+	choices := make([]provider.Choice, len(texts))
+	for i, text := range texts {
+		choices[i] = provider.Choice{
+			Index: i,
+			Message: provider.Message{
+				Role:    "assistant",
+				Content: provider.FlexibleContent{Text: text},
 			},
-		},
+		}
+	}
+	cachedResp := provider.ChatResponse{
+		ID:      uuid.New().String(),
+		Model:   m,
+		Choices: choices,
 		Usage: provider.Usage{
 			PromptTokens:     promptTokens,
 			CompletionTokens: completionTokens,
@@ -0,0 +1,294 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the aggregate system health behind the status badge.
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/health"
+	"llm-router-platform/internal/service/router"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Aggregate system health states.
+const (
+	systemHealthy  = "healthy"
+	systemDegraded = "degraded"
+	systemDown     = "down" // no provider is routable
+)
+
+// Dependency states reported by the system health endpoint.
+const (
+	dependencyOK       = "ok"
+	dependencyError    = "error"
+	dependencyDisabled = "disabled" // not configured
+)
+
+// SystemHealthHandler rolls providers, provider keys, proxies, the database
+// and Redis up into one status for dashboards and external monitoring.
+type SystemHealthHandler struct {
+	router      *router.Router
+	health      *health.Service
+	db          *gorm.DB
+	redisClient *redis.Client
+	logger      *zap.Logger
+}
+
+// NewSystemHealthHandler creates a new system health handler.
+func NewSystemHealthHandler(r *router.Router, healthSvc *health.Service, db *gorm.DB, redisClient *redis.Client, logger *zap.Logger) *SystemHealthHandler {
+	return &SystemHealthHandler{router: r, health: healthSvc, db: db, redisClient: redisClient, logger: logger}
+}
+
+// SystemHealth is the aggregate status and the factors behind it.
+type SystemHealth struct {
+	// Status is healthy, degraded (some providers, or Redis, are down) or
+	// down (no provider is routable, or the database is unreachable).
+	Status    string                `json:"status"`
+	Factors   []string              `json:"factors"`
+	Providers ProviderHealthSummary `json:"providers"`
+	Keys      KeyHealthSummary      `json:"keys"`
+	Proxies   ProxyHealthSummary    `json:"proxies"`
+	Database  string                `json:"database"`
+	Redis     string                `json:"redis"`
+	CheckedAt time.Time             `json:"checked_at"`
+}
+
+// ProviderHealthSummary counts the active providers and lists the ones that
+// are not routable.
+type ProviderHealthSummary struct {
+	Total       int                   `json:"total"`
+	Healthy     int                   `json:"healthy"`
+	Unavailable []UnavailableProvider `json:"unavailable"`
+}
+
+// UnavailableProvider is an active provider the router cannot send requests to.
+type UnavailableProvider struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Reason string    `json:"reason"`
+}
+
+// KeyHealthSummary counts the provider keys; healthy keys are active, not
+// failing health checks and not in cooldown.
+type KeyHealthSummary struct {
+	Total      int `json:"total"`
+	Active     int `json:"active"`
+	Healthy    int `json:"healthy"`
+	InCooldown int `json:"in_cooldown"`
+}
+
+// ProxyHealthSummary counts the active proxies.
+type ProxyHealthSummary struct {
+	Total   int `json:"total"`
+	Healthy int `json:"healthy"`
+}
+
+// System godoc
+// @Summary Get aggregate system health
+// @Description healthy, degraded when some providers are not routable or Redis is unreachable, down when no provider is routable or the database is unreachable; with the counts and factors behind it. Responds 503 when down.
+// @Tags Health
+// @Produce json
+// @Security BearerAuth
+// @Router /api/v1/health/system [get]
+func (h *SystemHealthHandler) System(c *gin.Context) {
+	ctx := c.Request.Context()
+	in := systemHealthInput{
+		database: h.pingDatabase(ctx),
+		redis:    h.pingRedis(ctx),
+	}
+
+	if in.database == dependencyOK {
+		if err := h.load(ctx, &in); err != nil {
+			h.logger.Error("failed to load system health", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load system health"})
+			return
+		}
+	}
+
+	out := summarizeSystemHealth(in, time.Now().UTC())
+	code := http.StatusOK
+	if out.Status == systemDown {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, out)
+}
+
+// load fills in the providers, keys and proxies.
+func (h *SystemHealthHandler) load(ctx context.Context, in *systemHealthInput) error {
+	providers, err := h.router.GetAllProviders(ctx)
+	if err != nil {
+		return err
+	}
+	statuses, err := h.health.GetProvidersHealth(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := h.router.ListProviderAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	proxies, err := h.health.GetProxiesHealth(ctx)
+	if err != nil {
+		return err
+	}
+
+	in.providerHealth = make(map[uuid.UUID]health.ProviderHealthStatus, len(statuses))
+	for _, st := range statuses {
+		in.providerHealth[st.ID] = st
+	}
+	in.circuitOpen = make(map[uuid.UUID]bool)
+	for _, p := range providers {
+		if p.IsActive && !h.router.IsProviderHealthy(p.ID) {
+			in.circuitOpen[p.ID] = true
+		}
+	}
+	in.providers = providers
+	in.keys = providerKeyStatuses(ctx, h.router, h.health, keys)
+	in.proxies = proxies
+	return nil
+}
+
+func (h *SystemHealthHandler) pingDatabase(ctx context.Context) string {
+	if h.db == nil {
+		return dependencyDisabled
+	}
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return dependencyError
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return dependencyError
+	}
+	return dependencyOK
+}
+
+func (h *SystemHealthHandler) pingRedis(ctx context.Context) string {
+	if h.redisClient == nil {
+		return dependencyDisabled
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := h.redisClient.Ping(ctx).Err(); err != nil {
+		return dependencyError
+	}
+	return dependencyOK
+}
+
+// systemHealthInput is everything the aggregate status is derived from.
+type systemHealthInput struct {
+	providers      []models.Provider
+	providerHealth map[uuid.UUID]health.ProviderHealthStatus // latest health check by provider
+	circuitOpen    map[uuid.UUID]bool
+	keys           []ProviderKeyStatus
+	proxies        []health.ProxyHealthStatus
+	database       string
+	redis          string
+}
+
+// summarizeSystemHealth derives the aggregate status. A provider is routable
+// when it is active, not draining, passing health checks, its circuit is
+// closed, if it requires a key it has a healthy one and, if it sends through a
+// proxy, some active proxy is healthy to carry it.
+func summarizeSystemHealth(in systemHealthInput, now time.Time) SystemHealth {
+	out := SystemHealth{
+		Factors:   []string{},
+		Database:  in.database,
+		Redis:     in.redis,
+		CheckedAt: now,
+	}
+	out.Providers.Unavailable = []UnavailableProvider{}
+
+	usableKeys := make(map[uuid.UUID]int)
+	for _, k := range in.keys {
+		out.Keys.Total++
+		if !k.IsActive {
+			continue
+		}
+		out.Keys.Active++
+		if k.InCooldown {
+			out.Keys.InCooldown++
+			continue
+		}
+		if k.Health != keyHealthUnhealthy {
+			out.Keys.Healthy++
+			usableKeys[k.ProviderID]++
+		}
+	}
+
+	for _, px := range in.proxies {
+		if !px.IsActive {
+			continue
+		}
+		out.Proxies.Total++
+		if px.IsHealthy {
+			out.Proxies.Healthy++
+		}
+	}
+
+	for _, p := range in.providers {
+		if !p.IsActive {
+			continue
+		}
+		out.Providers.Total++
+		reason := ""
+		switch {
+		case p.Draining:
+			reason = "draining"
+		case in.circuitOpen[p.ID]:
+			reason = "circuit open"
+		case p.RequiresAPIKey && usableKeys[p.ID] == 0:
+			reason = "no healthy API key"
+		case p.UseProxy && out.Proxies.Healthy == 0:
+			// Neither the default proxy nor any fallback can carry it.
+			reason = "no healthy proxy"
+		default:
+			if st, ok := in.providerHealth[p.ID]; ok && !st.IsHealthy {
+				reason = "failing health checks"
+			}
+		}
+		if reason == "" {
+			out.Providers.Healthy++
+			continue
+		}
+		out.Providers.Unavailable = append(out.Providers.Unavailable, UnavailableProvider{ID: p.ID, Name: p.Name, Reason: reason})
+	}
+
+	out.Status = systemHealthy
+	switch {
+	case in.database == dependencyError:
+		out.Status = systemDown
+		out.Factors = append(out.Factors, "database unreachable")
+	case out.Providers.Healthy == 0:
+		out.Status = systemDown
+		out.Factors = append(out.Factors, "no provider is routable")
+	case out.Providers.Healthy < out.Providers.Total:
+		out.Status = systemDegraded
+		out.Factors = append(out.Factors, fmt.Sprintf("%d of %d providers are not routable",
+			out.Providers.Total-out.Providers.Healthy, out.Providers.Total))
+	}
+	if in.redis == dependencyError {
+		if out.Status == systemHealthy {
+			out.Status = systemDegraded
+		}
+		out.Factors = append(out.Factors, "redis unreachable")
+	}
+	if out.Keys.InCooldown > 0 {
+		out.Factors = append(out.Factors, fmt.Sprintf("%d provider keys in cooldown", out.Keys.InCooldown))
+	}
+	if out.Proxies.Healthy < out.Proxies.Total {
+		out.Factors = append(out.Factors, fmt.Sprintf("%d of %d proxies unhealthy",
+			out.Proxies.Total-out.Proxies.Healthy, out.Proxies.Total))
+	}
+	return out
+}
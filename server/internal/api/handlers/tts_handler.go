@@ -124,6 +124,7 @@ func (h *ChatHandler) SynthesizeSpeech(c *gin.Context) {
 		ProjectID:   projectObj.ID,
 		APIKeyID:   userAPIKey.ID,
 		ProviderID: selectedProvider.ID,
+		ProviderKeyID: providerKeyID(result.UsedKey),
 		ModelName:  req.Model,
 		Latency:    latency.Milliseconds(),
 		StatusCode: http.StatusOK,
@@ -0,0 +1,224 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the signed-in user's usage endpoints.
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"llm-router-platform/internal/service/billing"
+	"llm-router-platform/internal/service/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// UsageHandler serves the caller's own usage over a chosen date range, scoped
+// the same way as the dashboard's GraphQL usage queries.
+type UsageHandler struct {
+	userSvc *user.Service
+	billing *billing.Service
+	logger  *zap.Logger
+}
+
+// NewUsageHandler creates a new usage handler.
+func NewUsageHandler(userSvc *user.Service, billingSvc *billing.Service, logger *zap.Logger) *UsageHandler {
+	return &UsageHandler{userSvc: userSvc, billing: billingSvc, logger: logger}
+}
+
+// Summary godoc
+// @Summary Get my usage summary
+// @Description Total requests, tokens, cost and success rate for the caller's organization (or org_id). Defaults to month to date.
+// @Tags Usage
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Shorthand window ending now: 7d, 30d, mtd, ... (instead of start/end)"
+// @Param start query string false "Start (RFC 3339 or YYYY-MM-DD; default: first of the month)"
+// @Param end query string false "End (RFC 3339, or YYYY-MM-DD inclusive; default: now)"
+// @Param org_id query string false "Organization ID (defaults to the caller's first organization)"
+// @Param project_id query string false "Project ID"
+// @Router /api/v1/usage/summary [get]
+func (h *UsageHandler) Summary(c *gin.Context) {
+	q, ok := h.query(c, periodMonthToDate)
+	if !ok {
+		return
+	}
+	summary, err := h.billing.GetUsageSummary(c.Request.Context(), q.orgID, q.projectID, nil, q.start, q.end)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"start": q.start, "end": q.end, "data": summary})
+}
+
+// Daily godoc
+// @Summary Get my daily usage
+// @Description Requests, tokens and cost per day for the caller's organization (or org_id). Defaults to the last 30 days.
+// @Tags Usage
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Shorthand window ending now: 7d, 30d, mtd, ... (instead of start/end)"
+// @Param start query string false "Start (RFC 3339 or YYYY-MM-DD; default: first of the month)"
+// @Param end query string false "End (RFC 3339, or YYYY-MM-DD inclusive; default: now)"
+// @Param org_id query string false "Organization ID (defaults to the caller's first organization)"
+// @Param project_id query string false "Project ID"
+// @Router /api/v1/usage/daily [get]
+func (h *UsageHandler) Daily(c *gin.Context) {
+	q, ok := h.query(c, "30d")
+	if !ok {
+		return
+	}
+	usage, err := h.billing.GetDailyUsageRange(c.Request.Context(), q.orgID, q.projectID, nil, q.start, q.end)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"start": q.start, "end": q.end, "data": usage})
+}
+
+// ByModel godoc
+// @Summary Get my usage by model
+// @Description Requests, input/output tokens and cost per model for the caller's organization (or org_id). Defaults to month to date.
+// @Tags Usage
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Shorthand window ending now: 7d, 30d, mtd, ... (instead of start/end)"
+// @Param start query string false "Start (RFC 3339 or YYYY-MM-DD; default: first of the month)"
+// @Param end query string false "End (RFC 3339, or YYYY-MM-DD inclusive; default: now)"
+// @Param org_id query string false "Organization ID (defaults to the caller's first organization)"
+// @Param project_id query string false "Project ID"
+// @Router /api/v1/usage/by-model [get]
+func (h *UsageHandler) ByModel(c *gin.Context) {
+	q, ok := h.query(c, periodMonthToDate)
+	if !ok {
+		return
+	}
+	usage, err := h.billing.GetUsageByModel(c.Request.Context(), q.orgID, q.projectID, nil, q.start, q.end)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"start": q.start, "end": q.end, "data": usage})
+}
+
+// ByProvider godoc
+// @Summary Get my usage by provider
+// @Description Requests, tokens, cost, success rate and latency per provider for the caller's organization (or org_id). Defaults to month to date.
+// @Tags Usage
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Shorthand window ending now: 7d, 30d, mtd, ... (instead of start/end)"
+// @Param start query string false "Start (RFC 3339 or YYYY-MM-DD; default: first of the month)"
+// @Param end query string false "End (RFC 3339, or YYYY-MM-DD inclusive; default: now)"
+// @Param org_id query string false "Organization ID (defaults to the caller's first organization)"
+// @Param project_id query string false "Project ID"
+// @Router /api/v1/usage/by-provider [get]
+func (h *UsageHandler) ByProvider(c *gin.Context) {
+	q, ok := h.query(c, periodMonthToDate)
+	if !ok {
+		return
+	}
+	usage, err := h.billing.GetUsageByProvider(c.Request.Context(), q.orgID, q.projectID, nil, q.start, q.end)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"start": q.start, "end": q.end, "data": usage})
+}
+
+// ByTag godoc
+// @Summary Get my usage by attribution tag
+// @Description Requests, input/output tokens and cost per tag key and value, from the metadata or X-Usage-Tags sent with chat requests. A request with several tags counts under each; untagged requests are not included. Defaults to month to date.
+// @Tags Usage
+// @Produce json
+// @Security BearerAuth
+// @Param key query string false "Only this tag key"
+// @Param period query string false "Shorthand window ending now: 7d, 30d, mtd, ... (instead of start/end)"
+// @Param start query string false "Start (RFC 3339 or YYYY-MM-DD; default: first of the month)"
+// @Param end query string false "End (RFC 3339, or YYYY-MM-DD inclusive; default: now)"
+// @Param org_id query string false "Organization ID (defaults to the caller's first organization)"
+// @Param project_id query string false "Project ID"
+// @Router /api/v1/usage/by-tag [get]
+func (h *UsageHandler) ByTag(c *gin.Context) {
+	key := c.Query("key")
+	if len(key) > maxUsageTagKeyLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key is too long"})
+		return
+	}
+	q, ok := h.query(c, periodMonthToDate)
+	if !ok {
+		return
+	}
+	usage, err := h.billing.GetUsageByTag(c.Request.Context(), q.orgID, q.projectID, key, q.start, q.end)
+	if err != nil {
+		h.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"start": q.start, "end": q.end, "data": usage})
+}
+
+// usageQuery is the scope and window of a usage request.
+type usageQuery struct {
+	orgID      uuid.UUID
+	projectID  *uuid.UUID
+	start, end time.Time
+}
+
+// query parses the window (defaulting to defaultPeriod) and the scope of a
+// usage request. It writes the error response itself and returns ok=false on
+// failure.
+func (h *UsageHandler) query(c *gin.Context, defaultPeriod string) (q usageQuery, ok bool) {
+	var err error
+	if q.start, q.end, err = usageRange(c, time.Now(), defaultPeriod); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return q, false
+	}
+	q.orgID, q.projectID, ok = h.scope(c)
+	return q, ok
+}
+
+// scope resolves the organization and optional project to report on. An
+// explicit org_id must be one the caller belongs to. It writes the error
+// response itself and returns ok=false on failure.
+func (h *UsageHandler) scope(c *gin.Context) (orgID uuid.UUID, projectID *uuid.UUID, ok bool) {
+	uid := c.GetString("user_id")
+	userID, err := uuid.Parse(uid)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return uuid.Nil, nil, false
+	}
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project_id"})
+			return uuid.Nil, nil, false
+		}
+		projectID = &id
+	}
+
+	ctx := c.Request.Context()
+	if raw := c.Query("org_id"); raw != "" {
+		if orgID, err = uuid.Parse(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid org_id"})
+			return uuid.Nil, nil, false
+		}
+		if err := h.userSvc.RequireOrgRole(ctx, uid, raw, "OWNER", "ADMIN", "MEMBER", "READONLY"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return uuid.Nil, nil, false
+		}
+		return orgID, projectID, true
+	}
+
+	orgs, err := h.userSvc.GetOrganizations(ctx, userID)
+	if err != nil || len(orgs) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no organization found for user"})
+		return uuid.Nil, nil, false
+	}
+	return orgs[0].ID, projectID, true
+}
+
+func (h *UsageHandler) internalError(c *gin.Context, err error) {
+	h.logger.Error("usage read failed", zap.String("path", c.FullPath()), zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load usage"})
+}
@@ -0,0 +1,88 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the date range parsing shared by the usage endpoints.
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxUsageRange bounds how far apart start and end may be.
+	maxUsageRange = 366 * 24 * time.Hour
+	// usageDateLayout is accepted for start and end besides RFC 3339.
+	usageDateLayout = "2006-01-02"
+	// periodMonthToDate is the period shorthand for the current month.
+	periodMonthToDate = "mtd"
+)
+
+// usageRange reads the reporting window of a usage endpoint from either
+// start/end or a period shorthand ("7d", "30d", "mtd"). Without any of them
+// it uses defaultPeriod. An omitted start defaults to the first of the month,
+// an omitted end to now, and a date-only end covers that whole day.
+func usageRange(c *gin.Context, now time.Time, defaultPeriod string) (start, end time.Time, err error) {
+	rawStart, rawEnd, period := c.Query("start"), c.Query("end"), c.Query("period")
+	if period != "" && (rawStart != "" || rawEnd != "") {
+		return start, end, errors.New("use either period or start/end, not both")
+	}
+	if period == "" && rawStart == "" && rawEnd == "" {
+		period = defaultPeriod
+	}
+	if period != "" {
+		return periodRange(period, now)
+	}
+
+	start = monthStartOf(now)
+	end = now
+	if rawStart != "" {
+		if start, err = parseUsageTime(rawStart, now.Location(), false); err != nil {
+			return start, end, errors.New("start must be RFC 3339 or YYYY-MM-DD")
+		}
+	}
+	if rawEnd != "" {
+		if end, err = parseUsageTime(rawEnd, now.Location(), true); err != nil {
+			return start, end, errors.New("end must be RFC 3339 or YYYY-MM-DD")
+		}
+	}
+	if !start.Before(end) {
+		return start, end, errors.New("start must be before end")
+	}
+	if end.Sub(start) > maxUsageRange {
+		return start, end, errors.New("range must not exceed 366 days")
+	}
+	return start, end, nil
+}
+
+// periodRange resolves a period shorthand ending now.
+func periodRange(period string, now time.Time) (start, end time.Time, err error) {
+	if period == periodMonthToDate {
+		return monthStartOf(now), now, nil
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+	if err != nil || !strings.HasSuffix(period, "d") || days < 1 || days > 366 {
+		return start, end, errors.New("period must be mtd or 1d to 366d")
+	}
+	return now.AddDate(0, 0, -days), now, nil
+}
+
+func parseUsageTime(raw string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(usageDateLayout, raw, loc)
+	if err != nil {
+		return t, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func monthStartOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,87 @@
+// Package handlers provides HTTP request handlers.
+// This file contains the client-supplied attribution tags recorded on usage
+// logs.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// usageTagsHeader carries attribution tags as comma-separated key=value
+// pairs, e.g. "project=checkout,env=prod".
+const usageTagsHeader = "X-Usage-Tags"
+
+// ctxKeyUsageTags holds the validated tags of the request.
+const ctxKeyUsageTags = "usage_tags"
+
+// Limits on attribution tags, matching OpenAI's request metadata.
+const (
+	maxUsageTags        = 16
+	maxUsageTagKeyLen   = 64
+	maxUsageTagValueLen = 512
+)
+
+// parseUsageTags merges the X-Usage-Tags header with the request body's
+// metadata, the body winning on duplicate keys, and validates the result.
+// It returns nil when the request carries no tags.
+func parseUsageTags(header string, metadata map[string]string) (models.StringMap, error) {
+	tags := make(models.StringMap, len(metadata))
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s entries must be key=value, got %q", usageTagsHeader, pair)
+		}
+		tags[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	for k, v := range metadata {
+		tags[k] = v
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	if len(tags) > maxUsageTags {
+		return nil, fmt.Errorf("at most %d usage tags are allowed, got %d", maxUsageTags, len(tags))
+	}
+	for k, v := range tags {
+		if k == "" {
+			return nil, errors.New("usage tag keys must not be empty")
+		}
+		if len(k) > maxUsageTagKeyLen {
+			return nil, fmt.Errorf("usage tag key %q exceeds %d characters", k[:maxUsageTagKeyLen], maxUsageTagKeyLen)
+		}
+		if len(v) > maxUsageTagValueLen {
+			return nil, fmt.Errorf("usage tag %q value exceeds %d characters", k, maxUsageTagValueLen)
+		}
+	}
+	return tags, nil
+}
+
+// setUsageTags parses and stores the request's attribution tags for its usage
+// logs.
+func setUsageTags(c *gin.Context, metadata map[string]string) error {
+	tags, err := parseUsageTags(c.GetHeader(usageTagsHeader), metadata)
+	if err != nil {
+		return err
+	}
+	if tags != nil {
+		c.Set(ctxKeyUsageTags, tags)
+	}
+	return nil
+}
+
+// usageTags returns the request's attribution tags, or nil.
+func usageTags(c *gin.Context) models.StringMap {
+	tags, _ := c.Get(ctxKeyUsageTags)
+	m, _ := tags.(models.StringMap)
+	return m
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"llm-router-platform/internal/config"
 	"llm-router-platform/internal/models"
 	"llm-router-platform/internal/service/user"
+	"llm-router-platform/pkg/sanitize"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -19,6 +21,16 @@ import (
 	"go.uber.org/zap"
 )
 
+// apiKeyPrefix starts every platform API key (see user.generateAPIKey).
+const apiKeyPrefix = "llm_"
+
+var (
+	errMissingAPIKey   = errors.New("missing API key")
+	errBadAuthScheme   = errors.New("invalid authorization format: expected 'Bearer <api key>'")
+	errJWTAsAPIKey     = errors.New("this endpoint requires an API key, not a session token: send the key in X-API-Key or as 'Bearer llm_...'")
+	errAPIKeyAsSession = errors.New("this endpoint requires a session token, not an API key: sign in and send the JWT as 'Bearer <token>'")
+)
+
 // AuthMiddleware handles JWT authentication.
 type AuthMiddleware struct {
 	jwtSecret   []byte
@@ -82,6 +94,9 @@ func (m *AuthMiddleware) parseTokenClaims(authHeader string) (jwt.MapClaims, err
 	if len(parts) != 2 || parts[0] != "Bearer" {
 		return nil, fmt.Errorf("invalid authorization format")
 	}
+	if strings.HasPrefix(parts[1], apiKeyPrefix) {
+		return nil, errAPIKeyAsSession
+	}
 
 	token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -173,17 +188,44 @@ func (m *AuthMiddleware) OptionalJWT() gin.HandlerFunc {
 	}
 }
 
+// apiKeyFromRequest extracts the API key from a request. X-API-Key takes
+// precedence; otherwise the key must be sent as "Authorization: Bearer <key>".
+// A JWT in the Authorization header is rejected with a precise error instead
+// of being looked up as a key.
+func apiKeyFromRequest(r *http.Request) (string, error) {
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return key, nil
+	}
+	authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
+	if authHeader == "" {
+		return "", errMissingAPIKey
+	}
+	if strings.HasPrefix(authHeader, apiKeyPrefix) {
+		return authHeader, nil // bare key without a scheme, accepted for older clients
+	}
+	scheme, token, ok := strings.Cut(authHeader, " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", errBadAuthScheme
+	}
+	if !strings.HasPrefix(token, apiKeyPrefix) && looksLikeJWT(token) {
+		return "", errJWTAsAPIKey
+	}
+	return token, nil
+}
+
+// looksLikeJWT reports whether token has the shape of a compact JWS: three
+// dot-separated segments starting with a base64url-encoded JSON header.
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2 && strings.HasPrefix(token, "eyJ")
+}
+
 // APIKey validates API key in header.
 func (m *AuthMiddleware) APIKey() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			apiKey = c.GetHeader("Authorization")
-			apiKey = strings.TrimPrefix(apiKey, "Bearer ")
-		}
-
-		if apiKey == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing API key"})
+		apiKey, err := apiKeyFromRequest(c.Request)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
 
@@ -193,6 +235,19 @@ func (m *AuthMiddleware) APIKey() gin.HandlerFunc {
 			return
 		}
 
+		if len(key.AllowedCIDRs) > 0 {
+			// ClientIP only honours X-Forwarded-For from TRUSTED_PROXIES, so a
+			// client cannot forge its way into the allowlist.
+			clientIP := c.ClientIP()
+			if !CheckIPAllowed(clientIP, ParseWhitelist(strings.Join(key.AllowedCIDRs, ","), m.logger), m.logger) {
+				m.logger.Warn("API key access blocked from non-allowlisted IP",
+					zap.String("ip", sanitize.LogValue(sanitize.MaskIP(clientIP))),
+					zap.String("key_prefix", key.KeyPrefix))
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: IP not allowed for this API key"})
+				return
+			}
+		}
+
 		c.Set("project", projectObj)
 		c.Set("api_key", key)
 		c.Set("project_id", projectObj.ID.String())
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func echoRouter(limit int64) *gin.Engine {
+	router := gin.New()
+	router.Use(BodySizeLimit(limit))
+	router.Use(Gzip(limit))
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.String(http.StatusRequestEntityTooLarge, "too large")
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+func TestGzipDecompressesRequestBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/echo", bytes.NewReader(gzipBytes(t, []byte("hello"))))
+	req.Header.Set("Content-Encoding", "gzip")
+	echoRouter(1000).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+}
+
+func TestGzipLimitsDecompressedSize(t *testing.T) {
+	// ~100 KB of zeros compresses to well under the 1 KB limit.
+	compressed := gzipBytes(t, make([]byte, 100<<10))
+	require.Less(t, len(compressed), 1000)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/echo", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	echoRouter(1000).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestGzipRejectsMalformedBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/echo", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+	echoRouter(1000).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGzipCompressesResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/echo", strings.NewReader("hello"))
+	req.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+	echoRouter(1000).ServeHTTP(w, req)
+
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	zr, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+}
+
+func TestGzipSkipsEventStream(t *testing.T) {
+	router := gin.New()
+	router.Use(Gzip(1000))
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, "data: hi\n\n")
+		c.Writer.Flush()
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: hi\n\n", w.Body.String())
+}
+
+func TestGzipWriterSupportsResponseController(t *testing.T) {
+	router := gin.New()
+	router.Use(Gzip(1000))
+	var deadlineErr error
+	router.GET("/stream", func(c *gin.Context) {
+		deadlineErr = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+		c.Status(http.StatusOK)
+	})
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL+"/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.NoError(t, deadlineErr)
+}
@@ -46,6 +46,32 @@ func TestCheckIPAllowed(t *testing.T) {
 	assert.False(t, middleware.CheckIPAllowed("invalid_ip", subnets, logger))
 }
 
+func TestCheckIPAllowedAPIKeyCIDRs(t *testing.T) {
+	logger := zap.NewNop()
+	subnets := middleware.ParseWhitelist("203.0.113.0/24,2001:db8::/48", logger)
+
+	assert.True(t, middleware.CheckIPAllowed("203.0.113.200", subnets, logger))
+	assert.True(t, middleware.CheckIPAllowed("2001:db8::42", subnets, logger))
+	assert.False(t, middleware.CheckIPAllowed("203.0.114.1", subnets, logger))
+	assert.False(t, middleware.CheckIPAllowed("2001:db9::1", subnets, logger))
+}
+
+func TestAPIKeyAllowlistIgnoresForgedForwardedFor(t *testing.T) {
+	logger := zap.NewNop()
+	allowed := middleware.ParseWhitelist("1.1.1.1", logger)
+
+	c, engine := gin.CreateTestContext(httptest.NewRecorder())
+	assert.NoError(t, engine.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("X-Forwarded-For", "1.1.1.1")
+
+	c.Request.RemoteAddr = "203.0.113.7:4567"
+	assert.False(t, middleware.CheckIPAllowed(c.ClientIP(), allowed, logger), "untrusted peers cannot set the client IP")
+
+	c.Request.RemoteAddr = "10.0.0.9:4567"
+	assert.True(t, middleware.CheckIPAllowed(c.ClientIP(), allowed, logger), "a trusted proxy's X-Forwarded-For is honoured")
+}
+
 func TestAdminIPWhitelist(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	logger := zap.NewNop()
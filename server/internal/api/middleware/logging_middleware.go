@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"llm-router-platform/pkg/sanitize"
@@ -10,9 +12,29 @@ import (
 	"go.uber.org/zap"
 )
 
+// requestLogFieldsKey holds the RequestLogFields a handler attached.
+const requestLogFieldsKey = "request_log_fields"
+
+// RequestLogFields carries the LLM details of a request so a slow request
+// warning can name the provider, model and token counts involved.
+type RequestLogFields struct {
+	Provider         string
+	Model            string
+	Stream           bool // latency covers the whole stream
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// SetRequestLogFields attaches LLM details to the request log. Later calls
+// replace earlier ones.
+func SetRequestLogFields(c *gin.Context, f RequestLogFields) {
+	c.Set(requestLogFieldsKey, f)
+}
+
 // LoggingMiddleware provides request logging.
 type LoggingMiddleware struct {
-	logger *zap.Logger
+	logger        *zap.Logger
+	slowThreshold time.Duration // 0 = no slow request warnings
 }
 
 // NewLoggingMiddleware creates a new logging middleware.
@@ -20,6 +42,12 @@ func NewLoggingMiddleware(logger *zap.Logger) *LoggingMiddleware {
 	return &LoggingMiddleware{logger: logger}
 }
 
+// SetSlowRequestThreshold makes Log emit an additional warn-level entry for
+// requests that take at least d. Zero or negative disables it.
+func (m *LoggingMiddleware) SetSlowRequestThreshold(d time.Duration) {
+	m.slowThreshold = d
+}
+
 // Log logs request details including the request ID for correlation.
 func (m *LoggingMiddleware) Log() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -50,12 +78,56 @@ func (m *LoggingMiddleware) Log() gin.HandlerFunc {
 			zap.Duration("latency", latency),
 			zap.String("client_ip", clientIP),
 		)
+
+		if m.slowThreshold > 0 && latency >= m.slowThreshold {
+			m.logSlow(c, reqIDStr, method, path, status, latency)
+		}
 	}
 }
 
+// logSlow warns about a request that exceeded the slow request threshold,
+// including the LLM details its handler attached.
+func (m *LoggingMiddleware) logSlow(c *gin.Context, requestID, method, path string, status int, latency time.Duration) {
+	fields := []zap.Field{
+		zap.String("request_id", requestID),
+		zap.String("method", method),
+		zap.String("path", path),
+		zap.Int("status", status),
+		zap.Duration("latency", latency),
+		zap.Duration("threshold", m.slowThreshold),
+	}
+	if v, ok := c.Get(requestLogFieldsKey); ok {
+		if f, ok := v.(RequestLogFields); ok {
+			fields = append(fields,
+				zap.String("provider", f.Provider),
+				zap.String("model", sanitize.LogValue(f.Model)),
+				zap.Bool("stream", f.Stream),
+				zap.Int("prompt_tokens", f.PromptTokens),
+				zap.Int("completion_tokens", f.CompletionTokens),
+			)
+		}
+	}
+	m.logger.Warn("slow request", fields...)
+}
+
+// Default CORS preflight answers. Methods cover GraphQL (POST) and the LLM
+// API (GET, POST); headers cover authentication, provider pinning, request
+// correlation and idempotent retries.
+var (
+	DefaultCORSMethods = []string{"GET", "POST", "OPTIONS"}
+	DefaultCORSHeaders = []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-LLM-Provider", "X-Request-ID", "Idempotency-Key"}
+)
+
+// DefaultCORSMaxAge is how long browsers may cache a preflight answer.
+const DefaultCORSMaxAge = 24 * time.Hour
+
 // CORSMiddleware handles CORS headers.
 type CORSMiddleware struct {
-	allowOrigins []string
+	allowOrigins     []string
+	allowCredentials bool
+	allowMethods     string
+	allowHeaders     string
+	maxAge           string
 }
 
 // NewCORSMiddleware creates a new CORS middleware.
@@ -70,9 +142,34 @@ func NewCORSMiddleware(origins []string, mode string) *CORSMiddleware {
 			}
 			// Silently drop "*" in release mode — forces explicit origin config
 		}
-		return &CORSMiddleware{allowOrigins: filtered}
+		origins = filtered
+	}
+	m := &CORSMiddleware{allowOrigins: origins, allowCredentials: true}
+	m.SetPreflight(nil, nil, DefaultCORSMaxAge)
+	return m
+}
+
+// SetAllowCredentials controls whether browsers may send cookies and
+// Authorization headers cross-origin. Credentials are only ever allowed for
+// explicitly configured origins, never with the "*" wildcard.
+func (m *CORSMiddleware) SetAllowCredentials(allow bool) {
+	m.allowCredentials = allow
+}
+
+// SetPreflight sets the methods and request headers cross-origin requests may
+// use and how long browsers cache the preflight answer; a maxAge of zero
+// makes them send a preflight before every request. Empty lists keep the
+// defaults.
+func (m *CORSMiddleware) SetPreflight(methods, headers []string, maxAge time.Duration) {
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	if len(headers) == 0 {
+		headers = DefaultCORSHeaders
 	}
-	return &CORSMiddleware{allowOrigins: origins}
+	m.allowMethods = strings.Join(methods, ", ")
+	m.allowHeaders = strings.Join(headers, ", ")
+	m.maxAge = strconv.Itoa(int(maxAge.Seconds()))
 }
 
 // Handle adds CORS headers.
@@ -93,17 +190,18 @@ func (m *CORSMiddleware) Handle() gin.HandlerFunc {
 				c.Header("Access-Control-Allow-Origin", "*")
 			} else {
 				c.Header("Access-Control-Allow-Origin", origin)
-				c.Header("Access-Control-Allow-Credentials", "true")
+				if m.allowCredentials {
+					c.Header("Access-Control-Allow-Credentials", "true")
+				}
 				// Vary: Origin prevents CDN/proxy cache poisoning when reflecting
 				// the request Origin header into Access-Control-Allow-Origin.
 				c.Header("Vary", "Origin")
 			}
 		}
 
-		// Only allow methods actually used: GraphQL (POST) and LLM API (GET, POST)
-		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-API-Key")
-		c.Header("Access-Control-Max-Age", "86400")
+		c.Header("Access-Control-Allow-Methods", m.allowMethods)
+		c.Header("Access-Control-Allow-Headers", m.allowHeaders)
+		c.Header("Access-Control-Max-Age", m.maxAge)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
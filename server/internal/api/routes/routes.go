@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"net/http" // Added for http.StatusOK
 	"net/http/pprof"
+	"time"
 
 	"llm-router-platform/internal/api/handlers"
 	"llm-router-platform/internal/api/middleware"
@@ -13,6 +14,7 @@ import (
 	"llm-router-platform/internal/graphql/dataloaders"
 	gqlhandler "llm-router-platform/internal/graphql/handler"
 	"llm-router-platform/internal/graphql/resolvers"
+	"llm-router-platform/internal/repository"
 	"llm-router-platform/internal/service/admin"
 	announcementSvc "llm-router-platform/internal/service/announcement"
 	"llm-router-platform/internal/service/audit"
@@ -32,6 +34,7 @@ import (
 	"llm-router-platform/internal/service/redeem"
 	"llm-router-platform/internal/service/router"
 	"llm-router-platform/internal/service/safety"
+	"llm-router-platform/internal/service/shadow"
 	"llm-router-platform/internal/service/task"
 	"llm-router-platform/internal/service/turnstile"
 	"llm-router-platform/internal/service/user"
@@ -62,6 +65,7 @@ type Services struct {
 	EmailVerifySvc   *user.EmailVerificationService
 	LoginLimiter     *user.LoginLimiter
 	Router           *router.Router
+	Shadow           *shadow.Service // nil when shadow traffic is disabled
 	Billing          *billing.Service
 	BudgetService    *billing.BudgetService
 	Subscription     *billing.SubscriptionService
@@ -87,6 +91,7 @@ type Services struct {
 	MonitoringSvc    *monitoring.Collector
 	TurnstileSvc     *turnstile.Service
 	SemanticCache    *semantic.SemanticCacheService
+	FailedRequests   repository.FailedRequestRepo
 	RedisClient      *redis.Client // For rate limiting middleware
 	DB               *gorm.DB      // For operational health checks
 	Config           *config.Config
@@ -112,13 +117,20 @@ func Setup(
 	// 5. Recovery
 	requestIDMiddleware := middleware.NewRequestIDMiddleware(logger)
 	corsMiddleware := middleware.NewCORSMiddleware(cfg.Server.CORSOrigins, cfg.Server.Mode)
+	corsMiddleware.SetAllowCredentials(cfg.Server.CORSAllowCredentials)
+	corsMiddleware.SetPreflight(cfg.Server.CORSAllowedMethods, cfg.Server.CORSAllowedHeaders, time.Duration(cfg.Server.CORSMaxAgeSeconds)*time.Second)
 	loggingMiddleware := middleware.NewLoggingMiddleware(logger)
+	loggingMiddleware.SetSlowRequestThreshold(cfg.Log.SlowRequestThreshold)
 	recoveryMiddleware := middleware.NewRecoveryMiddleware(logger)
 
 	engine.Use(requestIDMiddleware.Handle())
 	engine.Use(metricsCollector.Middleware())
+	engine.Use(middleware.Tracing())
 	engine.Use(middleware.SecurityHeaders())
 	engine.Use(middleware.BodySizeLimit(10 << 20)) // 10 MB hard limit
+	if cfg.Server.GzipEnabled {
+		engine.Use(middleware.Gzip(10 << 20)) // same limit, applied to the decompressed body
+	}
 	engine.Use(corsMiddleware.Handle())
 	engine.Use(loggingMiddleware.Log())
 	engine.Use(recoveryMiddleware.Recover())
@@ -281,6 +293,16 @@ func Setup(
 		chatSafety = safety.NewRuleEngine()
 	}
 	chatHandler := handlers.NewChatHandler(services.Router, services.Billing, chatMemory, services.Subscription, services.Balance, services.Observability, services.DB, chatCache, services.RedisClient, chatSafety, logger)
+	chatHandler.SetStreamFallback(cfg.Router.StreamFallbackEnabled)
+	chatHandler.SetStreamWriteTimeout(time.Duration(cfg.Server.StreamWriteTimeoutSeconds) * time.Second)
+	chatHandler.SetRequestDeadline(time.Duration(cfg.Router.RequestDeadlineSecs) * time.Second)
+	chatHandler.SetEndUserInjection(cfg.Router.InjectEndUser)
+	chatHandler.SetRoutingHeaders(cfg.Router.ExposeRoutingHeaders)
+	chatHandler.SetMessageLimits(cfg.Router.MaxChatMessages, cfg.Router.MaxMessageChars)
+	chatHandler.SetShadow(services.Shadow)
+	chatHandler.SetBudgets(services.BudgetService)
+	chatHandler.SetFailedRequestLog(services.FailedRequests, cfg.Router.FailedRequestLogPerMinute)
+	chatHandler.SetFailedRequestCapture(cfg.Router.FailedRequestCapture)
 	modelHandler := handlers.NewModelHandler(services.Router, services.Provider, logger)
 	paymentHandler := handlers.NewPaymentHandler(services.Payment, services.WechatPay, services.Alipay, logger)
 	auditExportHandler := handlers.NewAuditHandler(services.AuditService, logger)
@@ -321,6 +343,124 @@ func Setup(
 				auditGrp.GET("/export/csv", auditExportHandler.ExportCSV)
 			}
 
+			// ─── Provider Maintenance ────────────────────────────────
+			// Creates providers, shows one provider with its keys, health and
+			// routing state, bulk-imports provider keys, drains providers for
+			// maintenance, and verifies an unsaved provider config; the test
+			// key is never stored.
+			providerHandler := handlers.NewProviderHandler(services.Router, services.Health, logger)
+			providersGrp := v1.Group("/providers")
+			providersGrp.Use(authMiddleware.JWT())
+			providersGrp.Use(middleware.AdminOnly())
+			{
+				providersGrp.POST("", providerHandler.Create)
+				providersGrp.POST("/test", providerHandler.TestConfig)
+				providersGrp.GET("/:id", providerHandler.Get)
+				providersGrp.POST("/:id/keys/batch", providerHandler.BatchCreateKeys)
+				providersGrp.POST("/:id/drain", providerHandler.Drain)
+				providersGrp.DELETE("/:id/drain", providerHandler.Resume)
+			}
+			// Capabilities drive UI toggles, so any signed-in user may read them.
+			providerInfoGrp := v1.Group("/providers")
+			providerInfoGrp.Use(authMiddleware.JWT())
+			{
+				providerInfoGrp.GET("/:id/capabilities", providerHandler.Capabilities)
+			}
+
+			// ─── Proxy Pool ──────────────────────────────────────────
+			// Aggregates stored per-proxy counters for capacity planning and
+			// lets operators re-baseline them after fixing a proxy.
+			proxyHandler := handlers.NewProxyHandler(services.Proxy, services.AuditService, logger)
+			proxiesGrp := v1.Group("/proxies")
+			proxiesGrp.Use(authMiddleware.JWT())
+			proxiesGrp.Use(middleware.AdminOnly())
+			{
+				proxiesGrp.GET("/stats", proxyHandler.Stats)
+				proxiesGrp.POST("/reset-stats", proxyHandler.ResetAllStats)
+				proxiesGrp.POST("/:id/reset-stats", proxyHandler.ResetStats)
+			}
+
+			// ─── Alerts ──────────────────────────────────────────────
+			// Verifies alert webhooks and reports on their retry queue.
+			alertHandler := handlers.NewAlertHandler(services.Health, logger)
+			alertsGrp := v1.Group("/alerts")
+			alertsGrp.Use(authMiddleware.JWT())
+			alertsGrp.Use(middleware.AdminOnly())
+			{
+				alertsGrp.POST("/config/test", alertHandler.TestWebhook)
+				alertsGrp.GET("/deliveries/stats", alertHandler.DeliveryStats)
+			}
+
+			// ─── Health History ──────────────────────────────────────
+			// Health checks of a provider, key or proxy bucketed over time
+			// for uptime charts, and the aggregate system status.
+			healthTrendHandler := handlers.NewHealthTrendHandler(services.Health, logger)
+			systemHealthHandler := handlers.NewSystemHealthHandler(services.Router, services.Health, services.DB, services.RedisClient, logger)
+			healthGrp := v1.Group("/health")
+			healthGrp.Use(authMiddleware.JWT())
+			healthGrp.Use(middleware.AdminOnly())
+			{
+				healthGrp.GET("/system", systemHealthHandler.System)
+				healthGrp.GET("/:target_type/:target_id/trend", healthTrendHandler.Trend)
+			}
+
+			// ─── Usage ───────────────────────────────────────────────
+			// The caller's own usage over a date range (start/end or period).
+			usageHandler := handlers.NewUsageHandler(services.User, services.Billing, logger)
+			usageGrp := v1.Group("/usage")
+			usageGrp.Use(authMiddleware.JWT())
+			{
+				usageGrp.GET("/summary", usageHandler.Summary)
+				usageGrp.GET("/daily", usageHandler.Daily)
+				usageGrp.GET("/by-model", usageHandler.ByModel)
+				usageGrp.GET("/by-provider", usageHandler.ByProvider)
+				usageGrp.GET("/by-tag", usageHandler.ByTag)
+			}
+
+			// ─── Admin Operations ────────────────────────────────────
+			// Live in-memory counters, so dashboards can poll without GraphQL.
+			// Secret re-encryption after ENCRYPTION_KEY rotation.
+			// Audited read-only views of a user's dashboard for support.
+			// Model pricing and capability maintenance.
+			// Per-model routing overrides pinning a provider and key.
+			// Model tier aliases ("fast", "smart") clients may request.
+			// Dead-letter log of chat requests that failed on every provider.
+			// Provider key fleet view with health and router cooldown.
+			statsHandler := handlers.NewStatsHandler(chatHandler.Stats(), services.Router)
+			cryptoHandler := handlers.NewCryptoHandler(services.AdminSvc, services.AuditService, logger)
+			adminDashboardHandler := handlers.NewAdminDashboardHandler(services.User, services.Billing, services.AuditService, logger)
+			adminModelHandler := handlers.NewAdminModelHandler(services.AdminSvc, services.AuditService, logger)
+			routeOverrideHandler := handlers.NewRouteOverrideHandler(services.Router, services.AuditService, logger)
+			modelTierHandler := handlers.NewModelTierHandler(services.Router, services.AuditService, logger)
+			failedRequestHandler := handlers.NewFailedRequestHandler(services.FailedRequests, services.AuditService, logger)
+			providerKeyHandler := handlers.NewProviderKeyHandler(services.Router, services.Health, services.AuditService, logger)
+			adminGrp := v1.Group("/admin")
+			adminGrp.Use(authMiddleware.JWT())
+			adminGrp.Use(middleware.AdminOnly())
+			{
+				adminGrp.GET("/stats/realtime", statsHandler.Realtime)
+				adminGrp.POST("/crypto/rekey", cryptoHandler.Rekey)
+				adminGrp.GET("/dashboard/stats", adminDashboardHandler.Stats)
+				adminGrp.GET("/dashboard/usage/daily", adminDashboardHandler.DailyUsage)
+				adminGrp.GET("/dashboard/usage/providers", adminDashboardHandler.UsageByProvider)
+				adminGrp.GET("/dashboard/usage/recent", adminDashboardHandler.RecentUsage)
+				adminGrp.POST("/models", adminModelHandler.Create)
+				adminGrp.PUT("/models/:id", adminModelHandler.Update)
+				adminGrp.POST("/models/import", adminModelHandler.Import)
+				adminGrp.GET("/route-overrides", routeOverrideHandler.List)
+				adminGrp.POST("/route-overrides", routeOverrideHandler.Create)
+				adminGrp.PUT("/route-overrides/:id", routeOverrideHandler.Update)
+				adminGrp.DELETE("/route-overrides/:id", routeOverrideHandler.Delete)
+				adminGrp.GET("/model-tiers", modelTierHandler.Get)
+				adminGrp.PUT("/model-tiers", modelTierHandler.Update)
+				adminGrp.GET("/failed-requests", failedRequestHandler.List)
+				adminGrp.GET("/failed-requests/summary", failedRequestHandler.Summary)
+				adminGrp.GET("/failed-requests/:id/capture", failedRequestHandler.Capture)
+				adminGrp.GET("/provider-keys", providerKeyHandler.List)
+				adminGrp.GET("/provider-keys/cooldowns", providerKeyHandler.Cooldowns)
+				adminGrp.POST("/provider-keys/:id/clear-cooldown", providerKeyHandler.ClearCooldown)
+			}
+
 			// ─── LLM API Endpoints ──────────────────────────────
 			// Registered under /api/v1 (management API namespace).
 			registerLLMEndpoints(v1, applyLLMMiddleware, chatHandler, modelHandler, authMiddleware)
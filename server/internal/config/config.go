@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -35,6 +36,9 @@ type Config struct {
 	OAuth2        OAuth2Config
 	Turnstile     TurnstileConfig
 	Cleanup       CleanupConfig
+	Memory        MemoryConfig
+	Router        RouterConfig
+	Shadow        ShadowConfig
 	FeatureGates  *FeatureGates
 }
 
@@ -60,11 +64,19 @@ type ServerConfig struct {
 	Port                        string
 	Mode                        string
 	CORSOrigins                 []string // Allowed CORS origins; empty or ["*"] = allow all
+	CORSAllowCredentials        bool     // Allow credentialed requests from explicitly listed origins (default: true)
+	CORSAllowedMethods          []string // Methods allowed in CORS preflight; empty = GET, POST, OPTIONS
+	CORSAllowedHeaders          []string // Request headers allowed in CORS preflight; empty = built-in list
+	CORSMaxAgeSeconds           int      // How long browsers cache a preflight answer (default: 86400)
 	PprofEnabled                bool     // Opt-in pprof endpoints; default false
 	MetricsAllowUnauthenticated bool     // Expose /internal/metrics without auth for Prometheus scraping
 	ReadTimeoutSeconds          int      // HTTP server read timeout (default: 30)
-	WriteTimeoutSeconds         int      // HTTP server write timeout; must be large for LLM streaming (default: 600)
+	WriteTimeoutSeconds         int      // HTTP server write timeout for non-streaming responses (default: 600)
+	StreamWriteTimeoutSeconds   int      // Write deadline for SSE responses, replacing WriteTimeout; 0 = none (default: 0)
 	AllowLocalProviders         bool     // Allow provider URLs pointing to private/reserved IPs (default: false)
+	GzipEnabled                 bool     // gzip request decompression and response compression (default: false)
+	TrustedProxies              []string // Proxy IPs/CIDRs whose X-Forwarded-For gin trusts for ClientIP; empty = trust none
+	SeedDefaults                bool     // Seed default providers and models on startup (default: on unless GIN_MODE=release)
 }
 
 // DatabaseConfig holds database connection configuration.
@@ -78,6 +90,8 @@ type DatabaseConfig struct {
 	MaxOpenConns           int    // Maximum number of open connections to the database
 	MaxIdleConns           int    // Maximum number of idle connections in the pool
 	ConnMaxLifetimeMinutes int    // Maximum lifetime of a connection in minutes
+	ConnectAttempts        int    // Startup connection attempts before giving up
+	ConnectRetrySeconds    int    // Delay between startup connection attempts
 }
 
 // RedisConfig holds Redis connection configuration.
@@ -108,6 +122,9 @@ type ProviderConfig struct {
 	APIKey     string // #nosec G101 -- internal config, never serialized to API responses
 	BaseURL    string
 	HTTPClient HTTPClientProvider // Optional custom HTTP client (e.g., with proxy)
+	// PromptCaching marks the system prompt as a prompt-cache breakpoint
+	// (Anthropic cache_control); ignored by other providers.
+	PromptCaching bool
 }
 
 // HTTPClientProvider is a function that returns an HTTP client.
@@ -127,13 +144,23 @@ type HealthCheckConfig struct {
 	Timeout          time.Duration
 	RetryCount       int
 	FailureThreshold int
+	// LatencySLAWeightFactor scales the routing weight of a provider whose
+	// average latency breaches its alert config's latency threshold, until it
+	// recovers. 1 leaves the weight unchanged.
+	LatencySLAWeightFactor float64
 }
 
 // AlertConfig holds alert notification configuration.
 type AlertConfig struct {
-	Enabled      bool
-	WebhookURL   string
-	EmailEnabled bool
+	Enabled       bool
+	WebhookURL    string
+	WebhookSecret string // #nosec G101 -- HMAC key for alert webhook bodies
+	EmailEnabled  bool
+
+	// Failed webhook deliveries are queued and retried with exponential backoff.
+	RetryMaxAttempts int           // Attempts before a delivery is dead-lettered, including the first
+	RetryBaseDelay   time.Duration // Delay before the first retry; doubles per attempt
+	RetryMaxDelay    time.Duration // Upper bound for the retry delay
 }
 
 // EmailConfig holds transactional email configuration.
@@ -212,6 +239,9 @@ type RateLimitConfig struct {
 type LogConfig struct {
 	Level  string
 	Format string
+	// SlowRequestThreshold adds a warn-level log for requests at least this
+	// slow, with provider, model and token details. 0 disables it.
+	SlowRequestThreshold time.Duration
 }
 
 // AdminConfig holds default admin user configuration.
@@ -229,9 +259,55 @@ type RegistrationConfig struct {
 
 // CleanupConfig holds data retention settings for periodic cleanup jobs.
 type CleanupConfig struct {
-	HealthRetentionDays int // Days to retain health check history (default: 30)
-	AlertRetentionDays  int // Days to retain resolved alerts (default: 90)
-	AuditRetentionDays  int // Days to retain audit log entries (default: 90)
+	HealthRetentionDays        int // Days to retain health check history (default: 30)
+	AlertRetentionDays         int // Days to retain resolved alerts (default: 90)
+	AuditRetentionDays         int // Days to retain audit log entries (default: 90)
+	FailedRequestRetentionDays int // Days to retain dead-letter failed request records (default: 14)
+	FailedRequestCaptureDays   int // Days to retain captured payloads of failed requests; the record itself stays (default: 3)
+	ShadowLogRetentionDays     int // Days to retain shadow traffic comparison records (default: 7)
+	AlertDeliveryRetentionDays int // Days to retain delivered and dead-lettered alert webhook deliveries (default: 30)
+}
+
+// MemoryConfig holds conversation memory settings.
+type MemoryConfig struct {
+	MaxMessages int // Messages kept per conversation; oldest non-system messages are pruned (0 = unlimited, default: 200)
+}
+
+// ShadowConfig controls mirroring a sample of chat traffic to a shadow
+// provider for offline comparison. Shadow requests never affect the client
+// response and are not billed.
+type ShadowConfig struct {
+	Provider   string  // Shadow provider name; empty disables shadowing
+	Model      string  // Model sent to the shadow provider; empty = the requested model
+	SampleRate float64 // Fraction of chat requests mirrored, 0..1 (default: 0)
+}
+
+// RouterConfig holds upstream error-handling settings for the router.
+type RouterConfig struct {
+	QuotaKeywords             []string            // Quota/rate-limit error keywords; empty = built-in defaults
+	ProviderQuotaKeywords     map[string][]string // Extra keywords per provider name
+	StreamFallbackEnabled     bool                // Retry failed stream setups as non-streaming chat (default: false)
+	UnknownModelPolicy        string              // strategy | reject | catch_all (default: strategy)
+	CatchAllProvider          string              // Provider name used for unknown models when the policy is catch_all
+	MaxIdleConnsPerHost       int                 // Keep-alive connections kept per upstream host (default: 32)
+	IdleConnTimeoutSecs       int                 // Seconds an idle upstream connection is kept (default: 90)
+	RequestDeadlineSecs       int                 // Total budget for a chat request across retries and fallbacks; 0 = none (default: 600)
+	InjectEndUser             bool                // Send a hashed API key ID as "user" when a chat request has none (default: true)
+	ExposeRoutingHeaders      bool                // Report the serving provider, upstream model and attempt count in chat response headers (default: false)
+	ModelListCacheTTLSecs     int                 // Seconds upstream /models lists are cached (default: 300)
+	ModelListCacheRedis       bool                // Share cached model lists across instances through Redis (default: true)
+	FailedRequestLogPerMinute int                 // Failed chat requests recorded in the dead-letter log per minute; 0 = off (default: 60)
+	FailedRequestCapture      bool                // Also log chat requests failing with a 4xx upstream error, keeping their encrypted payload and upstream error bodies (default: false)
+	KeyRetryBackoffMs         int                 // Delay before retrying a request on the next API key, doubled per key and jittered; 0 = none (default: 200)
+	KeyRetryBackoffMaxMs      int                 // Cap on one key retry delay (default: 2000)
+	KeyRetryBudgetMs          int                 // Total key retry delay per request and provider before giving up; 0 = no cap (default: 5000)
+	KeySelection              string              // weighted | least_used: how a provider key is picked among eligible keys (default: weighted)
+	MaxChatMessages           int                 // Messages allowed in one chat request; 0 = unlimited (default: 1000)
+	MaxMessageChars           int                 // Characters allowed in one chat message; 0 = unlimited (default: 1000000)
+	ProviderMaxConcurrent     int                 // Chat requests sent to one provider at once, the rest queue; 0 = no queue (default: 0)
+	ProviderQueueDepth        int                 // Chat requests that may wait for a provider slot (default: 100)
+	ProviderQueueMaxWaitMs    int                 // How long a queued request waits for a provider slot (default: 5000)
+	ModelTierAliases          map[string]string   // Default tier alias → model, e.g. fast → a cheap model; admins may replace it at runtime
 }
 
 // ObservabilityConfig holds observability configuration (e.g. Langfuse, Sentry).
@@ -250,6 +326,15 @@ type ObservabilityConfig struct {
 	LokiURL           string
 }
 
+// seedDefaultsEnabled reports whether default providers and models are
+// seeded: in development only, unless SEED_DEFAULTS says otherwise.
+func seedDefaultsEnabled() bool {
+	if viper.IsSet("SEED_DEFAULTS") {
+		return viper.GetBool("SEED_DEFAULTS")
+	}
+	return viper.GetString("GIN_MODE") != "release"
+}
+
 // Load reads configuration from environment variables and .env file.
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
@@ -275,16 +360,50 @@ func Load() (*Config, error) {
 		}
 	}
 
+	var corsMethods, corsHeaders []string
+	for _, m := range strings.Split(viper.GetString("CORS_ALLOWED_METHODS"), ",") {
+		if trimmed := strings.TrimSpace(m); trimmed != "" {
+			corsMethods = append(corsMethods, strings.ToUpper(trimmed))
+		}
+	}
+	for _, h := range strings.Split(viper.GetString("CORS_ALLOWED_HEADERS"), ",") {
+		if trimmed := strings.TrimSpace(h); trimmed != "" {
+			corsHeaders = append(corsHeaders, trimmed)
+		}
+	}
+
+	var trustedProxies []string
+	for _, p := range strings.Split(viper.GetString("TRUSTED_PROXIES"), ",") {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			trustedProxies = append(trustedProxies, trimmed)
+		}
+	}
+
+	var quotaKeywords []string
+	for _, k := range strings.Split(viper.GetString("QUOTA_ERROR_KEYWORDS"), ",") {
+		if trimmed := strings.TrimSpace(k); trimmed != "" {
+			quotaKeywords = append(quotaKeywords, trimmed)
+		}
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
 			Port:                        viper.GetString("SERVER_PORT"),
 			Mode:                        viper.GetString("GIN_MODE"),
 			CORSOrigins:                 corsOrigins,
+			CORSAllowCredentials:        viper.GetBool("CORS_ALLOW_CREDENTIALS"),
+			CORSAllowedMethods:          corsMethods,
+			CORSAllowedHeaders:          corsHeaders,
+			CORSMaxAgeSeconds:           viper.GetInt("CORS_MAX_AGE_SECONDS"),
 			PprofEnabled:                viper.GetBool("PPROF_ENABLED"),
 			MetricsAllowUnauthenticated: viper.GetBool("METRICS_ALLOW_UNAUTHENTICATED"),
 			ReadTimeoutSeconds:          viper.GetInt("SERVER_READ_TIMEOUT_SECONDS"),
 			WriteTimeoutSeconds:         viper.GetInt("SERVER_WRITE_TIMEOUT_SECONDS"),
+			StreamWriteTimeoutSeconds:   viper.GetInt("SERVER_STREAM_WRITE_TIMEOUT_SECONDS"),
 			AllowLocalProviders:         viper.GetBool("ALLOW_LOCAL_PROVIDERS"),
+			GzipEnabled:                 viper.GetBool("GZIP_ENABLED"),
+			TrustedProxies:              trustedProxies,
+			SeedDefaults:                seedDefaultsEnabled(),
 		},
 		Database: DatabaseConfig{
 			Host:                   viper.GetString("DB_HOST"),
@@ -296,6 +415,8 @@ func Load() (*Config, error) {
 			MaxOpenConns:           viper.GetInt("DB_MAX_OPEN_CONNS"),
 			MaxIdleConns:           viper.GetInt("DB_MAX_IDLE_CONNS"),
 			ConnMaxLifetimeMinutes: viper.GetInt("DB_CONN_MAX_LIFETIME_MINUTES"),
+			ConnectAttempts:        viper.GetInt("DB_CONNECT_ATTEMPTS"),
+			ConnectRetrySeconds:    viper.GetInt("DB_CONNECT_RETRY_SECONDS"),
 		},
 		Redis: RedisConfig{
 			Host:       viper.GetString("REDIS_HOST"),
@@ -322,11 +443,18 @@ func Load() (*Config, error) {
 			Timeout:          time.Duration(viper.GetInt("HEALTH_CHECK_TIMEOUT")) * time.Second,
 			RetryCount:       viper.GetInt("HEALTH_CHECK_RETRY_COUNT"),
 			FailureThreshold: viper.GetInt("HEALTH_CHECK_FAILURE_THRESHOLD"),
+
+			LatencySLAWeightFactor: viper.GetFloat64("LATENCY_SLA_WEIGHT_FACTOR"),
 		},
 		Alert: AlertConfig{
-			Enabled:      viper.GetBool("ALERT_ENABLED"),
-			WebhookURL:   viper.GetString("ALERT_WEBHOOK_URL"),
-			EmailEnabled: viper.GetBool("ALERT_EMAIL_ENABLED"),
+			Enabled:       viper.GetBool("ALERT_ENABLED"),
+			WebhookURL:    viper.GetString("ALERT_WEBHOOK_URL"),
+			WebhookSecret: viper.GetString("ALERT_WEBHOOK_SECRET"),
+			EmailEnabled:  viper.GetBool("ALERT_EMAIL_ENABLED"),
+
+			RetryMaxAttempts: viper.GetInt("ALERT_RETRY_MAX_ATTEMPTS"),
+			RetryBaseDelay:   time.Duration(viper.GetInt("ALERT_RETRY_BASE_DELAY_SECONDS")) * time.Second,
+			RetryMaxDelay:    time.Duration(viper.GetInt("ALERT_RETRY_MAX_DELAY_SECONDS")) * time.Second,
 		},
 		Email: EmailConfig{
 			Enabled:  viper.GetBool("EMAIL_ENABLED"),
@@ -348,8 +476,9 @@ func Load() (*Config, error) {
 			RequestsPerMinute: viper.GetInt("RATE_LIMIT_REQUESTS_PER_MINUTE"),
 		},
 		Log: LogConfig{
-			Level:  viper.GetString("LOG_LEVEL"),
-			Format: viper.GetString("LOG_FORMAT"),
+			Level:                viper.GetString("LOG_LEVEL"),
+			Format:               viper.GetString("LOG_FORMAT"),
+			SlowRequestThreshold: time.Duration(viper.GetInt("LOG_SLOW_REQUEST_THRESHOLD_MS")) * time.Millisecond,
 		},
 		Admin: AdminConfig{
 			Email:    viper.GetString("ADMIN_EMAIL"),
@@ -417,10 +546,48 @@ func Load() (*Config, error) {
 			SecretKey: viper.GetString("TURNSTILE_SECRET_KEY"),
 			SiteKey:   viper.GetString("TURNSTILE_SITE_KEY"),
 		},
+		Memory: MemoryConfig{
+			MaxMessages: viper.GetInt("MEMORY_MAX_MESSAGES"),
+		},
+		Shadow: ShadowConfig{
+			Provider:   strings.TrimSpace(viper.GetString("SHADOW_PROVIDER")),
+			Model:      strings.TrimSpace(viper.GetString("SHADOW_MODEL")),
+			SampleRate: viper.GetFloat64("SHADOW_SAMPLE_RATE"),
+		},
+		Router: RouterConfig{
+			QuotaKeywords:             quotaKeywords,
+			ProviderQuotaKeywords:     parseProviderKeywords(viper.GetString("QUOTA_ERROR_PROVIDER_KEYWORDS")),
+			StreamFallbackEnabled:     viper.GetBool("STREAM_FALLBACK_ENABLED"),
+			UnknownModelPolicy:        strings.ToLower(viper.GetString("UNKNOWN_MODEL_POLICY")),
+			CatchAllProvider:          viper.GetString("CATCH_ALL_PROVIDER"),
+			MaxIdleConnsPerHost:       viper.GetInt("PROVIDER_MAX_IDLE_CONNS_PER_HOST"),
+			IdleConnTimeoutSecs:       viper.GetInt("PROVIDER_IDLE_CONN_TIMEOUT_SECONDS"),
+			RequestDeadlineSecs:       viper.GetInt("REQUEST_DEADLINE_SECONDS"),
+			InjectEndUser:             viper.GetBool("INJECT_END_USER_ID"),
+			ExposeRoutingHeaders:      viper.GetBool("EXPOSE_ROUTING_HEADERS"),
+			ModelListCacheTTLSecs:     viper.GetInt("MODEL_LIST_CACHE_TTL_SECONDS"),
+			ModelListCacheRedis:       viper.GetBool("MODEL_LIST_CACHE_REDIS"),
+			FailedRequestLogPerMinute: viper.GetInt("FAILED_REQUEST_LOG_PER_MINUTE"),
+			FailedRequestCapture:      viper.GetBool("FAILED_REQUEST_CAPTURE"),
+			KeyRetryBackoffMs:         viper.GetInt("KEY_RETRY_BACKOFF_MS"),
+			KeyRetryBackoffMaxMs:      viper.GetInt("KEY_RETRY_BACKOFF_MAX_MS"),
+			KeyRetryBudgetMs:          viper.GetInt("KEY_RETRY_BUDGET_MS"),
+			KeySelection:              strings.ToLower(viper.GetString("KEY_SELECTION")),
+			MaxChatMessages:           viper.GetInt("MAX_CHAT_MESSAGES"),
+			MaxMessageChars:           viper.GetInt("MAX_MESSAGE_CHARS"),
+			ProviderMaxConcurrent:     viper.GetInt("PROVIDER_MAX_CONCURRENT"),
+			ProviderQueueDepth:        viper.GetInt("PROVIDER_QUEUE_DEPTH"),
+			ProviderQueueMaxWaitMs:    viper.GetInt("PROVIDER_QUEUE_MAX_WAIT_MS"),
+			ModelTierAliases:          parseModelTierAliases(viper.GetString("MODEL_TIER_ALIASES")),
+		},
 		Cleanup: CleanupConfig{
-			HealthRetentionDays: viper.GetInt("CLEANUP_HEALTH_RETENTION_DAYS"),
-			AlertRetentionDays:  viper.GetInt("CLEANUP_ALERT_RETENTION_DAYS"),
-			AuditRetentionDays:  viper.GetInt("CLEANUP_AUDIT_RETENTION_DAYS"),
+			HealthRetentionDays:        viper.GetInt("CLEANUP_HEALTH_RETENTION_DAYS"),
+			AlertRetentionDays:         viper.GetInt("CLEANUP_ALERT_RETENTION_DAYS"),
+			AuditRetentionDays:         viper.GetInt("CLEANUP_AUDIT_RETENTION_DAYS"),
+			FailedRequestRetentionDays: viper.GetInt("CLEANUP_FAILED_REQUEST_RETENTION_DAYS"),
+			FailedRequestCaptureDays:   viper.GetInt("CLEANUP_FAILED_REQUEST_CAPTURE_RETENTION_DAYS"),
+			ShadowLogRetentionDays:     viper.GetInt("CLEANUP_SHADOW_LOG_RETENTION_DAYS"),
+			AlertDeliveryRetentionDays: viper.GetInt("CLEANUP_ALERT_DELIVERY_RETENTION_DAYS"),
 		},
 		FeatureGates: loadFeatureGates(),
 	}
@@ -441,6 +608,8 @@ func (c *Config) Validate() error {
 	errs = append(errs, validatePort(c.Redis.Port, "REDIS_PORT")...)
 	errs = append(errs, c.validateEmail()...)
 	errs = append(errs, c.validateJWT()...)
+	errs = append(errs, c.validateTrustedProxies()...)
+	errs = append(errs, c.validateUnknownModelPolicy()...)
 
 	if c.RateLimit.Enabled && c.RateLimit.RequestsPerMinute <= 0 {
 		errs = append(errs, "RATE_LIMIT_REQUESTS_PER_MINUTE must be > 0 when rate limiting is enabled")
@@ -456,9 +625,87 @@ func (c *Config) Validate() error {
 		errs = append(errs, fmt.Sprintf("REGISTRATION_MODE %q is not valid (open|invite|closed)", c.Registration.Mode))
 	}
 
+	if c.Server.CORSMaxAgeSeconds < 0 {
+		errs = append(errs, "CORS_MAX_AGE_SECONDS must be >= 0")
+	}
+	if c.Server.StreamWriteTimeoutSeconds < 0 {
+		errs = append(errs, "SERVER_STREAM_WRITE_TIMEOUT_SECONDS must be >= 0")
+	}
+	if c.Database.ConnectAttempts < 1 {
+		errs = append(errs, "DB_CONNECT_ATTEMPTS must be >= 1")
+	}
+	if c.Database.ConnectRetrySeconds < 0 {
+		errs = append(errs, "DB_CONNECT_RETRY_SECONDS must be >= 0")
+	}
+	if c.Memory.MaxMessages < 0 {
+		errs = append(errs, "MEMORY_MAX_MESSAGES must be >= 0")
+	}
+	if c.Shadow.SampleRate < 0 || c.Shadow.SampleRate > 1 {
+		errs = append(errs, "SHADOW_SAMPLE_RATE must be between 0 and 1")
+	}
+	if c.Router.MaxIdleConnsPerHost < 0 {
+		errs = append(errs, "PROVIDER_MAX_IDLE_CONNS_PER_HOST must be >= 0")
+	}
+	if c.Router.IdleConnTimeoutSecs < 0 {
+		errs = append(errs, "PROVIDER_IDLE_CONN_TIMEOUT_SECONDS must be >= 0")
+	}
+	if c.Router.RequestDeadlineSecs < 0 {
+		errs = append(errs, "REQUEST_DEADLINE_SECONDS must be >= 0")
+	}
+	if c.Router.ModelListCacheTTLSecs < 0 {
+		errs = append(errs, "MODEL_LIST_CACHE_TTL_SECONDS must be >= 0")
+	}
+	if c.Router.FailedRequestLogPerMinute < 0 {
+		errs = append(errs, "FAILED_REQUEST_LOG_PER_MINUTE must be >= 0")
+	}
+	if c.Router.MaxChatMessages < 0 {
+		errs = append(errs, "MAX_CHAT_MESSAGES must be >= 0")
+	}
+	if c.Router.MaxMessageChars < 0 {
+		errs = append(errs, "MAX_MESSAGE_CHARS must be >= 0")
+	}
+	if c.Router.KeyRetryBackoffMs < 0 {
+		errs = append(errs, "KEY_RETRY_BACKOFF_MS must be >= 0")
+	}
+	if c.Router.KeyRetryBackoffMaxMs < c.Router.KeyRetryBackoffMs {
+		errs = append(errs, "KEY_RETRY_BACKOFF_MAX_MS must be >= KEY_RETRY_BACKOFF_MS")
+	}
+	if c.Router.KeyRetryBudgetMs < 0 {
+		errs = append(errs, "KEY_RETRY_BUDGET_MS must be >= 0")
+	}
+	switch c.Router.KeySelection {
+	case "", "weighted", "least_used":
+	default:
+		errs = append(errs, fmt.Sprintf("KEY_SELECTION %q is not valid (weighted|least_used)", c.Router.KeySelection))
+	}
+	if c.Router.ProviderMaxConcurrent < 0 {
+		errs = append(errs, "PROVIDER_MAX_CONCURRENT must be >= 0")
+	}
+	if c.Router.ProviderQueueDepth < 0 {
+		errs = append(errs, "PROVIDER_QUEUE_DEPTH must be >= 0")
+	}
+	if c.Router.ProviderQueueMaxWaitMs < 0 {
+		errs = append(errs, "PROVIDER_QUEUE_MAX_WAIT_MS must be >= 0")
+	}
+	if c.Cleanup.FailedRequestRetentionDays < 1 {
+		errs = append(errs, "CLEANUP_FAILED_REQUEST_RETENTION_DAYS must be >= 1")
+	}
+	if c.Cleanup.FailedRequestCaptureDays < 1 {
+		errs = append(errs, "CLEANUP_FAILED_REQUEST_CAPTURE_RETENTION_DAYS must be >= 1")
+	}
+	if c.Cleanup.ShadowLogRetentionDays < 1 {
+		errs = append(errs, "CLEANUP_SHADOW_LOG_RETENTION_DAYS must be >= 1")
+	}
+	if c.Cleanup.AlertDeliveryRetentionDays < 1 {
+		errs = append(errs, "CLEANUP_ALERT_DELIVERY_RETENTION_DAYS must be >= 1")
+	}
+
 	if c.HealthCheck.Enabled && c.HealthCheck.Interval < 5*time.Second {
 		errs = append(errs, "HEALTH_CHECK_INTERVAL must be at least 5 seconds")
 	}
+	if c.HealthCheck.LatencySLAWeightFactor < 0 || c.HealthCheck.LatencySLAWeightFactor > 1 {
+		errs = append(errs, "LATENCY_SLA_WEIGHT_FACTOR must be between 0 and 1")
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -505,19 +752,112 @@ func (c *Config) validateJWT() []string {
 	return errs
 }
 
+// validateTrustedProxies returns validation errors for the trusted-proxy settings.
+func (c *Config) validateTrustedProxies() []string {
+	var errs []string
+	for _, p := range c.Server.TrustedProxies {
+		if net.ParseIP(p) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(p); err != nil {
+			errs = append(errs, fmt.Sprintf("TRUSTED_PROXIES entry %q is not a valid IP or CIDR", p))
+		}
+	}
+	return errs
+}
+
+// validateUnknownModelPolicy returns validation errors for the unknown-model routing policy.
+func (c *Config) validateUnknownModelPolicy() []string {
+	switch c.Router.UnknownModelPolicy {
+	case "", "strategy", "reject":
+		return nil
+	case "catch_all":
+		if strings.TrimSpace(c.Router.CatchAllProvider) == "" {
+			return []string{"CATCH_ALL_PROVIDER is required when UNKNOWN_MODEL_POLICY is catch_all"}
+		}
+		return nil
+	default:
+		return []string{fmt.Sprintf("UNKNOWN_MODEL_POLICY %q is not valid (strategy|reject|catch_all)", c.Router.UnknownModelPolicy)}
+	}
+}
+
+// parseProviderKeywords parses "provider=kw1|kw2;provider2=kw3" into a map of
+// provider name to keywords. Malformed entries are skipped.
+func parseProviderKeywords(raw string) map[string][]string {
+	out := make(map[string][]string)
+	for _, entry := range strings.Split(raw, ";") {
+		name, list, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		for _, k := range strings.Split(list, "|") {
+			if trimmed := strings.TrimSpace(k); trimmed != "" {
+				out[name] = append(out[name], trimmed)
+			}
+		}
+	}
+	return out
+}
+
+// parseModelTierAliases parses "fast=model-a,smart=model-b" into a map of
+// tier alias to model. Malformed entries are skipped.
+func parseModelTierAliases(raw string) map[string]string {
+	out := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		tier, model, ok := strings.Cut(entry, "=")
+		tier, model = strings.TrimSpace(tier), strings.TrimSpace(model)
+		if !ok || tier == "" || model == "" {
+			continue
+		}
+		out[tier] = model
+	}
+	return out
+}
+
 // setDefaults sets default values for configuration.
 func setDefaults() {
 	viper.SetDefault("SERVER_PORT", "8080")
 	viper.SetDefault("SERVER_READ_TIMEOUT_SECONDS", 30)
-	viper.SetDefault("SERVER_WRITE_TIMEOUT_SECONDS", 600) // Large to support LLM streaming
+	viper.SetDefault("SERVER_WRITE_TIMEOUT_SECONDS", 600)       // Non-streaming completions can still take minutes
+	viper.SetDefault("SERVER_STREAM_WRITE_TIMEOUT_SECONDS", 0) // SSE responses have no write deadline by default
 	viper.SetDefault("GIN_MODE", "release")
+	viper.SetDefault("GZIP_ENABLED", false)
+	viper.SetDefault("STREAM_FALLBACK_ENABLED", false)
+	viper.SetDefault("UNKNOWN_MODEL_POLICY", "strategy")
+	viper.SetDefault("CATCH_ALL_PROVIDER", "")
+	viper.SetDefault("PROVIDER_MAX_IDLE_CONNS_PER_HOST", 32)
+	viper.SetDefault("PROVIDER_IDLE_CONN_TIMEOUT_SECONDS", 90)
+	viper.SetDefault("REQUEST_DEADLINE_SECONDS", 600) // Matches SERVER_WRITE_TIMEOUT_SECONDS
+	viper.SetDefault("INJECT_END_USER_ID", true)
+	viper.SetDefault("EXPOSE_ROUTING_HEADERS", false)
+	viper.SetDefault("MAX_CHAT_MESSAGES", 1000)
+	viper.SetDefault("MAX_MESSAGE_CHARS", 1000000)
+	viper.SetDefault("MODEL_LIST_CACHE_TTL_SECONDS", 300)
+	viper.SetDefault("MODEL_LIST_CACHE_REDIS", true)
+	viper.SetDefault("FAILED_REQUEST_LOG_PER_MINUTE", 60)
+	viper.SetDefault("KEY_RETRY_BACKOFF_MS", 200)
+	viper.SetDefault("KEY_RETRY_BACKOFF_MAX_MS", 2000)
+	viper.SetDefault("KEY_RETRY_BUDGET_MS", 5000)
+	viper.SetDefault("KEY_SELECTION", "weighted")
+	viper.SetDefault("PROVIDER_MAX_CONCURRENT", 0)
+	viper.SetDefault("PROVIDER_QUEUE_DEPTH", 100)
+	viper.SetDefault("PROVIDER_QUEUE_MAX_WAIT_MS", 5000)
+	viper.SetDefault("MODEL_TIER_ALIASES", "fast=gpt-3.5-turbo,balanced=gpt-4-turbo,smart=gpt-4")
+	viper.SetDefault("TRUSTED_PROXIES", "") // Empty = trust no proxy headers; ClientIP is the TCP peer
 	viper.SetDefault("CORS_ORIGINS", "") // Empty = deny by default in production; set to "*" or specific origins
+	viper.SetDefault("CORS_ALLOW_CREDENTIALS", true)
+	viper.SetDefault("CORS_ALLOWED_METHODS", "") // Empty = GET, POST, OPTIONS
+	viper.SetDefault("CORS_ALLOWED_HEADERS", "") // Empty = built-in list incl. X-Request-ID and Idempotency-Key
+	viper.SetDefault("CORS_MAX_AGE_SECONDS", 86400)
 	viper.SetDefault("DB_HOST", "localhost")
 	viper.SetDefault("DB_PORT", "5432")
 	viper.SetDefault("DB_SSL_MODE", "require") // Production default; override to "disable" for local dev
 	viper.SetDefault("DB_MAX_OPEN_CONNS", 100)
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
 	viper.SetDefault("DB_CONN_MAX_LIFETIME_MINUTES", 60)
+	viper.SetDefault("DB_CONNECT_ATTEMPTS", 10)
+	viper.SetDefault("DB_CONNECT_RETRY_SECONDS", 3)
 	viper.SetDefault("REDIS_HOST", "localhost")
 	viper.SetDefault("REDIS_PORT", "6379")
 	viper.SetDefault("REDIS_DB", 0)
@@ -535,11 +875,16 @@ func setDefaults() {
 	viper.SetDefault("HEALTH_CHECK_TIMEOUT", 10)
 	viper.SetDefault("HEALTH_CHECK_RETRY_COUNT", 3)
 	viper.SetDefault("HEALTH_CHECK_FAILURE_THRESHOLD", 3)
+	viper.SetDefault("LATENCY_SLA_WEIGHT_FACTOR", 1.0)
+	viper.SetDefault("ALERT_RETRY_MAX_ATTEMPTS", 8)
+	viper.SetDefault("ALERT_RETRY_BASE_DELAY_SECONDS", 30)
+	viper.SetDefault("ALERT_RETRY_MAX_DELAY_SECONDS", 1800)
 	viper.SetDefault("JWT_EXPIRES_IN", "1h") // Short-lived access tokens; use refresh tokens for renewal
 	viper.SetDefault("JWT_REFRESH_EXPIRES_IN", "168h") // 7 days
 	viper.SetDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 60)
 	viper.SetDefault("LOG_LEVEL", "info")
 	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("LOG_SLOW_REQUEST_THRESHOLD_MS", 30000) // Above typical non-streaming completion latency
 	viper.SetDefault("ADMIN_NAME", "Administrator")
 	viper.SetDefault("ADMIN_IP_WHITELIST", "")      // Empty = deny by default in strict mode, or open if explicitly handled
 	viper.SetDefault("REGISTRATION_MODE", "open") // open by default; set to "invite" or "closed" as needed
@@ -547,6 +892,10 @@ func setDefaults() {
 	viper.SetDefault("CLEANUP_HEALTH_RETENTION_DAYS", 30)
 	viper.SetDefault("CLEANUP_ALERT_RETENTION_DAYS", 90)
 	viper.SetDefault("CLEANUP_AUDIT_RETENTION_DAYS", 90)
+	viper.SetDefault("CLEANUP_FAILED_REQUEST_RETENTION_DAYS", 14)
+	viper.SetDefault("CLEANUP_FAILED_REQUEST_CAPTURE_RETENTION_DAYS", 3)
+	viper.SetDefault("CLEANUP_SHADOW_LOG_RETENTION_DAYS", 7)
+	viper.SetDefault("CLEANUP_ALERT_DELIVERY_RETENTION_DAYS", 30)
 	viper.SetDefault("LANGFUSE_ENABLED", false)
 	viper.SetDefault("LANGFUSE_HOST", "https://cloud.langfuse.com")
 	viper.SetDefault("SENTRY_ENABLED", false)
@@ -560,6 +909,8 @@ func setDefaults() {
 	viper.SetDefault("OTEL_ENDPOINT", "")
 	viper.SetDefault("OTEL_SERVICE_NAME", "llm-router-platform")
 	viper.SetDefault("TURNSTILE_ENABLED", false)
+	viper.SetDefault("MEMORY_MAX_MESSAGES", 200)
+	viper.SetDefault("SHADOW_SAMPLE_RATE", 0.0)
 	viper.SetDefault("CACHE_HIT_COST_RATIO", 0.1) // Cache hits billed at 10% of model price
 	viper.SetDefault("LOKI_URL", "")              // Empty disables Loki querying
 }
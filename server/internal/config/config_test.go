@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -140,3 +141,53 @@ func TestFullConfig(t *testing.T) {
 	assert.Equal(t, "localhost", cfg.Redis.Host)
 	assert.Equal(t, "test-secret", cfg.JWT.Secret)
 }
+
+func TestParseProviderKeywords(t *testing.T) {
+	got := parseProviderKeywords(" azure = capacity exceeded | server busy ;gemini=overloaded;bogus;=x")
+
+	assert.Equal(t, map[string][]string{
+		"azure":  {"capacity exceeded", "server busy"},
+		"gemini": {"overloaded"},
+	}, got)
+	assert.Empty(t, parseProviderKeywords(""))
+}
+
+func TestValidateTrustedProxies(t *testing.T) {
+	cfg := &Config{Server: ServerConfig{TrustedProxies: []string{"10.0.0.0/8", "192.168.1.10", "::1"}}}
+	assert.Empty(t, cfg.validateTrustedProxies())
+
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/40", "lb.internal"}
+	assert.Len(t, cfg.validateTrustedProxies(), 2)
+}
+
+func TestValidateUnknownModelPolicy(t *testing.T) {
+	cfg := &Config{}
+	assert.Empty(t, cfg.validateUnknownModelPolicy())
+
+	cfg.Router.UnknownModelPolicy = "reject"
+	assert.Empty(t, cfg.validateUnknownModelPolicy())
+
+	cfg.Router.UnknownModelPolicy = "catch_all"
+	assert.Len(t, cfg.validateUnknownModelPolicy(), 1)
+	cfg.Router.CatchAllProvider = "openrouter"
+	assert.Empty(t, cfg.validateUnknownModelPolicy())
+
+	cfg.Router.UnknownModelPolicy = "random"
+	assert.Len(t, cfg.validateUnknownModelPolicy(), 1)
+}
+
+func TestSeedDefaultsEnabled(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	viper.Reset()
+	viper.Set("GIN_MODE", "debug")
+	assert.True(t, seedDefaultsEnabled(), "development seeds by default")
+	viper.Set("SEED_DEFAULTS", false)
+	assert.False(t, seedDefaultsEnabled())
+
+	viper.Reset()
+	viper.Set("GIN_MODE", "release")
+	assert.False(t, seedDefaultsEnabled(), "release mode does not seed by default")
+	viper.Set("SEED_DEFAULTS", "true")
+	assert.True(t, seedDefaultsEnabled())
+}
@@ -76,6 +76,16 @@ func InitializeVault(localKey string, vaultAddr, vaultToken, transitKey string)
 	return nil
 }
 
+// NewEncryptor returns a standalone AES-256-GCM encryptor for key, independent
+// of the default encryptor. It is used to read secrets written under a
+// previous ENCRYPTION_KEY during key rotation.
+func NewEncryptor(key string) (*Encryptor, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	return &Encryptor{key: []byte(key)}, nil
+}
+
 // MustInitialize calls Initialize and panics on error.
 // Use at application startup to guarantee encryption is available.
 func MustInitialize(key string) {
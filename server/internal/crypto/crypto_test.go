@@ -136,3 +136,26 @@ func TestHMACHash(t *testing.T) {
 		t.Error("Different inputs produced same HMAC hash")
 	}
 }
+
+func TestNewEncryptorIsIndependentOfDefault(t *testing.T) {
+	if _, err := NewEncryptor("short-key"); err != ErrInvalidKey {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+
+	oldEnc, err := NewEncryptor("old-key-old-key-old-key-old-key!")
+	if err != nil {
+		t.Fatalf("NewEncryptor failed: %v", err)
+	}
+	newEnc, _ := NewEncryptor("new-key-new-key-new-key-new-key!")
+
+	ciphertext, err := oldEnc.Encrypt("sk-secret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := newEnc.Decrypt(ciphertext); err == nil {
+		t.Fatal("ciphertext from one key must not decrypt with another")
+	}
+	if got, _ := oldEnc.Decrypt(ciphertext); got != "sk-secret" {
+		t.Fatalf("Decrypt mismatch: got %q", got)
+	}
+}
@@ -2,16 +2,21 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"llm-router-platform/internal/config"
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
 	"llm-router-platform/pkg/sanitize"
 
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -54,6 +59,41 @@ func New(cfg *config.DatabaseConfig, serverMode string, log *zap.Logger) (*Datab
 	}, nil
 }
 
+// Connect opens the database like New, retrying up to cfg.ConnectAttempts
+// times so a database that is still starting (common in container boots)
+// does not fail startup. Each failed attempt is logged; the last error is
+// returned once the attempts are exhausted or ctx is cancelled.
+func Connect(ctx context.Context, cfg *config.DatabaseConfig, serverMode string, log *zap.Logger) (*Database, error) {
+	attempts := max(cfg.ConnectAttempts, 1)
+	delay := time.Duration(cfg.ConnectRetrySeconds) * time.Second
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		db, err := New(cfg, serverMode, log)
+		if err == nil {
+			if attempt > 1 {
+				log.Info("database connected", zap.Int("attempt", attempt))
+			}
+			return db, nil
+		}
+		lastErr = err
+		if attempt == attempts {
+			break
+		}
+		log.Warn("database not ready, retrying",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("retry_in", delay),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return nil, fmt.Errorf("database unreachable after %d attempts: %w", attempts, lastErr)
+}
+
 // Migrate runs database migrations.
 func (d *Database) Migrate() error {
 	// Ensure required PostgreSQL extensions are loaded before AutoMigrate
@@ -79,9 +119,13 @@ func (d *Database) Migrate() error {
 		&models.HealthHistory{},
 		&models.Alert{},
 		&models.AlertConfig{},
+		&models.AlertDelivery{},
 		&models.ConversationMemory{},
 		&models.AuditLog{},
 		&models.Budget{},
+		&models.APIKeySpendAlert{},
+		&models.ShadowLog{},
+		&models.FailedRequest{},
 		&models.AsyncTask{},
 		&models.InviteCode{},
 		&models.MCPServer{},
@@ -100,6 +144,8 @@ func (d *Database) Migrate() error {
 		&models.ErrorLog{},
 		&models.IntegrationConfig{},
 		&models.RoutingRule{},
+		&models.FallbackChain{},
+		&models.ModelRouteOverride{},
 		&models.SemanticCache{},
 		&models.IdentityProvider{},
 		&models.WebhookEndpoint{},
@@ -112,6 +158,9 @@ func (d *Database) Migrate() error {
 	); err != nil {
 		return err
 	}
+	if err := backfillProviderTypes(d.DB); err != nil {
+		return err
+	}
 
 	// Create HNSW index on SemanticCache.embedding via raw SQL
 	// (GORM cannot generate valid USING hnsw syntax)
@@ -119,9 +168,36 @@ func (d *Database) Migrate() error {
 		d.logger.Warn("could not create HNSW index on semantic_caches (pgvector may be unavailable, semantic caching will be disabled)", zap.Error(err))
 	}
 
+	// Partial expression index from migration 000030, which GORM tags cannot
+	// express. Conversation memory relies on it to retry concurrent appends
+	// that picked the same sequence number.
+	if err := d.DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_conversation_memories_sequence
+		ON conversation_memories (project_id, conversation_id, COALESCE(api_key_id, '00000000-0000-0000-0000-000000000000'::uuid), sequence)
+		WHERE deleted_at IS NULL`).Error; err != nil {
+		return fmt.Errorf("create conversation memory sequence index (run migration 000030 to renumber duplicate sequences): %w", err)
+	}
+
 	return nil
 }
 
+// builtinProviderTypes are the provider types a provider created before the
+// type column existed may be named after.
+var builtinProviderTypes = []string{"openai", "anthropic", "google", "ollama", "lmstudio", "deepseek", "mistral", "vllm"}
+
+// backfillProviderTypes gives providers without a type the one migration
+// 000032 assigns, for schemas that AutoMigrate created or extended: the
+// built-in type they are named after, otherwise openai-compatible.
+func backfillProviderTypes(db *gorm.DB) error {
+	if err := db.Model(&models.Provider{}).
+		Where("type = '' AND name IN ?", builtinProviderTypes).
+		UpdateColumn("type", gorm.Expr("name")).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.Provider{}).
+		Where("type = ''").
+		UpdateColumn("type", "openai-compatible").Error
+}
+
 // Close closes the database connection.
 func (d *Database) Close() error {
 	sqlDB, err := d.DB.DB()
@@ -167,6 +243,17 @@ func (d *Database) SeedDefaultProviders() error {
 			UseProxy:       false,
 			RequiresAPIKey: true,
 		},
+		{
+			Name:           "mistral",
+			BaseURL:        "https://api.mistral.ai",
+			IsActive:       false,
+			Priority:       8,
+			Weight:         1.0,
+			MaxRetries:     3,
+			Timeout:        30,
+			UseProxy:       false,
+			RequiresAPIKey: true,
+		},
 		{
 			Name:           "ollama",
 			BaseURL:        "http://host.docker.internal:11434/v1",
@@ -202,13 +289,21 @@ func (d *Database) SeedDefaultProviders() error {
 		},
 	}
 
+	// ON CONFLICT DO NOTHING keeps seeding idempotent when several replicas
+	// start at once; existing providers are left untouched.
 	for _, provider := range providers {
-		var existing models.Provider
-		result := d.DB.Where("name = ?", provider.Name).First(&existing)
-		if result.Error != nil {
-			if err := d.DB.Create(&provider).Error; err != nil {
-				d.logger.Error("failed to seed provider", zap.String("name", provider.Name), zap.Error(err))
-			}
+		provider.Type = provider.Name // every seed is named after its client type
+		// A keyless seed such as mistral must not be created active and
+		// routable, so its false flags are written after the INSERT.
+		err := repository.CreateKeepingFalse(d.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoNothing: true,
+		}), &provider, map[string]bool{
+			"is_active":        provider.IsActive,
+			"requires_api_key": provider.RequiresAPIKey,
+		})
+		if err != nil {
+			d.logger.Error("failed to seed provider", zap.String("name", provider.Name), zap.Error(err))
 		}
 	}
 
@@ -276,10 +371,13 @@ func (d *Database) SeedDefaultAdminOnly(cfg *config.AdminConfig) error {
 	}
 
 	var existing models.User
-	if d.DB.Where("email = ?", cfg.Email).First(&existing).Error == nil {
+	err := d.DB.Where("email = ?", cfg.Email).First(&existing).Error
+	if err == nil {
 		d.logger.Info("admin user already exists, skipping seed (release mode)", zap.String("email", sanitize.MaskEmail(cfg.Email)))
-		d.ensureDefaultOrgProject(&existing)
-		return nil
+		return d.ensureDefaultOrgProject(&existing)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to look up admin user: %w", err)
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(cfg.Password), 12) // Unified bcrypt cost (matches user.Service)
@@ -303,9 +401,8 @@ func (d *Database) SeedDefaultAdminOnly(cfg *config.AdminConfig) error {
 		return err
 	}
 
-	d.ensureDefaultOrgProject(admin)
 	d.logger.Info("default admin user created (release mode)", zap.String("email", sanitize.MaskEmail(cfg.Email)))
-	return nil
+	return d.ensureDefaultOrgProject(admin)
 }
 
 // SeedDefaultAdmin creates a default admin user if configured.
@@ -317,6 +414,9 @@ func (d *Database) SeedDefaultAdmin(cfg *config.AdminConfig) error {
 
 	var existing models.User
 	result := d.DB.Where("email = ?", cfg.Email).First(&existing)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to look up admin user: %w", result.Error)
+	}
 	if result.Error == nil {
 		// Admin exists — verify password matches env config and sync if needed
 		if err := bcrypt.CompareHashAndPassword([]byte(existing.PasswordHash), []byte(cfg.Password)); err != nil {
@@ -336,8 +436,7 @@ func (d *Database) SeedDefaultAdmin(cfg *config.AdminConfig) error {
 			d.logger.Info("admin user already exists, password matches", zap.String("email", sanitize.MaskEmail(cfg.Email)))
 		}
 		// Back-fill org+project if missing (admin was created before this logic existed)
-		d.ensureDefaultOrgProject(&existing)
-		return nil
+		return d.ensureDefaultOrgProject(&existing)
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(cfg.Password), 12) // Unified bcrypt cost (matches user.Service)
@@ -361,20 +460,22 @@ func (d *Database) SeedDefaultAdmin(cfg *config.AdminConfig) error {
 		return err
 	}
 
-	// Auto-create default org+project for new admin (same as register flow)
-	d.ensureDefaultOrgProject(admin)
-
 	d.logger.Info("default admin user created", zap.String("email", sanitize.MaskEmail(cfg.Email)))
-	return nil
+
+	// Auto-create default org+project for new admin (same as register flow)
+	return d.ensureDefaultOrgProject(admin)
 }
 
 // ensureDefaultOrgProject creates a default Organization, Project, and membership
-// for a user if they don't already belong to any organization.
-func (d *Database) ensureDefaultOrgProject(user *models.User) {
+// for a user if they don't already belong to any organization. The records
+// are created in one transaction so a failed seed does not leave a partial org.
+func (d *Database) ensureDefaultOrgProject(user *models.User) error {
 	var count int64
-	d.DB.Model(&models.OrganizationMember{}).Where("user_id = ?", user.ID).Count(&count)
+	if err := d.DB.Model(&models.OrganizationMember{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check admin org membership: %w", err)
+	}
 	if count > 0 {
-		return // user already has an org
+		return nil // user already has an org
 	}
 
 	orgName := "Default Org"
@@ -383,22 +484,35 @@ func (d *Database) ensureDefaultOrgProject(user *models.User) {
 	}
 
 	org := models.Organization{Name: orgName, OwnerID: user.ID}
-	if err := d.DB.Create(&org).Error; err != nil {
-		d.logger.Error("failed to create default org for admin", zap.Error(err))
-		return
-	}
-
-	d.DB.Create(&models.OrganizationMember{OrgID: org.ID, UserID: user.ID, Role: "OWNER"})
-	d.DB.Create(&models.Project{OrgID: org.ID, Name: "Default", Description: "Auto-created project"})
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&org).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.OrganizationMember{OrgID: org.ID, UserID: user.ID, Role: "OWNER"}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.Project{OrgID: org.ID, Name: "Default", Description: "Auto-created project"}).Error; err != nil {
+			return err
+		}
 
-	// Grant welcome credit
-	if user.Balance == 0 {
-		user.Balance = 5.0
-		d.DB.Model(user).UpdateColumn("balance", 5.0)
-		d.DB.Create(&models.Transaction{OrgID: org.ID, UserID: user.ID, Type: "recharge", Amount: 5.0, Balance: 5.0, Description: "Welcome credit", Currency: "USD"})
+		// Grant welcome credit
+		if user.Balance == 0 {
+			if err := tx.Model(user).UpdateColumn("balance", 5.0).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.Transaction{OrgID: org.ID, UserID: user.ID, Type: "recharge", Amount: 5.0, Balance: 5.0, Description: "Welcome credit", Currency: "USD"}).Error; err != nil {
+				return err
+			}
+			user.Balance = 5.0
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create default org for admin: %w", err)
 	}
 
 	d.logger.Info("created default org+project for user", zap.String("email", sanitize.MaskEmail(user.Email)), zap.String("orgId", org.ID.String()))
+	return nil
 }
 
 // CleanupOldHealthHistory removes health history records older than the specified duration.
@@ -1,9 +1,14 @@
 package database
 
 import (
+	"context"
 	"testing"
 
+	"llm-router-platform/internal/config"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestDSNBuilding(t *testing.T) {
@@ -151,3 +156,17 @@ func TestSSLModeValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestConnectGivesUpAfterAttempts(t *testing.T) {
+	cfg := &config.DatabaseConfig{
+		Host:            "127.0.0.1",
+		Port:            "1", // nothing listens here
+		User:            "postgres",
+		Name:            "llm_router",
+		SSLMode:         "disable",
+		ConnectAttempts: 2,
+	}
+	_, err := Connect(context.Background(), cfg, "release", zap.NewNop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 2 attempts")
+}
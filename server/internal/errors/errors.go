@@ -35,6 +35,33 @@ const (
 	
 	// ErrCodeProviderQuotaExceeded indicates the upstream proxy provider (e.g. OpenAI) threw a 429 quota error.
 	ErrCodeProviderQuotaExceeded ErrorCode = "LLM_ROUTER_ERR_009"
+
+	// ErrCodeProviderNotFound indicates an explicitly requested provider does not exist or is inactive.
+	ErrCodeProviderNotFound ErrorCode = "LLM_ROUTER_ERR_010"
+
+	// ErrCodeModelNotSupported indicates no provider serves the requested model and the
+	// unknown-model policy rejects it rather than guessing a provider.
+	ErrCodeModelNotSupported ErrorCode = "LLM_ROUTER_ERR_011"
+
+	// ErrCodeRequestCostExceeded indicates the worst-case cost of a request exceeds the
+	// API key's per-request cost limit.
+	ErrCodeRequestCostExceeded ErrorCode = "LLM_ROUTER_ERR_012"
+
+	// ErrCodeUnsupportedParameter indicates the routed provider cannot honor a request
+	// parameter, such as a structured response_format.
+	ErrCodeUnsupportedParameter ErrorCode = "LLM_ROUTER_ERR_013"
+
+	// ErrCodeIdempotencyConflict indicates an Idempotency-Key is still in flight or was
+	// reused with a different request body.
+	ErrCodeIdempotencyConflict ErrorCode = "LLM_ROUTER_ERR_014"
+
+	// ErrCodeProviderSaturated indicates the routed provider's request queue is full or
+	// the request waited too long for a free slot.
+	ErrCodeProviderSaturated ErrorCode = "LLM_ROUTER_ERR_015"
+
+	// ErrCodeBudgetExceeded indicates a stream was cut off because its estimated cost
+	// reached the API key's per-request limit or the organization's remaining budget.
+	ErrCodeBudgetExceeded ErrorCode = "LLM_ROUTER_ERR_016"
 )
 
 // RouterError implements the built-in error interface while carrying machine-readable dimensions.
@@ -39,28 +39,29 @@ type DirectiveRoot struct {
 
 type ComplexityRoot struct {
 	AdminDashboard struct {
-		APIKeysHealthy   func(childComplexity int) int
-		APIKeysTotal     func(childComplexity int) int
-		ActiveProviders  func(childComplexity int) int
-		ActiveProxies    func(childComplexity int) int
-		ActiveUsersMonth func(childComplexity int) int
-		ActiveUsersToday func(childComplexity int) int
-		AvgLatencyMs     func(childComplexity int) int
-		CostToday        func(childComplexity int) int
-		ErrorCount       func(childComplexity int) int
-		McpCallCount     func(childComplexity int) int
-		McpErrorCount    func(childComplexity int) int
-		RequestsToday    func(childComplexity int) int
-		RevenueThisMonth func(childComplexity int) int
-		SuccessRate      func(childComplexity int) int
-		TokensToday      func(childComplexity int) int
-		TotalCost        func(childComplexity int) int
-		TotalProviders   func(childComplexity int) int
-		TotalProxies     func(childComplexity int) int
-		TotalRequests    func(childComplexity int) int
-		TotalRevenue     func(childComplexity int) int
-		TotalTokens      func(childComplexity int) int
-		TotalUsers       func(childComplexity int) int
+		APIKeysHealthy         func(childComplexity int) int
+		APIKeysTotal           func(childComplexity int) int
+		ActiveProviders        func(childComplexity int) int
+		ActiveProxies          func(childComplexity int) int
+		ActiveUsersMonth       func(childComplexity int) int
+		ActiveUsersToday       func(childComplexity int) int
+		AvgLatencyMs           func(childComplexity int) int
+		CostToday              func(childComplexity int) int
+		ErrorCount             func(childComplexity int) int
+		McpCallCount           func(childComplexity int) int
+		McpErrorCount          func(childComplexity int) int
+		MisconfiguredProviders func(childComplexity int) int
+		RequestsToday          func(childComplexity int) int
+		RevenueThisMonth       func(childComplexity int) int
+		SuccessRate            func(childComplexity int) int
+		TokensToday            func(childComplexity int) int
+		TotalCost              func(childComplexity int) int
+		TotalProviders         func(childComplexity int) int
+		TotalProxies           func(childComplexity int) int
+		TotalRequests          func(childComplexity int) int
+		TotalRevenue           func(childComplexity int) int
+		TotalTokens            func(childComplexity int) int
+		TotalUsers             func(childComplexity int) int
 	}
 
 	AdminUsageByUser struct {
@@ -123,19 +124,26 @@ type ComplexityRoot struct {
 	}
 
 	ApiKey struct {
-		Channel    func(childComplexity int) int
-		CreatedAt  func(childComplexity int) int
-		DailyLimit func(childComplexity int) int
-		ExpiresAt  func(childComplexity int) int
-		ID         func(childComplexity int) int
-		IsActive   func(childComplexity int) int
-		KeyPrefix  func(childComplexity int) int
-		LastUsedAt func(childComplexity int) int
-		Name       func(childComplexity int) int
-		ProjectID  func(childComplexity int) int
-		RateLimit  func(childComplexity int) int
-		Scopes     func(childComplexity int) int
-		TokenLimit func(childComplexity int) int
+		AllowedCidrs       func(childComplexity int) int
+		Channel            func(childComplexity int) int
+		CreatedAt          func(childComplexity int) int
+		DailyLimit         func(childComplexity int) int
+		ExpiresAt          func(childComplexity int) int
+		ID                 func(childComplexity int) int
+		IsActive           func(childComplexity int) int
+		KeyPrefix          func(childComplexity int) int
+		LastUsedAt         func(childComplexity int) int
+		MaxMessageChars    func(childComplexity int) int
+		MaxMessages        func(childComplexity int) int
+		MaxRequestCostUsd  func(childComplexity int) int
+		Name               func(childComplexity int) int
+		ProjectID          func(childComplexity int) int
+		RateLimit          func(childComplexity int) int
+		Scopes             func(childComplexity int) int
+		SpendThresholds    func(childComplexity int) int
+		SpendWebhookURL    func(childComplexity int) int
+		StreamBudgetCutoff func(childComplexity int) int
+		TokenLimit         func(childComplexity int) int
 	}
 
 	ApiKeyHealth struct {
@@ -385,6 +393,18 @@ type ComplexityRoot struct {
 		Total    func(childComplexity int) int
 	}
 
+	FallbackChain struct {
+		CreatedAt    func(childComplexity int) int
+		Description  func(childComplexity int) int
+		ID           func(childComplexity int) int
+		IsEnabled    func(childComplexity int) int
+		ModelPattern func(childComplexity int) int
+		Name         func(childComplexity int) int
+		Priority     func(childComplexity int) int
+		ProviderIds  func(childComplexity int) int
+		UpdatedAt    func(childComplexity int) int
+	}
+
 	FeatureGate struct {
 		Category    func(childComplexity int) int
 		Description func(childComplexity int) int
@@ -399,6 +419,7 @@ type ComplexityRoot struct {
 	}
 
 	HealthEvent struct {
+		CheckMode  func(childComplexity int) int
 		CreatedAt  func(childComplexity int) int
 		ID         func(childComplexity int) int
 		Message    func(childComplexity int) int
@@ -501,6 +522,12 @@ type ComplexityRoot struct {
 		Secret      func(childComplexity int) int
 	}
 
+	MisconfiguredProvider struct {
+		ID    func(childComplexity int) int
+		Issue func(childComplexity int) int
+		Name  func(childComplexity int) int
+	}
+
 	Model struct {
 		CreatedAt        func(childComplexity int) int
 		DisplayName      func(childComplexity int) int
@@ -542,6 +569,7 @@ type ComplexityRoot struct {
 		CreateAnnouncement           func(childComplexity int, input model.AnnouncementInput) int
 		CreateCoupon                 func(childComplexity int, input model.CouponInput) int
 		CreateDocument               func(childComplexity int, input model.DocumentInput) int
+		CreateFallbackChain          func(childComplexity int, input model.CreateFallbackChainInput) int
 		CreateIdentityProvider       func(childComplexity int, input model.CreateIdentityProviderInput) int
 		CreateInviteCode             func(childComplexity int, input model.InviteCodeInput) int
 		CreateMcpServer              func(childComplexity int, input model.McpServerInput) int
@@ -562,6 +590,7 @@ type ComplexityRoot struct {
 		DeleteBudget                 func(childComplexity int) int
 		DeleteCoupon                 func(childComplexity int, id string) int
 		DeleteDocument               func(childComplexity int, id string) int
+		DeleteFallbackChain          func(childComplexity int, id string) int
 		DeleteIdentityProvider       func(childComplexity int, id string) int
 		DeleteMcpServer              func(childComplexity int, id string) int
 		DeleteModel                  func(childComplexity int, id string) int
@@ -592,6 +621,11 @@ type ComplexityRoot struct {
 		RevokeRedeemCode             func(childComplexity int, id string) int
 		RotateRefreshToken           func(childComplexity int, refreshToken string) int
 		SendTestEmail                func(childComplexity int, to string) int
+		SetAPIKeyAllowedCidrs        func(childComplexity int, id string, cidrs []string) int
+		SetAPIKeyMaxRequestCost      func(childComplexity int, id string, maxCostUsd float64) int
+		SetAPIKeyMessageLimits       func(childComplexity int, id string, maxMessages int, maxMessageChars int) int
+		SetAPIKeySpendAlerts         func(childComplexity int, id string, thresholds []float64, webhookURL *string) int
+		SetAPIKeyStreamBudgetCutoff  func(childComplexity int, id string, enabled bool) int
 		SetActivePromptVersion       func(childComplexity int, templateID string, versionID string) int
 		SetBudget                    func(childComplexity int, input model.BudgetInput) int
 		SyncProviderModels           func(childComplexity int, providerID string) int
@@ -614,6 +648,7 @@ type ComplexityRoot struct {
 		UpdateCoupon                 func(childComplexity int, id string, input model.CouponInput) int
 		UpdateDlpConfig              func(childComplexity int, input model.UpdateDlpConfigInput) int
 		UpdateDocument               func(childComplexity int, id string, input model.DocumentInput) int
+		UpdateFallbackChain          func(childComplexity int, id string, input model.UpdateFallbackChainInput) int
 		UpdateFeatureGate            func(childComplexity int, name string, enabled bool) int
 		UpdateIdentityProvider       func(childComplexity int, id string, input model.UpdateIdentityProviderInput) int
 		UpdateIntegration            func(childComplexity int, name string, input model.UpdateIntegrationInput) int
@@ -725,45 +760,63 @@ type ComplexityRoot struct {
 	}
 
 	Provider struct {
-		BaseURL        func(childComplexity int) int
-		CreatedAt      func(childComplexity int) int
-		DefaultProxyID func(childComplexity int) int
-		ID             func(childComplexity int) int
-		IsActive       func(childComplexity int) int
-		MaxRetries     func(childComplexity int) int
-		Name           func(childComplexity int) int
-		Priority       func(childComplexity int) int
-		RequiresAPIKey func(childComplexity int) int
-		Timeout        func(childComplexity int) int
-		UseProxy       func(childComplexity int) int
-		Weight         func(childComplexity int) int
+		BaseURL               func(childComplexity int) int
+		CreatedAt             func(childComplexity int) int
+		DeepHealthCheck       func(childComplexity int) int
+		DefaultMaxTokens      func(childComplexity int) int
+		DefaultProxyID        func(childComplexity int) int
+		Draining              func(childComplexity int) int
+		HealthCheckModel      func(childComplexity int) int
+		ID                    func(childComplexity int) int
+		IsActive              func(childComplexity int) int
+		MaxOutputTokens       func(childComplexity int) int
+		MaxRetries            func(childComplexity int) int
+		Name                  func(childComplexity int) int
+		Priority              func(childComplexity int) int
+		PromptCaching         func(childComplexity int) int
+		RequiresAPIKey        func(childComplexity int) int
+		TLSCaBundlePath       func(childComplexity int) int
+		TLSInsecureSkipVerify func(childComplexity int) int
+		TLSMinVersion         func(childComplexity int) int
+		Timeout               func(childComplexity int) int
+		TrafficPercentage     func(childComplexity int) int
+		Type                  func(childComplexity int) int
+		UseProxy              func(childComplexity int) int
+		Weight                func(childComplexity int) int
 	}
 
 	ProviderApiKey struct {
-		Alias      func(childComplexity int) int
-		CreatedAt  func(childComplexity int) int
-		ID         func(childComplexity int) int
-		IsActive   func(childComplexity int) int
-		KeyPrefix  func(childComplexity int) int
-		LastUsedAt func(childComplexity int) int
-		Priority   func(childComplexity int) int
-		ProviderID func(childComplexity int) int
-		RateLimit  func(childComplexity int) int
-		UsageCount func(childComplexity int) int
-		Weight     func(childComplexity int) int
+		Alias               func(childComplexity int) int
+		CreatedAt           func(childComplexity int) int
+		ID                  func(childComplexity int) int
+		IsActive            func(childComplexity int) int
+		KeyPrefix           func(childComplexity int) int
+		LastUsedAt          func(childComplexity int) int
+		MonthlyBudgetUsd    func(childComplexity int) int
+		MonthlyCost         func(childComplexity int) int
+		MonthlyRequestLimit func(childComplexity int) int
+		MonthlyRequests     func(childComplexity int) int
+		Priority            func(childComplexity int) int
+		ProviderID          func(childComplexity int) int
+		RateLimit           func(childComplexity int) int
+		UsageCount          func(childComplexity int) int
+		Weight              func(childComplexity int) int
 	}
 
 	ProviderHealth struct {
-		BaseURL      func(childComplexity int) int
-		ErrorMessage func(childComplexity int) int
-		ID           func(childComplexity int) int
-		IsActive     func(childComplexity int) int
-		IsHealthy    func(childComplexity int) int
-		LastCheck    func(childComplexity int) int
-		Name         func(childComplexity int) int
-		ResponseTime func(childComplexity int) int
-		SuccessRate  func(childComplexity int) int
-		UseProxy     func(childComplexity int) int
+		AvgLatencyMs       func(childComplexity int) int
+		BaseURL            func(childComplexity int) int
+		ErrorMessage       func(childComplexity int) int
+		ID                 func(childComplexity int) int
+		IsActive           func(childComplexity int) int
+		IsHealthy          func(childComplexity int) int
+		LastCheck          func(childComplexity int) int
+		LatencySLABreached func(childComplexity int) int
+		LatencyThresholdMs func(childComplexity int) int
+		Name               func(childComplexity int) int
+		ResponseTime       func(childComplexity int) int
+		SuccessRate        func(childComplexity int) int
+		UseProxy           func(childComplexity int) int
 	}
 
 	ProviderStats struct {
@@ -774,6 +827,7 @@ type ComplexityRoot struct {
 		SuccessRate  func(childComplexity int) int
 		Tokens       func(childComplexity int) int
 		TotalCost    func(childComplexity int) int
+		TrafficShare func(childComplexity int) int
 	}
 
 	ProviderUsage struct {
@@ -845,6 +899,7 @@ type ComplexityRoot struct {
 		Document               func(childComplexity int, id string) int
 		Documents              func(childComplexity int) int
 		ErrorLogs              func(childComplexity int, page *int, pageSize *int) int
+		FallbackChains         func(childComplexity int) int
 		FeatureGates           func(childComplexity int) int
 		GetDlpConfig           func(childComplexity int, projectID string) int
 		HealthAPIKeys          func(childComplexity int) int
@@ -1236,6 +1291,11 @@ type MutationResolver interface {
 	UpdateAPIKey(ctx context.Context, id string, name *string, scopes *string, rateLimit *int, tokenLimit *int, isActive *bool) (*model.APIKey, error)
 	RevokeAPIKey(ctx context.Context, projectID string, id string) (*model.APIKey, error)
 	DeleteAPIKey(ctx context.Context, projectID string, id string) (bool, error)
+	SetAPIKeyAllowedCidrs(ctx context.Context, id string, cidrs []string) (*model.APIKey, error)
+	SetAPIKeySpendAlerts(ctx context.Context, id string, thresholds []float64, webhookURL *string) (*model.APIKey, error)
+	SetAPIKeyMaxRequestCost(ctx context.Context, id string, maxCostUsd float64) (*model.APIKey, error)
+	SetAPIKeyMessageLimits(ctx context.Context, id string, maxMessages int, maxMessageChars int) (*model.APIKey, error)
+	SetAPIKeyStreamBudgetCutoff(ctx context.Context, id string, enabled bool) (*model.APIKey, error)
 	UpdateProject(ctx context.Context, id string, input model.UpdateProjectInput) (*model.Project, error)
 	AddOrganizationMember(ctx context.Context, orgID string, email string, role string) (*model.OrganizationMember, error)
 	UpdateOrganizationMemberRole(ctx context.Context, orgID string, userID string, role string) (*model.OrganizationMember, error)
@@ -1314,6 +1374,9 @@ type MutationResolver interface {
 	CreateRoutingRule(ctx context.Context, input model.CreateRoutingRuleInput) (*model.RoutingRule, error)
 	UpdateRoutingRule(ctx context.Context, id string, input model.UpdateRoutingRuleInput) (*model.RoutingRule, error)
 	DeleteRoutingRule(ctx context.Context, id string) (bool, error)
+	CreateFallbackChain(ctx context.Context, input model.CreateFallbackChainInput) (*model.FallbackChain, error)
+	UpdateFallbackChain(ctx context.Context, id string, input model.UpdateFallbackChainInput) (*model.FallbackChain, error)
+	DeleteFallbackChain(ctx context.Context, id string) (bool, error)
 	ClearSemanticCache(ctx context.Context, id string) (bool, error)
 	ClearAllSemanticCaches(ctx context.Context) (bool, error)
 	UpdateCacheConfig(ctx context.Context, input model.CacheConfigInput) (*model.CacheConfig, error)
@@ -1387,6 +1450,7 @@ type QueryResolver interface {
 	RequestLogs(ctx context.Context, requestID *string, level *string, startTime *string, endTime *string, limit *int) ([]*model.LogEntry, error)
 	Integrations(ctx context.Context) ([]*model.IntegrationConfig, error)
 	RoutingRules(ctx context.Context, page *int, pageSize *int) (*model.RoutingRuleList, error)
+	FallbackChains(ctx context.Context) ([]*model.FallbackChain, error)
 	PromptTemplates(ctx context.Context) (*model.PromptTemplateConnection, error)
 	PromptTemplate(ctx context.Context, id string) (*model.PromptTemplate, error)
 	PromptVersions(ctx context.Context, templateID string) ([]*model.PromptVersion, error)
@@ -1491,6 +1555,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.AdminDashboard.McpErrorCount(childComplexity), true
+	case "AdminDashboard.misconfiguredProviders":
+		if e.ComplexityRoot.AdminDashboard.MisconfiguredProviders == nil {
+			break
+		}
+
+		return e.ComplexityRoot.AdminDashboard.MisconfiguredProviders(childComplexity), true
 	case "AdminDashboard.requestsToday":
 		if e.ComplexityRoot.AdminDashboard.RequestsToday == nil {
 			break
@@ -1810,6 +1880,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.ComplexityRoot.AnomalyResult.Message(childComplexity), true
 
+	case "ApiKey.allowedCidrs":
+		if e.ComplexityRoot.ApiKey.AllowedCidrs == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ApiKey.AllowedCidrs(childComplexity), true
 	case "ApiKey.channel":
 		if e.ComplexityRoot.ApiKey.Channel == nil {
 			break
@@ -1858,6 +1934,24 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.ApiKey.LastUsedAt(childComplexity), true
+	case "ApiKey.maxMessageChars":
+		if e.ComplexityRoot.ApiKey.MaxMessageChars == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ApiKey.MaxMessageChars(childComplexity), true
+	case "ApiKey.maxMessages":
+		if e.ComplexityRoot.ApiKey.MaxMessages == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ApiKey.MaxMessages(childComplexity), true
+	case "ApiKey.maxRequestCostUsd":
+		if e.ComplexityRoot.ApiKey.MaxRequestCostUsd == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ApiKey.MaxRequestCostUsd(childComplexity), true
 	case "ApiKey.name":
 		if e.ComplexityRoot.ApiKey.Name == nil {
 			break
@@ -1882,6 +1976,24 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.ApiKey.Scopes(childComplexity), true
+	case "ApiKey.spendThresholds":
+		if e.ComplexityRoot.ApiKey.SpendThresholds == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ApiKey.SpendThresholds(childComplexity), true
+	case "ApiKey.spendWebhookUrl":
+		if e.ComplexityRoot.ApiKey.SpendWebhookURL == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ApiKey.SpendWebhookURL(childComplexity), true
+	case "ApiKey.streamBudgetCutoff":
+		if e.ComplexityRoot.ApiKey.StreamBudgetCutoff == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ApiKey.StreamBudgetCutoff(childComplexity), true
 	case "ApiKey.tokenLimit":
 		if e.ComplexityRoot.ApiKey.TokenLimit == nil {
 			break
@@ -2946,6 +3058,61 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.ComplexityRoot.ErrorLogConnection.Total(childComplexity), true
 
+	case "FallbackChain.createdAt":
+		if e.ComplexityRoot.FallbackChain.CreatedAt == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.CreatedAt(childComplexity), true
+	case "FallbackChain.description":
+		if e.ComplexityRoot.FallbackChain.Description == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.Description(childComplexity), true
+	case "FallbackChain.id":
+		if e.ComplexityRoot.FallbackChain.ID == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.ID(childComplexity), true
+	case "FallbackChain.isEnabled":
+		if e.ComplexityRoot.FallbackChain.IsEnabled == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.IsEnabled(childComplexity), true
+	case "FallbackChain.modelPattern":
+		if e.ComplexityRoot.FallbackChain.ModelPattern == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.ModelPattern(childComplexity), true
+	case "FallbackChain.name":
+		if e.ComplexityRoot.FallbackChain.Name == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.Name(childComplexity), true
+	case "FallbackChain.priority":
+		if e.ComplexityRoot.FallbackChain.Priority == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.Priority(childComplexity), true
+	case "FallbackChain.providerIds":
+		if e.ComplexityRoot.FallbackChain.ProviderIds == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.ProviderIds(childComplexity), true
+	case "FallbackChain.updatedAt":
+		if e.ComplexityRoot.FallbackChain.UpdatedAt == nil {
+			break
+		}
+
+		return e.ComplexityRoot.FallbackChain.UpdatedAt(childComplexity), true
+
 	case "FeatureGate.category":
 		if e.ComplexityRoot.FeatureGate.Category == nil {
 			break
@@ -2990,6 +3157,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.ComplexityRoot.GenerateRedeemCodesResult.Count(childComplexity), true
 
+	case "HealthEvent.checkMode":
+		if e.ComplexityRoot.HealthEvent.CheckMode == nil {
+			break
+		}
+
+		return e.ComplexityRoot.HealthEvent.CheckMode(childComplexity), true
 	case "HealthEvent.createdAt":
 		if e.ComplexityRoot.HealthEvent.CreatedAt == nil {
 			break
@@ -3455,6 +3628,25 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.ComplexityRoot.MfaSecretInfo.Secret(childComplexity), true
 
+	case "MisconfiguredProvider.id":
+		if e.ComplexityRoot.MisconfiguredProvider.ID == nil {
+			break
+		}
+
+		return e.ComplexityRoot.MisconfiguredProvider.ID(childComplexity), true
+	case "MisconfiguredProvider.issue":
+		if e.ComplexityRoot.MisconfiguredProvider.Issue == nil {
+			break
+		}
+
+		return e.ComplexityRoot.MisconfiguredProvider.Issue(childComplexity), true
+	case "MisconfiguredProvider.name":
+		if e.ComplexityRoot.MisconfiguredProvider.Name == nil {
+			break
+		}
+
+		return e.ComplexityRoot.MisconfiguredProvider.Name(childComplexity), true
+
 	case "Model.createdAt":
 		if e.ComplexityRoot.Model.CreatedAt == nil {
 			break
@@ -3731,6 +3923,17 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Mutation.CreateDocument(childComplexity, args["input"].(model.DocumentInput)), true
+	case "Mutation.createFallbackChain":
+		if e.ComplexityRoot.Mutation.CreateFallbackChain == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createFallbackChain_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.CreateFallbackChain(childComplexity, args["input"].(model.CreateFallbackChainInput)), true
 	case "Mutation.createIdentityProvider":
 		if e.ComplexityRoot.Mutation.CreateIdentityProvider == nil {
 			break
@@ -3946,6 +4149,17 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Mutation.DeleteDocument(childComplexity, args["id"].(string)), true
+	case "Mutation.deleteFallbackChain":
+		if e.ComplexityRoot.Mutation.DeleteFallbackChain == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_deleteFallbackChain_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.DeleteFallbackChain(childComplexity, args["id"].(string)), true
 	case "Mutation.deleteIdentityProvider":
 		if e.ComplexityRoot.Mutation.DeleteIdentityProvider == nil {
 			break
@@ -4246,6 +4460,61 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Mutation.SendTestEmail(childComplexity, args["to"].(string)), true
+	case "Mutation.setApiKeyAllowedCidrs":
+		if e.ComplexityRoot.Mutation.SetAPIKeyAllowedCidrs == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_setApiKeyAllowedCidrs_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.SetAPIKeyAllowedCidrs(childComplexity, args["id"].(string), args["cidrs"].([]string)), true
+	case "Mutation.setApiKeyMaxRequestCost":
+		if e.ComplexityRoot.Mutation.SetAPIKeyMaxRequestCost == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_setApiKeyMaxRequestCost_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.SetAPIKeyMaxRequestCost(childComplexity, args["id"].(string), args["maxCostUsd"].(float64)), true
+	case "Mutation.setApiKeyMessageLimits":
+		if e.ComplexityRoot.Mutation.SetAPIKeyMessageLimits == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_setApiKeyMessageLimits_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.SetAPIKeyMessageLimits(childComplexity, args["id"].(string), args["maxMessages"].(int), args["maxMessageChars"].(int)), true
+	case "Mutation.setApiKeySpendAlerts":
+		if e.ComplexityRoot.Mutation.SetAPIKeySpendAlerts == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_setApiKeySpendAlerts_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.SetAPIKeySpendAlerts(childComplexity, args["id"].(string), args["thresholds"].([]float64), args["webhookUrl"].(*string)), true
+	case "Mutation.setApiKeyStreamBudgetCutoff":
+		if e.ComplexityRoot.Mutation.SetAPIKeyStreamBudgetCutoff == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_setApiKeyStreamBudgetCutoff_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.SetAPIKeyStreamBudgetCutoff(childComplexity, args["id"].(string), args["enabled"].(bool)), true
 	case "Mutation.setActivePromptVersion":
 		if e.ComplexityRoot.Mutation.SetActivePromptVersion == nil {
 			break
@@ -4478,6 +4747,17 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Mutation.UpdateDocument(childComplexity, args["id"].(string), args["input"].(model.DocumentInput)), true
+	case "Mutation.updateFallbackChain":
+		if e.ComplexityRoot.Mutation.UpdateFallbackChain == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_updateFallbackChain_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.ComplexityRoot.Mutation.UpdateFallbackChain(childComplexity, args["id"].(string), args["input"].(model.UpdateFallbackChainInput)), true
 	case "Mutation.updateFeatureGate":
 		if e.ComplexityRoot.Mutation.UpdateFeatureGate == nil {
 			break
@@ -5091,12 +5371,36 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Provider.CreatedAt(childComplexity), true
+	case "Provider.deepHealthCheck":
+		if e.ComplexityRoot.Provider.DeepHealthCheck == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.DeepHealthCheck(childComplexity), true
+	case "Provider.defaultMaxTokens":
+		if e.ComplexityRoot.Provider.DefaultMaxTokens == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.DefaultMaxTokens(childComplexity), true
 	case "Provider.defaultProxyId":
 		if e.ComplexityRoot.Provider.DefaultProxyID == nil {
 			break
 		}
 
 		return e.ComplexityRoot.Provider.DefaultProxyID(childComplexity), true
+	case "Provider.draining":
+		if e.ComplexityRoot.Provider.Draining == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.Draining(childComplexity), true
+	case "Provider.healthCheckModel":
+		if e.ComplexityRoot.Provider.HealthCheckModel == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.HealthCheckModel(childComplexity), true
 	case "Provider.id":
 		if e.ComplexityRoot.Provider.ID == nil {
 			break
@@ -5109,6 +5413,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Provider.IsActive(childComplexity), true
+	case "Provider.maxOutputTokens":
+		if e.ComplexityRoot.Provider.MaxOutputTokens == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.MaxOutputTokens(childComplexity), true
 	case "Provider.maxRetries":
 		if e.ComplexityRoot.Provider.MaxRetries == nil {
 			break
@@ -5127,6 +5437,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Provider.Priority(childComplexity), true
+	case "Provider.promptCaching":
+		if e.ComplexityRoot.Provider.PromptCaching == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.PromptCaching(childComplexity), true
 	case "Provider.requiresApiKey":
 		if e.ComplexityRoot.Provider.RequiresAPIKey == nil {
 			break
@@ -5139,6 +5455,36 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Provider.Timeout(childComplexity), true
+	case "Provider.tlsCaBundlePath":
+		if e.ComplexityRoot.Provider.TLSCaBundlePath == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.TLSCaBundlePath(childComplexity), true
+	case "Provider.tlsInsecureSkipVerify":
+		if e.ComplexityRoot.Provider.TLSInsecureSkipVerify == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.TLSInsecureSkipVerify(childComplexity), true
+	case "Provider.tlsMinVersion":
+		if e.ComplexityRoot.Provider.TLSMinVersion == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.TLSMinVersion(childComplexity), true
+	case "Provider.trafficPercentage":
+		if e.ComplexityRoot.Provider.TrafficPercentage == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.TrafficPercentage(childComplexity), true
+	case "Provider.type":
+		if e.ComplexityRoot.Provider.Type == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Provider.Type(childComplexity), true
 	case "Provider.useProxy":
 		if e.ComplexityRoot.Provider.UseProxy == nil {
 			break
@@ -5188,6 +5534,30 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.ProviderApiKey.LastUsedAt(childComplexity), true
+	case "ProviderApiKey.monthlyBudgetUsd":
+		if e.ComplexityRoot.ProviderApiKey.MonthlyBudgetUsd == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderApiKey.MonthlyBudgetUsd(childComplexity), true
+	case "ProviderApiKey.monthlyCost":
+		if e.ComplexityRoot.ProviderApiKey.MonthlyCost == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderApiKey.MonthlyCost(childComplexity), true
+	case "ProviderApiKey.monthlyRequestLimit":
+		if e.ComplexityRoot.ProviderApiKey.MonthlyRequestLimit == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderApiKey.MonthlyRequestLimit(childComplexity), true
+	case "ProviderApiKey.monthlyRequests":
+		if e.ComplexityRoot.ProviderApiKey.MonthlyRequests == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderApiKey.MonthlyRequests(childComplexity), true
 	case "ProviderApiKey.priority":
 		if e.ComplexityRoot.ProviderApiKey.Priority == nil {
 			break
@@ -5219,6 +5589,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.ComplexityRoot.ProviderApiKey.Weight(childComplexity), true
 
+	case "ProviderHealth.avgLatencyMs":
+		if e.ComplexityRoot.ProviderHealth.AvgLatencyMs == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderHealth.AvgLatencyMs(childComplexity), true
 	case "ProviderHealth.baseUrl":
 		if e.ComplexityRoot.ProviderHealth.BaseURL == nil {
 			break
@@ -5255,6 +5631,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.ProviderHealth.LastCheck(childComplexity), true
+	case "ProviderHealth.latencySlaBreached":
+		if e.ComplexityRoot.ProviderHealth.LatencySLABreached == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderHealth.LatencySLABreached(childComplexity), true
+	case "ProviderHealth.latencyThresholdMs":
+		if e.ComplexityRoot.ProviderHealth.LatencyThresholdMs == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderHealth.LatencyThresholdMs(childComplexity), true
 	case "ProviderHealth.name":
 		if e.ComplexityRoot.ProviderHealth.Name == nil {
 			break
@@ -5322,6 +5710,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.ProviderStats.TotalCost(childComplexity), true
+	case "ProviderStats.trafficShare":
+		if e.ComplexityRoot.ProviderStats.TrafficShare == nil {
+			break
+		}
+
+		return e.ComplexityRoot.ProviderStats.TrafficShare(childComplexity), true
 
 	case "ProviderUsage.cost":
 		if e.ComplexityRoot.ProviderUsage.Cost == nil {
@@ -5701,6 +6095,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.ComplexityRoot.Query.ErrorLogs(childComplexity, args["page"].(*int), args["pageSize"].(*int)), true
+	case "Query.fallbackChains":
+		if e.ComplexityRoot.Query.FallbackChains == nil {
+			break
+		}
+
+		return e.ComplexityRoot.Query.FallbackChains(childComplexity), true
 	case "Query.featureGates":
 		if e.ComplexityRoot.Query.FeatureGates == nil {
 			break
@@ -7547,6 +7947,7 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputCacheConfigInput,
 		ec.unmarshalInputChangePasswordInput,
 		ec.unmarshalInputCouponInput,
+		ec.unmarshalInputCreateFallbackChainInput,
 		ec.unmarshalInputCreateIdentityProviderInput,
 		ec.unmarshalInputCreateProviderInput,
 		ec.unmarshalInputCreateRoutingRuleInput,
@@ -7571,6 +7972,7 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputResetPasswordInput,
 		ec.unmarshalInputSystemSettingsInput,
 		ec.unmarshalInputUpdateDlpConfigInput,
+		ec.unmarshalInputUpdateFallbackChainInput,
 		ec.unmarshalInputUpdateIdentityProviderInput,
 		ec.unmarshalInputUpdateIntegrationInput,
 		ec.unmarshalInputUpdateNotificationChannelInput,
@@ -7736,6 +8138,7 @@ type Query {
   requestLogs(requestId: String, level: String, startTime: String, endTime: String, limit: Int): [LogEntry!]! @auth(role: ADMIN)
   integrations: [IntegrationConfig!]! @auth(role: ADMIN)
   routingRules(page: Int = 1, pageSize: Int = 20): RoutingRuleList! @auth(role: ADMIN)
+  fallbackChains: [FallbackChain!]! @auth(role: ADMIN)
   promptTemplates: PromptTemplateConnection! @auth(role: ADMIN)
   promptTemplate(id: ID!): PromptTemplate! @auth(role: ADMIN)
   promptVersions(templateId: ID!): [PromptVersion!]! @auth(role: ADMIN)
@@ -7788,6 +8191,12 @@ type Mutation {
   updateApiKey(id: ID!, name: String, scopes: String, rateLimit: Int, tokenLimit: Int, isActive: Boolean): ApiKey! @auth
   revokeApiKey(projectId: ID!, id: ID!): ApiKey! @auth
   deleteApiKey(projectId: ID!, id: ID!): Boolean! @auth
+  setApiKeyAllowedCidrs(id: ID!, cidrs: [String!]!): ApiKey! @auth
+  setApiKeySpendAlerts(id: ID!, thresholds: [Float!]!, webhookUrl: String): ApiKey! @auth
+  setApiKeyMaxRequestCost(id: ID!, maxCostUsd: Float!): ApiKey! @auth
+  # Raising or lifting the server-wide limits requires an admin.
+  setApiKeyMessageLimits(id: ID!, maxMessages: Int!, maxMessageChars: Int!): ApiKey! @auth
+  setApiKeyStreamBudgetCutoff(id: ID!, enabled: Boolean!): ApiKey! @auth
   updateProject(id: ID!, input: UpdateProjectInput!): Project! @auth
 
   # ── Organization Members ──
@@ -7916,6 +8325,11 @@ type Mutation {
   createRoutingRule(input: CreateRoutingRuleInput!): RoutingRule! @auth(role: ADMIN)
   updateRoutingRule(id: ID!, input: UpdateRoutingRuleInput!): RoutingRule! @auth(role: ADMIN)
   deleteRoutingRule(id: ID!): Boolean! @auth(role: ADMIN)
+
+  # ── Admin: Fallback Chains ──
+  createFallbackChain(input: CreateFallbackChainInput!): FallbackChain! @auth(role: ADMIN)
+  updateFallbackChain(id: ID!, input: UpdateFallbackChainInput!): FallbackChain! @auth(role: ADMIN)
+  deleteFallbackChain(id: ID!): Boolean! @auth(role: ADMIN)
 }
 `, BuiltIn: false},
 	{Name: "../schema/types_announcement.graphqls", Input: `# ──────────────────────────────────────────────────
@@ -8223,6 +8637,15 @@ type AdminDashboard {
   apiKeysHealthy: Int!
   mcpCallCount: Int!
   mcpErrorCount: Int!
+  # Providers that cannot serve requests as configured
+  misconfiguredProviders: [MisconfiguredProvider!]!
+}
+
+type MisconfiguredProvider {
+  id: ID!
+  name: String!
+  # Machine-readable reason, e.g. "no_active_api_keys"
+  issue: String!
 }
 
 type AdminUsageByUser {
@@ -8261,6 +8684,7 @@ type ProviderStats {
   successRate: Float!
   avgLatencyMs: Float!
   totalCost: Float!
+  trafficShare: Float! # percentage of all requests in the period served by this provider
 }
 
 type ModelStats {
@@ -8524,6 +8948,9 @@ type ProviderHealth {
   lastCheck: DateTime
   successRate: Float!
   errorMessage: String
+  avgLatencyMs: Float!
+  latencyThresholdMs: Int!
+  latencySlaBreached: Boolean!
 }
 
 type HealthEvent {
@@ -8532,6 +8959,7 @@ type HealthEvent {
   targetId: ID!
   status: String!
   message: String
+  checkMode: String
   createdAt: DateTime!
 }
 
@@ -8873,6 +9301,7 @@ input PromptVersionInput {
 type Provider {
   id: ID!
   name: String!
+  type: String! # protocol selecting the client, e.g. anthropic or openai-compatible
   baseUrl: String!
   isActive: Boolean!
   priority: Int!
@@ -8882,6 +9311,16 @@ type Provider {
   useProxy: Boolean!
   defaultProxyId: ID
   requiresApiKey: Boolean!
+  deepHealthCheck: Boolean!
+  healthCheckModel: String
+  defaultMaxTokens: Int! # max_tokens sent when a request omits it; 0 = none
+  maxOutputTokens: Int! # ceiling on a request's max_tokens; 0 = none
+  trafficPercentage: Int! # cap on the provider's share of a shared model's traffic; 0 or 100 = none
+  tlsInsecureSkipVerify: Boolean! # certificate verification disabled (self-signed endpoints)
+  tlsMinVersion: String # "1.2" or "1.3"; null = default (1.2)
+  tlsCaBundlePath: String # PEM file of extra trusted CAs on the server
+  promptCaching: Boolean! # Anthropic: system prompt marked with cache_control
+  draining: Boolean! # active but taken out of routing for maintenance
   createdAt: DateTime!
 }
 
@@ -8895,12 +9334,17 @@ type ProviderApiKey {
   weight: Float!
   rateLimit: Int!
   usageCount: Int!
+  monthlyRequestLimit: Int!
+  monthlyBudgetUsd: Float!
+  monthlyRequests: Int!
+  monthlyCost: Float!
   lastUsedAt: DateTime
   createdAt: DateTime!
 }
 
 input ProviderInput {
   name: String
+  type: String
   baseUrl: String
   isActive: Boolean
   priority: Int
@@ -8910,6 +9354,15 @@ input ProviderInput {
   useProxy: Boolean
   defaultProxyId: ID
   requiresApiKey: Boolean
+  deepHealthCheck: Boolean
+  healthCheckModel: String
+  defaultMaxTokens: Int
+  maxOutputTokens: Int
+  trafficPercentage: Int
+  tlsInsecureSkipVerify: Boolean
+  tlsMinVersion: String # "" resets to the default
+  tlsCaBundlePath: String # "" clears
+  promptCaching: Boolean
 }
 
 input ProviderApiKeyInput {
@@ -8918,16 +9371,21 @@ input ProviderApiKeyInput {
   priority: Int
   weight: Float
   rateLimit: Int
+  monthlyRequestLimit: Int
+  monthlyBudgetUsd: Float
 }
 
 input UpdateProviderApiKeyInput {
   priority: Int
   weight: Float
   rateLimit: Int
+  monthlyRequestLimit: Int
+  monthlyBudgetUsd: Float
 }
 
 input CreateProviderInput {
   name: String!
+  type: String # required unless name is a provider type
   baseUrl: String!
   isActive: Boolean
   priority: Int
@@ -8936,6 +9394,10 @@ input CreateProviderInput {
   timeout: Int
   useProxy: Boolean
   requiresApiKey: Boolean
+  deepHealthCheck: Boolean
+  healthCheckModel: String
+  defaultMaxTokens: Int
+  maxOutputTokens: Int
 }
 `, BuiltIn: false},
 	{Name: "../schema/types_proxy.graphqls", Input: `# ──────────────────────────────────────────────────
@@ -9091,6 +9553,36 @@ input UpdateRoutingRuleInput {
     priority: Int
     isEnabled: Boolean
 }
+
+type FallbackChain {
+    id: ID!
+    name: String!
+    description: String!
+    modelPattern: String!
+    providerIds: [ID!]!
+    priority: Int!
+    isEnabled: Boolean!
+    createdAt: DateTime!
+    updatedAt: DateTime!
+}
+
+input CreateFallbackChainInput {
+    name: String!
+    description: String
+    modelPattern: String!
+    providerIds: [ID!]!
+    priority: Int!
+    isEnabled: Boolean!
+}
+
+input UpdateFallbackChainInput {
+    name: String
+    description: String
+    modelPattern: String
+    providerIds: [ID!]
+    priority: Int
+    isEnabled: Boolean
+}
 `, BuiltIn: false},
 	{Name: "../schema/types_sso.graphqls", Input: `type IdentityProvider {
     id: ID!
@@ -9252,6 +9744,13 @@ type ApiKey {
   rateLimit: Int!
   tokenLimit: Int!
   dailyLimit: Int!
+  allowedCidrs: [String!]!
+  spendThresholds: [Float!]!
+  spendWebhookUrl: String
+  maxRequestCostUsd: Float!
+  maxMessages: Int!
+  maxMessageChars: Int!
+  streamBudgetCutoff: Boolean!
   expiresAt: DateTime
   lastUsedAt: DateTime
   createdAt: DateTime!
@@ -9615,6 +10114,17 @@ func (ec *executionContext) field_Mutation_createDocument_args(ctx context.Conte
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_createFallbackChain_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNCreateFallbackChainInput2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐCreateFallbackChainInput)
+	if err != nil {
+		return nil, err
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_createIdentityProvider_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -9839,6 +10349,17 @@ func (ec *executionContext) field_Mutation_deleteDocument_args(ctx context.Conte
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_deleteFallbackChain_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_deleteIdentityProvider_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -10134,6 +10655,96 @@ func (ec *executionContext) field_Mutation_setActivePromptVersion_args(ctx conte
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_setApiKeyAllowedCidrs_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "cidrs", ec.unmarshalNString2ᚕstringᚄ)
+	if err != nil {
+		return nil, err
+	}
+	args["cidrs"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_setApiKeyMaxRequestCost_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "maxCostUsd", ec.unmarshalNFloat2float64)
+	if err != nil {
+		return nil, err
+	}
+	args["maxCostUsd"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_setApiKeyMessageLimits_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "maxMessages", ec.unmarshalNInt2int)
+	if err != nil {
+		return nil, err
+	}
+	args["maxMessages"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "maxMessageChars", ec.unmarshalNInt2int)
+	if err != nil {
+		return nil, err
+	}
+	args["maxMessageChars"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_setApiKeySpendAlerts_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "thresholds", ec.unmarshalNFloat2ᚕfloat64ᚄ)
+	if err != nil {
+		return nil, err
+	}
+	args["thresholds"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "webhookUrl", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["webhookUrl"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_setApiKeyStreamBudgetCutoff_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "enabled", ec.unmarshalNBoolean2bool)
+	if err != nil {
+		return nil, err
+	}
+	args["enabled"] = arg1
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_setBudget_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -10398,6 +11009,22 @@ func (ec *executionContext) field_Mutation_updateDocument_args(ctx context.Conte
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_updateFallbackChain_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "id", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["id"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNUpdateFallbackChainInput2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐUpdateFallbackChainInput)
+	if err != nil {
+		return nil, err
+	}
+	args["input"] = arg1
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_updateFeatureGate_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -12091,6 +12718,43 @@ func (ec *executionContext) fieldContext_AdminDashboard_mcpErrorCount(_ context.
 	return fc, nil
 }
 
+func (ec *executionContext) _AdminDashboard_misconfiguredProviders(ctx context.Context, field graphql.CollectedField, obj *model.AdminDashboard) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_AdminDashboard_misconfiguredProviders,
+		func(ctx context.Context) (any, error) {
+			return obj.MisconfiguredProviders, nil
+		},
+		nil,
+		ec.marshalNMisconfiguredProvider2ᚕᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐMisconfiguredProviderᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_AdminDashboard_misconfiguredProviders(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "AdminDashboard",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_MisconfiguredProvider_id(ctx, field)
+			case "name":
+				return ec.fieldContext_MisconfiguredProvider_name(ctx, field)
+			case "issue":
+				return ec.fieldContext_MisconfiguredProvider_issue(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type MisconfiguredProvider", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _AdminUsageByUser_userId(ctx context.Context, field graphql.CollectedField, obj *model.AdminUsageByUser) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -13590,6 +14254,209 @@ func (ec *executionContext) fieldContext_ApiKey_dailyLimit(_ context.Context, fi
 	return fc, nil
 }
 
+func (ec *executionContext) _ApiKey_allowedCidrs(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ApiKey_allowedCidrs,
+		func(ctx context.Context) (any, error) {
+			return obj.AllowedCidrs, nil
+		},
+		nil,
+		ec.marshalNString2ᚕstringᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ApiKey_allowedCidrs(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ApiKey_spendThresholds(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ApiKey_spendThresholds,
+		func(ctx context.Context) (any, error) {
+			return obj.SpendThresholds, nil
+		},
+		nil,
+		ec.marshalNFloat2ᚕfloat64ᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ApiKey_spendThresholds(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ApiKey_spendWebhookUrl(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ApiKey_spendWebhookUrl,
+		func(ctx context.Context) (any, error) {
+			return obj.SpendWebhookURL, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_ApiKey_spendWebhookUrl(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ApiKey_maxRequestCostUsd(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ApiKey_maxRequestCostUsd,
+		func(ctx context.Context) (any, error) {
+			return obj.MaxRequestCostUsd, nil
+		},
+		nil,
+		ec.marshalNFloat2float64,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ApiKey_maxRequestCostUsd(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ApiKey_maxMessages(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ApiKey_maxMessages,
+		func(ctx context.Context) (any, error) {
+			return obj.MaxMessages, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ApiKey_maxMessages(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ApiKey_maxMessageChars(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ApiKey_maxMessageChars,
+		func(ctx context.Context) (any, error) {
+			return obj.MaxMessageChars, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ApiKey_maxMessageChars(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ApiKey_streamBudgetCutoff(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ApiKey_streamBudgetCutoff,
+		func(ctx context.Context) (any, error) {
+			return obj.StreamBudgetCutoff, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ApiKey_streamBudgetCutoff(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _ApiKey_expiresAt(ctx context.Context, field graphql.CollectedField, obj *model.APIKey) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -18829,6 +19696,267 @@ func (ec *executionContext) fieldContext_ErrorLogConnection_pageSize(_ context.C
 	return fc, nil
 }
 
+func (ec *executionContext) _FallbackChain_id(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_id,
+		func(ctx context.Context) (any, error) {
+			return obj.ID, nil
+		},
+		nil,
+		ec.marshalNID2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_name(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_name,
+		func(ctx context.Context) (any, error) {
+			return obj.Name, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_description(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_description,
+		func(ctx context.Context) (any, error) {
+			return obj.Description, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_description(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_modelPattern(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_modelPattern,
+		func(ctx context.Context) (any, error) {
+			return obj.ModelPattern, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_modelPattern(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_providerIds(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_providerIds,
+		func(ctx context.Context) (any, error) {
+			return obj.ProviderIds, nil
+		},
+		nil,
+		ec.marshalNID2ᚕstringᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_providerIds(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_priority(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_priority,
+		func(ctx context.Context) (any, error) {
+			return obj.Priority, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_priority(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_isEnabled(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_isEnabled,
+		func(ctx context.Context) (any, error) {
+			return obj.IsEnabled, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_isEnabled(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_createdAt,
+		func(ctx context.Context) (any, error) {
+			return obj.CreatedAt, nil
+		},
+		nil,
+		ec.marshalNDateTime2timeᚐTime,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_createdAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type DateTime does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FallbackChain_updatedAt(ctx context.Context, field graphql.CollectedField, obj *model.FallbackChain) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_FallbackChain_updatedAt,
+		func(ctx context.Context) (any, error) {
+			return obj.UpdatedAt, nil
+		},
+		nil,
+		ec.marshalNDateTime2timeᚐTime,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_FallbackChain_updatedAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FallbackChain",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type DateTime does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _FeatureGate_name(ctx context.Context, field graphql.CollectedField, obj *model.FeatureGate) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -19177,6 +20305,35 @@ func (ec *executionContext) fieldContext_HealthEvent_message(_ context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _HealthEvent_checkMode(ctx context.Context, field graphql.CollectedField, obj *model.HealthEvent) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_HealthEvent_checkMode,
+		func(ctx context.Context) (any, error) {
+			return obj.CheckMode, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_HealthEvent_checkMode(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "HealthEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _HealthEvent_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.HealthEvent) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -21260,6 +22417,93 @@ func (ec *executionContext) fieldContext_MfaSecretInfo_backupCodes(_ context.Con
 	return fc, nil
 }
 
+func (ec *executionContext) _MisconfiguredProvider_id(ctx context.Context, field graphql.CollectedField, obj *model.MisconfiguredProvider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_MisconfiguredProvider_id,
+		func(ctx context.Context) (any, error) {
+			return obj.ID, nil
+		},
+		nil,
+		ec.marshalNID2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_MisconfiguredProvider_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "MisconfiguredProvider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _MisconfiguredProvider_name(ctx context.Context, field graphql.CollectedField, obj *model.MisconfiguredProvider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_MisconfiguredProvider_name,
+		func(ctx context.Context) (any, error) {
+			return obj.Name, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_MisconfiguredProvider_name(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "MisconfiguredProvider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _MisconfiguredProvider_issue(ctx context.Context, field graphql.CollectedField, obj *model.MisconfiguredProvider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_MisconfiguredProvider_issue,
+		func(ctx context.Context) (any, error) {
+			return obj.Issue, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_MisconfiguredProvider_issue(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "MisconfiguredProvider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Model_id(ctx context.Context, field graphql.CollectedField, obj *model.Model) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -22893,6 +24137,20 @@ func (ec *executionContext) fieldContext_Mutation_updateApiKey(ctx context.Conte
 				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
 			case "dailyLimit":
 				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
 			case "expiresAt":
 				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
 			case "lastUsedAt":
@@ -22980,6 +24238,20 @@ func (ec *executionContext) fieldContext_Mutation_revokeApiKey(ctx context.Conte
 				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
 			case "dailyLimit":
 				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
 			case "expiresAt":
 				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
 			case "lastUsedAt":
@@ -23063,6 +24335,511 @@ func (ec *executionContext) fieldContext_Mutation_deleteApiKey(ctx context.Conte
 	return fc, nil
 }
 
+func (ec *executionContext) _Mutation_setApiKeyAllowedCidrs(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_setApiKeyAllowedCidrs,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().SetAPIKeyAllowedCidrs(ctx, fc.Args["id"].(string), fc.Args["cidrs"].([]string))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "USER")
+				if err != nil {
+					var zeroVal *model.APIKey
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal *model.APIKey
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNApiKey2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐAPIKey,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_setApiKeyAllowedCidrs(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_ApiKey_id(ctx, field)
+			case "projectId":
+				return ec.fieldContext_ApiKey_projectId(ctx, field)
+			case "channel":
+				return ec.fieldContext_ApiKey_channel(ctx, field)
+			case "name":
+				return ec.fieldContext_ApiKey_name(ctx, field)
+			case "keyPrefix":
+				return ec.fieldContext_ApiKey_keyPrefix(ctx, field)
+			case "isActive":
+				return ec.fieldContext_ApiKey_isActive(ctx, field)
+			case "scopes":
+				return ec.fieldContext_ApiKey_scopes(ctx, field)
+			case "rateLimit":
+				return ec.fieldContext_ApiKey_rateLimit(ctx, field)
+			case "tokenLimit":
+				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
+			case "dailyLimit":
+				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
+			case "lastUsedAt":
+				return ec.fieldContext_ApiKey_lastUsedAt(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_ApiKey_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ApiKey", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_setApiKeyAllowedCidrs_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_setApiKeySpendAlerts(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_setApiKeySpendAlerts,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().SetAPIKeySpendAlerts(ctx, fc.Args["id"].(string), fc.Args["thresholds"].([]float64), fc.Args["webhookUrl"].(*string))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "USER")
+				if err != nil {
+					var zeroVal *model.APIKey
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal *model.APIKey
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNApiKey2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐAPIKey,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_setApiKeySpendAlerts(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_ApiKey_id(ctx, field)
+			case "projectId":
+				return ec.fieldContext_ApiKey_projectId(ctx, field)
+			case "channel":
+				return ec.fieldContext_ApiKey_channel(ctx, field)
+			case "name":
+				return ec.fieldContext_ApiKey_name(ctx, field)
+			case "keyPrefix":
+				return ec.fieldContext_ApiKey_keyPrefix(ctx, field)
+			case "isActive":
+				return ec.fieldContext_ApiKey_isActive(ctx, field)
+			case "scopes":
+				return ec.fieldContext_ApiKey_scopes(ctx, field)
+			case "rateLimit":
+				return ec.fieldContext_ApiKey_rateLimit(ctx, field)
+			case "tokenLimit":
+				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
+			case "dailyLimit":
+				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
+			case "lastUsedAt":
+				return ec.fieldContext_ApiKey_lastUsedAt(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_ApiKey_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ApiKey", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_setApiKeySpendAlerts_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_setApiKeyMaxRequestCost(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_setApiKeyMaxRequestCost,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().SetAPIKeyMaxRequestCost(ctx, fc.Args["id"].(string), fc.Args["maxCostUsd"].(float64))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "USER")
+				if err != nil {
+					var zeroVal *model.APIKey
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal *model.APIKey
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNApiKey2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐAPIKey,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_setApiKeyMaxRequestCost(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_ApiKey_id(ctx, field)
+			case "projectId":
+				return ec.fieldContext_ApiKey_projectId(ctx, field)
+			case "channel":
+				return ec.fieldContext_ApiKey_channel(ctx, field)
+			case "name":
+				return ec.fieldContext_ApiKey_name(ctx, field)
+			case "keyPrefix":
+				return ec.fieldContext_ApiKey_keyPrefix(ctx, field)
+			case "isActive":
+				return ec.fieldContext_ApiKey_isActive(ctx, field)
+			case "scopes":
+				return ec.fieldContext_ApiKey_scopes(ctx, field)
+			case "rateLimit":
+				return ec.fieldContext_ApiKey_rateLimit(ctx, field)
+			case "tokenLimit":
+				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
+			case "dailyLimit":
+				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
+			case "lastUsedAt":
+				return ec.fieldContext_ApiKey_lastUsedAt(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_ApiKey_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ApiKey", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_setApiKeyMaxRequestCost_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_setApiKeyMessageLimits(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_setApiKeyMessageLimits,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().SetAPIKeyMessageLimits(ctx, fc.Args["id"].(string), fc.Args["maxMessages"].(int), fc.Args["maxMessageChars"].(int))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "USER")
+				if err != nil {
+					var zeroVal *model.APIKey
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal *model.APIKey
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNApiKey2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐAPIKey,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_setApiKeyMessageLimits(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_ApiKey_id(ctx, field)
+			case "projectId":
+				return ec.fieldContext_ApiKey_projectId(ctx, field)
+			case "channel":
+				return ec.fieldContext_ApiKey_channel(ctx, field)
+			case "name":
+				return ec.fieldContext_ApiKey_name(ctx, field)
+			case "keyPrefix":
+				return ec.fieldContext_ApiKey_keyPrefix(ctx, field)
+			case "isActive":
+				return ec.fieldContext_ApiKey_isActive(ctx, field)
+			case "scopes":
+				return ec.fieldContext_ApiKey_scopes(ctx, field)
+			case "rateLimit":
+				return ec.fieldContext_ApiKey_rateLimit(ctx, field)
+			case "tokenLimit":
+				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
+			case "dailyLimit":
+				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
+			case "lastUsedAt":
+				return ec.fieldContext_ApiKey_lastUsedAt(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_ApiKey_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ApiKey", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_setApiKeyMessageLimits_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_setApiKeyStreamBudgetCutoff(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_setApiKeyStreamBudgetCutoff,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().SetAPIKeyStreamBudgetCutoff(ctx, fc.Args["id"].(string), fc.Args["enabled"].(bool))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "USER")
+				if err != nil {
+					var zeroVal *model.APIKey
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal *model.APIKey
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNApiKey2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐAPIKey,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_setApiKeyStreamBudgetCutoff(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_ApiKey_id(ctx, field)
+			case "projectId":
+				return ec.fieldContext_ApiKey_projectId(ctx, field)
+			case "channel":
+				return ec.fieldContext_ApiKey_channel(ctx, field)
+			case "name":
+				return ec.fieldContext_ApiKey_name(ctx, field)
+			case "keyPrefix":
+				return ec.fieldContext_ApiKey_keyPrefix(ctx, field)
+			case "isActive":
+				return ec.fieldContext_ApiKey_isActive(ctx, field)
+			case "scopes":
+				return ec.fieldContext_ApiKey_scopes(ctx, field)
+			case "rateLimit":
+				return ec.fieldContext_ApiKey_rateLimit(ctx, field)
+			case "tokenLimit":
+				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
+			case "dailyLimit":
+				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
+			case "lastUsedAt":
+				return ec.fieldContext_ApiKey_lastUsedAt(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_ApiKey_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ApiKey", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_setApiKeyStreamBudgetCutoff_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Mutation_updateProject(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -24490,6 +26267,8 @@ func (ec *executionContext) fieldContext_Mutation_createProvider(ctx context.Con
 				return ec.fieldContext_Provider_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Provider_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Provider_type(ctx, field)
 			case "baseUrl":
 				return ec.fieldContext_Provider_baseUrl(ctx, field)
 			case "isActive":
@@ -24508,6 +26287,26 @@ func (ec *executionContext) fieldContext_Mutation_createProvider(ctx context.Con
 				return ec.fieldContext_Provider_defaultProxyId(ctx, field)
 			case "requiresApiKey":
 				return ec.fieldContext_Provider_requiresApiKey(ctx, field)
+			case "deepHealthCheck":
+				return ec.fieldContext_Provider_deepHealthCheck(ctx, field)
+			case "healthCheckModel":
+				return ec.fieldContext_Provider_healthCheckModel(ctx, field)
+			case "defaultMaxTokens":
+				return ec.fieldContext_Provider_defaultMaxTokens(ctx, field)
+			case "maxOutputTokens":
+				return ec.fieldContext_Provider_maxOutputTokens(ctx, field)
+			case "trafficPercentage":
+				return ec.fieldContext_Provider_trafficPercentage(ctx, field)
+			case "tlsInsecureSkipVerify":
+				return ec.fieldContext_Provider_tlsInsecureSkipVerify(ctx, field)
+			case "tlsMinVersion":
+				return ec.fieldContext_Provider_tlsMinVersion(ctx, field)
+			case "tlsCaBundlePath":
+				return ec.fieldContext_Provider_tlsCaBundlePath(ctx, field)
+			case "promptCaching":
+				return ec.fieldContext_Provider_promptCaching(ctx, field)
+			case "draining":
+				return ec.fieldContext_Provider_draining(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Provider_createdAt(ctx, field)
 			}
@@ -24634,6 +26433,8 @@ func (ec *executionContext) fieldContext_Mutation_updateProvider(ctx context.Con
 				return ec.fieldContext_Provider_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Provider_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Provider_type(ctx, field)
 			case "baseUrl":
 				return ec.fieldContext_Provider_baseUrl(ctx, field)
 			case "isActive":
@@ -24652,6 +26453,26 @@ func (ec *executionContext) fieldContext_Mutation_updateProvider(ctx context.Con
 				return ec.fieldContext_Provider_defaultProxyId(ctx, field)
 			case "requiresApiKey":
 				return ec.fieldContext_Provider_requiresApiKey(ctx, field)
+			case "deepHealthCheck":
+				return ec.fieldContext_Provider_deepHealthCheck(ctx, field)
+			case "healthCheckModel":
+				return ec.fieldContext_Provider_healthCheckModel(ctx, field)
+			case "defaultMaxTokens":
+				return ec.fieldContext_Provider_defaultMaxTokens(ctx, field)
+			case "maxOutputTokens":
+				return ec.fieldContext_Provider_maxOutputTokens(ctx, field)
+			case "trafficPercentage":
+				return ec.fieldContext_Provider_trafficPercentage(ctx, field)
+			case "tlsInsecureSkipVerify":
+				return ec.fieldContext_Provider_tlsInsecureSkipVerify(ctx, field)
+			case "tlsMinVersion":
+				return ec.fieldContext_Provider_tlsMinVersion(ctx, field)
+			case "tlsCaBundlePath":
+				return ec.fieldContext_Provider_tlsCaBundlePath(ctx, field)
+			case "promptCaching":
+				return ec.fieldContext_Provider_promptCaching(ctx, field)
+			case "draining":
+				return ec.fieldContext_Provider_draining(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Provider_createdAt(ctx, field)
 			}
@@ -24719,6 +26540,8 @@ func (ec *executionContext) fieldContext_Mutation_toggleProvider(ctx context.Con
 				return ec.fieldContext_Provider_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Provider_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Provider_type(ctx, field)
 			case "baseUrl":
 				return ec.fieldContext_Provider_baseUrl(ctx, field)
 			case "isActive":
@@ -24737,6 +26560,26 @@ func (ec *executionContext) fieldContext_Mutation_toggleProvider(ctx context.Con
 				return ec.fieldContext_Provider_defaultProxyId(ctx, field)
 			case "requiresApiKey":
 				return ec.fieldContext_Provider_requiresApiKey(ctx, field)
+			case "deepHealthCheck":
+				return ec.fieldContext_Provider_deepHealthCheck(ctx, field)
+			case "healthCheckModel":
+				return ec.fieldContext_Provider_healthCheckModel(ctx, field)
+			case "defaultMaxTokens":
+				return ec.fieldContext_Provider_defaultMaxTokens(ctx, field)
+			case "maxOutputTokens":
+				return ec.fieldContext_Provider_maxOutputTokens(ctx, field)
+			case "trafficPercentage":
+				return ec.fieldContext_Provider_trafficPercentage(ctx, field)
+			case "tlsInsecureSkipVerify":
+				return ec.fieldContext_Provider_tlsInsecureSkipVerify(ctx, field)
+			case "tlsMinVersion":
+				return ec.fieldContext_Provider_tlsMinVersion(ctx, field)
+			case "tlsCaBundlePath":
+				return ec.fieldContext_Provider_tlsCaBundlePath(ctx, field)
+			case "promptCaching":
+				return ec.fieldContext_Provider_promptCaching(ctx, field)
+			case "draining":
+				return ec.fieldContext_Provider_draining(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Provider_createdAt(ctx, field)
 			}
@@ -24804,6 +26647,8 @@ func (ec *executionContext) fieldContext_Mutation_toggleProviderProxy(ctx contex
 				return ec.fieldContext_Provider_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Provider_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Provider_type(ctx, field)
 			case "baseUrl":
 				return ec.fieldContext_Provider_baseUrl(ctx, field)
 			case "isActive":
@@ -24822,6 +26667,26 @@ func (ec *executionContext) fieldContext_Mutation_toggleProviderProxy(ctx contex
 				return ec.fieldContext_Provider_defaultProxyId(ctx, field)
 			case "requiresApiKey":
 				return ec.fieldContext_Provider_requiresApiKey(ctx, field)
+			case "deepHealthCheck":
+				return ec.fieldContext_Provider_deepHealthCheck(ctx, field)
+			case "healthCheckModel":
+				return ec.fieldContext_Provider_healthCheckModel(ctx, field)
+			case "defaultMaxTokens":
+				return ec.fieldContext_Provider_defaultMaxTokens(ctx, field)
+			case "maxOutputTokens":
+				return ec.fieldContext_Provider_maxOutputTokens(ctx, field)
+			case "trafficPercentage":
+				return ec.fieldContext_Provider_trafficPercentage(ctx, field)
+			case "tlsInsecureSkipVerify":
+				return ec.fieldContext_Provider_tlsInsecureSkipVerify(ctx, field)
+			case "tlsMinVersion":
+				return ec.fieldContext_Provider_tlsMinVersion(ctx, field)
+			case "tlsCaBundlePath":
+				return ec.fieldContext_Provider_tlsCaBundlePath(ctx, field)
+			case "promptCaching":
+				return ec.fieldContext_Provider_promptCaching(ctx, field)
+			case "draining":
+				return ec.fieldContext_Provider_draining(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Provider_createdAt(ctx, field)
 			}
@@ -24903,6 +26768,14 @@ func (ec *executionContext) fieldContext_Mutation_createProviderApiKey(ctx conte
 				return ec.fieldContext_ProviderApiKey_rateLimit(ctx, field)
 			case "usageCount":
 				return ec.fieldContext_ProviderApiKey_usageCount(ctx, field)
+			case "monthlyRequestLimit":
+				return ec.fieldContext_ProviderApiKey_monthlyRequestLimit(ctx, field)
+			case "monthlyBudgetUsd":
+				return ec.fieldContext_ProviderApiKey_monthlyBudgetUsd(ctx, field)
+			case "monthlyRequests":
+				return ec.fieldContext_ProviderApiKey_monthlyRequests(ctx, field)
+			case "monthlyCost":
+				return ec.fieldContext_ProviderApiKey_monthlyCost(ctx, field)
 			case "lastUsedAt":
 				return ec.fieldContext_ProviderApiKey_lastUsedAt(ctx, field)
 			case "createdAt":
@@ -24986,6 +26859,14 @@ func (ec *executionContext) fieldContext_Mutation_updateProviderApiKey(ctx conte
 				return ec.fieldContext_ProviderApiKey_rateLimit(ctx, field)
 			case "usageCount":
 				return ec.fieldContext_ProviderApiKey_usageCount(ctx, field)
+			case "monthlyRequestLimit":
+				return ec.fieldContext_ProviderApiKey_monthlyRequestLimit(ctx, field)
+			case "monthlyBudgetUsd":
+				return ec.fieldContext_ProviderApiKey_monthlyBudgetUsd(ctx, field)
+			case "monthlyRequests":
+				return ec.fieldContext_ProviderApiKey_monthlyRequests(ctx, field)
+			case "monthlyCost":
+				return ec.fieldContext_ProviderApiKey_monthlyCost(ctx, field)
 			case "lastUsedAt":
 				return ec.fieldContext_ProviderApiKey_lastUsedAt(ctx, field)
 			case "createdAt":
@@ -25069,6 +26950,14 @@ func (ec *executionContext) fieldContext_Mutation_toggleProviderApiKey(ctx conte
 				return ec.fieldContext_ProviderApiKey_rateLimit(ctx, field)
 			case "usageCount":
 				return ec.fieldContext_ProviderApiKey_usageCount(ctx, field)
+			case "monthlyRequestLimit":
+				return ec.fieldContext_ProviderApiKey_monthlyRequestLimit(ctx, field)
+			case "monthlyBudgetUsd":
+				return ec.fieldContext_ProviderApiKey_monthlyBudgetUsd(ctx, field)
+			case "monthlyRequests":
+				return ec.fieldContext_ProviderApiKey_monthlyRequests(ctx, field)
+			case "monthlyCost":
+				return ec.fieldContext_ProviderApiKey_monthlyCost(ctx, field)
 			case "lastUsedAt":
 				return ec.fieldContext_ProviderApiKey_lastUsedAt(ctx, field)
 			case "createdAt":
@@ -26289,6 +28178,12 @@ func (ec *executionContext) fieldContext_Mutation_checkProviderHealth(ctx contex
 				return ec.fieldContext_ProviderHealth_successRate(ctx, field)
 			case "errorMessage":
 				return ec.fieldContext_ProviderHealth_errorMessage(ctx, field)
+			case "avgLatencyMs":
+				return ec.fieldContext_ProviderHealth_avgLatencyMs(ctx, field)
+			case "latencyThresholdMs":
+				return ec.fieldContext_ProviderHealth_latencyThresholdMs(ctx, field)
+			case "latencySlaBreached":
+				return ec.fieldContext_ProviderHealth_latencySlaBreached(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ProviderHealth", field.Name)
 		},
@@ -26369,6 +28264,12 @@ func (ec *executionContext) fieldContext_Mutation_checkAllProviderHealth(_ conte
 				return ec.fieldContext_ProviderHealth_successRate(ctx, field)
 			case "errorMessage":
 				return ec.fieldContext_ProviderHealth_errorMessage(ctx, field)
+			case "avgLatencyMs":
+				return ec.fieldContext_ProviderHealth_avgLatencyMs(ctx, field)
+			case "latencyThresholdMs":
+				return ec.fieldContext_ProviderHealth_latencyThresholdMs(ctx, field)
+			case "latencySlaBreached":
+				return ec.fieldContext_ProviderHealth_latencySlaBreached(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ProviderHealth", field.Name)
 		},
@@ -28929,6 +30830,223 @@ func (ec *executionContext) fieldContext_Mutation_deleteRoutingRule(ctx context.
 	return fc, nil
 }
 
+func (ec *executionContext) _Mutation_createFallbackChain(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_createFallbackChain,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().CreateFallbackChain(ctx, fc.Args["input"].(model.CreateFallbackChainInput))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "ADMIN")
+				if err != nil {
+					var zeroVal *model.FallbackChain
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal *model.FallbackChain
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNFallbackChain2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFallbackChain,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_createFallbackChain(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_FallbackChain_id(ctx, field)
+			case "name":
+				return ec.fieldContext_FallbackChain_name(ctx, field)
+			case "description":
+				return ec.fieldContext_FallbackChain_description(ctx, field)
+			case "modelPattern":
+				return ec.fieldContext_FallbackChain_modelPattern(ctx, field)
+			case "providerIds":
+				return ec.fieldContext_FallbackChain_providerIds(ctx, field)
+			case "priority":
+				return ec.fieldContext_FallbackChain_priority(ctx, field)
+			case "isEnabled":
+				return ec.fieldContext_FallbackChain_isEnabled(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_FallbackChain_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_FallbackChain_updatedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FallbackChain", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_createFallbackChain_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_updateFallbackChain(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_updateFallbackChain,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().UpdateFallbackChain(ctx, fc.Args["id"].(string), fc.Args["input"].(model.UpdateFallbackChainInput))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "ADMIN")
+				if err != nil {
+					var zeroVal *model.FallbackChain
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal *model.FallbackChain
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNFallbackChain2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFallbackChain,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_updateFallbackChain(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_FallbackChain_id(ctx, field)
+			case "name":
+				return ec.fieldContext_FallbackChain_name(ctx, field)
+			case "description":
+				return ec.fieldContext_FallbackChain_description(ctx, field)
+			case "modelPattern":
+				return ec.fieldContext_FallbackChain_modelPattern(ctx, field)
+			case "providerIds":
+				return ec.fieldContext_FallbackChain_providerIds(ctx, field)
+			case "priority":
+				return ec.fieldContext_FallbackChain_priority(ctx, field)
+			case "isEnabled":
+				return ec.fieldContext_FallbackChain_isEnabled(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_FallbackChain_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_FallbackChain_updatedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FallbackChain", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_updateFallbackChain_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_deleteFallbackChain(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_deleteFallbackChain,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.Resolvers.Mutation().DeleteFallbackChain(ctx, fc.Args["id"].(string))
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "ADMIN")
+				if err != nil {
+					var zeroVal bool
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal bool
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_deleteFallbackChain(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_deleteFallbackChain_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Mutation_clearSemanticCache(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -31684,6 +33802,35 @@ func (ec *executionContext) fieldContext_Provider_name(_ context.Context, field
 	return fc, nil
 }
 
+func (ec *executionContext) _Provider_type(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_type,
+		func(ctx context.Context) (any, error) {
+			return obj.Type, nil
+		},
+		nil,
+		ec.marshalNString2string,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Provider_baseUrl(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -31945,6 +34092,296 @@ func (ec *executionContext) fieldContext_Provider_requiresApiKey(_ context.Conte
 	return fc, nil
 }
 
+func (ec *executionContext) _Provider_deepHealthCheck(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_deepHealthCheck,
+		func(ctx context.Context) (any, error) {
+			return obj.DeepHealthCheck, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_deepHealthCheck(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_healthCheckModel(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_healthCheckModel,
+		func(ctx context.Context) (any, error) {
+			return obj.HealthCheckModel, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_healthCheckModel(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_defaultMaxTokens(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_defaultMaxTokens,
+		func(ctx context.Context) (any, error) {
+			return obj.DefaultMaxTokens, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_defaultMaxTokens(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_maxOutputTokens(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_maxOutputTokens,
+		func(ctx context.Context) (any, error) {
+			return obj.MaxOutputTokens, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_maxOutputTokens(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_trafficPercentage(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_trafficPercentage,
+		func(ctx context.Context) (any, error) {
+			return obj.TrafficPercentage, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_trafficPercentage(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_tlsInsecureSkipVerify(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_tlsInsecureSkipVerify,
+		func(ctx context.Context) (any, error) {
+			return obj.TLSInsecureSkipVerify, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_tlsInsecureSkipVerify(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_tlsMinVersion(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_tlsMinVersion,
+		func(ctx context.Context) (any, error) {
+			return obj.TLSMinVersion, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_tlsMinVersion(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_tlsCaBundlePath(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_tlsCaBundlePath,
+		func(ctx context.Context) (any, error) {
+			return obj.TLSCaBundlePath, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_tlsCaBundlePath(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_promptCaching(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_promptCaching,
+		func(ctx context.Context) (any, error) {
+			return obj.PromptCaching, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_promptCaching(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Provider_draining(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Provider_draining,
+		func(ctx context.Context) (any, error) {
+			return obj.Draining, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Provider_draining(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Provider",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Provider_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.Provider) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -32235,6 +34672,122 @@ func (ec *executionContext) fieldContext_ProviderApiKey_usageCount(_ context.Con
 	return fc, nil
 }
 
+func (ec *executionContext) _ProviderApiKey_monthlyRequestLimit(ctx context.Context, field graphql.CollectedField, obj *model.ProviderAPIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderApiKey_monthlyRequestLimit,
+		func(ctx context.Context) (any, error) {
+			return obj.MonthlyRequestLimit, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderApiKey_monthlyRequestLimit(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ProviderApiKey_monthlyBudgetUsd(ctx context.Context, field graphql.CollectedField, obj *model.ProviderAPIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderApiKey_monthlyBudgetUsd,
+		func(ctx context.Context) (any, error) {
+			return obj.MonthlyBudgetUsd, nil
+		},
+		nil,
+		ec.marshalNFloat2float64,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderApiKey_monthlyBudgetUsd(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ProviderApiKey_monthlyRequests(ctx context.Context, field graphql.CollectedField, obj *model.ProviderAPIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderApiKey_monthlyRequests,
+		func(ctx context.Context) (any, error) {
+			return obj.MonthlyRequests, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderApiKey_monthlyRequests(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ProviderApiKey_monthlyCost(ctx context.Context, field graphql.CollectedField, obj *model.ProviderAPIKey) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderApiKey_monthlyCost,
+		func(ctx context.Context) (any, error) {
+			return obj.MonthlyCost, nil
+		},
+		nil,
+		ec.marshalNFloat2float64,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderApiKey_monthlyCost(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderApiKey",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _ProviderApiKey_lastUsedAt(ctx context.Context, field graphql.CollectedField, obj *model.ProviderAPIKey) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -32583,6 +35136,93 @@ func (ec *executionContext) fieldContext_ProviderHealth_errorMessage(_ context.C
 	return fc, nil
 }
 
+func (ec *executionContext) _ProviderHealth_avgLatencyMs(ctx context.Context, field graphql.CollectedField, obj *model.ProviderHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderHealth_avgLatencyMs,
+		func(ctx context.Context) (any, error) {
+			return obj.AvgLatencyMs, nil
+		},
+		nil,
+		ec.marshalNFloat2float64,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderHealth_avgLatencyMs(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ProviderHealth_latencyThresholdMs(ctx context.Context, field graphql.CollectedField, obj *model.ProviderHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderHealth_latencyThresholdMs,
+		func(ctx context.Context) (any, error) {
+			return obj.LatencyThresholdMs, nil
+		},
+		nil,
+		ec.marshalNInt2int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderHealth_latencyThresholdMs(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ProviderHealth_latencySlaBreached(ctx context.Context, field graphql.CollectedField, obj *model.ProviderHealth) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderHealth_latencySlaBreached,
+		func(ctx context.Context) (any, error) {
+			return obj.LatencySLABreached, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderHealth_latencySlaBreached(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderHealth",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _ProviderStats_providerId(ctx context.Context, field graphql.CollectedField, obj *model.ProviderStats) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -32786,6 +35426,35 @@ func (ec *executionContext) fieldContext_ProviderStats_totalCost(_ context.Conte
 	return fc, nil
 }
 
+func (ec *executionContext) _ProviderStats_trafficShare(ctx context.Context, field graphql.CollectedField, obj *model.ProviderStats) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ProviderStats_trafficShare,
+		func(ctx context.Context) (any, error) {
+			return obj.TrafficShare, nil
+		},
+		nil,
+		ec.marshalNFloat2float64,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ProviderStats_trafficShare(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ProviderStats",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _ProviderUsage_providerId(ctx context.Context, field graphql.CollectedField, obj *model.ProviderUsage) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -34208,6 +36877,20 @@ func (ec *executionContext) fieldContext_Query_myApiKeys(ctx context.Context, fi
 				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
 			case "dailyLimit":
 				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
 			case "expiresAt":
 				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
 			case "lastUsedAt":
@@ -35299,6 +37982,8 @@ func (ec *executionContext) fieldContext_Query_providerStats(ctx context.Context
 				return ec.fieldContext_ProviderStats_avgLatencyMs(ctx, field)
 			case "totalCost":
 				return ec.fieldContext_ProviderStats_totalCost(ctx, field)
+			case "trafficShare":
+				return ec.fieldContext_ProviderStats_trafficShare(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ProviderStats", field.Name)
 		},
@@ -35476,6 +38161,8 @@ func (ec *executionContext) fieldContext_Query_adminDashboard(_ context.Context,
 				return ec.fieldContext_AdminDashboard_mcpCallCount(ctx, field)
 			case "mcpErrorCount":
 				return ec.fieldContext_AdminDashboard_mcpErrorCount(ctx, field)
+			case "misconfiguredProviders":
+				return ec.fieldContext_AdminDashboard_misconfiguredProviders(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type AdminDashboard", field.Name)
 		},
@@ -36037,6 +38724,20 @@ func (ec *executionContext) fieldContext_Query_userApiKeys(ctx context.Context,
 				return ec.fieldContext_ApiKey_tokenLimit(ctx, field)
 			case "dailyLimit":
 				return ec.fieldContext_ApiKey_dailyLimit(ctx, field)
+			case "allowedCidrs":
+				return ec.fieldContext_ApiKey_allowedCidrs(ctx, field)
+			case "spendThresholds":
+				return ec.fieldContext_ApiKey_spendThresholds(ctx, field)
+			case "spendWebhookUrl":
+				return ec.fieldContext_ApiKey_spendWebhookUrl(ctx, field)
+			case "maxRequestCostUsd":
+				return ec.fieldContext_ApiKey_maxRequestCostUsd(ctx, field)
+			case "maxMessages":
+				return ec.fieldContext_ApiKey_maxMessages(ctx, field)
+			case "maxMessageChars":
+				return ec.fieldContext_ApiKey_maxMessageChars(ctx, field)
+			case "streamBudgetCutoff":
+				return ec.fieldContext_ApiKey_streamBudgetCutoff(ctx, field)
 			case "expiresAt":
 				return ec.fieldContext_ApiKey_expiresAt(ctx, field)
 			case "lastUsedAt":
@@ -36107,6 +38808,8 @@ func (ec *executionContext) fieldContext_Query_providers(_ context.Context, fiel
 				return ec.fieldContext_Provider_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Provider_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Provider_type(ctx, field)
 			case "baseUrl":
 				return ec.fieldContext_Provider_baseUrl(ctx, field)
 			case "isActive":
@@ -36125,6 +38828,26 @@ func (ec *executionContext) fieldContext_Query_providers(_ context.Context, fiel
 				return ec.fieldContext_Provider_defaultProxyId(ctx, field)
 			case "requiresApiKey":
 				return ec.fieldContext_Provider_requiresApiKey(ctx, field)
+			case "deepHealthCheck":
+				return ec.fieldContext_Provider_deepHealthCheck(ctx, field)
+			case "healthCheckModel":
+				return ec.fieldContext_Provider_healthCheckModel(ctx, field)
+			case "defaultMaxTokens":
+				return ec.fieldContext_Provider_defaultMaxTokens(ctx, field)
+			case "maxOutputTokens":
+				return ec.fieldContext_Provider_maxOutputTokens(ctx, field)
+			case "trafficPercentage":
+				return ec.fieldContext_Provider_trafficPercentage(ctx, field)
+			case "tlsInsecureSkipVerify":
+				return ec.fieldContext_Provider_tlsInsecureSkipVerify(ctx, field)
+			case "tlsMinVersion":
+				return ec.fieldContext_Provider_tlsMinVersion(ctx, field)
+			case "tlsCaBundlePath":
+				return ec.fieldContext_Provider_tlsCaBundlePath(ctx, field)
+			case "promptCaching":
+				return ec.fieldContext_Provider_promptCaching(ctx, field)
+			case "draining":
+				return ec.fieldContext_Provider_draining(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Provider_createdAt(ctx, field)
 			}
@@ -36195,6 +38918,14 @@ func (ec *executionContext) fieldContext_Query_providerApiKeys(ctx context.Conte
 				return ec.fieldContext_ProviderApiKey_rateLimit(ctx, field)
 			case "usageCount":
 				return ec.fieldContext_ProviderApiKey_usageCount(ctx, field)
+			case "monthlyRequestLimit":
+				return ec.fieldContext_ProviderApiKey_monthlyRequestLimit(ctx, field)
+			case "monthlyBudgetUsd":
+				return ec.fieldContext_ProviderApiKey_monthlyBudgetUsd(ctx, field)
+			case "monthlyRequests":
+				return ec.fieldContext_ProviderApiKey_monthlyRequests(ctx, field)
+			case "monthlyCost":
+				return ec.fieldContext_ProviderApiKey_monthlyCost(ctx, field)
 			case "lastUsedAt":
 				return ec.fieldContext_ProviderApiKey_lastUsedAt(ctx, field)
 			case "createdAt":
@@ -36365,6 +39096,12 @@ func (ec *executionContext) fieldContext_Query_providerHealth(ctx context.Contex
 				return ec.fieldContext_ProviderHealth_successRate(ctx, field)
 			case "errorMessage":
 				return ec.fieldContext_ProviderHealth_errorMessage(ctx, field)
+			case "avgLatencyMs":
+				return ec.fieldContext_ProviderHealth_avgLatencyMs(ctx, field)
+			case "latencyThresholdMs":
+				return ec.fieldContext_ProviderHealth_latencyThresholdMs(ctx, field)
+			case "latencySlaBreached":
+				return ec.fieldContext_ProviderHealth_latencySlaBreached(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ProviderHealth", field.Name)
 		},
@@ -36802,6 +39539,12 @@ func (ec *executionContext) fieldContext_Query_healthProviders(_ context.Context
 				return ec.fieldContext_ProviderHealth_successRate(ctx, field)
 			case "errorMessage":
 				return ec.fieldContext_ProviderHealth_errorMessage(ctx, field)
+			case "avgLatencyMs":
+				return ec.fieldContext_ProviderHealth_avgLatencyMs(ctx, field)
+			case "latencyThresholdMs":
+				return ec.fieldContext_ProviderHealth_latencyThresholdMs(ctx, field)
+			case "latencySlaBreached":
+				return ec.fieldContext_ProviderHealth_latencySlaBreached(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ProviderHealth", field.Name)
 		},
@@ -36861,6 +39604,8 @@ func (ec *executionContext) fieldContext_Query_healthHistory(_ context.Context,
 				return ec.fieldContext_HealthEvent_status(ctx, field)
 			case "message":
 				return ec.fieldContext_HealthEvent_message(ctx, field)
+			case "checkMode":
+				return ec.fieldContext_HealthEvent_checkMode(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_HealthEvent_createdAt(ctx, field)
 			}
@@ -37928,6 +40673,73 @@ func (ec *executionContext) fieldContext_Query_routingRules(ctx context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _Query_fallbackChains(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_fallbackChains,
+		func(ctx context.Context) (any, error) {
+			return ec.Resolvers.Query().FallbackChains(ctx)
+		},
+		func(ctx context.Context, next graphql.Resolver) graphql.Resolver {
+			directive0 := next
+
+			directive1 := func(ctx context.Context) (any, error) {
+				role, err := ec.unmarshalORole2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐRole(ctx, "ADMIN")
+				if err != nil {
+					var zeroVal []*model.FallbackChain
+					return zeroVal, err
+				}
+				if ec.Directives.Auth == nil {
+					var zeroVal []*model.FallbackChain
+					return zeroVal, errors.New("directive auth is not implemented")
+				}
+				return ec.Directives.Auth(ctx, nil, directive0, role)
+			}
+
+			next = directive1
+			return next
+		},
+		ec.marshalNFallbackChain2ᚕᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFallbackChainᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_fallbackChains(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_FallbackChain_id(ctx, field)
+			case "name":
+				return ec.fieldContext_FallbackChain_name(ctx, field)
+			case "description":
+				return ec.fieldContext_FallbackChain_description(ctx, field)
+			case "modelPattern":
+				return ec.fieldContext_FallbackChain_modelPattern(ctx, field)
+			case "providerIds":
+				return ec.fieldContext_FallbackChain_providerIds(ctx, field)
+			case "priority":
+				return ec.fieldContext_FallbackChain_priority(ctx, field)
+			case "isEnabled":
+				return ec.fieldContext_FallbackChain_isEnabled(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_FallbackChain_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_FallbackChain_updatedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FallbackChain", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query_promptTemplates(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -40891,6 +43703,8 @@ func (ec *executionContext) fieldContext_RoutingRule_targetProvider(_ context.Co
 				return ec.fieldContext_Provider_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Provider_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Provider_type(ctx, field)
 			case "baseUrl":
 				return ec.fieldContext_Provider_baseUrl(ctx, field)
 			case "isActive":
@@ -40909,6 +43723,26 @@ func (ec *executionContext) fieldContext_RoutingRule_targetProvider(_ context.Co
 				return ec.fieldContext_Provider_defaultProxyId(ctx, field)
 			case "requiresApiKey":
 				return ec.fieldContext_Provider_requiresApiKey(ctx, field)
+			case "deepHealthCheck":
+				return ec.fieldContext_Provider_deepHealthCheck(ctx, field)
+			case "healthCheckModel":
+				return ec.fieldContext_Provider_healthCheckModel(ctx, field)
+			case "defaultMaxTokens":
+				return ec.fieldContext_Provider_defaultMaxTokens(ctx, field)
+			case "maxOutputTokens":
+				return ec.fieldContext_Provider_maxOutputTokens(ctx, field)
+			case "trafficPercentage":
+				return ec.fieldContext_Provider_trafficPercentage(ctx, field)
+			case "tlsInsecureSkipVerify":
+				return ec.fieldContext_Provider_tlsInsecureSkipVerify(ctx, field)
+			case "tlsMinVersion":
+				return ec.fieldContext_Provider_tlsMinVersion(ctx, field)
+			case "tlsCaBundlePath":
+				return ec.fieldContext_Provider_tlsCaBundlePath(ctx, field)
+			case "promptCaching":
+				return ec.fieldContext_Provider_promptCaching(ctx, field)
+			case "draining":
+				return ec.fieldContext_Provider_draining(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Provider_createdAt(ctx, field)
 			}
@@ -40946,6 +43780,8 @@ func (ec *executionContext) fieldContext_RoutingRule_fallbackProvider(_ context.
 				return ec.fieldContext_Provider_id(ctx, field)
 			case "name":
 				return ec.fieldContext_Provider_name(ctx, field)
+			case "type":
+				return ec.fieldContext_Provider_type(ctx, field)
 			case "baseUrl":
 				return ec.fieldContext_Provider_baseUrl(ctx, field)
 			case "isActive":
@@ -40964,6 +43800,26 @@ func (ec *executionContext) fieldContext_RoutingRule_fallbackProvider(_ context.
 				return ec.fieldContext_Provider_defaultProxyId(ctx, field)
 			case "requiresApiKey":
 				return ec.fieldContext_Provider_requiresApiKey(ctx, field)
+			case "deepHealthCheck":
+				return ec.fieldContext_Provider_deepHealthCheck(ctx, field)
+			case "healthCheckModel":
+				return ec.fieldContext_Provider_healthCheckModel(ctx, field)
+			case "defaultMaxTokens":
+				return ec.fieldContext_Provider_defaultMaxTokens(ctx, field)
+			case "maxOutputTokens":
+				return ec.fieldContext_Provider_maxOutputTokens(ctx, field)
+			case "trafficPercentage":
+				return ec.fieldContext_Provider_trafficPercentage(ctx, field)
+			case "tlsInsecureSkipVerify":
+				return ec.fieldContext_Provider_tlsInsecureSkipVerify(ctx, field)
+			case "tlsMinVersion":
+				return ec.fieldContext_Provider_tlsMinVersion(ctx, field)
+			case "tlsCaBundlePath":
+				return ec.fieldContext_Provider_tlsCaBundlePath(ctx, field)
+			case "promptCaching":
+				return ec.fieldContext_Provider_promptCaching(ctx, field)
+			case "draining":
+				return ec.fieldContext_Provider_draining(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Provider_createdAt(ctx, field)
 			}
@@ -47797,6 +50653,71 @@ func (ec *executionContext) unmarshalInputCouponInput(ctx context.Context, obj a
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputCreateFallbackChainInput(ctx context.Context, obj any) (model.CreateFallbackChainInput, error) {
+	var it model.CreateFallbackChainInput
+	if obj == nil {
+		return it, nil
+	}
+
+	asMap := map[string]any{}
+	for k, v := range obj.(map[string]any) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"name", "description", "modelPattern", "providerIds", "priority", "isEnabled"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "name":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Name = data
+		case "description":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Description = data
+		case "modelPattern":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("modelPattern"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ModelPattern = data
+		case "providerIds":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("providerIds"))
+			data, err := ec.unmarshalNID2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ProviderIds = data
+		case "priority":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("priority"))
+			data, err := ec.unmarshalNInt2int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Priority = data
+		case "isEnabled":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("isEnabled"))
+			data, err := ec.unmarshalNBoolean2bool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.IsEnabled = data
+		}
+	}
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputCreateIdentityProviderInput(ctx context.Context, obj any) (model.CreateIdentityProviderInput, error) {
 	var it model.CreateIdentityProviderInput
 	if obj == nil {
@@ -47922,7 +50843,7 @@ func (ec *executionContext) unmarshalInputCreateProviderInput(ctx context.Contex
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"name", "baseUrl", "isActive", "priority", "weight", "maxRetries", "timeout", "useProxy", "requiresApiKey"}
+	fieldsInOrder := [...]string{"name", "type", "baseUrl", "isActive", "priority", "weight", "maxRetries", "timeout", "useProxy", "requiresApiKey", "deepHealthCheck", "healthCheckModel", "defaultMaxTokens", "maxOutputTokens"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -47936,6 +50857,13 @@ func (ec *executionContext) unmarshalInputCreateProviderInput(ctx context.Contex
 				return it, err
 			}
 			it.Name = data
+		case "type":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Type = data
 		case "baseUrl":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("baseUrl"))
 			data, err := ec.unmarshalNString2string(ctx, v)
@@ -47992,6 +50920,34 @@ func (ec *executionContext) unmarshalInputCreateProviderInput(ctx context.Contex
 				return it, err
 			}
 			it.RequiresAPIKey = data
+		case "deepHealthCheck":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("deepHealthCheck"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.DeepHealthCheck = data
+		case "healthCheckModel":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("healthCheckModel"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.HealthCheckModel = data
+		case "defaultMaxTokens":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("defaultMaxTokens"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.DefaultMaxTokens = data
+		case "maxOutputTokens":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("maxOutputTokens"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MaxOutputTokens = data
 		}
 	}
 	return it, nil
@@ -48827,7 +51783,7 @@ func (ec *executionContext) unmarshalInputProviderApiKeyInput(ctx context.Contex
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"apiKey", "alias", "priority", "weight", "rateLimit"}
+	fieldsInOrder := [...]string{"apiKey", "alias", "priority", "weight", "rateLimit", "monthlyRequestLimit", "monthlyBudgetUsd"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -48869,6 +51825,20 @@ func (ec *executionContext) unmarshalInputProviderApiKeyInput(ctx context.Contex
 				return it, err
 			}
 			it.RateLimit = data
+		case "monthlyRequestLimit":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("monthlyRequestLimit"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MonthlyRequestLimit = data
+		case "monthlyBudgetUsd":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("monthlyBudgetUsd"))
+			data, err := ec.unmarshalOFloat2ᚖfloat64(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MonthlyBudgetUsd = data
 		}
 	}
 	return it, nil
@@ -48885,7 +51855,7 @@ func (ec *executionContext) unmarshalInputProviderInput(ctx context.Context, obj
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"name", "baseUrl", "isActive", "priority", "weight", "maxRetries", "timeout", "useProxy", "defaultProxyId", "requiresApiKey"}
+	fieldsInOrder := [...]string{"name", "type", "baseUrl", "isActive", "priority", "weight", "maxRetries", "timeout", "useProxy", "defaultProxyId", "requiresApiKey", "deepHealthCheck", "healthCheckModel", "defaultMaxTokens", "maxOutputTokens", "trafficPercentage", "tlsInsecureSkipVerify", "tlsMinVersion", "tlsCaBundlePath", "promptCaching"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -48899,6 +51869,13 @@ func (ec *executionContext) unmarshalInputProviderInput(ctx context.Context, obj
 				return it, err
 			}
 			it.Name = data
+		case "type":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("type"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Type = data
 		case "baseUrl":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("baseUrl"))
 			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
@@ -48962,6 +51939,69 @@ func (ec *executionContext) unmarshalInputProviderInput(ctx context.Context, obj
 				return it, err
 			}
 			it.RequiresAPIKey = data
+		case "deepHealthCheck":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("deepHealthCheck"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.DeepHealthCheck = data
+		case "healthCheckModel":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("healthCheckModel"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.HealthCheckModel = data
+		case "defaultMaxTokens":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("defaultMaxTokens"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.DefaultMaxTokens = data
+		case "maxOutputTokens":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("maxOutputTokens"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MaxOutputTokens = data
+		case "trafficPercentage":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("trafficPercentage"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.TrafficPercentage = data
+		case "tlsInsecureSkipVerify":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("tlsInsecureSkipVerify"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.TLSInsecureSkipVerify = data
+		case "tlsMinVersion":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("tlsMinVersion"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.TLSMinVersion = data
+		case "tlsCaBundlePath":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("tlsCaBundlePath"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.TLSCaBundlePath = data
+		case "promptCaching":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("promptCaching"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.PromptCaching = data
 		}
 	}
 	return it, nil
@@ -49287,6 +52327,71 @@ func (ec *executionContext) unmarshalInputUpdateDlpConfigInput(ctx context.Conte
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputUpdateFallbackChainInput(ctx context.Context, obj any) (model.UpdateFallbackChainInput, error) {
+	var it model.UpdateFallbackChainInput
+	if obj == nil {
+		return it, nil
+	}
+
+	asMap := map[string]any{}
+	for k, v := range obj.(map[string]any) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"name", "description", "modelPattern", "providerIds", "priority", "isEnabled"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "name":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Name = data
+		case "description":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("description"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Description = data
+		case "modelPattern":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("modelPattern"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ModelPattern = data
+		case "providerIds":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("providerIds"))
+			data, err := ec.unmarshalOID2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.ProviderIds = data
+		case "priority":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("priority"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Priority = data
+		case "isEnabled":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("isEnabled"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.IsEnabled = data
+		}
+	}
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputUpdateIdentityProviderInput(ctx context.Context, obj any) (model.UpdateIdentityProviderInput, error) {
 	var it model.UpdateIdentityProviderInput
 	if obj == nil {
@@ -49574,7 +52679,7 @@ func (ec *executionContext) unmarshalInputUpdateProviderApiKeyInput(ctx context.
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"priority", "weight", "rateLimit"}
+	fieldsInOrder := [...]string{"priority", "weight", "rateLimit", "monthlyRequestLimit", "monthlyBudgetUsd"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -49602,6 +52707,20 @@ func (ec *executionContext) unmarshalInputUpdateProviderApiKeyInput(ctx context.
 				return it, err
 			}
 			it.RateLimit = data
+		case "monthlyRequestLimit":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("monthlyRequestLimit"))
+			data, err := ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MonthlyRequestLimit = data
+		case "monthlyBudgetUsd":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("monthlyBudgetUsd"))
+			data, err := ec.unmarshalOFloat2ᚖfloat64(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MonthlyBudgetUsd = data
 		}
 	}
 	return it, nil
@@ -49859,6 +52978,11 @@ func (ec *executionContext) _AdminDashboard(ctx context.Context, sel ast.Selecti
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "misconfiguredProviders":
+			out.Values[i] = ec._AdminDashboard_misconfiguredProviders(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -50325,6 +53449,38 @@ func (ec *executionContext) _ApiKey(ctx context.Context, sel ast.SelectionSet, o
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "allowedCidrs":
+			out.Values[i] = ec._ApiKey_allowedCidrs(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "spendThresholds":
+			out.Values[i] = ec._ApiKey_spendThresholds(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "spendWebhookUrl":
+			out.Values[i] = ec._ApiKey_spendWebhookUrl(ctx, field, obj)
+		case "maxRequestCostUsd":
+			out.Values[i] = ec._ApiKey_maxRequestCostUsd(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "maxMessages":
+			out.Values[i] = ec._ApiKey_maxMessages(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "maxMessageChars":
+			out.Values[i] = ec._ApiKey_maxMessageChars(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "streamBudgetCutoff":
+			out.Values[i] = ec._ApiKey_streamBudgetCutoff(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "expiresAt":
 			out.Values[i] = ec._ApiKey_expiresAt(ctx, field, obj)
 		case "lastUsedAt":
@@ -52025,6 +55181,85 @@ func (ec *executionContext) _ErrorLogConnection(ctx context.Context, sel ast.Sel
 	return out
 }
 
+var fallbackChainImplementors = []string{"FallbackChain"}
+
+func (ec *executionContext) _FallbackChain(ctx context.Context, sel ast.SelectionSet, obj *model.FallbackChain) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, fallbackChainImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("FallbackChain")
+		case "id":
+			out.Values[i] = ec._FallbackChain_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "name":
+			out.Values[i] = ec._FallbackChain_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "description":
+			out.Values[i] = ec._FallbackChain_description(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "modelPattern":
+			out.Values[i] = ec._FallbackChain_modelPattern(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "providerIds":
+			out.Values[i] = ec._FallbackChain_providerIds(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "priority":
+			out.Values[i] = ec._FallbackChain_priority(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "isEnabled":
+			out.Values[i] = ec._FallbackChain_isEnabled(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "createdAt":
+			out.Values[i] = ec._FallbackChain_createdAt(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "updatedAt":
+			out.Values[i] = ec._FallbackChain_updatedAt(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.Deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.ProcessDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var featureGateImplementors = []string{"FeatureGate"}
 
 func (ec *executionContext) _FeatureGate(ctx context.Context, sel ast.SelectionSet, obj *model.FeatureGate) graphql.Marshaler {
@@ -52161,6 +55396,8 @@ func (ec *executionContext) _HealthEvent(ctx context.Context, sel ast.SelectionS
 			}
 		case "message":
 			out.Values[i] = ec._HealthEvent_message(ctx, field, obj)
+		case "checkMode":
+			out.Values[i] = ec._HealthEvent_checkMode(ctx, field, obj)
 		case "createdAt":
 			out.Values[i] = ec._HealthEvent_createdAt(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -52687,29 +55924,78 @@ func (ec *executionContext) _McpTool(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var mfaSecretInfoImplementors = []string{"MfaSecretInfo"}
+var mfaSecretInfoImplementors = []string{"MfaSecretInfo"}
+
+func (ec *executionContext) _MfaSecretInfo(ctx context.Context, sel ast.SelectionSet, obj *model.MfaSecretInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, mfaSecretInfoImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("MfaSecretInfo")
+		case "secret":
+			out.Values[i] = ec._MfaSecretInfo_secret(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "qrCodeUrl":
+			out.Values[i] = ec._MfaSecretInfo_qrCodeUrl(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "backupCodes":
+			out.Values[i] = ec._MfaSecretInfo_backupCodes(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.Deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.ProcessDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var misconfiguredProviderImplementors = []string{"MisconfiguredProvider"}
 
-func (ec *executionContext) _MfaSecretInfo(ctx context.Context, sel ast.SelectionSet, obj *model.MfaSecretInfo) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, mfaSecretInfoImplementors)
+func (ec *executionContext) _MisconfiguredProvider(ctx context.Context, sel ast.SelectionSet, obj *model.MisconfiguredProvider) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, misconfiguredProviderImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("MfaSecretInfo")
-		case "secret":
-			out.Values[i] = ec._MfaSecretInfo_secret(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("MisconfiguredProvider")
+		case "id":
+			out.Values[i] = ec._MisconfiguredProvider_id(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "qrCodeUrl":
-			out.Values[i] = ec._MfaSecretInfo_qrCodeUrl(ctx, field, obj)
+		case "name":
+			out.Values[i] = ec._MisconfiguredProvider_name(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "backupCodes":
-			out.Values[i] = ec._MfaSecretInfo_backupCodes(ctx, field, obj)
+		case "issue":
+			out.Values[i] = ec._MisconfiguredProvider_issue(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
@@ -53030,6 +56316,41 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "setApiKeyAllowedCidrs":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_setApiKeyAllowedCidrs(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "setApiKeySpendAlerts":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_setApiKeySpendAlerts(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "setApiKeyMaxRequestCost":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_setApiKeyMaxRequestCost(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "setApiKeyMessageLimits":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_setApiKeyMessageLimits(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "setApiKeyStreamBudgetCutoff":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_setApiKeyStreamBudgetCutoff(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "updateProject":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
 				return ec._Mutation_updateProject(ctx, field)
@@ -53576,6 +56897,27 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "createFallbackChain":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_createFallbackChain(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "updateFallbackChain":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_updateFallbackChain(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "deleteFallbackChain":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_deleteFallbackChain(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "clearSemanticCache":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
 				return ec._Mutation_clearSemanticCache(ctx, field)
@@ -54287,6 +57629,11 @@ func (ec *executionContext) _Provider(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "type":
+			out.Values[i] = ec._Provider_type(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "baseUrl":
 			out.Values[i] = ec._Provider_baseUrl(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -54329,6 +57676,47 @@ func (ec *executionContext) _Provider(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "deepHealthCheck":
+			out.Values[i] = ec._Provider_deepHealthCheck(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "healthCheckModel":
+			out.Values[i] = ec._Provider_healthCheckModel(ctx, field, obj)
+		case "defaultMaxTokens":
+			out.Values[i] = ec._Provider_defaultMaxTokens(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "maxOutputTokens":
+			out.Values[i] = ec._Provider_maxOutputTokens(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "trafficPercentage":
+			out.Values[i] = ec._Provider_trafficPercentage(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "tlsInsecureSkipVerify":
+			out.Values[i] = ec._Provider_tlsInsecureSkipVerify(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "tlsMinVersion":
+			out.Values[i] = ec._Provider_tlsMinVersion(ctx, field, obj)
+		case "tlsCaBundlePath":
+			out.Values[i] = ec._Provider_tlsCaBundlePath(ctx, field, obj)
+		case "promptCaching":
+			out.Values[i] = ec._Provider_promptCaching(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "draining":
+			out.Values[i] = ec._Provider_draining(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "createdAt":
 			out.Values[i] = ec._Provider_createdAt(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
@@ -54413,6 +57801,26 @@ func (ec *executionContext) _ProviderApiKey(ctx context.Context, sel ast.Selecti
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "monthlyRequestLimit":
+			out.Values[i] = ec._ProviderApiKey_monthlyRequestLimit(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "monthlyBudgetUsd":
+			out.Values[i] = ec._ProviderApiKey_monthlyBudgetUsd(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "monthlyRequests":
+			out.Values[i] = ec._ProviderApiKey_monthlyRequests(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "monthlyCost":
+			out.Values[i] = ec._ProviderApiKey_monthlyCost(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		case "lastUsedAt":
 			out.Values[i] = ec._ProviderApiKey_lastUsedAt(ctx, field, obj)
 		case "createdAt":
@@ -54498,6 +57906,21 @@ func (ec *executionContext) _ProviderHealth(ctx context.Context, sel ast.Selecti
 			}
 		case "errorMessage":
 			out.Values[i] = ec._ProviderHealth_errorMessage(ctx, field, obj)
+		case "avgLatencyMs":
+			out.Values[i] = ec._ProviderHealth_avgLatencyMs(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "latencyThresholdMs":
+			out.Values[i] = ec._ProviderHealth_latencyThresholdMs(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "latencySlaBreached":
+			out.Values[i] = ec._ProviderHealth_latencySlaBreached(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -54567,6 +57990,11 @@ func (ec *executionContext) _ProviderStats(ctx context.Context, sel ast.Selectio
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "trafficShare":
+			out.Values[i] = ec._ProviderStats_trafficShare(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -56200,6 +59628,28 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "fallbackChains":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_fallbackChains(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "promptTemplates":
 			field := field
@@ -59612,6 +63062,11 @@ func (ec *executionContext) unmarshalNCouponInput2llmᚑrouterᚑplatformᚋinte
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalNCreateFallbackChainInput2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐCreateFallbackChainInput(ctx context.Context, v any) (model.CreateFallbackChainInput, error) {
+	res, err := ec.unmarshalInputCreateFallbackChainInput(ctx, v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) unmarshalNCreateIdentityProviderInput2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐCreateIdentityProviderInput(ctx context.Context, v any) (model.CreateIdentityProviderInput, error) {
 	res, err := ec.unmarshalInputCreateIdentityProviderInput(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -59842,6 +63297,36 @@ func (ec *executionContext) marshalNErrorLogConnection2ᚖllmᚑrouterᚑplatfor
 	return ec._ErrorLogConnection(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNFallbackChain2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFallbackChain(ctx context.Context, sel ast.SelectionSet, v model.FallbackChain) graphql.Marshaler {
+	return ec._FallbackChain(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNFallbackChain2ᚕᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFallbackChainᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.FallbackChain) graphql.Marshaler {
+	ret := graphql.MarshalSliceConcurrently(ctx, len(v), 0, false, func(ctx context.Context, i int) graphql.Marshaler {
+		fc := graphql.GetFieldContext(ctx)
+		fc.Result = &v[i]
+		return ec.marshalNFallbackChain2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFallbackChain(ctx, sel, v[i])
+	})
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNFallbackChain2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFallbackChain(ctx context.Context, sel ast.SelectionSet, v *model.FallbackChain) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._FallbackChain(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNFeatureGate2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐFeatureGate(ctx context.Context, sel ast.SelectionSet, v model.FeatureGate) graphql.Marshaler {
 	return ec._FeatureGate(ctx, sel, &v)
 }
@@ -59888,6 +63373,36 @@ func (ec *executionContext) marshalNFloat2float64(ctx context.Context, sel ast.S
 	return res
 }
 
+func (ec *executionContext) unmarshalNFloat2ᚕfloat64ᚄ(ctx context.Context, v any) ([]float64, error) {
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]float64, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNFloat2float64(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalNFloat2ᚕfloat64ᚄ(ctx context.Context, sel ast.SelectionSet, v []float64) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNFloat2float64(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) unmarshalNGenerateRedeemCodesInput2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐGenerateRedeemCodesInput(ctx context.Context, v any) (model.GenerateRedeemCodesInput, error) {
 	res, err := ec.unmarshalInputGenerateRedeemCodesInput(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -59949,6 +63464,36 @@ func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.Selec
 	return res
 }
 
+func (ec *executionContext) unmarshalNID2ᚕstringᚄ(ctx context.Context, v any) ([]string, error) {
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]string, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNID2string(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalNID2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNID2string(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) marshalNIdentityProvider2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐIdentityProvider(ctx context.Context, sel ast.SelectionSet, v model.IdentityProvider) graphql.Marshaler {
 	return ec._IdentityProvider(ctx, sel, &v)
 }
@@ -60197,6 +63742,32 @@ func (ec *executionContext) marshalNMfaSecretInfo2ᚖllmᚑrouterᚑplatformᚋi
 	return ec._MfaSecretInfo(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNMisconfiguredProvider2ᚕᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐMisconfiguredProviderᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.MisconfiguredProvider) graphql.Marshaler {
+	ret := graphql.MarshalSliceConcurrently(ctx, len(v), 0, false, func(ctx context.Context, i int) graphql.Marshaler {
+		fc := graphql.GetFieldContext(ctx)
+		fc.Result = &v[i]
+		return ec.marshalNMisconfiguredProvider2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐMisconfiguredProvider(ctx, sel, v[i])
+	})
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNMisconfiguredProvider2ᚖllmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐMisconfiguredProvider(ctx context.Context, sel ast.SelectionSet, v *model.MisconfiguredProvider) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._MisconfiguredProvider(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNModel2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐModel(ctx context.Context, sel ast.SelectionSet, v model.Model) graphql.Marshaler {
 	return ec._Model(ctx, sel, &v)
 }
@@ -61196,6 +64767,11 @@ func (ec *executionContext) unmarshalNUpdateDlpConfigInput2llmᚑrouterᚑplatfo
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalNUpdateFallbackChainInput2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐUpdateFallbackChainInput(ctx context.Context, v any) (model.UpdateFallbackChainInput, error) {
+	res, err := ec.unmarshalInputUpdateFallbackChainInput(ctx, v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) unmarshalNUpdateIdentityProviderInput2llmᚑrouterᚑplatformᚋinternalᚋgraphqlᚋmodelᚐUpdateIdentityProviderInput(ctx context.Context, v any) (model.UpdateIdentityProviderInput, error) {
 	res, err := ec.unmarshalInputUpdateIdentityProviderInput(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -61731,6 +65307,42 @@ func (ec *executionContext) marshalOFloat2ᚖfloat64(ctx context.Context, sel as
 	return res
 }
 
+func (ec *executionContext) unmarshalOID2ᚕstringᚄ(ctx context.Context, v any) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]string, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNID2string(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalOID2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNID2string(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) unmarshalOID2ᚖstring(ctx context.Context, v any) (*string, error) {
 	if v == nil {
 		return nil, nil
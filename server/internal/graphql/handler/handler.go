@@ -222,6 +222,7 @@ func setupErrorMasking(srv *handler.Server, logger *zap.Logger) {
 			"rate limit exceeded: try again later":  true,
 			"forbidden: access denied":              true,
 			"account not found":                     true,
+			"provider name already exists":          true,
 			"too many failed login attempts, please try again later": true,
 		}
 		if clientErrors[msg] {
@@ -11,28 +11,29 @@ import (
 )
 
 type AdminDashboard struct {
-	TotalUsers       int     `json:"totalUsers"`
-	ActiveUsersToday int     `json:"activeUsersToday"`
-	ActiveUsersMonth int     `json:"activeUsersMonth"`
-	TotalRevenue     float64 `json:"totalRevenue"`
-	RevenueThisMonth float64 `json:"revenueThisMonth"`
-	TotalRequests    int     `json:"totalRequests"`
-	RequestsToday    int     `json:"requestsToday"`
-	TotalTokens      int     `json:"totalTokens"`
-	TokensToday      int     `json:"tokensToday"`
-	TotalCost        float64 `json:"totalCost"`
-	CostToday        float64 `json:"costToday"`
-	SuccessRate      float64 `json:"successRate"`
-	ErrorCount       int     `json:"errorCount"`
-	AvgLatencyMs     float64 `json:"avgLatencyMs"`
-	ActiveProviders  int     `json:"activeProviders"`
-	TotalProviders   int     `json:"totalProviders"`
-	ActiveProxies    int     `json:"activeProxies"`
-	TotalProxies     int     `json:"totalProxies"`
-	APIKeysTotal     int     `json:"apiKeysTotal"`
-	APIKeysHealthy   int     `json:"apiKeysHealthy"`
-	McpCallCount     int     `json:"mcpCallCount"`
-	McpErrorCount    int     `json:"mcpErrorCount"`
+	TotalUsers             int                      `json:"totalUsers"`
+	ActiveUsersToday       int                      `json:"activeUsersToday"`
+	ActiveUsersMonth       int                      `json:"activeUsersMonth"`
+	TotalRevenue           float64                  `json:"totalRevenue"`
+	RevenueThisMonth       float64                  `json:"revenueThisMonth"`
+	TotalRequests          int                      `json:"totalRequests"`
+	RequestsToday          int                      `json:"requestsToday"`
+	TotalTokens            int                      `json:"totalTokens"`
+	TokensToday            int                      `json:"tokensToday"`
+	TotalCost              float64                  `json:"totalCost"`
+	CostToday              float64                  `json:"costToday"`
+	SuccessRate            float64                  `json:"successRate"`
+	ErrorCount             int                      `json:"errorCount"`
+	AvgLatencyMs           float64                  `json:"avgLatencyMs"`
+	ActiveProviders        int                      `json:"activeProviders"`
+	TotalProviders         int                      `json:"totalProviders"`
+	ActiveProxies          int                      `json:"activeProxies"`
+	TotalProxies           int                      `json:"totalProxies"`
+	APIKeysTotal           int                      `json:"apiKeysTotal"`
+	APIKeysHealthy         int                      `json:"apiKeysHealthy"`
+	McpCallCount           int                      `json:"mcpCallCount"`
+	McpErrorCount          int                      `json:"mcpErrorCount"`
+	MisconfiguredProviders []*MisconfiguredProvider `json:"misconfiguredProviders"`
 }
 
 type AdminUsageByUser struct {
@@ -118,19 +119,26 @@ type AnomalyResult struct {
 }
 
 type APIKey struct {
-	ID         string     `json:"id"`
-	ProjectID  string     `json:"projectId"`
-	Channel    string     `json:"channel"`
-	Name       string     `json:"name"`
-	KeyPrefix  string     `json:"keyPrefix"`
-	IsActive   bool       `json:"isActive"`
-	Scopes     string     `json:"scopes"`
-	RateLimit  int        `json:"rateLimit"`
-	TokenLimit int        `json:"tokenLimit"`
-	DailyLimit int        `json:"dailyLimit"`
-	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
-	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
-	CreatedAt  time.Time  `json:"createdAt"`
+	ID                 string     `json:"id"`
+	ProjectID          string     `json:"projectId"`
+	Channel            string     `json:"channel"`
+	Name               string     `json:"name"`
+	KeyPrefix          string     `json:"keyPrefix"`
+	IsActive           bool       `json:"isActive"`
+	Scopes             string     `json:"scopes"`
+	RateLimit          int        `json:"rateLimit"`
+	TokenLimit         int        `json:"tokenLimit"`
+	DailyLimit         int        `json:"dailyLimit"`
+	AllowedCidrs       []string   `json:"allowedCidrs"`
+	SpendThresholds    []float64  `json:"spendThresholds"`
+	SpendWebhookURL    *string    `json:"spendWebhookUrl,omitempty"`
+	MaxRequestCostUsd  float64    `json:"maxRequestCostUsd"`
+	MaxMessages        int        `json:"maxMessages"`
+	MaxMessageChars    int        `json:"maxMessageChars"`
+	StreamBudgetCutoff bool       `json:"streamBudgetCutoff"`
+	ExpiresAt          *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt         *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
 }
 
 type APIKeyHealth struct {
@@ -326,6 +334,15 @@ type CouponInput struct {
 	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
 }
 
+type CreateFallbackChainInput struct {
+	Name         string   `json:"name"`
+	Description  *string  `json:"description,omitempty"`
+	ModelPattern string   `json:"modelPattern"`
+	ProviderIds  []string `json:"providerIds"`
+	Priority     int      `json:"priority"`
+	IsEnabled    bool     `json:"isEnabled"`
+}
+
 type CreateIdentityProviderInput struct {
 	OrgID            string  `json:"orgId"`
 	Type             string  `json:"type"`
@@ -343,15 +360,20 @@ type CreateIdentityProviderInput struct {
 }
 
 type CreateProviderInput struct {
-	Name           string   `json:"name"`
-	BaseURL        string   `json:"baseUrl"`
-	IsActive       *bool    `json:"isActive,omitempty"`
-	Priority       *int     `json:"priority,omitempty"`
-	Weight         *float64 `json:"weight,omitempty"`
-	MaxRetries     *int     `json:"maxRetries,omitempty"`
-	Timeout        *int     `json:"timeout,omitempty"`
-	UseProxy       *bool    `json:"useProxy,omitempty"`
-	RequiresAPIKey *bool    `json:"requiresApiKey,omitempty"`
+	Name             string   `json:"name"`
+	Type             *string  `json:"type,omitempty"`
+	BaseURL          string   `json:"baseUrl"`
+	IsActive         *bool    `json:"isActive,omitempty"`
+	Priority         *int     `json:"priority,omitempty"`
+	Weight           *float64 `json:"weight,omitempty"`
+	MaxRetries       *int     `json:"maxRetries,omitempty"`
+	Timeout          *int     `json:"timeout,omitempty"`
+	UseProxy         *bool    `json:"useProxy,omitempty"`
+	RequiresAPIKey   *bool    `json:"requiresApiKey,omitempty"`
+	DeepHealthCheck  *bool    `json:"deepHealthCheck,omitempty"`
+	HealthCheckModel *string  `json:"healthCheckModel,omitempty"`
+	DefaultMaxTokens *int     `json:"defaultMaxTokens,omitempty"`
+	MaxOutputTokens  *int     `json:"maxOutputTokens,omitempty"`
 }
 
 type CreateRoutingRuleInput struct {
@@ -483,6 +505,18 @@ type ErrorLogConnection struct {
 	PageSize int         `json:"pageSize"`
 }
 
+type FallbackChain struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	ModelPattern string    `json:"modelPattern"`
+	ProviderIds  []string  `json:"providerIds"`
+	Priority     int       `json:"priority"`
+	IsEnabled    bool      `json:"isEnabled"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
 type FeatureGate struct {
 	Name        string `json:"name"`
 	Enabled     bool   `json:"enabled"`
@@ -512,6 +546,7 @@ type HealthEvent struct {
 	TargetID   string    `json:"targetId"`
 	Status     string    `json:"status"`
 	Message    *string   `json:"message,omitempty"`
+	CheckMode  *string   `json:"checkMode,omitempty"`
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
@@ -635,6 +670,12 @@ type MfaSecretInfo struct {
 	BackupCodes []string `json:"backupCodes"`
 }
 
+type MisconfiguredProvider struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Issue string `json:"issue"`
+}
+
 type Model struct {
 	ID               string    `json:"id"`
 	ProviderID       string    `json:"providerId"`
@@ -795,66 +836,96 @@ type PromptVersionInput struct {
 }
 
 type Provider struct {
-	ID             string    `json:"id"`
-	Name           string    `json:"name"`
-	BaseURL        string    `json:"baseUrl"`
-	IsActive       bool      `json:"isActive"`
-	Priority       int       `json:"priority"`
-	Weight         float64   `json:"weight"`
-	MaxRetries     int       `json:"maxRetries"`
-	Timeout        int       `json:"timeout"`
-	UseProxy       bool      `json:"useProxy"`
-	DefaultProxyID *string   `json:"defaultProxyId,omitempty"`
-	RequiresAPIKey bool      `json:"requiresApiKey"`
-	CreatedAt      time.Time `json:"createdAt"`
+	ID                    string    `json:"id"`
+	Name                  string    `json:"name"`
+	Type                  string    `json:"type"`
+	BaseURL               string    `json:"baseUrl"`
+	IsActive              bool      `json:"isActive"`
+	Priority              int       `json:"priority"`
+	Weight                float64   `json:"weight"`
+	MaxRetries            int       `json:"maxRetries"`
+	Timeout               int       `json:"timeout"`
+	UseProxy              bool      `json:"useProxy"`
+	DefaultProxyID        *string   `json:"defaultProxyId,omitempty"`
+	RequiresAPIKey        bool      `json:"requiresApiKey"`
+	DeepHealthCheck       bool      `json:"deepHealthCheck"`
+	HealthCheckModel      *string   `json:"healthCheckModel,omitempty"`
+	DefaultMaxTokens      int       `json:"defaultMaxTokens"`
+	MaxOutputTokens       int       `json:"maxOutputTokens"`
+	TrafficPercentage     int       `json:"trafficPercentage"`
+	TLSInsecureSkipVerify bool      `json:"tlsInsecureSkipVerify"`
+	TLSMinVersion         *string   `json:"tlsMinVersion,omitempty"`
+	TLSCaBundlePath       *string   `json:"tlsCaBundlePath,omitempty"`
+	PromptCaching         bool      `json:"promptCaching"`
+	Draining              bool      `json:"draining"`
+	CreatedAt             time.Time `json:"createdAt"`
 }
 
 type ProviderAPIKey struct {
-	ID         string     `json:"id"`
-	ProviderID string     `json:"providerId"`
-	Alias      string     `json:"alias"`
-	KeyPrefix  string     `json:"keyPrefix"`
-	IsActive   bool       `json:"isActive"`
-	Priority   int        `json:"priority"`
-	Weight     float64    `json:"weight"`
-	RateLimit  int        `json:"rateLimit"`
-	UsageCount int        `json:"usageCount"`
-	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
-	CreatedAt  time.Time  `json:"createdAt"`
+	ID                  string     `json:"id"`
+	ProviderID          string     `json:"providerId"`
+	Alias               string     `json:"alias"`
+	KeyPrefix           string     `json:"keyPrefix"`
+	IsActive            bool       `json:"isActive"`
+	Priority            int        `json:"priority"`
+	Weight              float64    `json:"weight"`
+	RateLimit           int        `json:"rateLimit"`
+	UsageCount          int        `json:"usageCount"`
+	MonthlyRequestLimit int        `json:"monthlyRequestLimit"`
+	MonthlyBudgetUsd    float64    `json:"monthlyBudgetUsd"`
+	MonthlyRequests     int        `json:"monthlyRequests"`
+	MonthlyCost         float64    `json:"monthlyCost"`
+	LastUsedAt          *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
 }
 
 type ProviderAPIKeyInput struct {
-	APIKey    string   `json:"apiKey"`
-	Alias     string   `json:"alias"`
-	Priority  *int     `json:"priority,omitempty"`
-	Weight    *float64 `json:"weight,omitempty"`
-	RateLimit *int     `json:"rateLimit,omitempty"`
+	APIKey              string   `json:"apiKey"`
+	Alias               string   `json:"alias"`
+	Priority            *int     `json:"priority,omitempty"`
+	Weight              *float64 `json:"weight,omitempty"`
+	RateLimit           *int     `json:"rateLimit,omitempty"`
+	MonthlyRequestLimit *int     `json:"monthlyRequestLimit,omitempty"`
+	MonthlyBudgetUsd    *float64 `json:"monthlyBudgetUsd,omitempty"`
 }
 
 type ProviderHealth struct {
-	ID           string     `json:"id"`
-	Name         string     `json:"name"`
-	BaseURL      string     `json:"baseUrl"`
-	IsActive     bool       `json:"isActive"`
-	IsHealthy    bool       `json:"isHealthy"`
-	UseProxy     bool       `json:"useProxy"`
-	ResponseTime float64    `json:"responseTime"`
-	LastCheck    *time.Time `json:"lastCheck,omitempty"`
-	SuccessRate  float64    `json:"successRate"`
-	ErrorMessage *string    `json:"errorMessage,omitempty"`
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	BaseURL            string     `json:"baseUrl"`
+	IsActive           bool       `json:"isActive"`
+	IsHealthy          bool       `json:"isHealthy"`
+	UseProxy           bool       `json:"useProxy"`
+	ResponseTime       float64    `json:"responseTime"`
+	LastCheck          *time.Time `json:"lastCheck,omitempty"`
+	SuccessRate        float64    `json:"successRate"`
+	ErrorMessage       *string    `json:"errorMessage,omitempty"`
+	AvgLatencyMs       float64    `json:"avgLatencyMs"`
+	LatencyThresholdMs int        `json:"latencyThresholdMs"`
+	LatencySLABreached bool       `json:"latencySlaBreached"`
 }
 
 type ProviderInput struct {
-	Name           *string  `json:"name,omitempty"`
-	BaseURL        *string  `json:"baseUrl,omitempty"`
-	IsActive       *bool    `json:"isActive,omitempty"`
-	Priority       *int     `json:"priority,omitempty"`
-	Weight         *float64 `json:"weight,omitempty"`
-	MaxRetries     *int     `json:"maxRetries,omitempty"`
-	Timeout        *int     `json:"timeout,omitempty"`
-	UseProxy       *bool    `json:"useProxy,omitempty"`
-	DefaultProxyID *string  `json:"defaultProxyId,omitempty"`
-	RequiresAPIKey *bool    `json:"requiresApiKey,omitempty"`
+	Name                  *string  `json:"name,omitempty"`
+	Type                  *string  `json:"type,omitempty"`
+	BaseURL               *string  `json:"baseUrl,omitempty"`
+	IsActive              *bool    `json:"isActive,omitempty"`
+	Priority              *int     `json:"priority,omitempty"`
+	Weight                *float64 `json:"weight,omitempty"`
+	MaxRetries            *int     `json:"maxRetries,omitempty"`
+	Timeout               *int     `json:"timeout,omitempty"`
+	UseProxy              *bool    `json:"useProxy,omitempty"`
+	DefaultProxyID        *string  `json:"defaultProxyId,omitempty"`
+	RequiresAPIKey        *bool    `json:"requiresApiKey,omitempty"`
+	DeepHealthCheck       *bool    `json:"deepHealthCheck,omitempty"`
+	HealthCheckModel      *string  `json:"healthCheckModel,omitempty"`
+	DefaultMaxTokens      *int     `json:"defaultMaxTokens,omitempty"`
+	MaxOutputTokens       *int     `json:"maxOutputTokens,omitempty"`
+	TrafficPercentage     *int     `json:"trafficPercentage,omitempty"`
+	TLSInsecureSkipVerify *bool    `json:"tlsInsecureSkipVerify,omitempty"`
+	TLSMinVersion         *string  `json:"tlsMinVersion,omitempty"`
+	TLSCaBundlePath       *string  `json:"tlsCaBundlePath,omitempty"`
+	PromptCaching         *bool    `json:"promptCaching,omitempty"`
 }
 
 type ProviderStats struct {
@@ -865,6 +936,7 @@ type ProviderStats struct {
 	SuccessRate  float64 `json:"successRate"`
 	AvgLatencyMs float64 `json:"avgLatencyMs"`
 	TotalCost    float64 `json:"totalCost"`
+	TrafficShare float64 `json:"trafficShare"`
 }
 
 type ProviderUsage struct {
@@ -1138,6 +1210,15 @@ type UpdateDlpConfigInput struct {
 	CustomRegex     []string     `json:"customRegex,omitempty"`
 }
 
+type UpdateFallbackChainInput struct {
+	Name         *string  `json:"name,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	ModelPattern *string  `json:"modelPattern,omitempty"`
+	ProviderIds  []string `json:"providerIds,omitempty"`
+	Priority     *int     `json:"priority,omitempty"`
+	IsEnabled    *bool    `json:"isEnabled,omitempty"`
+}
+
 type UpdateIdentityProviderInput struct {
 	Name             *string `json:"name,omitempty"`
 	IsActive         *bool   `json:"isActive,omitempty"`
@@ -1177,9 +1258,11 @@ type UpdateProjectInput struct {
 }
 
 type UpdateProviderAPIKeyInput struct {
-	Priority  *int     `json:"priority,omitempty"`
-	Weight    *float64 `json:"weight,omitempty"`
-	RateLimit *int     `json:"rateLimit,omitempty"`
+	Priority            *int     `json:"priority,omitempty"`
+	Weight              *float64 `json:"weight,omitempty"`
+	RateLimit           *int     `json:"rateLimit,omitempty"`
+	MonthlyRequestLimit *int     `json:"monthlyRequestLimit,omitempty"`
+	MonthlyBudgetUsd    *float64 `json:"monthlyBudgetUsd,omitempty"`
 }
 
 type UpdateRoutingRuleInput struct {
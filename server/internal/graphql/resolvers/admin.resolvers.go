@@ -232,6 +232,12 @@ func (r *queryResolver) AdminDashboard(ctx context.Context) (*model.AdminDashboa
 
 	// Infrastructure
 	infra := r.AdminSvc.GetInfraCounts(ctx)
+	misconfigured := []*model.MisconfiguredProvider{}
+	if missing, err := r.Health.ProvidersMissingKeys(ctx); err == nil {
+		for _, p := range missing {
+			misconfigured = append(misconfigured, &model.MisconfiguredProvider{ID: p.ID.String(), Name: p.Name, Issue: "no_active_api_keys"})
+		}
+	}
 
 	return &model.AdminDashboard{
 		TotalUsers:       int(totalUsers),
@@ -256,6 +262,8 @@ func (r *queryResolver) AdminDashboard(ctx context.Context) (*model.AdminDashboa
 		APIKeysHealthy:   int(infra.APIKeyActive),
 		McpCallCount:     mcpCalls,
 		McpErrorCount:    mcpErrors,
+
+		MisconfiguredProviders: misconfigured,
 	}, nil
 }
 
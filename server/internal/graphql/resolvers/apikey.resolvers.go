@@ -12,6 +12,7 @@ import (
 	"llm-router-platform/internal/service/audit"
 	"llm-router-platform/pkg/sanitize"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -66,7 +67,7 @@ func (r *mutationResolver) CreateAPIKey(ctx context.Context, projectID string, n
 		RateLimit:  key.RateLimit,
 		TokenLimit: int(key.TokenLimit),
 		DailyLimit: key.DailyLimit,
-		ExpiresAt:  &key.ExpiresAt,
+		ExpiresAt:  key.ExpiresAt,
 		CreatedAt:  key.CreatedAt,
 	}, nil
 }
@@ -139,6 +140,170 @@ func (r *mutationResolver) DeleteAPIKey(ctx context.Context, projectID string, i
 	return true, nil
 }
 
+// SetAPIKeyAllowedCidrs is the resolver for the setApiKeyAllowedCidrs field.
+func (r *mutationResolver) SetAPIKeyAllowedCidrs(ctx context.Context, id string, cidrs []string) (*model.APIKey, error) {
+	uid, _ := directives.UserIDFromContext(ctx)
+
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API key ID")
+	}
+
+	existing, err := r.UserSvc.GetAPIKeyByID(ctx, keyID)
+	if err != nil || existing == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+	if err := r.UserSvc.RequireProjectRole(ctx, uid, existing.ProjectID.String(), "admin"); err != nil {
+		return nil, err
+	}
+
+	key, err := r.UserSvc.SetAPIKeyAllowedCIDRs(ctx, keyID, cidrs)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, ua := clientInfo(ctx)
+	userID, _ := uuid.Parse(uid)
+	r.AuditService.Log(ctx, audit.ActionAPIKeyRevoke, userID, keyID, ip, ua, map[string]interface{}{"event": "allowed_cidrs", "cidrs": []string(key.AllowedCIDRs)})
+
+	return apiKeyToGQL(key), nil
+}
+
+// SetAPIKeySpendAlerts is the resolver for the setApiKeySpendAlerts field.
+func (r *mutationResolver) SetAPIKeySpendAlerts(ctx context.Context, id string, thresholds []float64, webhookURL *string) (*model.APIKey, error) {
+	uid, _ := directives.UserIDFromContext(ctx)
+
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API key ID")
+	}
+
+	existing, err := r.UserSvc.GetAPIKeyByID(ctx, keyID)
+	if err != nil || existing == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+	if err := r.UserSvc.RequireProjectRole(ctx, uid, existing.ProjectID.String(), "admin"); err != nil {
+		return nil, err
+	}
+
+	url := ""
+	if webhookURL != nil {
+		url = strings.TrimSpace(*webhookURL)
+	}
+	if url != "" {
+		if err := sanitize.ValidateWebhookURL(url, false, r.Config().Server.AllowLocalProviders); err != nil {
+			return nil, fmt.Errorf("invalid webhook URL: %w", err)
+		}
+	}
+
+	key, err := r.UserSvc.SetAPIKeySpendAlerts(ctx, keyID, thresholds, url)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, ua := clientInfo(ctx)
+	userID, _ := uuid.Parse(uid)
+	r.AuditService.Log(ctx, audit.ActionAPIKeyRevoke, userID, keyID, ip, ua, map[string]interface{}{"event": "spend_alerts", "thresholds": []float64(key.SpendThresholds)})
+
+	return apiKeyToGQL(key), nil
+}
+
+// SetAPIKeyMaxRequestCost is the resolver for the setApiKeyMaxRequestCost field.
+func (r *mutationResolver) SetAPIKeyMaxRequestCost(ctx context.Context, id string, maxCostUsd float64) (*model.APIKey, error) {
+	uid, _ := directives.UserIDFromContext(ctx)
+
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API key ID")
+	}
+
+	existing, err := r.UserSvc.GetAPIKeyByID(ctx, keyID)
+	if err != nil || existing == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+	if err := r.UserSvc.RequireProjectRole(ctx, uid, existing.ProjectID.String(), "admin"); err != nil {
+		return nil, err
+	}
+
+	key, err := r.UserSvc.SetAPIKeyMaxRequestCost(ctx, keyID, maxCostUsd)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, ua := clientInfo(ctx)
+	userID, _ := uuid.Parse(uid)
+	r.AuditService.Log(ctx, audit.ActionAPIKeyRevoke, userID, keyID, ip, ua, map[string]interface{}{"event": "max_request_cost", "max_request_cost_usd": key.MaxRequestCostUSD})
+
+	return apiKeyToGQL(key), nil
+}
+
+// SetAPIKeyMessageLimits is the resolver for the setApiKeyMessageLimits field.
+// Project admins may only tighten the server-wide limits; raising or lifting
+// them requires a platform admin.
+func (r *mutationResolver) SetAPIKeyMessageLimits(ctx context.Context, id string, maxMessages int, maxMessageChars int) (*model.APIKey, error) {
+	uid, _ := directives.UserIDFromContext(ctx)
+
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API key ID")
+	}
+
+	limits := r.Config().Router
+	if raisesLimit(limits.MaxChatMessages, maxMessages) || raisesLimit(limits.MaxMessageChars, maxMessageChars) {
+		if err := requireAdmin(ctx); err != nil {
+			return nil, fmt.Errorf("raising or lifting the server message limits requires admin access")
+		}
+	}
+
+	existing, err := r.UserSvc.GetAPIKeyByID(ctx, keyID)
+	if err != nil || existing == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+	if err := r.UserSvc.RequireProjectRole(ctx, uid, existing.ProjectID.String(), "admin"); err != nil {
+		return nil, err
+	}
+
+	key, err := r.UserSvc.SetAPIKeyMessageLimits(ctx, keyID, maxMessages, maxMessageChars)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, ua := clientInfo(ctx)
+	userID, _ := uuid.Parse(uid)
+	r.AuditService.Log(ctx, audit.ActionAPIKeyRevoke, userID, keyID, ip, ua, map[string]interface{}{"event": "message_limits", "max_messages": key.MaxMessages, "max_message_chars": key.MaxMessageChars})
+
+	return apiKeyToGQL(key), nil
+}
+
+// SetAPIKeyStreamBudgetCutoff is the resolver for the setApiKeyStreamBudgetCutoff field.
+func (r *mutationResolver) SetAPIKeyStreamBudgetCutoff(ctx context.Context, id string, enabled bool) (*model.APIKey, error) {
+	uid, _ := directives.UserIDFromContext(ctx)
+
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API key ID")
+	}
+
+	existing, err := r.UserSvc.GetAPIKeyByID(ctx, keyID)
+	if err != nil || existing == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+	if err := r.UserSvc.RequireProjectRole(ctx, uid, existing.ProjectID.String(), "admin"); err != nil {
+		return nil, err
+	}
+
+	key, err := r.UserSvc.SetAPIKeyStreamBudgetCutoff(ctx, keyID, enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, ua := clientInfo(ctx)
+	userID, _ := uuid.Parse(uid)
+	r.AuditService.Log(ctx, audit.ActionAPIKeyRevoke, userID, keyID, ip, ua, map[string]interface{}{"event": "stream_budget_cutoff", "enabled": key.StreamBudgetCutoff})
+
+	return apiKeyToGQL(key), nil
+}
+
 // MyAPIKeys is the resolver for the myApiKeys field.
 func (r *queryResolver) MyAPIKeys(ctx context.Context, projectID string) ([]*model.APIKey, error) {
 	uid, _ := directives.UserIDFromContext(ctx)
@@ -0,0 +1,55 @@
+package resolvers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"llm-router-platform/internal/config"
+	"llm-router-platform/internal/graphql/directives"
+	"llm-router-platform/internal/service/admin"
+)
+
+func userContext(role string) context.Context {
+	gin.SetMode(gin.TestMode)
+	gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+	gc.Request = httptest.NewRequest("POST", "/graphql", nil)
+	gc.Set("user_id", uuid.NewString())
+	gc.Set("role", role)
+	return context.WithValue(context.Background(), directives.GinContextKey, gc)
+}
+
+func TestSetAPIKeyMessageLimitsRejectsRaiseByNonAdmin(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Router.MaxChatMessages = 100
+	cfg.Router.MaxMessageChars = 10000
+	r := &mutationResolver{&Resolver{AdminSvc: admin.NewService(nil, nil, cfg, zap.NewNop())}}
+
+	for name, limits := range map[string][2]int{
+		"raise messages": {500, 0},
+		"raise chars":    {0, 20000},
+		"lift messages":  {-1, 0},
+		"lift chars":     {50, -1},
+	} {
+		_, err := r.SetAPIKeyMessageLimits(userContext("user"), uuid.NewString(), limits[0], limits[1])
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "requires admin access", name)
+	}
+}
+
+func TestRaisesLimit(t *testing.T) {
+	assert.False(t, raisesLimit(100, 0))
+	assert.False(t, raisesLimit(100, 50))
+	assert.False(t, raisesLimit(100, 100))
+	assert.True(t, raisesLimit(100, 101))
+	assert.True(t, raisesLimit(100, -1))
+	// An unlimited server setting cannot be raised.
+	assert.False(t, raisesLimit(0, -1))
+	assert.False(t, raisesLimit(0, 500))
+}
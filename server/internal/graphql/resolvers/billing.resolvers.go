@@ -9,6 +9,7 @@ import (
 	"llm-router-platform/internal/graphql/directives"
 	"llm-router-platform/internal/graphql/model"
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/billing"
 	"strings"
 	"time"
 
@@ -459,15 +460,32 @@ func (r *queryResolver) MyRedeemHistory(ctx context.Context) ([]*model.RedeemRec
 }
 
 // Dashboard is the resolver for the dashboard field.
+// Admins see platform-wide usage; everyone else sees their organization's.
 func (r *queryResolver) Dashboard(ctx context.Context, projectID *string, channel *string) (*model.Dashboard, error) {
-	activeUsers, _ := r.UserSvc.CountActiveUsers(ctx, monthStart())
-	_ = r.resolveProjectID(projectID) // reserved for future project-level filter
 	now := time.Now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	summarize := func(start time.Time) (*billing.UsageSummary, error) {
+		return r.Billing.GetSystemUsageSummary(ctx, channel, start, now)
+	}
+	countActive := func() (int64, error) { return r.UserSvc.CountActiveUsers(ctx, monthStart()) }
+	if role, _ := directives.UserRoleFromContext(ctx); role != "admin" {
+		orgID, err := r.resolveOrgID(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		pid := r.resolveProjectID(projectID)
+		summarize = func(start time.Time) (*billing.UsageSummary, error) {
+			return r.Billing.GetUsageSummary(ctx, orgID, pid, channel, start, now)
+		}
+		countActive = func() (int64, error) { return r.UserSvc.CountActiveOrgUsers(ctx, orgID, monthStart()) }
+	}
+	activeUsers, _ := countActive()
 
 	// Monthly summary
-	sysSummary, _ := r.Billing.GetSystemUsageSummary(ctx, channel, monthStart(), now)
+	sysSummary, _ := summarize(monthStart())
 	totalReq, totalTokens, errorCount, mcpCalls, mcpErrors := 0, 0, 0, 0, 0
-	totalCost, successRate := 0.0, 0.0
+	totalCost, successRate, avgLatency := 0.0, 0.0, 0.0
 	if sysSummary != nil {
 		totalReq = int(sysSummary.TotalRequests)
 		totalTokens = int(sysSummary.TotalTokens)
@@ -476,13 +494,13 @@ func (r *queryResolver) Dashboard(ctx context.Context, projectID *string, channe
 		errorCount = int(sysSummary.ErrorCount)
 		mcpCalls = int(sysSummary.MCPCallCount)
 		mcpErrors = int(sysSummary.MCPErrorCount)
+		avgLatency = sysSummary.AvgLatency
 	}
 
 	// Today's summary
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	todayReq, todayTokens := 0, 0
 	todayCost := 0.0
-	if todaySummary, err := r.Billing.GetSystemUsageSummary(ctx, channel, todayStart, now); err == nil && todaySummary != nil {
+	if todaySummary, err := summarize(todayStart); err == nil && todaySummary != nil {
 		todayReq = int(todaySummary.TotalRequests)
 		todayTokens = int(todaySummary.TotalTokens)
 		todayCost = todaySummary.TotalCost
@@ -494,17 +512,18 @@ func (r *queryResolver) Dashboard(ctx context.Context, projectID *string, channe
 	return &model.Dashboard{
 		TotalRequests: totalReq, SuccessRate: successRate,
 		TotalTokens: totalTokens, TotalCost: totalCost,
-		ActiveUsers:     int(activeUsers),
-		ActiveProviders: int(infra.ProviderActive),
-		ActiveProxies:   int(infra.ProxyActive),
-		RequestsToday:   todayReq,
-		CostToday:       todayCost,
-		TokensToday:     todayTokens,
-		ErrorCount:      errorCount,
-		McpCallCount:    mcpCalls,
-		McpErrorCount:   mcpErrors,
-		APIKeys:         &model.APIKeysSummary{Total: int(infra.APIKeyTotal), Healthy: int(infra.APIKeyActive)},
-		Proxies:         &model.ProxiesSummary{Total: int(infra.ProxyTotal), Healthy: int(infra.ProxyActive)},
+		AverageLatencyMs: avgLatency,
+		ActiveUsers:      int(activeUsers),
+		ActiveProviders:  int(infra.ProviderActive),
+		ActiveProxies:    int(infra.ProxyActive),
+		RequestsToday:    todayReq,
+		CostToday:        todayCost,
+		TokensToday:      todayTokens,
+		ErrorCount:       errorCount,
+		McpCallCount:     mcpCalls,
+		McpErrorCount:    mcpErrors,
+		APIKeys:          &model.APIKeysSummary{Total: int(infra.APIKeyTotal), Healthy: int(infra.APIKeyActive)},
+		Proxies:          &model.ProxiesSummary{Total: int(infra.ProxyTotal), Healthy: int(infra.ProxyActive)},
 	}, nil
 }
 
@@ -528,6 +547,10 @@ func (r *queryResolver) ProviderStats(ctx context.Context, projectID *string, ch
 	if err != nil {
 		return nil, err
 	}
+	var totalRequests int64
+	for _, u := range usage {
+		totalRequests += u.Requests
+	}
 	out := make([]*model.ProviderStats, len(usage))
 	for i, u := range usage {
 		out[i] = &model.ProviderStats{
@@ -535,6 +558,10 @@ func (r *queryResolver) ProviderStats(ctx context.Context, projectID *string, ch
 			Tokens: int(u.Tokens), TotalCost: u.Cost,
 			SuccessRate: u.SuccessRate, AvgLatencyMs: u.AvgLatency,
 		}
+		// Realized traffic split, to compare against canary TrafficPercentage caps.
+		if totalRequests > 0 {
+			out[i].TrafficShare = float64(u.Requests) / float64(totalRequests) * 100
+		}
 	}
 	return out, nil
 }
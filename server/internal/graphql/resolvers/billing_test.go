@@ -0,0 +1,103 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"llm-router-platform/internal/config"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/admin"
+	"llm-router-platform/internal/service/billing"
+	"llm-router-platform/internal/service/user"
+)
+
+// scriptedConn is a database/sql driver connection answering queries from a
+// test function, so resolvers built on the gorm-backed services can run
+// without Postgres.
+type scriptedConn struct {
+	respond func(query string, args []driver.NamedValue) (columns []string, rows [][]driver.Value)
+}
+
+func (c *scriptedConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("scripted db: prepared statements are not supported")
+}
+func (c *scriptedConn) Close() error { return nil }
+func (c *scriptedConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("scripted db: no transactions")
+}
+func (c *scriptedConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *scriptedConn) Driver() driver.Driver                        { return nil }
+
+func (c *scriptedConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	columns, rows := c.respond(query, args)
+	return &scriptedRows{columns: columns, rows: rows}, nil
+}
+
+type scriptedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return r.columns }
+func (r *scriptedRows) Close() error      { return nil }
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestDashboardTodayIsNotServedFromMonthCache(t *testing.T) {
+	orgID := uuid.New()
+	sqlDB := sql.OpenDB(&scriptedConn{respond: func(query string, args []driver.NamedValue) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, `FROM "organizations"`):
+			return []string{"id", "name"}, [][]driver.Value{{orgID.String(), "acme"}}
+		case strings.Contains(query, "total_requests"):
+			// One request per minute of the window tells ranges apart.
+			start, end := args[0].Value.(time.Time), args[1].Value.(time.Time)
+			n := int64(end.Sub(start) / time.Minute)
+			return []string{"total_requests", "total_tokens", "total_cost", "avg_latency", "success_count", "error_count", "mcp_call_count", "mcp_error_count"},
+				[][]driver.Value{{n, n, float64(n), 100.0, n, int64(0), int64(0), int64(0)}}
+		}
+		return nil, nil
+	}})
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	mr := miniredis.RunT(t)
+	r := &queryResolver{&Resolver{
+		Billing:  billing.NewService(repository.NewUsageLogRepository(db), nil, redis.NewClient(&redis.Options{Addr: mr.Addr()}), zap.NewNop()),
+		UserSvc:  user.NewService(repository.NewUserRepository(db), nil, nil, repository.NewOrganizationRepository(db), zap.NewNop()),
+		AdminSvc: admin.NewService(db, nil, &config.Config{}, zap.NewNop()),
+	}}
+
+	// The first call seeds the month cache; the second must still read
+	// today's totals from the database.
+	for i := 0; i < 2; i++ {
+		dash, err := r.Dashboard(userContext("user"), nil, nil)
+		require.NoError(t, err)
+		now := time.Now()
+		todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		assert.InDelta(t, int(now.Sub(todayStart)/time.Minute), dash.RequestsToday, 1)
+		assert.InDelta(t, int(now.Sub(monthStart())/time.Minute), dash.TotalRequests, 1)
+	}
+}
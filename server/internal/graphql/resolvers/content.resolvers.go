@@ -310,6 +310,105 @@ func (r *queryResolver) RoutingRules(ctx context.Context, page *int, pageSize *i
 	}, nil
 }
 
+// CreateFallbackChain is the resolver for the createFallbackChain field.
+func (r *mutationResolver) CreateFallbackChain(ctx context.Context, input model.CreateFallbackChainInput) (*model.FallbackChain, error) {
+	providerIDs, err := parseFallbackProviderIDs(input.ProviderIds)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := &models.FallbackChain{
+		Name:         input.Name,
+		Description:  derefStr(input.Description),
+		ModelPattern: input.ModelPattern,
+		ProviderIDs:  providerIDs,
+		Priority:     input.Priority,
+		IsEnabled:    input.IsEnabled,
+	}
+
+	repo := repository.NewFallbackChainRepository(r.AdminSvc.DB())
+	if err := repo.Create(ctx, chain); err != nil {
+		return nil, err
+	}
+	r.Router.InvalidateFallbackChains()
+
+	return fallbackChainToGQL(chain), nil
+}
+
+// UpdateFallbackChain is the resolver for the updateFallbackChain field.
+func (r *mutationResolver) UpdateFallbackChain(ctx context.Context, id string, input model.UpdateFallbackChainInput) (*model.FallbackChain, error) {
+	chainID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fallback chain id")
+	}
+
+	repo := repository.NewFallbackChainRepository(r.AdminSvc.DB())
+	chain, err := repo.GetByID(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		chain.Name = *input.Name
+	}
+	if input.Description != nil {
+		chain.Description = *input.Description
+	}
+	if input.ModelPattern != nil {
+		chain.ModelPattern = *input.ModelPattern
+	}
+	if input.ProviderIds != nil {
+		providerIDs, err := parseFallbackProviderIDs(input.ProviderIds)
+		if err != nil {
+			return nil, err
+		}
+		chain.ProviderIDs = providerIDs
+	}
+	if input.Priority != nil {
+		chain.Priority = *input.Priority
+	}
+	if input.IsEnabled != nil {
+		chain.IsEnabled = *input.IsEnabled
+	}
+
+	if err := repo.Update(ctx, chain); err != nil {
+		return nil, err
+	}
+	r.Router.InvalidateFallbackChains()
+
+	return fallbackChainToGQL(chain), nil
+}
+
+// DeleteFallbackChain is the resolver for the deleteFallbackChain field.
+func (r *mutationResolver) DeleteFallbackChain(ctx context.Context, id string) (bool, error) {
+	chainID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid fallback chain id")
+	}
+
+	repo := repository.NewFallbackChainRepository(r.AdminSvc.DB())
+	if err := repo.Delete(ctx, chainID); err != nil {
+		return false, err
+	}
+	r.Router.InvalidateFallbackChains()
+	return true, nil
+}
+
+// FallbackChains is the resolver for the fallbackChains field.
+func (r *queryResolver) FallbackChains(ctx context.Context) ([]*model.FallbackChain, error) {
+	repo := repository.NewFallbackChainRepository(r.AdminSvc.DB())
+	chains, err := repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*model.FallbackChain, len(chains))
+	for i := range chains {
+		out[i] = fallbackChainToGQL(&chains[i])
+	}
+	return out, nil
+}
+
 // ActiveAnnouncements is the resolver for the activeAnnouncements field.
 func (r *queryResolver) ActiveAnnouncements(ctx context.Context) ([]*model.Announcement, error) {
 	list, err := r.AnnouncementSvc.GetActive(ctx)
@@ -258,6 +258,8 @@ func (r *queryResolver) HealthProviders(ctx context.Context) ([]*model.ProviderH
 			IsActive: s.IsActive, IsHealthy: s.IsHealthy, UseProxy: s.UseProxy,
 			ResponseTime: float64(s.ResponseTime), LastCheck: lc,
 			SuccessRate: s.SuccessRate, ErrorMessage: em,
+			AvgLatencyMs: float64(s.AvgLatencyMs), LatencyThresholdMs: s.LatencyThresholdMs,
+			LatencySLABreached: s.LatencySLABreached,
 		}
 	}
 	return out, nil
@@ -279,10 +281,14 @@ func (r *queryResolver) HealthHistory(ctx context.Context) ([]*model.HealthEvent
 		if !h.IsHealthy {
 			status = "unhealthy"
 		}
+		var mode *string
+		if h.CheckMode != "" {
+			mode = &h.CheckMode
+		}
 		out[i] = &model.HealthEvent{
 			ID: h.ID.String(), TargetType: h.TargetType,
 			TargetID: h.TargetID.String(), Status: status,
-			Message: msg, CreatedAt: h.CreatedAt,
+			Message: msg, CheckMode: mode, CreatedAt: h.CreatedAt,
 		}
 	}
 	return out, nil
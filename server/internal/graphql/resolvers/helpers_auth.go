@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"llm-router-platform/internal/graphql/directives"
+	"llm-router-platform/internal/graphql/model"
 	"llm-router-platform/internal/models"
 	"llm-router-platform/pkg/sanitize"
 	"time"
@@ -25,6 +26,24 @@ func clientInfo(ctx context.Context) (ip, userAgent string) {
 	return gc.ClientIP(), gc.Request.UserAgent()
 }
 
+// requireAdmin applies the @auth(role: ADMIN) check to a resolver that is
+// otherwise open to all users, for arguments only admins may set.
+func requireAdmin(ctx context.Context) error {
+	admin := model.RoleAdmin
+	_, err := directives.Auth(ctx, nil, func(context.Context) (interface{}, error) { return nil, nil }, &admin)
+	return err
+}
+
+// raisesLimit reports whether an API key override loosens a server-wide
+// limit, where 0 means unlimited. An override of 0 keeps the server limit
+// and a negative one lifts it.
+func raisesLimit(limit, override int) bool {
+	if limit <= 0 {
+		return false
+	}
+	return override < 0 || override > limit
+}
+
 // ── JWT helpers ──────────────────────────────────────────────────────
 
 func (r *mutationResolver) generateJWT(u *models.User) (string, error) {
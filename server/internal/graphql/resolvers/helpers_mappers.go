@@ -4,9 +4,13 @@ package resolvers
 
 import (
 	"encoding/json"
+	"fmt"
 	"llm-router-platform/internal/graphql/model"
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/admin"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // ── Model → GQL converters ──────────────────────────────────────────
@@ -31,17 +35,25 @@ func userToListItem(u *models.User) *model.UserListItem {
 }
 
 func apiKeyToGQL(k *models.APIKey) *model.APIKey {
-	var lastUsed, expires *time.Time
+	var lastUsed *time.Time
 	if !k.LastUsedAt.IsZero() {
 		lastUsed = &k.LastUsedAt
 	}
-	if !k.ExpiresAt.IsZero() {
-		expires = &k.ExpiresAt
+	var spendWebhook *string
+	if k.SpendWebhookURL != "" {
+		spendWebhook = &k.SpendWebhookURL
 	}
 	return &model.APIKey{
 		ID: k.ID.String(), ProjectID: k.ProjectID.String(), Channel: k.Channel, Name: k.Name, KeyPrefix: k.KeyPrefix,
 		IsActive: k.IsActive, Scopes: k.Scopes, RateLimit: k.RateLimit, TokenLimit: int(k.TokenLimit), DailyLimit: k.DailyLimit,
-		LastUsedAt: lastUsed, ExpiresAt: expires, CreatedAt: k.CreatedAt,
+		LastUsedAt: lastUsed, ExpiresAt: k.ExpiresAt, CreatedAt: k.CreatedAt,
+		AllowedCidrs:       append([]string{}, k.AllowedCIDRs...),
+		SpendThresholds:    append([]float64{}, k.SpendThresholds...),
+		SpendWebhookURL:    spendWebhook,
+		MaxRequestCostUsd:  k.MaxRequestCostUSD,
+		MaxMessages:        k.MaxMessages,
+		MaxMessageChars:    k.MaxMessageChars,
+		StreamBudgetCutoff: k.StreamBudgetCutoff,
 	}
 }
 
@@ -80,13 +92,34 @@ func providerToGQL(p *models.Provider) *model.Provider {
 		s := p.DefaultProxyID.String()
 		proxyID = &s
 	}
+	var healthCheckModel *string
+	if p.HealthCheckModel != "" {
+		healthCheckModel = &p.HealthCheckModel
+	}
+	var tlsMinVersion, tlsCABundlePath *string
+	if p.TLSMinVersion != "" {
+		tlsMinVersion = &p.TLSMinVersion
+	}
+	if p.TLSCABundlePath != "" {
+		tlsCABundlePath = &p.TLSCABundlePath
+	}
 	return &model.Provider{
-		ID: p.ID.String(), Name: p.Name, BaseURL: p.BaseURL,
+		ID: p.ID.String(), Name: p.Name, Type: p.ClientType(), BaseURL: p.BaseURL,
 		IsActive: p.IsActive, Priority: p.Priority, Weight: p.Weight,
 		MaxRetries: p.MaxRetries, Timeout: p.Timeout,
 		UseProxy: p.UseProxy, DefaultProxyID: proxyID,
-		RequiresAPIKey: p.RequiresAPIKey,
-		CreatedAt:      p.CreatedAt,
+		RequiresAPIKey:        p.RequiresAPIKey,
+		DeepHealthCheck:       p.DeepHealthCheck,
+		HealthCheckModel:      healthCheckModel,
+		DefaultMaxTokens:      p.DefaultMaxTokens,
+		MaxOutputTokens:       p.MaxOutputTokens,
+		TrafficPercentage:     p.TrafficPercentage,
+		TLSInsecureSkipVerify: p.TLSInsecureSkipVerify,
+		TLSMinVersion:         tlsMinVersion,
+		TLSCaBundlePath:       tlsCABundlePath,
+		PromptCaching:         p.PromptCaching,
+		Draining:              p.Draining,
+		CreatedAt:             p.CreatedAt,
 	}
 }
 
@@ -107,16 +140,50 @@ func modelToGQL(m *models.Model) *model.Model {
 	}
 }
 
+// modelInputSpec converts GraphQL model input to the spec the admin service
+// validates; unset fields keep their current value or the model default.
+func modelInputSpec(input model.ModelInput) admin.ModelSpec {
+	return admin.ModelSpec{
+		Name:             input.Name,
+		DisplayName:      input.DisplayName,
+		InputPricePer1K:  input.InputPricePer1k,
+		OutputPricePer1K: input.OutputPricePer1k,
+		PricePerSecond:   input.PricePerSecond,
+		PricePerImage:    input.PricePerImage,
+		PricePerMinute:   input.PricePerMinute,
+		MaxTokens:        input.MaxTokens,
+		IsActive:         input.IsActive,
+	}
+}
+
 func providerAPIKeyToGQL(k *models.ProviderAPIKey) *model.ProviderAPIKey {
 	return &model.ProviderAPIKey{
 		ID: k.ID.String(), ProviderID: k.ProviderID.String(),
 		Alias: k.Alias, KeyPrefix: k.KeyPrefix,
 		IsActive: k.IsActive, Priority: k.Priority,
 		Weight: k.Weight, RateLimit: k.RateLimit,
+		MonthlyRequestLimit: int(k.MonthlyRequestLimit), MonthlyBudgetUsd: k.MonthlyBudgetUSD,
 		CreatedAt: k.CreatedAt,
 	}
 }
 
+// applyProviderKeyCaps copies optional monthly caps onto a provider key.
+func applyProviderKeyCaps(k *models.ProviderAPIKey, requestLimit *int, budgetUSD *float64) error {
+	if requestLimit != nil {
+		if *requestLimit < 0 {
+			return fmt.Errorf("monthlyRequestLimit must be >= 0")
+		}
+		k.MonthlyRequestLimit = int64(*requestLimit)
+	}
+	if budgetUSD != nil {
+		if *budgetUSD < 0 {
+			return fmt.Errorf("monthlyBudgetUsd must be >= 0")
+		}
+		k.MonthlyBudgetUSD = *budgetUSD
+	}
+	return nil
+}
+
 func proxyToGQL(p *models.Proxy) *model.Proxy {
 	var upID *string
 	if p.UpstreamProxyID != nil {
@@ -281,6 +348,39 @@ func routingRuleToGQL(rule *models.RoutingRule) *model.RoutingRule {
 	}
 }
 
+func fallbackChainToGQL(chain *models.FallbackChain) *model.FallbackChain {
+	providerIDs := make([]string, len(chain.ProviderIDs))
+	copy(providerIDs, chain.ProviderIDs)
+
+	return &model.FallbackChain{
+		ID:           chain.ID.String(),
+		Name:         chain.Name,
+		Description:  chain.Description,
+		ModelPattern: chain.ModelPattern,
+		ProviderIds:  providerIDs,
+		Priority:     chain.Priority,
+		IsEnabled:    chain.IsEnabled,
+		CreatedAt:    chain.CreatedAt,
+		UpdatedAt:    chain.UpdatedAt,
+	}
+}
+
+// parseFallbackProviderIDs validates an ordered provider ID list for a fallback chain.
+func parseFallbackProviderIDs(ids []string) (models.StringArray, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("fallback chain requires at least one provider")
+	}
+	out := make(models.StringArray, len(ids))
+	for i, s := range ids {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid provider id %q", s)
+		}
+		out[i] = id.String()
+	}
+	return out, nil
+}
+
 func mapIdentityProviderToGraphQL(idp *models.IdentityProvider) *model.IdentityProvider {
 	if idp == nil {
 		return nil
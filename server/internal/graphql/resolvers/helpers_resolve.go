@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"llm-router-platform/internal/graphql/directives"
+	"llm-router-platform/internal/models"
 
 	"github.com/google/uuid"
 )
@@ -54,3 +55,31 @@ func (r *Resolver) resolveOrgProjectIDs(ctx context.Context, providedOrgID *stri
 	projectID := r.resolveProjectID(providedProjectID)
 	return orgID, projectID, nil
 }
+
+// resolveDefaultProxyID parses a provider's defaultProxyId input. An empty
+// string clears the pin; otherwise the proxy must exist and be active.
+func (r *Resolver) resolveDefaultProxyID(ctx context.Context, raw string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default proxy ID")
+	}
+	if err := r.Router.ValidateDefaultProxy(ctx, id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// validateOutputTokenLimits rejects negative token limits and a default that
+// exceeds the provider's own ceiling.
+func validateOutputTokenLimits(p *models.Provider) error {
+	if p.DefaultMaxTokens < 0 || p.MaxOutputTokens < 0 {
+		return fmt.Errorf("defaultMaxTokens and maxOutputTokens must be >= 0")
+	}
+	if p.MaxOutputTokens > 0 && p.DefaultMaxTokens > p.MaxOutputTokens {
+		return fmt.Errorf("defaultMaxTokens (%d) exceeds maxOutputTokens (%d)", p.DefaultMaxTokens, p.MaxOutputTokens)
+	}
+	return nil
+}
@@ -10,32 +10,28 @@ import (
 	"llm-router-platform/internal/crypto"
 	"llm-router-platform/internal/graphql/model"
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/router"
 	"llm-router-platform/pkg/sanitize"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 // CreateProvider is the resolver for the createProvider field.
 func (r *mutationResolver) CreateProvider(ctx context.Context, input model.CreateProviderInput) (*model.Provider, error) {
 	// SSRF protection: validate the URL
-	if err := sanitize.ValidateWebhookURL(input.BaseURL, true, r.Config().Server.AllowLocalProviders); err != nil {
-		return nil, fmt.Errorf("invalid base URL: %w", err)
+	if err := r.Router.ValidateProviderBaseURL(input.BaseURL); err != nil {
+		return nil, err
 	}
 
-	p := &models.Provider{
-		Name:           input.Name,
-		BaseURL:        input.BaseURL,
-		IsActive:       false,
-		Priority:       5,
-		Weight:         1.0,
-		MaxRetries:     3,
-		Timeout:        30,
-		UseProxy:       false,
-		RequiresAPIKey: true,
+	var providerType string
+	if input.Type != nil {
+		providerType = *input.Type
 	}
+	p := router.NewProvider(input.Name, providerType, input.BaseURL)
 
 	// Apply optional overrides
 	if input.IsActive != nil {
@@ -59,6 +55,21 @@ func (r *mutationResolver) CreateProvider(ctx context.Context, input model.Creat
 	if input.RequiresAPIKey != nil {
 		p.RequiresAPIKey = *input.RequiresAPIKey
 	}
+	if input.DeepHealthCheck != nil {
+		p.DeepHealthCheck = *input.DeepHealthCheck
+	}
+	if input.HealthCheckModel != nil {
+		p.HealthCheckModel = *input.HealthCheckModel
+	}
+	if input.DefaultMaxTokens != nil {
+		p.DefaultMaxTokens = *input.DefaultMaxTokens
+	}
+	if input.MaxOutputTokens != nil {
+		p.MaxOutputTokens = *input.MaxOutputTokens
+	}
+	if err := validateOutputTokenLimits(p); err != nil {
+		return nil, err
+	}
 
 	if err := r.Router.CreateProvider(ctx, p); err != nil {
 		return nil, err
@@ -85,6 +96,9 @@ func (r *mutationResolver) UpdateProvider(ctx context.Context, id string, input
 	if input.Name != nil {
 		p.Name = *input.Name
 	}
+	if input.Type != nil {
+		p.Type = *input.Type
+	}
 	if input.BaseURL != nil {
 		// SSRF protection: validate the URL is not pointing to internal/private IPs
 		// Allow HTTP since some local providers (Ollama, vLLM) use it
@@ -112,16 +126,54 @@ func (r *mutationResolver) UpdateProvider(ctx context.Context, id string, input
 		p.UseProxy = *input.UseProxy
 	}
 	if input.DefaultProxyID != nil {
-		if *input.DefaultProxyID == "" {
-			p.DefaultProxyID = nil
-		} else {
-			pid, _ := uuid.Parse(*input.DefaultProxyID)
-			p.DefaultProxyID = &pid
+		proxyID, err := r.resolveDefaultProxyID(ctx, *input.DefaultProxyID)
+		if err != nil {
+			return nil, err
 		}
+		p.DefaultProxyID = proxyID
 	}
 	if input.RequiresAPIKey != nil {
 		p.RequiresAPIKey = *input.RequiresAPIKey
 	}
+	if input.DeepHealthCheck != nil {
+		p.DeepHealthCheck = *input.DeepHealthCheck
+	}
+	if input.HealthCheckModel != nil {
+		p.HealthCheckModel = *input.HealthCheckModel
+	}
+	if input.DefaultMaxTokens != nil {
+		p.DefaultMaxTokens = *input.DefaultMaxTokens
+	}
+	if input.MaxOutputTokens != nil {
+		p.MaxOutputTokens = *input.MaxOutputTokens
+	}
+	if input.TrafficPercentage != nil {
+		if *input.TrafficPercentage < 0 || *input.TrafficPercentage > 100 {
+			return nil, fmt.Errorf("trafficPercentage must be between 0 and 100")
+		}
+		p.TrafficPercentage = *input.TrafficPercentage
+	}
+	if input.TLSInsecureSkipVerify != nil {
+		if *input.TLSInsecureSkipVerify && !p.TLSInsecureSkipVerify {
+			r.Logger.Warn("TLS certificate verification disabled for provider", zap.String("provider", p.Name))
+		}
+		p.TLSInsecureSkipVerify = *input.TLSInsecureSkipVerify
+	}
+	if input.TLSMinVersion != nil {
+		p.TLSMinVersion = *input.TLSMinVersion
+	}
+	if input.TLSCaBundlePath != nil {
+		p.TLSCABundlePath = *input.TLSCaBundlePath
+	}
+	if input.PromptCaching != nil {
+		p.PromptCaching = *input.PromptCaching
+	}
+	if err := r.Router.ValidateProviderTLS(p); err != nil {
+		return nil, err
+	}
+	if err := validateOutputTokenLimits(p); err != nil {
+		return nil, err
+	}
 	if err := r.Router.UpdateProvider(ctx, p); err != nil {
 		return nil, err
 	}
@@ -160,10 +212,7 @@ func (r *mutationResolver) ToggleProviderProxy(ctx context.Context, id string) (
 func (r *mutationResolver) CreateProviderAPIKey(ctx context.Context, providerID string, input model.ProviderAPIKeyInput) (*model.ProviderAPIKey, error) {
 	pid, _ := uuid.Parse(providerID)
 	input.APIKey = strings.TrimSpace(input.APIKey)
-	keyPrefix := input.APIKey
-	if len(keyPrefix) > 10 {
-		keyPrefix = keyPrefix[:8] + "..."
-	}
+	keyPrefix := router.ProviderKeyPrefix(input.APIKey)
 	encrypted, err := crypto.Encrypt(input.APIKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt API key")
@@ -184,6 +233,9 @@ func (r *mutationResolver) CreateProviderAPIKey(ctx context.Context, providerID
 		ProviderID: pid, Alias: input.Alias, EncryptedAPIKey: encrypted,
 		KeyPrefix: keyPrefix, IsActive: true, Priority: prio, Weight: weight, RateLimit: rl,
 	}
+	if err := applyProviderKeyCaps(key, input.MonthlyRequestLimit, input.MonthlyBudgetUsd); err != nil {
+		return nil, err
+	}
 	if err := r.Router.CreateProviderAPIKey(ctx, key); err != nil {
 		return nil, err
 	}
@@ -206,6 +258,9 @@ func (r *mutationResolver) UpdateProviderAPIKey(ctx context.Context, providerID
 	if input.RateLimit != nil {
 		key.RateLimit = *input.RateLimit
 	}
+	if err := applyProviderKeyCaps(key, input.MonthlyRequestLimit, input.MonthlyBudgetUsd); err != nil {
+		return nil, err
+	}
 	if err := r.Router.UpdateProviderAPIKey(ctx, key); err != nil {
 		return nil, err
 	}
@@ -234,32 +289,13 @@ func (r *mutationResolver) CreateModel(ctx context.Context, providerID string, i
 	if err != nil {
 		return nil, fmt.Errorf("invalid provider id")
 	}
-	m := models.Model{
-		ProviderID:  pid,
-		Name:        input.Name,
-		DisplayName: derefStrDefault(input.DisplayName, input.Name),
-		IsActive:    derefBool(input.IsActive, true),
-		MaxTokens:   valInt(input.MaxTokens, 4096),
-	}
-	if input.InputPricePer1k != nil {
-		m.InputPricePer1K = *input.InputPricePer1k
-	}
-	if input.OutputPricePer1k != nil {
-		m.OutputPricePer1K = *input.OutputPricePer1k
-	}
-	if input.PricePerSecond != nil {
-		m.PricePerSecond = *input.PricePerSecond
-	}
-	if input.PricePerImage != nil {
-		m.PricePerImage = *input.PricePerImage
-	}
-	if input.PricePerMinute != nil {
-		m.PricePerMinute = *input.PricePerMinute
-	}
-	if err := r.AdminSvc.DB().Create(&m).Error; err != nil {
-		return nil, fmt.Errorf("failed to create model: %w", err)
+	spec := modelInputSpec(input)
+	spec.ProviderID = &pid
+	m, err := r.AdminSvc.CreateModelFromSpec(ctx, spec)
+	if err != nil {
+		return nil, err
 	}
-	return modelToGQL(&m), nil
+	return modelToGQL(m), nil
 }
 
 // UpdateModel is the resolver for the updateModel field.
@@ -268,39 +304,11 @@ func (r *mutationResolver) UpdateModel(ctx context.Context, id string, input mod
 	if err != nil {
 		return nil, fmt.Errorf("invalid model id")
 	}
-	var m models.Model
-	if err := r.AdminSvc.DB().First(&m, "id = ?", mid).Error; err != nil {
-		return nil, fmt.Errorf("model not found")
-	}
-	m.Name = input.Name
-	if input.DisplayName != nil {
-		m.DisplayName = *input.DisplayName
-	}
-	if input.MaxTokens != nil {
-		m.MaxTokens = *input.MaxTokens
-	}
-	if input.IsActive != nil {
-		m.IsActive = *input.IsActive
-	}
-	if input.InputPricePer1k != nil {
-		m.InputPricePer1K = *input.InputPricePer1k
-	}
-	if input.OutputPricePer1k != nil {
-		m.OutputPricePer1K = *input.OutputPricePer1k
-	}
-	if input.PricePerSecond != nil {
-		m.PricePerSecond = *input.PricePerSecond
-	}
-	if input.PricePerImage != nil {
-		m.PricePerImage = *input.PricePerImage
-	}
-	if input.PricePerMinute != nil {
-		m.PricePerMinute = *input.PricePerMinute
-	}
-	if err := r.AdminSvc.DB().Save(&m).Error; err != nil {
-		return nil, fmt.Errorf("failed to update model: %w", err)
+	m, err := r.AdminSvc.UpdateModelFromSpec(ctx, mid, modelInputSpec(input))
+	if err != nil {
+		return nil, err
 	}
-	return modelToGQL(&m), nil
+	return modelToGQL(m), nil
 }
 
 // DeleteModel is the resolver for the deleteModel field.
@@ -403,6 +411,8 @@ func (r *mutationResolver) SyncProviderModels(ctx context.Context, providerID st
 		}
 		r.AdminSvc.DB().Create(&m)
 	}
+	// Drop the cached upstream list so /v1/models reflects the sync.
+	r.Router.InvalidateModelList(ctx, prov.Name)
 
 	// Return full model list
 	var allModels []models.Model
@@ -434,9 +444,19 @@ func (r *queryResolver) ProviderAPIKeys(ctx context.Context, providerID string)
 	if err != nil {
 		return nil, err
 	}
+	ids := make([]uuid.UUID, len(keys))
+	for i := range keys {
+		ids[i] = keys[i].ID
+	}
+	usage, err := r.Router.KeyMonthlyUsage(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key usage: %w", err)
+	}
 	out := make([]*model.ProviderAPIKey, len(keys))
 	for i := range keys {
 		out[i] = providerAPIKeyToGQL(&keys[i])
+		out[i].MonthlyRequests = int(usage[keys[i].ID].Requests)
+		out[i].MonthlyCost = usage[keys[i].ID].Cost
 	}
 	return out, nil
 }
@@ -56,13 +56,18 @@ func (r *mutationResolver) UpdateProxy(ctx context.Context, id string, input mod
 	if err != nil {
 		return nil, err
 	}
+	r.Router.InvalidateProxyClients(pid)
 	return proxyToGQL(p), nil
 }
 
 // DeleteProxy is the resolver for the deleteProxy field.
 func (r *mutationResolver) DeleteProxy(ctx context.Context, id string) (bool, error) {
 	pid, _ := uuid.Parse(id)
-	return true, r.Proxy.Delete(ctx, pid)
+	if err := r.Proxy.Delete(ctx, pid); err != nil {
+		return false, err
+	}
+	r.Router.InvalidateProxyClients(pid)
+	return true, nil
 }
 
 // ToggleProxyStatus is the resolver for the toggleProxyStatus field.
@@ -72,6 +77,7 @@ func (r *mutationResolver) ToggleProxyStatus(ctx context.Context, id string) (*m
 	if err != nil {
 		return nil, err
 	}
+	r.Router.InvalidateProxyClients(pid)
 	return proxyToGQL(p), nil
 }
 
@@ -72,9 +72,12 @@ type UsageLog struct {
 	ModelID        uuid.UUID `gorm:"type:uuid;index" json:"model_id"`
 	ModelName      string    `gorm:"index" json:"model_name"`
 	ProxyID        uuid.UUID `gorm:"type:uuid;index" json:"proxy_id"`
+	ProviderKeyID  uuid.UUID `gorm:"type:uuid;index" json:"provider_key_id"` // Provider API key that served the request
 	RequestTokens  int       `gorm:"column:request_tokens" json:"input_tokens"`
 	ResponseTokens int       `gorm:"column:response_tokens" json:"output_tokens"`
 	TotalTokens    int       `json:"total_tokens"`
+	CacheCreationTokens int  `gorm:"default:0" json:"cache_creation_tokens,omitempty"` // Prompt-cache writes, included in RequestTokens
+	CacheReadTokens     int  `gorm:"default:0" json:"cache_read_tokens,omitempty"`     // Prompt-cache reads, included in RequestTokens
 	DurationMs     int64     `json:"duration_ms,omitempty"`      // TTS/Audio duration in milliseconds
 	ItemCount      int       `json:"item_count,omitempty"`       // Number of items (images, frames)
 	BytesProcessed int64     `json:"bytes_processed,omitempty"` // File size in bytes
@@ -82,6 +85,9 @@ type UsageLog struct {
 	Latency        int64     `gorm:"column:latency" json:"latency_ms"`
 	StatusCode     int       `json:"status_code"`
 	ErrorMessage   string    `json:"error_message,omitempty"`
+	ProviderForced bool      `gorm:"default:false" json:"provider_forced"` // Provider pinned via X-LLM-Provider
+	StreamDowngraded bool     `gorm:"default:false" json:"stream_downgraded"` // Stream request served by a non-streaming call
+	Tags           StringMap `gorm:"type:jsonb" json:"tags,omitempty"`       // Client attribution tags (metadata / X-Usage-Tags)
 	
 	// MCP stats
 	MCPCallCount   int       `gorm:"default:0" json:"mcp_call_count"`
@@ -103,3 +109,14 @@ type Budget struct {
 	WebhookURL      string     `json:"webhook_url,omitempty"`
 	Email           string     `json:"email,omitempty"`
 }
+
+// APIKeySpendAlert records that an API key's spend threshold has fired in a
+// billing period, so each threshold notifies at most once per period.
+type APIKeySpendAlert struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	APIKeyID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_api_key_spend_alerts_key_period_threshold" json:"api_key_id"`
+	Period       string    `gorm:"type:varchar(7);not null;uniqueIndex:idx_api_key_spend_alerts_key_period_threshold" json:"period"` // YYYY-MM
+	ThresholdUSD float64   `gorm:"type:decimal(20,4);not null;uniqueIndex:idx_api_key_spend_alerts_key_period_threshold" json:"threshold_usd"`
+	SpendUSD     float64   `gorm:"type:decimal(20,6);not null;default:0" json:"spend_usd"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// FailedRequest is a dead-letter record of a chat request that failed on
+// every provider and key it was tried on. Attempts lists each upstream call in
+// order, so triage can tell a provider-wide outage from a model-specific
+// issue. Records are sampled and purged after a retention period.
+//
+// In capture mode the request payload and the upstream error bodies are kept
+// too, encrypted, and cleared after a shorter retention; Captured reports
+// whether they are still stored.
+type FailedRequest struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt    time.Time      `gorm:"index" json:"created_at"`
+	RequestID    string         `gorm:"type:varchar(64)" json:"request_id"`
+	ProjectID    uuid.UUID      `gorm:"type:uuid;index" json:"project_id"`
+	APIKeyID     uuid.UUID      `gorm:"type:uuid" json:"api_key_id"`
+	ModelName    string         `gorm:"type:varchar(255);index" json:"model_name"`
+	Stream       bool           `json:"stream"`
+	AttemptCount int            `json:"attempt_count"`
+	Attempts     datatypes.JSON `gorm:"type:jsonb" json:"attempts"` // []FailedAttempt
+	StatusCode   int            `json:"status_code"`
+	ErrorMessage string         `gorm:"type:text" json:"error_message"` // error returned to the client
+	Captured     bool           `gorm:"not null;default:false" json:"captured"`
+	Payload      string         `gorm:"type:text" json:"-"` // encrypted request JSON
+	ErrorBodies  string         `gorm:"type:text" json:"-"` // encrypted []CapturedError JSON
+}
+
+// FailedAttempt is one upstream call of a FailedRequest.
+type FailedAttempt struct {
+	ProviderID uuid.UUID `json:"provider_id"`
+	Provider   string    `json:"provider"`
+	KeyAlias   string    `json:"key_alias,omitempty"`
+	Error      string    `json:"error"`
+}
+
+// CapturedError is the upstream response of one failed attempt, kept by
+// failed request capture.
+type CapturedError struct {
+	Provider   string `json:"provider"`
+	KeyAlias   string `json:"key_alias,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Body       string `json:"body"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FallbackChain defines an explicit, ordered list of providers to try for models
+// matching ModelPattern. When a chain matches, it replaces priority-based
+// fallback ordering.
+type FallbackChain struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name         string         `gorm:"type:varchar(255);not null" json:"name"`
+	Description  string         `gorm:"type:text" json:"description"`
+	ModelPattern string         `gorm:"type:varchar(255);not null" json:"model_pattern"`      // Glob or exact match
+	ProviderIDs  StringArray    `gorm:"type:jsonb;not null;default:'[]'" json:"provider_ids"` // Ordered, first is tried first
+	Priority     int            `gorm:"type:integer;not null;default:0" json:"priority"`      // Higher is matched first
+	IsEnabled    bool           `gorm:"type:boolean;not null;default:true" json:"is_enabled"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ProviderUUIDs returns the chain's provider IDs in order, skipping malformed entries.
+func (c *FallbackChain) ProviderUUIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(c.ProviderIDs))
+	for _, s := range c.ProviderIDs {
+		if id, err := uuid.Parse(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
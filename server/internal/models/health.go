@@ -14,6 +14,7 @@ type HealthHistory struct {
 	IsHealthy    bool      `json:"is_healthy"`
 	ResponseTime int64     `json:"response_time"`
 	ErrorMessage string    `json:"error_message,omitempty"`
+	CheckMode    string    `gorm:"default:shallow" json:"check_mode"` // "shallow" or "deep"
 	CheckedAt    time.Time `gorm:"index" json:"checked_at"`
 }
 
@@ -43,3 +44,26 @@ type AlertConfig struct {
 	WebhookURL         string    `json:"webhook_url,omitempty"`
 	Email              string    `json:"email,omitempty"`
 }
+
+// Alert webhook delivery states.
+const (
+	AlertDeliveryPending   = "pending"   // waiting for the next retry
+	AlertDeliveryDelivered = "delivered" // a retry succeeded
+	AlertDeliveryDead      = "dead"      // gave up after the maximum attempts
+)
+
+// AlertDelivery queues an alert webhook whose first delivery failed so it can
+// be retried with backoff. Payload is the exact JSON body; it is re-signed
+// on every attempt.
+type AlertDelivery struct {
+	BaseModel
+	AlertID       *uuid.UUID `gorm:"type:uuid;index" json:"alert_id,omitempty"`
+	URL           string     `gorm:"type:text;not null" json:"url"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"type:varchar(20);not null;default:pending;index" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	StatusCode    int        `gorm:"not null;default:0" json:"status_code"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
@@ -66,20 +66,44 @@ func (ic *InviteCode) IsValid() bool {
 // APIKey represents an API key for authentication.
 type APIKey struct {
 	BaseModel
-	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
-	ProjectID  uuid.UUID `gorm:"type:uuid;not null;index" json:"project_id"`
-	Channel    string    `gorm:"type:varchar(128);default:'default'" json:"channel"`
-	KeyHash    string    `gorm:"not null;uniqueIndex" json:"-"`
-	KeyPrefix  string    `gorm:"not null" json:"key_prefix"`
-	Name       string    `json:"name"`
-	IsActive   bool      `gorm:"default:true" json:"is_active"`
-	Scopes     string    `gorm:"type:text;default:'all'" json:"scopes"` // Comma-separated or JSON list of scopes: all, chat, embeddings, etc.
-	RateLimit  int       `gorm:"default:1000" json:"rate_limit"`
-	TokenLimit int64     `gorm:"default:0" json:"token_limit"` // 0 = unlimited tokens per minute
-	DailyLimit int       `gorm:"default:10000" json:"daily_limit"`
-	ExpiresAt  time.Time `json:"expires_at"`
-	LastUsedAt time.Time `json:"last_used_at"`
-	Project    Project   `gorm:"foreignKey:ProjectID" json:"-"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	ProjectID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"project_id"`
+	Channel    string     `gorm:"type:varchar(128);default:'default'" json:"channel"`
+	KeyHash    string     `gorm:"not null;uniqueIndex" json:"-"`
+	KeyPrefix  string     `gorm:"not null" json:"key_prefix"`
+	Name       string     `json:"name"`
+	IsActive   bool       `gorm:"default:true" json:"is_active"`
+	Scopes     string     `gorm:"type:text;default:'all'" json:"scopes"` // Comma-separated or JSON list of scopes: all, chat, embeddings, etc.
+	RateLimit  int        `gorm:"default:1000" json:"rate_limit"`
+	TokenLimit int64      `gorm:"default:0" json:"token_limit"` // 0 = unlimited tokens per minute
+	DailyLimit int        `gorm:"default:10000" json:"daily_limit"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // nil = never expires
+	LastUsedAt time.Time  `json:"last_used_at"`
+	// AllowedCIDRs restricts which source IPs may use the key; empty = allow all.
+	AllowedCIDRs StringArray `gorm:"type:jsonb;not null;default:'[]'" json:"allowed_cidrs"`
+	// MaxRequestCostUSD rejects a request whose worst-case cost (prompt plus
+	// max_tokens at model pricing) exceeds it. Zero means no limit.
+	MaxRequestCostUSD float64 `gorm:"not null;default:0" json:"max_request_cost_usd"`
+	// MaxMessages and MaxMessageChars override the server-wide limits on
+	// messages per chat request and characters per message. Zero keeps the
+	// server limit; -1 lifts it for trusted high-context clients.
+	MaxMessages     int `gorm:"not null;default:0" json:"max_messages"`
+	MaxMessageChars int `gorm:"not null;default:0" json:"max_message_chars"`
+	// StreamBudgetCutoff ends a streamed chat completion once its estimated
+	// cost reaches MaxRequestCostUSD or the organization's remaining monthly
+	// budget, instead of only checking before the request is sent.
+	StreamBudgetCutoff bool `gorm:"not null;default:false" json:"stream_budget_cutoff"`
+	// SpendThresholds are USD amounts, sorted ascending. Each one fires
+	// SpendWebhookURL once per calendar month when the key's spend crosses it.
+	SpendThresholds Float64Array `gorm:"type:jsonb;not null;default:'[]'" json:"spend_thresholds"`
+	SpendWebhookURL string       `gorm:"type:text;not null;default:''" json:"spend_webhook_url,omitempty"`
+	Project         Project      `gorm:"foreignKey:ProjectID" json:"-"`
+}
+
+// IsExpired reports whether the key's expiry time has passed at now. Keys
+// without an expiry never expire.
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(now)
 }
 
 // AuditLog records security-relevant events for incident investigation.
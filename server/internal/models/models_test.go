@@ -59,6 +59,33 @@ func TestProviderModel(t *testing.T) {
 	assert.Equal(t, 10, provider.Priority)
 }
 
+func TestProviderOutputTokenLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		def, max    int
+		requested   int
+		want        int
+		wantClamped bool
+	}{
+		{"no limits passes through", 0, 0, 500, 500, false},
+		{"no limits leaves omitted", 0, 0, 0, 0, false},
+		{"default fills omitted", 1024, 0, 0, 1024, false},
+		{"default ignored when requested", 1024, 0, 300, 300, false},
+		{"under ceiling", 1024, 4096, 2000, 2000, false},
+		{"over ceiling is clamped", 1024, 4096, 8000, 4096, true},
+		{"ceiling fills omitted without default", 0, 4096, 0, 4096, false},
+		{"default above ceiling is capped silently", 8192, 4096, 0, 4096, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Provider{DefaultMaxTokens: tt.def, MaxOutputTokens: tt.max}
+			got, clamped := p.OutputTokenLimit(tt.requested)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantClamped, clamped)
+		})
+	}
+}
+
 func TestModelModel(t *testing.T) {
 	providerID := uuid.New()
 	model := Model{
@@ -288,3 +315,10 @@ func TestAsyncTaskStatusTransitions(t *testing.T) {
 }
 
 
+
+func TestProviderTrafficCap(t *testing.T) {
+	for pct, want := range map[int]float64{0: 1, 100: 1, 150: 1, -5: 1, 1: 0.01, 25: 0.25, 99: 0.99} {
+		p := Provider{TrafficPercentage: pct}
+		assert.InDelta(t, want, p.TrafficCap(), 1e-9, "pct %d", pct)
+	}
+}
@@ -11,6 +11,7 @@ import (
 type Provider struct {
 	BaseModel
 	Name           string     `gorm:"uniqueIndex;not null" json:"name"`
+	Type           string     `gorm:"not null;default:''" json:"type"` // protocol that selects the client, e.g. anthropic or openai-compatible; Name only identifies the instance
 	BaseURL        string     `gorm:"not null" json:"base_url"`
 	IsActive       bool       `gorm:"default:true" json:"is_active"`
 	Priority       int        `gorm:"default:0" json:"priority"`
@@ -20,6 +21,34 @@ type Provider struct {
 	UseProxy       bool       `gorm:"default:false" json:"use_proxy"`
 	DefaultProxyID *uuid.UUID `gorm:"type:uuid" json:"default_proxy_id,omitempty"`
 	RequiresAPIKey bool       `gorm:"default:true" json:"requires_api_key"`
+	// DeepHealthCheck makes health probes send a 1-token completion instead of
+	// only listing models. Off by default because every probe costs tokens.
+	DeepHealthCheck  bool   `gorm:"default:false" json:"deep_health_check"`
+	HealthCheckModel string `json:"health_check_model,omitempty"` // model for deep checks; empty = provider default
+	// DefaultMaxTokens is sent as max_tokens when a request omits it, and
+	// MaxOutputTokens caps what a request may ask for. 0 disables either.
+	DefaultMaxTokens int `gorm:"not null;default:0" json:"default_max_tokens"`
+	MaxOutputTokens  int `gorm:"not null;default:0" json:"max_output_tokens"`
+	// TrafficPercentage caps the share of a model's traffic this provider gets
+	// when several providers serve it, for ramping up a canary. 0 or 100
+	// disables the cap.
+	TrafficPercentage int `gorm:"not null;default:0" json:"traffic_percentage"`
+	// TLS settings for self-hosted endpoints with private or self-signed
+	// certificates. Verification stays on unless TLSInsecureSkipVerify is set;
+	// TLSMinVersion is "1.2" or "1.3" (empty = 1.2) and TLSCABundlePath names
+	// a PEM file trusted alongside the system roots.
+	TLSInsecureSkipVerify bool   `gorm:"not null;default:false" json:"tls_insecure_skip_verify"`
+	TLSMinVersion         string `gorm:"not null;default:''" json:"tls_min_version,omitempty"`
+	TLSCABundlePath       string `gorm:"not null;default:''" json:"tls_ca_bundle_path,omitempty"`
+	// PromptCaching marks the system prompt of Anthropic requests with an
+	// ephemeral cache_control breakpoint, so repeated long system prompts are
+	// read from Anthropic's prompt cache at a fraction of the input price.
+	PromptCaching bool `gorm:"not null;default:false" json:"prompt_caching"`
+	// Draining takes an active provider out of request routing for
+	// maintenance while in-flight requests finish; unlike IsActive=false it
+	// keeps the provider health-checked, so operators can tell when it is
+	// safe to bring back.
+	Draining bool `gorm:"not null;default:false" json:"draining"`
 	// ModelPatterns is a JSON array of glob patterns used for model→provider routing.
 	// Examples: ["gpt-*","o1*","dall-e*","whisper*","tts*"]
 	// When empty, falls back to hardcoded heuristics.
@@ -27,6 +56,40 @@ type Provider struct {
 	Models         []Model    `gorm:"foreignKey:ProviderID" json:"models,omitempty"`
 }
 
+// ClientType returns the provider type that selects the provider's client:
+// Type, or Name for a provider whose type was never set.
+func (p *Provider) ClientType() string {
+	if p.Type != "" {
+		return p.Type
+	}
+	return p.Name
+}
+
+// OutputTokenLimit resolves the max_tokens to send to this provider for a
+// request that asked for requested (0 = omitted). An omitted value falls back
+// to DefaultMaxTokens, or to MaxOutputTokens when there is no default, and
+// MaxOutputTokens caps the result. clamped reports whether the requested
+// value was lowered to the ceiling.
+func (p *Provider) OutputTokenLimit(requested int) (maxTokens int, clamped bool) {
+	maxTokens = requested
+	if maxTokens <= 0 {
+		maxTokens = p.DefaultMaxTokens
+	}
+	if p.MaxOutputTokens > 0 && (maxTokens <= 0 || maxTokens > p.MaxOutputTokens) {
+		return p.MaxOutputTokens, requested > p.MaxOutputTokens
+	}
+	return maxTokens, false
+}
+
+// TrafficCap returns the largest fraction of matching traffic the provider
+// may receive: TrafficPercentage/100, or 1 when uncapped.
+func (p *Provider) TrafficCap() float64 {
+	if p.TrafficPercentage <= 0 || p.TrafficPercentage >= 100 {
+		return 1
+	}
+	return float64(p.TrafficPercentage) / 100
+}
+
 // GetModelPatterns deserializes the ModelPatterns JSON field into a string slice.
 func (p *Provider) GetModelPatterns() []string {
 	if len(p.ModelPatterns) == 0 {
@@ -51,8 +114,13 @@ type Model struct {
 	PricePerImage    float64   `gorm:"default:0" json:"price_per_image,omitempty"`   // Image generation per-image pricing
 	PricePerMinute   float64   `gorm:"default:0" json:"price_per_minute,omitempty"` // Video per-minute pricing
 	MaxTokens        int       `gorm:"default:4096" json:"max_tokens"`
-	IsActive         bool      `gorm:"default:true" json:"is_active"`
-	Provider         Provider  `gorm:"foreignKey:ProviderID" json:"-"`
+	ContextWindow    int       `gorm:"default:0" json:"context_window"` // 0 = unknown
+	// Capability flags, maintained by operators alongside pricing.
+	SupportsStreaming bool     `gorm:"default:true" json:"supports_streaming"`
+	SupportsVision    bool     `gorm:"default:false" json:"supports_vision"`
+	SupportsTools     bool     `gorm:"default:false" json:"supports_tools"`
+	IsActive          bool     `gorm:"default:true" json:"is_active"`
+	Provider          Provider `gorm:"foreignKey:ProviderID" json:"-"`
 }
 
 // ProviderAPIKey represents a provider-specific API key.
@@ -66,7 +134,10 @@ type ProviderAPIKey struct {
 	Priority        int       `gorm:"default:1" json:"priority"` // 1 is highest priority
 	Weight          float64   `gorm:"default:1.0" json:"weight"`
 	RateLimit       int       `gorm:"default:0" json:"rate_limit"`
-	UsageCount      int64     `gorm:"default:0" json:"usage_count"`
-	LastUsedAt      time.Time `json:"last_used_at"`
-	Provider        Provider  `gorm:"foreignKey:ProviderID" json:"-"`
+	// Monthly caps (calendar month, UTC) enforced by the router; 0 = unlimited.
+	MonthlyRequestLimit int64     `gorm:"default:0" json:"monthly_request_limit"`
+	MonthlyBudgetUSD    float64   `gorm:"default:0" json:"monthly_budget_usd"`
+	UsageCount          int64     `gorm:"default:0" json:"usage_count"`
+	LastUsedAt          time.Time `json:"last_used_at"`
+	Provider            Provider  `gorm:"foreignKey:ProviderID" json:"-"`
 }
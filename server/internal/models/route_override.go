@@ -0,0 +1,18 @@
+package models
+
+import "github.com/google/uuid"
+
+// ModelRouteOverride pins every request for a model to one provider and,
+// optionally, one of its API keys. Overrides are consulted before routing
+// rules, model heuristics and the selection strategy.
+type ModelRouteOverride struct {
+	BaseModel
+	ModelName   string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"model_name"` // exact match
+	ProviderID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"provider_id"`
+	APIKeyID    *uuid.UUID `gorm:"type:uuid" json:"api_key_id,omitempty"` // nil = any key of the provider
+	Description string     `gorm:"type:text" json:"description"`
+	IsEnabled   bool       `gorm:"not null;default:true" json:"is_enabled"`
+	// AllowFallback lets requests route normally while the pinned provider or
+	// key is unavailable. When false they fail fast instead.
+	AllowFallback bool `gorm:"not null;default:false" json:"allow_fallback"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShadowLog records one request mirrored to a shadow provider, next to the
+// primary response it is compared against. Primary tokens and cost live on
+// the linked usage log; shadow requests are never billed. Both response texts
+// are stored encrypted, and left empty when no encryption key is configured.
+type ShadowLog struct {
+	ID                uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt         time.Time `gorm:"index" json:"created_at"`
+	UsageLogID        uuid.UUID `gorm:"type:uuid;index" json:"usage_log_id"`
+	PrimaryProviderID uuid.UUID `gorm:"type:uuid" json:"primary_provider_id"`
+	ShadowProviderID  uuid.UUID `gorm:"type:uuid;index" json:"shadow_provider_id"`
+	ModelName         string    `gorm:"type:varchar(255)" json:"model_name"`
+	ShadowModelName   string    `gorm:"type:varchar(255)" json:"shadow_model_name"`
+	Streamed          bool      `json:"streamed"`        // primary was served as SSE; the shadow always runs non-streaming
+	PrimaryLatency    int64     `json:"primary_latency"` // ms
+	PrimaryResponse   string    `gorm:"type:text" json:"primary_response"`
+	ShadowLatency     int64     `json:"shadow_latency"` // ms
+	PromptTokens      int       `json:"prompt_tokens"`
+	CompletionTokens  int       `json:"completion_tokens"`
+	Cost              float64   `gorm:"type:decimal(20,6);default:0" json:"cost"` // shadow cost at its model price
+	ShadowResponse    string    `gorm:"type:text" json:"shadow_response"`
+	StatusCode        int       `json:"status_code"`
+	ErrorMessage      string    `gorm:"type:text" json:"error_message,omitempty"`
+}
@@ -27,6 +27,48 @@ func (a StringArray) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
 
+// Float64Array is a JSONB-backed list of numbers.
+type Float64Array []float64
+
+func (a *Float64Array) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, &a)
+}
+
+func (a Float64Array) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(a)
+}
+
+// StringMap is a JSONB-backed string map. An empty map is stored as NULL.
+type StringMap map[string]string
+
+func (m *StringMap) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, m)
+}
+
+func (m StringMap) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
 // WebhookEndpoint represents a destination URL configured by a tenant to receive events.
 // A webhook belongs to a specific Project.
 type WebhookEndpoint struct {
@@ -0,0 +1,80 @@
+// Package repository provides database access layer.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"llm-router-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AlertDeliveryRepository handles the alert webhook retry queue.
+type AlertDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertDeliveryRepository creates a new alert delivery repository.
+func NewAlertDeliveryRepository(db *gorm.DB) *AlertDeliveryRepository {
+	return &AlertDeliveryRepository{db: db}
+}
+
+// Create queues a delivery.
+func (r *AlertDeliveryRepository) Create(ctx context.Context, d *models.AlertDelivery) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+// Update saves the outcome of a delivery attempt.
+func (r *AlertDeliveryRepository) Update(ctx context.Context, d *models.AlertDelivery) error {
+	return r.db.WithContext(ctx).Save(d).Error
+}
+
+// ClaimDue returns up to limit pending deliveries whose next attempt is due
+// and pushes their next attempt out by lease, so other replicas polling the
+// queue skip them while this one delivers.
+func (r *AlertDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.AlertDelivery, error) {
+	var deliveries []models.AlertDelivery
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE alert_deliveries SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM alert_deliveries
+			WHERE status = ? AND next_attempt_at <= ? AND deleted_at IS NULL
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, models.AlertDeliveryPending, now, limit,
+	).Scan(&deliveries).Error
+	return deliveries, err
+}
+
+// CountByStatus returns the number of deliveries in each status.
+func (r *AlertDeliveryRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.AlertDelivery{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// DeleteFinishedBefore removes delivered and dead-lettered deliveries last
+// updated before the given time. Pending deliveries are kept.
+func (r *AlertDeliveryRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("status IN ? AND updated_at < ?", []string{models.AlertDeliveryDelivered, models.AlertDeliveryDead}, before).
+		Delete(&models.AlertDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -28,7 +28,7 @@ func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error
 func (r *AlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Alert, error) {
 	var alert models.Alert
 	if err := r.db.WithContext(ctx).First(&alert, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &alert, nil
 }
@@ -91,7 +91,7 @@ func (r *AlertConfigRepository) GetByTarget(ctx context.Context, targetType stri
 	var config models.AlertConfig
 	if err := r.db.WithContext(ctx).
 		First(&config, "target_type = ? AND target_id = ?", targetType, targetID).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &config, nil
 }
@@ -5,6 +5,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -32,7 +33,7 @@ func (r *AuditLogRepository) Create(ctx context.Context, entry *models.AuditLog)
 		var lastLog models.AuditLog
 		if err := tx.Order("created_at DESC, id DESC").First(&lastLog).Error; err == nil {
 			entry.PreviousHash = lastLog.Signature
-		} else if err == gorm.ErrRecordNotFound {
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
 			entry.PreviousHash = "genesis"
 		} else {
 			return err
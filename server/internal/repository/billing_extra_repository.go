@@ -25,7 +25,7 @@ func (r *PlanRepository) Create(ctx context.Context, plan *models.Plan) error {
 func (r *PlanRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Plan, error) {
 	var plan models.Plan
 	if err := r.db.WithContext(ctx).First(&plan, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &plan, nil
 }
@@ -33,7 +33,7 @@ func (r *PlanRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Pla
 func (r *PlanRepository) GetByName(ctx context.Context, name string) (*models.Plan, error) {
 	var plan models.Plan
 	if err := r.db.WithContext(ctx).First(&plan, "name = ?", name).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &plan, nil
 }
@@ -79,7 +79,7 @@ func (r *SubscriptionRepository) Create(ctx context.Context, sub *models.Subscri
 func (r *SubscriptionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.Subscription, error) {
 	var sub models.Subscription
 	if err := r.db.WithContext(ctx).Preload("Plan").First(&sub, "org_id = ?", userID).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &sub, nil
 }
@@ -88,7 +88,7 @@ func (r *SubscriptionRepository) GetByUserID(ctx context.Context, userID uuid.UU
 func (r *SubscriptionRepository) GetByStripeCustomerID(ctx context.Context, customerID string) (*models.Subscription, error) {
 	var sub models.Subscription
 	err := r.db.WithContext(ctx).Where("stripe_customer_id = ?", customerID).First(&sub).Error
-	return &sub, err
+	return &sub, translateError(err)
 }
 
 func (r *SubscriptionRepository) Update(ctx context.Context, sub *models.Subscription) error {
@@ -110,7 +110,7 @@ func (r *SubscriptionRepository) CreateOrder(ctx context.Context, order *models.
 func (r *SubscriptionRepository) GetOrderByNo(ctx context.Context, orderNo string) (*models.Order, error) {
 	var order models.Order
 	if err := r.db.WithContext(ctx).First(&order, "order_no = ?", orderNo).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &order, nil
 }
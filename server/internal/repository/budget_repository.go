@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"llm-router-platform/internal/models"
 
@@ -24,10 +25,12 @@ func NewBudgetRepository(db *gorm.DB) *BudgetRepository {
 func (r *BudgetRepository) Upsert(ctx context.Context, budget *models.Budget) error {
 	var existing models.Budget
 	err := r.db.WithContext(ctx).Where("org_id = ?", budget.OrgID).First(&existing).Error
-	if err != nil {
-		// Not found — create
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return r.db.WithContext(ctx).Create(budget).Error
 	}
+	if err != nil {
+		return err
+	}
 	// Found — update
 	existing.MonthlyLimitUSD = budget.MonthlyLimitUSD
 	existing.AlertThreshold = budget.AlertThreshold
@@ -42,7 +45,7 @@ func (r *BudgetRepository) Upsert(ctx context.Context, budget *models.Budget) er
 func (r *BudgetRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.Budget, error) {
 	var budget models.Budget
 	if err := r.db.WithContext(ctx).Where("org_id = ?", userID).First(&budget).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &budget, nil
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"llm-router-platform/internal/models"
 
@@ -20,7 +21,7 @@ func NewConfigRepository(db *gorm.DB) *ConfigRepository {
 func (r *ConfigRepository) Get(ctx context.Context, key string) (*models.SystemConfig, error) {
 	var cfg models.SystemConfig
 	if err := r.db.WithContext(ctx).First(&cfg, "key = ?", key).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &cfg, nil
 }
@@ -28,10 +29,12 @@ func (r *ConfigRepository) Get(ctx context.Context, key string) (*models.SystemC
 func (r *ConfigRepository) Set(ctx context.Context, cfg *models.SystemConfig) error {
 	var existing models.SystemConfig
 	err := r.db.WithContext(ctx).First(&existing, "key = ?", cfg.Key).Error
-	if err != nil {
-		// Not found — create
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return r.db.WithContext(ctx).Create(cfg).Error
 	}
+	if err != nil {
+		return err
+	}
 	// Found — update
 	existing.Value = cfg.Value
 	existing.Description = cfg.Description
@@ -0,0 +1,43 @@
+package repository
+
+import "gorm.io/gorm"
+
+// CreateKeepingFalse inserts value and then writes the given boolean columns
+// that are false, in one transaction. gorm leaves zero values out of an
+// INSERT when the column has a default, so without this a false field on a
+// `default:true` column is stored as true.
+//
+// db may carry an ON CONFLICT DO NOTHING clause; a row it skips is left
+// untouched. Such callers must leave the primary key to the database, as a
+// skipped insert is told apart by the key staying unset.
+func CreateKeepingFalse(db *gorm.DB, value interface{}, columns map[string]bool) error {
+	falses := make(map[string]interface{})
+	for col, v := range columns {
+		if !v {
+			falses[col] = false
+		}
+	}
+	// The session copies the statement db carries, so a savepoint taken when
+	// db is already inside a transaction does not leak into the writes below.
+	return db.Session(&gorm.Session{}).Transaction(func(tx *gorm.DB) error {
+		res := tx.Create(value)
+		if res.Error != nil {
+			return res.Error
+		}
+		if len(falses) == 0 || !inserted(res) {
+			return nil
+		}
+		return tx.Model(value).UpdateColumns(falses).Error
+	})
+}
+
+// inserted reports whether the Create in res stored a row, judged by its
+// primary key having been set.
+func inserted(res *gorm.DB) bool {
+	pk := res.Statement.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return true
+	}
+	_, zero := pk.ValueOf(res.Statement.Context, res.Statement.ReflectValue)
+	return !zero
+}
@@ -28,7 +28,7 @@ func (r *ErrorLogRepository) Create(ctx context.Context, log *models.ErrorLog) e
 func (r *ErrorLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ErrorLog, error) {
 	var log models.ErrorLog
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&log).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &log, nil
 }
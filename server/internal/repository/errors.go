@@ -0,0 +1,34 @@
+// Package repository provides database access layer.
+// This file contains translation of driver errors callers need to act on.
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrDuplicateKey is returned when a write violates a unique constraint.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrNotFound is returned when a lookup matches no row. It wraps
+// gorm.ErrRecordNotFound, so errors.Is matches either.
+var ErrNotFound = fmt.Errorf("not found: %w", gorm.ErrRecordNotFound)
+
+// pgUniqueViolation is the PostgreSQL SQLSTATE for unique_violation.
+const pgUniqueViolation = "23505"
+
+// translateError maps a missing row to ErrNotFound and a unique-constraint
+// violation to ErrDuplicateKey, and returns any other error unchanged.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("%w: %s", ErrDuplicateKey, pgErr.ConstraintName)
+	}
+	return err
+}
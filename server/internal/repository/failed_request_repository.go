@@ -0,0 +1,118 @@
+// Package repository provides database access layer.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FailedRequestRepository handles the dead-letter log of chat requests that
+// failed on every provider and key.
+type FailedRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewFailedRequestRepository creates a new failed request repository.
+func NewFailedRequestRepository(db *gorm.DB) *FailedRequestRepository {
+	return &FailedRequestRepository{db: db}
+}
+
+// FailedRequestFilter narrows a failed request listing. Empty fields match all.
+type FailedRequestFilter struct {
+	ModelName string
+	Provider  string // provider name appearing in any attempt
+	Since     time.Time
+}
+
+// FailedRequestCountRow is the number of failed requests for one model or
+// provider.
+type FailedRequestCountRow struct {
+	Name     string `json:"name"`
+	Requests int64  `json:"requests"`
+}
+
+// Create inserts a new failed request record.
+func (r *FailedRequestRepository) Create(ctx context.Context, req *models.FailedRequest) error {
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+// List returns failed requests matching filter, newest first, with the total
+// number of matches.
+func (r *FailedRequestRepository) List(ctx context.Context, filter FailedRequestFilter, limit, offset int) ([]models.FailedRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.FailedRequest{})
+	if filter.ModelName != "" {
+		query = query.Where("model_name = ?", filter.ModelName)
+	}
+	if filter.Provider != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements(attempts) a WHERE a->>'provider' = ?)", filter.Provider)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var reqs []models.FailedRequest
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&reqs).Error; err != nil {
+		return nil, 0, err
+	}
+	return reqs, total, nil
+}
+
+// GetByID returns one failed request, including its captured payload.
+func (r *FailedRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.FailedRequest, error) {
+	var req models.FailedRequest
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&req).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &req, nil
+}
+
+// CountByModelSince returns the failed requests per model since the given time.
+func (r *FailedRequestRepository) CountByModelSince(ctx context.Context, since time.Time) ([]FailedRequestCountRow, error) {
+	var rows []FailedRequestCountRow
+	err := r.db.WithContext(ctx).Model(&models.FailedRequest{}).
+		Select("model_name AS name, COUNT(id) AS requests").
+		Where("created_at >= ?", since).
+		Group("model_name").
+		Order("requests DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CountByProviderSince returns, per provider, the failed requests since the
+// given time that attempted it. A request that tried several providers counts
+// once for each.
+func (r *FailedRequestRepository) CountByProviderSince(ctx context.Context, since time.Time) ([]FailedRequestCountRow, error) {
+	var rows []FailedRequestCountRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT a->>'provider' AS name, COUNT(DISTINCT f.id) AS requests
+		FROM failed_requests f, jsonb_array_elements(f.attempts) a
+		WHERE f.created_at >= ?
+		GROUP BY 1
+		ORDER BY requests DESC`, since,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// DeleteOlderThan removes failed requests created before the given time.
+func (r *FailedRequestRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.FailedRequest{})
+	return result.RowsAffected, result.Error
+}
+
+// ClearCapturesOlderThan drops the captured payloads of failed requests
+// created before the given time, keeping the records themselves.
+func (r *FailedRequestRepository) ClearCapturesOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.FailedRequest{}).
+		Where("captured AND created_at < ?", before).
+		Updates(map[string]interface{}{"captured": false, "payload": "", "error_bodies": ""})
+	return result.RowsAffected, result.Error
+}
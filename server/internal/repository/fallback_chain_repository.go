@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FallbackChainRepository struct {
+	db *gorm.DB
+}
+
+// NewFallbackChainRepository creates a new FallbackChainRepo.
+func NewFallbackChainRepository(db *gorm.DB) FallbackChainRepo {
+	return &FallbackChainRepository{db: db}
+}
+
+// Create inserts a chain. IsEnabled defaults to true in the schema, so a
+// disabled chain is written explicitly.
+func (r *FallbackChainRepository) Create(ctx context.Context, chain *models.FallbackChain) error {
+	return CreateKeepingFalse(r.db.WithContext(ctx), chain, map[string]bool{"is_enabled": chain.IsEnabled})
+}
+
+func (r *FallbackChainRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.FallbackChain, error) {
+	var chain models.FallbackChain
+	if err := r.db.WithContext(ctx).First(&chain, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &chain, nil
+}
+
+func (r *FallbackChainRepository) GetAll(ctx context.Context) ([]models.FallbackChain, error) {
+	var chains []models.FallbackChain
+	err := r.db.WithContext(ctx).Order("priority DESC, created_at DESC").Find(&chains).Error
+	return chains, err
+}
+
+func (r *FallbackChainRepository) GetActive(ctx context.Context) ([]models.FallbackChain, error) {
+	var chains []models.FallbackChain
+	err := r.db.WithContext(ctx).Where("is_enabled = ?", true).Order("priority DESC, created_at ASC").Find(&chains).Error
+	return chains, err
+}
+
+func (r *FallbackChainRepository) Update(ctx context.Context, chain *models.FallbackChain) error {
+	return r.db.WithContext(ctx).Save(chain).Error
+}
+
+func (r *FallbackChainRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.FallbackChain{}, "id = ?", id).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"llm-router-platform/internal/models"
 
@@ -49,3 +50,32 @@ func (r *HealthHistoryRepository) GetRecent(ctx context.Context, targetType stri
 	}
 	return histories, nil
 }
+
+// HealthTrendRow holds the health checks of one time bucket.
+type HealthTrendRow struct {
+	BucketStart     time.Time `json:"bucket_start"`
+	Checks          int64     `json:"checks"`
+	Healthy         int64     `json:"healthy"`
+	AvgResponseTime float64   `json:"avg_response_time"`
+}
+
+// GetTrend groups the health checks of a target since the given time into
+// epoch-aligned buckets, oldest first. Buckets without checks are omitted.
+// The average response time covers healthy checks only, so timeouts do not
+// mask the latency of a recovering target.
+func (r *HealthHistoryRepository) GetTrend(ctx context.Context, targetType string, targetID uuid.UUID, bucket time.Duration, since time.Time) ([]HealthTrendRow, error) {
+	secs := int64(bucket / time.Second)
+	var rows []HealthTrendRow
+	if err := r.db.WithContext(ctx).Model(&models.HealthHistory{}).
+		Select(`to_timestamp(floor(extract(epoch from checked_at) / ?) * ?) AS bucket_start,
+				COUNT(*) AS checks,
+				COALESCE(SUM(CASE WHEN is_healthy THEN 1 ELSE 0 END), 0) AS healthy,
+				COALESCE(AVG(response_time) FILTER (WHERE is_healthy), 0) AS avg_response_time`, secs, secs).
+		Where("target_type = ? AND target_id = ? AND checked_at >= ?", targetType, targetID, since).
+		Group("bucket_start").
+		Order("bucket_start").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
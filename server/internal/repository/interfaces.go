@@ -21,6 +21,7 @@ type UserRepo interface {
 	GetAll(ctx context.Context) ([]models.User, error)
 	Count(ctx context.Context) (int64, error)
 	CountActiveUsers(ctx context.Context, since time.Time) (int64, error)
+	CountActiveUsersInOrg(ctx context.Context, orgID uuid.UUID, since time.Time) (int64, error)
 	Search(ctx context.Context, query string) ([]models.User, error)
 }
 
@@ -91,6 +92,7 @@ type ProxyRepo interface {
 	GetActive(ctx context.Context) ([]models.Proxy, error)
 	GetAll(ctx context.Context) ([]models.Proxy, error)
 	Update(ctx context.Context, proxy *models.Proxy) error
+	ResetStats(ctx context.Context, ids []uuid.UUID) (int64, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
@@ -110,6 +112,9 @@ type UsageLogRepo interface {
 	AggregateDailyByTimeRange(ctx context.Context, orgID *uuid.UUID, projectID *uuid.UUID, channel *string, start, end time.Time) ([]DailyUsageRow, error)
 	AggregateByProviderByTimeRange(ctx context.Context, orgID *uuid.UUID, projectID *uuid.UUID, channel *string, start, end time.Time) ([]ProviderUsageRow, error)
 	AggregateByModelByTimeRange(ctx context.Context, orgID *uuid.UUID, projectID *uuid.UUID, channel *string, start, end time.Time) ([]ModelUsageRow, error)
+	AggregateByTagByTimeRange(ctx context.Context, orgID *uuid.UUID, projectID *uuid.UUID, tagKey string, start, end time.Time) ([]TagUsageRow, error)
+	AggregateByProviderKeySince(ctx context.Context, keyIDs []uuid.UUID, since time.Time) ([]ProviderKeyUsageRow, error)
+	SumCostByAPIKeySince(ctx context.Context, apiKeyID uuid.UUID, since time.Time) (float64, error)
 }
 
 // ErrorLogRepo defines the interface for error log data access.
@@ -118,11 +123,29 @@ type ErrorLogRepo interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.ErrorLog, error)
 }
 
+// ShadowLogRepo defines the interface for shadow traffic log data access.
+type ShadowLogRepo interface {
+	Create(ctx context.Context, log *models.ShadowLog) error
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+// FailedRequestRepo defines the interface for the failed request dead-letter log.
+type FailedRequestRepo interface {
+	Create(ctx context.Context, req *models.FailedRequest) error
+	List(ctx context.Context, filter FailedRequestFilter, limit, offset int) ([]models.FailedRequest, int64, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.FailedRequest, error)
+	CountByModelSince(ctx context.Context, since time.Time) ([]FailedRequestCountRow, error)
+	CountByProviderSince(ctx context.Context, since time.Time) ([]FailedRequestCountRow, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+	ClearCapturesOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
 // HealthHistoryRepo defines the interface for health history data access.
 type HealthHistoryRepo interface {
 	Create(ctx context.Context, history *models.HealthHistory) error
 	GetByTarget(ctx context.Context, targetType string, targetID uuid.UUID, limit int) ([]models.HealthHistory, error)
 	GetRecent(ctx context.Context, targetType string, limit int) ([]models.HealthHistory, error)
+	GetTrend(ctx context.Context, targetType string, targetID uuid.UUID, bucket time.Duration, since time.Time) ([]HealthTrendRow, error)
 }
 
 // ConversationMemoryRepo defines the interface for conversation memory data access.
@@ -131,6 +154,9 @@ type ConversationMemoryRepo interface {
 	GetByConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) ([]models.ConversationMemory, error)
 	DeleteByConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) error
 	DeleteOldestByConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string, count int) error
+	DeleteOldestNonSystemByConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string, count int) error
+	MaxSequence(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) (int, error)
+	CountByConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) (int64, error)
 	ListConversationIDs(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID) ([]string, error)
 }
 
@@ -151,6 +177,15 @@ type AlertConfigRepo interface {
 	GetAll(ctx context.Context) ([]models.AlertConfig, error)
 }
 
+// AlertDeliveryRepo defines the interface for the alert webhook retry queue.
+type AlertDeliveryRepo interface {
+	Create(ctx context.Context, d *models.AlertDelivery) error
+	Update(ctx context.Context, d *models.AlertDelivery) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.AlertDelivery, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
 // BudgetRepo defines the interface for budget data access.
 type BudgetRepo interface {
 	Upsert(ctx context.Context, budget *models.Budget) error
@@ -158,6 +193,12 @@ type BudgetRepo interface {
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
+// SpendAlertRepo defines the interface for API key spend alert data access.
+type SpendAlertRepo interface {
+	MarkFired(ctx context.Context, alert *models.APIKeySpendAlert) (bool, error)
+	Unmark(ctx context.Context, alert *models.APIKeySpendAlert) error
+}
+
 // TaskRepo defines the interface for task data access.
 type TaskRepo interface {
 	Create(ctx context.Context, task *models.AsyncTask) error
@@ -247,6 +288,25 @@ type RoutingRuleRepo interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// FallbackChainRepo defines the interface for explicit provider fallback chains.
+type FallbackChainRepo interface {
+	Create(ctx context.Context, chain *models.FallbackChain) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.FallbackChain, error)
+	GetAll(ctx context.Context) ([]models.FallbackChain, error)
+	GetActive(ctx context.Context) ([]models.FallbackChain, error)
+	Update(ctx context.Context, chain *models.FallbackChain) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ModelRouteOverrideRepo defines the interface for per-model routing overrides.
+type ModelRouteOverrideRepo interface {
+	Create(ctx context.Context, o *models.ModelRouteOverride) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ModelRouteOverride, error)
+	GetAll(ctx context.Context) ([]models.ModelRouteOverride, error)
+	Update(ctx context.Context, o *models.ModelRouteOverride) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
 // Compile-time interface satisfaction checks.
 var (
 	_ UserRepo               = (*UserRepository)(nil)
@@ -260,7 +320,9 @@ var (
 	_ ConversationMemoryRepo = (*ConversationMemoryRepository)(nil)
 	_ AlertRepo              = (*AlertRepository)(nil)
 	_ AlertConfigRepo        = (*AlertConfigRepository)(nil)
+	_ AlertDeliveryRepo      = (*AlertDeliveryRepository)(nil)
 	_ BudgetRepo             = (*BudgetRepository)(nil)
+	_ SpendAlertRepo         = (*SpendAlertRepository)(nil)
 	_ TaskRepo               = (*TaskRepository)(nil)
 	_ AuditLogRepo           = (*AuditLogRepository)(nil)
 	_ MCPRepo                = (*MCPRepository)(nil)
@@ -269,5 +331,9 @@ var (
 	_ TransactionRepo        = (*TransactionRepository)(nil)
 	_ ConfigRepo             = (*ConfigRepository)(nil)
 	_ RoutingRuleRepo        = (*RoutingRuleRepository)(nil)
+	_ FallbackChainRepo      = (*FallbackChainRepository)(nil)
+	_ ModelRouteOverrideRepo = (*ModelRouteOverrideRepository)(nil)
 	_ ErrorLogRepo           = (*ErrorLogRepository)(nil)
+	_ ShadowLogRepo          = (*ShadowLogRepository)(nil)
+	_ FailedRequestRepo      = (*FailedRequestRepository)(nil)
 )
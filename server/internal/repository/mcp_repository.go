@@ -28,7 +28,7 @@ func (r *MCPRepository) CreateServer(ctx context.Context, server *models.MCPServ
 func (r *MCPRepository) GetServerByID(ctx context.Context, id uuid.UUID) (*models.MCPServer, error) {
 	var server models.MCPServer
 	if err := r.db.WithContext(ctx).First(&server, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &server, nil
 }
@@ -37,7 +37,7 @@ func (r *MCPRepository) GetServerByID(ctx context.Context, id uuid.UUID) (*model
 func (r *MCPRepository) GetServerByName(ctx context.Context, name string) (*models.MCPServer, error) {
 	var server models.MCPServer
 	if err := r.db.WithContext(ctx).First(&server, "name = ?", name).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &server, nil
 }
@@ -142,7 +142,7 @@ func (r *MCPRepository) GetToolByName(ctx context.Context, serverName, toolName
 		Where("mcp_servers.name = ? AND mcp_tools.name = ?", serverName, toolName).
 		First(&tool).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &tool, nil
 }
@@ -19,9 +19,10 @@ func NewConversationMemoryRepository(db *gorm.DB) *ConversationMemoryRepository
 	return &ConversationMemoryRepository{db: db}
 }
 
-// Create inserts a new conversation memory.
+// Create inserts a new conversation memory. A sequence number already used in
+// the conversation yields ErrDuplicateKey.
 func (r *ConversationMemoryRepository) Create(ctx context.Context, memory *models.ConversationMemory) error {
-	return r.db.WithContext(ctx).Create(memory).Error
+	return translateError(r.db.WithContext(ctx).Create(memory).Error)
 }
 
 // scopeQuery builds a query scoped to project, conversation, and optionally API key.
@@ -72,6 +73,47 @@ func (r *ConversationMemoryRepository) DeleteOldestByConversation(ctx context.Co
 		Delete(&models.ConversationMemory{}).Error
 }
 
+// MaxSequence returns the highest sequence number in a conversation, or 0 when it is empty.
+func (r *ConversationMemoryRepository) MaxSequence(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) (int, error) {
+	var maxSeq int
+	err := r.scopeQuery(ctx, projectID, apiKeyID, conversationID).
+		Model(&models.ConversationMemory{}).
+		Select("COALESCE(MAX(sequence), 0)").
+		Scan(&maxSeq).Error
+	return maxSeq, err
+}
+
+// CountByConversation returns the number of messages stored for a conversation.
+func (r *ConversationMemoryRepository) CountByConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) (int64, error) {
+	var count int64
+	err := r.scopeQuery(ctx, projectID, apiKeyID, conversationID).
+		Model(&models.ConversationMemory{}).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteOldestNonSystemByConversation deletes the oldest N non-system messages
+// from a conversation, leaving system messages in place.
+func (r *ConversationMemoryRepository) DeleteOldestNonSystemByConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string, count int) error {
+	var ids []uuid.UUID
+	if err := r.scopeQuery(ctx, projectID, apiKeyID, conversationID).
+		Model(&models.ConversationMemory{}).
+		Where("role <> ?", "system").
+		Order("sequence ASC").
+		Limit(count).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Unscoped().
+		Where("id IN ?", ids).
+		Delete(&models.ConversationMemory{}).Error
+}
+
 // ListConversationIDs returns all conversation IDs for a project scoped to API key.
 func (r *ConversationMemoryRepository) ListConversationIDs(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID) ([]string, error) {
 	var ids []string
@@ -33,7 +33,7 @@ func (r *OrganizationRepository) Create(ctx context.Context, org *models.Organiz
 func (r *OrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
 	var org models.Organization
 	if err := r.db.WithContext(ctx).First(&org, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &org, nil
 }
@@ -71,7 +71,7 @@ func (r *ProjectRepository) Create(ctx context.Context, project *models.Project)
 func (r *ProjectRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Project, error) {
 	var project models.Project
 	if err := r.db.WithContext(ctx).Preload("DlpConfig").First(&project, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &project, nil
 }
@@ -30,7 +30,7 @@ func (r *PromptRepository) List(ctx context.Context) ([]models.PromptTemplate, e
 func (r *PromptRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PromptTemplate, error) {
 	var t models.PromptTemplate
 	err := r.db.WithContext(ctx).First(&t, "id = ?", id).Error
-	return &t, err
+	return &t, translateError(err)
 }
 
 // Create creates a new prompt template.
@@ -59,7 +59,7 @@ func (r *PromptRepository) ListVersions(ctx context.Context, templateID uuid.UUI
 func (r *PromptRepository) GetVersionByID(ctx context.Context, id uuid.UUID) (*models.PromptVersion, error) {
 	var v models.PromptVersion
 	err := r.db.WithContext(ctx).First(&v, "id = ?", id).Error
-	return &v, err
+	return &v, translateError(err)
 }
 
 // CreateVersion creates a new version, auto-incrementing the version number.
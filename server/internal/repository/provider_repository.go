@@ -21,16 +21,19 @@ func NewProviderRepository(db *gorm.DB) *ProviderRepository {
 	return &ProviderRepository{db: db}
 }
 
-// Create inserts a new provider.
+// Create inserts a new provider. A taken name yields ErrDuplicateKey.
 func (r *ProviderRepository) Create(ctx context.Context, provider *models.Provider) error {
-	return r.db.WithContext(ctx).Create(provider).Error
+	return translateError(CreateKeepingFalse(r.db.WithContext(ctx), provider, map[string]bool{
+		"is_active":        provider.IsActive,
+		"requires_api_key": provider.RequiresAPIKey,
+	}))
 }
 
 // GetByID retrieves a provider by ID.
 func (r *ProviderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Provider, error) {
 	var provider models.Provider
 	if err := r.db.WithContext(ctx).First(&provider, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &provider, nil
 }
@@ -39,7 +42,7 @@ func (r *ProviderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 func (r *ProviderRepository) GetByName(ctx context.Context, name string) (*models.Provider, error) {
 	var provider models.Provider
 	if err := r.db.WithContext(ctx).First(&provider, "name = ?", name).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &provider, nil
 }
@@ -64,7 +67,7 @@ func (r *ProviderRepository) GetAll(ctx context.Context) ([]models.Provider, err
 
 // Update updates a provider.
 func (r *ProviderRepository) Update(ctx context.Context, provider *models.Provider) error {
-	return r.db.WithContext(ctx).Save(provider).Error
+	return translateError(r.db.WithContext(ctx).Save(provider).Error)
 }
 
 // Delete permanently removes a provider by ID.
@@ -109,7 +112,7 @@ func (r *ProviderAPIKeyRepository) GetByProvider(ctx context.Context, providerID
 func (r *ProviderAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ProviderAPIKey, error) {
 	var key models.ProviderAPIKey
 	if err := r.db.WithContext(ctx).First(&key, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &key, nil
 }
@@ -147,7 +150,7 @@ func NewModelRepository(db *gorm.DB) *ModelRepository {
 func (r *ModelRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Model, error) {
 	var model models.Model
 	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &model, nil
 }
@@ -156,7 +159,7 @@ func (r *ModelRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Mo
 func (r *ModelRepository) GetByName(ctx context.Context, name string) (*models.Model, error) {
 	var model models.Model
 	if err := r.db.WithContext(ctx).First(&model, "name = ?", name).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &model, nil
 }
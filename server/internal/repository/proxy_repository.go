@@ -28,7 +28,7 @@ func (r *ProxyRepository) Create(ctx context.Context, proxy *models.Proxy) error
 func (r *ProxyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Proxy, error) {
 	var proxy models.Proxy
 	if err := r.db.WithContext(ctx).First(&proxy, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &proxy, nil
 }
@@ -56,6 +56,23 @@ func (r *ProxyRepository) Update(ctx context.Context, proxy *models.Proxy) error
 	return r.db.WithContext(ctx).Save(proxy).Error
 }
 
+// ResetStats zeroes the success/failure counters and average latency of the
+// given proxies, or of every proxy when ids is empty. Returns the number reset.
+func (r *ProxyRepository) ResetStats(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.Proxy{})
+	if len(ids) > 0 {
+		q = q.Where("id IN ?", ids)
+	} else {
+		q = q.Where("1 = 1") // GORM refuses bulk updates without a condition
+	}
+	result := q.Updates(map[string]interface{}{
+		"success_count": 0,
+		"failure_count": 0,
+		"avg_latency":   0,
+	})
+	return result.RowsAffected, result.Error
+}
+
 // Delete permanently removes a proxy from the database.
 func (r *ProxyRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Unscoped().Delete(&models.Proxy{}, "id = ?", id).Error
@@ -1,10 +1,20 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"llm-router-platform/internal/models"
 )
@@ -173,3 +183,92 @@ func TestConversationMemoryModelFields(t *testing.T) {
 	assert.Equal(t, "user", memory.Role)
 	assert.Equal(t, 1, memory.Sequence)
 }
+
+func TestTranslateErrorUniqueViolation(t *testing.T) {
+	dup := fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_providers_name"})
+	assert.ErrorIs(t, translateError(dup), ErrDuplicateKey)
+
+	other := &pgconn.PgError{Code: "23503"}
+	assert.Equal(t, error(other), translateError(other))
+	assert.NoError(t, translateError(nil))
+	assert.False(t, errors.Is(translateError(errors.New("boom")), ErrDuplicateKey))
+}
+
+func TestTranslateErrorNotFound(t *testing.T) {
+	err := translateError(gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "callers matching the gorm error keep working")
+	assert.False(t, errors.Is(translateError(errors.New("connection refused")), ErrNotFound))
+}
+
+func TestCountActiveUsersInOrgScopesToOrganization(t *testing.T) {
+	// Dry-run mode renders the query without a database connection.
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 sslmode=disable"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var queries []string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}))
+
+	orgID := uuid.New()
+	_, err = NewUserRepository(db).CountActiveUsersInOrg(context.Background(), orgID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], `COUNT(DISTINCT("usage_logs"."user_id"))`)
+	assert.Contains(t, queries[0], "JOIN projects ON usage_logs.project_id = projects.id")
+	assert.Contains(t, queries[0], fmt.Sprintf("projects.org_id = '%s'", orgID))
+}
+
+// dryRunPool lets dry-run sessions open transactions without a database.
+type dryRunPool struct{ gorm.ConnPool }
+
+func (p *dryRunPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) { return p, nil }
+func (p *dryRunPool) Commit() error                                                  { return nil }
+func (p *dryRunPool) Rollback() error                                                { return nil }
+
+func TestFallbackChainCreateKeepsDisabled(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunPool{}}), &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	var statements []string
+	capture := func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("test:capture", capture))
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:capture", capture))
+
+	chain := &models.FallbackChain{ID: uuid.New(), Name: "gpt", ModelPattern: "gpt-*", ProviderIDs: models.StringArray{uuid.NewString()}, IsEnabled: false}
+	require.NoError(t, NewFallbackChainRepository(db).Create(context.Background(), chain))
+
+	require.Len(t, statements, 2, "a disabled chain needs a second write; the column defaults to true")
+	assert.Contains(t, statements[1], `"is_enabled"=false`)
+
+	statements = nil
+	chain = &models.FallbackChain{ID: uuid.New(), Name: "gpt", ModelPattern: "gpt-*", ProviderIDs: models.StringArray{uuid.NewString()}, IsEnabled: true}
+	require.NoError(t, NewFallbackChainRepository(db).Create(context.Background(), chain))
+	assert.Len(t, statements, 1)
+}
+
+func TestCreateKeepingFalseLeavesSkippedRowsUntouched(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunPool{}}), &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	var updates int
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:count", func(*gorm.DB) { updates++ }))
+
+	seed := func(id uuid.UUID) error {
+		p := &models.Provider{Name: "mistral", BaseURL: "https://api.mistral.ai/v1", IsActive: false}
+		p.ID = id
+		onConflict := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true})
+		return CreateKeepingFalse(onConflict, p, map[string]bool{"is_active": p.IsActive})
+	}
+
+	// The database assigns the key; a conflicting seed gets none back.
+	require.NoError(t, seed(uuid.Nil))
+	assert.Zero(t, updates, "an existing provider keeps its flags")
+	require.NoError(t, seed(uuid.New()))
+	assert.Equal(t, 1, updates, "an inserted provider gets its false flags")
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModelRouteOverrideRepository handles per-model routing override data access.
+type ModelRouteOverrideRepository struct {
+	db *gorm.DB
+}
+
+// NewModelRouteOverrideRepository creates a new ModelRouteOverrideRepo.
+func NewModelRouteOverrideRepository(db *gorm.DB) ModelRouteOverrideRepo {
+	return &ModelRouteOverrideRepository{db: db}
+}
+
+// Create inserts an override. A model that already has one yields ErrDuplicateKey.
+func (r *ModelRouteOverrideRepository) Create(ctx context.Context, o *models.ModelRouteOverride) error {
+	return translateError(CreateKeepingFalse(r.db.WithContext(ctx), o, map[string]bool{"is_enabled": o.IsEnabled}))
+}
+
+// GetByID retrieves an override by ID.
+func (r *ModelRouteOverrideRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ModelRouteOverride, error) {
+	var o models.ModelRouteOverride
+	if err := r.db.WithContext(ctx).First(&o, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &o, nil
+}
+
+// GetAll retrieves every override ordered by model name.
+func (r *ModelRouteOverrideRepository) GetAll(ctx context.Context) ([]models.ModelRouteOverride, error) {
+	var overrides []models.ModelRouteOverride
+	err := r.db.WithContext(ctx).Order("model_name").Find(&overrides).Error
+	return overrides, err
+}
+
+// Update saves an override. Renaming onto another override's model yields ErrDuplicateKey.
+func (r *ModelRouteOverrideRepository) Update(ctx context.Context, o *models.ModelRouteOverride) error {
+	return translateError(r.db.WithContext(ctx).Save(o).Error)
+}
+
+// Delete permanently removes an override so its model name can be reused.
+func (r *ModelRouteOverrideRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.ModelRouteOverride{}, "id = ?", id).Error
+}
@@ -25,7 +25,7 @@ func (r *RoutingRuleRepository) Create(ctx context.Context, rule *models.Routing
 func (r *RoutingRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RoutingRule, error) {
 	var rule models.RoutingRule
 	if err := r.db.WithContext(ctx).Preload("TargetProvider").Preload("FallbackProvider").First(&rule, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &rule, nil
 }
@@ -0,0 +1,32 @@
+// Package repository provides database access layer.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"llm-router-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ShadowLogRepository handles shadow traffic comparison records.
+type ShadowLogRepository struct {
+	db *gorm.DB
+}
+
+// NewShadowLogRepository creates a new shadow log repository.
+func NewShadowLogRepository(db *gorm.DB) *ShadowLogRepository {
+	return &ShadowLogRepository{db: db}
+}
+
+// Create inserts a new shadow log.
+func (r *ShadowLogRepository) Create(ctx context.Context, log *models.ShadowLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// DeleteOlderThan removes shadow logs created before the given time.
+func (r *ShadowLogRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.ShadowLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,40 @@
+// Package repository provides database access layer.
+package repository
+
+import (
+	"context"
+
+	"llm-router-platform/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SpendAlertRepository handles API key spend alert data access.
+type SpendAlertRepository struct {
+	db *gorm.DB
+}
+
+// NewSpendAlertRepository creates a new spend alert repository.
+func NewSpendAlertRepository(db *gorm.DB) *SpendAlertRepository {
+	return &SpendAlertRepository{db: db}
+}
+
+// MarkFired records that a threshold fired for a key in a period. It returns
+// false when the threshold was already recorded, so concurrent checks across
+// replicas notify only once.
+func (r *SpendAlertRepository) MarkFired(ctx context.Context, alert *models.APIKeySpendAlert) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Unmark deletes the record of a threshold that fired, so a later check can
+// fire it again. It is used when the notification could not be delivered.
+func (r *SpendAlertRepository) Unmark(ctx context.Context, alert *models.APIKeySpendAlert) error {
+	return r.db.WithContext(ctx).
+		Where("api_key_id = ? AND period = ? AND threshold_usd = ?", alert.APIKeyID, alert.Period, alert.ThresholdUSD).
+		Delete(&models.APIKeySpendAlert{}).Error
+}
@@ -30,7 +30,7 @@ func (r *TaskRepository) Create(ctx context.Context, task *models.AsyncTask) err
 func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AsyncTask, error) {
 	var task models.AsyncTask
 	if err := r.db.WithContext(ctx).First(&task, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &task, nil
 }
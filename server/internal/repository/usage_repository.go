@@ -30,7 +30,7 @@ func (r *UsageLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	var log models.UsageLog
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&log).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &log, nil
 }
@@ -40,6 +40,23 @@ func (r *UsageLogRepository) Update(ctx context.Context, log *models.UsageLog) e
 	return r.db.WithContext(ctx).Save(log).Error
 }
 
+// MarkStreamDowngraded flags a usage log whose streaming request was served
+// by a non-streaming upstream call.
+func (r *UsageLogRepository) MarkStreamDowngraded(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.UsageLog{}).Where("id = ?", id).Update("stream_downgraded", true).Error
+}
+
+// SetProviderKey attributes a usage log to the provider API key that served it.
+func (r *UsageLogRepository) SetProviderKey(ctx context.Context, id, providerKeyID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.UsageLog{}).Where("id = ?", id).Update("provider_key_id", providerKeyID).Error
+}
+
+// SetProvider attributes a usage log to the provider that served it, when a
+// fallback chain moved the request off the provider it was recorded for.
+func (r *UsageLogRepository) SetProvider(ctx context.Context, id, providerID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.UsageLog{}).Where("id = ?", id).Update("provider_id", providerID).Error
+}
+
 // GetByOrgOrProjectTimeRange retrieves usage logs for a specific org or project.
 func (r *UsageLogRepository) GetByOrgOrProjectTimeRange(ctx context.Context, orgID *uuid.UUID, projectID *uuid.UUID, start, end time.Time) ([]models.UsageLog, error) {
 	var logs []models.UsageLog
@@ -234,6 +251,43 @@ func (r *UsageLogRepository) AggregateByProviderByTimeRange(ctx context.Context,
 	return rows, nil
 }
 
+// ProviderKeyUsageRow holds request and cost totals for one provider API key.
+type ProviderKeyUsageRow struct {
+	ProviderKeyID uuid.UUID `json:"provider_key_id"`
+	Requests      int64     `json:"requests"`
+	Cost          float64   `json:"cost"`
+}
+
+// AggregateByProviderKeySince returns usage per provider API key since the given time.
+// Keys without usage are omitted.
+func (r *UsageLogRepository) AggregateByProviderKeySince(ctx context.Context, keyIDs []uuid.UUID, since time.Time) ([]ProviderKeyUsageRow, error) {
+	if len(keyIDs) == 0 {
+		return nil, nil
+	}
+	var rows []ProviderKeyUsageRow
+	err := r.db.WithContext(ctx).Model(&models.UsageLog{}).
+		Select(`provider_key_id,
+				COUNT(id) AS requests,
+				COALESCE(SUM(cost), 0) AS cost`).
+		Where("provider_key_id IN ? AND created_at >= ?", keyIDs, since).
+		Group("provider_key_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SumCostByAPIKeySince returns the total cost billed to an API key since the given time.
+func (r *UsageLogRepository) SumCostByAPIKeySince(ctx context.Context, apiKeyID uuid.UUID, since time.Time) (float64, error) {
+	var total float64
+	err := r.db.WithContext(ctx).Model(&models.UsageLog{}).
+		Select("COALESCE(SUM(cost), 0)").
+		Where("api_key_id = ? AND created_at >= ?", apiKeyID, since).
+		Scan(&total).Error
+	return total, err
+}
+
 // ModelUsageRow holds a single SQL-aggregated model usage bucket.
 type ModelUsageRow struct {
 	ModelID      uuid.UUID `json:"model_id"`
@@ -275,6 +329,52 @@ func (r *UsageLogRepository) AggregateByModelByTimeRange(ctx context.Context, or
 	return rows, nil
 }
 
+// TagUsageRow holds a single SQL-aggregated attribution tag usage bucket.
+type TagUsageRow struct {
+	TagKey       string  `json:"tag_key"`
+	TagValue     string  `json:"tag_value"`
+	Requests     int64   `json:"requests"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	Cost         float64 `json:"cost"`
+}
+
+// AggregateByTagByTimeRange returns usage grouped by attribution tag key and
+// value (SQL GROUP BY over the expanded tags). A request with several tags
+// counts once under each; untagged requests are left out. A non-empty tagKey
+// restricts the result to that key.
+func (r *UsageLogRepository) AggregateByTagByTimeRange(ctx context.Context, orgID *uuid.UUID, projectID *uuid.UUID, tagKey string, start, end time.Time) ([]TagUsageRow, error) {
+	var rows []TagUsageRow
+	query := r.db.WithContext(ctx).Model(&models.UsageLog{}).
+		Joins("CROSS JOIN LATERAL jsonb_each_text(usage_logs.tags) AS tag").
+		Select(`tag.key AS tag_key, tag.value AS tag_value,
+				COUNT(usage_logs.id) AS requests,
+				COALESCE(SUM(usage_logs.request_tokens), 0) AS input_tokens,
+				COALESCE(SUM(usage_logs.response_tokens), 0) AS output_tokens,
+				COALESCE(SUM(usage_logs.total_tokens), 0) AS total_tokens,
+				COALESCE(SUM(usage_logs.cost), 0) AS cost`).
+		Where("usage_logs.created_at >= ? AND usage_logs.created_at <= ?", start, end).
+		Where("usage_logs.tags IS NOT NULL").
+		Group("tag.key, tag.value").
+		Order("tag.key, cost DESC")
+
+	if orgID != nil {
+		query = query.Joins("JOIN projects ON usage_logs.project_id = projects.id").Where("projects.org_id = ?", *orgID)
+	}
+	if projectID != nil {
+		query = query.Where("usage_logs.project_id = ?", *projectID)
+	}
+	if tagKey != "" {
+		query = query.Where("tag.key = ?", tagKey)
+	}
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // CountByOrgOrProject counts total usage logs matching org/project in a time range (for pagination).
 func (r *UsageLogRepository) CountByOrgOrProject(ctx context.Context, orgID *uuid.UUID, projectID *uuid.UUID, start, end time.Time) (int64, error) {
 	var count int64
@@ -308,7 +408,7 @@ func (r *UsageLogRepository) CountInterruptedByIDAndProject(ctx context.Context,
 func (r *UsageLogRepository) GetOrgIDByProjectID(ctx context.Context, projectID uuid.UUID) (uuid.UUID, error) {
 	var project models.Project
 	if err := r.db.WithContext(ctx).Select("org_id").Where("id = ?", projectID).First(&project).Error; err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, translateError(err)
 	}
 	return project.OrgID, nil
 }
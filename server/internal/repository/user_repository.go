@@ -31,7 +31,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
 	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
@@ -40,7 +40,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	if err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
@@ -81,6 +81,21 @@ func (r *UserRepository) CountActiveUsers(ctx context.Context, since time.Time)
 	return count, nil
 }
 
+// CountActiveUsersInOrg counts users who have usage logs in an organization's
+// projects since a given time.
+func (r *UserRepository) CountActiveUsersInOrg(ctx context.Context, orgID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&models.UsageLog{}).
+		Joins("JOIN projects ON usage_logs.project_id = projects.id").
+		Where("projects.org_id = ? AND usage_logs.created_at >= ?", orgID, since).
+		Distinct("usage_logs.user_id").
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // Search finds users matching a query string (email or name).
 func (r *UserRepository) Search(ctx context.Context, query string) ([]models.User, error) {
 	var users []models.User
@@ -114,7 +129,7 @@ func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error
 func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
 	var key models.APIKey
 	if err := r.db.WithContext(ctx).First(&key, "id = ?", id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &key, nil
 }
@@ -123,7 +138,7 @@ func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.A
 func (r *APIKeyRepository) GetByKeyHash(ctx context.Context, hash string) (*models.APIKey, error) {
 	var key models.APIKey
 	if err := r.db.WithContext(ctx).First(&key, "key_hash = ?", hash).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &key, nil
 }
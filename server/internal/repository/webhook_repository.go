@@ -41,7 +41,7 @@ func (r *webhookRepository) GetEndpointByID(ctx context.Context, id uuid.UUID) (
 	var endpoint models.WebhookEndpoint
 	err := r.db.WithContext(ctx).First(&endpoint, "id = ?", id).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &endpoint, nil
 }
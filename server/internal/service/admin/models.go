@@ -0,0 +1,281 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxModelImport bounds a single pricing-table import.
+const maxModelImport = 1000
+
+var (
+	// ErrInvalidModel is returned when a model definition fails validation.
+	ErrInvalidModel = errors.New("invalid model")
+	// ErrModelNotFound is returned when the model to update does not exist.
+	ErrModelNotFound = errors.New("model not found")
+	// ErrModelExists is returned when creating a model whose name is already
+	// registered for the provider.
+	ErrModelExists = errors.New("model already exists for this provider")
+	// ErrModelProviderNotFound is returned when the referenced provider does
+	// not exist.
+	ErrModelProviderNotFound = errors.New("provider not found")
+)
+
+// ModelSpec is an admin-supplied model definition. The provider is given by
+// provider_id or by provider name. Nil fields are left unchanged on update
+// and take the model defaults on create.
+type ModelSpec struct {
+	ProviderID        *uuid.UUID `json:"provider_id,omitempty"`
+	Provider          string     `json:"provider,omitempty"`
+	Name              string     `json:"name"`
+	DisplayName       *string    `json:"display_name,omitempty"`
+	InputPricePer1K   *float64   `json:"input_price_per_1k,omitempty"`
+	OutputPricePer1K  *float64   `json:"output_price_per_1k,omitempty"`
+	PricePerSecond    *float64   `json:"price_per_second,omitempty"`
+	PricePerImage     *float64   `json:"price_per_image,omitempty"`
+	PricePerMinute    *float64   `json:"price_per_minute,omitempty"`
+	MaxTokens         *int       `json:"max_tokens,omitempty"`
+	ContextWindow     *int       `json:"context_window,omitempty"`
+	SupportsStreaming *bool      `json:"supports_streaming,omitempty"`
+	SupportsVision    *bool      `json:"supports_vision,omitempty"`
+	SupportsTools     *bool      `json:"supports_tools,omitempty"`
+	IsActive          *bool      `json:"is_active,omitempty"`
+}
+
+// ModelImportResult reports what a pricing-table import changed.
+type ModelImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// newModel returns a model with the same defaults as the database columns.
+func newModel(providerID uuid.UUID, name string) *models.Model {
+	return &models.Model{
+		ProviderID:        providerID,
+		Name:              name,
+		DisplayName:       name,
+		MaxTokens:         4096,
+		SupportsStreaming: true,
+		IsActive:          true,
+	}
+}
+
+// apply copies the set fields of spec onto m.
+func (spec *ModelSpec) apply(m *models.Model) {
+	if spec.Name != "" {
+		m.Name = spec.Name
+	}
+	setIf(&m.DisplayName, spec.DisplayName)
+	setIf(&m.InputPricePer1K, spec.InputPricePer1K)
+	setIf(&m.OutputPricePer1K, spec.OutputPricePer1K)
+	setIf(&m.PricePerSecond, spec.PricePerSecond)
+	setIf(&m.PricePerImage, spec.PricePerImage)
+	setIf(&m.PricePerMinute, spec.PricePerMinute)
+	setIf(&m.MaxTokens, spec.MaxTokens)
+	setIf(&m.ContextWindow, spec.ContextWindow)
+	setIf(&m.SupportsStreaming, spec.SupportsStreaming)
+	setIf(&m.SupportsVision, spec.SupportsVision)
+	setIf(&m.SupportsTools, spec.SupportsTools)
+	setIf(&m.IsActive, spec.IsActive)
+}
+
+func setIf[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+// validateModel checks a model before it is saved.
+func validateModel(m *models.Model) error {
+	if m.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidModel)
+	}
+	if len(m.Name) > 255 || len(m.DisplayName) > 255 {
+		return fmt.Errorf("%w: name and display_name must be at most 255 characters", ErrInvalidModel)
+	}
+	prices := map[string]float64{
+		"input_price_per_1k":  m.InputPricePer1K,
+		"output_price_per_1k": m.OutputPricePer1K,
+		"price_per_second":    m.PricePerSecond,
+		"price_per_image":     m.PricePerImage,
+		"price_per_minute":    m.PricePerMinute,
+	}
+	for field, p := range prices {
+		if p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+			return fmt.Errorf("%w: %s must be a non-negative number", ErrInvalidModel, field)
+		}
+	}
+	if m.MaxTokens <= 0 {
+		return fmt.Errorf("%w: max_tokens must be positive", ErrInvalidModel)
+	}
+	if m.ContextWindow < 0 {
+		return fmt.Errorf("%w: context_window must not be negative", ErrInvalidModel)
+	}
+	if m.ContextWindow > 0 && m.MaxTokens > m.ContextWindow {
+		return fmt.Errorf("%w: max_tokens exceeds context_window", ErrInvalidModel)
+	}
+	return nil
+}
+
+// resolveModelProvider returns the provider ID named by spec.
+func resolveModelProvider(tx *gorm.DB, spec *ModelSpec) (uuid.UUID, error) {
+	var prov models.Provider
+	q := tx.Select("id")
+	switch {
+	case spec.ProviderID != nil:
+		q = q.Where("id = ?", *spec.ProviderID)
+	case spec.Provider != "":
+		q = q.Where("name = ?", spec.Provider)
+	default:
+		return uuid.Nil, fmt.Errorf("%w: provider_id or provider is required", ErrInvalidModel)
+	}
+	if err := q.First(&prov).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, ErrModelProviderNotFound
+		}
+		return uuid.Nil, err
+	}
+	return prov.ID, nil
+}
+
+// findModel looks up a model by provider and name, returning nil if absent.
+func findModel(tx *gorm.DB, providerID uuid.UUID, name string) (*models.Model, error) {
+	var m models.Model
+	err := tx.Where("provider_id = ? AND name = ?", providerID, name).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// createModel inserts m, keeping the default:true columns it sets to false.
+func createModel(tx *gorm.DB, m *models.Model) error {
+	return repository.CreateKeepingFalse(tx, m, map[string]bool{
+		"supports_streaming": m.SupportsStreaming,
+		"is_active":          m.IsActive,
+	})
+}
+
+// CreateModelFromSpec validates spec and creates the model it describes.
+func (s *Service) CreateModelFromSpec(ctx context.Context, spec ModelSpec) (*models.Model, error) {
+	db := s.db.WithContext(ctx)
+	providerID, err := resolveModelProvider(db, &spec)
+	if err != nil {
+		return nil, err
+	}
+	m := newModel(providerID, spec.Name)
+	spec.apply(m)
+	if err := validateModel(m); err != nil {
+		return nil, err
+	}
+	existing, err := findModel(db, providerID, m.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrModelExists
+	}
+	if err := createModel(db, m); err != nil {
+		return nil, fmt.Errorf("failed to create model: %w", err)
+	}
+	return m, nil
+}
+
+// UpdateModelFromSpec applies the set fields of spec to an existing model.
+// Giving a provider moves the model to that provider.
+func (s *Service) UpdateModelFromSpec(ctx context.Context, id uuid.UUID, spec ModelSpec) (*models.Model, error) {
+	db := s.db.WithContext(ctx)
+	var m models.Model
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrModelNotFound
+		}
+		return nil, err
+	}
+	if spec.ProviderID != nil || spec.Provider != "" {
+		providerID, err := resolveModelProvider(db, &spec)
+		if err != nil {
+			return nil, err
+		}
+		m.ProviderID = providerID
+	}
+	spec.apply(&m)
+	if err := validateModel(&m); err != nil {
+		return nil, err
+	}
+	if existing, err := findModel(db, m.ProviderID, m.Name); err != nil {
+		return nil, err
+	} else if existing != nil && existing.ID != m.ID {
+		return nil, ErrModelExists
+	}
+	if err := db.Save(&m).Error; err != nil {
+		return nil, fmt.Errorf("failed to update model: %w", err)
+	}
+	return &m, nil
+}
+
+// ImportModels upserts a pricing table, matching existing models by provider
+// and name. The import is all-or-nothing: any invalid entry rolls back every
+// change and the error names the offending entry.
+func (s *Service) ImportModels(ctx context.Context, specs []ModelSpec) (*ModelImportResult, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%w: no models to import", ErrInvalidModel)
+	}
+	if len(specs) > maxModelImport {
+		return nil, fmt.Errorf("%w: at most %d models per import", ErrInvalidModel, maxModelImport)
+	}
+
+	result := &ModelImportResult{}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range specs {
+			spec := &specs[i]
+			if err := importModel(tx, spec, result); err != nil {
+				return fmt.Errorf("models[%d] (%s): %w", i, spec.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func importModel(tx *gorm.DB, spec *ModelSpec, result *ModelImportResult) error {
+	providerID, err := resolveModelProvider(tx, spec)
+	if err != nil {
+		return err
+	}
+	if spec.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidModel)
+	}
+	m, err := findModel(tx, providerID, spec.Name)
+	if err != nil {
+		return err
+	}
+	created := m == nil
+	if created {
+		m = newModel(providerID, spec.Name)
+	}
+	spec.apply(m)
+	if err := validateModel(m); err != nil {
+		return err
+	}
+	if created {
+		result.Created++
+		return createModel(tx, m)
+	}
+	result.Updated++
+	return tx.Save(m).Error
+}
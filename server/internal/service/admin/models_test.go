@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"math"
+	"testing"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelSpecApplyOnlySetFields(t *testing.T) {
+	m := newModel(uuid.New(), "gpt-4o")
+	m.InputPricePer1K = 0.005
+
+	out := 0.015
+	ctxWindow := 128000
+	vision := true
+	spec := ModelSpec{OutputPricePer1K: &out, ContextWindow: &ctxWindow, SupportsVision: &vision}
+	spec.apply(m)
+
+	assert.Equal(t, "gpt-4o", m.Name, "empty name keeps the current one")
+	assert.Equal(t, 0.005, m.InputPricePer1K, "unset price is unchanged")
+	assert.Equal(t, 0.015, m.OutputPricePer1K)
+	assert.Equal(t, 128000, m.ContextWindow)
+	assert.True(t, m.SupportsVision)
+	assert.True(t, m.SupportsStreaming, "defaults survive")
+	assert.True(t, m.IsActive)
+}
+
+func TestValidateModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(m *models.Model)
+		ok     bool
+	}{
+		{"defaults", func(m *models.Model) {}, true},
+		{"missing name", func(m *models.Model) { m.Name = "" }, false},
+		{"negative price", func(m *models.Model) { m.InputPricePer1K = -0.01 }, false},
+		{"NaN price", func(m *models.Model) { m.OutputPricePer1K = math.NaN() }, false},
+		{"infinite price", func(m *models.Model) { m.PricePerImage = math.Inf(1) }, false},
+		{"zero max tokens", func(m *models.Model) { m.MaxTokens = 0 }, false},
+		{"negative context window", func(m *models.Model) { m.ContextWindow = -1 }, false},
+		{"max tokens over context window", func(m *models.Model) { m.ContextWindow = 2048 }, false},
+		{"max tokens within context window", func(m *models.Model) { m.ContextWindow = 8192 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModel(uuid.New(), "claude-3-5-sonnet")
+			tt.mutate(m)
+			err := validateModel(m)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidModel)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"llm-router-platform/internal/crypto"
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrRekeyFailed is returned when one or more secrets could not be decrypted
+// with either the old or the current key; the migration is rolled back.
+var ErrRekeyFailed = errors.New("some secrets could not be decrypted; no changes were made")
+
+// RekeyResult reports the outcome of re-encrypting stored secrets.
+type RekeyResult struct {
+	DryRun       bool           `json:"dry_run"`
+	ProviderKeys RekeyCounts    `json:"provider_keys"`
+	Proxies      RekeyCounts    `json:"proxies"`
+	Failures     []RekeyFailure `json:"failures"`
+}
+
+// RekeyCounts summarizes one kind of secret.
+type RekeyCounts struct {
+	Total    int `json:"total"`
+	Migrated int `json:"migrated"` // decrypted with the old key (and re-encrypted unless dry run)
+	Current  int `json:"current"`  // already readable with the current key
+	Failed   int `json:"failed"`
+}
+
+// RekeyFailure identifies a secret that neither key could decrypt.
+type RekeyFailure struct {
+	Kind  string    `json:"kind"`
+	ID    uuid.UUID `json:"id"`
+	Error string    `json:"error"`
+}
+
+// rekeyOutcome classifies a single secret during re-encryption.
+type rekeyOutcome int
+
+const (
+	rekeyMigrated rekeyOutcome = iota
+	rekeyCurrent
+	rekeyFailed
+)
+
+// RekeySecrets re-encrypts provider API keys and proxy passwords that were
+// written under oldKey with the current encryptor. Secrets that already
+// decrypt with the current key are left alone, so a partially rotated
+// database can be re-run safely. All updates happen in one transaction,
+// which is rolled back if any secret fails to decrypt. With dryRun nothing
+// is written.
+func (s *Service) RekeySecrets(ctx context.Context, oldKey string, dryRun bool) (*RekeyResult, error) {
+	oldEnc, err := crypto.NewEncryptor(oldKey)
+	if err != nil {
+		return nil, err
+	}
+	current := crypto.GetEncryptor()
+	if current == nil {
+		return nil, crypto.ErrNotInitialized
+	}
+
+	result := &RekeyResult{DryRun: dryRun, Failures: []RekeyFailure{}}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var keys []models.ProviderAPIKey
+		if err := tx.Unscoped().Select("id", "encrypted_api_key").Find(&keys).Error; err != nil {
+			return fmt.Errorf("load provider keys: %w", err)
+		}
+		for _, k := range keys {
+			updated, outcome, err := rekeySecret(oldEnc, current, k.EncryptedAPIKey, dryRun)
+			result.ProviderKeys.record(outcome)
+			if outcome == rekeyFailed {
+				result.Failures = append(result.Failures, RekeyFailure{Kind: "provider_key", ID: k.ID, Error: err.Error()})
+				continue
+			}
+			if outcome == rekeyMigrated && !dryRun {
+				if err := tx.Unscoped().Model(&models.ProviderAPIKey{}).Where("id = ?", k.ID).
+					UpdateColumn("encrypted_api_key", updated).Error; err != nil {
+					return fmt.Errorf("update provider key %s: %w", k.ID, err)
+				}
+			}
+		}
+
+		var proxies []models.Proxy
+		if err := tx.Unscoped().Select("id", "password").Where("password <> ''").Find(&proxies).Error; err != nil {
+			return fmt.Errorf("load proxies: %w", err)
+		}
+		for _, p := range proxies {
+			updated, outcome, err := rekeySecret(oldEnc, current, p.Password, dryRun)
+			result.Proxies.record(outcome)
+			if outcome == rekeyFailed {
+				result.Failures = append(result.Failures, RekeyFailure{Kind: "proxy", ID: p.ID, Error: err.Error()})
+				continue
+			}
+			if outcome == rekeyMigrated && !dryRun {
+				if err := tx.Unscoped().Model(&models.Proxy{}).Where("id = ?", p.ID).
+					UpdateColumn("password", updated).Error; err != nil {
+					return fmt.Errorf("update proxy %s: %w", p.ID, err)
+				}
+			}
+		}
+
+		if len(result.Failures) > 0 && !dryRun {
+			return ErrRekeyFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrRekeyFailed) {
+		return nil, err
+	}
+
+	s.logger.Info("secret re-encryption finished",
+		zap.Bool("dry_run", dryRun),
+		zap.Int("provider_keys_migrated", result.ProviderKeys.Migrated),
+		zap.Int("proxies_migrated", result.Proxies.Migrated),
+		zap.Int("failed", len(result.Failures)),
+	)
+	return result, err
+}
+
+// rekeySecret decrypts ciphertext with oldEnc and, unless dryRun, re-encrypts
+// it with current. Ciphertext that current can already read is reported as
+// rekeyCurrent and returned unchanged.
+func rekeySecret(oldEnc, current crypto.EncryptorInterface, ciphertext string, dryRun bool) (string, rekeyOutcome, error) {
+	plaintext, err := oldEnc.Decrypt(ciphertext)
+	if err != nil {
+		if _, curErr := current.Decrypt(ciphertext); curErr == nil {
+			return ciphertext, rekeyCurrent, nil
+		}
+		return "", rekeyFailed, errors.New("cannot decrypt with the old or the current key")
+	}
+	if dryRun {
+		return ciphertext, rekeyMigrated, nil
+	}
+	updated, err := current.Encrypt(plaintext)
+	if err != nil {
+		return "", rekeyFailed, fmt.Errorf("re-encrypt: %w", err)
+	}
+	return updated, rekeyMigrated, nil
+}
+
+func (c *RekeyCounts) record(outcome rekeyOutcome) {
+	c.Total++
+	switch outcome {
+	case rekeyMigrated:
+		c.Migrated++
+	case rekeyCurrent:
+		c.Current++
+	case rekeyFailed:
+		c.Failed++
+	}
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"testing"
+
+	"llm-router-platform/internal/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRekeySecret(t *testing.T) {
+	oldEnc, err := crypto.NewEncryptor("old-key-old-key-old-key-old-key!")
+	require.NoError(t, err)
+	current, err := crypto.NewEncryptor("new-key-new-key-new-key-new-key!")
+	require.NoError(t, err)
+	other, err := crypto.NewEncryptor("zzz-key-zzz-key-zzz-key-zzz-key!")
+	require.NoError(t, err)
+
+	underOld, _ := oldEnc.Encrypt("sk-old")
+	underCurrent, _ := current.Encrypt("sk-current")
+	underOther, _ := other.Encrypt("sk-other")
+
+	t.Run("migrates old ciphertext", func(t *testing.T) {
+		updated, outcome, err := rekeySecret(oldEnc, current, underOld, false)
+		require.NoError(t, err)
+		assert.Equal(t, rekeyMigrated, outcome)
+		plain, err := current.Decrypt(updated)
+		require.NoError(t, err)
+		assert.Equal(t, "sk-old", plain)
+	})
+
+	t.Run("dry run leaves ciphertext untouched", func(t *testing.T) {
+		updated, outcome, err := rekeySecret(oldEnc, current, underOld, true)
+		require.NoError(t, err)
+		assert.Equal(t, rekeyMigrated, outcome)
+		assert.Equal(t, underOld, updated)
+	})
+
+	t.Run("skips secrets already under the current key", func(t *testing.T) {
+		updated, outcome, err := rekeySecret(oldEnc, current, underCurrent, false)
+		require.NoError(t, err)
+		assert.Equal(t, rekeyCurrent, outcome)
+		assert.Equal(t, underCurrent, updated)
+	})
+
+	t.Run("flags secrets neither key can read", func(t *testing.T) {
+		_, outcome, err := rekeySecret(oldEnc, current, underOther, false)
+		assert.Error(t, err)
+		assert.Equal(t, rekeyFailed, outcome)
+	})
+}
+
+func TestRekeyCountsRecord(t *testing.T) {
+	var c RekeyCounts
+	c.record(rekeyMigrated)
+	c.record(rekeyMigrated)
+	c.record(rekeyCurrent)
+	c.record(rekeyFailed)
+	assert.Equal(t, RekeyCounts{Total: 4, Migrated: 2, Current: 1, Failed: 1}, c)
+}
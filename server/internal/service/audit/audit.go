@@ -157,4 +157,12 @@ const (
 	ActionAPIKeyRevoke      = "apikey_revoke"
 	ActionTokensInvalidated = "tokens_invalidated"
 	ActionQuotaUpdate       = "quota_update"
+	ActionSecretsRekey      = "secrets_rekey"
+	ActionImpersonateRead   = "impersonate_read"
+	ActionModelUpdate       = "model_update"
+	ActionProxyStatsReset   = "proxy_stats_reset"
+	ActionRouteOverride     = "route_override"
+	ActionKeyCooldownClear  = "key_cooldown_clear"
+	ActionModelTiersUpdate  = "model_tiers_update"
+	ActionCaptureRead       = "failed_request_capture_read"
 )
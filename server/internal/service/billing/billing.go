@@ -3,12 +3,14 @@ package billing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
 	"llm-router-platform/internal/models"
 	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/observability"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/google/uuid"
@@ -40,15 +42,16 @@ var (
 // Service handles billing and usage tracking.
 type Service struct {
 	usageRepo *repository.UsageLogRepository
-	modelRepo *repository.ModelRepository
+	modelRepo repository.ModelRepo
 	redis     *redis.Client
+	keySpend  *KeySpendNotifier // nil = per-key spend webhooks disabled
 	logger    *zap.Logger
 }
 
 // NewService creates a new billing service.
 func NewService(
 	usageRepo *repository.UsageLogRepository,
-	modelRepo *repository.ModelRepository,
+	modelRepo repository.ModelRepo,
 	redisClient *redis.Client,
 	logger *zap.Logger,
 ) *Service {
@@ -60,9 +63,14 @@ func NewService(
 	}
 }
 
-// UpdateUsageTokens updates an existing usage log with final token counts and status.
+// UpdateUsageTokens updates an existing usage log with final token counts and
+// status. requestTokens includes the prompt-cache writes and reads, which are
+// billed at the cache rates.
 // Used for streaming requests to ensure usage is recorded even if the stream is interrupted.
-func (s *Service) UpdateUsageTokens(ctx context.Context, logID uuid.UUID, requestTokens, responseTokens int, statusCode int, latencyMs int64, errorMessage string) error {
+func (s *Service) UpdateUsageTokens(ctx context.Context, logID uuid.UUID, requestTokens, responseTokens, cacheCreationTokens, cacheReadTokens int, statusCode int, latencyMs int64, errorMessage string) (err error) {
+	ctx, span := observability.StartSpan(ctx, "billing.update_usage")
+	defer func() { observability.EndSpan(span, err) }()
+
 	log, err := s.usageRepo.GetByID(ctx, logID)
 	if err != nil {
 		return err
@@ -71,17 +79,14 @@ func (s *Service) UpdateUsageTokens(ctx context.Context, logID uuid.UUID, reques
 	log.RequestTokens = requestTokens
 	log.ResponseTokens = responseTokens
 	log.TotalTokens = requestTokens + responseTokens
+	log.CacheCreationTokens = cacheCreationTokens
+	log.CacheReadTokens = cacheReadTokens
 	log.StatusCode = statusCode
 	log.ErrorMessage = errorMessage
 	log.IsSuccess = statusCode >= 200 && statusCode < 300
 	log.Latency = latencyMs
 
-	if log.ModelID != uuid.Nil {
-		model, err := s.modelRepo.GetByID(ctx, log.ModelID)
-		if err == nil {
-			log.Cost = s.calculateCost(model, log.RequestTokens, log.ResponseTokens)
-		}
-	}
+	s.applyCost(ctx, log)
 
 	err = s.usageRepo.Update(ctx, log)
 
@@ -89,20 +94,21 @@ func (s *Service) UpdateUsageTokens(ctx context.Context, logID uuid.UUID, reques
 	if s.redis != nil && err == nil && log.IsSuccess {
 		s.incrUsageCache(ctx, log)
 	}
+	if err == nil {
+		s.checkKeySpend(ctx, log)
+	}
 
 	return err
 }
 
 // RecordUsage records API usage.
-func (s *Service) RecordUsage(ctx context.Context, log *models.UsageLog) error {
-	if log.ModelID != uuid.Nil {
-		model, err := s.modelRepo.GetByID(ctx, log.ModelID)
-		if err == nil {
-			log.Cost = s.calculateCost(model, log.RequestTokens, log.ResponseTokens)
-		}
-	}
+func (s *Service) RecordUsage(ctx context.Context, log *models.UsageLog) (err error) {
+	ctx, span := observability.StartSpan(ctx, "billing.record_usage", observability.AttrModel.String(log.ModelName))
+	defer func() { observability.EndSpan(span, err) }()
+
+	s.applyCost(ctx, log)
 
-	err := s.usageRepo.Create(ctx, log)
+	err = s.usageRepo.Create(ctx, log)
 
 	// Refresh redis cache — use org-scoped key matching GetUsageSummary read path
 	if s.redis != nil && err == nil {
@@ -110,6 +116,8 @@ func (s *Service) RecordUsage(ctx context.Context, log *models.UsageLog) error {
 	}
 	if err != nil {
 		billingRecordErrorsTotal.WithLabelValues("record_usage").Inc()
+	} else {
+		s.checkKeySpend(ctx, log)
 	}
 
 	return err
@@ -121,14 +129,12 @@ func (s *Service) RecordUsage(ctx context.Context, log *models.UsageLog) error {
 // due to a process crash between the two operations.
 //
 // If balanceSvc is nil or cost is zero, it behaves identically to RecordUsage.
-func (s *Service) RecordUsageAndDeduct(ctx context.Context, log *models.UsageLog, balanceSvc *BalanceService, userID uuid.UUID, description string) error {
+func (s *Service) RecordUsageAndDeduct(ctx context.Context, log *models.UsageLog, balanceSvc *BalanceService, userID uuid.UUID, description string) (err error) {
+	ctx, span := observability.StartSpan(ctx, "billing.record_usage", observability.AttrModel.String(log.ModelName))
+	defer func() { observability.EndSpan(span, err) }()
+
 	// Calculate cost first (outside transaction — read-only)
-	if log.ModelID != uuid.Nil {
-		model, err := s.modelRepo.GetByID(ctx, log.ModelID)
-		if err == nil {
-			log.Cost = s.calculateCost(model, log.RequestTokens, log.ResponseTokens)
-		}
-	}
+	s.applyCost(ctx, log)
 
 	// If no balance service or zero cost, fall back to simple insert
 	if balanceSvc == nil || log.Cost <= 0 {
@@ -136,11 +142,14 @@ func (s *Service) RecordUsageAndDeduct(ctx context.Context, log *models.UsageLog
 		if s.redis != nil && err == nil {
 			s.incrUsageCache(ctx, log)
 		}
+		if err == nil {
+			s.checkKeySpend(ctx, log)
+		}
 		return err
 	}
 
 	// Atomic transaction: insert usage log + deduct balance
-	err := balanceSvc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = balanceSvc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 1. Insert usage log within the transaction
 		if err := tx.Create(log).Error; err != nil {
 			return err
@@ -183,6 +192,8 @@ func (s *Service) RecordUsageAndDeduct(ctx context.Context, log *models.UsageLog
 	}
 	if err != nil {
 		billingRecordErrorsTotal.WithLabelValues("record_usage_and_deduct").Inc()
+	} else {
+		s.checkKeySpend(ctx, log)
 	}
 
 	return err
@@ -218,18 +229,75 @@ func (s *Service) incrUsageCache(ctx context.Context, log *models.UsageLog) {
 		return
 	}
 
-	now := time.Now()
-	monthStr := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
-	key := fmt.Sprintf("billing:usage:org:%s:%s", orgID.String(), monthStr)
+	key := usageCacheKey(orgID, time.Now())
 
 	pipe := s.redis.Pipeline()
 	pipe.HIncrBy(ctx, key, "total_requests", 1)
 	pipe.HIncrBy(ctx, key, "total_tokens", int64(log.TotalTokens))
 	pipe.HIncrByFloat(ctx, key, "total_cost", log.Cost)
+	if log.StatusCode >= 200 && log.StatusCode < 300 {
+		pipe.HIncrBy(ctx, key, "success_count", 1)
+	} else {
+		pipe.HIncrBy(ctx, key, "error_count", 1)
+	}
+	pipe.HIncrBy(ctx, key, "latency_total", log.Latency)
+	pipe.HIncrBy(ctx, key, "mcp_call_count", int64(log.MCPCallCount))
+	pipe.HIncrBy(ctx, key, "mcp_error_count", int64(log.MCPErrorCount))
 	pipe.Expire(ctx, key, 32*24*time.Hour)
 	_, _ = pipe.Exec(ctx)
 }
 
+// usageCacheKey is the Redis hash holding an organization's month-to-date
+// usage totals for the month containing now.
+func usageCacheKey(orgID uuid.UUID, now time.Time) string {
+	return fmt.Sprintf("billing:usage:org:%s:%d-%02d", orgID.String(), now.Year(), now.Month())
+}
+
+// isMonthToDate reports whether [start, end] is the current month up to now,
+// the only window the org usage cache holds.
+func isMonthToDate(start, end, now time.Time) bool {
+	local := now.In(start.Location())
+	monthStart := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, start.Location())
+	return start.Equal(monthStart) && now.Sub(end).Abs() < time.Minute
+}
+
+// applyCost resolves the model for a usage log and fills in its cost.
+// The proxy handlers only know the requested model name, so when ModelID is
+// unset the model is looked up by name and ModelID is back-filled. Lookup
+// failures (e.g. dynamic models without a pricing row) leave the cost at zero
+// so usage is still recorded under the model name.
+func (s *Service) applyCost(ctx context.Context, log *models.UsageLog) {
+	var (
+		model *models.Model
+		err   error
+	)
+	switch {
+	case log.ModelID != uuid.Nil:
+		model, err = s.modelRepo.GetByID(ctx, log.ModelID)
+	case log.ModelName != "":
+		model, err = s.modelRepo.GetByName(ctx, log.ModelName)
+	default:
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("model lookup failed, recording usage without cost",
+				zap.String("model", log.ModelName), zap.Error(err))
+		}
+		return
+	}
+
+	log.ModelID = model.ID
+	log.Cost = s.calculateCost(model, log.RequestTokens, log.ResponseTokens) +
+		promptCacheAdjustment(model, log.CacheCreationTokens, log.CacheReadTokens)
+}
+
+// Anthropic prompt-cache pricing relative to the model's input price.
+const (
+	cacheWritePriceFactor = 1.25
+	cacheReadPriceFactor  = 0.1
+)
+
 // calculateCost calculates the cost for token usage.
 func (s *Service) calculateCost(model *models.Model, inputTokens, outputTokens int) float64 {
 	inputCost := float64(inputTokens) / 1000 * model.InputPricePer1K
@@ -237,6 +305,50 @@ func (s *Service) calculateCost(model *models.Model, inputTokens, outputTokens i
 	return inputCost + outputCost
 }
 
+// promptCacheAdjustment corrects the input cost of prompt tokens that were
+// written to or read from the provider's prompt cache. calculateCost charged
+// them at the full input price; cache writes cost more and reads much less.
+func promptCacheAdjustment(model *models.Model, writeTokens, readTokens int) float64 {
+	perToken := model.InputPricePer1K / 1000
+	return float64(writeTokens)*perToken*(cacheWritePriceFactor-1) +
+		float64(readTokens)*perToken*(cacheReadPriceFactor-1)
+}
+
+// EstimateMaxCost returns the worst-case USD cost of a request before it is
+// sent: promptTokens plus maxTokens completion tokens for each of n choices,
+// priced with the same model row applyCost would charge. A maxTokens of zero
+// falls back to the model's MaxTokens. Models without a pricing row cost zero.
+func (s *Service) EstimateMaxCost(ctx context.Context, modelName string, promptTokens, maxTokens, n int) (float64, error) {
+	model, err := s.modelRepo.GetByName(ctx, modelName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if maxTokens <= 0 {
+		maxTokens = model.MaxTokens
+	}
+	if n < 1 {
+		n = 1
+	}
+	return s.calculateCost(model, promptTokens, maxTokens*n), nil
+}
+
+// TokenPrices returns the per-1K input and output prices applyCost charges
+// for modelName, so callers can price tokens as they arrive. Models without a
+// pricing row cost zero.
+func (s *Service) TokenPrices(ctx context.Context, modelName string) (inputPer1K, outputPer1K float64, err error) {
+	model, err := s.modelRepo.GetByName(ctx, modelName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	return model.InputPricePer1K, model.OutputPricePer1K, nil
+}
+
 // UsageSummary represents aggregated usage data.
 type UsageSummary struct {
 	TotalRequests int64   `json:"total_requests"`
@@ -252,29 +364,18 @@ type UsageSummary struct {
 // GetUsageSummary returns aggregated usage for an organization or project.
 func (s *Service) GetUsageSummary(ctx context.Context, orgID uuid.UUID, projectID *uuid.UUID, channel *string, startTime, endTime time.Time) (*UsageSummary, error) {
 	now := time.Now()
-	isCurrentMonth := startTime.Year() == now.Year() && startTime.Month() == now.Month()
 
-	// Redis cache is only populated at the org level (no project/channel dims),
-	// so only attempt a cache hit when no sub-filters are applied.
-	useCache := s.redis != nil && isCurrentMonth && projectID == nil && (channel == nil || *channel == "")
+	// The Redis cache holds org-level month-to-date totals only (no
+	// project/channel dims), so any other window or filter reads the database.
+	useCache := s.redis != nil && isMonthToDate(startTime, endTime, now) && projectID == nil && (channel == nil || *channel == "")
+	key := usageCacheKey(orgID, now)
 
 	if useCache {
-		monthStr := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
-		key := fmt.Sprintf("billing:usage:org:%s:%s", orgID.String(), monthStr)
-
 		res, err := s.redis.HGetAll(ctx, key).Result()
-		if err == nil && len(res) > 0 {
-			reqs, _ := strconv.ParseInt(res["total_requests"], 10, 64)
-			tokens, _ := strconv.ParseInt(res["total_tokens"], 10, 64)
-			cost, _ := strconv.ParseFloat(res["total_cost"], 64)
-			successRate, _ := strconv.ParseFloat(res["success_rate"], 64)
-
-			return &UsageSummary{
-				TotalRequests: reqs,
-				TotalTokens:   tokens,
-				TotalCost:     cost,
-				SuccessRate:   successRate,
-			}, nil
+		// Without the seeded marker the hash only holds increments recorded
+		// since it expired, not the whole month.
+		if err == nil && res["seeded"] != "" {
+			return cachedUsageSummary(res), nil
 		}
 	}
 
@@ -297,13 +398,17 @@ func (s *Service) GetUsageSummary(ctx context.Context, orgID uuid.UUID, projectI
 	}
 
 	if useCache && summary.TotalRequests > 0 {
-		monthStr := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
-		key := fmt.Sprintf("billing:usage:org:%s:%s", orgID.String(), monthStr)
 		pipe := s.redis.Pipeline()
-		pipe.HSet(ctx, key, "total_requests", summary.TotalRequests)
-		pipe.HSet(ctx, key, "total_tokens", summary.TotalTokens)
-		pipe.HSet(ctx, key, "total_cost", summary.TotalCost)
-		pipe.HSet(ctx, key, "success_rate", summary.SuccessRate)
+		pipe.HSet(ctx, key,
+			"seeded", 1,
+			"total_requests", row.TotalRequests,
+			"total_tokens", row.TotalTokens,
+			"total_cost", row.TotalCost,
+			"success_count", row.SuccessCount,
+			"error_count", row.ErrorCount,
+			"latency_total", int64(row.AvgLatency*float64(row.TotalRequests)),
+			"mcp_call_count", row.MCPCallCount,
+			"mcp_error_count", row.MCPErrorCount)
 		pipe.Expire(ctx, key, 30*time.Second)
 		_, _ = pipe.Exec(ctx)
 	}
@@ -311,6 +416,28 @@ func (s *Service) GetUsageSummary(ctx context.Context, orgID uuid.UUID, projectI
 	return summary, nil
 }
 
+// cachedUsageSummary rebuilds a summary from the org usage cache hash.
+func cachedUsageSummary(res map[string]string) *UsageSummary {
+	parseInt := func(field string) int64 {
+		v, _ := strconv.ParseInt(res[field], 10, 64)
+		return v
+	}
+	cost, _ := strconv.ParseFloat(res["total_cost"], 64)
+	summary := &UsageSummary{
+		TotalRequests: parseInt("total_requests"),
+		TotalTokens:   parseInt("total_tokens"),
+		TotalCost:     cost,
+		ErrorCount:    parseInt("error_count"),
+		MCPCallCount:  parseInt("mcp_call_count"),
+		MCPErrorCount: parseInt("mcp_error_count"),
+	}
+	if summary.TotalRequests > 0 {
+		summary.SuccessRate = float64(parseInt("success_count")) / float64(summary.TotalRequests) * 100
+		summary.AvgLatency = float64(parseInt("latency_total")) / float64(summary.TotalRequests)
+	}
+	return summary
+}
+
 // GetSystemUsageSummary returns aggregated usage for all users (system-wide).
 func (s *Service) GetSystemUsageSummary(ctx context.Context, channel *string, startTime, endTime time.Time) (*UsageSummary, error) {
 	row, err := s.usageRepo.AggregateByTimeRange(ctx, nil, nil, channel, startTime, endTime)
@@ -345,8 +472,12 @@ type DailyUsage struct {
 // GetDailyUsage returns daily usage statistics (SQL aggregation).
 func (s *Service) GetDailyUsage(ctx context.Context, orgID uuid.UUID, projectID *uuid.UUID, channel *string, days int) ([]DailyUsage, error) {
 	endTime := time.Now()
-	startTime := endTime.AddDate(0, 0, -days)
+	return s.GetDailyUsageRange(ctx, orgID, projectID, channel, endTime.AddDate(0, 0, -days), endTime)
+}
 
+// GetDailyUsageRange returns daily usage statistics between startTime and
+// endTime (SQL aggregation).
+func (s *Service) GetDailyUsageRange(ctx context.Context, orgID uuid.UUID, projectID *uuid.UUID, channel *string, startTime, endTime time.Time) ([]DailyUsage, error) {
 	rows, err := s.usageRepo.AggregateDailyByTimeRange(ctx, &orgID, projectID, channel, startTime, endTime)
 	if err != nil {
 		return nil, err
@@ -450,6 +581,40 @@ func (s *Service) GetUsageByModel(ctx context.Context, orgID uuid.UUID, projectI
 	return result, nil
 }
 
+// TagUsage represents usage per attribution tag key and value.
+type TagUsage struct {
+	Key          string  `json:"key"`
+	Value        string  `json:"value"`
+	Requests     int64   `json:"requests"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	Cost         float64 `json:"cost"`
+}
+
+// GetUsageByTag returns usage grouped by attribution tag (SQL aggregation),
+// optionally for a single tag key.
+func (s *Service) GetUsageByTag(ctx context.Context, orgID uuid.UUID, projectID *uuid.UUID, tagKey string, startTime, endTime time.Time) ([]TagUsage, error) {
+	rows, err := s.usageRepo.AggregateByTagByTimeRange(ctx, &orgID, projectID, tagKey, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TagUsage, len(rows))
+	for i, r := range rows {
+		result[i] = TagUsage{
+			Key:          r.TagKey,
+			Value:        r.TagValue,
+			Requests:     r.Requests,
+			InputTokens:  r.InputTokens,
+			OutputTokens: r.OutputTokens,
+			TotalTokens:  r.TotalTokens,
+			Cost:         r.Cost,
+		}
+	}
+	return result, nil
+}
+
 // GetSystemUsageByModel returns usage grouped by model for all users (SQL aggregation).
 func (s *Service) GetSystemUsageByModel(ctx context.Context, channel *string, startTime, endTime time.Time) ([]ModelUsage, error) {
 	rows, err := s.usageRepo.AggregateByModelByTimeRange(ctx, nil, nil, channel, startTime, endTime)
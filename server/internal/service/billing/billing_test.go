@@ -1,15 +1,47 @@
 package billing
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	"llm-router-platform/internal/models"
 )
 
+// stubModelRepo serves models by name for cost resolution tests.
+type stubModelRepo struct {
+	byName map[string]*models.Model
+}
+
+func (m *stubModelRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Model, error) {
+	for _, mod := range m.byName {
+		if mod.ID == id {
+			return mod, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *stubModelRepo) GetByName(_ context.Context, name string) (*models.Model, error) {
+	if mod, ok := m.byName[name]; ok {
+		return mod, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *stubModelRepo) GetByProvider(_ context.Context, _ uuid.UUID) ([]models.Model, error) {
+	return nil, nil
+}
+func (m *stubModelRepo) GetByProviderSorted(_ context.Context, _ uuid.UUID) ([]models.Model, error) {
+	return nil, nil
+}
+func (m *stubModelRepo) Create(_ context.Context, _ *models.Model) error { return nil }
+func (m *stubModelRepo) Update(_ context.Context, _ *models.Model) error { return nil }
+func (m *stubModelRepo) Delete(_ context.Context, _ uuid.UUID) error     { return nil }
+
 func TestUsageSummary(t *testing.T) {
 	summary := UsageSummary{
 		TotalRequests: 1000,
@@ -129,6 +161,31 @@ func TestTimePeriods(t *testing.T) {
 	assert.True(t, thirtyDaysAgo.Before(now))
 }
 
+func TestIsMonthToDate(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, isMonthToDate(monthStart, now, now))
+	assert.True(t, isMonthToDate(monthStart, now.Add(-time.Second), now))
+	assert.False(t, isMonthToDate(now.AddDate(0, 0, -7), now, now), "7 day window")
+	assert.False(t, isMonthToDate(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), now, now), "today")
+	assert.False(t, isMonthToDate(monthStart, now.Add(-time.Hour), now), "ends before now")
+}
+
+func TestCachedUsageSummary(t *testing.T) {
+	summary := cachedUsageSummary(map[string]string{
+		"total_requests": "4", "total_tokens": "100", "total_cost": "0.5",
+		"success_count": "3", "error_count": "1", "latency_total": "800",
+		"mcp_call_count": "2", "mcp_error_count": "1",
+	})
+
+	assert.Equal(t, &UsageSummary{
+		TotalRequests: 4, TotalTokens: 100, TotalCost: 0.5,
+		AvgLatency: 200, SuccessRate: 75, ErrorCount: 1,
+		MCPCallCount: 2, MCPErrorCount: 1,
+	}, summary)
+}
+
 func TestEmptyUsageSummary(t *testing.T) {
 	summary := UsageSummary{}
 
@@ -136,3 +193,62 @@ func TestEmptyUsageSummary(t *testing.T) {
 	assert.Equal(t, int64(0), summary.TotalTokens)
 	assert.Equal(t, float64(0), summary.TotalCost)
 }
+
+func TestApplyCost_ResolvesModelByName(t *testing.T) {
+	model := &models.Model{Name: "gpt-4o", InputPricePer1K: 0.005, OutputPricePer1K: 0.015}
+	model.ID = uuid.New()
+	svc := NewService(nil, &stubModelRepo{byName: map[string]*models.Model{"gpt-4o": model}}, nil, zap.NewNop())
+
+	log := &models.UsageLog{ModelName: "gpt-4o", RequestTokens: 1000, ResponseTokens: 2000}
+	svc.applyCost(context.Background(), log)
+
+	assert.Equal(t, model.ID, log.ModelID)
+	assert.InDelta(t, 0.035, log.Cost, 0.0001)
+}
+
+func TestApplyCost_PromptCacheTokens(t *testing.T) {
+	model := &models.Model{Name: "claude-3-5-sonnet", InputPricePer1K: 0.003, OutputPricePer1K: 0.015}
+	svc := NewService(nil, &stubModelRepo{byName: map[string]*models.Model{"claude-3-5-sonnet": model}}, nil, zap.NewNop())
+
+	// 1000 uncached + 1000 written + 10000 read prompt tokens, 1000 output.
+	log := &models.UsageLog{
+		ModelName: "claude-3-5-sonnet", RequestTokens: 12000, ResponseTokens: 1000,
+		CacheCreationTokens: 1000, CacheReadTokens: 10000,
+	}
+	svc.applyCost(context.Background(), log)
+
+	assert.InDelta(t, 0.003+0.00375+0.003+0.015, log.Cost, 0.000001)
+}
+
+func TestApplyCost_UnknownModelRecordsZeroCost(t *testing.T) {
+	svc := NewService(nil, &stubModelRepo{byName: map[string]*models.Model{}}, nil, zap.NewNop())
+
+	log := &models.UsageLog{ModelName: "llama3:8b", RequestTokens: 1000, ResponseTokens: 2000}
+	svc.applyCost(context.Background(), log)
+
+	assert.Equal(t, uuid.Nil, log.ModelID)
+	assert.Equal(t, "llama3:8b", log.ModelName)
+	assert.Zero(t, log.Cost)
+}
+
+func TestEstimateMaxCost(t *testing.T) {
+	model := &models.Model{Name: "gpt-4o", InputPricePer1K: 0.005, OutputPricePer1K: 0.015, MaxTokens: 4096}
+	svc := NewService(nil, &stubModelRepo{byName: map[string]*models.Model{"gpt-4o": model}}, nil, zap.NewNop())
+	ctx := context.Background()
+
+	cost, err := svc.EstimateMaxCost(ctx, "gpt-4o", 1000, 2000, 1)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.035, cost, 1e-9)
+
+	cost, err = svc.EstimateMaxCost(ctx, "gpt-4o", 1000, 2000, 3)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.005+0.09, cost, 1e-9, "completion tokens scale with n")
+
+	cost, err = svc.EstimateMaxCost(ctx, "gpt-4o", 0, 0, 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, 4.096*0.015, cost, 1e-9, "unset max_tokens uses the model limit")
+
+	cost, err = svc.EstimateMaxCost(ctx, "llama3:8b", 1000, 2000, 1)
+	assert.NoError(t, err)
+	assert.Zero(t, cost, "unpriced models cannot breach a cost limit")
+}
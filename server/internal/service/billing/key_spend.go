@@ -0,0 +1,148 @@
+package billing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// keySpendCheckTimeout bounds one asynchronous spend check, including the
+// webhook deliveries it triggers.
+const keySpendCheckTimeout = 30 * time.Second
+
+// WebhookSender delivers a JSON payload to a webhook URL.
+// health.AlertNotifier satisfies it.
+type WebhookSender interface {
+	SendWebhook(ctx context.Context, url string, payload interface{}) error
+}
+
+// KeySpendNotifier fires a webhook when an API key's spend in the current
+// calendar month (UTC) crosses one of the key's SpendThresholds. Each
+// threshold fires at most once per month; a threshold whose webhook could not
+// be delivered fires again on the next check.
+type KeySpendNotifier struct {
+	apiKeyRepo repository.APIKeyRepo
+	usageRepo  repository.UsageLogRepo
+	alertRepo  repository.SpendAlertRepo
+	sender     WebhookSender
+	inflight   sync.Map // API key ID → struct{}; skips overlapping checks for a hot key
+	logger     *zap.Logger
+}
+
+// NewKeySpendNotifier creates a new key spend notifier.
+func NewKeySpendNotifier(
+	apiKeyRepo repository.APIKeyRepo,
+	usageRepo repository.UsageLogRepo,
+	alertRepo repository.SpendAlertRepo,
+	sender WebhookSender,
+	logger *zap.Logger,
+) *KeySpendNotifier {
+	return &KeySpendNotifier{
+		apiKeyRepo: apiKeyRepo,
+		usageRepo:  usageRepo,
+		alertRepo:  alertRepo,
+		sender:     sender,
+		logger:     logger,
+	}
+}
+
+// Check compares the key's spend this month against its thresholds and sends
+// a webhook for each threshold crossed for the first time. It is a no-op for
+// keys without thresholds or a webhook URL, and when a check for the same key
+// is already running.
+func (n *KeySpendNotifier) Check(ctx context.Context, apiKeyID uuid.UUID) {
+	if _, busy := n.inflight.LoadOrStore(apiKeyID, struct{}{}); busy {
+		return
+	}
+	defer n.inflight.Delete(apiKeyID)
+
+	key, err := n.apiKeyRepo.GetByID(ctx, apiKeyID)
+	if err != nil || len(key.SpendThresholds) == 0 || key.SpendWebhookURL == "" {
+		return
+	}
+
+	now := time.Now().UTC()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	period := periodStart.Format("2006-01")
+
+	spend, err := n.usageRepo.SumCostByAPIKeySince(ctx, apiKeyID, periodStart)
+	if err != nil {
+		n.logger.Warn("failed to aggregate API key spend", zap.String("api_key_id", apiKeyID.String()), zap.Error(err))
+		return
+	}
+
+	for _, threshold := range key.SpendThresholds {
+		if spend < threshold {
+			continue
+		}
+		// Claiming the threshold first keeps replicas from notifying twice.
+		alert := &models.APIKeySpendAlert{
+			APIKeyID:     apiKeyID,
+			Period:       period,
+			ThresholdUSD: threshold,
+			SpendUSD:     spend,
+		}
+		fired, err := n.alertRepo.MarkFired(ctx, alert)
+		if err != nil {
+			n.logger.Warn("failed to record spend alert", zap.String("api_key_id", apiKeyID.String()), zap.Error(err))
+			continue
+		}
+		if !fired {
+			continue
+		}
+
+		payload := map[string]interface{}{
+			"event":         "api_key.spend_threshold",
+			"api_key_id":    key.ID.String(),
+			"api_key_name":  key.Name,
+			"key_prefix":    key.KeyPrefix,
+			"project_id":    key.ProjectID.String(),
+			"period":        period,
+			"threshold_usd": threshold,
+			"spend_usd":     spend,
+			"timestamp":     now.Format(time.RFC3339),
+		}
+		if err := n.sender.SendWebhook(ctx, key.SpendWebhookURL, payload); err != nil {
+			n.logger.Error("failed to send spend threshold webhook",
+				zap.String("api_key_id", apiKeyID.String()),
+				zap.Float64("threshold_usd", threshold),
+				zap.Error(err),
+			)
+			if err := n.alertRepo.Unmark(ctx, alert); err != nil {
+				n.logger.Warn("failed to release undelivered spend alert", zap.String("api_key_id", apiKeyID.String()), zap.Error(err))
+			}
+			continue
+		}
+		n.logger.Info("API key spend threshold crossed",
+			zap.String("api_key_id", apiKeyID.String()),
+			zap.String("period", period),
+			zap.Float64("threshold_usd", threshold),
+			zap.Float64("spend_usd", spend),
+		)
+	}
+}
+
+// SetKeySpendNotifier enables per-key spend threshold webhooks. Each recorded
+// usage with a cost triggers an asynchronous check for its API key.
+func (s *Service) SetKeySpendNotifier(n *KeySpendNotifier) {
+	s.keySpend = n
+}
+
+// checkKeySpend runs the key spend check in the background, detached from the
+// request context so it survives the response being written.
+func (s *Service) checkKeySpend(ctx context.Context, log *models.UsageLog) {
+	if s.keySpend == nil || log.Cost <= 0 || log.APIKeyID == uuid.Nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), keySpendCheckTimeout)
+	go func(apiKeyID uuid.UUID) {
+		defer cancel()
+		s.keySpend.Check(ctx, apiKeyID)
+	}(log.APIKeyID)
+}
@@ -0,0 +1,128 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+)
+
+type stubAPIKeyRepo struct {
+	repository.APIKeyRepo
+	key *models.APIKey
+}
+
+func (r *stubAPIKeyRepo) GetByID(_ context.Context, _ uuid.UUID) (*models.APIKey, error) {
+	return r.key, nil
+}
+
+type stubKeyUsageRepo struct {
+	repository.UsageLogRepo
+	spend float64
+	since time.Time
+}
+
+func (r *stubKeyUsageRepo) SumCostByAPIKeySince(_ context.Context, _ uuid.UUID, since time.Time) (float64, error) {
+	r.since = since
+	return r.spend, nil
+}
+
+type stubSpendAlertRepo struct {
+	fired map[string]bool
+}
+
+func (r *stubSpendAlertRepo) MarkFired(_ context.Context, a *models.APIKeySpendAlert) (bool, error) {
+	k := fmt.Sprintf("%s/%s/%v", a.APIKeyID, a.Period, a.ThresholdUSD)
+	if r.fired[k] {
+		return false, nil
+	}
+	r.fired[k] = true
+	return true, nil
+}
+
+func (r *stubSpendAlertRepo) Unmark(_ context.Context, a *models.APIKeySpendAlert) error {
+	delete(r.fired, fmt.Sprintf("%s/%s/%v", a.APIKeyID, a.Period, a.ThresholdUSD))
+	return nil
+}
+
+type stubWebhookSender struct {
+	urls     []string
+	payloads []map[string]interface{}
+	err      error
+}
+
+func (s *stubWebhookSender) SendWebhook(_ context.Context, url string, payload interface{}) error {
+	s.urls = append(s.urls, url)
+	s.payloads = append(s.payloads, payload.(map[string]interface{}))
+	return s.err
+}
+
+func TestKeySpendNotifier_FiresEachThresholdOncePerPeriod(t *testing.T) {
+	key := &models.APIKey{
+		Name:            "ci",
+		KeyPrefix:       "sk-ci",
+		SpendThresholds: models.Float64Array{10, 50, 100},
+		SpendWebhookURL: "https://hooks.example.com/spend",
+	}
+	key.ID = uuid.New()
+	usage := &stubKeyUsageRepo{spend: 12.5}
+	sender := &stubWebhookSender{}
+	n := NewKeySpendNotifier(&stubAPIKeyRepo{key: key}, usage, &stubSpendAlertRepo{fired: map[string]bool{}}, sender, zap.NewNop())
+
+	n.Check(context.Background(), key.ID)
+	require.Len(t, sender.payloads, 1)
+	assert.Equal(t, "https://hooks.example.com/spend", sender.urls[0])
+	assert.Equal(t, 10.0, sender.payloads[0]["threshold_usd"])
+	assert.Equal(t, 12.5, sender.payloads[0]["spend_usd"])
+	assert.Equal(t, key.ID.String(), sender.payloads[0]["api_key_id"])
+	assert.Equal(t, time.Now().UTC().Format("2006-01"), sender.payloads[0]["period"])
+	assert.Equal(t, 1, usage.since.Day())
+	assert.Equal(t, time.UTC, usage.since.Location(), "periods are calendar months in UTC")
+
+	// Same spend again: nothing new crossed.
+	n.Check(context.Background(), key.ID)
+	assert.Len(t, sender.payloads, 1)
+
+	// Jumping past two thresholds fires both.
+	usage.spend = 120
+	n.Check(context.Background(), key.ID)
+	require.Len(t, sender.payloads, 3)
+	assert.Equal(t, 50.0, sender.payloads[1]["threshold_usd"])
+	assert.Equal(t, 100.0, sender.payloads[2]["threshold_usd"])
+}
+
+func TestKeySpendNotifier_RetriesUndeliveredThreshold(t *testing.T) {
+	key := &models.APIKey{SpendThresholds: models.Float64Array{10}, SpendWebhookURL: "https://hooks.example.com/spend"}
+	key.ID = uuid.New()
+	sender := &stubWebhookSender{err: errors.New("connection refused")}
+	n := NewKeySpendNotifier(&stubAPIKeyRepo{key: key}, &stubKeyUsageRepo{spend: 12}, &stubSpendAlertRepo{fired: map[string]bool{}}, sender, zap.NewNop())
+
+	n.Check(context.Background(), key.ID)
+	require.Len(t, sender.payloads, 1)
+
+	sender.err = nil
+	n.Check(context.Background(), key.ID)
+	assert.Len(t, sender.payloads, 2, "a failed delivery is attempted again")
+
+	n.Check(context.Background(), key.ID)
+	assert.Len(t, sender.payloads, 2, "a delivered threshold does not fire again")
+}
+
+func TestKeySpendNotifier_SkipsUnconfiguredKeys(t *testing.T) {
+	key := &models.APIKey{SpendThresholds: models.Float64Array{1}}
+	key.ID = uuid.New()
+	sender := &stubWebhookSender{}
+	n := NewKeySpendNotifier(&stubAPIKeyRepo{key: key}, &stubKeyUsageRepo{spend: 5}, &stubSpendAlertRepo{fired: map[string]bool{}}, sender, zap.NewNop())
+
+	n.Check(context.Background(), key.ID)
+	assert.Empty(t, sender.payloads)
+}
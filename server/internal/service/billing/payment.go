@@ -3,6 +3,7 @@ package billing
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -56,9 +57,12 @@ func (s *PaymentService) CreateCheckoutSession(ctx context.Context, userID uuid.
 	}
 
 	plan, err := s.planRepo.GetByID(ctx, planID)
-	if err != nil {
+	if errors.Is(err, repository.ErrNotFound) {
 		return "", fmt.Errorf("plan not found")
 	}
+	if err != nil {
+		return "", err
+	}
 
 	orderNo := fmt.Sprintf("ORD-%d-%s", time.Now().Unix(), uuid.New().String()[:8])
 
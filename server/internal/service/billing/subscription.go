@@ -2,6 +2,7 @@ package billing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -114,9 +115,12 @@ func (s *SubscriptionService) GetQuotaUsage(ctx context.Context, orgID uuid.UUID
 // This is an internal flow (no external payment gateway).
 func (s *SubscriptionService) ChangePlan(ctx context.Context, orgID uuid.UUID, planID uuid.UUID) (*models.Subscription, error) {
 	plan, err := s.planRepo.GetByID(ctx, planID)
-	if err != nil {
+	if errors.Is(err, repository.ErrNotFound) {
 		return nil, fmt.Errorf("plan not found")
 	}
+	if err != nil {
+		return nil, err
+	}
 	if !plan.IsActive {
 		return nil, fmt.Errorf("plan is not available")
 	}
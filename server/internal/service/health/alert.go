@@ -4,10 +4,14 @@ package health
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
 	cryptorand "crypto/rand"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -21,12 +25,20 @@ import (
 
 // AlertNotifier handles alert notifications.
 type AlertNotifier struct {
-	alertRepo       *repository.AlertRepository
-	alertConfigRepo *repository.AlertConfigRepository
+	alertRepo       repository.AlertRepo
+	alertConfigRepo repository.AlertConfigRepo
 	webhookClient   *http.Client
+	webhookSecret   string // signs webhook bodies when set
+	allowLocal      bool
+	deliveries      repository.AlertDeliveryRepo // nil = failed webhooks are not retried
+	retry           DeliveryRetryConfig
 	logger          *zap.Logger
 }
 
+// alertSignatureHeader carries the HMAC-SHA256 of an alert webhook body, in
+// the same "sha256=<hex>" form project webhooks use.
+const alertSignatureHeader = "X-Hub-Signature-256"
+
 // NewAlertNotifier creates a new AlertNotifier. allowLocal controls whether
 // alert webhook delivery may reach private/reserved IP ranges (SSRF guard).
 func NewAlertNotifier(
@@ -39,10 +51,17 @@ func NewAlertNotifier(
 		alertRepo:       alertRepo,
 		alertConfigRepo: alertConfigRepo,
 		webhookClient:   sanitize.SafeHTTPClient(allowLocal, 10*time.Second),
+		allowLocal:      allowLocal,
 		logger:          logger,
 	}
 }
 
+// SetWebhookSecret enables HMAC signing of alert webhook bodies so receivers
+// can verify they come from this server. Empty leaves them unsigned.
+func (n *AlertNotifier) SetWebhookSecret(secret string) {
+	n.webhookSecret = secret
+}
+
 // GetAlerts retrieves alerts with pagination and optional status filter.
 func (n *AlertNotifier) GetAlerts(ctx context.Context, status string, page, pageSize int) ([]models.Alert, int64, error) {
 	offset := (page - 1) * pageSize
@@ -77,6 +96,21 @@ func (n *AlertNotifier) ResolveAlert(ctx context.Context, alertID uuid.UUID) err
 	return n.alertRepo.Update(ctx, alert)
 }
 
+// FindOpenAlert returns the ID of the newest active or acknowledged alert of
+// alertType on a target, or uuid.Nil when there is none.
+func (n *AlertNotifier) FindOpenAlert(ctx context.Context, targetType string, targetID uuid.UUID, alertType string) (uuid.UUID, error) {
+	alerts, err := n.alertRepo.GetByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	for _, a := range alerts {
+		if a.AlertType == alertType && a.Status != "resolved" {
+			return a.ID, nil
+		}
+	}
+	return uuid.Nil, nil
+}
+
 // GetAlertConfigByTarget returns alert config for a specific target.
 func (n *AlertNotifier) GetAlertConfigByTarget(ctx context.Context, targetType string, targetID uuid.UUID) (*models.AlertConfig, error) {
 	return n.alertConfigRepo.GetByTarget(ctx, targetType, targetID)
@@ -103,6 +137,13 @@ func (n *AlertNotifier) UpdateAlertConfig(ctx context.Context, config *models.Al
 
 // Notify sends an alert notification.
 func (n *AlertNotifier) Notify(ctx context.Context, targetType string, targetID uuid.UUID, alertType, message string) error {
+	_, err := n.Raise(ctx, targetType, targetID, alertType, message)
+	return err
+}
+
+// Raise records and sends an alert like Notify, and returns its ID so the
+// caller can resolve it once the condition clears.
+func (n *AlertNotifier) Raise(ctx context.Context, targetType string, targetID uuid.UUID, alertType, message string) (uuid.UUID, error) {
 	alert := &models.Alert{
 		TargetType: targetType,
 		TargetID:   targetID,
@@ -112,12 +153,12 @@ func (n *AlertNotifier) Notify(ctx context.Context, targetType string, targetID
 	}
 
 	if err := n.alertRepo.Create(ctx, alert); err != nil {
-		return err
+		return uuid.Nil, err
 	}
 
 	config, err := n.alertConfigRepo.GetByTarget(ctx, targetType, targetID)
 	if err != nil || !config.IsEnabled {
-		return nil
+		return alert.ID, nil
 	}
 
 	if config.WebhookURL != "" {
@@ -126,10 +167,11 @@ func (n *AlertNotifier) Notify(ctx context.Context, targetType string, targetID
 		}
 	}
 
-	return nil
+	return alert.ID, nil
 }
 
-// sendWebhook sends an alert via webhook.
+// sendWebhook sends an alert via webhook. A failed delivery is queued for
+// retry when a delivery queue is configured.
 func (n *AlertNotifier) sendWebhook(ctx context.Context, url string, alert *models.Alert) error {
 	payload := map[string]interface{}{
 		"target_type": alert.TargetType,
@@ -138,30 +180,119 @@ func (n *AlertNotifier) sendWebhook(ctx context.Context, url string, alert *mode
 		"message":     alert.Message,
 		"timestamp":   time.Now().Format(time.RFC3339),
 	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	status, err := n.postBody(ctx, url, body, true)
+	if err == nil && status >= 400 {
+		err = fmt.Errorf("webhook answered with status %d", status)
+	}
+	if err != nil {
+		n.enqueueDelivery(ctx, &alert.ID, url, body, status, err)
+	}
+	return err
+}
 
+// SendWebhook posts payload as JSON to url using the SSRF-guarded alert
+// client. Responses with status 400 or above are reported as errors. The body
+// is not signed: the URL is chosen by a tenant, who must not receive bodies
+// signed with the platform's alert secret.
+func (n *AlertNotifier) SendWebhook(ctx context.Context, url string, payload interface{}) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
+	status, err := n.postBody(ctx, url, body, false)
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return errors.New("webhook request failed")
+	}
+	return nil
+}
 
+// postWebhook delivers payload to url, signed when a secret is configured,
+// and returns the response status code.
+func (n *AlertNotifier) postWebhook(ctx context.Context, url string, payload interface{}) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	return n.postBody(ctx, url, body, true)
+}
+
+// postBody posts an already encoded JSON body to url and returns the response
+// status code. signed adds the alert signature when a secret is configured;
+// only platform alerts are signed.
+func (n *AlertNotifier) postBody(ctx context.Context, url string, body []byte, signed bool) (int, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if signed && n.webhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(n.webhookSecret))
+		mac.Write(body)
+		req.Header.Set(alertSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
 
 	resp, err := n.webhookClient.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return errors.New("webhook request failed")
+// WebhookTestResult reports how a webhook answered a test alert.
+type WebhookTestResult struct {
+	URL        string `json:"url"`
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Signed     bool   `json:"signed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// TestWebhook sends a sample alert to url, signed exactly like real alerts.
+// Only an unusable URL is returned as an error; delivery failures are
+// reported in the result.
+func (n *AlertNotifier) TestWebhook(ctx context.Context, url string) (*WebhookTestResult, error) {
+	if url == "" {
+		return nil, fmt.Errorf("%w: a URL is required", ErrInvalidWebhookURL)
+	}
+	if err := sanitize.ValidateWebhookURL(url, n.allowLocal, n.allowLocal); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWebhookURL, err)
 	}
 
-	return nil
+	payload := map[string]interface{}{
+		"target_type": "test",
+		"target_id":   uuid.Nil.String(),
+		"alert_type":  "webhook_test",
+		"message":     "Test alert: webhook delivery is working",
+		"timestamp":   time.Now().Format(time.RFC3339),
+	}
+	start := time.Now()
+	status, err := n.postWebhook(ctx, url, payload)
+	result := &WebhookTestResult{
+		URL:        url,
+		StatusCode: status,
+		LatencyMs:  time.Since(start).Milliseconds(),
+		Signed:     n.webhookSecret != "",
+	}
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case status >= 400:
+		result.Error = fmt.Sprintf("webhook answered with status %d", status)
+	default:
+		result.Delivered = true
+	}
+	return result, nil
 }
 
 // Scheduler runs periodic health checks.
@@ -171,6 +302,11 @@ type Scheduler struct {
 	interval      time.Duration
 	stopCh        chan struct{}
 	logger        *zap.Logger
+	missingKeys   map[uuid.UUID]bool // providers already alerted for having no active keys
+
+	latencyBreaches map[uuid.UUID]*latencyBreach // providers over their latency SLA
+	penalizer       LatencyPenalizer             // nil = breaches only alert
+	penaltyFactor   float64
 }
 
 // NewScheduler creates a new health check scheduler.
@@ -185,9 +321,18 @@ func NewScheduler(healthService *Service, notifier *AlertNotifier, interval time
 	}
 }
 
+// initialLoadMinDelay is the first retry delay for the initial provider
+// load; it doubles on each failure up to the check interval.
+const initialLoadMinDelay = time.Second
+
 // Start starts the health check scheduler with ±20% jitter to avoid thundering herd.
+// Checks begin only after the active provider set has loaded once.
 func (s *Scheduler) Start(ctx context.Context) {
+	if !s.waitForProviders(ctx) {
+		return
+	}
 	s.logger.Info("health check scheduler started", zap.Duration("interval", s.interval))
+	s.checkProviderKeys(ctx)
 
 	for {
 		// Apply ±20% jitter: interval * (0.8 + rand(0, 0.4))
@@ -209,6 +354,50 @@ func (s *Scheduler) Start(ctx context.Context) {
 	}
 }
 
+// waitForProviders blocks until the active providers can be loaded, so the
+// first checks do not run against a database that is still coming up. It
+// returns false if the scheduler is stopped first.
+func (s *Scheduler) waitForProviders(ctx context.Context) bool {
+	return retryUntil(ctx, s.stopCh, initialLoadMinDelay, s.interval,
+		func(ctx context.Context) error {
+			providers, err := s.healthService.providerRepo.GetActive(ctx)
+			if err == nil {
+				s.logger.Info("initial provider load complete", zap.Int("active_providers", len(providers)))
+			}
+			return err
+		},
+		func(attempt int, delay time.Duration, err error) {
+			s.logger.Warn("initial provider load failed, delaying health checks",
+				zap.Int("attempt", attempt), zap.Duration("retry_in", delay), zap.Error(err))
+		})
+}
+
+// retryUntil calls fn until it succeeds, backing off from minDelay and
+// doubling up to maxDelay. It returns false if ctx is done or stop is closed
+// before fn succeeds.
+func retryUntil(ctx context.Context, stop <-chan struct{}, minDelay, maxDelay time.Duration, fn func(context.Context) error, onErr func(attempt int, delay time.Duration, err error)) bool {
+	delay := minDelay
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return true
+		}
+		onErr(attempt, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			return false
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+		delay = min(delay*2, max(maxDelay, minDelay))
+	}
+}
+
 // jitteredInterval returns the interval with ±20% random jitter.
 func (s *Scheduler) jitteredInterval() time.Duration {
 	var buf [8]byte
@@ -228,10 +417,13 @@ func (s *Scheduler) Stop() {
 func (s *Scheduler) runHealthChecks(ctx context.Context) {
 	s.logger.Debug("running scheduled health checks")
 
+	s.checkProviderKeys(ctx)
+
 	// Check providers
 	if err := s.healthService.CheckAllProviders(ctx); err != nil {
 		s.logger.Error("failed to check providers health", zap.Error(err))
 	}
+	s.checkLatencySLAs(ctx)
 
 	// Check API keys
 	apiKeyStatuses, err := s.healthService.GetAPIKeysHealth(ctx)
@@ -278,6 +470,30 @@ func (s *Scheduler) runHealthChecks(ctx context.Context) {
 	}
 }
 
+// checkProviderKeys alerts when an active provider that requires an API key
+// has no active key, which makes every request routed to it fail. Each
+// provider is alerted once until it is fixed, rather than on every run.
+func (s *Scheduler) checkProviderKeys(ctx context.Context) {
+	missing, err := s.healthService.ProvidersMissingKeys(ctx)
+	if err != nil {
+		s.logger.Error("failed to check provider API keys", zap.Error(err))
+		return
+	}
+
+	current := make(map[uuid.UUID]bool, len(missing))
+	for _, p := range missing {
+		current[p.ID] = true
+		if s.missingKeys[p.ID] {
+			continue
+		}
+		s.logger.Warn("active provider requires an API key but has none configured", zap.String("provider", p.Name))
+		s.notify(ctx, "provider", p.ID,
+			"provider_missing_keys",
+			"Provider "+p.Name+" requires an API key but has no active keys; requests routed to it will fail")
+	}
+	s.missingKeys = current
+}
+
 // notify dispatches an alert through the AlertNotifier if available.
 func (s *Scheduler) notify(ctx context.Context, targetType string, targetID uuid.UUID, alertType, message string) {
 	if s.notifier == nil {
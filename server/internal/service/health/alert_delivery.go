@@ -0,0 +1,182 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	// deliveryBatchSize bounds how many queued deliveries one retry pass sends.
+	deliveryBatchSize = 50
+	// deliveryClaimLease hides claimed deliveries from other replicas while
+	// they are being sent; it must exceed the webhook client timeout.
+	deliveryClaimLease = time.Minute
+)
+
+var alertDeliveriesGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "llm_router",
+		Name:      "alert_webhook_deliveries",
+		Help:      "Queued alert webhook deliveries by status (pending = awaiting retry, dead = gave up).",
+	},
+	[]string{"status"},
+)
+
+// DeliveryRetryConfig controls retries of failed alert webhooks.
+type DeliveryRetryConfig struct {
+	MaxAttempts int           // including the first attempt
+	BaseDelay   time.Duration // delay before the first retry; doubles per attempt
+	MaxDelay    time.Duration // upper bound for the backoff
+}
+
+// DefaultDeliveryRetryConfig retries for roughly an hour and a half before
+// dead-lettering a delivery.
+func DefaultDeliveryRetryConfig() DeliveryRetryConfig {
+	return DeliveryRetryConfig{MaxAttempts: 8, BaseDelay: 30 * time.Second, MaxDelay: 30 * time.Minute}
+}
+
+// delay returns how long to wait after the given number of failed attempts.
+func (c DeliveryRetryConfig) delay(attempts int) time.Duration {
+	d := c.BaseDelay
+	for i := 1; i < attempts && d < c.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > c.MaxDelay {
+		d = c.MaxDelay
+	}
+	return d
+}
+
+// SetDeliveryQueue persists failed alert webhooks to repo so RetryDeliveries
+// can resend them. Zero fields in cfg take DefaultDeliveryRetryConfig values.
+func (n *AlertNotifier) SetDeliveryQueue(repo repository.AlertDeliveryRepo, cfg DeliveryRetryConfig) {
+	def := DefaultDeliveryRetryConfig()
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = def.MaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = def.BaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = def.MaxDelay
+	}
+	n.deliveries = repo
+	n.retry = cfg
+}
+
+// enqueueDelivery records a failed first attempt so it is retried later.
+func (n *AlertNotifier) enqueueDelivery(ctx context.Context, alertID *uuid.UUID, url string, body []byte, status int, sendErr error) {
+	if n.deliveries == nil {
+		return
+	}
+	d := &models.AlertDelivery{
+		AlertID:    alertID,
+		URL:        url,
+		Payload:    string(body),
+		Status:     models.AlertDeliveryPending,
+		Attempts:   1,
+		StatusCode: status,
+		LastError:  sendErr.Error(),
+	}
+	if n.retry.MaxAttempts <= 1 {
+		d.Status = models.AlertDeliveryDead
+	} else {
+		d.NextAttemptAt = time.Now().Add(n.retry.delay(1))
+	}
+	if err := n.deliveries.Create(ctx, d); err != nil {
+		n.logger.Error("failed to queue alert webhook for retry", zap.String("url", url), zap.Error(err))
+	}
+}
+
+// RetryDeliveries resends queued alert webhooks whose backoff has elapsed,
+// marking each delivered, rescheduled, or dead once it runs out of attempts.
+func (n *AlertNotifier) RetryDeliveries(ctx context.Context) {
+	if n.deliveries == nil {
+		return
+	}
+	due, err := n.deliveries.ClaimDue(ctx, time.Now(), deliveryClaimLease, deliveryBatchSize)
+	if err != nil {
+		n.logger.Error("failed to load queued alert webhooks", zap.Error(err))
+		return
+	}
+
+	for i := range due {
+		n.retryDelivery(ctx, &due[i])
+	}
+	n.refreshDeliveryGauges(ctx)
+}
+
+func (n *AlertNotifier) retryDelivery(ctx context.Context, d *models.AlertDelivery) {
+	status, err := n.postBody(ctx, d.URL, []byte(d.Payload), true)
+	if err == nil && status >= 400 {
+		err = fmt.Errorf("webhook answered with status %d", status)
+	}
+	d.Attempts++
+	d.StatusCode = status
+
+	switch {
+	case err == nil:
+		now := time.Now()
+		d.Status = models.AlertDeliveryDelivered
+		d.DeliveredAt = &now
+		d.LastError = ""
+	case d.Attempts >= n.retry.MaxAttempts:
+		d.Status = models.AlertDeliveryDead
+		d.LastError = err.Error()
+		n.logger.Warn("alert webhook dead-lettered",
+			zap.String("delivery_id", d.ID.String()),
+			zap.String("url", d.URL),
+			zap.Int("attempts", d.Attempts),
+			zap.Error(err),
+		)
+	default:
+		d.NextAttemptAt = time.Now().Add(n.retry.delay(d.Attempts))
+		d.LastError = err.Error()
+	}
+
+	if err := n.deliveries.Update(ctx, d); err != nil {
+		n.logger.Error("failed to record alert webhook attempt", zap.String("delivery_id", d.ID.String()), zap.Error(err))
+	}
+}
+
+// DeliveryStats counts queued alert webhooks by outcome.
+type DeliveryStats struct {
+	Pending   int64 `json:"pending"`
+	Delivered int64 `json:"delivered"`
+	Dead      int64 `json:"dead"`
+}
+
+// DeliveryStats returns how many failed alert webhooks are still awaiting a
+// retry, were eventually delivered, or were dead-lettered.
+func (n *AlertNotifier) DeliveryStats(ctx context.Context) (*DeliveryStats, error) {
+	if n.deliveries == nil {
+		return &DeliveryStats{}, nil
+	}
+	counts, err := n.deliveries.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DeliveryStats{
+		Pending:   counts[models.AlertDeliveryPending],
+		Delivered: counts[models.AlertDeliveryDelivered],
+		Dead:      counts[models.AlertDeliveryDead],
+	}, nil
+}
+
+func (n *AlertNotifier) refreshDeliveryGauges(ctx context.Context) {
+	stats, err := n.DeliveryStats(ctx)
+	if err != nil {
+		return
+	}
+	alertDeliveriesGauge.WithLabelValues(models.AlertDeliveryPending).Set(float64(stats.Pending))
+	alertDeliveriesGauge.WithLabelValues(models.AlertDeliveryDead).Set(float64(stats.Dead))
+}
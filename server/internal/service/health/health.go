@@ -3,6 +3,8 @@ package health
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"time"
 
 	"llm-router-platform/internal/config"
@@ -11,11 +13,21 @@ import (
 	"llm-router-platform/internal/repository"
 	"llm-router-platform/internal/service/provider"
 	"llm-router-platform/internal/service/proxy"
+	"llm-router-platform/pkg/sanitize"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrAlertingDisabled is returned when no alert notifier is configured.
+	ErrAlertingDisabled = errors.New("alerting is not enabled")
+	// ErrNoAlertWebhook is returned when a target has no webhook to test.
+	ErrNoAlertWebhook = errors.New("no webhook configured for this target")
+	// ErrInvalidWebhookURL is returned for a webhook URL that cannot be used.
+	ErrInvalidWebhookURL = errors.New("invalid webhook URL")
+)
+
 // Service handles health checks for API keys, proxies, and providers.
 type Service struct {
 	apiKeyRepo        *repository.APIKeyRepository
@@ -26,10 +38,18 @@ type Service struct {
 	alertNotifier     *AlertNotifier
 	providerRegistry  *provider.Registry
 	proxyService      *proxy.Service
+	clients           ClientBuilder // nil = build probe clients locally
 	logger            *zap.Logger
 	allowLocal        bool
 }
 
+// ClientBuilder selects provider keys and builds provider clients the way
+// the chat path does. The router implements it.
+type ClientBuilder interface {
+	SelectAPIKey(ctx context.Context, providerID uuid.UUID) (*models.ProviderAPIKey, error)
+	GetProviderClientWithKey(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey) (provider.Client, error)
+}
+
 // NewService creates a new health service. allowLocal mirrors the server's
 // ALLOW_LOCAL_PROVIDERS flag and controls whether health probes may reach
 // private/reserved IPs.
@@ -59,6 +79,13 @@ func NewService(
 	}
 }
 
+// SetClientBuilder makes health checks select keys and build clients through
+// b, so providers added at runtime are probed with the same key selection,
+// transport and settings as live traffic.
+func (s *Service) SetClientBuilder(b ClientBuilder) {
+	s.clients = b
+}
+
 // ─── Status Types ───────────────────────────────────────────────────────
 
 // APIKeyHealthStatus represents health status of an API key.
@@ -93,21 +120,60 @@ type ProviderHealthStatus struct {
 	Name         string    `json:"name"`
 	BaseURL      string    `json:"base_url"`
 	IsActive     bool      `json:"is_active"`
+	Draining     bool      `json:"draining"` // active but taken out of routing for maintenance
 	IsHealthy    bool      `json:"is_healthy"`
 	UseProxy     bool      `json:"use_proxy"`
 	ResponseTime int64     `json:"response_time"`
 	LastCheck    time.Time `json:"last_check"`
 	SuccessRate  float64   `json:"success_rate"`
 	ErrorMessage string    `json:"error_message,omitempty"`
+	// AvgLatencyMs is the mean latency of the recent healthy checks, 0 until
+	// there are enough of them. It is compared with LatencyThresholdMs, the
+	// alert config's latency SLA (0 = none).
+	AvgLatencyMs       int64 `json:"avg_latency_ms"`
+	LatencyThresholdMs int   `json:"latency_threshold_ms"`
+	LatencySLABreached bool  `json:"latency_sla_breached"`
 }
 
 // ─── Provider Client Helpers ────────────────────────────────────────────
 
+// selectAPIKey picks the key a provider health check probes with.
+func (s *Service) selectAPIKey(ctx context.Context, p *models.Provider) (*models.ProviderAPIKey, error) {
+	if s.clients != nil {
+		return s.clients.SelectAPIKey(ctx, p.ID)
+	}
+	keys, err := s.providerKeyRepo.GetActiveByProvider(ctx, p.ID)
+	if err != nil || len(keys) == 0 {
+		return nil, errors.New("no active API keys for provider")
+	}
+	return &keys[0], nil
+}
+
+// providerClient returns the client a health check probes p with, built
+// through the client builder when one is set.
+func (s *Service) providerClient(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey) (provider.Client, error) {
+	if s.clients != nil {
+		return s.clients.GetProviderClientWithKey(ctx, p, apiKey)
+	}
+	return s.getProviderClient(p, apiKey)
+}
+
+// errProxyNeedsClientBuilder is returned when a provider routed through a
+// proxy is probed without a client builder: a direct probe would not test
+// the path its traffic takes.
+var errProxyNeedsClientBuilder = errors.New("provider uses a proxy; its health check needs the router's client builder")
+
 // getProviderClient creates a provider client dynamically using a ProviderAPIKey.
 func (s *Service) getProviderClient(p *models.Provider, apiKey *models.ProviderAPIKey) (provider.Client, error) {
-	// First try registry for local providers (Ollama, LM Studio)
-	if client, ok := s.providerRegistry.Get(p.Name); ok {
-		return client, nil
+	if p.UseProxy {
+		return nil, errProxyNeedsClientBuilder
+	}
+
+	// Without a key, try the registry for local providers (Ollama, LM Studio)
+	if apiKey == nil {
+		if client, ok := s.providerRegistry.Get(p.Name); ok {
+			return client, nil
+		}
 	}
 
 	// Create client dynamically with the provider API key
@@ -125,13 +191,34 @@ func (s *Service) getProviderClient(p *models.Provider, apiKey *models.ProviderA
 		BaseURL: p.BaseURL,
 	}
 
-	return s.createProviderClient(p.Name, cfg)
+	// Probe self-hosted endpoints with the same TLS settings as live traffic.
+	tlsCfg, err := provider.TLSOptions{
+		InsecureSkipVerify: p.TLSInsecureSkipVerify,
+		MinVersion:         p.TLSMinVersion,
+		CABundlePath:       p.TLSCABundlePath,
+	}.Config()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		timeout := time.Duration(p.Timeout) * time.Second
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		cfg.HTTPClient = func() *http.Client {
+			t := sanitize.SafeTransport(s.allowLocal)
+			t.TLSClientConfig = tlsCfg
+			return &http.Client{Transport: t, Timeout: timeout}
+		}
+	}
+
+	return s.createProviderClient(p.ClientType(), cfg)
 }
 
-// createProviderClient creates a provider client based on provider name.
+// createProviderClient creates a provider client based on provider type.
 // Delegates to the shared factory in the provider package.
-func (s *Service) createProviderClient(name string, cfg *config.ProviderConfig) (provider.Client, error) {
-	return provider.NewClientByName(name, cfg, s.logger)
+func (s *Service) createProviderClient(providerType string, cfg *config.ProviderConfig) (provider.Client, error) {
+	return provider.NewClientByName(providerType, cfg, s.logger)
 }
 
 // ─── Alert Management ───────────────────────────────────────────────────
@@ -141,6 +228,41 @@ func (s *Service) GetHealthHistory(ctx context.Context, targetType string, limit
 	return s.healthHistoryRepo.GetRecent(ctx, targetType, limit)
 }
 
+// ProviderHealthHistory returns the latest health checks of one provider,
+// newest first.
+func (s *Service) ProviderHealthHistory(ctx context.Context, id uuid.UUID, limit int) ([]models.HealthHistory, error) {
+	return s.healthHistoryRepo.GetByTarget(ctx, "provider", id, limit)
+}
+
+// HealthTrendPoint is the uptime of a target over one time bucket.
+type HealthTrendPoint struct {
+	BucketStart  time.Time `json:"bucket_start"`
+	Checks       int64     `json:"checks"`
+	SuccessRate  float64   `json:"success_rate"`   // 0-100
+	AvgLatencyMs float64   `json:"avg_latency_ms"` // Healthy checks only; 0 = none
+}
+
+// HealthTrend returns the health checks of a target since the given time,
+// bucketed for charting uptime. Buckets without checks are omitted.
+func (s *Service) HealthTrend(ctx context.Context, targetType string, id uuid.UUID, bucket time.Duration, since time.Time) ([]HealthTrendPoint, error) {
+	rows, err := s.healthHistoryRepo.GetTrend(ctx, targetType, id, bucket, since)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]HealthTrendPoint, len(rows))
+	for i, r := range rows {
+		out[i] = HealthTrendPoint{
+			BucketStart:  r.BucketStart,
+			Checks:       r.Checks,
+			AvgLatencyMs: r.AvgResponseTime,
+		}
+		if r.Checks > 0 {
+			out[i].SuccessRate = float64(r.Healthy) * 100 / float64(r.Checks)
+		}
+	}
+	return out, nil
+}
+
 // GetAlerts returns alerts with pagination.
 func (s *Service) GetAlerts(ctx context.Context, status string, page, pageSize int) ([]models.Alert, int64, error) {
 	if s.alertNotifier == nil {
@@ -173,6 +295,41 @@ func (s *Service) UpdateAlertConfig(ctx context.Context, config *models.AlertCon
 	return s.alertNotifier.UpdateAlertConfig(ctx, config)
 }
 
+// TestAlertWebhook sends a sample alert to url, or to the webhook configured
+// for the target when url is empty.
+func (s *Service) TestAlertWebhook(ctx context.Context, targetType string, targetID uuid.UUID, url string) (*WebhookTestResult, error) {
+	if s.alertNotifier == nil {
+		return nil, ErrAlertingDisabled
+	}
+	if url == "" {
+		config, err := s.alertNotifier.GetAlertConfigByTarget(ctx, targetType, targetID)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && config.WebhookURL == "") {
+			return nil, ErrNoAlertWebhook
+		}
+		if err != nil {
+			return nil, err
+		}
+		url = config.WebhookURL
+	}
+	return s.alertNotifier.TestWebhook(ctx, url)
+}
+
+// RetryAlertDeliveries resends failed alert webhooks whose backoff has elapsed.
+func (s *Service) RetryAlertDeliveries(ctx context.Context) {
+	if s.alertNotifier == nil {
+		return
+	}
+	s.alertNotifier.RetryDeliveries(ctx)
+}
+
+// AlertDeliveryStats counts failed alert webhooks by retry outcome.
+func (s *Service) AlertDeliveryStats(ctx context.Context) (*DeliveryStats, error) {
+	if s.alertNotifier == nil {
+		return &DeliveryStats{}, nil
+	}
+	return s.alertNotifier.DeliveryStats(ctx)
+}
+
 // GetAlertConfig returns alert configuration for a target.
 func (s *Service) GetAlertConfig(ctx context.Context, targetType string, targetID uuid.UUID) (*models.AlertConfig, error) {
 	if s.alertNotifier == nil {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/provider"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -16,7 +17,12 @@ func (s *Service) GetAPIKeysHealth(ctx context.Context) ([]APIKeyHealthStatus, e
 	if err != nil {
 		return nil, err
 	}
+	return s.APIKeysHealth(ctx, keys), nil
+}
 
+// APIKeysHealth summarizes the recorded health checks of the given keys. A
+// key that was never checked reports a zero LastCheck.
+func (s *Service) APIKeysHealth(ctx context.Context, keys []models.ProviderAPIKey) []APIKeyHealthStatus {
 	statuses := make([]APIKeyHealthStatus, len(keys))
 	for i, key := range keys {
 		history, _ := s.healthHistoryRepo.GetByTarget(ctx, "api_key", key.ID, 10)
@@ -55,7 +61,7 @@ func (s *Service) GetAPIKeysHealth(ctx context.Context) ([]APIKeyHealthStatus, e
 		}
 	}
 
-	return statuses, nil
+	return statuses
 }
 
 // CheckSingleAPIKey checks health of a specific provider API key.
@@ -78,7 +84,7 @@ func (s *Service) CheckSingleAPIKey(ctx context.Context, id uuid.UUID) (*APIKeyH
 	}
 
 	// Create client dynamically using the provider API key
-	client, err := s.getProviderClient(p, key)
+	client, err := s.providerClient(ctx, p, key)
 	if err != nil {
 		return &APIKeyHealthStatus{
 			ID:        key.ID,
@@ -88,13 +94,14 @@ func (s *Service) CheckSingleAPIKey(ctx context.Context, id uuid.UUID) (*APIKeyH
 		}, nil
 	}
 
-	healthy, latency, _ := client.CheckHealth(ctx)
+	healthy, latency, checkMode, _ := provider.CheckHealthWithMode(ctx, client, p.DeepHealthCheck, p.HealthCheckModel)
 
 	history := &models.HealthHistory{
 		TargetType:   "api_key",
 		TargetID:     key.ID,
 		IsHealthy:    healthy,
 		ResponseTime: latency.Milliseconds(),
+		CheckMode:    checkMode,
 		CheckedAt:    time.Now(),
 	}
 	if err := s.healthHistoryRepo.Create(ctx, history); err != nil {
@@ -2,15 +2,10 @@ package health
 
 import (
 	"context"
-	"io"
-	"net/http"
-	"net/url"
-	"strings"
 	"time"
 
-	"llm-router-platform/internal/crypto"
 	"llm-router-platform/internal/models"
-	"llm-router-platform/pkg/sanitize"
+	"llm-router-platform/internal/service/provider"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -49,18 +44,25 @@ func (s *Service) GetProvidersHealth(ctx context.Context) ([]ProviderHealthStatu
 		if len(history) > 0 {
 			successRate = float64(successCount) / float64(len(history))
 		}
+		avgLatency := averageLatency(history)
+		threshold := s.latencyThreshold(ctx, "provider", p.ID)
 
 		statuses[i] = ProviderHealthStatus{
 			ID:           p.ID,
 			Name:         p.Name,
 			BaseURL:      p.BaseURL,
 			IsActive:     p.IsActive,
+			Draining:     p.Draining,
 			IsHealthy:    isHealthy,
 			UseProxy:     p.UseProxy,
 			ResponseTime: lastResponseTime,
 			LastCheck:    lastCheck,
 			SuccessRate:  successRate,
 			ErrorMessage: errorMsg,
+
+			AvgLatencyMs:       avgLatency,
+			LatencyThresholdMs: threshold,
+			LatencySLABreached: threshold > 0 && avgLatency > int64(threshold),
 		}
 	}
 
@@ -78,16 +80,14 @@ func (s *Service) CheckSingleProvider(ctx context.Context, id uuid.UUID) (*Provi
 	var healthy bool
 	var latency time.Duration
 	var errorMsg string
+	checkMode := provider.HealthCheckShallow
 
-	// Get an active API key for this provider (if it requires one)
+	// Select an API key for this provider (if it requires one)
 	var apiKey *models.ProviderAPIKey
 	if p.RequiresAPIKey {
-		keys, err := s.providerKeyRepo.GetActiveByProvider(ctx, p.ID)
-		if err != nil || len(keys) == 0 {
+		if apiKey, err = s.selectAPIKey(ctx, p); err != nil {
 			healthy = false
-			errorMsg = "no active API keys for provider"
-		} else {
-			apiKey = &keys[0] // Use the first active key for health check
+			errorMsg = err.Error()
 		}
 	}
 
@@ -98,26 +98,21 @@ func (s *Service) CheckSingleProvider(ctx context.Context, id uuid.UUID) (*Provi
 			zap.String("base_url", p.BaseURL),
 			zap.Bool("use_proxy", p.UseProxy))
 
-		// Create client dynamically
-		client, err := s.getProviderClient(p, apiKey)
+		// Create client dynamically. Built through the client builder, it
+		// reaches the provider through the same proxy as live traffic.
+		client, err := s.providerClient(ctx, p, apiKey)
 		if err != nil {
 			healthy = false
 			errorMsg = "failed to create provider client: " + err.Error()
 			s.logger.Error("failed to create provider client", zap.Error(err))
 		} else {
-			// Check health using proxy if enabled
-			if p.UseProxy {
-				s.logger.Info("checking health with proxy", zap.String("provider", p.Name))
-				healthy, latency, errorMsg = s.checkWithProxy(ctx, p, apiKey)
+			s.logger.Info("checking health", zap.String("provider", p.Name), zap.Bool("deep", p.DeepHealthCheck))
+			healthy, latency, checkMode, err = provider.CheckHealthWithMode(ctx, client, p.DeepHealthCheck, p.HealthCheckModel)
+			if err != nil {
+				errorMsg = err.Error()
+				s.logger.Error("health check failed", zap.String("provider", p.Name), zap.String("mode", checkMode), zap.Error(err))
 			} else {
-				s.logger.Info("checking health directly", zap.String("provider", p.Name))
-				healthy, latency, err = client.CheckHealth(ctx)
-				if err != nil {
-					errorMsg = err.Error()
-					s.logger.Error("health check failed", zap.String("provider", p.Name), zap.Error(err))
-				} else {
-					s.logger.Info("health check completed", zap.String("provider", p.Name), zap.Bool("healthy", healthy), zap.Duration("latency", latency))
-				}
+				s.logger.Info("health check completed", zap.String("provider", p.Name), zap.String("mode", checkMode), zap.Bool("healthy", healthy), zap.Duration("latency", latency))
 			}
 		}
 	}
@@ -128,6 +123,7 @@ func (s *Service) CheckSingleProvider(ctx context.Context, id uuid.UUID) (*Provi
 		IsHealthy:    healthy,
 		ResponseTime: latency.Milliseconds(),
 		ErrorMessage: errorMsg,
+		CheckMode:    checkMode,
 		CheckedAt:    time.Now(),
 	}
 	_ = s.healthHistoryRepo.Create(ctx, history)
@@ -143,6 +139,7 @@ func (s *Service) CheckSingleProvider(ctx context.Context, id uuid.UUID) (*Provi
 		Name:         p.Name,
 		BaseURL:      p.BaseURL,
 		IsActive:     p.IsActive,
+		Draining:     p.Draining,
 		IsHealthy:    healthy,
 		UseProxy:     p.UseProxy,
 		ResponseTime: latency.Milliseconds(),
@@ -152,154 +149,46 @@ func (s *Service) CheckSingleProvider(ctx context.Context, id uuid.UUID) (*Provi
 	}, nil
 }
 
-// checkWithProxy performs a health check using a proxy.
-func (s *Service) checkWithProxy(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey) (bool, time.Duration, string) {
-	var proxyInfo *models.Proxy
-
-	// Use provider's default proxy if set, otherwise use any active proxy
-	if p.DefaultProxyID != nil {
-		proxy, err := s.proxyRepo.GetByID(ctx, *p.DefaultProxyID)
-		if err != nil {
-			s.logger.Warn("failed to get default proxy, falling back to active proxies",
-				zap.String("provider", p.Name),
-				zap.String("proxy_id", p.DefaultProxyID.String()),
-				zap.Error(err))
-		} else if proxy.IsActive {
-			proxyInfo = proxy
-		}
-	}
-
-	// If no default proxy or it's inactive, get any active proxy
-	if proxyInfo == nil {
-		proxies, err := s.proxyRepo.GetActive(ctx)
-		if err != nil || len(proxies) == 0 {
-			return false, 0, "no active proxy available"
-		}
-		proxyInfo = &proxies[0]
-	}
-
-	proxyURL, err := url.Parse(proxyInfo.URL)
+// CheckAllProviders runs health checks on all active providers.
+func (s *Service) CheckAllProviders(ctx context.Context) error {
+	providers, err := s.providerRepo.GetActive(ctx)
 	if err != nil {
-		return false, 0, "invalid proxy URL"
+		return err
 	}
 
-	s.logger.Info("using proxy for health check",
-		zap.String("provider", p.Name),
-		zap.String("proxy_url", proxyInfo.URL))
-
-	// Create HTTP client with proxy + SSRF dial guard
-	httpClient := sanitize.SafeHTTPClientWithProxy(
-		s.allowLocal,
-		time.Duration(p.Timeout)*time.Second,
-		proxyURL,
-	)
-
-	// Decrypt API key if available
-	var decryptedKey string
-	if apiKey != nil && apiKey.EncryptedAPIKey != "" {
-		decryptedKey, err = crypto.Decrypt(apiKey.EncryptedAPIKey)
-		if err != nil {
-			return false, 0, "failed to decrypt API key: " + err.Error()
-		}
+	for _, p := range providers {
+		_, _ = s.CheckSingleProvider(ctx, p.ID)
 	}
 
-	// Determine health check endpoint based on provider
-	healthURL := s.resolveHealthURL(p.Name, p.BaseURL, decryptedKey)
+	return nil
+}
 
-	start := time.Now()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+// ProvidersMissingKeys returns the active providers that require an API key
+// but have no active key, so every request routed to them fails.
+func (s *Service) ProvidersMissingKeys(ctx context.Context) ([]models.Provider, error) {
+	providers, err := s.providerRepo.GetActive(ctx)
 	if err != nil {
-		return false, 0, err.Error()
+		return nil, err
 	}
-
-	// Add authorization headers for providers that need them
-	s.setAuthHeaders(req, p.Name, decryptedKey)
-
-	resp, err := httpClient.Do(req)
-	latency := time.Since(start)
+	keys, err := s.providerKeyRepo.GetAll(ctx)
 	if err != nil {
-		return false, latency, err.Error()
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	return s.evaluateHealthResponse(p.Name, decryptedKey, resp, latency)
-}
-
-// resolveHealthURL returns the health check endpoint for a given provider.
-func (s *Service) resolveHealthURL(providerName, baseURL, decryptedKey string) string {
-	switch providerName {
-	case "openai", "lmstudio", "vllm":
-		return baseURL + "/models"
-	case "ollama":
-		// Ollama's native health check is /api/tags, but if using OpenAI compatibility
-		// it should be /v1/models. Since we fixed OllamaClient to use /v1, we use /models here.
-		if strings.HasSuffix(baseURL, "/v1") || strings.Contains(baseURL, "/v1/") {
-			return baseURL + "/models"
-		}
-		return baseURL + "/api/tags"
-	case "anthropic":
-		return baseURL + "/v1/messages"
-	case "google":
-		u := baseURL + "/v1beta/models"
-		if decryptedKey != "" {
-			u += "?key=" + decryptedKey
-		}
-		return u
-	default:
-		return baseURL + "/models"
+		return nil, err
 	}
+	return providersMissingKeys(providers, keys), nil
 }
 
-// setAuthHeaders adds appropriate authorization headers for the given provider.
-func (s *Service) setAuthHeaders(req *http.Request, providerName, decryptedKey string) {
-	switch providerName {
-	case "openai", "lmstudio", "vllm", "ollama":
-		if decryptedKey != "" {
-			req.Header.Set("Authorization", "Bearer "+decryptedKey)
-		}
-	case "anthropic":
-		if decryptedKey != "" {
-			req.Header.Set("x-api-key", decryptedKey)
-			req.Header.Set("anthropic-version", "2023-06-01")
-		}
-	default:
-		// Default to OpenAI-compatible header for unknown providers
-		if decryptedKey != "" {
-			req.Header.Set("Authorization", "Bearer "+decryptedKey)
+func providersMissingKeys(providers []models.Provider, keys []models.ProviderAPIKey) []models.Provider {
+	hasKey := make(map[uuid.UUID]bool, len(providers))
+	for _, k := range keys {
+		if k.IsActive {
+			hasKey[k.ProviderID] = true
 		}
 	}
-}
-
-// evaluateHealthResponse interprets the HTTP response for a health check.
-func (s *Service) evaluateHealthResponse(providerName, decryptedKey string, resp *http.Response, latency time.Duration) (bool, time.Duration, string) {
-	// For Anthropic without API key, a 401 means the endpoint is reachable
-	if providerName == "anthropic" && decryptedKey == "" && resp.StatusCode == http.StatusUnauthorized {
-		return true, latency, ""
-	}
-
-	// For Google without API key, a 400/403 means the endpoint is reachable
-	if providerName == "google" && decryptedKey == "" && (resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusForbidden) {
-		return true, latency, ""
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return false, latency, "API returned status " + resp.Status + ": " + string(respBody)
-	}
-
-	return true, latency, ""
-}
-
-// CheckAllProviders runs health checks on all active providers.
-func (s *Service) CheckAllProviders(ctx context.Context) error {
-	providers, err := s.providerRepo.GetActive(ctx)
-	if err != nil {
-		return err
-	}
-
+	var missing []models.Provider
 	for _, p := range providers {
-		_, _ = s.CheckSingleProvider(ctx, p.ID)
+		if p.RequiresAPIKey && !hasKey[p.ID] {
+			missing = append(missing, p)
+		}
 	}
-
-	return nil
+	return missing
 }
@@ -1,13 +1,25 @@
 package health
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/provider"
 )
 
 func TestAPIKeyHealthStatus(t *testing.T) {
@@ -202,3 +214,459 @@ func TestAlertStatusTransitions(t *testing.T) {
 	nextStates = validTransitions[alert.Status]
 	assert.Len(t, nextStates, 0)
 }
+
+func TestRetryUntilBacksOffUntilSuccess(t *testing.T) {
+	calls := 0
+	var delays []time.Duration
+	ok := retryUntil(context.Background(), nil, time.Millisecond, 3*time.Millisecond,
+		func(context.Context) error {
+			calls++
+			if calls < 4 {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+		func(_ int, delay time.Duration, _ error) { delays = append(delays, delay) })
+
+	assert.True(t, ok)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}, delays)
+}
+
+func TestRetryUntilStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ok := retryUntil(ctx, nil, time.Hour, time.Hour,
+		func(context.Context) error { return errors.New("down") },
+		func(int, time.Duration, error) { cancel() })
+	assert.False(t, ok)
+
+	stop := make(chan struct{})
+	close(stop)
+	ok = retryUntil(context.Background(), stop, time.Hour, time.Hour,
+		func(context.Context) error { return errors.New("down") },
+		func(int, time.Duration, error) {})
+	assert.False(t, ok)
+}
+
+func TestProvidersMissingKeys(t *testing.T) {
+	newProvider := func(name string, requiresKey bool) models.Provider {
+		p := models.Provider{Name: name, RequiresAPIKey: requiresKey, IsActive: true}
+		p.ID = uuid.New()
+		return p
+	}
+	openai := newProvider("openai", true)
+	anthropic := newProvider("anthropic", true)
+	deepseek := newProvider("deepseek", true)
+	ollama := newProvider("ollama", false)
+
+	keys := []models.ProviderAPIKey{
+		{ProviderID: openai.ID, IsActive: true},
+		{ProviderID: anthropic.ID, IsActive: false},
+	}
+
+	missing := providersMissingKeys([]models.Provider{openai, anthropic, deepseek, ollama}, keys)
+
+	require.Len(t, missing, 2)
+	assert.Equal(t, "anthropic", missing[0].Name, "only inactive keys")
+	assert.Equal(t, "deepseek", missing[1].Name, "no keys at all")
+}
+
+type fakeClientBuilder struct {
+	key      *models.ProviderAPIKey
+	gotKey   *models.ProviderAPIKey
+	selected []uuid.UUID
+}
+
+func (b *fakeClientBuilder) SelectAPIKey(_ context.Context, providerID uuid.UUID) (*models.ProviderAPIKey, error) {
+	b.selected = append(b.selected, providerID)
+	if b.key == nil {
+		return nil, errors.New("no active API keys for provider")
+	}
+	return b.key, nil
+}
+
+func (b *fakeClientBuilder) GetProviderClientWithKey(_ context.Context, _ *models.Provider, apiKey *models.ProviderAPIKey) (provider.Client, error) {
+	b.gotKey = apiKey
+	return nil, errors.New("built by the router")
+}
+
+func TestHealthChecksUseClientBuilder(t *testing.T) {
+	// A provider added at runtime has no registry entry.
+	p := &models.Provider{Name: "runtime-added", RequiresAPIKey: true}
+	p.ID = uuid.New()
+	key := &models.ProviderAPIKey{ProviderID: p.ID, Alias: "selected"}
+	b := &fakeClientBuilder{key: key}
+	s := &Service{logger: zap.NewNop()}
+	s.SetClientBuilder(b)
+	ctx := context.Background()
+
+	got, err := s.selectAPIKey(ctx, p)
+	require.NoError(t, err)
+	assert.Same(t, key, got)
+	assert.Equal(t, []uuid.UUID{p.ID}, b.selected)
+
+	_, err = s.providerClient(ctx, p, got)
+	assert.EqualError(t, err, "built by the router")
+	assert.Same(t, key, b.gotKey)
+
+	b.key = nil
+	_, err = s.selectAPIKey(ctx, p)
+	assert.EqualError(t, err, "no active API keys for provider")
+}
+
+func TestProxiedProviderIsNotProbedDirectly(t *testing.T) {
+	p := &models.Provider{Name: "openai", BaseURL: "https://api.openai.com/v1", UseProxy: true}
+	s := &Service{logger: zap.NewNop()}
+
+	_, err := s.providerClient(context.Background(), p, nil)
+	assert.ErrorIs(t, err, errProxyNeedsClientBuilder)
+
+	b := &fakeClientBuilder{}
+	s.SetClientBuilder(b)
+	_, err = s.providerClient(context.Background(), p, nil)
+	assert.EqualError(t, err, "built by the router", "the router builds the proxied client")
+}
+
+func TestAlertNotifierTestWebhookSignsPayload(t *testing.T) {
+	var gotSig string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(alertSignatureHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := NewAlertNotifier(nil, nil, zap.NewNop(), true)
+	n.SetWebhookSecret("s3cret")
+
+	result, err := n.TestWebhook(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.True(t, result.Delivered)
+	assert.True(t, result.Signed)
+	assert.Equal(t, http.StatusNoContent, result.StatusCode)
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(gotBody)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), gotSig)
+}
+
+func TestAlertNotifierSendWebhookIsUnsigned(t *testing.T) {
+	var gotSig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(alertSignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := NewAlertNotifier(nil, nil, zap.NewNop(), true)
+	n.SetWebhookSecret("s3cret")
+
+	require.NoError(t, n.SendWebhook(context.Background(), srv.URL, map[string]string{"event": "api_key.spend_threshold"}))
+	assert.Empty(t, gotSig, "tenant webhooks never carry the platform signature")
+}
+
+func TestAlertNotifierTestWebhookReportsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(alertSignatureHeader), "unsigned without a secret")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	n := NewAlertNotifier(nil, nil, zap.NewNop(), true)
+	result, err := n.TestWebhook(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.False(t, result.Delivered)
+	assert.Equal(t, http.StatusBadGateway, result.StatusCode)
+	assert.NotEmpty(t, result.Error)
+
+	_, err = n.TestWebhook(context.Background(), "ftp://example.com/hook")
+	assert.ErrorIs(t, err, ErrInvalidWebhookURL, "unusable URLs are rejected up front")
+}
+
+// fakeAlertConfigRepo returns a fixed alert config lookup result.
+type fakeAlertConfigRepo struct {
+	repository.AlertConfigRepo
+	config *models.AlertConfig
+	err    error
+}
+
+func (r *fakeAlertConfigRepo) GetByTarget(context.Context, string, uuid.UUID) (*models.AlertConfig, error) {
+	return r.config, r.err
+}
+
+func TestTestAlertWebhookForTarget(t *testing.T) {
+	configs := &fakeAlertConfigRepo{}
+	s := &Service{alertNotifier: &AlertNotifier{alertConfigRepo: configs, logger: zap.NewNop()}}
+	ctx := context.Background()
+
+	configs.err = repository.ErrNotFound
+	_, err := s.TestAlertWebhook(ctx, "provider", uuid.New(), "")
+	assert.ErrorIs(t, err, ErrNoAlertWebhook)
+
+	configs.config, configs.err = &models.AlertConfig{}, nil
+	_, err = s.TestAlertWebhook(ctx, "provider", uuid.New(), "")
+	assert.ErrorIs(t, err, ErrNoAlertWebhook, "a config without a webhook has nothing to test")
+
+	dbErr := errors.New("connection refused")
+	configs.config, configs.err = nil, dbErr
+	_, err = s.TestAlertWebhook(ctx, "provider", uuid.New(), "")
+	assert.ErrorIs(t, err, dbErr, "a failed lookup is not reported as a missing webhook")
+	assert.NotErrorIs(t, err, ErrNoAlertWebhook)
+
+	_, err = (&Service{}).TestAlertWebhook(ctx, "provider", uuid.New(), "")
+	assert.ErrorIs(t, err, ErrAlertingDisabled)
+}
+
+// fakeDeliveryRepo is an in-memory AlertDeliveryRepo.
+type fakeDeliveryRepo struct {
+	items []*models.AlertDelivery
+}
+
+func (f *fakeDeliveryRepo) Create(_ context.Context, d *models.AlertDelivery) error {
+	d.ID = uuid.New()
+	f.items = append(f.items, d)
+	return nil
+}
+
+func (f *fakeDeliveryRepo) Update(_ context.Context, d *models.AlertDelivery) error {
+	for i, it := range f.items {
+		if it.ID == d.ID {
+			cp := *d
+			f.items[i] = &cp
+		}
+	}
+	return nil
+}
+
+func (f *fakeDeliveryRepo) ClaimDue(_ context.Context, now time.Time, _ time.Duration, limit int) ([]models.AlertDelivery, error) {
+	var due []models.AlertDelivery
+	for _, it := range f.items {
+		if it.Status == models.AlertDeliveryPending && !it.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, *it)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeDeliveryRepo) DeleteFinishedBefore(_ context.Context, before time.Time) (int64, error) {
+	kept := f.items[:0]
+	for _, it := range f.items {
+		if it.Status != models.AlertDeliveryPending && it.UpdatedAt.Before(before) {
+			continue
+		}
+		kept = append(kept, it)
+	}
+	n := int64(len(f.items) - len(kept))
+	f.items = kept
+	return n, nil
+}
+
+func (f *fakeDeliveryRepo) CountByStatus(_ context.Context) (map[string]int64, error) {
+	counts := map[string]int64{}
+	for _, it := range f.items {
+		counts[it.Status]++
+	}
+	return counts, nil
+}
+
+// makeDue pulls every pending delivery's next attempt into the past.
+func (f *fakeDeliveryRepo) makeDue() {
+	for _, it := range f.items {
+		it.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func TestDeliveryRetryConfigBacksOffExponentially(t *testing.T) {
+	cfg := DeliveryRetryConfig{MaxAttempts: 10, BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+	assert.Equal(t, 10*time.Second, cfg.delay(1))
+	assert.Equal(t, 20*time.Second, cfg.delay(2))
+	assert.Equal(t, 40*time.Second, cfg.delay(3))
+	assert.Equal(t, time.Minute, cfg.delay(4), "capped at MaxDelay")
+	assert.Equal(t, time.Minute, cfg.delay(50))
+}
+
+func TestFailedAlertWebhookIsRetriedUntilDelivered(t *testing.T) {
+	failures := 2
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	repo := &fakeDeliveryRepo{}
+	n := NewAlertNotifier(nil, nil, zap.NewNop(), true)
+	n.SetDeliveryQueue(repo, DeliveryRetryConfig{MaxAttempts: 5, BaseDelay: time.Minute})
+
+	alert := &models.Alert{BaseModel: models.BaseModel{ID: uuid.New()}, AlertType: "provider_down", Message: "down"}
+	require.Error(t, n.sendWebhook(context.Background(), srv.URL, alert))
+	require.Len(t, repo.items, 1)
+	d := repo.items[0]
+	assert.Equal(t, models.AlertDeliveryPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, d.StatusCode)
+	assert.True(t, d.NextAttemptAt.After(time.Now().Add(50*time.Second)), "first retry waits the base delay")
+
+	n.RetryDeliveries(context.Background())
+	assert.Equal(t, 1, calls, "not due yet")
+
+	repo.makeDue()
+	n.RetryDeliveries(context.Background())
+	d = repo.items[0]
+	assert.Equal(t, models.AlertDeliveryPending, d.Status)
+	assert.Equal(t, 2, d.Attempts)
+	assert.True(t, d.NextAttemptAt.After(time.Now().Add(110*time.Second)), "second retry waits twice as long")
+
+	repo.makeDue()
+	n.RetryDeliveries(context.Background())
+	d = repo.items[0]
+	assert.Equal(t, models.AlertDeliveryDelivered, d.Status)
+	assert.Equal(t, 3, d.Attempts)
+	assert.NotNil(t, d.DeliveredAt)
+	assert.Empty(t, d.LastError)
+
+	stats, err := n.DeliveryStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryStats{Delivered: 1}, *stats)
+}
+
+func TestFailedAlertWebhookIsDeadLetteredAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	repo := &fakeDeliveryRepo{}
+	n := NewAlertNotifier(nil, nil, zap.NewNop(), true)
+	n.SetDeliveryQueue(repo, DeliveryRetryConfig{MaxAttempts: 3, BaseDelay: time.Second})
+
+	alert := &models.Alert{BaseModel: models.BaseModel{ID: uuid.New()}}
+	require.Error(t, n.sendWebhook(context.Background(), srv.URL, alert))
+	for i := 0; i < 5; i++ {
+		repo.makeDue()
+		n.RetryDeliveries(context.Background())
+	}
+
+	d := repo.items[0]
+	assert.Equal(t, models.AlertDeliveryDead, d.Status)
+	assert.Equal(t, 3, d.Attempts, "no attempts after dead-lettering")
+	assert.Contains(t, d.LastError, "500")
+
+	stats, err := n.DeliveryStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Dead)
+	assert.Equal(t, int64(0), stats.Pending)
+}
+
+type recordingPenalizer struct{ factors map[uuid.UUID]float64 }
+
+func (p *recordingPenalizer) SetLatencyPenalty(id uuid.UUID, factor float64) { p.factors[id] = factor }
+
+func TestAverageLatencyNeedsEnoughHealthyChecks(t *testing.T) {
+	check := func(healthy bool, ms int64) models.HealthHistory {
+		return models.HealthHistory{IsHealthy: healthy, ResponseTime: ms}
+	}
+	assert.Equal(t, int64(0), averageLatency([]models.HealthHistory{check(true, 900), check(true, 1100), check(false, 5000)}))
+	assert.Equal(t, int64(1000), averageLatency([]models.HealthHistory{check(true, 900), check(true, 1100), check(false, 5000), check(true, 1000)}))
+}
+
+func TestSchedulerLatencySLABreachPenalizesUntilRecovered(t *testing.T) {
+	penalizer := &recordingPenalizer{factors: map[uuid.UUID]float64{}}
+	s := NewScheduler(nil, nil, time.Minute, zap.NewNop())
+	s.SetLatencyPenalizer(penalizer, 0.25)
+	ctx := context.Background()
+	slow := ProviderHealthStatus{ID: uuid.New(), Name: "openai", AvgLatencyMs: 2400, LatencyThresholdMs: 1500, LatencySLABreached: true}
+
+	s.evaluateLatencySLAs(ctx, []ProviderHealthStatus{slow})
+	assert.InDelta(t, 0.25, penalizer.factors[slow.ID], 1e-9)
+	require.Contains(t, s.latencyBreaches, slow.ID)
+	assert.True(t, s.latencyBreaches[slow.ID].raised)
+
+	unknown := slow
+	unknown.AvgLatencyMs, unknown.LatencySLABreached = 0, false
+	s.evaluateLatencySLAs(ctx, []ProviderHealthStatus{unknown})
+	assert.Contains(t, s.latencyBreaches, slow.ID, "too few healthy checks keeps the breach open")
+
+	recovered := slow
+	recovered.AvgLatencyMs, recovered.LatencySLABreached = 900, false
+	s.evaluateLatencySLAs(ctx, []ProviderHealthStatus{recovered})
+	assert.NotContains(t, s.latencyBreaches, slow.ID)
+	assert.InDelta(t, 1.0, penalizer.factors[slow.ID], 1e-9, "the penalty is lifted")
+
+	s.evaluateLatencySLAs(ctx, []ProviderHealthStatus{slow})
+	s.evaluateLatencySLAs(ctx, nil)
+	assert.Empty(t, s.latencyBreaches, "a deactivated provider's breach is resolved")
+}
+
+// fakeAlertRepo keeps alerts in memory.
+type fakeAlertRepo struct {
+	repository.AlertRepo
+	alerts []models.Alert
+}
+
+func (r *fakeAlertRepo) GetByTarget(_ context.Context, targetType string, targetID uuid.UUID) ([]models.Alert, error) {
+	var out []models.Alert
+	for _, a := range r.alerts {
+		if a.TargetType == targetType && a.TargetID == targetID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAlertRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Alert, error) {
+	for i := range r.alerts {
+		if r.alerts[i].ID == id {
+			return &r.alerts[i], nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *fakeAlertRepo) Update(_ context.Context, alert *models.Alert) error {
+	for i := range r.alerts {
+		if r.alerts[i].ID == alert.ID {
+			r.alerts[i] = *alert
+		}
+	}
+	return nil
+}
+
+func TestSchedulerLatencySLAAdoptsOpenAlertsAfterRestart(t *testing.T) {
+	breached := ProviderHealthStatus{ID: uuid.New(), Name: "openai", AvgLatencyMs: 2400, LatencyThresholdMs: 1500, LatencySLABreached: true}
+	recovered := ProviderHealthStatus{ID: uuid.New(), Name: "azure", AvgLatencyMs: 900, LatencyThresholdMs: 1500}
+	alert := func(target uuid.UUID, alertType, status string) models.Alert {
+		a := models.Alert{TargetType: "provider", TargetID: target, AlertType: alertType, Status: status}
+		a.ID = uuid.New()
+		return a
+	}
+	repo := &fakeAlertRepo{alerts: []models.Alert{
+		alert(breached.ID, AlertTypeLatencySLABreach, "resolved"),
+		alert(breached.ID, AlertTypeLatencySLABreach, "acknowledged"),
+		alert(recovered.ID, "provider_unhealthy", "active"),
+		alert(recovered.ID, AlertTypeLatencySLABreach, "active"),
+	}}
+	notifier := &AlertNotifier{alertRepo: repo, logger: zap.NewNop()}
+	penalizer := &recordingPenalizer{factors: map[uuid.UUID]float64{}}
+	s := NewScheduler(nil, notifier, time.Minute, zap.NewNop())
+	s.SetLatencyPenalizer(penalizer, 0.5)
+
+	// A Raise would reach the (nil) alert config repository; adopting the
+	// open alert must not raise another one.
+	s.evaluateLatencySLAs(context.Background(), []ProviderHealthStatus{breached, recovered})
+
+	require.Contains(t, s.latencyBreaches, breached.ID)
+	assert.Equal(t, repo.alerts[1].ID, s.latencyBreaches[breached.ID].alertID, "the acknowledged alert is still open")
+	assert.True(t, s.latencyBreaches[breached.ID].raised)
+	assert.InDelta(t, 0.5, penalizer.factors[breached.ID], 1e-9)
+	assert.Len(t, repo.alerts, 4, "no duplicate alert is recorded")
+
+	assert.NotContains(t, s.latencyBreaches, recovered.ID)
+	assert.Equal(t, "resolved", repo.alerts[3].Status, "an alert left open by an earlier run is resolved once the provider recovered")
+	assert.Equal(t, "active", repo.alerts[2].Status, "other alert types are left alone")
+}
@@ -0,0 +1,176 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AlertTypeLatencySLABreach is raised on a provider whose average health
+// check latency exceeds the latency threshold of its alert config, and
+// resolved once it recovers.
+const AlertTypeLatencySLABreach = "latency_sla_breach"
+
+// latencySLAMinSamples is how many healthy checks the rolling average needs
+// before it is compared with the threshold, so one slow check does not alert.
+const latencySLAMinSamples = 3
+
+// LatencyPenalizer scales a provider's routing weight while it breaches its
+// latency SLA. The router implements it.
+type LatencyPenalizer interface {
+	SetLatencyPenalty(providerID uuid.UUID, factor float64)
+}
+
+// averageLatency returns the mean response time of the healthy checks in
+// history, or 0 when there are fewer than latencySLAMinSamples of them.
+func averageLatency(history []models.HealthHistory) int64 {
+	var total int64
+	samples := 0
+	for _, h := range history {
+		if h.IsHealthy && h.ResponseTime > 0 {
+			total += h.ResponseTime
+			samples++
+		}
+	}
+	if samples < latencySLAMinSamples {
+		return 0
+	}
+	return total / int64(samples)
+}
+
+// latencyThreshold returns the latency SLA of a target in milliseconds, or 0
+// when it has none or its alert config is disabled.
+func (s *Service) latencyThreshold(ctx context.Context, targetType string, targetID uuid.UUID) int {
+	if s.alertNotifier == nil {
+		return 0
+	}
+	config, err := s.alertNotifier.GetAlertConfigByTarget(ctx, targetType, targetID)
+	if err != nil || !config.IsEnabled || config.LatencyThresholdMs <= 0 {
+		return 0
+	}
+	return config.LatencyThresholdMs
+}
+
+// latencyBreach is a provider currently over its latency SLA. raised is false
+// until its alert was recorded, so a failed raise is retried on the next run.
+type latencyBreach struct {
+	alertID uuid.UUID
+	raised  bool
+}
+
+// SetLatencyPenalizer makes providers breaching their latency SLA get their
+// routing weight multiplied by factor until they recover. A factor of 1 or
+// more only alerts.
+func (s *Scheduler) SetLatencyPenalizer(p LatencyPenalizer, factor float64) {
+	s.penalizer = p
+	s.penaltyFactor = factor
+}
+
+// checkLatencySLAs raises a latency_sla_breach alert when a provider's
+// average latency exceeds its threshold and resolves it once the average is
+// back under it, the threshold is removed or the provider is deactivated.
+func (s *Scheduler) checkLatencySLAs(ctx context.Context) {
+	statuses, err := s.healthService.GetProvidersHealth(ctx)
+	if err != nil {
+		s.logger.Error("failed to get provider statuses for latency SLAs", zap.Error(err))
+		return
+	}
+	s.evaluateLatencySLAs(ctx, statuses)
+}
+
+func (s *Scheduler) evaluateLatencySLAs(ctx context.Context, statuses []ProviderHealthStatus) {
+	firstRun := s.latencyBreaches == nil
+	if firstRun {
+		s.latencyBreaches = make(map[uuid.UUID]*latencyBreach)
+	}
+	seen := make(map[uuid.UUID]bool, len(statuses))
+	for _, st := range statuses {
+		seen[st.ID] = true
+		breach, open := s.latencyBreaches[st.ID]
+		// Breaches are only tracked in memory: pick up an alert left open by an
+		// earlier run or another instance rather than raising a duplicate, and
+		// on the first run so one that recovered meanwhile still gets resolved.
+		if !open && (firstRun || st.LatencySLABreached) {
+			if id := s.openAlert(ctx, st.ID); id != uuid.Nil {
+				breach, open = &latencyBreach{alertID: id, raised: true}, true
+				s.latencyBreaches[st.ID] = breach
+				s.penalize(st.ID, s.penaltyFactor)
+			}
+		}
+		switch {
+		case st.LatencySLABreached:
+			if !open {
+				breach = &latencyBreach{}
+				s.latencyBreaches[st.ID] = breach
+				s.logger.Warn("provider latency SLA breached", zap.String("provider", st.Name),
+					zap.Int64("avg_latency_ms", st.AvgLatencyMs), zap.Int("threshold_ms", st.LatencyThresholdMs))
+				s.penalize(st.ID, s.penaltyFactor)
+			}
+			if !breach.raised {
+				msg := fmt.Sprintf("Provider %s average latency %dms exceeds its %dms SLA",
+					st.Name, st.AvgLatencyMs, st.LatencyThresholdMs)
+				breach.alertID, breach.raised = s.raise(ctx, "provider", st.ID, AlertTypeLatencySLABreach, msg)
+			}
+		case open && (st.LatencyThresholdMs == 0 || st.AvgLatencyMs > 0):
+			// An unknown average (too few healthy checks) keeps the breach open.
+			s.latencyRecovered(ctx, st.ID, breach)
+		}
+	}
+	for id, breach := range s.latencyBreaches {
+		if !seen[id] {
+			s.latencyRecovered(ctx, id, breach)
+		}
+	}
+}
+
+func (s *Scheduler) latencyRecovered(ctx context.Context, providerID uuid.UUID, breach *latencyBreach) {
+	delete(s.latencyBreaches, providerID)
+	s.penalize(providerID, 1)
+	s.logger.Info("provider latency SLA recovered", zap.String("provider_id", providerID.String()))
+	if s.notifier == nil || breach.alertID == uuid.Nil {
+		return
+	}
+	if err := s.notifier.ResolveAlert(ctx, breach.alertID); err != nil {
+		s.logger.Error("failed to resolve latency SLA alert", zap.String("alert_id", breach.alertID.String()), zap.Error(err))
+	}
+}
+
+// openAlert returns the open latency SLA alert of a provider, or uuid.Nil.
+func (s *Scheduler) openAlert(ctx context.Context, providerID uuid.UUID) uuid.UUID {
+	if s.notifier == nil {
+		return uuid.Nil
+	}
+	id, err := s.notifier.FindOpenAlert(ctx, "provider", providerID, AlertTypeLatencySLABreach)
+	if err != nil {
+		s.logger.Error("failed to look up open latency SLA alert", zap.String("provider_id", providerID.String()), zap.Error(err))
+	}
+	return id
+}
+
+func (s *Scheduler) penalize(providerID uuid.UUID, factor float64) {
+	if s.penalizer != nil && s.penaltyFactor < 1 {
+		s.penalizer.SetLatencyPenalty(providerID, factor)
+	}
+}
+
+// raise records an alert through the notifier and returns its ID. Without a
+// notifier the alert is only logged, which counts as raised.
+func (s *Scheduler) raise(ctx context.Context, targetType string, targetID uuid.UUID, alertType, message string) (uuid.UUID, bool) {
+	if s.notifier == nil {
+		s.notify(ctx, targetType, targetID, alertType, message)
+		return uuid.Nil, true
+	}
+	id, err := s.notifier.Raise(ctx, targetType, targetID, alertType, message)
+	if err != nil {
+		s.logger.Error("failed to raise health alert",
+			zap.String("target_type", targetType),
+			zap.String("alert_type", alertType),
+			zap.Error(err))
+		return uuid.Nil, false
+	}
+	return id, true
+}
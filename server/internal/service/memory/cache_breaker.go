@@ -0,0 +1,133 @@
+// Package memory provides conversation memory management.
+// This file implements the breaker that bypasses the Redis cache while Redis
+// is unreachable.
+package memory
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// cacheTripFailures is how many consecutive Redis failures trip the
+	// breaker, so a single blip does not bypass the cache.
+	cacheTripFailures = 3
+	// cacheCooldown is how long the cache is bypassed after the breaker trips
+	// before the next call tries Redis again.
+	cacheCooldown = 30 * time.Second
+	// cachePurgeTimeout bounds one background purge of the conversation cache.
+	cachePurgeTimeout = time.Minute
+	// cacheWarnInterval rate-limits the warnings logged while Redis fails.
+	cacheWarnInterval = time.Minute
+)
+
+// cacheBreaker trips after cacheTripFailures consecutive Redis failures so
+// conversations are served from the database without first waiting on Redis.
+// After cacheCooldown the next caller starts a purge; the breaker closes once
+// the purge succeeds.
+//
+// Writes and invalidations skipped or failed while Redis misbehaves may leave
+// stale entries in Redis, which every instance reads. A failed write, or a
+// trip, therefore marks the cache stale: it is bypassed until a background
+// purge has cleared every conversation entry.
+type cacheBreaker struct {
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu         sync.Mutex
+	failures   int // consecutive failures
+	openUntil  time.Time
+	open       bool
+	stale      bool // a purge is needed before the cache is used again
+	purging    bool
+	lastWarn   time.Time
+	suppressed int // failures not logged since lastWarn
+}
+
+func newCacheBreaker(logger *zap.Logger) *cacheBreaker {
+	return &cacheBreaker{logger: logger, now: time.Now}
+}
+
+// allow reports whether Redis should be called. When the cache is stale and
+// no purge is running (once the cooldown has passed, if the breaker is open)
+// it tells a single caller to start one; the cache stays bypassed until that
+// purge reports back through purged.
+func (b *cacheBreaker) allow() (ok, purge bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.purging || (b.open && b.now().Before(b.openUntil)) {
+		return false, false
+	}
+	if b.open || b.stale {
+		b.purging = true
+		return false, true
+	}
+	return true, false
+}
+
+// purged records the outcome of the purge allow asked for. Success closes
+// the breaker; failure counts as a Redis failure.
+func (b *cacheBreaker) purged(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.purging = false
+	if err != nil {
+		b.fail("purge", err)
+		return
+	}
+	if b.open {
+		b.logger.Info("redis reachable again, conversation cache re-enabled",
+			zap.Int("suppressed_errors", b.suppressed))
+		b.suppressed = 0
+		b.lastWarn = time.Time{}
+	}
+	b.open, b.stale, b.failures = false, false, 0
+}
+
+// record updates the breaker with the outcome of a Redis call. A cache miss
+// (redis.Nil) counts as success. A failed set or delete marks the cache
+// stale at once; only the purge closes an open breaker.
+func (b *cacheBreaker) record(op string, err error) {
+	if err != nil && errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		if !b.open {
+			b.failures = 0
+		}
+		return
+	}
+	b.fail(op, err)
+}
+
+// fail counts a Redis failure and trips the breaker once enough have come in
+// a row. The caller holds b.mu.
+func (b *cacheBreaker) fail(op string, err error) {
+	now := b.now()
+	b.failures++
+	if op != "get" {
+		b.stale = true
+	}
+	if b.failures < cacheTripFailures {
+		return
+	}
+	b.open, b.stale = true, true
+	b.openUntil = now.Add(cacheCooldown)
+	if now.Sub(b.lastWarn) < cacheWarnInterval {
+		b.suppressed++
+		return
+	}
+	b.logger.Warn("redis unavailable, bypassing conversation cache",
+		zap.String("op", op),
+		zap.Error(err),
+		zap.Duration("retry_after", cacheCooldown),
+		zap.Int("suppressed_errors", b.suppressed))
+	b.lastWarn = now
+	b.suppressed = 0
+}
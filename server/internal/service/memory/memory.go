@@ -4,38 +4,61 @@ package memory
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"llm-router-platform/internal/crypto"
 	"llm-router-platform/internal/models"
 	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/provider"
 	"llm-router-platform/pkg/sanitize"
+	"llm-router-platform/pkg/tokencount"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// DefaultMaxMessages is the default cap on stored messages per conversation.
+const DefaultMaxMessages = 200
+
+// sequenceAttempts bounds how often AddMessage picks a new sequence number
+// after a concurrent write to the same conversation took the one it chose.
+const sequenceAttempts = 10
+
 // Service handles conversation memory.
 type Service struct {
-	memoryRepo *repository.ConversationMemoryRepository
-	redis      *redis.Client
-	logger     *zap.Logger
-	ttl        time.Duration
+	memoryRepo  repository.ConversationMemoryRepo
+	redis       *redis.Client
+	cache       *cacheBreaker
+	logger      *zap.Logger
+	ttl         time.Duration
+	maxMessages int // 0 = unlimited
 }
 
 // NewService creates a new memory service.
 func NewService(
-	memoryRepo *repository.ConversationMemoryRepository,
+	memoryRepo repository.ConversationMemoryRepo,
 	redisClient *redis.Client,
 	logger *zap.Logger,
 ) *Service {
 	return &Service{
-		memoryRepo: memoryRepo,
-		redis:      redisClient,
-		logger:     logger,
-		ttl:        24 * time.Hour,
+		memoryRepo:  memoryRepo,
+		redis:       redisClient,
+		cache:       newCacheBreaker(logger),
+		logger:      logger,
+		ttl:         24 * time.Hour,
+		maxMessages: DefaultMaxMessages,
+	}
+}
+
+// SetMaxMessages caps the messages stored per conversation; AddMessage prunes
+// the oldest non-system messages beyond it. 0 disables the cap.
+func (s *Service) SetMaxMessages(n int) {
+	if n < 0 {
+		n = 0
 	}
+	s.maxMessages = n
 }
 
 // Message represents a conversation message.
@@ -47,12 +70,10 @@ type Message struct {
 
 // AddMessage adds a message to conversation memory.
 // L4: Content is encrypted at rest using AES-256-GCM.
+// Sequence numbers are unique per conversation (enforced by the database), so
+// concurrent writes to one conversation retry with the next free number
+// instead of storing duplicates.
 func (s *Service) AddMessage(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID, role, content string, tokenCount int) error {
-	sequence, err := s.getNextSequence(ctx, projectID, apiKeyID, conversationID)
-	if err != nil {
-		return err
-	}
-
 	// L4: Encrypt content before storing
 	encryptedContent := content
 	if crypto.IsInitialized() {
@@ -74,16 +95,52 @@ func (s *Service) AddMessage(ctx context.Context, projectID uuid.UUID, apiKeyID
 		Role:           role,
 		Content:        encryptedContent,
 		TokenCount:     tokenCount,
-		Sequence:       sequence,
 	}
 
-	if err := s.memoryRepo.Create(ctx, memory); err != nil {
-		return err
+	for attempt := 1; ; attempt++ {
+		sequence, err := s.getNextSequence(ctx, projectID, apiKeyID, conversationID)
+		if err != nil {
+			return err
+		}
+		memory.Sequence = sequence
+		err = s.memoryRepo.Create(ctx, memory)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, repository.ErrDuplicateKey) || attempt == sequenceAttempts {
+			return err
+		}
+		s.logger.Debug("conversation sequence taken by a concurrent write, retrying",
+			zap.String("conversation_id", sanitize.LogValue(conversationID)),
+			zap.Int("sequence", sequence))
+	}
+
+	if err := s.pruneToCap(ctx, projectID, apiKeyID, conversationID); err != nil {
+		s.logger.Warn("failed to prune conversation history",
+			zap.Error(err),
+			zap.String("conversation_id", sanitize.LogValue(conversationID)),
+		)
 	}
 
 	return s.updateCache(ctx, projectID, apiKeyID, conversationID)
 }
 
+// pruneToCap deletes the oldest non-system messages beyond maxMessages.
+func (s *Service) pruneToCap(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) error {
+	if s.maxMessages <= 0 {
+		return nil
+	}
+	count, err := s.memoryRepo.CountByConversation(ctx, projectID, apiKeyID, conversationID)
+	if err != nil {
+		return err
+	}
+	excess := int(count) - s.maxMessages
+	if excess <= 0 {
+		return nil
+	}
+	return s.memoryRepo.DeleteOldestNonSystemByConversation(ctx, projectID, apiKeyID, conversationID, excess)
+}
+
 // GetConversation retrieves conversation messages.
 // L4: Content is decrypted on read.
 func (s *Service) GetConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) ([]Message, error) {
@@ -132,6 +189,61 @@ func (s *Service) GetConversationWithLimit(ctx context.Context, projectID uuid.U
 	return messages[len(messages)-limit:], nil
 }
 
+// BuildContext assembles the most recent conversation messages whose combined
+// token count fits maxPromptTokens, returned oldest-first and ready to send to
+// a provider. System messages are always kept. Messages stored without a
+// token count are measured with model's tokenizer.
+func (s *Service) BuildContext(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID, model string, maxPromptTokens int) ([]provider.Message, error) {
+	messages, err := s.GetConversation(ctx, projectID, apiKeyID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	fitted := fitTokenBudget(messages, model, maxPromptTokens)
+	out := make([]provider.Message, len(fitted))
+	for i, m := range fitted {
+		out[i] = provider.Message{Role: m.Role, Content: provider.StringContent(m.Content)}
+	}
+	return out, nil
+}
+
+// fitTokenBudget keeps every system message plus the newest other messages
+// that fit in the remaining budget. Selection stops at the first message that
+// does not fit so the kept history stays contiguous.
+func fitTokenBudget(messages []Message, model string, maxTokens int) []Message {
+	tokens := make([]int, len(messages))
+	remaining := maxTokens
+	for i, m := range messages {
+		tokens[i] = m.TokenCount
+		if tokens[i] <= 0 {
+			tokens[i] = tokencount.CountTokens(m.Content, model)
+		}
+		if m.Role == "system" {
+			remaining -= tokens[i]
+		}
+	}
+
+	keep := make([]bool, len(messages))
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "system" {
+			continue
+		}
+		if tokens[i] > remaining {
+			break
+		}
+		remaining -= tokens[i]
+		keep[i] = true
+	}
+
+	out := make([]Message, 0, len(messages))
+	for i, m := range messages {
+		if keep[i] || m.Role == "system" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
 // ClearConversation deletes all messages in a conversation.
 func (s *Service) ClearConversation(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) error {
 	if err := s.memoryRepo.DeleteByConversation(ctx, projectID, apiKeyID, conversationID); err != nil {
@@ -192,18 +304,69 @@ func (s *Service) TruncateConversation(ctx context.Context, projectID uuid.UUID,
 	return s.updateCache(ctx, projectID, apiKeyID, conversationID)
 }
 
-// getNextSequence returns the next sequence number.
+// getNextSequence returns the next sequence number. It follows the highest
+// stored sequence rather than the message count, so numbers keep increasing
+// after older messages are pruned or truncated.
 func (s *Service) getNextSequence(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) (int, error) {
-	messages, err := s.memoryRepo.GetByConversation(ctx, projectID, apiKeyID, conversationID)
+	maxSeq, err := s.memoryRepo.MaxSequence(ctx, projectID, apiKeyID, conversationID)
 	if err != nil {
-		return 1, nil
+		return 0, err
 	}
-	return len(messages) + 1, nil
+	return maxSeq + 1, nil
+}
+
+// cacheKeyPrefix starts every conversation cache key.
+const cacheKeyPrefix = "conversation:"
+
+// cacheAllowed reports whether Redis should be used. After an outage or a
+// failed write every cached conversation is cleared in the background before
+// the cache is used again, so no instance serves an entry that missed an
+// update; callers go to the database meanwhile.
+func (s *Service) cacheAllowed(ctx context.Context) bool {
+	if s.redis == nil {
+		return false
+	}
+	ok, purge := s.cache.allow()
+	if purge {
+		go s.runPurge(context.WithoutCancel(ctx))
+	}
+	return ok
+}
+
+// runPurge clears the conversation cache and reports the outcome to the
+// breaker.
+func (s *Service) runPurge(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, cachePurgeTimeout)
+	defer cancel()
+	s.cache.purged(s.purgeCache(ctx))
+}
+
+// purgeCache deletes every conversation cache entry.
+func (s *Service) purgeCache(ctx context.Context) error {
+	const batch = 500
+	iter := s.redis.Scan(ctx, 0, cacheKeyPrefix+"*", batch).Iterator()
+	keys := make([]string, 0, batch)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == batch {
+			if err := s.redis.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return s.redis.Del(ctx, keys...).Err()
+	}
+	return nil
 }
 
 // cacheKey generates a cache key — includes apiKeyID when present for namespace isolation.
 func (s *Service) cacheKey(projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) string {
-	key := "conversation:" + projectID.String() + ":"
+	key := cacheKeyPrefix + projectID.String() + ":"
 	if apiKeyID != nil {
 		key += apiKeyID.String() + ":"
 	}
@@ -211,14 +374,16 @@ func (s *Service) cacheKey(projectID uuid.UUID, apiKeyID *uuid.UUID, conversatio
 	return key
 }
 
-// getFromCache retrieves messages from Redis cache.
+// getFromCache retrieves messages from Redis cache. It returns nothing while
+// the cache is bypassed.
 func (s *Service) getFromCache(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) ([]Message, error) {
-	if s.redis == nil {
+	if !s.cacheAllowed(ctx) {
 		return nil, nil
 	}
 
 	key := s.cacheKey(projectID, apiKeyID, conversationID)
 	data, err := s.redis.Get(ctx, key).Bytes()
+	s.cache.record("get", err)
 	if err != nil {
 		return nil, err
 	}
@@ -231,9 +396,11 @@ func (s *Service) getFromCache(ctx context.Context, projectID uuid.UUID, apiKeyI
 	return messages, nil
 }
 
-// setCache stores messages in Redis cache.
+// setCache stores messages in Redis cache. Redis failures go to the cache
+// breaker instead of failing the caller, whose write already reached the
+// database.
 func (s *Service) setCache(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string, messages []Message) error {
-	if s.redis == nil {
+	if !s.cacheAllowed(ctx) {
 		return nil
 	}
 
@@ -243,7 +410,8 @@ func (s *Service) setCache(ctx context.Context, projectID uuid.UUID, apiKeyID *u
 		return err
 	}
 
-	return s.redis.Set(ctx, key, data, s.ttl).Err()
+	s.cache.record("set", s.redis.Set(ctx, key, data, s.ttl).Err())
+	return nil
 }
 
 // updateCache refreshes the cache from database.
@@ -272,14 +440,16 @@ func (s *Service) updateCache(ctx context.Context, projectID uuid.UUID, apiKeyID
 	return s.setCache(ctx, projectID, apiKeyID, conversationID, messages)
 }
 
-// deleteCache removes conversation from cache.
+// deleteCache removes conversation from cache. A failed delete marks the
+// cache stale, and the purge that follows clears the entry.
 func (s *Service) deleteCache(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) error {
-	if s.redis == nil {
+	if !s.cacheAllowed(ctx) {
 		return nil
 	}
 
 	key := s.cacheKey(projectID, apiKeyID, conversationID)
-	return s.redis.Del(ctx, key).Err()
+	s.cache.record("delete", s.redis.Del(ctx, key).Err())
+	return nil
 }
 
 // CompressConversation replaces older messages with a summary to reduce token usage.
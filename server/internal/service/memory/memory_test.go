@@ -2,10 +2,23 @@ package memory
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
 )
 
 func TestMessage(t *testing.T) {
@@ -149,3 +162,318 @@ func TestEmptyConversation(t *testing.T) {
 
 	assert.Len(t, messages, 0)
 }
+
+func TestFitTokenBudget_MixedSizes(t *testing.T) {
+	messages := []Message{
+		{Role: "system", Content: "You are helpful.", TokenCount: 10},
+		{Role: "user", Content: "first", TokenCount: 50},
+		{Role: "assistant", Content: "long answer", TokenCount: 400},
+		{Role: "user", Content: "second", TokenCount: 30},
+		{Role: "assistant", Content: "short", TokenCount: 20},
+		{Role: "user", Content: "third", TokenCount: 40},
+	}
+
+	got := fitTokenBudget(messages, "gpt-4", 150)
+
+	total := 0
+	contents := make([]string, len(got))
+	for i, m := range got {
+		total += m.TokenCount
+		contents[i] = m.Content
+	}
+	assert.LessOrEqual(t, total, 150)
+	// The 400-token answer does not fit, so older history stops there even
+	// though "first" alone would fit.
+	assert.Equal(t, []string{"You are helpful.", "second", "short", "third"}, contents)
+}
+
+func TestFitTokenBudget_KeepsSystemWhenOverBudget(t *testing.T) {
+	messages := []Message{
+		{Role: "system", Content: "rules", TokenCount: 100},
+		{Role: "user", Content: "hi", TokenCount: 5},
+	}
+
+	got := fitTokenBudget(messages, "gpt-4", 50)
+
+	assert.Len(t, got, 1)
+	assert.Equal(t, "system", got[0].Role)
+}
+
+func TestFitTokenBudget_CountsUnmeasuredMessages(t *testing.T) {
+	messages := []Message{
+		{Role: "user", Content: strings.Repeat("word ", 200)},
+		{Role: "user", Content: "hi", TokenCount: 1},
+	}
+
+	got := fitTokenBudget(messages, "gpt-4", 50)
+
+	assert.Len(t, got, 1)
+	assert.Equal(t, "hi", got[0].Content)
+}
+
+// fakeMemoryRepo keeps a single conversation in memory, ordered by sequence.
+type fakeMemoryRepo struct {
+	repository.ConversationMemoryRepo
+	rows []models.ConversationMemory
+}
+
+func (r *fakeMemoryRepo) Create(_ context.Context, m *models.ConversationMemory) error {
+	r.rows = append(r.rows, *m)
+	return nil
+}
+
+func (r *fakeMemoryRepo) GetByConversation(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ string) ([]models.ConversationMemory, error) {
+	return r.rows, nil
+}
+
+func (r *fakeMemoryRepo) MaxSequence(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ string) (int, error) {
+	maxSeq := 0
+	for _, m := range r.rows {
+		if m.Sequence > maxSeq {
+			maxSeq = m.Sequence
+		}
+	}
+	return maxSeq, nil
+}
+
+func (r *fakeMemoryRepo) CountByConversation(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ string) (int64, error) {
+	return int64(len(r.rows)), nil
+}
+
+func (r *fakeMemoryRepo) DeleteOldestNonSystemByConversation(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ string, count int) error {
+	kept := r.rows[:0]
+	for _, m := range r.rows {
+		if count > 0 && m.Role != "system" {
+			count--
+			continue
+		}
+		kept = append(kept, m)
+	}
+	r.rows = kept
+	return nil
+}
+
+func TestAddMessage_PrunesOldestNonSystemBeyondCap(t *testing.T) {
+	repo := &fakeMemoryRepo{}
+	svc := NewService(repo, nil, zap.NewNop())
+	svc.SetMaxMessages(3)
+	ctx := context.Background()
+	projectID := uuid.New()
+
+	assert.NoError(t, svc.AddMessage(ctx, projectID, nil, "conv", "system", "rules", 1))
+	for _, content := range []string{"one", "two", "three", "four"} {
+		assert.NoError(t, svc.AddMessage(ctx, projectID, nil, "conv", "user", content, 1))
+	}
+
+	var contents []string
+	var sequences []int
+	for _, m := range repo.rows {
+		contents = append(contents, m.Content)
+		sequences = append(sequences, m.Sequence)
+	}
+	assert.Equal(t, []string{"rules", "three", "four"}, contents)
+	assert.Equal(t, []int{1, 4, 5}, sequences)
+
+	// Sequence numbers keep increasing after pruning instead of reusing the count.
+	assert.NoError(t, svc.AddMessage(ctx, projectID, nil, "conv", "user", "five", 1))
+	assert.Len(t, repo.rows, 3)
+	assert.Equal(t, 6, repo.rows[len(repo.rows)-1].Sequence)
+}
+
+func TestAddMessage_ZeroCapKeepsEverything(t *testing.T) {
+	repo := &fakeMemoryRepo{}
+	svc := NewService(repo, nil, zap.NewNop())
+	svc.SetMaxMessages(0)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, svc.AddMessage(context.Background(), uuid.New(), nil, "conv", "user", "hi", 1))
+	}
+
+	assert.Len(t, repo.rows, 5)
+}
+
+// uniqueSequenceRepo is safe for concurrent use and rejects a sequence number
+// already stored, like the unique index on conversation_memories.
+type uniqueSequenceRepo struct {
+	fakeMemoryRepo
+	mu sync.Mutex
+}
+
+func (r *uniqueSequenceRepo) Create(ctx context.Context, m *models.ConversationMemory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.rows {
+		if row.Sequence == m.Sequence {
+			return fmt.Errorf("%w: idx_conversation_memories_sequence", repository.ErrDuplicateKey)
+		}
+	}
+	return r.fakeMemoryRepo.Create(ctx, m)
+}
+
+func (r *uniqueSequenceRepo) MaxSequence(ctx context.Context, projectID uuid.UUID, apiKeyID *uuid.UUID, conversationID string) (int, error) {
+	r.mu.Lock()
+	maxSeq, err := r.fakeMemoryRepo.MaxSequence(ctx, projectID, apiKeyID, conversationID)
+	r.mu.Unlock()
+	runtime.Gosched() // let concurrent writers read the same maximum
+	return maxSeq, err
+}
+
+func (r *uniqueSequenceRepo) GetByConversation(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ string) ([]models.ConversationMemory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.ConversationMemory(nil), r.rows...), nil
+}
+
+func TestAddMessage_ConcurrentWritesGetDistinctSequences(t *testing.T) {
+	repo := &uniqueSequenceRepo{}
+	svc := NewService(repo, nil, zap.NewNop())
+	svc.SetMaxMessages(0)
+	projectID := uuid.New()
+
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.AddMessage(context.Background(), projectID, nil, "conv", "user", "hi", 1)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	seen := make(map[int]bool, writers)
+	for _, m := range repo.rows {
+		assert.False(t, seen[m.Sequence], "sequence %d stored twice", m.Sequence)
+		seen[m.Sequence] = true
+	}
+	assert.Len(t, seen, writers)
+	for seq := 1; seq <= writers; seq++ {
+		assert.True(t, seen[seq], "sequence %d missing", seq)
+	}
+}
+
+// waitForPurge waits for the background cache purge, if any, to report back.
+func waitForPurge(t *testing.T, svc *Service) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		svc.cache.mu.Lock()
+		defer svc.cache.mu.Unlock()
+		return !svc.cache.purging
+	}, time.Second, time.Millisecond)
+}
+
+func TestCacheBreaker_TripsAfterConsecutiveFailures(t *testing.T) {
+	b := newCacheBreaker(zap.NewNop())
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	fail := errors.New("LOADING redis is loading")
+
+	b.record("get", fail)
+	b.record("get", fail)
+	ok, purge := b.allow()
+	assert.True(t, ok, "failed reads below the threshold do not trip the breaker")
+	assert.False(t, purge)
+
+	b.record("get", nil)
+	b.record("get", fail)
+	b.record("get", fail)
+	ok, _ = b.allow()
+	assert.True(t, ok, "a success resets the count")
+
+	b.record("get", fail)
+	ok, purge = b.allow()
+	assert.False(t, ok, "the breaker trips on the third failure in a row")
+	assert.False(t, purge, "nothing is purged during the cooldown")
+
+	now = now.Add(cacheCooldown)
+	ok, purge = b.allow()
+	assert.False(t, ok)
+	assert.True(t, purge, "after the cooldown one caller starts the purge")
+	ok, purge = b.allow()
+	assert.False(t, ok, "the cache is bypassed while the purge runs")
+	assert.False(t, purge, "only one purge runs")
+
+	b.purged(nil)
+	ok, purge = b.allow()
+	assert.True(t, ok, "a successful purge closes the breaker")
+	assert.False(t, purge)
+
+	b.record("set", fail)
+	ok, purge = b.allow()
+	assert.False(t, ok, "a failed write leaves a possibly stale entry")
+	assert.True(t, purge)
+	b.purged(fail)
+	ok, purge = b.allow()
+	assert.False(t, ok, "a failed purge is retried")
+	assert.True(t, purge)
+}
+
+func TestConversationCache_BypassesUnreachableRedisAndRecovers(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer func() { _ = client.Close() }()
+
+	repo := &fakeMemoryRepo{}
+	svc := NewService(repo, client, zap.NewNop())
+	ctx := context.Background()
+	projectID := uuid.New()
+	key := svc.cacheKey(projectID, nil, "conv")
+
+	require.NoError(t, svc.AddMessage(ctx, projectID, nil, "conv", "user", "one", 1))
+	assert.True(t, mr.Exists(key))
+
+	mr.SetError("LOADING redis is loading")
+	require.NoError(t, svc.AddMessage(ctx, projectID, nil, "conv", "user", "two", 1), "a cache failure does not fail the write")
+
+	msgs, err := svc.GetConversation(ctx, projectID, nil, "conv")
+	require.NoError(t, err)
+	assert.Len(t, msgs, 2, "served from the database")
+	waitForPurge(t, svc)
+
+	mr.SetError("")
+	msgs, err = svc.GetConversation(ctx, projectID, nil, "conv")
+	require.NoError(t, err)
+	assert.Len(t, msgs, 2, "the entry cached before the outage is not served")
+	waitForPurge(t, svc)
+	assert.False(t, mr.Exists(key), "the purge clears the stale entry")
+
+	msgs, err = svc.GetConversation(ctx, projectID, nil, "conv")
+	require.NoError(t, err)
+	assert.Len(t, msgs, 2)
+	assert.True(t, mr.Exists(key), "caching resumes")
+	ok, purge := svc.cache.allow()
+	assert.True(t, ok)
+	assert.False(t, purge)
+}
+
+func TestConversationCache_RecoveryClearsEntriesSharedWithOtherInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer func() { _ = client.Close() }()
+
+	repo := &fakeMemoryRepo{}
+	a := NewService(repo, client, zap.NewNop())
+	b := NewService(repo, client, zap.NewNop())
+	ctx := context.Background()
+	projectID := uuid.New()
+
+	require.NoError(t, b.AddMessage(ctx, projectID, nil, "conv", "user", "one", 1))
+
+	// Instance a loses Redis and cannot refresh the shared entry.
+	mr.SetError("LOADING redis is loading")
+	require.NoError(t, a.AddMessage(ctx, projectID, nil, "conv", "user", "two", 1))
+	mr.SetError("")
+
+	_, err := a.GetConversation(ctx, projectID, nil, "unrelated")
+	require.NoError(t, err)
+	waitForPurge(t, a)
+
+	msgs, err := b.GetConversation(ctx, projectID, nil, "conv")
+	require.NoError(t, err)
+	assert.Len(t, msgs, 2, "the other instance no longer reads the stale entry")
+}
@@ -67,6 +67,7 @@ func NewOTelService(ctx context.Context, cfg config.ObservabilityConfig, logger
 		sdktrace.WithSpanProcessor(bsp),
 	)
 	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator())
 
 	// 2. Meter Provider
 	metricExporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpoint(cfg.OTelEndpoint), otlpmetrichttp.WithInsecure())
@@ -0,0 +1,98 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifies spans created by this service. Spans go to
+// the global TracerProvider, which stays a no-op unless NewOTelService
+// installed an exporter, so instrumented code paths cost almost nothing by
+// default.
+const TracerName = "llm-router-platform"
+
+// Span attribute keys shared across the request path.
+const (
+	AttrProvider = attribute.Key("llm.provider")
+	AttrModel    = attribute.Key("llm.model")
+)
+
+// StartSpan starts a child span of whatever span ctx carries.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err on span, if any, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// propagator is the W3C trace-context + baggage propagator installed globally
+// when OpenTelemetry is enabled.
+func propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// tracingTransport wraps an http.RoundTripper with a client span per upstream
+// call and injects the trace context into the outgoing headers.
+type tracingTransport struct {
+	base     http.RoundTripper
+	provider string
+}
+
+// NewTracingTransport returns base instrumented with a client span for each
+// request, tagged with the provider name. A nil base uses http.DefaultTransport.
+func NewTracingTransport(base http.RoundTripper, providerName string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &tracingTransport{base: base, provider: providerName}
+}
+
+// RoundTrip implements http.RoundTripper. The span ends when response headers
+// arrive; streamed bodies are covered by the caller's span.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(TracerName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttrProvider.String(t.provider),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		EndSpan(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
+
+// CloseIdleConnections forwards to the wrapped transport so http.Client's
+// CloseIdleConnections still reaches the connection pool.
+func (t *tracingTransport) CloseIdleConnections() {
+	type closeIdler interface{ CloseIdleConnections() }
+	if c, ok := t.base.(closeIdler); ok {
+		c.CloseIdleConnections()
+	}
+}
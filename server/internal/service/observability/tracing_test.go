@@ -0,0 +1,94 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// withRecorder installs an in-memory tracer provider and the W3C propagator
+// for the duration of a test.
+func withRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prevProp := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagator())
+	t.Cleanup(func() {
+		// The original global provider delegates to whatever was set, so
+		// restore an explicit no-op one instead.
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(prevProp)
+	})
+	return rec
+}
+
+func TestTracingTransport_RecordsClientSpanAndPropagates(t *testing.T) {
+	rec := withRecorder(t)
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, parent := StartSpan(context.Background(), "provider.chat", AttrModel.String("gpt-4o"))
+	client := &http.Client{Transport: NewTracingTransport(nil, "openai")}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/v1/chat/completions", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	parent.End()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	httpSpan := spans[0]
+	if httpSpan.Name() != "HTTP POST" || httpSpan.SpanKind() != trace.SpanKindClient {
+		t.Errorf("unexpected span %q kind %v", httpSpan.Name(), httpSpan.SpanKind())
+	}
+	if httpSpan.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("HTTP span should be a child of the provider span")
+	}
+	if httpSpan.Status().Code.String() != "Error" {
+		t.Errorf("5xx response should mark the span as error, got %v", httpSpan.Status().Code)
+	}
+	if traceparent == "" {
+		t.Error("trace context was not injected into the upstream request")
+	}
+}
+
+func TestStartSpan_NoopWithoutProvider(t *testing.T) {
+	_, span := StartSpan(context.Background(), "router.route")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("spans should not record when no exporter is configured")
+	}
+}
+
+type idleCounter struct {
+	http.RoundTripper
+	closed int
+}
+
+func (c *idleCounter) CloseIdleConnections() { c.closed++ }
+
+func TestTracingTransport_ForwardsCloseIdleConnections(t *testing.T) {
+	base := &idleCounter{RoundTripper: http.DefaultTransport}
+	client := &http.Client{Transport: NewTracingTransport(base, "openai")}
+	client.CloseIdleConnections()
+	if base.closed != 1 {
+		t.Errorf("CloseIdleConnections reached the wrapped transport %d times, want 1", base.closed)
+	}
+}
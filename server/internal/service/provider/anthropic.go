@@ -17,10 +17,11 @@ import (
 
 // AnthropicClient implements the Client interface for Anthropic.
 type AnthropicClient struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	logger     *zap.Logger
+	apiKey        string
+	baseURL       string
+	httpClient    *http.Client
+	logger        *zap.Logger
+	promptCaching bool // Mark the system prompt with cache_control
 }
 
 // NewAnthropicClient creates a new Anthropic client.
@@ -32,20 +33,36 @@ func NewAnthropicClient(cfg *config.ProviderConfig, logger *zap.Logger) *Anthrop
 		httpClient = cfg.HTTPClient()
 	}
 	return &AnthropicClient{
-		apiKey:     cfg.APIKey,
-		baseURL:    cfg.BaseURL,
-		httpClient: httpClient,
-		logger:     logger,
+		apiKey:        cfg.APIKey,
+		baseURL:       cfg.BaseURL,
+		httpClient:    httpClient,
+		logger:        logger,
+		promptCaching: cfg.PromptCaching,
 	}
 }
 
 // Chat sends a chat completion request to Anthropic.
 func (c *AnthropicClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if err := CheckResponseFormat("anthropic", req.ResponseFormat); err != nil {
+		return nil, err
+	}
+	system, turns := splitAnthropicSystem(req.Messages)
+	messages, err := anthropicMessages(turns)
+	if err != nil {
+		return nil, err
+	}
 	anthropicReq := map[string]interface{}{
 		"model":      req.Model,
-		"messages":   req.Messages,
+		"messages":   messages,
 		"max_tokens": req.MaxTokens,
 	}
+	if system != "" {
+		anthropicReq["system"] = c.systemField(system)
+	}
+	applyAnthropicSampling(anthropicReq, req)
+	if err := applyAnthropicTools(anthropicReq, req); err != nil {
+		return nil, err
+	}
 
 	body, err := json.Marshal(anthropicReq)
 	if err != nil {
@@ -69,56 +86,108 @@ func (c *AnthropicClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResp
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "Anthropic API error",
-		}
+		return nil, newProviderError(c.logger, "Anthropic API error", resp, respBody)
 	}
 
 	var anthropicResp struct {
-		ID      string `json:"id"`
-		Model   string `json:"model"`
-		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		} `json:"content"`
-		Usage struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-		} `json:"usage"`
+		ID         string                  `json:"id"`
+		Model      string                  `json:"model"`
+		Content    []anthropicContentBlock `json:"content"`
+		StopReason string                  `json:"stop_reason"`
+		Usage      anthropicUsage          `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&anthropicResp); err != nil {
 		return nil, err
 	}
 
-	content := ""
-	for _, c := range anthropicResp.Content {
-		if c.Type == "text" {
-			content += c.Text
-		}
-	}
-
 	return &ChatResponse{
 		ID:    anthropicResp.ID,
 		Model: anthropicResp.Model,
 		Choices: []Choice{
 			{
 				Index:        0,
-				Message:      Message{Role: "assistant", Content: StringContent(content)},
-				FinishReason: "stop",
+				Message:      anthropicAssistantMessage(anthropicResp.Content),
+				FinishReason: anthropicFinishReason(anthropicResp.StopReason),
 			},
 		},
-		Usage: Usage{
-			PromptTokens:     anthropicResp.Usage.InputTokens,
-			CompletionTokens: anthropicResp.Usage.OutputTokens,
-			TotalTokens:      anthropicResp.Usage.InputTokens + anthropicResp.Usage.OutputTokens,
-		},
+		Usage: anthropicResp.Usage.toUsage(),
 	}, nil
 }
 
+// anthropicUsage is the usage block of a Messages API response. input_tokens
+// excludes the tokens written to or read from the prompt cache.
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// toUsage reports every prompt token, cached or not, in PromptTokens and
+// breaks out the cache writes and reads for billing.
+func (u anthropicUsage) toUsage() Usage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return Usage{
+		PromptTokens:        prompt,
+		CompletionTokens:    u.OutputTokens,
+		TotalTokens:         prompt + u.OutputTokens,
+		CacheCreationTokens: u.CacheCreationInputTokens,
+		CacheReadTokens:     u.CacheReadInputTokens,
+	}
+}
+
+// systemField returns the Messages API "system" value: the plain string, or
+// with prompt caching enabled a single text block ending in an ephemeral
+// cache_control breakpoint, so the system prompt is cached across requests.
+func (c *AnthropicClient) systemField(system string) interface{} {
+	if !c.promptCaching {
+		return system
+	}
+	return []map[string]interface{}{{
+		"type":          "text",
+		"text":          system,
+		"cache_control": map[string]string{"type": "ephemeral"},
+	}}
+}
+
+// applyAnthropicSampling copies the optional sampling parameters the Messages
+// API understands. Anthropic names the stop parameter "stop_sequences" and has
+// no equivalent for the OpenAI penalties or n. The end user travels as
+// metadata.user_id.
+func applyAnthropicSampling(anthropicReq map[string]interface{}, req *ChatRequest) {
+	if req.Temperature > 0 {
+		anthropicReq["temperature"] = req.Temperature
+	}
+	if req.TopP != nil {
+		anthropicReq["top_p"] = *req.TopP
+	}
+	if len(req.Stop) > 0 {
+		anthropicReq["stop_sequences"] = []string(req.Stop)
+	}
+	if req.User != "" {
+		anthropicReq["metadata"] = map[string]string{"user_id": req.User}
+	}
+}
+
+// splitAnthropicSystem moves system-role messages out of the conversation.
+// Anthropic rejects "system" inside messages and expects a top-level system
+// string instead; multiple system messages are joined with blank lines.
+func splitAnthropicSystem(messages []Message) (string, []Message) {
+	var system []string
+	turns := make([]Message, 0, len(messages))
+	for _, m := range messages {
+		if m.Role == "system" {
+			if m.Content.Text != "" {
+				system = append(system, m.Content.Text)
+			}
+			continue
+		}
+		turns = append(turns, m)
+	}
+	return strings.Join(system, "\n\n"), turns
+}
+
 // Embeddings returns ErrNotImplemented as Anthropic doesn't natively support this endpoint format.
 func (c *AnthropicClient) Embeddings(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
 	return nil, ErrNotImplemented
@@ -149,11 +218,20 @@ func (c *AnthropicClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
 }
 
 // CheckHealth verifies the Anthropic API is accessible.
+// Anthropic has no cheap listing endpoint, so this is always a completion.
 func (c *AnthropicClient) CheckHealth(ctx context.Context) (bool, time.Duration, error) {
+	return c.CheckHealthDeep(ctx, "")
+}
+
+// CheckHealthDeep sends a minimal completion to verify the key can generate.
+func (c *AnthropicClient) CheckHealthDeep(ctx context.Context, model string) (bool, time.Duration, error) {
 	start := time.Now()
 
+	if model == "" {
+		model = "claude-3-haiku-20240307"
+	}
 	req := &ChatRequest{
-		Model: "claude-3-haiku-20240307",
+		Model: model,
 		Messages: []Message{
 			{Role: "user", Content: StringContent("Hi")},
 		},
@@ -168,17 +246,32 @@ func (c *AnthropicClient) CheckHealth(ctx context.Context) (bool, time.Duration,
 
 // StreamChat sends a real SSE streaming request to Anthropic Messages API.
 func (c *AnthropicClient) StreamChat(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	if err := CheckResponseFormat("anthropic", req.ResponseFormat); err != nil {
+		return nil, err
+	}
 	maxTokens := req.MaxTokens
 	if maxTokens <= 0 {
 		maxTokens = 1024
 	}
 
+	system, turns := splitAnthropicSystem(req.Messages)
+	messages, err := anthropicMessages(turns)
+	if err != nil {
+		return nil, err
+	}
 	anthropicReq := map[string]interface{}{
 		"model":      req.Model,
-		"messages":   req.Messages,
+		"messages":   messages,
 		"max_tokens": maxTokens,
 		"stream":     true,
 	}
+	if system != "" {
+		anthropicReq["system"] = c.systemField(system)
+	}
+	applyAnthropicSampling(anthropicReq, req)
+	if err := applyAnthropicTools(anthropicReq, req); err != nil {
+		return nil, err
+	}
 
 	body, err := json.Marshal(anthropicReq)
 	if err != nil {
@@ -202,12 +295,7 @@ func (c *AnthropicClient) StreamChat(ctx context.Context, req *ChatRequest) (<-c
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "Anthropic API error",
-		}
+		return nil, newProviderError(c.logger, "Anthropic API error", resp, respBody)
 	}
 
 	chunks := make(chan StreamChunk)
@@ -217,6 +305,10 @@ func (c *AnthropicClient) StreamChat(ctx context.Context, req *ChatRequest) (<-c
 
 		scanner := bufio.NewScanner(resp.Body)
 		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		var usage anthropicUsage
+		// Tool calls are numbered in order of appearance, keyed by the
+		// content block index Anthropic streams them under.
+		toolIndex := map[int]int{}
 
 		for scanner.Scan() {
 			line := scanner.Text()
@@ -229,20 +321,39 @@ func (c *AnthropicClient) StreamChat(ctx context.Context, req *ChatRequest) (<-c
 				Type  string `json:"type"`
 				Index int    `json:"index"`
 				Delta struct {
-					Type string `json:"type"`
-					Text string `json:"text"`
+					Type        string `json:"type"`
+					Text        string `json:"text"`
+					PartialJSON string `json:"partial_json"`
+					StopReason  string `json:"stop_reason"`
 				} `json:"delta"`
-				Usage struct {
-					OutputTokens int `json:"output_tokens"`
-				} `json:"usage"`
+				ContentBlock anthropicContentBlock `json:"content_block"`
+				Message      struct {
+					Usage anthropicUsage `json:"usage"`
+				} `json:"message"`
+				Usage anthropicUsage `json:"usage"`
 			}
 			if err := json.Unmarshal([]byte(data), &event); err != nil {
 				continue
 			}
 
 			switch event.Type {
+			case "message_start":
+				// Input and prompt-cache usage arrive with the message start.
+				usage = event.Message.Usage
+			case "content_block_start":
+				if event.ContentBlock.Type == "tool_use" {
+					n := len(toolIndex)
+					toolIndex[event.Index] = n
+					chunks <- toolCallChunk(req.Model, toolCallDelta{
+						Index:    n,
+						ID:       event.ContentBlock.ID,
+						Type:     "function",
+						Function: toolCallDeltaFunction{Name: event.ContentBlock.Name},
+					})
+				}
 			case "content_block_delta":
-				if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+				switch {
+				case event.Delta.Type == "text_delta" && event.Delta.Text != "":
 					chunks <- StreamChunk{
 						Model: req.Model,
 						Choices: []DeltaChoice{{
@@ -250,25 +361,54 @@ func (c *AnthropicClient) StreamChat(ctx context.Context, req *ChatRequest) (<-c
 							Delta: Delta{Content: event.Delta.Text},
 						}},
 					}
+				case event.Delta.Type == "input_json_delta" && event.Delta.PartialJSON != "":
+					if n, ok := toolIndex[event.Index]; ok {
+						chunks <- toolCallChunk(req.Model, toolCallDelta{
+							Index:    n,
+							Function: toolCallDeltaFunction{Arguments: event.Delta.PartialJSON},
+						})
+					}
 				}
 			case "message_delta":
-				// Final usage info
-				if event.Usage.OutputTokens > 0 {
+				if event.Delta.StopReason != "" {
 					chunks <- StreamChunk{
-						Usage: &Usage{
-							CompletionTokens: event.Usage.OutputTokens,
-						},
+						Model: req.Model,
+						Choices: []DeltaChoice{{
+							Index:        0,
+							FinishReason: anthropicFinishReason(event.Delta.StopReason),
+						}},
 					}
 				}
+				// Final usage, sent even when no output was produced so the
+				// prompt tokens of the stream are still billed.
+				usage.OutputTokens = event.Usage.OutputTokens
+				final := usage.toUsage()
+				chunks <- StreamChunk{Usage: &final}
 			case "message_stop":
 				chunks <- StreamChunk{Done: true}
 				return
 			}
 		}
 
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			chunks <- streamReadError(err)
+			return
+		}
 		// If we exit the loop without message_stop, send done
 		chunks <- StreamChunk{Done: true}
 	}()
 
 	return chunks, nil
 }
+
+// toolCallChunk wraps one streamed tool call delta in a chunk for choice 0.
+func toolCallChunk(model string, delta toolCallDelta) StreamChunk {
+	calls, _ := json.Marshal([]toolCallDelta{delta})
+	return StreamChunk{
+		Model: model,
+		Choices: []DeltaChoice{{
+			Index: 0,
+			Delta: Delta{ToolCalls: calls},
+		}},
+	}
+}
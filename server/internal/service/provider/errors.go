@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// maxProviderErrorDetail bounds the provider-supplied part of an error message
+// so oversized or echoed request payloads never reach logs or clients.
+const maxProviderErrorDetail = 300
+
+// newProviderError builds a ProviderError for a non-200 upstream response.
+// The raw body is kept on the error and logged at debug level only.
+func newProviderError(logger *zap.Logger, message string, resp *http.Response, body []byte) *ProviderError {
+	if logger != nil {
+		logger.Debug("provider returned error response",
+			zap.String("provider_error", message),
+			zap.Int("status", resp.StatusCode),
+			zap.ByteString("body", body),
+		)
+	}
+	return &ProviderError{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+		Body:       body,
+		Message:    message,
+	}
+}
+
+// decodeChatResponse decodes the body of a 200 chat completion response.
+// Some OpenAI-compatible backends, and Ollama on certain failures, answer 200
+// with an error object instead of choices; such bodies become a ProviderError
+// and bodies without choices return ErrEmptyResponse, so an empty completion
+// is never served or billed as a success.
+func decodeChatResponse(logger *zap.Logger, message string, resp *http.Response, body []byte) (*ChatResponse, error) {
+	var env struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &env) == nil && len(env.Error) > 0 && string(env.Error) != "null" {
+		return nil, newProviderError(logger, message, resp, body)
+	}
+
+	var chatResp ChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("%s: %w", message, ErrEmptyResponse)
+	}
+	return &chatResp, nil
+}
+
+// providerErrorBody covers the error envelopes used by the supported
+// providers: OpenAI/Anthropic/Gemini nest an "error" object, Ollama returns
+// "error" as a string, and Mistral reports "message"/"type" or "detail" at
+// the top level.
+type providerErrorBody struct {
+	Error   json.RawMessage `json:"error"`
+	Message string          `json:"message"`
+	Type    string          `json:"type"`
+	Detail  json.RawMessage `json:"detail"`
+}
+
+type providerErrorObject struct {
+	Message string          `json:"message"`
+	Type    string          `json:"type"`
+	Code    json.RawMessage `json:"code"`
+	Status  string          `json:"status"`
+}
+
+// summarizeProviderError renders a concise "provider returned 429:
+// rate_limit_exceeded - ..." message from a provider error response.
+func summarizeProviderError(status int, body []byte) string {
+	code, msg := parseProviderErrorBody(body)
+	detail := msg
+	if code != "" && msg != "" {
+		detail = code + " - " + msg
+	} else if code != "" {
+		detail = code
+	}
+	if detail == "" {
+		detail = strings.Join(strings.Fields(string(body)), " ")
+	}
+
+	out := "provider returned " + strconv.Itoa(status)
+	if status == 0 {
+		out = "provider returned an error"
+	}
+	if detail == "" {
+		return out
+	}
+	return out + ": " + truncateErrorDetail(detail)
+}
+
+// parseProviderErrorBody extracts an error code and message from a provider
+// error body, returning empty strings when the body is not a known shape.
+func parseProviderErrorBody(body []byte) (code, message string) {
+	var env providerErrorBody
+	if err := json.Unmarshal(body, &env); err != nil {
+		return "", ""
+	}
+
+	if len(env.Error) > 0 {
+		var s string
+		if json.Unmarshal(env.Error, &s) == nil {
+			return "", s
+		}
+		var obj providerErrorObject
+		if json.Unmarshal(env.Error, &obj) == nil {
+			return errorObjectCode(obj), obj.Message
+		}
+	}
+
+	if env.Message != "" || env.Type != "" {
+		return env.Type, env.Message
+	}
+	if len(env.Detail) > 0 {
+		var s string
+		if json.Unmarshal(env.Detail, &s) == nil {
+			return "", s
+		}
+		return "", string(env.Detail)
+	}
+	return "", ""
+}
+
+// errorObjectCode prefers a symbolic code, then the error type, then the
+// Gemini-style status. Numeric codes only repeat the HTTP status.
+func errorObjectCode(obj providerErrorObject) string {
+	var code string
+	if json.Unmarshal(obj.Code, &code) == nil && code != "" {
+		return code
+	}
+	if obj.Type != "" {
+		return obj.Type
+	}
+	return obj.Status
+}
+
+// truncateErrorDetail collapses whitespace and caps s at maxProviderErrorDetail
+// bytes without splitting a UTF-8 sequence.
+func truncateErrorDetail(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= maxProviderErrorDetail {
+		return s
+	}
+	cut := maxProviderErrorDetail
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 
 	"llm-router-platform/internal/config"
@@ -8,7 +9,23 @@ import (
 	"go.uber.org/zap"
 )
 
-// NewClientByName creates a provider Client by provider name.
+// TypeOpenAICompatible is the provider type of upstreams that speak the
+// OpenAI API but have no dedicated client, such as gateways and hosted
+// open-weight models.
+const TypeOpenAICompatible = "openai-compatible"
+
+// ErrUnknownProviderType is returned for a provider type no client exists for.
+var ErrUnknownProviderType = errors.New("unknown provider type")
+
+// IsKnownType reports whether NewClientByName can build a client for the
+// provider type.
+func IsKnownType(providerType string) bool {
+	_, ok := builtinCapabilities[providerType]
+	return ok || providerType == TypeOpenAICompatible
+}
+
+// NewClientByName creates a provider Client by provider type; name is the
+// type, not the provider instance's name (see models.Provider.ClientType).
 // This is the single source of truth for mapping provider names to client
 // constructors, eliminating the duplicated switch blocks in Router and Health.
 // Every client is wrapped with RetryClient for automatic transient error retry.
@@ -36,13 +53,10 @@ func NewClientByNameWithRetry(name string, cfg *config.ProviderConfig, retryCfg
 		inner = NewDeepSeekClient(cfg, logger)
 	case "mistral":
 		inner = NewMistralClient(cfg, logger)
-	case "vllm":
+	case "vllm", TypeOpenAICompatible:
 		inner = NewOpenAIClient(cfg, logger)
 	default:
-		// Fall back to OpenAI-compatible client for unknown providers
-		logger.Debug("unknown provider name, falling back to OpenAI-compatible client",
-			zap.String("provider", name))
-		inner = NewOpenAIClient(cfg, logger)
+		return nil, fmt.Errorf("%w %q", ErrUnknownProviderType, name)
 	}
 
 	// Wrap with retry decorator for automatic transient error handling.
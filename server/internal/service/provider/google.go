@@ -59,8 +59,16 @@ type geminiPart struct {
 
 // geminiGenerationConfig represents generation configuration.
 type geminiGenerationConfig struct {
-	MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
-	Temperature     float64 `json:"temperature,omitempty"`
+	MaxOutputTokens  int      `json:"maxOutputTokens,omitempty"`
+	Temperature      float64  `json:"temperature,omitempty"`
+	TopP             *float64 `json:"topP,omitempty"`
+	StopSequences    []string `json:"stopSequences,omitempty"`
+	CandidateCount   int      `json:"candidateCount,omitempty"`
+	PresencePenalty  *float64 `json:"presencePenalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequencyPenalty,omitempty"`
+	// Structured output, translated from OpenAI's response_format.
+	ResponseMimeType   string          `json:"responseMimeType,omitempty"`
+	ResponseJSONSchema json.RawMessage `json:"responseJsonSchema,omitempty"`
 }
 
 // geminiResponse represents a Google Gemini API response.
@@ -71,6 +79,7 @@ type geminiResponse struct {
 
 // geminiCandidate represents a response candidate.
 type geminiCandidate struct {
+	Index        int           `json:"index"`
 	Content      geminiContent `json:"content"`
 	FinishReason string        `json:"finishReason"`
 }
@@ -124,6 +133,31 @@ func buildGeminiContents(messages []Message) []geminiContent {
 	return contents
 }
 
+// buildGeminiGenerationConfig maps the OpenAI-style sampling parameters and
+// response_format onto Gemini's generationConfig, returning nil when none are
+// set. JSON modes become an application/json response MIME type, plus the
+// schema for json_schema.
+func buildGeminiGenerationConfig(req *ChatRequest) *geminiGenerationConfig {
+	if req.MaxTokens <= 0 && req.Temperature <= 0 && req.TopP == nil && len(req.Stop) == 0 &&
+		req.N <= 0 && req.PresencePenalty == nil && req.FrequencyPenalty == nil && !req.ResponseFormat.Structured() {
+		return nil
+	}
+	cfg := &geminiGenerationConfig{
+		MaxOutputTokens:  req.MaxTokens,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		StopSequences:    req.Stop,
+		CandidateCount:   req.N,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+	}
+	if req.ResponseFormat.Structured() {
+		cfg.ResponseMimeType = "application/json"
+		cfg.ResponseJSONSchema = req.ResponseFormat.schema()
+	}
+	return cfg
+}
+
 // Chat sends a chat completion request to Google Gemini.
 func (c *GoogleClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
 	contents := buildGeminiContents(req.Messages)
@@ -132,12 +166,7 @@ func (c *GoogleClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 		Contents: contents,
 	}
 
-	if req.MaxTokens > 0 || req.Temperature > 0 {
-		geminiReq.GenerationConfig = &geminiGenerationConfig{
-			MaxOutputTokens: req.MaxTokens,
-			Temperature:     req.Temperature,
-		}
-	}
+	geminiReq.GenerationConfig = buildGeminiGenerationConfig(req)
 
 	body, err := json.Marshal(geminiReq)
 	if err != nil {
@@ -162,12 +191,7 @@ func (c *GoogleClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "Google API error",
-		}
+		return nil, newProviderError(c.logger, "Google API error", resp, respBody)
 	}
 
 	var geminiResp geminiResponse
@@ -276,12 +300,7 @@ func (c *GoogleClient) Embeddings(ctx context.Context, req *EmbeddingRequest) (*
 		if resp.StatusCode != http.StatusOK {
 			respBody, _ := io.ReadAll(resp.Body)
 			_ = resp.Body.Close()
-			return nil, &ProviderError{
-				StatusCode: resp.StatusCode,
-				Headers:    resp.Header,
-				Body:       respBody,
-				Message:    "Google API error",
-			}
+			return nil, newProviderError(c.logger, "Google API error", resp, respBody)
 		}
 
 		var embedResp geminiEmbedResponse
@@ -335,12 +354,7 @@ func (c *GoogleClient) StreamChat(ctx context.Context, req *ChatRequest) (<-chan
 		Contents: contents,
 	}
 
-	if req.MaxTokens > 0 || req.Temperature > 0 {
-		geminiReq.GenerationConfig = &geminiGenerationConfig{
-			MaxOutputTokens: req.MaxTokens,
-			Temperature:     req.Temperature,
-		}
-	}
+	geminiReq.GenerationConfig = buildGeminiGenerationConfig(req)
 
 	body, err := json.Marshal(geminiReq)
 	if err != nil {
@@ -365,49 +379,142 @@ func (c *GoogleClient) StreamChat(ctx context.Context, req *ChatRequest) (<-chan
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "Google API error",
-		}
+		return nil, newProviderError(c.logger, "Google API error", resp, respBody)
 	}
 
 	chunks := make(chan StreamChunk)
-	go func() {
-		defer close(chunks)
-		defer func() { _ = resp.Body.Close() }()
+	go processGeminiStream(ctx, resp.Body, req.Model, chunks, c.logger)
 
-		scanner := bufio.NewScanner(resp.Body)
-		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return chunks, nil
+}
 
+// processGeminiStream translates a streamGenerateContent response body into
+// StreamChunks. It accepts both wire formats: SSE "data:" lines (alt=sse) and
+// the default JSON array of GenerateContentResponse objects.
+func processGeminiStream(ctx context.Context, body io.ReadCloser, model string, chunks chan<- StreamChunk, logger *zap.Logger) {
+	defer close(chunks)
+	defer func() { _ = body.Close() }()
+
+	send := func(chunk StreamChunk) bool {
+		select {
+		case chunks <- chunk:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	emit := func(resp *geminiResponse) bool {
+		chunk, ok := geminiStreamChunk(resp, model)
+		if !ok {
+			return true
+		}
+		return send(chunk)
+	}
+
+	reader := bufio.NewReaderSize(body, 64*1024)
+	if first, err := peekNonSpace(reader); err == nil && first == '[' {
+		dec := json.NewDecoder(reader)
+		if _, err := dec.Token(); err == nil {
+			for dec.More() {
+				var geminiResp geminiResponse
+				if err := dec.Decode(&geminiResp); err != nil {
+					if logger != nil {
+						logger.Debug("failed to decode Gemini stream element", zap.Error(err))
+					}
+					break
+				}
+				if !emit(&geminiResp) {
+					return
+				}
+			}
+		}
+	} else {
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
 		for scanner.Scan() {
 			line := scanner.Text()
-			if !strings.HasPrefix(line, "data: ") {
+			if !strings.HasPrefix(line, "data:") {
 				continue
 			}
-			data := strings.TrimPrefix(line, "data: ")
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
 
 			var geminiResp geminiResponse
 			if err := json.Unmarshal([]byte(data), &geminiResp); err != nil {
 				continue
 			}
-
-			if len(geminiResp.Candidates) > 0 && len(geminiResp.Candidates[0].Content.Parts) > 0 {
-				chunks <- StreamChunk{
-					Model: req.Model,
-					Choices: []DeltaChoice{{
-						Index: 0,
-						Delta: Delta{Content: geminiResp.Candidates[0].Content.Parts[0].Text},
-					}},
-				}
+			if !emit(&geminiResp) {
+				return
 			}
 		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			send(streamReadError(err))
+			return
+		}
+	}
 
-		chunks <- StreamChunk{Done: true}
-	}()
+	send(StreamChunk{Done: true})
+}
 
-	return chunks, nil
+// peekNonSpace skips leading whitespace and returns the next byte unread.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// geminiStreamChunk converts one streamed Gemini response into a StreamChunk.
+// usageMetadata is cumulative, so the last chunk carrying it holds the final
+// counts. ok is false when the response has neither content nor usage.
+func geminiStreamChunk(resp *geminiResponse, model string) (chunk StreamChunk, ok bool) {
+	chunk.Model = model
+	for _, cand := range resp.Candidates {
+		var text strings.Builder
+		for _, part := range cand.Content.Parts {
+			text.WriteString(part.Text)
+		}
+		finish := geminiFinishReason(cand.FinishReason)
+		if text.Len() == 0 && finish == "" {
+			continue
+		}
+		chunk.Choices = append(chunk.Choices, DeltaChoice{
+			Index:        cand.Index,
+			Delta:        Delta{Content: text.String()},
+			FinishReason: finish,
+		})
+	}
+	if resp.UsageMetadata != nil {
+		chunk.Usage = &Usage{
+			PromptTokens:     resp.UsageMetadata.PromptTokenCount,
+			CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:      resp.UsageMetadata.TotalTokenCount,
+		}
+	}
+	return chunk, len(chunk.Choices) > 0 || chunk.Usage != nil
+}
+
+// geminiFinishReason maps Gemini finish reasons onto OpenAI's values.
+func geminiFinishReason(reason string) string {
+	switch reason {
+	case "", "FINISH_REASON_UNSPECIFIED":
+		return ""
+	case "STOP":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII":
+		return "content_filter"
+	default:
+		return strings.ToLower(reason)
+	}
 }
 
 // ListModels returns available models from Google Gemini.
@@ -64,15 +64,14 @@ func (c *LMStudioClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespo
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, errors.New(string(bodyBytes))
+		return nil, newProviderError(c.logger, "LM Studio API error", resp, bodyBytes)
 	}
 
-	var result ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return nil, err
 	}
-
-	return &result, nil
+	return decodeChatResponse(c.logger, "LM Studio API error", resp, respBody)
 }
 
 // Embeddings sends an embeddings request to LM Studio.
@@ -99,7 +98,7 @@ func (c *LMStudioClient) Embeddings(ctx context.Context, req *EmbeddingRequest)
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, errors.New(string(respBody))
+		return nil, newProviderError(c.logger, "LM Studio API error", resp, respBody)
 	}
 
 	var embResp EmbeddingResponse
@@ -164,12 +163,7 @@ func (c *LMStudioClient) TranscribeAudio(ctx context.Context, req *AudioTranscri
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "LM Studio audio transcription error",
-		}
+		return nil, newProviderError(c.logger, "LM Studio audio transcription error", resp, respBody)
 	}
 
 	if req.ResponseFormat == "text" || req.ResponseFormat == "srt" || req.ResponseFormat == "vtt" {
@@ -210,12 +204,7 @@ func (c *LMStudioClient) SynthesizeSpeech(ctx context.Context, req *SpeechReques
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "LM Studio speech synthesis error",
-		}
+		return nil, newProviderError(c.logger, "LM Studio speech synthesis error", resp, respBody)
 	}
 
 	audioData, err := io.ReadAll(resp.Body)
@@ -287,7 +276,7 @@ func (c *LMStudioClient) CheckHealth(ctx context.Context) (bool, time.Duration,
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return false, latency, errors.New("API returned HTTP " + resp.Status + ": " + string(respBody))
+		return false, latency, newProviderError(c.logger, "health check failed", resp, respBody)
 	}
 
 	return true, latency, nil
@@ -318,7 +307,7 @@ func (c *LMStudioClient) StreamChat(ctx context.Context, req *ChatRequest) (<-ch
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, errors.New(string(respBody))
+		return nil, newProviderError(c.logger, "LM Studio API error", resp, respBody)
 	}
 
 	chunks := make(chan StreamChunk)
@@ -47,9 +47,27 @@ func NewMistralClient(cfg *config.ProviderConfig, logger *zap.Logger) *MistralCl
 	}
 }
 
+// mistralChatBody is a chat request in Mistral's dialect.
+type mistralChatBody struct {
+	*ChatRequest
+	SafePrompt bool `json:"safe_prompt,omitempty"`
+}
+
+// mistralChatRequest drops the OpenAI "user" field, which Mistral rejects as
+// an unknown parameter, and adds Mistral's safe_prompt.
+func mistralChatRequest(req *ChatRequest) *mistralChatBody {
+	body := &mistralChatBody{ChatRequest: req, SafePrompt: req.SafePrompt}
+	if req.User != "" {
+		cp := *req
+		cp.User = ""
+		body.ChatRequest = &cp
+	}
+	return body
+}
+
 // Chat sends a chat completion request to Mistral.
 func (c *MistralClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
-	body, err := json.Marshal(req)
+	body, err := json.Marshal(mistralChatRequest(req))
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
@@ -70,15 +88,14 @@ func (c *MistralClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespon
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("mistral API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, newProviderError(c.logger, "Mistral API error", resp, respBody)
 	}
 
-	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
 	}
-
-	return &chatResp, nil
+	return decodeChatResponse(c.logger, "Mistral API error", resp, respBody)
 }
 
 // Embeddings sends an embeddings request to Mistral.
@@ -104,7 +121,7 @@ func (c *MistralClient) Embeddings(ctx context.Context, req *EmbeddingRequest) (
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("mistral embeddings error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, newProviderError(c.logger, "Mistral embeddings error", resp, respBody)
 	}
 
 	var embResp EmbeddingResponse
@@ -118,7 +135,7 @@ func (c *MistralClient) Embeddings(ctx context.Context, req *EmbeddingRequest) (
 // StreamChat sends a streaming chat request to Mistral.
 func (c *MistralClient) StreamChat(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
 	req.Stream = true
-	body, err := json.Marshal(req)
+	body, err := json.Marshal(mistralChatRequest(req))
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
@@ -139,7 +156,7 @@ func (c *MistralClient) StreamChat(ctx context.Context, req *ChatRequest) (<-cha
 	if resp.StatusCode != http.StatusOK {
 		defer func() { _ = resp.Body.Close() }()
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("mistral stream error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, newProviderError(c.logger, "Mistral stream error", resp, respBody)
 	}
 
 	ch := make(chan StreamChunk, 100)
@@ -166,6 +183,9 @@ func (c *MistralClient) StreamChat(ctx context.Context, req *ChatRequest) (<-cha
 			}
 			ch <- chunk
 		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			ch <- streamReadError(err)
+		}
 	}()
 
 	return ch, nil
@@ -70,15 +70,14 @@ func (c *OllamaClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, errors.New(string(bodyBytes))
+		return nil, newProviderError(c.logger, "Ollama API error", resp, bodyBytes)
 	}
 
-	var result ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return nil, err
 	}
-
-	return &result, nil
+	return decodeChatResponse(c.logger, "Ollama API error", resp, respBody)
 }
 
 // Embeddings sends an embeddings request to Ollama.
@@ -105,7 +104,7 @@ func (c *OllamaClient) Embeddings(ctx context.Context, req *EmbeddingRequest) (*
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, errors.New(string(respBody))
+		return nil, newProviderError(c.logger, "Ollama API error", resp, respBody)
 	}
 
 	var embResp EmbeddingResponse
@@ -210,7 +209,7 @@ func (c *OllamaClient) StreamChat(ctx context.Context, req *ChatRequest) (<-chan
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, errors.New(string(respBody))
+		return nil, newProviderError(c.logger, "Ollama API error", resp, respBody)
 	}
 
 	chunks := make(chan StreamChunk)
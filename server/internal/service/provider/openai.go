@@ -63,20 +63,14 @@ func (c *OpenAIClient) Chat(ctx context.Context, req *ChatRequest) (*ChatRespons
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "OpenAI API error",
-		}
+		return nil, newProviderError(c.logger, "OpenAI API error", resp, respBody)
 	}
 
-	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return nil, err
 	}
-
-	return &chatResp, nil
+	return decodeChatResponse(c.logger, "OpenAI API error", resp, respBody)
 }
 
 // Embeddings sends an embeddings request to OpenAI.
@@ -102,12 +96,7 @@ func (c *OpenAIClient) Embeddings(ctx context.Context, req *EmbeddingRequest) (*
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "OpenAI API error",
-		}
+		return nil, newProviderError(c.logger, "OpenAI API error", resp, respBody)
 	}
 
 	var embResp EmbeddingResponse
@@ -147,12 +136,7 @@ func (c *OpenAIClient) StreamChat(ctx context.Context, req *ChatRequest) (<-chan
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "OpenAI API error",
-		}
+		return nil, newProviderError(c.logger, "OpenAI API error", resp, respBody)
 	}
 
 	chunks := make(chan StreamChunk)
@@ -210,12 +194,40 @@ func (c *OpenAIClient) CheckHealth(ctx context.Context) (bool, time.Duration, er
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return false, latency, errors.New("API returned HTTP " + resp.Status + ": " + string(respBody))
+		return false, latency, newProviderError(c.logger, "health check failed", resp, respBody)
 	}
 
 	return true, latency, nil
 }
 
+// CheckHealthDeep verifies the key can generate by sending a 1-token
+// completion. Listing models succeeds even when the account is out of quota,
+// so this catches keys that CheckHealth reports as healthy. When model is
+// empty the first model listed by the upstream is used.
+func (c *OpenAIClient) CheckHealthDeep(ctx context.Context, model string) (bool, time.Duration, error) {
+	start := time.Now()
+
+	if model == "" {
+		available, err := c.ListModels(ctx)
+		if err != nil {
+			return false, time.Since(start), err
+		}
+		if len(available) == 0 {
+			return false, time.Since(start), errors.New("no models available for deep health check")
+		}
+		model = available[0].ID
+	}
+
+	_, err := c.Chat(ctx, &ChatRequest{
+		Model:     model,
+		Messages:  []Message{{Role: "user", Content: StringContent("Hi")}},
+		MaxTokens: 1,
+	})
+	latency := time.Since(start)
+
+	return err == nil, latency, err
+}
+
 // GenerateImage sends an image generation request to OpenAI.
 func (c *OpenAIClient) GenerateImage(ctx context.Context, req *ImageGenerationRequest) (*ImageGenerationResponse, error) {
 	body, err := json.Marshal(req)
@@ -239,12 +251,7 @@ func (c *OpenAIClient) GenerateImage(ctx context.Context, req *ImageGenerationRe
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "OpenAI API error",
-		}
+		return nil, newProviderError(c.logger, "OpenAI API error", resp, respBody)
 	}
 
 	var imgResp ImageGenerationResponse
@@ -305,12 +312,7 @@ func (c *OpenAIClient) TranscribeAudio(ctx context.Context, req *AudioTranscript
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "OpenAI API error",
-		}
+		return nil, newProviderError(c.logger, "OpenAI API error", resp, respBody)
 	}
 
 	// For specific response formats, OpenAI returns raw text or JSON
@@ -351,12 +353,7 @@ func (c *OpenAIClient) SynthesizeSpeech(ctx context.Context, req *SpeechRequest)
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			StatusCode: resp.StatusCode,
-			Headers:    resp.Header,
-			Body:       respBody,
-			Message:    "OpenAI API error",
-		}
+		return nil, newProviderError(c.logger, "OpenAI API error", resp, respBody)
 	}
 
 	audioData, err := io.ReadAll(resp.Body)
@@ -1,8 +1,23 @@
 package provider
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+
+	"llm-router-platform/internal/config"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -206,6 +221,63 @@ func TestRegistryTTSCapability(t *testing.T) {
 	assert.Contains(t, matrix["test-tts"], CapChat)
 }
 
+func TestRegistryUnregister(t *testing.T) {
+	registry := NewRegistry(zap.NewNop())
+	registry.Register("openai", nil)
+
+	assert.True(t, registry.Unregister("openai"))
+	assert.False(t, registry.Unregister("openai"))
+	_, ok := registry.Get("openai")
+	assert.False(t, ok)
+	assert.Empty(t, registry.List())
+}
+
+// TestRegistryConcurrentAccess exercises the registry from many goroutines;
+// run with -race to catch unsynchronized map access.
+func TestRegistryConcurrentAccess(t *testing.T) {
+	registry := NewRegistry(zap.NewNop())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("provider-%d", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				registry.RegisterWithCapabilities(name, nil, CapChat)
+				registry.Unregister(name)
+				registry.Register(name, nil)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				registry.Get(name)
+				registry.GetInfo(name)
+				registry.HasCapability(name, CapChat)
+				registry.List()
+				registry.CapabilityMatrix()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, registry.List(), 8)
+}
+
+func TestBuiltinCapabilities(t *testing.T) {
+	caps, known := BuiltinCapabilities("openai")
+	assert.True(t, known)
+	assert.True(t, caps[CapStream])
+	assert.True(t, caps[CapEmbeddings])
+
+	caps, known = BuiltinCapabilities("anthropic")
+	assert.True(t, known)
+	assert.False(t, caps[CapEmbeddings])
+
+	caps, known = BuiltinCapabilities("some-gateway")
+	assert.False(t, known, "OpenAI-compatible fallback is not assumed to support anything")
+	assert.Empty(t, caps)
+}
+
 func TestFlexibleContentVideoTransparency(t *testing.T) {
 	// Multimodal content with video_url part (used by Gemini 2.x, GPT-4o)
 	rawJSON := `[{"type":"text","text":"Describe this video"},{"type":"video_url","video_url":{"url":"https://example.com/video.mp4"}}]`
@@ -284,3 +356,672 @@ func TestFlexibleContentNullPreserved(t *testing.T) {
 	assert.Equal(t, "null", string(marshaled))
 }
 
+
+func TestCheckHealthWithMode_DeepSendsCompletion(t *testing.T) {
+	var chatCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/chat/completions":
+			chatCalls++
+			var req ChatRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "gpt-4o-mini", req.Model)
+			assert.Equal(t, 1, req.MaxTokens)
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"insufficient_quota"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o-mini"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewOpenAIClient(&config.ProviderConfig{APIKey: "sk-test", BaseURL: srv.URL}, zap.NewNop())
+
+	healthy, _, mode, err := CheckHealthWithMode(context.Background(), client, false, "")
+	require.NoError(t, err)
+	assert.True(t, healthy, "shallow check only lists models")
+	assert.Equal(t, HealthCheckShallow, mode)
+
+	healthy, _, mode, err = CheckHealthWithMode(context.Background(), client, true, "gpt-4o-mini")
+	require.Error(t, err)
+	assert.False(t, healthy, "deep check must surface quota exhaustion")
+	assert.Equal(t, HealthCheckDeep, mode)
+	assert.Equal(t, 1, chatCalls)
+}
+
+func TestCheckHealthWithMode_FallsBackWhenUnsupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	client := NewRetryClient(NewMistralClient(&config.ProviderConfig{BaseURL: srv.URL}, zap.NewNop()), DefaultRetryConfig(), zap.NewNop())
+
+	_, _, mode, _ := CheckHealthWithMode(context.Background(), client, true, "")
+	assert.Equal(t, HealthCheckShallow, mode)
+}
+
+func TestAnthropicChat_MovesSystemToTopLevel(t *testing.T) {
+	var body struct {
+		System   string `json:"system"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"id":"msg_1","model":"claude-3-haiku","content":[{"type":"text","text":"hi"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: srv.URL}, zap.NewNop())
+	_, err := client.Chat(context.Background(), &ChatRequest{
+		Model:     "claude-3-haiku",
+		MaxTokens: 16,
+		Messages: []Message{
+			{Role: "system", Content: StringContent("Be terse.")},
+			{Role: "user", Content: StringContent("Hello")},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Be terse.", body.System)
+	require.Len(t, body.Messages, 1)
+	assert.Equal(t, "user", body.Messages[0].Role)
+	assert.Equal(t, "Hello", body.Messages[0].Content)
+}
+
+func TestSplitAnthropicSystem_JoinsMultiple(t *testing.T) {
+	system, turns := splitAnthropicSystem([]Message{
+		{Role: "system", Content: StringContent("A")},
+		{Role: "user", Content: StringContent("q")},
+		{Role: "system", Content: StringContent("B")},
+		{Role: "assistant", Content: StringContent("a")},
+	})
+
+	assert.Equal(t, "A\n\nB", system)
+	require.Len(t, turns, 2)
+	assert.Equal(t, "user", turns[0].Role)
+	assert.Equal(t, "assistant", turns[1].Role)
+}
+
+func TestAnthropicChat_PromptCaching(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"id":"msg_1","model":"claude-3-haiku","content":[{"type":"text","text":"hi"}],
+			"usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":200,"cache_read_input_tokens":1000}}`))
+	}))
+	defer srv.Close()
+
+	client := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: srv.URL, PromptCaching: true}, zap.NewNop())
+	resp, err := client.Chat(context.Background(), &ChatRequest{
+		Model:     "claude-3-haiku",
+		MaxTokens: 16,
+		Messages: []Message{
+			{Role: "system", Content: StringContent("Long agent instructions.")},
+			{Role: "user", Content: StringContent("Hello")},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"type":          "text",
+		"text":          "Long agent instructions.",
+		"cache_control": map[string]interface{}{"type": "ephemeral"},
+	}}, body["system"])
+	assert.Equal(t, Usage{
+		PromptTokens:        1210,
+		CompletionTokens:    5,
+		TotalTokens:         1215,
+		CacheCreationTokens: 200,
+		CacheReadTokens:     1000,
+	}, resp.Usage)
+}
+
+func TestStopSequences_AcceptsStringOrArray(t *testing.T) {
+	var req ChatRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"model":"m","messages":[],"stop":"END"}`), &req))
+	assert.Equal(t, StopSequences{"END"}, req.Stop)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"model":"m","messages":[],"stop":["a","b"]}`), &req))
+	assert.Equal(t, StopSequences{"a", "b"}, req.Stop)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"stop":42}`), &req))
+}
+
+func TestAnthropicChat_MapsStopAndTopP(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"id":"msg_1","model":"claude-3-haiku","content":[{"type":"text","text":"hi"}]}`))
+	}))
+	defer srv.Close()
+
+	topP := 0.9
+	client := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: srv.URL}, zap.NewNop())
+	_, err := client.Chat(context.Background(), &ChatRequest{
+		Model:     "claude-3-haiku",
+		MaxTokens: 16,
+		TopP:      &topP,
+		Stop:      StopSequences{"\n\nHuman:"},
+		Messages:  []Message{{Role: "user", Content: StringContent("Hello")}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.9, body["top_p"])
+	assert.Equal(t, []interface{}{"\n\nHuman:"}, body["stop_sequences"])
+	assert.NotContains(t, body, "stop")
+}
+
+func TestAnthropicChat_TranslatesTools(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"id":"msg_1","model":"claude-3-haiku","stop_reason":"tool_use","content":[
+			{"type":"tool_use","id":"toolu_2","name":"get_weather","input":{"city":"Paris"}}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: srv.URL}, zap.NewNop())
+	resp, err := client.Chat(context.Background(), &ChatRequest{
+		Model:      "claude-3-haiku",
+		MaxTokens:  64,
+		Tools:      json.RawMessage(`[{"type":"function","function":{"name":"get_weather","description":"Weather by city","parameters":{"type":"object","properties":{"city":{"type":"string"}}}}}]`),
+		ToolChoice: json.RawMessage(`"required"`),
+		Messages: []Message{
+			{Role: "user", Content: StringContent("Weather in Oslo and Paris?")},
+			{Role: "assistant", Content: FlexibleContent{Raw: json.RawMessage("null")},
+				ToolCalls: json.RawMessage(`[{"id":"toolu_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Oslo\"}"}}]`)},
+			{Role: "tool", ToolCallID: "toolu_1", Content: StringContent("-3C")},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"name":         "get_weather",
+		"description":  "Weather by city",
+		"input_schema": map[string]interface{}{"type": "object", "properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}}},
+	}}, body["tools"])
+	assert.Equal(t, map[string]interface{}{"type": "any"}, body["tool_choice"])
+	msgs := body["messages"].([]interface{})
+	require.Len(t, msgs, 3)
+	assert.Equal(t, map[string]interface{}{"role": "assistant", "content": []interface{}{map[string]interface{}{
+		"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": map[string]interface{}{"city": "Oslo"},
+	}}}, msgs[1])
+	assert.Equal(t, map[string]interface{}{"role": "user", "content": []interface{}{map[string]interface{}{
+		"type": "tool_result", "tool_use_id": "toolu_1", "content": "-3C",
+	}}}, msgs[2])
+
+	choice := resp.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	assert.Equal(t, "null", string(choice.Message.Content.Raw))
+	calls, err := ParseToolCalls(choice.Message.ToolCalls)
+	require.NoError(t, err)
+	assert.Equal(t, []ToolCall{{ID: "toolu_2", Type: "function",
+		Function: ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}}}, calls)
+}
+
+func TestAnthropicStreamChat_ToolCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`data: {"type":"message_start","message":{"usage":{"input_tokens":12}}}
+
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Checking."}}
+
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}
+
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}
+
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Oslo\"}"}}
+
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":20}}
+
+data: {"type":"message_stop"}
+
+`))
+	}))
+	defer srv.Close()
+
+	client := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: srv.URL}, zap.NewNop())
+	chunks, err := client.StreamChat(context.Background(), &ChatRequest{
+		Model:    "claude-3-haiku",
+		Tools:    json.RawMessage(`[{"type":"function","function":{"name":"get_weather"}}]`),
+		Messages: []Message{{Role: "user", Content: StringContent("Weather in Oslo?")}},
+	})
+	require.NoError(t, err)
+
+	var text, args, finish string
+	var first toolCallDelta
+	for chunk := range chunks {
+		require.NoError(t, chunk.Error)
+		for _, c := range chunk.Choices {
+			text += c.Delta.Content
+			if c.FinishReason != "" {
+				finish = c.FinishReason
+			}
+			if len(c.Delta.ToolCalls) == 0 {
+				continue
+			}
+			var deltas []toolCallDelta
+			require.NoError(t, json.Unmarshal(c.Delta.ToolCalls, &deltas))
+			require.Len(t, deltas, 1)
+			assert.Equal(t, 0, deltas[0].Index)
+			if deltas[0].ID != "" {
+				first = deltas[0]
+			}
+			args += deltas[0].Function.Arguments
+		}
+	}
+	assert.Equal(t, "Checking.", text)
+	assert.Equal(t, "toolu_1", first.ID)
+	assert.Equal(t, "get_weather", first.Function.Name)
+	assert.Equal(t, `{"city":"Oslo"}`, args)
+	assert.Equal(t, "tool_calls", finish)
+}
+
+func TestAnthropicStreamChat_ReportsUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Usage
+	}{
+		{"with output", `{"output_tokens":20}`, Usage{PromptTokens: 42, CompletionTokens: 20, TotalTokens: 62, CacheReadTokens: 30}},
+		// A stream stopped before any output still bills its prompt.
+		{"no output", `{"output_tokens":0}`, Usage{PromptTokens: 42, TotalTokens: 42, CacheReadTokens: 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`data: {"type":"message_start","message":{"usage":{"input_tokens":12,"cache_read_input_tokens":30}}}
+
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":` + tt.output + `}
+
+data: {"type":"message_stop"}
+
+`))
+			}))
+			defer srv.Close()
+
+			client := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: srv.URL}, zap.NewNop())
+			chunks, err := client.StreamChat(context.Background(), &ChatRequest{
+				Model:    "claude-3-haiku",
+				Messages: []Message{{Role: "user", Content: StringContent("Hi")}},
+			})
+			require.NoError(t, err)
+
+			var usage *Usage
+			for chunk := range chunks {
+				require.NoError(t, chunk.Error)
+				if chunk.Usage != nil {
+					usage = chunk.Usage
+				}
+			}
+			require.NotNil(t, usage, "the stream must carry usage for billing")
+			assert.Equal(t, tt.want, *usage)
+		})
+	}
+}
+
+func TestToolArguments_AcceptsStringOrObject(t *testing.T) {
+	calls, err := ParseToolCalls(json.RawMessage(`[
+		{"id":"a","type":"function","function":{"name":"f","arguments":"{\"x\":1}"}},
+		{"id":"b","type":"function","function":{"name":"f","arguments":{"x":1}}}]`))
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, calls[0].Function.Arguments, calls[1].Function.Arguments)
+
+	out, err := json.Marshal(calls[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"b","type":"function","function":{"name":"f","arguments":"{\"x\":1}"}}`, string(out))
+}
+
+func TestChatRequestUserPassthrough(t *testing.T) {
+	var anthropicBody, mistralBody map[string]interface{}
+	anthropicSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&anthropicBody))
+		_, _ = w.Write([]byte(`{"id":"msg_1","model":"claude-3-haiku","content":[{"type":"text","text":"hi"}]}`))
+	}))
+	defer anthropicSrv.Close()
+	mistralSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&mistralBody))
+		_, _ = w.Write([]byte(`{"id":"c1","model":"mistral-small","choices":[{"index":0,"message":{"role":"assistant","content":"hi"}}]}`))
+	}))
+	defer mistralSrv.Close()
+
+	req := &ChatRequest{
+		Model:     "claude-3-haiku",
+		MaxTokens: 16,
+		User:      "tenant-hash",
+		Messages:  []Message{{Role: "user", Content: StringContent("Hello")}},
+	}
+	_, err := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: anthropicSrv.URL}, zap.NewNop()).Chat(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"user_id": "tenant-hash"}, anthropicBody["metadata"])
+	assert.NotContains(t, anthropicBody, "user")
+
+	_, err = NewMistralClient(&config.ProviderConfig{APIKey: "k", BaseURL: mistralSrv.URL}, zap.NewNop()).Chat(context.Background(), req)
+	require.NoError(t, err)
+	assert.NotContains(t, mistralBody, "user", "Mistral rejects unknown parameters")
+	assert.NotContains(t, mistralBody, "safe_prompt")
+	assert.Equal(t, "tenant-hash", req.User, "caller's request is not modified")
+
+	req.SafePrompt = true
+	_, err = NewMistralClient(&config.ProviderConfig{APIKey: "k", BaseURL: mistralSrv.URL}, zap.NewNop()).Chat(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, true, mistralBody["safe_prompt"])
+	assert.Equal(t, req.Model, mistralBody["model"], "embedded request fields are flattened")
+
+	out, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "safe_prompt", "OpenAI-style bodies never carry safe_prompt")
+}
+
+func TestBuildGeminiGenerationConfig(t *testing.T) {
+	assert.Nil(t, buildGeminiGenerationConfig(&ChatRequest{}))
+
+	topP := 0.5
+	cfg := buildGeminiGenerationConfig(&ChatRequest{TopP: &topP, Stop: StopSequences{"x"}, N: 2})
+	require.NotNil(t, cfg)
+	assert.Equal(t, &topP, cfg.TopP)
+	assert.Equal(t, []string{"x"}, cfg.StopSequences)
+	assert.Equal(t, 2, cfg.CandidateCount)
+}
+
+func TestSummarizeProviderError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"openai", 429, `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`, "provider returned 429: rate_limit_exceeded - Rate limit reached"},
+		{"anthropic", 529, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, "provider returned 529: overloaded_error - Overloaded"},
+		{"gemini", 400, `{"error":{"code":400,"message":"API key not valid","status":"INVALID_ARGUMENT"}}`, "provider returned 400: INVALID_ARGUMENT - API key not valid"},
+		{"ollama", 404, `{"error":"model 'llama9' not found"}`, "provider returned 404: model 'llama9' not found"},
+		{"mistral", 401, `{"object":"error","message":"Unauthorized","type":"invalid_request_error"}`, "provider returned 401: invalid_request_error - Unauthorized"},
+		{"plain text", 502, "<html>\n  Bad Gateway\n</html>", "provider returned 502: <html> Bad Gateway </html>"},
+		{"empty", 503, "", "provider returned 503"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, summarizeProviderError(tt.status, []byte(tt.body)))
+		})
+	}
+}
+
+func TestProviderError_TruncatesLargeBodies(t *testing.T) {
+	msg := strings.Repeat("echoed prompt ", 200)
+	body, _ := json.Marshal(map[string]interface{}{"error": map[string]string{"message": msg}})
+	err := &ProviderError{StatusCode: 400, Body: body, Message: "OpenAI API error"}
+
+	assert.True(t, strings.HasPrefix(err.Error(), "OpenAI API error: provider returned 400: echoed prompt"))
+	assert.LessOrEqual(t, len(err.Error()), 400)
+	assert.True(t, strings.HasSuffix(err.Error(), "..."))
+}
+
+// Captured from streamGenerateContent?alt=sse (trimmed).
+const geminiSSEStream = `data: {"candidates": [{"content": {"parts": [{"text": "Hello"}],"role": "model"},"index": 0}],"usageMetadata": {"promptTokenCount": 4,"totalTokenCount": 4},"modelVersion": "gemini-1.5-flash"}
+
+data: {"candidates": [{"content": {"parts": [{"text": ", world"}],"role": "model"},"index": 0}],"usageMetadata": {"promptTokenCount": 4,"totalTokenCount": 4},"modelVersion": "gemini-1.5-flash"}
+
+data: {"candidates": [{"content": {"parts": [{"text": "!"}],"role": "model"},"finishReason": "STOP","index": 0}],"usageMetadata": {"promptTokenCount": 4,"candidatesTokenCount": 3,"totalTokenCount": 7},"modelVersion": "gemini-1.5-flash"}
+
+`
+
+// Captured from streamGenerateContent without alt=sse (trimmed).
+const geminiArrayStream = `[{
+  "candidates": [{"content": {"parts": [{"text": "Hello"}],"role": "model"},"index": 0}],
+  "usageMetadata": {"promptTokenCount": 4,"totalTokenCount": 4}
+}
+,
+{
+  "candidates": [{"content": {"parts": [{"text": ", world"}, {"text": "!"}],"role": "model"},"finishReason": "MAX_TOKENS","index": 0}],
+  "usageMetadata": {"promptTokenCount": 4,"candidatesTokenCount": 3,"totalTokenCount": 7}
+}
+]`
+
+func collectGeminiStream(t *testing.T, raw string) (string, []string, *Usage, bool) {
+	t.Helper()
+	chunks := make(chan StreamChunk)
+	go processGeminiStream(context.Background(), io.NopCloser(strings.NewReader(raw)), "gemini-1.5-flash", chunks, zap.NewNop())
+
+	var text strings.Builder
+	var finishes []string
+	var usage *Usage
+	var done bool
+	for chunk := range chunks {
+		if chunk.Done {
+			done = true
+			continue
+		}
+		assert.Equal(t, "gemini-1.5-flash", chunk.Model)
+		for _, c := range chunk.Choices {
+			text.WriteString(c.Delta.Content)
+			if c.FinishReason != "" {
+				finishes = append(finishes, c.FinishReason)
+			}
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+	return text.String(), finishes, usage, done
+}
+
+func TestProcessGeminiStream_SSE(t *testing.T) {
+	text, finishes, usage, done := collectGeminiStream(t, geminiSSEStream)
+
+	assert.Equal(t, "Hello, world!", text)
+	assert.Equal(t, []string{"stop"}, finishes)
+	require.NotNil(t, usage)
+	assert.Equal(t, Usage{PromptTokens: 4, CompletionTokens: 3, TotalTokens: 7}, *usage)
+	assert.True(t, done)
+}
+
+func TestProcessGeminiStream_JSONArray(t *testing.T) {
+	text, finishes, usage, done := collectGeminiStream(t, geminiArrayStream)
+
+	assert.Equal(t, "Hello, world!", text)
+	assert.Equal(t, []string{"length"}, finishes)
+	require.NotNil(t, usage)
+	assert.Equal(t, 7, usage.TotalTokens)
+	assert.True(t, done)
+}
+
+func TestProcessSSEStream_ReadErrorIsReported(t *testing.T) {
+	body := io.MultiReader(
+		strings.NewReader("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n"),
+		iotest.ErrReader(errors.New("connection reset by peer")),
+	)
+	chunks := make(chan StreamChunk)
+	go processSSEStream(context.Background(), io.NopCloser(body), chunks, zap.NewNop())
+
+	var got []StreamChunk
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+	require.Len(t, got, 2)
+	assert.Equal(t, "Hel", got[0].Choices[0].Delta.Content)
+	assert.False(t, got[1].Done, "a truncated stream must not look complete")
+	assert.ErrorIs(t, got[1].Error, ErrStreamInterrupted)
+	assert.ErrorContains(t, got[1].Error, "connection reset by peer")
+}
+
+func TestGoogleStreamChat_UsesSSEEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1beta/models/gemini-1.5-flash:streamGenerateContent", r.URL.Path)
+		assert.Equal(t, "sse", r.URL.Query().Get("alt"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(geminiSSEStream))
+	}))
+	defer srv.Close()
+
+	client := NewGoogleClient(&config.ProviderConfig{APIKey: "test", BaseURL: srv.URL}, zap.NewNop())
+	chunks, err := client.StreamChat(context.Background(), &ChatRequest{
+		Model:    "gemini-1.5-flash",
+		Messages: []Message{{Role: "user", Content: StringContent("hi")}},
+	})
+	require.NoError(t, err)
+
+	var text strings.Builder
+	for chunk := range chunks {
+		for _, c := range chunk.Choices {
+			text.WriteString(c.Delta.Content)
+		}
+	}
+	assert.Equal(t, "Hello, world!", text.String())
+}
+
+func TestOpenAIChat_ForwardsResponseFormat(t *testing.T) {
+	var body map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"{}"},"finish_reason":"stop"}],"usage":{"prompt_tokens":7,"completion_tokens":2,"total_tokens":9}}`))
+	}))
+	defer srv.Close()
+
+	schema := `{"name":"answer","strict":true,"schema":{"type":"object","properties":{"ok":{"type":"boolean"}}}}`
+	client := NewOpenAIClient(&config.ProviderConfig{APIKey: "sk-test", BaseURL: srv.URL}, zap.NewNop())
+	resp, err := client.Chat(context.Background(), &ChatRequest{
+		Model:          "gpt-4o",
+		Messages:       []Message{{Role: "user", Content: StringContent("Reply in JSON")}},
+		ResponseFormat: &ResponseFormat{Type: "json_schema", JSONSchema: json.RawMessage(schema)},
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"type":"json_schema","json_schema":`+schema+`}`, string(body["response_format"]))
+	assert.Equal(t, 9, resp.Usage.TotalTokens, "usage is still reported")
+}
+
+func TestChat_RejectsErrorBodyWithStatusOK(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	cfg := &config.ProviderConfig{APIKey: "k", BaseURL: srv.URL}
+	clients := map[string]Client{
+		"openai":   NewOpenAIClient(cfg, zap.NewNop()),
+		"ollama":   NewOllamaClient(cfg, zap.NewNop()),
+		"lmstudio": NewLMStudioClient(cfg, zap.NewNop()),
+		"mistral":  NewMistralClient(cfg, zap.NewNop()),
+	}
+	req := &ChatRequest{Model: "m", Messages: []Message{{Role: "user", Content: StringContent("Hello")}}}
+
+	for name, client := range clients {
+		body = `{"error":{"message":"model is overloaded","type":"server_error"}}`
+		_, err := client.Chat(context.Background(), req)
+		var provErr *ProviderError
+		require.ErrorAs(t, err, &provErr, name)
+		assert.Contains(t, err.Error(), "server_error - model is overloaded", name)
+
+		body = `{"error":"model 'm' not found, try pulling it first"}`
+		_, err = client.Chat(context.Background(), req)
+		require.ErrorAs(t, err, &provErr, name)
+		assert.Contains(t, err.Error(), "not found", name)
+
+		body = `{"id":"c1","model":"m","choices":[]}`
+		_, err = client.Chat(context.Background(), req)
+		assert.ErrorIs(t, err, ErrEmptyResponse, name)
+
+		body = `{"id":"c1","model":"m","error":null,"choices":[{"index":0,"message":{"role":"assistant","content":"hi"}}]}`
+		resp, err := client.Chat(context.Background(), req)
+		require.NoError(t, err, name)
+		assert.Equal(t, "hi", resp.Choices[0].Message.Content.Text, name)
+	}
+}
+
+func TestAnthropicChat_RejectsStructuredResponseFormat(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	defer srv.Close()
+
+	client := NewAnthropicClient(&config.ProviderConfig{APIKey: "sk-ant", BaseURL: srv.URL}, zap.NewNop())
+	_, err := client.Chat(context.Background(), &ChatRequest{
+		Model:          "claude-3-haiku",
+		Messages:       []Message{{Role: "user", Content: StringContent("Hello")}},
+		ResponseFormat: &ResponseFormat{Type: "json_object"},
+	})
+
+	assert.ErrorIs(t, err, ErrResponseFormatUnsupported)
+	assert.False(t, called, "request must not reach the provider")
+}
+
+func TestCheckResponseFormat(t *testing.T) {
+	jsonObject := &ResponseFormat{Type: "json_object"}
+	jsonSchema := &ResponseFormat{Type: "json_schema", JSONSchema: json.RawMessage(`{"schema":{}}`)}
+
+	assert.NoError(t, CheckResponseFormat("anthropic", nil))
+	assert.NoError(t, CheckResponseFormat("anthropic", &ResponseFormat{Type: "text"}))
+	assert.ErrorIs(t, CheckResponseFormat("anthropic", jsonObject), ErrResponseFormatUnsupported)
+	assert.NoError(t, CheckResponseFormat("deepseek", jsonObject))
+	assert.ErrorIs(t, CheckResponseFormat("deepseek", jsonSchema), ErrResponseFormatUnsupported)
+	assert.NoError(t, CheckResponseFormat("openai", jsonSchema))
+	assert.NoError(t, CheckResponseFormat("google", jsonSchema))
+}
+
+func TestBuildGeminiGenerationConfig_ResponseFormat(t *testing.T) {
+	cfg := buildGeminiGenerationConfig(&ChatRequest{ResponseFormat: &ResponseFormat{Type: "json_object"}})
+	require.NotNil(t, cfg)
+	assert.Equal(t, "application/json", cfg.ResponseMimeType)
+	assert.Nil(t, cfg.ResponseJSONSchema)
+
+	cfg = buildGeminiGenerationConfig(&ChatRequest{ResponseFormat: &ResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: json.RawMessage(`{"name":"answer","schema":{"type":"object"}}`),
+	}})
+	require.NotNil(t, cfg)
+	assert.JSONEq(t, `{"type":"object"}`, string(cfg.ResponseJSONSchema))
+
+	assert.Nil(t, buildGeminiGenerationConfig(&ChatRequest{ResponseFormat: &ResponseFormat{Type: "text"}}))
+}
+
+func TestTLSOptions(t *testing.T) {
+	cfg, err := TLSOptions{}.Config()
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "defaults leave the transport untouched")
+
+	_, err = TLSOptions{MinVersion: "1.1"}.Config()
+	assert.Error(t, err)
+	cfg, err = TLSOptions{MinVersion: "1.3"}.Config()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.MinVersion)
+	assert.False(t, cfg.InsecureSkipVerify)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+	get := func(opts TLSOptions) error {
+		cfg, err := opts.Config()
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.Error(t, get(TLSOptions{MinVersion: "1.2"}), "self-signed certificates are rejected by default")
+	assert.NoError(t, get(TLSOptions{InsecureSkipVerify: true}))
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(bundle, certPEM, 0o600))
+	assert.NoError(t, get(TLSOptions{CABundlePath: bundle}), "a private CA bundle is trusted")
+
+	require.NoError(t, os.WriteFile(bundle, []byte("not a certificate"), 0o600))
+	_, err = TLSOptions{CABundlePath: bundle}.Config()
+	assert.Error(t, err)
+	_, err = TLSOptions{CABundlePath: filepath.Join(t.TempDir(), "missing.pem")}.Config()
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"sync"
+
 	"go.uber.org/zap"
 )
 
@@ -23,8 +25,11 @@ type ProviderInfo struct {
 	Capabilities map[Capability]bool
 }
 
-// Registry holds all registered provider clients and their capabilities.
+// Registry holds all registered provider clients and their capabilities. It
+// is safe for concurrent use, so providers can be registered and removed while
+// requests are being served.
 type Registry struct {
+	mu        sync.RWMutex
 	providers map[string]*ProviderInfo
 	logger    *zap.Logger
 }
@@ -39,6 +44,8 @@ func NewRegistry(logger *zap.Logger) *Registry {
 
 // Register adds a provider client to the registry with default capabilities.
 func (r *Registry) Register(name string, client Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.providers[name] = &ProviderInfo{
 		Client: client,
 		Capabilities: map[Capability]bool{
@@ -54,14 +61,27 @@ func (r *Registry) RegisterWithCapabilities(name string, client Client, caps ...
 	for _, c := range caps {
 		capMap[c] = true
 	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.providers[name] = &ProviderInfo{
 		Client:       client,
 		Capabilities: capMap,
 	}
 }
 
+// Unregister removes a provider client, reporting whether it was registered.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.providers[name]
+	delete(r.providers, name)
+	return ok
+}
+
 // Get retrieves a provider client by name.
 func (r *Registry) Get(name string) (Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	info, ok := r.providers[name]
 	if !ok {
 		return nil, false
@@ -71,12 +91,16 @@ func (r *Registry) Get(name string) (Client, bool) {
 
 // GetInfo retrieves full provider info (client + capabilities).
 func (r *Registry) GetInfo(name string) (*ProviderInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	info, ok := r.providers[name]
 	return info, ok
 }
 
 // HasCapability checks if a provider supports a specific capability.
 func (r *Registry) HasCapability(name string, cap Capability) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	info, ok := r.providers[name]
 	if !ok {
 		return false
@@ -86,6 +110,8 @@ func (r *Registry) HasCapability(name string, cap Capability) bool {
 
 // List returns all registered provider names.
 func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	names := make([]string, 0, len(r.providers))
 	for name := range r.providers {
 		names = append(names, name)
@@ -95,6 +121,8 @@ func (r *Registry) List() []string {
 
 // CapabilityMatrix returns a map of provider → supported capabilities.
 func (r *Registry) CapabilityMatrix() map[string][]Capability {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	matrix := make(map[string][]Capability, len(r.providers))
 	for name, info := range r.providers {
 		caps := make([]Capability, 0)
@@ -107,3 +135,28 @@ func (r *Registry) CapabilityMatrix() map[string][]Capability {
 	}
 	return matrix
 }
+
+// builtinCapabilities lists the chat, streaming and embedding support of each
+// provider type NewClientByName knows by name. Keep in sync with that switch.
+var builtinCapabilities = map[string][]Capability{
+	"openai":    {CapChat, CapStream, CapEmbeddings},
+	"anthropic": {CapChat, CapStream},
+	"google":    {CapChat, CapStream, CapEmbeddings},
+	"ollama":    {CapChat, CapStream, CapEmbeddings},
+	"lmstudio":  {CapChat, CapStream, CapEmbeddings},
+	"deepseek":  {CapChat, CapStream},
+	"mistral":   {CapChat, CapStream, CapEmbeddings},
+	"vllm":      {CapChat, CapStream, CapEmbeddings},
+}
+
+// BuiltinCapabilities returns the capabilities of a provider type. known is
+// false for the openai-compatible type, whose support depends on the upstream
+// and cannot be assumed.
+func BuiltinCapabilities(name string) (caps map[Capability]bool, known bool) {
+	list, known := builtinCapabilities[name]
+	caps = make(map[Capability]bool, len(list))
+	for _, c := range list {
+		caps[c] = true
+	}
+	return caps, known
+}
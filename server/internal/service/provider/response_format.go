@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrResponseFormatUnsupported is returned when a provider cannot honor the
+// requested structured response_format.
+var ErrResponseFormatUnsupported = errors.New("response_format not supported by provider")
+
+// ResponseFormat is OpenAI's response_format parameter. Type is "text",
+// "json_object" or "json_schema"; JSONSchema holds the json_schema object
+// ({"name", "schema", "strict"}) verbatim.
+type ResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema json.RawMessage `json:"json_schema,omitempty"`
+}
+
+// Structured reports whether f asks for JSON output.
+func (f *ResponseFormat) Structured() bool {
+	return f != nil && f.Type != "" && f.Type != "text"
+}
+
+// Validate checks the format type and that json_schema carries a schema object.
+func (f *ResponseFormat) Validate() error {
+	if f == nil {
+		return nil
+	}
+	switch f.Type {
+	case "text", "json_object":
+		return nil
+	case "json_schema":
+		var js struct {
+			Schema json.RawMessage `json:"schema"`
+		}
+		if len(f.JSONSchema) == 0 || json.Unmarshal(f.JSONSchema, &js) != nil {
+			return errors.New("response_format.json_schema must be an object when type is json_schema")
+		}
+		return nil
+	default:
+		return fmt.Errorf("response_format.type must be one of text, json_object, json_schema; got %q", f.Type)
+	}
+}
+
+// schema returns the JSON schema of a json_schema format, or nil.
+func (f *ResponseFormat) schema() json.RawMessage {
+	if f == nil || f.Type != "json_schema" {
+		return nil
+	}
+	var js struct {
+		Schema json.RawMessage `json:"schema"`
+	}
+	if json.Unmarshal(f.JSONSchema, &js) != nil {
+		return nil
+	}
+	return js.Schema
+}
+
+// unsupportedResponseFormats lists, by provider type, the structured formats
+// its client cannot honor. OpenAI-compatible clients forward response_format
+// verbatim and Google translates it, so only the exceptions are listed.
+var unsupportedResponseFormats = map[string][]string{
+	"anthropic": {"json_object", "json_schema"},
+	"deepseek":  {"json_schema"},
+}
+
+// CheckResponseFormat returns ErrResponseFormatUnsupported when the client for
+// providerType (see models.Provider.ClientType) cannot honor f.
+func CheckResponseFormat(providerType string, f *ResponseFormat) error {
+	if !f.Structured() {
+		return nil
+	}
+	if slices.Contains(unsupportedResponseFormats[providerType], f.Type) {
+		return fmt.Errorf("%w: %s does not support response_format type %q", ErrResponseFormatUnsupported, providerType, f.Type)
+	}
+	return nil
+}
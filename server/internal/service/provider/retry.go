@@ -143,6 +143,23 @@ func (r *RetryClient) CheckHealth(ctx context.Context) (bool, time.Duration, err
 	return healthy, latency, err
 }
 
+// CheckHealthDeep retries the inner client's deep check on transient failures.
+// Returns ErrNotImplemented when the wrapped client has no deep check.
+func (r *RetryClient) CheckHealthDeep(ctx context.Context, model string) (bool, time.Duration, error) {
+	dc, ok := r.inner.(DeepHealthChecker)
+	if !ok {
+		return false, 0, ErrNotImplemented
+	}
+	var healthy bool
+	var latency time.Duration
+	err := r.retryDo(ctx, "CheckHealthDeep", func(ctx context.Context) error {
+		var e error
+		healthy, latency, e = dc.CheckHealthDeep(ctx, model)
+		return e
+	})
+	return healthy, latency, err
+}
+
 // retryDo executes fn with exponential backoff retry on transient errors.
 func (r *RetryClient) retryDo(ctx context.Context, method string, fn func(ctx context.Context) error) error {
 	var lastErr error
@@ -202,6 +219,12 @@ func (r *RetryClient) isRetryable(err error) bool {
 	}
 
 	errMsg := err.Error()
+	var provErr *ProviderError
+	if errors.As(err, &provErr) {
+		// Match on the raw upstream body rather than the summarized message,
+		// whose rendered status code would otherwise trip the patterns below.
+		errMsg = provErr.Message + ": " + string(provErr.Body)
+	}
 
 	// HTTP status-based retryable detection
 	var re retryableError
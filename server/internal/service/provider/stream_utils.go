@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
 
@@ -49,4 +50,22 @@ func processSSEStream(ctx context.Context, body io.ReadCloser, chunks chan<- Str
 			}
 		}
 	}
+	// A read error ends the loop like EOF does; report it so a truncated
+	// stream is not mistaken for a complete one. Errors caused by our own
+	// cancellation are not reported.
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		if logger != nil {
+			logger.Debug("stream read failed", zap.Error(err))
+		}
+		select {
+		case chunks <- streamReadError(err):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// streamReadError is the chunk sent when reading a stream body fails before
+// the provider finished the response.
+func streamReadError(err error) StreamChunk {
+	return StreamChunk{Error: fmt.Errorf("%w: %v", ErrStreamInterrupted, err)}
 }
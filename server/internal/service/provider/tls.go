@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSOptions are per-provider TLS settings for self-hosted endpoints, such as
+// on-prem vLLM or TGI deployments behind a private CA. The zero value keeps
+// Go's defaults: system roots, full verification and TLS 1.2 or later.
+type TLSOptions struct {
+	// InsecureSkipVerify disables certificate verification entirely. It is an
+	// explicit opt-in for endpoints with self-signed certificates.
+	InsecureSkipVerify bool
+	// MinVersion is "1.2" or "1.3"; empty keeps the default (1.2).
+	MinVersion string
+	// CABundlePath is a PEM file of CA certificates trusted in addition to the
+	// system roots.
+	CABundlePath string
+}
+
+// IsZero reports whether o leaves every setting at its default.
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+// ParseTLSVersion maps "1.2" or "1.3" to its crypto/tls constant. Empty
+// returns 0, meaning the default.
+func ParseTLSVersion(s string) (uint16, error) {
+	switch s {
+	case "":
+		return 0, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q: use 1.2 or 1.3", s)
+	}
+}
+
+// Config builds the client TLS configuration for o, or nil when o uses the
+// defaults. It fails when the minimum version is unknown or the CA bundle
+// cannot be read or holds no certificates.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+	minVersion, err := ParseTLSVersion(o.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.InsecureSkipVerify, // #nosec G402 -- explicit per-provider opt-in
+	}
+	if minVersion != 0 {
+		cfg.MinVersion = minVersion
+	}
+	if o.CABundlePath != "" {
+		pem, err := os.ReadFile(o.CABundlePath) // #nosec G304 -- path set by an admin
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("CA bundle contains no PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
@@ -0,0 +1,337 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Tool calling is expressed in the OpenAI format throughout the router:
+// requests carry "tools" and "tool_choice", responses carry "tool_calls" on
+// the assistant message. OpenAI-compatible clients forward them verbatim;
+// clients of other APIs translate to and from their native format.
+
+// Tool is an OpenAI function tool definition.
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes a function the model may call. Parameters is a JSON
+// schema object.
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall is a function call made by the model, as found in an assistant
+// message's tool_calls.
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction names the called function. Arguments is the JSON-encoded
+// argument object.
+type ToolCallFunction struct {
+	Name      string        `json:"name"`
+	Arguments ToolArguments `json:"arguments"`
+}
+
+// ToolArguments holds a tool call's arguments as a JSON string, the OpenAI
+// wire format. Some backends (e.g. Ollama) send a JSON object instead; it is
+// accepted and re-encoded as a string.
+type ToolArguments string
+
+// UnmarshalJSON accepts a JSON string, an object or null.
+func (a *ToolArguments) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*a = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = ToolArguments(s)
+		return nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return errors.New("tool call arguments must be a JSON string or object")
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return err
+	}
+	*a = ToolArguments(buf.String())
+	return nil
+}
+
+// toolCallDelta is one entry of a streamed delta's tool_calls. The first
+// delta of a call carries its id and name; later ones append to arguments.
+type toolCallDelta struct {
+	Index    int                   `json:"index"`
+	ID       string                `json:"id,omitempty"`
+	Type     string                `json:"type,omitempty"`
+	Function toolCallDeltaFunction `json:"function"`
+}
+
+type toolCallDeltaFunction struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+// Tool choice modes, as parsed from the OpenAI tool_choice parameter.
+const (
+	ToolChoiceAuto     = "auto"
+	ToolChoiceNone     = "none"
+	ToolChoiceRequired = "required"
+	// ToolChoiceFunction forces a call to one named function.
+	ToolChoiceFunction = "function"
+)
+
+// ParseTools decodes the OpenAI tools parameter. Empty or null yields no
+// tools; every tool must be a named function.
+func ParseTools(raw json.RawMessage) ([]Tool, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var tools []Tool
+	if err := json.Unmarshal(raw, &tools); err != nil {
+		return nil, errors.New("tools must be an array of function tools")
+	}
+	for i, t := range tools {
+		if t.Type != "function" {
+			return nil, fmt.Errorf("tools[%d].type must be \"function\"", i)
+		}
+		if t.Function.Name == "" {
+			return nil, fmt.Errorf("tools[%d].function.name is required", i)
+		}
+	}
+	return tools, nil
+}
+
+// ParseToolChoice decodes the OpenAI tool_choice parameter into a mode and,
+// for ToolChoiceFunction, the forced function name. Empty or null yields an
+// empty mode, leaving the choice to the provider.
+func ParseToolChoice(raw json.RawMessage) (mode, name string, err error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", "", nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		switch s {
+		case ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired:
+			return s, "", nil
+		}
+		return "", "", fmt.Errorf("tool_choice must be one of auto, none, required or a function; got %q", s)
+	}
+	var obj struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if json.Unmarshal(raw, &obj) != nil || obj.Type != "function" || obj.Function.Name == "" {
+		return "", "", errors.New(`tool_choice object must be {"type":"function","function":{"name":...}}`)
+	}
+	return ToolChoiceFunction, obj.Function.Name, nil
+}
+
+// ValidateTools checks the tools and tool_choice parameters together: a
+// forced function must be one of the tools, and tool_choice other than "none"
+// needs tools to choose from.
+func ValidateTools(toolsRaw, choiceRaw json.RawMessage) error {
+	tools, err := ParseTools(toolsRaw)
+	if err != nil {
+		return err
+	}
+	mode, name, err := ParseToolChoice(choiceRaw)
+	if err != nil {
+		return err
+	}
+	if mode == "" || mode == ToolChoiceNone {
+		return nil
+	}
+	if len(tools) == 0 {
+		return errors.New("tool_choice requires tools")
+	}
+	if mode == ToolChoiceFunction {
+		for _, t := range tools {
+			if t.Function.Name == name {
+				return nil
+			}
+		}
+		return fmt.Errorf("tool_choice names function %q, which is not in tools", name)
+	}
+	return nil
+}
+
+// ParseToolCalls decodes the tool_calls of an assistant message.
+func ParseToolCalls(raw json.RawMessage) ([]ToolCall, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var calls []ToolCall
+	if err := json.Unmarshal(raw, &calls); err != nil {
+		return nil, fmt.Errorf("decode tool_calls: %w", err)
+	}
+	return calls, nil
+}
+
+// anthropicEmptySchema is sent for function tools declared without
+// parameters; Anthropic requires an input_schema on every tool.
+var anthropicEmptySchema = json.RawMessage(`{"type":"object","properties":{}}`)
+
+// applyAnthropicTools translates the OpenAI tools and tool_choice of req into
+// the Messages API "tools" and "tool_choice" fields.
+func applyAnthropicTools(anthropicReq map[string]interface{}, req *ChatRequest) error {
+	tools, err := ParseTools(req.Tools)
+	if err != nil || len(tools) == 0 {
+		return err
+	}
+	out := make([]map[string]interface{}, len(tools))
+	for i, t := range tools {
+		schema := t.Function.Parameters
+		if len(schema) == 0 || string(schema) == "null" {
+			schema = anthropicEmptySchema
+		}
+		out[i] = map[string]interface{}{"name": t.Function.Name, "input_schema": schema}
+		if t.Function.Description != "" {
+			out[i]["description"] = t.Function.Description
+		}
+	}
+	anthropicReq["tools"] = out
+
+	mode, name, err := ParseToolChoice(req.ToolChoice)
+	if err != nil {
+		return err
+	}
+	switch mode {
+	case ToolChoiceAuto:
+		anthropicReq["tool_choice"] = map[string]string{"type": "auto"}
+	case ToolChoiceNone:
+		anthropicReq["tool_choice"] = map[string]string{"type": "none"}
+	case ToolChoiceRequired:
+		anthropicReq["tool_choice"] = map[string]string{"type": "any"}
+	case ToolChoiceFunction:
+		anthropicReq["tool_choice"] = map[string]string{"type": "tool", "name": name}
+	}
+	return nil
+}
+
+// anthropicTurn is a Messages API message whose content is a list of blocks.
+type anthropicTurn struct {
+	Role    string                   `json:"role"`
+	Content []map[string]interface{} `json:"content"`
+}
+
+// anthropicMessages converts OpenAI-style turns for the Messages API. An
+// assistant message's tool_calls become tool_use blocks, and "tool" role
+// results become tool_result blocks of a user turn; consecutive results share
+// one turn, as Anthropic requires every result of a turn's calls to follow
+// it together. Other messages pass through unchanged.
+func anthropicMessages(turns []Message) ([]interface{}, error) {
+	out := make([]interface{}, 0, len(turns))
+	var results *anthropicTurn
+	for _, m := range turns {
+		switch {
+		case m.Role == "tool":
+			block := map[string]interface{}{
+				"type":        "tool_result",
+				"tool_use_id": m.ToolCallID,
+				"content":     m.Content.Text,
+			}
+			if results == nil {
+				results = &anthropicTurn{Role: "user"}
+				out = append(out, results)
+			}
+			results.Content = append(results.Content, block)
+			continue
+		case m.Role == "assistant" && len(m.ToolCalls) > 0:
+			calls, err := ParseToolCalls(m.ToolCalls)
+			if err != nil {
+				return nil, err
+			}
+			turn := &anthropicTurn{Role: "assistant"}
+			if m.Content.Text != "" {
+				turn.Content = append(turn.Content, map[string]interface{}{"type": "text", "text": m.Content.Text})
+			}
+			for _, call := range calls {
+				input := json.RawMessage(call.Function.Arguments)
+				if len(input) == 0 {
+					input = json.RawMessage(`{}`)
+				} else if !json.Valid(input) {
+					return nil, fmt.Errorf("tool call %s: arguments are not valid JSON", call.ID)
+				}
+				turn.Content = append(turn.Content, map[string]interface{}{
+					"type":  "tool_use",
+					"id":    call.ID,
+					"name":  call.Function.Name,
+					"input": input,
+				})
+			}
+			out = append(out, turn)
+		default:
+			out = append(out, m)
+		}
+		results = nil
+	}
+	return out, nil
+}
+
+// anthropicContentBlock is a content block of a Messages API response.
+type anthropicContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// anthropicAssistantMessage folds response content blocks into an OpenAI
+// assistant message: text blocks are concatenated and tool_use blocks become
+// tool_calls. Content is null when the model only called tools.
+func anthropicAssistantMessage(blocks []anthropicContentBlock) Message {
+	text := ""
+	var calls []ToolCall
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			text += b.Text
+		case "tool_use":
+			args := string(b.Input)
+			if args == "" || args == "null" {
+				args = "{}"
+			}
+			calls = append(calls, ToolCall{
+				ID:       b.ID,
+				Type:     "function",
+				Function: ToolCallFunction{Name: b.Name, Arguments: ToolArguments(args)},
+			})
+		}
+	}
+	msg := Message{Role: "assistant", Content: StringContent(text)}
+	if len(calls) > 0 {
+		msg.ToolCalls, _ = json.Marshal(calls)
+		if text == "" {
+			msg.Content = FlexibleContent{Raw: json.RawMessage("null")}
+		}
+	}
+	return msg
+}
+
+// anthropicFinishReason maps a Messages API stop_reason to the OpenAI
+// finish_reason.
+func anthropicFinishReason(stopReason string) string {
+	switch stopReason {
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	default:
+		return "stop"
+	}
+}
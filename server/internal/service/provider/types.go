@@ -12,6 +12,12 @@ import (
 var (
 	// ErrNotImplemented is returned when a provider does not support an operation.
 	ErrNotImplemented = errors.New("operation not implemented by this provider")
+	// ErrEmptyResponse is returned when a provider answers a chat request
+	// successfully but without any choices.
+	ErrEmptyResponse = errors.New("provider returned no choices")
+	// ErrStreamInterrupted is sent as a StreamChunk error when reading a
+	// streaming response fails midway, e.g. on a connection reset.
+	ErrStreamInterrupted = errors.New("provider stream interrupted")
 )
 
 // ProviderError encapsulates an error from an upstream LLM provider, preserving HTTP details.
@@ -22,18 +28,20 @@ type ProviderError struct {
 	Message    string
 }
 
-// Error implements the error interface.
+// Error implements the error interface. The provider body is summarized
+// rather than echoed so errors stay short and free of request content.
 func (e *ProviderError) Error() string {
-	if e.Message != "" && len(e.Body) > 0 {
-		return e.Message + ": " + string(e.Body)
-	}
-	if len(e.Body) > 0 {
-		return string(e.Body)
+	if e.StatusCode == 0 && len(e.Body) == 0 {
+		if e.Message != "" {
+			return e.Message
+		}
+		return "unknown provider error"
 	}
+	summary := summarizeProviderError(e.StatusCode, e.Body)
 	if e.Message != "" {
-		return e.Message
+		return e.Message + ": " + summary
 	}
-	return "unknown provider error"
+	return summary
 }
 
 // FlexibleContent handles the OpenAI-compatible content field which can be
@@ -111,6 +119,37 @@ type Client interface {
 	CheckHealth(ctx context.Context) (bool, time.Duration, error)
 }
 
+// Health check modes, recorded on each HealthHistory row.
+const (
+	// HealthCheckShallow only verifies the API is reachable (e.g. GET /models).
+	HealthCheckShallow = "shallow"
+	// HealthCheckDeep sends a 1-token completion to verify the key can
+	// actually generate. It consumes quota, so it is opt-in per provider.
+	HealthCheckDeep = "deep"
+)
+
+// DeepHealthChecker is implemented by clients that can verify generation
+// rather than mere reachability. An empty model lets the client choose one.
+type DeepHealthChecker interface {
+	CheckHealthDeep(ctx context.Context, model string) (bool, time.Duration, error)
+}
+
+// CheckHealthWithMode runs a deep health check when requested and supported
+// by the client, falling back to CheckHealth otherwise. It returns the mode
+// that was actually used so callers can record it.
+func CheckHealthWithMode(ctx context.Context, client Client, deep bool, model string) (bool, time.Duration, string, error) {
+	if deep {
+		if dc, ok := client.(DeepHealthChecker); ok {
+			healthy, latency, err := dc.CheckHealthDeep(ctx, model)
+			if !errors.Is(err, ErrNotImplemented) {
+				return healthy, latency, HealthCheckDeep, err
+			}
+		}
+	}
+	healthy, latency, err := client.CheckHealth(ctx)
+	return healthy, latency, HealthCheckShallow, err
+}
+
 // StreamChunk represents a streaming response chunk.
 type StreamChunk struct {
 	ID      string        `json:"id,omitempty"`
@@ -137,14 +176,52 @@ type Delta struct {
 
 // ChatRequest represents a chat completion request.
 type ChatRequest struct {
-	Model         string                 `json:"model"`
-	Messages      []Message              `json:"messages"`
-	MaxTokens     int                    `json:"max_tokens,omitempty"`
-	Temperature   float64                `json:"temperature,omitempty"`
-	Stream        bool                   `json:"stream,omitempty"`
-	StreamOptions map[string]interface{} `json:"stream_options,omitempty"`
-	Tools         json.RawMessage        `json:"tools,omitempty"`
-	ToolChoice    json.RawMessage        `json:"tool_choice,omitempty"`
+	Model            string                 `json:"model"`
+	Messages         []Message              `json:"messages"`
+	MaxTokens        int                    `json:"max_tokens,omitempty"`
+	Temperature      float64                `json:"temperature,omitempty"`
+	TopP             *float64               `json:"top_p,omitempty"`
+	FrequencyPenalty *float64               `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64               `json:"presence_penalty,omitempty"`
+	N                int                    `json:"n,omitempty"`
+	Stop             StopSequences          `json:"stop,omitempty"`
+	Stream           bool                   `json:"stream,omitempty"`
+	StreamOptions    map[string]interface{} `json:"stream_options,omitempty"`
+	Tools            json.RawMessage        `json:"tools,omitempty"`
+	ToolChoice       json.RawMessage        `json:"tool_choice,omitempty"`
+	ResponseFormat   *ResponseFormat        `json:"response_format,omitempty"`
+	// User identifies the end user to the provider for abuse monitoring.
+	User string `json:"user,omitempty"`
+	// SafePrompt asks Mistral to prepend its guardrail system prompt. Only
+	// the Mistral client sends it; other providers reject the parameter.
+	SafePrompt bool `json:"-"`
+}
+
+// StopSequences holds the "stop" parameter, which OpenAI accepts either as a
+// single string or as an array of strings.
+type StopSequences []string
+
+// UnmarshalJSON accepts a string, an array of strings or null.
+func (s *StopSequences) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*s = nil
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single == "" {
+			*s = nil
+		} else {
+			*s = StopSequences{single}
+		}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return errors.New("stop must be a string or an array of strings")
+	}
+	*s = many
+	return nil
 }
 
 // Message represents a chat message.
@@ -176,6 +253,10 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+	// Prompt-cache tokens reported by Anthropic. Both are already counted in
+	// PromptTokens; they are billed at the cache write and read rates.
+	CacheCreationTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadTokens     int `json:"cache_read_input_tokens,omitempty"`
 }
 
 // ModelInfo represents model information.
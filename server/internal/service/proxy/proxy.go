@@ -8,6 +8,7 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -24,16 +25,25 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxUpstreamChainDepth bounds the number of upstream hops behind a proxy.
+const maxUpstreamChainDepth = 5
+
+// Upstream chain validation errors.
+var (
+	ErrUpstreamCycle        = errors.New("proxy upstream chain forms a cycle")
+	ErrUpstreamChainTooDeep = errors.New("proxy upstream chain is too deep")
+)
+
 // Service handles proxy pool management.
 type Service struct {
-	proxyRepo  *repository.ProxyRepository
+	proxyRepo  repository.ProxyRepo
 	httpClient *http.Client
 	mu         sync.RWMutex
 	logger     *zap.Logger
 }
 
 // NewService creates a new proxy service.
-func NewService(proxyRepo *repository.ProxyRepository, logger *zap.Logger) *Service {
+func NewService(proxyRepo repository.ProxyRepo, logger *zap.Logger) *Service {
 	return &Service{
 		proxyRepo: proxyRepo,
 		httpClient: &http.Client{
@@ -45,6 +55,10 @@ func NewService(proxyRepo *repository.ProxyRepository, logger *zap.Logger) *Serv
 
 // Create adds a new proxy.
 func (s *Service) Create(ctx context.Context, proxyURL, proxyType, region, username, password string, upstreamProxyID *uuid.UUID) (*models.Proxy, error) {
+	if err := s.validateUpstreamChain(ctx, uuid.Nil, proxyURL, upstreamProxyID); err != nil {
+		return nil, err
+	}
+
 	// Encrypt password if provided
 	encryptedPassword := password
 	if password != "" {
@@ -92,6 +106,9 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, proxyURL, proxyType,
 	if err != nil {
 		return nil, err
 	}
+	if err := s.validateUpstreamChain(ctx, id, proxyURL, upstreamProxyID); err != nil {
+		return nil, err
+	}
 
 	proxy.URL = proxyURL
 	proxy.Type = proxyType
@@ -114,11 +131,50 @@ func (s *Service) Update(ctx context.Context, id uuid.UUID, proxyURL, proxyType,
 	return proxy, nil
 }
 
+// validateUpstreamChain walks the upstream chain starting at upstreamID and
+// rejects it when it loops back to a proxy already on the chain (including
+// selfID, which is uuid.Nil for a proxy being created) or has more than
+// maxUpstreamChainDepth hops. Errors name the proxies on the chain by URL.
+func (s *Service) validateUpstreamChain(ctx context.Context, selfID uuid.UUID, selfURL string, upstreamID *uuid.UUID) error {
+	if upstreamID == nil {
+		return nil
+	}
+
+	chain := []string{selfURL}
+	seen := make(map[uuid.UUID]string)
+	if selfID != uuid.Nil {
+		seen[selfID] = selfURL
+	}
+	for next, depth := upstreamID, 1; next != nil; depth++ {
+		if label, ok := seen[*next]; ok {
+			return fmt.Errorf("%w: %s -> %s", ErrUpstreamCycle, strings.Join(chain, " -> "), label)
+		}
+		if depth > maxUpstreamChainDepth {
+			return fmt.Errorf("%w: more than %d upstream hops", ErrUpstreamChainTooDeep, maxUpstreamChainDepth)
+		}
+		upstream, err := s.proxyRepo.GetByID(ctx, *next)
+		if err != nil {
+			return fmt.Errorf("upstream proxy %s not found: %w", next, err)
+		}
+		seen[upstream.ID] = upstream.URL
+		chain = append(chain, upstream.URL)
+		next = upstream.UpstreamProxyID
+	}
+	return nil
+}
+
 // Delete removes a proxy.
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.proxyRepo.Delete(ctx, id)
 }
 
+// ResetStats zeroes the counters and average latency of the given proxies, or
+// of the whole pool when ids is empty, so a proxy that has been fixed is no
+// longer judged by its earlier failures. It returns how many were reset.
+func (s *Service) ResetStats(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	return s.proxyRepo.ResetStats(ctx, ids)
+}
+
 // Toggle enables or disables a proxy.
 func (s *Service) Toggle(ctx context.Context, id uuid.UUID) (*models.Proxy, error) {
 	proxy, err := s.proxyRepo.GetByID(ctx, id)
@@ -194,217 +250,174 @@ func (s *Service) normalizeProxyURL(proxy *models.Proxy) string {
 	return proxyURLStr
 }
 
-// buildProxyTransport creates an http.Transport with proxy chain support.
-func (s *Service) buildProxyTransport(ctx context.Context, proxy *models.Proxy) (*http.Transport, error) {
-	proxyURLStr := s.normalizeProxyURL(proxy)
-	proxyURL, err := url.Parse(proxyURLStr)
+// proxyURLWithAuth parses the proxy's URL and attaches its decrypted credentials.
+func (s *Service) proxyURLWithAuth(proxy *models.Proxy) (*url.URL, error) {
+	proxyURL, err := url.Parse(s.normalizeProxyURL(proxy))
 	if err != nil {
 		return nil, err
 	}
-
 	if proxy.Username != "" && proxy.Password != "" {
 		// Decrypt password before using
 		password, _ := crypto.Decrypt(proxy.Password)
 		proxyURL.User = url.UserPassword(proxy.Username, password)
 	}
+	return proxyURL, nil
+}
 
-	// Check if this proxy has an upstream proxy
-	if proxy.UpstreamProxyID != nil {
-		upstreamProxy, err := s.proxyRepo.GetByID(ctx, *proxy.UpstreamProxyID)
-		if err != nil {
-			s.logger.Warn("failed to get upstream proxy, using direct connection",
-				zap.String("proxy_id", proxy.ID.String()),
-				zap.String("upstream_id", proxy.UpstreamProxyID.String()),
-				zap.Error(err))
-		} else {
-			// Build chained transport
-			return s.buildChainedTransport(ctx, proxyURL, upstreamProxy)
-		}
+// buildProxyTransport creates an http.Transport with proxy chain support.
+func (s *Service) buildProxyTransport(ctx context.Context, proxy *models.Proxy) (*http.Transport, error) {
+	hops, err := s.resolveProxyChain(ctx, proxy)
+	if err != nil {
+		return nil, err
 	}
 
-	// Simple single-proxy transport
-	return &http.Transport{
-		Proxy: http.ProxyURL(proxyURL),
-	}, nil
+	if len(hops) == 1 {
+		// Simple single-proxy transport
+		return &http.Transport{
+			Proxy: http.ProxyURL(hops[0]),
+		}, nil
+	}
+	return s.buildChainedTransport(hops), nil
 }
 
-// buildChainedTransport creates a transport that connects through an upstream proxy first.
-// The flow is: client -> upstream proxy (CONNECT) -> [TLS if HTTPS proxy] -> target proxy -> destination
-func (s *Service) buildChainedTransport(ctx context.Context, targetProxyURL *url.URL, upstreamProxy *models.Proxy) (*http.Transport, error) {
-	upstreamURLStr := s.normalizeProxyURL(upstreamProxy)
-	upstreamURL, err := url.Parse(upstreamURLStr)
+// resolveProxyChain returns the proxy URLs in dial order: the outermost
+// upstream (entry) first and proxy itself (exit) last. An upstream that
+// cannot be loaded ends the chain there, so traffic still leaves through the
+// hops that could be resolved.
+func (s *Service) resolveProxyChain(ctx context.Context, proxy *models.Proxy) ([]*url.URL, error) {
+	exitURL, err := s.proxyURLWithAuth(proxy)
 	if err != nil {
 		return nil, err
 	}
 
-	if upstreamProxy.Username != "" && upstreamProxy.Password != "" {
-		upstreamURL.User = url.UserPassword(upstreamProxy.Username, upstreamProxy.Password)
-	}
-
-	// Check if target proxy requires TLS (HTTPS proxy)
-	targetRequiresTLS := targetProxyURL.Scheme == "https"
+	hops := []*url.URL{exitURL}
+	seen := map[uuid.UUID]bool{proxy.ID: true}
+	for current := proxy; current.UpstreamProxyID != nil; {
+		if seen[*current.UpstreamProxyID] {
+			return nil, fmt.Errorf("%w at proxy %s", ErrUpstreamCycle, current.UpstreamProxyID)
+		}
+		if len(hops) > maxUpstreamChainDepth {
+			return nil, fmt.Errorf("%w: more than %d upstream hops", ErrUpstreamChainTooDeep, maxUpstreamChainDepth)
+		}
 
-	s.logger.Debug("building chained transport",
-		zap.String("upstream", upstreamURL.Host),
-		zap.String("target", targetProxyURL.Host),
-		zap.String("target_scheme", targetProxyURL.Scheme),
-		zap.Bool("target_requires_tls", targetRequiresTLS))
+		upstream, err := s.proxyRepo.GetByID(ctx, *current.UpstreamProxyID)
+		if err != nil {
+			s.logger.Warn("failed to get upstream proxy, chain ends before it",
+				zap.String("proxy_id", current.ID.String()),
+				zap.String("upstream_id", current.UpstreamProxyID.String()),
+				zap.Error(err))
+			break
+		}
+		upstreamURL, err := s.proxyURLWithAuth(upstream)
+		if err != nil {
+			return nil, err
+		}
+		seen[upstream.ID] = true
+		hops = append([]*url.URL{upstreamURL}, hops...)
+		current = upstream
+	}
+	return hops, nil
+}
 
-	// Capture logger for use in closure
+// buildChainedTransport creates a transport that tunnels through every hop in
+// order. The flow is: client -> hops[0] (CONNECT) -> [TLS if HTTPS proxy] ->
+// hops[1] (CONNECT) -> ... -> last hop (CONNECT) -> destination.
+// A two-element chain is the classic upstream -> target proxy setup.
+func (s *Service) buildChainedTransport(hops []*url.URL) *http.Transport {
 	logger := s.logger
+	hostsField := zap.Strings("hops", hopHosts(hops))
+	logger.Debug("building chained transport", hostsField)
 
-	// Create a custom dialer that:
-	// 1. Connects to upstream proxy
-	// 2. Sends CONNECT to establish tunnel to target proxy
-	// 3. If target is HTTPS proxy, perform TLS handshake
-	// 4. Through this tunnel, sends another CONNECT to target proxy for the final destination
-	transport := &http.Transport{
+	return &http.Transport{
 		DialContext: func(dialCtx context.Context, network, addr string) (net.Conn, error) {
-			logger.Info("chained proxy dial started",
-				zap.String("network", network),
-				zap.String("target_addr", addr),
-				zap.String("upstream_host", upstreamURL.Host),
-				zap.String("target_proxy", targetProxyURL.Host))
-
-			// First connect to the upstream proxy
 			dialer := &net.Dialer{
 				Timeout:   10 * time.Second,
 				KeepAlive: 30 * time.Second,
 			}
 
-			upstreamHost := upstreamURL.Host
-			logger.Info("step 1: connecting to upstream proxy", zap.String("upstream", upstreamHost))
-			conn, err := dialer.DialContext(dialCtx, "tcp", upstreamHost)
+			entry := hops[0]
+			conn, err := dialer.DialContext(dialCtx, "tcp", entry.Host)
 			if err != nil {
-				logger.Error("failed to connect to upstream proxy", zap.String("upstream", upstreamHost), zap.Error(err))
-				return nil, fmt.Errorf("failed to connect to upstream proxy %s: %w", upstreamHost, err)
-			}
-			logger.Info("step 1: connected to upstream proxy", zap.String("upstream", upstreamHost))
-
-			// Send CONNECT request to upstream proxy for the target proxy
-			targetHost := targetProxyURL.Host
-			connectReq := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", targetHost, targetHost)
-
-			// Add upstream proxy auth if needed
-			if upstreamURL.User != nil {
-				username := upstreamURL.User.Username()
-				password, _ := upstreamURL.User.Password()
-				auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
-				connectReq += fmt.Sprintf("Proxy-Authorization: Basic %s\r\n", auth)
+				logger.Error("failed to connect to entry proxy", zap.String("proxy", entry.Host), zap.Error(err))
+				return nil, fmt.Errorf("failed to connect to upstream proxy %s: %w", entry.Host, err)
 			}
-			connectReq += "\r\n"
-
-			logger.Info("step 2: sending CONNECT to upstream for target proxy",
-				zap.String("target_host", targetHost),
-				zap.String("request", strings.TrimSpace(connectReq)))
-
-			if _, err := conn.Write([]byte(connectReq)); err != nil {
-				logger.Error("failed to send CONNECT to upstream", zap.Error(err))
-				_ = conn.Close()
-				return nil, fmt.Errorf("failed to send CONNECT to upstream proxy: %w", err)
+			if conn, err = proxyTLS(dialCtx, conn, entry); err != nil {
+				return nil, err
 			}
 
-			// Read response from upstream proxy
-			reader := bufio.NewReader(conn)
-			resp, err := http.ReadResponse(reader, nil)
-			if err != nil {
-				logger.Error("failed to read CONNECT response from upstream", zap.Error(err))
-				_ = conn.Close()
-				return nil, fmt.Errorf("failed to read CONNECT response from upstream: %w", err)
-			}
-			_ = resp.Body.Close()
-
-			logger.Info("step 2: upstream CONNECT response", zap.Int("status_code", resp.StatusCode), zap.String("status", resp.Status))
-
-			if resp.StatusCode != http.StatusOK {
-				_ = conn.Close()
-				return nil, fmt.Errorf("upstream CONNECT to target proxy failed: %s", resp.Status)
-			}
-
-			// Now we have a tunnel to the target proxy
-			// If target proxy is HTTPS, we need to perform TLS handshake
-			var targetConn net.Conn
-			targetConn = conn
-			if targetRequiresTLS {
-				// Extract hostname without port for TLS ServerName
-				targetHostname := targetHost
-				if h, _, err := net.SplitHostPort(targetHost); err == nil {
-					targetHostname = h
+			// Each hop is asked to CONNECT to the next one; the last hop
+			// connects to the destination.
+			for i, hop := range hops {
+				target := addr
+				if i+1 < len(hops) {
+					target = hops[i+1].Host
 				}
-
-				logger.Info("step 3: performing TLS handshake with target proxy", zap.String("server_name", targetHostname))
-
-				tlsConn := tls.Client(conn, &tls.Config{
-					ServerName: targetHostname,
-					MinVersion: tls.VersionTLS12,
-				})
-				if err := tlsConn.HandshakeContext(dialCtx); err != nil {
-					logger.Error("TLS handshake with target proxy failed", zap.Error(err))
+				if err := proxyConnect(conn, hop, target); err != nil {
 					_ = conn.Close()
-					return nil, fmt.Errorf("TLS handshake with target proxy failed: %w", err)
+					logger.Error("chained proxy CONNECT failed", hostsField, zap.Int("hop", i), zap.Error(err))
+					return nil, err
+				}
+				if i+1 < len(hops) {
+					if conn, err = proxyTLS(dialCtx, conn, hops[i+1]); err != nil {
+						return nil, err
+					}
 				}
-				logger.Info("step 3: TLS handshake successful",
-					zap.Uint16("tls_version", tlsConn.ConnectionState().Version),
-					zap.String("cipher_suite", tls.CipherSuiteName(tlsConn.ConnectionState().CipherSuite)))
-				targetConn = tlsConn
-				// Create new reader for TLS connection
-				reader = bufio.NewReader(tlsConn)
-			} else {
-				logger.Info("step 3: skipping TLS (target is HTTP proxy)")
-			}
-
-			// Send CONNECT request through the tunnel to target proxy for the final destination (addr)
-			connectReq2 := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", addr, addr)
-
-			// Add target proxy auth if needed
-			if targetProxyURL.User != nil {
-				username := targetProxyURL.User.Username()
-				password, _ := targetProxyURL.User.Password()
-				auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
-				connectReq2 += fmt.Sprintf("Proxy-Authorization: Basic %s\r\n", auth)
-				logger.Info("step 4: adding auth for target proxy")
-			}
-			connectReq2 += "\r\n"
-
-			logger.Info("step 4: sending CONNECT to target proxy for final destination",
-				zap.String("destination", addr))
-
-			if _, err := targetConn.Write([]byte(connectReq2)); err != nil {
-				logger.Error("failed to send CONNECT to target proxy", zap.Error(err))
-				_ = targetConn.Close()
-				return nil, fmt.Errorf("failed to send CONNECT to target proxy: %w", err)
 			}
 
-			// Read response from target proxy
-			resp2, err := http.ReadResponse(reader, nil)
-			if err != nil {
-				logger.Error("failed to read CONNECT response from target proxy", zap.Error(err))
-				_ = targetConn.Close()
-				return nil, fmt.Errorf("failed to read CONNECT response from target proxy: %w", err)
-			}
-			_ = resp2.Body.Close()
+			logger.Debug("chained proxy tunnel established", hostsField, zap.String("destination", addr))
+			return conn, nil
+		},
+	}
+}
 
-			logger.Info("step 4: target proxy CONNECT response",
-				zap.Int("status_code", resp2.StatusCode),
-				zap.String("status", resp2.Status),
-				zap.String("destination", addr))
+// proxyConnect asks the proxy at hop, reached over conn, to open a tunnel to
+// target, and waits for a 2xx response.
+func proxyConnect(conn net.Conn, hop *url.URL, target string) error {
+	connectReq := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", target, target)
+	if hop.User != nil {
+		password, _ := hop.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(hop.User.Username() + ":" + password))
+		connectReq += fmt.Sprintf("Proxy-Authorization: Basic %s\r\n", auth)
+	}
+	connectReq += "\r\n"
 
-			if resp2.StatusCode != http.StatusOK {
-				_ = targetConn.Close()
-				return nil, fmt.Errorf("target proxy CONNECT to %s failed: %s", addr, resp2.Status)
-			}
+	if _, err := conn.Write([]byte(connectReq)); err != nil {
+		return fmt.Errorf("failed to send CONNECT to proxy %s: %w", hop.Host, err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return fmt.Errorf("failed to read CONNECT response from proxy %s: %w", hop.Host, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("proxy %s CONNECT to %s failed: %s", hop.Host, target, resp.Status)
+	}
+	return nil
+}
 
-			logger.Info("chained proxy tunnel established successfully",
-				zap.String("upstream", upstreamHost),
-				zap.String("target_proxy", targetHost),
-				zap.String("destination", addr))
+// proxyTLS wraps conn in TLS when hop is an HTTPS proxy. conn is closed on
+// handshake failure.
+func proxyTLS(ctx context.Context, conn net.Conn, hop *url.URL) (net.Conn, error) {
+	if hop.Scheme != "https" {
+		return conn, nil
+	}
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName: hop.Hostname(),
+		MinVersion: tls.VersionTLS12,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("TLS handshake with proxy %s failed: %w", hop.Host, err)
+	}
+	return tlsConn, nil
+}
 
-			// Now we have a tunnel through both proxies to the final destination
-			return targetConn, nil
-		},
+func hopHosts(hops []*url.URL) []string {
+	hosts := make([]string, len(hops))
+	for i, h := range hops {
+		hosts[i] = h.Host
 	}
-
-	return transport, nil
+	return hosts
 }
 
 // checkProxyHealth tests proxy connectivity.
@@ -1,15 +1,35 @@
 package proxy
 
 import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
 
+	"llm-router-platform/internal/crypto"
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
 )
 
+// TestMain initializes the crypto module used for proxy passwords.
+func TestMain(m *testing.M) {
+	_ = crypto.Initialize("test-32byte-encryption-key-xtra!")
+	os.Exit(m.Run())
+}
+
 func TestProxyModel(t *testing.T) {
 	proxy := &models.Proxy{
 		URL:      "http://proxy.example.com:8080",
@@ -178,3 +198,247 @@ func TestEmptyProxy(t *testing.T) {
 	assert.Empty(t, proxy.Type)
 	assert.False(t, proxy.IsActive)
 }
+
+type fakeProxyRepo struct {
+	repository.ProxyRepo
+	proxies map[uuid.UUID]*models.Proxy
+}
+
+func (r *fakeProxyRepo) Create(_ context.Context, p *models.Proxy) error {
+	p.ID = uuid.New()
+	r.proxies[p.ID] = p
+	return nil
+}
+
+func (r *fakeProxyRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Proxy, error) {
+	p, ok := r.proxies[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *fakeProxyRepo) Update(_ context.Context, p *models.Proxy) error {
+	r.proxies[p.ID] = p
+	return nil
+}
+
+func TestUpstreamChainRejectsCycle(t *testing.T) {
+	svc := NewService(&fakeProxyRepo{proxies: map[uuid.UUID]*models.Proxy{}}, zap.NewNop())
+	ctx := context.Background()
+
+	a, err := svc.Create(ctx, "http://a:8080", "http", "", "", "", nil)
+	assert.NoError(t, err)
+	b, err := svc.Create(ctx, "http://b:8080", "http", "", "", "", &a.ID)
+	assert.NoError(t, err)
+
+	_, err = svc.Update(ctx, a.ID, "http://a:8080", "http", "", true, "", "", &b.ID)
+	assert.ErrorIs(t, err, ErrUpstreamCycle)
+	assert.Contains(t, err.Error(), "http://a:8080 -> http://b:8080 -> http://a:8080")
+
+	_, err = svc.Update(ctx, a.ID, "http://a:8080", "http", "", true, "", "", &a.ID)
+	assert.ErrorIs(t, err, ErrUpstreamCycle, "a proxy cannot be its own upstream")
+
+	stored, _ := svc.GetByID(ctx, a.ID)
+	assert.Nil(t, stored.UpstreamProxyID, "rejected update must not be persisted")
+}
+
+func TestUpstreamChainDepthLimit(t *testing.T) {
+	svc := NewService(&fakeProxyRepo{proxies: map[uuid.UUID]*models.Proxy{}}, zap.NewNop())
+	ctx := context.Background()
+
+	var upstream *uuid.UUID
+	for i := 0; i <= maxUpstreamChainDepth; i++ { // the last one has maxUpstreamChainDepth hops
+		p, err := svc.Create(ctx, "http://hop:8080", "http", "", "", "", upstream)
+		assert.NoError(t, err)
+		upstream = &p.ID
+	}
+
+	_, err := svc.Create(ctx, "http://top:8080", "http", "", "", "", upstream)
+	assert.ErrorIs(t, err, ErrUpstreamChainTooDeep)
+
+	missing := uuid.New()
+	_, err = svc.Create(ctx, "http://orphan:8080", "http", "", "", "", &missing)
+	assert.Error(t, err)
+}
+
+// connectProxy is a minimal HTTP CONNECT proxy that records the tunnel
+// targets and Proxy-Authorization headers it receives.
+type connectProxy struct {
+	ln      net.Listener
+	mu      sync.Mutex
+	targets []string
+	auths   []string
+}
+
+func newConnectProxy(t *testing.T) *connectProxy {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &connectProxy{ln: ln}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go p.serve(conn)
+		}
+	}()
+	return p
+}
+
+func (p *connectProxy) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	req, err := http.ReadRequest(bufio.NewReader(conn))
+	if err != nil || req.Method != http.MethodConnect {
+		return
+	}
+	p.mu.Lock()
+	p.targets = append(p.targets, req.Host)
+	p.auths = append(p.auths, req.Header.Get("Proxy-Authorization"))
+	p.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", req.Host)
+	if err != nil {
+		_, _ = conn.Write([]byte("HTTP/1.1 502 Bad Gateway\r\n\r\n"))
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+	done := make(chan struct{}, 2)
+	go func() { _, _ = io.Copy(upstream, conn); done <- struct{}{} }()
+	go func() { _, _ = io.Copy(conn, upstream); done <- struct{}{} }()
+	<-done
+}
+
+func (p *connectProxy) addr() string { return p.ln.Addr().String() }
+
+func (p *connectProxy) seen() ([]string, []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.targets...), append([]string(nil), p.auths...)
+}
+
+func TestChainedTransportThreeHops(t *testing.T) {
+	dest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer dest.Close()
+	destHost := strings.TrimPrefix(dest.URL, "http://")
+
+	entry, mid, exit := newConnectProxy(t), newConnectProxy(t), newConnectProxy(t)
+	svc := NewService(&fakeProxyRepo{proxies: map[uuid.UUID]*models.Proxy{}}, zap.NewNop())
+	ctx := context.Background()
+
+	// Upstream links point from the exit back towards the entry.
+	entryProxy, err := svc.Create(ctx, entry.addr(), "http", "", "", "", nil)
+	assert.NoError(t, err)
+	midProxy, err := svc.Create(ctx, mid.addr(), "http", "", "alice", "s3cret", &entryProxy.ID)
+	assert.NoError(t, err)
+	exitProxy, err := svc.Create(ctx, exit.addr(), "http", "", "", "", &midProxy.ID)
+	assert.NoError(t, err)
+
+	transport, err := svc.buildProxyTransport(ctx, exitProxy)
+	assert.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get(dest.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+
+	targets, _ := entry.seen()
+	assert.Equal(t, []string{mid.addr()}, targets)
+	targets, auths := mid.seen()
+	assert.Equal(t, []string{exit.addr()}, targets)
+	assert.Equal(t, []string{"Basic " + base64.StdEncoding.EncodeToString([]byte("alice:s3cret"))}, auths,
+		"upstream hop credentials are decrypted before use")
+	targets, _ = exit.seen()
+	assert.Equal(t, []string{destHost}, targets)
+}
+
+func TestChainedTransportTwoHops(t *testing.T) {
+	dest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer dest.Close()
+
+	upstream, target := newConnectProxy(t), newConnectProxy(t)
+	svc := NewService(&fakeProxyRepo{proxies: map[uuid.UUID]*models.Proxy{}}, zap.NewNop())
+	ctx := context.Background()
+
+	upstreamProxy, _ := svc.Create(ctx, upstream.addr(), "http", "", "", "", nil)
+	targetProxy, _ := svc.Create(ctx, target.addr(), "http", "", "", "", &upstreamProxy.ID)
+
+	hops, err := svc.resolveProxyChain(ctx, targetProxy)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{upstream.addr(), target.addr()}, hopHosts(hops))
+
+	transport, err := svc.buildProxyTransport(ctx, targetProxy)
+	assert.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get(dest.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestChainedTransportReportsFailingHop(t *testing.T) {
+	entry := newConnectProxy(t)
+	svc := NewService(&fakeProxyRepo{proxies: map[uuid.UUID]*models.Proxy{}}, zap.NewNop())
+	ctx := context.Background()
+
+	// The exit proxy is unreachable, so the entry answers its CONNECT with 502.
+	entryProxy, _ := svc.Create(ctx, entry.addr(), "http", "", "", "", nil)
+	exitProxy, _ := svc.Create(ctx, "127.0.0.1:1", "http", "", "", "", &entryProxy.ID)
+
+	transport, err := svc.buildProxyTransport(ctx, exitProxy)
+	assert.NoError(t, err)
+	_, err = (&http.Client{Transport: transport}).Get("http://example.invalid/")
+	assert.ErrorContains(t, err, "502")
+}
+
+func TestComputePoolStats(t *testing.T) {
+	proxies := []models.Proxy{
+		{Type: "http", Region: "us", IsActive: true, SuccessCount: 90, FailureCount: 10, AvgLatency: 100},
+		{Type: "socks5", Region: "us", IsActive: true, SuccessCount: 1, FailureCount: 9, AvgLatency: 1000},
+		{Type: "http", Region: "", IsActive: false, SuccessCount: 0, FailureCount: 0},
+		{Type: "http", Region: "eu", IsActive: true},
+	}
+
+	stats := computePoolStats(proxies)
+
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 3, stats.Active)
+	assert.Equal(t, 2, stats.Healthy, "inactive and mostly-failing proxies are unhealthy")
+	assert.Equal(t, int64(91), stats.SuccessCount)
+	assert.Equal(t, int64(19), stats.FailureCount)
+	assert.InDelta(t, 91.0/110, stats.SuccessRate, 1e-9)
+	assert.InDelta(t, (100*100.0+10*1000.0)/110, stats.AvgLatency, 1e-9)
+
+	us := stats.ByRegion["us"]
+	assert.Equal(t, 2, us.Total)
+	assert.Equal(t, 1, us.Healthy)
+	assert.Equal(t, 1, stats.ByRegion[unspecifiedGroup].Total)
+	assert.Zero(t, stats.ByRegion["eu"].SuccessRate, "no outcomes recorded")
+
+	assert.Equal(t, 3, stats.ByType["http"].Total)
+	assert.InDelta(t, 0.9, stats.ByType["http"].SuccessRate, 1e-9)
+	assert.InDelta(t, 1000.0, stats.ByType["socks5"].AvgLatency, 1e-9)
+}
+
+func TestComputePoolStatsEmpty(t *testing.T) {
+	stats := computePoolStats(nil)
+	assert.Zero(t, stats.Total)
+	assert.Zero(t, stats.SuccessRate)
+	assert.Empty(t, stats.ByRegion)
+	assert.Empty(t, stats.ByType)
+}
@@ -0,0 +1,96 @@
+package proxy
+
+import (
+	"context"
+
+	"llm-router-platform/internal/models"
+)
+
+// healthySuccessRate is the minimum recorded success rate for an active proxy
+// to count as healthy. Proxies with no recorded outcomes yet count as healthy.
+const healthySuccessRate = 0.5
+
+// unspecifiedGroup labels proxies without a region in the pool breakdown.
+const unspecifiedGroup = "unspecified"
+
+// GroupStats aggregates the stored counters of a set of proxies.
+type GroupStats struct {
+	Total        int     `json:"total"`
+	Active       int     `json:"active"`
+	Healthy      int     `json:"healthy"`
+	SuccessCount int64   `json:"success_count"`
+	FailureCount int64   `json:"failure_count"`
+	SuccessRate  float64 `json:"success_rate"`   // 0 when no outcomes are recorded
+	AvgLatency   float64 `json:"avg_latency_ms"` // weighted by recorded outcomes
+}
+
+// PoolStats is the pool-wide view of the proxy counters, with breakdowns by
+// region and proxy type.
+type PoolStats struct {
+	GroupStats
+	ByRegion map[string]*GroupStats `json:"by_region"`
+	ByType   map[string]*GroupStats `json:"by_type"`
+}
+
+// Stats aggregates the stored per-proxy counters. It runs no health checks.
+func (s *Service) Stats(ctx context.Context) (*PoolStats, error) {
+	proxies, err := s.proxyRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return computePoolStats(proxies), nil
+}
+
+// computePoolStats folds proxies into pool-wide, per-region and per-type totals.
+func computePoolStats(proxies []models.Proxy) *PoolStats {
+	stats := &PoolStats{
+		ByRegion: map[string]*GroupStats{},
+		ByType:   map[string]*GroupStats{},
+	}
+	latency := map[*GroupStats]float64{} // latency sum weighted by outcomes
+	group := func(m map[string]*GroupStats, key string) *GroupStats {
+		if key == "" {
+			key = unspecifiedGroup
+		}
+		g, ok := m[key]
+		if !ok {
+			g = &GroupStats{}
+			m[key] = g
+		}
+		return g
+	}
+
+	for i := range proxies {
+		p := &proxies[i]
+		for _, g := range []*GroupStats{&stats.GroupStats, group(stats.ByRegion, p.Region), group(stats.ByType, p.Type)} {
+			g.Total++
+			if p.IsActive {
+				g.Active++
+			}
+			if isHealthy(p) {
+				g.Healthy++
+			}
+			g.SuccessCount += p.SuccessCount
+			g.FailureCount += p.FailureCount
+			latency[g] += p.AvgLatency * float64(p.SuccessCount+p.FailureCount)
+		}
+	}
+
+	for g, sum := range latency {
+		if outcomes := g.SuccessCount + g.FailureCount; outcomes > 0 {
+			g.SuccessRate = float64(g.SuccessCount) / float64(outcomes)
+			g.AvgLatency = sum / float64(outcomes)
+		}
+	}
+	return stats
+}
+
+// isHealthy reports whether an active proxy's recorded success rate is at
+// least healthySuccessRate.
+func isHealthy(p *models.Proxy) bool {
+	if !p.IsActive {
+		return false
+	}
+	outcomes := p.SuccessCount + p.FailureCount
+	return outcomes == 0 || float64(p.SuccessCount)/float64(outcomes) >= healthySuccessRate
+}
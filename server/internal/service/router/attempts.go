@@ -0,0 +1,94 @@
+package router
+
+import (
+	"context"
+	"sync"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Attempt is one upstream call made while serving a request.
+type Attempt struct {
+	ProviderID uuid.UUID
+	Provider   string
+	KeyAlias   string // empty for providers that don't require keys
+	Err        error
+}
+
+// Attempts collects the upstream calls ExecuteChat, ExecuteChatWithFallback
+// and ExecuteStreamChat make for a request, including failed key rotations
+// and fallback providers, so the caller can report how a request was served.
+type Attempts struct {
+	mu   sync.Mutex
+	list []Attempt
+}
+
+type attemptsKey struct{}
+
+// WithAttempts returns a context that records upstream attempts into the
+// returned Attempts.
+func WithAttempts(ctx context.Context) (context.Context, *Attempts) {
+	a := &Attempts{}
+	return context.WithValue(ctx, attemptsKey{}, a), a
+}
+
+// AttemptsFrom returns the Attempts attached to ctx by WithAttempts, or nil.
+func AttemptsFrom(ctx context.Context) *Attempts {
+	a, _ := ctx.Value(attemptsKey{}).(*Attempts)
+	return a
+}
+
+// recordAttempt appends an attempt to the Attempts attached to ctx, if any.
+func recordAttempt(ctx context.Context, p *models.Provider, key *models.ProviderAPIKey, err error) {
+	a := AttemptsFrom(ctx)
+	if a == nil {
+		return
+	}
+	at := Attempt{ProviderID: p.ID, Provider: p.Name, Err: err}
+	if key != nil {
+		at.KeyAlias = key.Alias
+	}
+	a.mu.Lock()
+	a.list = append(a.list, at)
+	a.mu.Unlock()
+}
+
+// Count returns the number of upstream attempts made.
+func (a *Attempts) Count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.list)
+}
+
+// Last returns the most recent attempt; for a successful request this is the
+// one that served it.
+func (a *Attempts) Last() (Attempt, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.list) == 0 {
+		return Attempt{}, false
+	}
+	return a.list[len(a.list)-1], true
+}
+
+// All returns a copy of the attempts in the order they were made.
+func (a *Attempts) All() []Attempt {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Attempt(nil), a.list...)
+}
+
+// Fallback reports whether more than one provider was tried. Retrying the
+// same provider with another key is not a fallback.
+func (a *Attempts) Fallback() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, at := range a.list {
+		if at.ProviderID != a.list[0].ProviderID {
+			return true
+		}
+	}
+	return false
+}
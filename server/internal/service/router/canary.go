@@ -0,0 +1,94 @@
+package router
+
+import (
+	"llm-router-platform/internal/models"
+)
+
+// trafficShares returns the relative share of matching traffic each provider
+// should receive. Without canaries the shares are the providers' weights.
+// A canary (TrafficPercentage between 1 and 99) gets its weighted share capped
+// at that percentage, and the traffic it gives up goes to the uncapped
+// providers in proportion to their weights.
+func trafficShares(providers []models.Provider) []float64 {
+	shares := make([]float64, len(providers))
+	var totalWeight, uncappedWeight float64
+	capped := false
+	for i := range providers {
+		shares[i] = providers[i].Weight
+		totalWeight += providers[i].Weight
+		if providers[i].TrafficCap() < 1 {
+			capped = true
+		} else {
+			uncappedWeight += providers[i].Weight
+		}
+	}
+	if !capped {
+		return shares
+	}
+
+	weight := func(p *models.Provider) float64 { return p.Weight }
+	if totalWeight == 0 {
+		// Without weights every provider starts from an equal share.
+		weight = func(*models.Provider) float64 { return 1 }
+		totalWeight = float64(len(providers))
+		uncappedWeight = 0
+		for i := range providers {
+			if providers[i].TrafficCap() >= 1 {
+				uncappedWeight++
+			}
+		}
+	}
+
+	remaining := 1.0
+	for i := range providers {
+		p := &providers[i]
+		if limit := p.TrafficCap(); limit < 1 {
+			shares[i] = min(weight(p)/totalWeight, limit)
+			remaining -= shares[i]
+		}
+	}
+	for i := range providers {
+		p := &providers[i]
+		if p.TrafficCap() < 1 {
+			continue
+		}
+		if uncappedWeight > 0 {
+			shares[i] = remaining * weight(p) / uncappedWeight
+		} else {
+			shares[i] = 0
+		}
+	}
+	return shares
+}
+
+// selectAmongServing picks one of the providers at idxs that all serve the
+// requested model. Providers whose circuit breaker is open are skipped, so a
+// failing canary sheds its traffic to the others; the rest are chosen by
+// weight, honoring canary traffic caps.
+func (r *Router) selectAmongServing(providers []models.Provider, idxs []int) *models.Provider {
+	if len(idxs) == 1 {
+		return &providers[idxs[0]]
+	}
+
+	candidates := make([]int, 0, len(idxs))
+	for _, i := range idxs {
+		if r.IsProviderHealthy(providers[i].ID) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = idxs
+	}
+
+	serving := make([]models.Provider, len(candidates))
+	for j, i := range candidates {
+		serving[j] = providers[i]
+	}
+	chosen := r.selectWeighted(serving)
+	for _, i := range candidates {
+		if providers[i].ID == chosen.ID {
+			return &providers[i]
+		}
+	}
+	return &providers[candidates[0]]
+}
@@ -0,0 +1,119 @@
+package router
+
+import (
+	"testing"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficShares_NoCanaryKeepsWeights(t *testing.T) {
+	assert.Equal(t, []float64{1, 3}, trafficShares(weightedProviders(1, 3)))
+}
+
+func TestTrafficShares_CapsCanary(t *testing.T) {
+	providers := weightedProviders(1, 1)
+	providers[1].TrafficPercentage = 5
+
+	shares := trafficShares(providers)
+	assert.InDelta(t, 0.95, shares[0], 1e-9)
+	assert.InDelta(t, 0.05, shares[1], 1e-9)
+}
+
+func TestTrafficShares_CapAboveWeightedShareHasNoEffect(t *testing.T) {
+	providers := weightedProviders(3, 1)
+	providers[1].TrafficPercentage = 50 // weighted share is already 25%
+
+	shares := trafficShares(providers)
+	assert.InDelta(t, 0.75, shares[0], 1e-9)
+	assert.InDelta(t, 0.25, shares[1], 1e-9)
+}
+
+func TestTrafficShares_ZeroWeightsStartEqual(t *testing.T) {
+	providers := weightedProviders(0, 0, 0)
+	providers[2].TrafficPercentage = 10
+
+	shares := trafficShares(providers)
+	assert.InDelta(t, 0.45, shares[0], 1e-9)
+	assert.InDelta(t, 0.45, shares[1], 1e-9)
+	assert.InDelta(t, 0.10, shares[2], 1e-9)
+}
+
+func TestSelectWeighted_CanaryRampFollowsCap(t *testing.T) {
+	providers := weightedProviders(1, 1)
+	providers[1].TrafficPercentage = 10
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	r.SetRandomSource(NewSeededRandom(11))
+
+	const draws = 10000
+	canary := 0
+	for i := 0; i < draws; i++ {
+		if r.selectWeighted(providers).Name == "b" {
+			canary++
+		}
+	}
+	assert.InDelta(t, 0.10, float64(canary)/draws, 0.02)
+}
+
+func TestRoute_SharedModelSplitsAndShedsUnhealthyCanary(t *testing.T) {
+	stable := models.Provider{Name: "stable", IsActive: true, Weight: 1}
+	stable.ID = uuid.New()
+	canary := models.Provider{Name: "canary", IsActive: true, Weight: 1, TrafficPercentage: 20}
+	canary.ID = uuid.New()
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{stable, canary}}, nil)
+	r.modelRepo = &mockModelRepo{models: map[uuid.UUID][]models.Model{
+		stable.ID: {{ProviderID: stable.ID, Name: "house-model", IsActive: true}},
+		canary.ID: {{ProviderID: canary.ID, Name: "house-model", IsActive: true}},
+	}}
+
+	r.SetRandomSource(fixedRandom{f: 0.9})
+	p, _, err := r.Route(t.Context(), "house-model")
+	assert.NoError(t, err)
+	assert.Equal(t, "canary", p.Name, "draws past the stable share go to the canary")
+
+	r.SetRandomSource(fixedRandom{f: 0.5})
+	p, _, err = r.Route(t.Context(), "house-model")
+	assert.NoError(t, err)
+	assert.Equal(t, "stable", p.Name)
+
+	for i := 0; i < 10; i++ {
+		r.MarkProviderFailure(canary.ID)
+	}
+	r.SetRandomSource(fixedRandom{f: 0.9})
+	p, _, err = r.Route(t.Context(), "house-model")
+	assert.NoError(t, err)
+	assert.Equal(t, "stable", p.Name, "an open circuit sheds the canary's traffic")
+}
+
+func TestRoute_CanarySplitAppliesToPatternAndHeuristicMatches(t *testing.T) {
+	for name, setup := range map[string]func(p *models.Provider){
+		"pattern":   func(p *models.Provider) { p.ModelPatterns = []byte(`["house-*"]`) },
+		"heuristic": func(p *models.Provider) { p.Type = "openai" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			stable := models.Provider{Name: "stable", IsActive: true, Weight: 1}
+			stable.ID = uuid.New()
+			canary := models.Provider{Name: "canary", IsActive: true, Weight: 1, TrafficPercentage: 20}
+			canary.ID = uuid.New()
+			setup(&stable)
+			setup(&canary)
+			r := newTestRouter(&mockProviderRepo{providers: []models.Provider{stable, canary}}, nil)
+			model := "house-model"
+			if name == "heuristic" {
+				model = "gpt-4o"
+			}
+
+			r.SetRandomSource(fixedRandom{f: 0.5})
+			p, _, err := r.Route(t.Context(), model)
+			assert.NoError(t, err)
+			assert.Equal(t, "stable", p.Name)
+
+			r.SetRandomSource(fixedRandom{f: 0.9})
+			p, _, err = r.Route(t.Context(), model)
+			assert.NoError(t, err)
+			assert.Equal(t, "canary", p.Name, "draws past the stable share go to the canary")
+		})
+	}
+}
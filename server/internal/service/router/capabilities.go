@@ -0,0 +1,118 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/google/uuid"
+)
+
+// ErrProviderNotFound is returned when a provider ID does not exist.
+var ErrProviderNotFound = errors.New("provider not found")
+
+// providerLookupError reports a missing provider as ErrProviderNotFound and
+// passes database failures through unchanged.
+func providerLookupError(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrProviderNotFound
+	}
+	return err
+}
+
+// Names reported in ProviderCapabilities.Unverified.
+const (
+	capabilityStreaming  = "streaming"
+	capabilityVision     = "vision"
+	capabilityTools      = "tools"
+	capabilityEmbeddings = "embeddings"
+	capabilityMaxContext = "max_context"
+)
+
+// ProviderCapabilities summarizes what a provider can serve so front-ends can
+// disable unsupported options. Capabilities nothing declares are reported as
+// false and listed in Unverified.
+type ProviderCapabilities struct {
+	ProviderID   uuid.UUID           `json:"provider_id"`
+	ProviderName string              `json:"provider_name"`
+	Streaming    bool                `json:"streaming"`
+	Vision       bool                `json:"vision"`
+	Tools        bool                `json:"tools"`
+	Embeddings   bool                `json:"embeddings"`
+	MaxContext   int                 `json:"max_context"` // largest model context window; 0 = unknown
+	Unverified   []string            `json:"unverified,omitempty"`
+	Models       []ModelCapabilities `json:"models"`
+}
+
+// ModelCapabilities holds the capability flags operators set on one model.
+type ModelCapabilities struct {
+	Name          string `json:"name"`
+	Streaming     bool   `json:"streaming"`
+	Vision        bool   `json:"vision"`
+	Tools         bool   `json:"tools"`
+	ContextWindow int    `json:"context_window"` // 0 = unknown
+}
+
+// ProviderCapabilities derives a provider's capabilities from the flags on its
+// active models and from what its provider type supports. A provider counts as
+// supporting vision or tools when any of its models does.
+func (r *Router) ProviderCapabilities(ctx context.Context, id uuid.UUID) (*ProviderCapabilities, error) {
+	p, err := r.providerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, providerLookupError(err)
+	}
+	dbModels, err := r.modelRepo.GetByProviderSorted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := &ProviderCapabilities{
+		ProviderID:   p.ID,
+		ProviderName: p.Name,
+		Models:       make([]ModelCapabilities, 0, len(dbModels)),
+	}
+	modelStreaming := false
+	for _, m := range dbModels {
+		if !m.IsActive {
+			continue
+		}
+		caps.Models = append(caps.Models, ModelCapabilities{
+			Name:          m.Name,
+			Streaming:     m.SupportsStreaming,
+			Vision:        m.SupportsVision,
+			Tools:         m.SupportsTools,
+			ContextWindow: m.ContextWindow,
+		})
+		modelStreaming = modelStreaming || m.SupportsStreaming
+		caps.Vision = caps.Vision || m.SupportsVision
+		caps.Tools = caps.Tools || m.SupportsTools
+		if m.ContextWindow > caps.MaxContext {
+			caps.MaxContext = m.ContextWindow
+		}
+	}
+	hasModels := len(caps.Models) > 0
+
+	typeCaps, knownType := provider.BuiltinCapabilities(p.ClientType())
+	switch {
+	case knownType:
+		caps.Streaming = typeCaps[provider.CapStream] && (modelStreaming || !hasModels)
+	case hasModels:
+		caps.Streaming = modelStreaming
+	default:
+		caps.Unverified = append(caps.Unverified, capabilityStreaming)
+	}
+	if !hasModels {
+		caps.Unverified = append(caps.Unverified, capabilityVision, capabilityTools)
+	}
+	if knownType {
+		caps.Embeddings = typeCaps[provider.CapEmbeddings]
+	} else {
+		caps.Unverified = append(caps.Unverified, capabilityEmbeddings)
+	}
+	if caps.MaxContext == 0 {
+		caps.Unverified = append(caps.Unverified, capabilityMaxContext)
+	}
+	return caps, nil
+}
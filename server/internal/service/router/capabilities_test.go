@@ -0,0 +1,81 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderCapabilities_AggregatesModelFlags(t *testing.T) {
+	p := models.Provider{Name: "openai", IsActive: true}
+	p.ID = uuid.New()
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{p}}, nil)
+	r.modelRepo.(*mockModelRepo).models[p.ID] = []models.Model{
+		{Name: "gpt-4o", SupportsStreaming: true, SupportsVision: true, SupportsTools: true, ContextWindow: 128000, IsActive: true},
+		{Name: "gpt-3.5-turbo", SupportsStreaming: true, ContextWindow: 16385, IsActive: true},
+		{Name: "retired", SupportsVision: true, ContextWindow: 1000000, IsActive: false},
+	}
+
+	caps, err := r.ProviderCapabilities(context.Background(), p.ID)
+	require.NoError(t, err)
+	assert.True(t, caps.Streaming)
+	assert.True(t, caps.Vision)
+	assert.True(t, caps.Tools)
+	assert.True(t, caps.Embeddings, "openai serves embeddings")
+	assert.Equal(t, 128000, caps.MaxContext, "inactive models are ignored")
+	assert.Empty(t, caps.Unverified)
+	assert.Len(t, caps.Models, 2)
+}
+
+func TestProviderCapabilities_UnknownDefaultsToUnverified(t *testing.T) {
+	p := models.Provider{Name: "my-gateway", IsActive: true}
+	p.ID = uuid.New()
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{p}}, nil)
+
+	caps, err := r.ProviderCapabilities(context.Background(), p.ID)
+	require.NoError(t, err)
+	assert.False(t, caps.Streaming)
+	assert.False(t, caps.Vision)
+	assert.False(t, caps.Tools)
+	assert.False(t, caps.Embeddings)
+	assert.Zero(t, caps.MaxContext)
+	assert.ElementsMatch(t, []string{"streaming", "vision", "tools", "embeddings", "max_context"}, caps.Unverified)
+	assert.Empty(t, caps.Models)
+}
+
+func TestProviderCapabilities_KnownTypeWithoutEmbeddings(t *testing.T) {
+	p := models.Provider{Name: "anthropic", IsActive: true}
+	p.ID = uuid.New()
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{p}}, nil)
+	r.modelRepo.(*mockModelRepo).models[p.ID] = []models.Model{
+		{Name: "claude-3-haiku", SupportsStreaming: true, SupportsTools: true, ContextWindow: 200000, IsActive: true},
+	}
+
+	caps, err := r.ProviderCapabilities(context.Background(), p.ID)
+	require.NoError(t, err)
+	assert.True(t, caps.Streaming)
+	assert.False(t, caps.Vision)
+	assert.True(t, caps.Tools)
+	assert.False(t, caps.Embeddings)
+	assert.Empty(t, caps.Unverified, "flags on configured models count as verified")
+}
+
+func TestProviderCapabilities_NotFound(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	_, err := r.ProviderCapabilities(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrProviderNotFound)
+}
+
+func TestProviderLookupError(t *testing.T) {
+	assert.ErrorIs(t, providerLookupError(repository.ErrNotFound), ErrProviderNotFound)
+
+	dbErr := errors.New("connection refused")
+	assert.Equal(t, dbErr, providerLookupError(dbErr), "database failures are not reported as not found")
+}
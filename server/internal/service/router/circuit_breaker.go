@@ -260,6 +260,35 @@ func (cb *CircuitBreaker) GetState(providerID uuid.UUID) (CircuitState, int) {
 	return c.state, c.consecutiveErrors
 }
 
+// CircuitSnapshot is the observed state of one provider's circuit.
+type CircuitSnapshot struct {
+	ProviderID        uuid.UUID `json:"provider_id"`
+	ProviderName      string    `json:"provider_name"`
+	State             string    `json:"state"`
+	ConsecutiveErrors int       `json:"consecutive_errors"`
+}
+
+// Snapshot returns the state of every provider circuit seen so far.
+func (cb *CircuitBreaker) Snapshot() []CircuitSnapshot {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	out := make([]CircuitSnapshot, 0, len(cb.circuits))
+	for id, c := range cb.circuits {
+		state := c.state
+		if state == CircuitOpen && time.Since(c.openedAt) >= cb.cfg.RecoveryTimeout {
+			state = CircuitHalfOpen
+		}
+		out = append(out, CircuitSnapshot{
+			ProviderID:        id,
+			ProviderName:      c.providerName,
+			State:             state.String(),
+			ConsecutiveErrors: c.consecutiveErrors,
+		})
+	}
+	return out
+}
+
 // Reset forces a circuit back to closed state. Useful for manual recovery via admin API.
 func (cb *CircuitBreaker) Reset(providerID uuid.UUID) {
 	cb.mu.Lock()
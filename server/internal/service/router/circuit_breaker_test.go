@@ -191,6 +191,30 @@ func TestCircuitBreaker_IndependentProviders(t *testing.T) {
 	}
 }
 
+func TestCircuitBreaker_Snapshot(t *testing.T) {
+	cb := newTestCB(2, 5*time.Second, 1)
+	tripped := uuid.New()
+	healthy := uuid.New()
+
+	cb.RecordFailure(tripped, "provider-1")
+	cb.RecordFailure(tripped, "provider-1")
+	cb.RecordSuccess(healthy, "provider-2")
+
+	states := map[string]CircuitSnapshot{}
+	for _, s := range cb.Snapshot() {
+		states[s.ProviderName] = s
+	}
+	if len(states) != 2 {
+		t.Fatalf("expected 2 circuits, got %d", len(states))
+	}
+	if s := states["provider-1"]; s.State != "open" || s.ConsecutiveErrors != 2 || s.ProviderID != tripped {
+		t.Errorf("unexpected snapshot for provider-1: %+v", s)
+	}
+	if s := states["provider-2"]; s.State != "closed" {
+		t.Errorf("expected provider-2 closed, got %q", s.State)
+	}
+}
+
 func TestCircuitState_String(t *testing.T) {
 	tests := []struct {
 		state    CircuitState
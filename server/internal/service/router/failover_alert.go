@@ -0,0 +1,192 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/pkg/sanitize"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AlertTypeProviderFailover is raised on the primary provider of a fallback
+// chain when a request had to be served by a later provider.
+const AlertTypeProviderFailover = "provider_failover"
+
+// failoverAlertTimeout bounds recording and delivering one alert; it runs
+// off the request path.
+const failoverAlertTimeout = 15 * time.Second
+
+// FailoverAlerter raises, finds and resolves operator alerts.
+// health.AlertNotifier implements it.
+type FailoverAlerter interface {
+	Raise(ctx context.Context, targetType string, targetID uuid.UUID, alertType, message string) (uuid.UUID, error)
+	ResolveAlert(ctx context.Context, alertID uuid.UUID) error
+	FindOpenAlert(ctx context.Context, targetType string, targetID uuid.UUID, alertType string) (uuid.UUID, error)
+}
+
+// failoverKey identifies a failover episode: a model whose chain primary
+// could not serve it.
+type failoverKey struct {
+	model   string
+	primary uuid.UUID
+}
+
+// failoverAlert is an open episode. id stays nil until the alert is stored.
+type failoverAlert struct {
+	id       uuid.UUID
+	resolved bool
+}
+
+// failoverAlerts raises one alert per failover episode and resolves it once
+// the primary serves the model again. Episodes are tracked per instance; an
+// alert already stored for the primary, raised before a restart or by another
+// instance, is adopted rather than duplicated.
+type failoverAlerts struct {
+	alerter FailoverAlerter
+	mu      sync.Mutex
+	open    map[failoverKey]*failoverAlert
+	checked map[uuid.UUID]bool // primaries whose stored alerts were looked up
+}
+
+// SetFailoverAlerter enables alerts when a fallback chain routes a model away
+// from its primary provider. Call before the router starts serving requests.
+func (r *Router) SetFailoverAlerter(a FailoverAlerter) {
+	if a == nil {
+		r.failover = nil
+		return
+	}
+	r.failover = &failoverAlerts{alerter: a, open: make(map[failoverKey]*failoverAlert), checked: make(map[uuid.UUID]bool)}
+}
+
+// failedOver raises an alert on primary unless one is already open for the
+// model. The alert is recorded and delivered in the background.
+func (r *Router) failedOver(model string, primary, served *models.Provider, cause error) {
+	f := r.failover
+	if f == nil {
+		return
+	}
+	k := failoverKey{model: strings.ToLower(model), primary: primary.ID}
+	f.mu.Lock()
+	if _, ok := f.open[k]; ok {
+		f.mu.Unlock()
+		return
+	}
+	a := &failoverAlert{}
+	f.open[k] = a
+	f.checked[primary.ID] = true
+	f.mu.Unlock()
+
+	msg := fmt.Sprintf("Model %s failed over from provider %s to %s", model, primary.Name, served.Name)
+	if cause != nil {
+		msg += ": " + sanitize.TruncateErrorMessage(cause.Error())
+	}
+	r.logger.Warn("fallback chain primary failed, alerting",
+		zap.String("model", sanitize.LogValue(model)),
+		zap.String("primary", primary.Name),
+		zap.String("served_by", served.Name),
+	)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), failoverAlertTimeout)
+		defer cancel()
+		id, err := r.raiseFailover(ctx, f, primary, msg)
+		f.mu.Lock()
+		if err != nil {
+			if f.open[k] == a {
+				delete(f.open, k) // let the next failover try again
+			}
+			f.mu.Unlock()
+			r.logger.Error("failed to raise failover alert", zap.String("primary", primary.Name), zap.Error(err))
+			return
+		}
+		a.id = id
+		resolved := a.resolved
+		f.mu.Unlock()
+		if resolved {
+			r.resolveFailover(ctx, f, id)
+		}
+	}()
+}
+
+// primaryServed resolves the open failover alert of model on primary, if any.
+func (r *Router) primaryServed(model string, primary *models.Provider) {
+	f := r.failover
+	if f == nil {
+		return
+	}
+	k := failoverKey{model: strings.ToLower(model), primary: primary.ID}
+	var id uuid.UUID
+	f.mu.Lock()
+	a, ok := f.open[k]
+	if ok {
+		delete(f.open, k)
+		a.resolved = true
+		id = a.id
+	}
+	adopt := !ok && !f.checked[primary.ID]
+	f.checked[primary.ID] = true
+	f.mu.Unlock()
+	if adopt {
+		go r.resolveStoredFailover(f, primary)
+		return
+	}
+	if id == uuid.Nil {
+		return // none open, or the raising goroutine resolves it once stored
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), failoverAlertTimeout)
+		defer cancel()
+		r.resolveFailover(ctx, f, id)
+	}()
+}
+
+// raiseFailover returns the failover alert already open on primary, or raises
+// a new one.
+func (r *Router) raiseFailover(ctx context.Context, f *failoverAlerts, primary *models.Provider, msg string) (uuid.UUID, error) {
+	id, err := f.alerter.FindOpenAlert(ctx, "provider", primary.ID, AlertTypeProviderFailover)
+	if err != nil {
+		r.logger.Warn("failed to look up open failover alert", zap.String("primary", primary.Name), zap.Error(err))
+	}
+	if id != uuid.Nil {
+		return id, nil
+	}
+	return f.alerter.Raise(ctx, "provider", primary.ID, AlertTypeProviderFailover, msg)
+}
+
+// resolveStoredFailover resolves a failover alert on primary that this
+// instance does not track, left open by an earlier run or another instance,
+// the first time primary serves after startup. An alert adopted meanwhile by
+// a new failover on this instance is left open.
+func (r *Router) resolveStoredFailover(f *failoverAlerts, primary *models.Provider) {
+	ctx, cancel := context.WithTimeout(context.Background(), failoverAlertTimeout)
+	defer cancel()
+	id, err := f.alerter.FindOpenAlert(ctx, "provider", primary.ID, AlertTypeProviderFailover)
+	if err != nil {
+		r.logger.Warn("failed to look up open failover alert", zap.String("primary", primary.Name), zap.Error(err))
+		return
+	}
+	if id == uuid.Nil {
+		return
+	}
+	f.mu.Lock()
+	for k := range f.open {
+		if k.primary == primary.ID {
+			f.mu.Unlock()
+			return
+		}
+	}
+	f.mu.Unlock()
+	r.resolveFailover(ctx, f, id)
+}
+
+func (r *Router) resolveFailover(ctx context.Context, f *failoverAlerts, id uuid.UUID) {
+	if err := f.alerter.ResolveAlert(ctx, id); err != nil {
+		r.logger.Error("failed to resolve failover alert", zap.String("alert_id", id.String()), zap.Error(err))
+	}
+}
@@ -0,0 +1,251 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/pkg/sanitize"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// matchFallbackChain returns the highest-priority enabled chain whose pattern
+// matches modelName, or nil when none applies.
+func (r *Router) matchFallbackChain(ctx context.Context, modelName string) *models.FallbackChain {
+	if r.fallbackRepo == nil {
+		return nil
+	}
+	chains := r.fallbackChains.get(ctx, r)
+
+	modelLower := strings.ToLower(modelName)
+	for i := range chains {
+		if matchesGlobPattern(modelLower, strings.ToLower(chains[i].ModelPattern)) {
+			return &chains[i]
+		}
+	}
+	return nil
+}
+
+// fallbackChainsRefresh bounds how long an instance routes with a stale
+// fallback chain after another instance changes it.
+const fallbackChainsRefresh = 30 * time.Second
+
+// InvalidateFallbackChains makes the next request reload the fallback chains.
+// Call it after creating, updating or deleting a chain.
+func (r *Router) InvalidateFallbackChains() {
+	r.fallbackChains.invalidate()
+}
+
+// fallbackChainCache caches the enabled fallback chains for
+// fallbackChainsRefresh so routing does not query the database on every
+// request. Like routeOverrides, one lookup reloads a stale cache outside the
+// mutex while the others wait for it.
+type fallbackChainCache struct {
+	sf singleflight.Group
+
+	mu       sync.Mutex
+	chains   []models.FallbackChain
+	loadedAt time.Time
+	gen      uint64 // bumped by invalidate; a load that raced it stays stale
+}
+
+// get returns the enabled chains in priority order, reloading them from the
+// router's repository when stale.
+func (c *fallbackChainCache) get(ctx context.Context, r *Router) []models.FallbackChain {
+	c.mu.Lock()
+	stale := c.loadedAt.IsZero() || time.Since(c.loadedAt) >= fallbackChainsRefresh
+	c.mu.Unlock()
+	if stale {
+		_, _, _ = c.sf.Do("load", func() (interface{}, error) {
+			c.load(context.WithoutCancel(ctx), r)
+			return nil, nil
+		})
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.chains
+}
+
+func (c *fallbackChainCache) load(ctx context.Context, r *Router) {
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+
+	chains, err := r.fallbackRepo.GetActive(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.loadedAt = time.Now()
+	}
+	if err != nil {
+		// Keep routing with the last known chains until the next refresh.
+		r.logger.Warn("failed to load fallback chains", zap.Error(err))
+		return
+	}
+	c.chains = chains
+}
+
+// invalidate makes the next lookup reload the chains.
+func (c *fallbackChainCache) invalidate() {
+	c.mu.Lock()
+	c.loadedAt = time.Time{}
+	c.gen++
+	c.mu.Unlock()
+}
+
+// fallbackOrder returns providers in the order they should be attempted for
+// modelName. A matching fallback chain wins; providers it does not list are
+// dropped. Without a chain the order is priority-based.
+func (r *Router) fallbackOrder(ctx context.Context, modelName string, providers []models.Provider) []models.Provider {
+	chain := r.matchFallbackChain(ctx, modelName)
+	if chain == nil {
+		ordered := make([]models.Provider, len(providers))
+		copy(ordered, providers)
+		sortByPriority(ordered)
+		return ordered
+	}
+
+	ordered := make([]models.Provider, 0, len(chain.ProviderIDs))
+	for _, id := range chain.ProviderUUIDs() {
+		for i := range providers {
+			if providers[i].ID == id {
+				ordered = append(ordered, providers[i])
+				break
+			}
+		}
+	}
+	return ordered
+}
+
+// ExecuteChatWithFallback runs a chat request through the fallback chain
+// matching req.Model. Providers are attempted in chain order until one
+// succeeds; p and apiKey are only used if p appears in the chain. Without a
+// matching chain it behaves exactly like ExecuteChat on p. The provider that
+// served the request is reported in ChatResult.Provider. Serving from a later
+// provider raises a failover alert on the chain's primary, resolved once the
+// primary serves the model again. Each attempt holds a slot in its
+// provider's request queue only while that provider is called; a saturated
+// provider counts as a failed attempt.
+func (r *Router) ExecuteChatWithFallback(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, maxRetries int) (*ChatResult, error) {
+	var res *ChatResult
+	served, err := r.runFallbackChain(ctx, p, apiKey, req, func(candidate *models.Provider, key *models.ProviderAPIKey, req *provider.ChatRequest) error {
+		var err error
+		res, err = r.ExecuteChatInSlot(ctx, candidate, key, req, maxRetries)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Provider = served
+	return res, nil
+}
+
+// ExecuteStreamChatWithFallback establishes a stream through the fallback
+// chain matching req.Model, trying providers in chain order like
+// ExecuteChatWithFallback until one opens a stream. Failover only happens
+// before the stream is open: a stream that fails midway is not resumed on
+// another provider. The provider that opened the stream is reported in
+// StreamResult.Provider and keeps its queue slot until StreamResult.Release.
+func (r *Router) ExecuteStreamChatWithFallback(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, maxRetries int) (*StreamResult, error) {
+	var res *StreamResult
+	served, err := r.runFallbackChain(ctx, p, apiKey, req, func(candidate *models.Provider, key *models.ProviderAPIKey, req *provider.ChatRequest) error {
+		var err error
+		res, err = r.ExecuteStreamChatInSlot(ctx, candidate, key, req, maxRetries)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Provider = served
+	return res, nil
+}
+
+// runFallbackChain calls attempt on each provider of the chain matching
+// req.Model until one succeeds and returns that provider. Without a matching
+// chain attempt runs once on p.
+func (r *Router) runFallbackChain(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, attempt func(candidate *models.Provider, key *models.ProviderAPIKey, req *provider.ChatRequest) error) (*models.Provider, error) {
+	chain := r.matchFallbackChain(ctx, req.Model)
+	if chain == nil {
+		if err := attempt(p, apiKey, req); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+
+	providers, err := r.routableProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ordered := r.fallbackOrder(ctx, req.Model, providers)
+	if len(ordered) == 0 {
+		return nil, errors.New("no active providers in fallback chain " + chain.Name)
+	}
+
+	var lastErr, primaryErr error
+	for i := range ordered {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		candidate := &ordered[i]
+
+		key := apiKey
+		if candidate.ID != p.ID {
+			key = nil
+		}
+		if candidate.RequiresAPIKey && key == nil {
+			if key, err = r.selectAPIKey(ctx, candidate.ID); err != nil {
+				lastErr = err
+				if i == 0 {
+					primaryErr = err
+				}
+				continue
+			}
+		}
+
+		err := attempt(candidate, key, withOutputTokenLimit(candidate, req))
+		if err == nil {
+			if i == 0 {
+				r.primaryServed(req.Model, candidate)
+			} else {
+				r.failedOver(req.Model, &ordered[0], candidate, primaryErr)
+			}
+			return candidate, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		lastErr = err
+		if i == 0 {
+			primaryErr = err
+		}
+		r.logger.Warn("provider in fallback chain failed, trying next",
+			zap.Error(err),
+			zap.String("chain", chain.Name),
+			zap.String("provider", candidate.Name),
+			zap.String("model", sanitize.LogValue(req.Model)),
+		)
+	}
+
+	return nil, lastErr
+}
+
+// withOutputTokenLimit returns req with max_tokens resolved against p's
+// default and ceiling, so a fallback provider never receives more than it
+// allows. req is copied only when the value changes.
+func withOutputTokenLimit(p *models.Provider, req *provider.ChatRequest) *provider.ChatRequest {
+	maxTokens, _ := p.OutputTokenLimit(req.MaxTokens)
+	if maxTokens == req.MaxTokens {
+		return req
+	}
+	cp := *req
+	cp.MaxTokens = maxTokens
+	return &cp
+}
@@ -0,0 +1,470 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keylessProvider(name, baseURL string, priority int) models.Provider {
+	p := models.Provider{Name: name, BaseURL: baseURL, IsActive: true, Priority: priority, MaxRetries: 1}
+	p.ID = uuid.New()
+	return p
+}
+
+func TestFallbackOrder_ChainOverridesPriority(t *testing.T) {
+	a := keylessProvider("openai", "http://a", 100)
+	b := keylessProvider("openai", "http://b", 50)
+	c := keylessProvider("openai", "http://c", 10)
+	providers := []models.Provider{a, b, c}
+
+	r := newTestRouter(&mockProviderRepo{providers: providers}, nil)
+	r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "gpt",
+		ModelPattern: "gpt-*",
+		ProviderIDs:  models.StringArray{c.ID.String(), a.ID.String()},
+		IsEnabled:    true,
+	}}}
+
+	ordered := r.fallbackOrder(context.Background(), "GPT-4o", providers)
+	require.Len(t, ordered, 2, "providers outside the chain are dropped")
+	assert.Equal(t, c.ID, ordered[0].ID)
+	assert.Equal(t, a.ID, ordered[1].ID)
+
+	ordered = r.fallbackOrder(context.Background(), "claude-3", providers)
+	require.Len(t, ordered, 3)
+	assert.Equal(t, []uuid.UUID{a.ID, b.ID, c.ID}, []uuid.UUID{ordered[0].ID, ordered[1].ID, ordered[2].ID})
+}
+
+func TestMatchFallbackChain_CachesUntilInvalidated(t *testing.T) {
+	a := keylessProvider("openai", "http://a", 100)
+	repo := &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "gpt",
+		ModelPattern: "gpt-*",
+		ProviderIDs:  models.StringArray{a.ID.String()},
+		IsEnabled:    true,
+	}}}
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a}}, nil)
+	r.fallbackRepo = repo
+
+	require.NotNil(t, r.matchFallbackChain(context.Background(), "gpt-4o"))
+	require.NotNil(t, r.matchFallbackChain(context.Background(), "gpt-4o-mini"))
+	assert.Equal(t, 1, repo.loads, "chains are loaded once, not per request")
+
+	repo.chains[0].IsEnabled = false
+	r.InvalidateFallbackChains()
+	assert.Nil(t, r.matchFallbackChain(context.Background(), "gpt-4o"))
+	assert.Equal(t, 2, repo.loads)
+}
+
+func TestExecuteChatWithFallback_WalksChainInOrder(t *testing.T) {
+	var primaryCalls, secondaryCalls atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		primaryCalls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		secondaryCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer secondary.Close()
+
+	a := keylessProvider("openai", primary.URL, 10)
+	b := keylessProvider("openai", secondary.URL, 100)
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+	r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "critical",
+		ModelPattern: "gpt-4o",
+		ProviderIDs:  models.StringArray{a.ID.String(), b.ID.String()},
+		IsEnabled:    true,
+	}}}
+
+	ctx, attempts := WithAttempts(context.Background())
+	req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+	res, err := r.ExecuteChatWithFallback(ctx, &b, nil, req, 3)
+
+	require.NoError(t, err)
+	require.NotNil(t, res.Provider)
+	assert.Equal(t, b.ID, res.Provider.ID)
+	assert.Equal(t, int32(1), primaryCalls.Load(), "chain order puts the primary first despite lower priority")
+	assert.Equal(t, int32(1), secondaryCalls.Load())
+
+	assert.Equal(t, 2, attempts.Count())
+	assert.True(t, attempts.Fallback())
+	last, ok := attempts.Last()
+	require.True(t, ok)
+	assert.Equal(t, b.ID, last.ProviderID)
+	assert.NoError(t, last.Err)
+}
+
+func TestExecuteChatWithFallback_SkipsSaturatedProvider(t *testing.T) {
+	var primaryCalls atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		primaryCalls.Add(1)
+	}))
+	defer primary.Close()
+
+	var r *Router
+	var activeDuringCall []ProviderQueueStat
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		activeDuringCall = r.ProviderQueueStats()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer secondary.Close()
+
+	a := keylessProvider("primary", primary.URL, 10)
+	b := keylessProvider("secondary", secondary.URL, 100)
+	a.Type, b.Type = "openai", "openai"
+	r = newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+	r.SetProviderQueue(ProviderQueueConfig{MaxConcurrent: 1, MaxWait: time.Second})
+	r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "critical",
+		ModelPattern: "gpt-4o",
+		ProviderIDs:  models.StringArray{a.ID.String(), b.ID.String()},
+		IsEnabled:    true,
+	}}}
+
+	// Another request holds the primary's only slot.
+	release, err := r.AcquireProviderSlot(context.Background(), &a)
+	require.NoError(t, err)
+	defer release()
+
+	req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+	res, err := r.ExecuteChatWithFallback(context.Background(), &a, nil, req, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, res.Provider.ID)
+	assert.Zero(t, primaryCalls.Load(), "a saturated provider is not called")
+	assert.Equal(t, []ProviderQueueStat{{ProviderName: "primary", Active: 1}, {ProviderName: "secondary", Active: 1}}, activeDuringCall)
+	assert.Equal(t, []ProviderQueueStat{{ProviderName: "primary", Active: 1}, {ProviderName: "secondary"}}, r.ProviderQueueStats(),
+		"the fallback slot is freed once its call returns")
+}
+
+func TestExecuteStreamChatWithFallback_OpensStreamOnNextProvider(t *testing.T) {
+	var primaryCalls atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		primaryCalls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"id\":\"x\",\"object\":\"chat.completion.chunk\",\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ok\"}}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer secondary.Close()
+
+	a := keylessProvider("primary", primary.URL, 100)
+	b := keylessProvider("secondary", secondary.URL, 10)
+	a.Type, b.Type = "openai", "openai"
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+	r.SetProviderQueue(ProviderQueueConfig{MaxConcurrent: 1, MaxWait: time.Second})
+	r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "critical",
+		ModelPattern: "gpt-4o",
+		ProviderIDs:  models.StringArray{a.ID.String(), b.ID.String()},
+		IsEnabled:    true,
+	}}}
+
+	req := &provider.ChatRequest{Model: "gpt-4o", Stream: true, Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+	res, err := r.ExecuteStreamChatWithFallback(context.Background(), &a, nil, req, 3)
+
+	require.NoError(t, err)
+	require.NotNil(t, res.Provider)
+	assert.Equal(t, b.ID, res.Provider.ID)
+	assert.Equal(t, int32(1), primaryCalls.Load())
+	var text string
+	for chunk := range res.Stream {
+		require.NoError(t, chunk.Error)
+		if len(chunk.Choices) > 0 {
+			text += chunk.Choices[0].Delta.Content
+		}
+	}
+	assert.Equal(t, "ok", text)
+
+	assert.Equal(t, []ProviderQueueStat{{ProviderName: "primary"}, {ProviderName: "secondary", Active: 1}}, r.ProviderQueueStats(),
+		"the serving provider's slot is held for the stream")
+	res.Release()
+	res.Release()
+	assert.Equal(t, []ProviderQueueStat{{ProviderName: "primary"}, {ProviderName: "secondary"}}, r.ProviderQueueStats())
+}
+
+type fakeFailoverAlerter struct {
+	mu       sync.Mutex
+	raised   []string
+	targets  []uuid.UUID
+	resolved []uuid.UUID
+	ids      []uuid.UUID
+	stored   uuid.UUID // open alert left by an earlier run
+	lookups  int
+}
+
+func (f *fakeFailoverAlerter) FindOpenAlert(context.Context, string, uuid.UUID, string) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lookups++
+	return f.stored, nil
+}
+
+func (f *fakeFailoverAlerter) Raise(_ context.Context, _ string, targetID uuid.UUID, _, message string) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := uuid.New()
+	f.raised = append(f.raised, message)
+	f.targets = append(f.targets, targetID)
+	f.ids = append(f.ids, id)
+	return id, nil
+}
+
+func (f *fakeFailoverAlerter) ResolveAlert(_ context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resolved = append(f.resolved, id)
+	if f.stored == id {
+		f.stored = uuid.Nil
+	}
+	return nil
+}
+
+func (f *fakeFailoverAlerter) counts() (raised, resolved int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.raised), len(f.resolved)
+}
+
+func TestExecuteChatWithFallback_AlertsOnFailoverUntilPrimaryRecovers(t *testing.T) {
+	var primaryDown atomic.Bool
+	primaryDown.Store(true)
+	ok := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		ok(w)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { ok(w) }))
+	defer secondary.Close()
+
+	a := keylessProvider("openai", primary.URL, 100)
+	a.Name, a.Type = "primary", "openai"
+	b := keylessProvider("openai", secondary.URL, 10)
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+	r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "critical",
+		ModelPattern: "gpt-4o",
+		ProviderIDs:  models.StringArray{a.ID.String(), b.ID.String()},
+		IsEnabled:    true,
+	}}}
+	alerter := &fakeFailoverAlerter{}
+	r.SetFailoverAlerter(alerter)
+
+	req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+	for i := 0; i < 2; i++ {
+		_, err := r.ExecuteChatWithFallback(context.Background(), &a, nil, req, 3)
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { raised, _ := alerter.counts(); return raised == 1 }, time.Second, 10*time.Millisecond)
+	alerter.mu.Lock()
+	assert.Equal(t, a.ID, alerter.targets[0], "raised on the skipped primary")
+	assert.Contains(t, alerter.raised[0], "gpt-4o")
+	assert.Contains(t, alerter.raised[0], "primary")
+	alerter.mu.Unlock()
+
+	primaryDown.Store(false)
+	_, err := r.ExecuteChatWithFallback(context.Background(), &a, nil, req, 3)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { _, resolved := alerter.counts(); return resolved == 1 }, time.Second, 10*time.Millisecond)
+	alerter.mu.Lock()
+	assert.Equal(t, alerter.ids[0], alerter.resolved[0])
+	alerter.mu.Unlock()
+	raised, _ := alerter.counts()
+	assert.Equal(t, 1, raised, "one alert per failover episode")
+}
+
+func TestExecuteChatWithFallback_AdoptsStoredFailoverAlert(t *testing.T) {
+	var primaryDown atomic.Bool
+	ok := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		ok(w)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { ok(w) }))
+	defer secondary.Close()
+
+	a := keylessProvider("openai", primary.URL, 100)
+	b := keylessProvider("openai", secondary.URL, 10)
+	newRouter := func(alerter *fakeFailoverAlerter) *Router {
+		r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+		r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+			Name:         "critical",
+			ModelPattern: "gpt-4o",
+			ProviderIDs:  models.StringArray{a.ID.String(), b.ID.String()},
+			IsEnabled:    true,
+		}}}
+		r.SetFailoverAlerter(alerter)
+		return r
+	}
+	req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+
+	// A failover after a restart reuses the alert the earlier run left open.
+	stored := uuid.New()
+	alerter := &fakeFailoverAlerter{stored: stored}
+	r := newRouter(alerter)
+	primaryDown.Store(true)
+	_, err := r.ExecuteChatWithFallback(context.Background(), &a, nil, req, 3)
+	require.NoError(t, err)
+	primaryDown.Store(false)
+	require.Eventually(t, func() bool {
+		_, err := r.ExecuteChatWithFallback(context.Background(), &a, nil, req, 3)
+		require.NoError(t, err)
+		_, resolved := alerter.counts()
+		return resolved == 1
+	}, time.Second, 10*time.Millisecond)
+	raised, _ := alerter.counts()
+	assert.Equal(t, 0, raised, "no duplicate alert")
+	assert.Equal(t, stored, alerter.resolved[0])
+
+	// A primary that recovered while the instance was down resolves it too,
+	// looking it up only once.
+	stored = uuid.New()
+	alerter = &fakeFailoverAlerter{stored: stored}
+	r = newRouter(alerter)
+	for i := 0; i < 3; i++ {
+		_, err = r.ExecuteChatWithFallback(context.Background(), &a, nil, req, 3)
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { _, resolved := alerter.counts(); return resolved == 1 }, time.Second, 10*time.Millisecond)
+	alerter.mu.Lock()
+	assert.Equal(t, stored, alerter.resolved[0])
+	assert.Equal(t, 1, alerter.lookups)
+	alerter.mu.Unlock()
+}
+
+func TestExecuteChatWithFallback_NoChainUsesSelectedProvider(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	a := keylessProvider("openai", srv.URL, 10)
+	b := keylessProvider("openai", srv.URL, 100)
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+
+	ctx, attempts := WithAttempts(context.Background())
+	_, err := r.ExecuteChatWithFallback(ctx, &a, nil, &provider.ChatRequest{Model: "gpt-4o"}, 3)
+
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "without a chain no other provider is tried")
+	assert.Equal(t, 1, attempts.Count())
+	assert.False(t, attempts.Fallback())
+}
+
+func TestExecuteChatWithFallback_DeadlineCancelsUpstream(t *testing.T) {
+	upstreamCanceled := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		// The server only notices a client disconnect once the body is read.
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+		close(upstreamCanceled)
+	}))
+	defer slow.Close()
+	var fallbackCalls atomic.Int32
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fallbackCalls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer fallback.Close()
+
+	a := keylessProvider("openai", slow.URL, 100)
+	b := keylessProvider("openai", fallback.URL, 10)
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+	r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "critical",
+		ModelPattern: "gpt-4o",
+		ProviderIDs:  models.StringArray{a.ID.String(), b.ID.String()},
+		IsEnabled:    true,
+	}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := r.ExecuteChatWithFallback(ctx, &a, nil, &provider.ChatRequest{Model: "gpt-4o"}, 3)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	assert.Equal(t, int32(0), fallbackCalls.Load(), "no fallback once the deadline has passed")
+	select {
+	case <-upstreamCanceled:
+	case <-time.After(time.Second):
+		t.Fatal("upstream call not canceled at the deadline")
+	}
+}
+
+func TestExecuteChatWithFallback_ClampsMaxTokensPerProvider(t *testing.T) {
+	var primaryMax, secondaryMax atomic.Int32
+	readMax := func(r *http.Request) int32 {
+		var body struct {
+			MaxTokens int32 `json:"max_tokens"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		return body.MaxTokens
+	}
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryMax.Store(readMax(r))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryMax.Store(readMax(r))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer secondary.Close()
+
+	a := keylessProvider("openai", primary.URL, 100)
+	b := keylessProvider("openai", secondary.URL, 10)
+	b.MaxOutputTokens = 100
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{a, b}}, nil)
+	r.fallbackRepo = &mockFallbackChainRepo{chains: []models.FallbackChain{{
+		Name:         "critical",
+		ModelPattern: "gpt-4o",
+		ProviderIDs:  models.StringArray{a.ID.String(), b.ID.String()},
+		IsEnabled:    true,
+	}}}
+
+	req := &provider.ChatRequest{Model: "gpt-4o", MaxTokens: 500}
+	_, err := r.ExecuteChatWithFallback(context.Background(), &a, nil, req, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(500), primaryMax.Load())
+	assert.Equal(t, int32(100), secondaryMax.Load(), "fallback provider's ceiling applies")
+	assert.Equal(t, 500, req.MaxTokens, "caller's request is not mutated")
+}
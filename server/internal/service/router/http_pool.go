@@ -0,0 +1,113 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Default connection-pool tuning for provider HTTP clients.
+const (
+	defaultProviderMaxIdleConnsPerHost = 32
+	defaultProviderIdleConnTimeout     = 90 * time.Second
+)
+
+// providerHTTPPool caches provider HTTP clients so TCP/TLS connections to an
+// upstream are reused across requests instead of paying a handshake per call.
+// Entries are keyed by provider and egress route (direct or a specific
+// proxy), so proxied and direct traffic never share a Transport. The cached
+// clients carry no credentials: provider API keys are set per request by the
+// provider.Client built around them, which is never cached.
+type providerHTTPPool struct {
+	mu             sync.Mutex
+	clients        map[string]pooledHTTPClient
+	maxIdlePerHost int
+	idleTimeout    time.Duration
+}
+
+// pooledHTTPClient is a cached client plus the version of the route settings
+// (e.g. proxy URL and credentials) it was built from.
+type pooledHTTPClient struct {
+	client  *http.Client
+	version string
+}
+
+func newProviderHTTPPool() *providerHTTPPool {
+	return &providerHTTPPool{
+		clients:        make(map[string]pooledHTTPClient),
+		maxIdlePerHost: defaultProviderMaxIdleConnsPerHost,
+		idleTimeout:    defaultProviderIdleConnTimeout,
+	}
+}
+
+// get returns the client cached under key, building it when missing or when
+// the route settings changed since it was built. A replaced client's idle
+// connections are closed.
+func (p *providerHTTPPool) get(key, version string, build func() *http.Client) *http.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.clients[key]; ok {
+		if entry.version == version {
+			return entry.client
+		}
+		entry.client.CloseIdleConnections()
+	}
+
+	client := build()
+	if t, ok := client.Transport.(*http.Transport); ok {
+		t.MaxIdleConnsPerHost = p.maxIdlePerHost
+		if t.MaxIdleConns > 0 && t.MaxIdleConns < p.maxIdlePerHost {
+			t.MaxIdleConns = p.maxIdlePerHost
+		}
+		t.IdleConnTimeout = p.idleTimeout
+	}
+	p.clients[key] = pooledHTTPClient{client: client, version: version}
+	return client
+}
+
+// evict drops the clients whose key matches and closes their idle
+// connections, so a route that no longer applies stops holding sockets.
+func (p *providerHTTPPool) evict(match func(key string) bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, entry := range p.clients {
+		if match(key) {
+			entry.client.CloseIdleConnections()
+			delete(p.clients, key)
+		}
+	}
+}
+
+// httpPoolKey is the pool key of provider p's clients on an egress route.
+func httpPoolKey(providerID uuid.UUID, providerName, route string) string {
+	return providerID.String() + "/" + providerName + "/" + route
+}
+
+// evictProviderClients drops every pooled client of the provider.
+func (r *Router) evictProviderClients(providerID uuid.UUID) {
+	prefix := providerID.String() + "/"
+	r.httpPool.evict(func(key string) bool { return strings.HasPrefix(key, prefix) })
+}
+
+// InvalidateProxyClients drops the pooled provider clients that send through
+// the proxy. Call after the proxy is updated, toggled or deleted.
+func (r *Router) InvalidateProxyClients(proxyID uuid.UUID) {
+	suffix := "/" + proxyRoute(proxyID)
+	r.httpPool.evict(func(key string) bool { return strings.HasSuffix(key, suffix) })
+}
+
+// SetHTTPPoolLimits tunes the keep-alive pool of provider HTTP clients.
+// Zero values keep the defaults (32 idle connections per host, 90s idle
+// timeout). Call before the router starts serving requests.
+func (r *Router) SetHTTPPoolLimits(maxIdleConnsPerHost int, idleConnTimeout time.Duration) {
+	if maxIdleConnsPerHost > 0 {
+		r.httpPool.maxIdlePerHost = maxIdleConnsPerHost
+	}
+	if idleConnTimeout > 0 {
+		r.httpPool.idleTimeout = idleConnTimeout
+	}
+}
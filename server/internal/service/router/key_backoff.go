@@ -0,0 +1,88 @@
+// Package router provides LLM request routing logic.
+// This file implements the backoff between API key rotation attempts.
+package router
+
+import (
+	"context"
+	"time"
+)
+
+// KeyRetryBackoff spaces out the key rotation of one request. Without it a
+// rate-limited provider sees every key tried back to back, and all of them
+// land in cooldown before a transient limit has had a chance to clear.
+type KeyRetryBackoff struct {
+	// Initial is the delay before the second key; it doubles for each
+	// further key. Zero disables the backoff.
+	Initial time.Duration
+	// Max caps a single delay.
+	Max time.Duration
+	// Budget caps the total delay of one key-rotation loop; once a delay
+	// would exceed it, the remaining keys are not tried. Zero = no cap.
+	Budget time.Duration
+}
+
+// DefaultKeyRetryBackoff returns the default key rotation backoff.
+func DefaultKeyRetryBackoff() KeyRetryBackoff {
+	return KeyRetryBackoff{
+		Initial: 200 * time.Millisecond,
+		Max:     2 * time.Second,
+		Budget:  5 * time.Second,
+	}
+}
+
+// SetKeyRetryBackoff configures the delay between key rotation attempts.
+// Call before the router starts serving requests.
+func (r *Router) SetKeyRetryBackoff(b KeyRetryBackoff) {
+	r.keyBackoff = b
+}
+
+// keyRetryWaiter tracks the backoff spent by one key-rotation loop.
+type keyRetryWaiter struct {
+	cfg   KeyRetryBackoff
+	rng   RandomSource
+	spent time.Duration
+}
+
+func (r *Router) newKeyRetryWaiter() *keyRetryWaiter {
+	return &keyRetryWaiter{cfg: r.keyBackoff, rng: r.rng}
+}
+
+// delay returns the jittered delay before retry attempt (1 = second key): a
+// uniform pick from the upper half of the exponential step, so concurrent
+// requests against the same provider spread out.
+func (w *keyRetryWaiter) delay(attempt int) time.Duration {
+	d := w.cfg.Initial
+	for i := 1; i < attempt && (w.cfg.Max <= 0 || d < w.cfg.Max); i++ {
+		d *= 2
+	}
+	if w.cfg.Max > 0 && d > w.cfg.Max {
+		d = w.cfg.Max
+	}
+	return d/2 + time.Duration(w.rng.Float64()*float64(d/2))
+}
+
+// wait pauses before retry attempt. It returns false, without waiting, when
+// the pause would exceed the retry budget or outlast the request deadline,
+// so the caller gives up with the error it already has. A context canceled
+// while waiting ends the pause early; the caller's context check reports it.
+func (w *keyRetryWaiter) wait(ctx context.Context, attempt int) bool {
+	if w.cfg.Initial <= 0 {
+		return true
+	}
+	d := w.delay(attempt)
+	if w.cfg.Budget > 0 && w.spent+d > w.cfg.Budget {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return false
+	}
+	w.spent += d
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+	return true
+}
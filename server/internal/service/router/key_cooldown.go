@@ -0,0 +1,80 @@
+// Package router provides LLM request routing logic.
+// This file lets operators inspect and lift provider key cooldowns.
+package router
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrProviderKeyNotFound is returned when a provider key ID does not exist.
+var ErrProviderKeyNotFound = errors.New("provider key not found")
+
+// KeyCooldown is a provider key that key selection skips until its recorded
+// failure expires.
+type KeyCooldown struct {
+	Reason    string
+	Remaining time.Duration
+}
+
+// KeyCooldownDetails is KeyCooldowns with the time left on each cooldown.
+func (r *Router) KeyCooldownDetails(ctx context.Context, ids []uuid.UUID) map[uuid.UUID]KeyCooldown {
+	out := make(map[uuid.UUID]KeyCooldown, len(ids))
+	if len(ids) == 0 {
+		return out
+	}
+	if r.redisClient != nil {
+		pipe := r.redisClient.Pipeline()
+		reasons := make([]*redis.StringCmd, len(ids))
+		ttls := make([]*redis.DurationCmd, len(ids))
+		for i, id := range ids {
+			reasons[i] = pipe.Get(ctx, failedKeyPrefix+id.String())
+			ttls[i] = pipe.PTTL(ctx, failedKeyPrefix+id.String())
+		}
+		// A missing key fails its GET with redis.Nil, which Exec reports.
+		_, err := pipe.Exec(ctx)
+		if err == nil || errors.Is(err, redis.Nil) {
+			for i, id := range ids {
+				if reason, err := reasons[i].Result(); err == nil {
+					out[id] = KeyCooldown{Reason: reason, Remaining: max(ttls[i].Val(), 0)}
+				}
+			}
+			return out
+		}
+		r.logger.Debug("redis failed for key cooldown details, using in-memory fallback", zap.Error(err))
+	}
+
+	r.failedKeysMu.RLock()
+	defer r.failedKeysMu.RUnlock()
+	for _, id := range ids {
+		if info, ok := r.failedKeys[id]; ok {
+			if remaining := failedKeyTTL - time.Since(info.FailedAt); remaining > 0 {
+				out[id] = KeyCooldown{Reason: info.Reason, Remaining: remaining}
+			}
+		}
+	}
+	return out
+}
+
+// ClearKeyCooldown lifts a provider key's cooldown so key selection uses it
+// again immediately, e.g. after its plan was upgraded. It reports whether the
+// key was in cooldown.
+func (r *Router) ClearKeyCooldown(ctx context.Context, keyID uuid.UUID) (bool, error) {
+	if _, err := r.providerKeyRepo.GetByID(ctx, keyID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, ErrProviderKeyNotFound
+		}
+		return false, err
+	}
+	_, inCooldown := r.KeyCooldownDetails(ctx, []uuid.UUID{keyID})[keyID]
+	r.ClearKeyFailure(keyID)
+	r.logger.Info("provider key cooldown cleared", zap.String("key_id", keyID.String()), zap.Bool("was_in_cooldown", inCooldown))
+	return inCooldown, nil
+}
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearKeyCooldown(t *testing.T) {
+	keys := aliasedKeys(1, 1)
+	providerID := uuid.New()
+	r := newTestRouter(&mockProviderRepo{}, &mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{providerID: keys}})
+	ctx := context.Background()
+
+	r.MarkKeyFailed(keys[0].ID, "429 rate limit exceeded")
+	r.failedKeys[keys[1].ID] = &FailedKeyInfo{FailedAt: time.Now().Add(-failedKeyTTL - time.Second), Reason: "expired"}
+
+	details := r.KeyCooldownDetails(ctx, []uuid.UUID{keys[0].ID, keys[1].ID})
+	require.Len(t, details, 1, "expired failures are not cooldowns")
+	assert.Equal(t, "429 rate limit exceeded", details[keys[0].ID].Reason)
+	assert.InDelta(t, failedKeyTTL.Seconds(), details[keys[0].ID].Remaining.Seconds(), 1)
+
+	cleared, err := r.ClearKeyCooldown(ctx, keys[0].ID)
+	require.NoError(t, err)
+	assert.True(t, cleared)
+	assert.False(t, r.isKeyTemporarilyFailed(keys[0].ID), "the key rejoins selection immediately")
+
+	cleared, err = r.ClearKeyCooldown(ctx, keys[0].ID)
+	require.NoError(t, err)
+	assert.False(t, cleared, "clearing twice is harmless")
+
+	_, err = r.ClearKeyCooldown(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrProviderKeyNotFound)
+}
@@ -0,0 +1,147 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// keyUsageTTL bounds how stale the cached monthly consumption of a capped
+// provider key may be before it is re-read from usage logs.
+const keyUsageTTL = time.Minute
+
+// keyUsageEntry caches one provider key's consumption for a calendar month.
+type keyUsageEntry struct {
+	usage     repository.ProviderKeyUsageRow
+	month     time.Time
+	fetchedAt time.Time
+}
+
+// SetUsageRepo enables the monthly request and cost caps on provider API keys,
+// measured from the usage logs attributed to each key. Without it caps are
+// not enforced. Call before the router starts serving requests.
+func (r *Router) SetUsageRepo(repo repository.UsageLogRepo) {
+	r.usageRepo = repo
+}
+
+// monthStart returns the first instant of t's calendar month in UTC. Caps
+// reset when it changes.
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// hasMonthlyCap reports whether the key has a request or cost cap configured.
+func hasMonthlyCap(k *models.ProviderAPIKey) bool {
+	return k.MonthlyRequestLimit > 0 || k.MonthlyBudgetUSD > 0
+}
+
+// overMonthlyCap reports whether usage has reached either of the key's caps.
+func overMonthlyCap(k *models.ProviderAPIKey, usage repository.ProviderKeyUsageRow) bool {
+	if k.MonthlyRequestLimit > 0 && usage.Requests >= k.MonthlyRequestLimit {
+		return true
+	}
+	return k.MonthlyBudgetUSD > 0 && usage.Cost >= k.MonthlyBudgetUSD
+}
+
+// KeyMonthlyUsage returns the current month's request and cost totals for
+// the given provider keys, read directly from usage logs. Keys without usage
+// are absent from the result.
+func (r *Router) KeyMonthlyUsage(ctx context.Context, keyIDs []uuid.UUID) (map[uuid.UUID]repository.ProviderKeyUsageRow, error) {
+	out := make(map[uuid.UUID]repository.ProviderKeyUsageRow, len(keyIDs))
+	if r.usageRepo == nil {
+		return out, nil
+	}
+	rows, err := r.usageRepo.AggregateByProviderKeySince(ctx, keyIDs, monthStart(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.ProviderKeyID] = row
+	}
+	return out, nil
+}
+
+// filterCappedKeys drops keys that have used up their monthly request or cost
+// cap. Usage lookup errors fail open so a database hiccup does not take every
+// capped key out of rotation.
+func (r *Router) filterCappedKeys(ctx context.Context, keys []models.ProviderAPIKey) []models.ProviderAPIKey {
+	if r.usageRepo == nil {
+		return keys
+	}
+
+	var capped []uuid.UUID
+	for i := range keys {
+		if hasMonthlyCap(&keys[i]) {
+			capped = append(capped, keys[i].ID)
+		}
+	}
+	if len(capped) == 0 {
+		return keys
+	}
+
+	usage := r.cachedKeyUsage(ctx, capped)
+	available := make([]models.ProviderAPIKey, 0, len(keys))
+	for i := range keys {
+		if u, ok := usage[keys[i].ID]; ok && overMonthlyCap(&keys[i], u) {
+			r.logger.Debug("provider key reached monthly cap",
+				zap.String("key_id", keys[i].ID.String()),
+				zap.Int64("requests", u.Requests),
+				zap.Float64("cost", u.Cost))
+			continue
+		}
+		available = append(available, keys[i])
+	}
+	return available
+}
+
+// cachedKeyUsage returns this month's usage for keyIDs, refreshing entries
+// older than keyUsageTTL or from a previous month in a single query.
+func (r *Router) cachedKeyUsage(ctx context.Context, keyIDs []uuid.UUID) map[uuid.UUID]repository.ProviderKeyUsageRow {
+	now := time.Now()
+	month := monthStart(now)
+	out := make(map[uuid.UUID]repository.ProviderKeyUsageRow, len(keyIDs))
+
+	var stale []uuid.UUID
+	r.keyUsageMu.RLock()
+	for _, id := range keyIDs {
+		if e, ok := r.keyUsage[id]; ok && e.month.Equal(month) && now.Sub(e.fetchedAt) < keyUsageTTL {
+			out[id] = e.usage
+		} else {
+			stale = append(stale, id)
+		}
+	}
+	r.keyUsageMu.RUnlock()
+	if len(stale) == 0 {
+		return out
+	}
+
+	rows, err := r.usageRepo.AggregateByProviderKeySince(ctx, stale, month)
+	if err != nil {
+		r.logger.Warn("failed to load provider key usage", zap.Error(err))
+		return out
+	}
+	fetched := make(map[uuid.UUID]repository.ProviderKeyUsageRow, len(stale))
+	for _, id := range stale {
+		fetched[id] = repository.ProviderKeyUsageRow{ProviderKeyID: id}
+	}
+	for _, row := range rows {
+		fetched[row.ProviderKeyID] = row
+	}
+
+	r.keyUsageMu.Lock()
+	if r.keyUsage == nil {
+		r.keyUsage = make(map[uuid.UUID]keyUsageEntry)
+	}
+	for id, u := range fetched {
+		r.keyUsage[id] = keyUsageEntry{usage: u, month: month, fetchedAt: now}
+		out[id] = u
+	}
+	r.keyUsageMu.Unlock()
+	return out
+}
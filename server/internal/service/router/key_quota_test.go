@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cappedKeyRouter(t *testing.T, rows []repository.ProviderKeyUsageRow, keys ...models.ProviderAPIKey) (*Router, uuid.UUID, *mockUsageLogRepo) {
+	t.Helper()
+	providerID := uuid.New()
+	for i := range keys {
+		keys[i].ProviderID = providerID
+		keys[i].IsActive = true
+		keys[i].Weight = 1
+	}
+	r := newTestRouter(&mockProviderRepo{}, &mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{providerID: keys}})
+	usage := &mockUsageLogRepo{rows: rows}
+	r.SetUsageRepo(usage)
+	return r, providerID, usage
+}
+
+func TestSelectAPIKey_SkipsKeysOverMonthlyCap(t *testing.T) {
+	exhausted := models.ProviderAPIKey{MonthlyRequestLimit: 100}
+	exhausted.ID = uuid.New()
+	spare := models.ProviderAPIKey{MonthlyBudgetUSD: 50}
+	spare.ID = uuid.New()
+
+	r, providerID, _ := cappedKeyRouter(t, []repository.ProviderKeyUsageRow{
+		{ProviderKeyID: exhausted.ID, Requests: 100},
+		{ProviderKeyID: spare.ID, Requests: 10, Cost: 12.5},
+	}, exhausted, spare)
+
+	for i := 0; i < 10; i++ {
+		key, err := r.selectAPIKey(context.Background(), providerID)
+		require.NoError(t, err)
+		assert.Equal(t, spare.ID, key.ID)
+	}
+}
+
+func TestSelectAPIKey_AllKeysCapped(t *testing.T) {
+	k := models.ProviderAPIKey{MonthlyBudgetUSD: 20}
+	k.ID = uuid.New()
+
+	r, providerID, _ := cappedKeyRouter(t, []repository.ProviderKeyUsageRow{
+		{ProviderKeyID: k.ID, Requests: 3, Cost: 20.01},
+	}, k)
+
+	_, err := r.selectAPIKey(context.Background(), providerID)
+	assert.ErrorContains(t, err, "monthly cap")
+}
+
+func TestSelectAPIKey_CachesKeyUsage(t *testing.T) {
+	k := models.ProviderAPIKey{MonthlyRequestLimit: 5}
+	k.ID = uuid.New()
+	uncapped := models.ProviderAPIKey{}
+	uncapped.ID = uuid.New()
+
+	r, providerID, usage := cappedKeyRouter(t, nil, k, uncapped)
+
+	for i := 0; i < 3; i++ {
+		_, err := r.selectAPIKey(context.Background(), providerID)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, usage.queries)
+}
+
+func TestMonthStart(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	got := monthStart(time.Date(2026, 3, 1, 2, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), got)
+}
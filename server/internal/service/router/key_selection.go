@@ -0,0 +1,145 @@
+// Package router provides LLM request routing logic.
+// This file implements the least-used provider key selection mode.
+package router
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// KeySelection decides how a provider key is picked among the eligible keys
+// of the best priority.
+type KeySelection string
+
+const (
+	// KeySelectionWeighted picks a key at random in proportion to its weight.
+	KeySelectionWeighted KeySelection = "weighted"
+	// KeySelectionLeastUsed picks the key with the fewest recent selections
+	// relative to its weight, so a key pool is loaded evenly rather than by
+	// chance.
+	KeySelectionLeastUsed KeySelection = "least_used"
+)
+
+// keyLoadHalfLife is how quickly past selections stop counting towards a
+// key's recent load.
+const keyLoadHalfLife = time.Minute
+
+// keyLoadNegligible is the decayed load below which a key is forgotten, as it
+// barely differs from a key never picked.
+const keyLoadNegligible = 1e-3
+
+// SetKeySelection configures how provider keys are picked. An empty mode
+// keeps KeySelectionWeighted. Recent load is tracked per instance. Call
+// before the router starts serving requests.
+func (r *Router) SetKeySelection(mode KeySelection) {
+	if mode == "" {
+		mode = KeySelectionWeighted
+	}
+	r.keySelection = mode
+}
+
+// pickKey selects one of the eligible keys with the configured mode.
+func (r *Router) pickKey(keys []models.ProviderAPIKey) (*models.ProviderAPIKey, error) {
+	if r.keySelection != KeySelectionLeastUsed || len(keys) == 0 {
+		return selectWeightedKey(r.rng, keys)
+	}
+	return r.keyLoads.pick(r.rng, bestPriorityKeys(keys), time.Now()), nil
+}
+
+// keysReloaded forgets the recent load of the provider's keys that are no
+// longer active, given the keys just loaded for it.
+func (r *Router) keysReloaded(providerID uuid.UUID, keys []models.ProviderAPIKey) {
+	if r.keySelection == KeySelectionLeastUsed {
+		r.keyLoads.prune(providerID, keys, time.Now())
+	}
+}
+
+// keyLoad is a key's recent selection count, decayed to at.
+type keyLoad struct {
+	providerID uuid.UUID
+	count      float64
+	at         time.Time
+}
+
+// keyLoadTracker counts recent selections per key with exponential decay.
+type keyLoadTracker struct {
+	mu    sync.Mutex
+	loads map[uuid.UUID]keyLoad
+}
+
+func newKeyLoadTracker() *keyLoadTracker {
+	return &keyLoadTracker{loads: make(map[uuid.UUID]keyLoad)}
+}
+
+// pick returns the key with the lowest recent load per unit of weight,
+// breaking ties at random, and counts the selection against it. As in
+// weighted mode, keys of weight 0 are only picked when every key has weight 0.
+func (t *keyLoadTracker) pick(rng RandomSource, keys []models.ProviderAPIKey, now time.Time) *models.ProviderAPIKey {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	weighted := false
+	for _, k := range keys {
+		if k.Weight > 0 {
+			weighted = true
+			break
+		}
+	}
+
+	best, ties := math.Inf(1), 0
+	var picked int
+	for i, k := range keys {
+		weight := k.Weight
+		switch {
+		case !weighted:
+			weight = 1
+		case weight <= 0:
+			continue
+		}
+		score := t.decayed(k.ID, now) / weight
+		switch {
+		case score < best-1e-9:
+			best, ties, picked = score, 1, i
+		case score <= best+1e-9:
+			// Reservoir sampling keeps every tied key equally likely.
+			ties++
+			if rng.Intn(ties) == 0 {
+				picked = i
+			}
+		}
+	}
+	id := keys[picked].ID
+	t.loads[id] = keyLoad{providerID: keys[picked].ProviderID, count: t.decayed(id, now) + 1, at: now}
+	return &keys[picked]
+}
+
+// prune drops the loads of the provider's keys missing from active, and of
+// any key whose load has decayed away, so deleted keys are not kept forever.
+func (t *keyLoadTracker) prune(providerID uuid.UUID, active []models.ProviderAPIKey, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keep := make(map[uuid.UUID]bool, len(active))
+	for _, k := range active {
+		keep[k.ID] = true
+	}
+	for id, l := range t.loads {
+		if (l.providerID == providerID && !keep[id]) || t.decayed(id, now) < keyLoadNegligible {
+			delete(t.loads, id)
+		}
+	}
+}
+
+// decayed returns the key's selection count decayed to now.
+func (t *keyLoadTracker) decayed(id uuid.UUID, now time.Time) float64 {
+	l, ok := t.loads[id]
+	if !ok {
+		return 0
+	}
+	return l.count * math.Exp2(-now.Sub(l.at).Seconds()/keyLoadHalfLife.Seconds())
+}
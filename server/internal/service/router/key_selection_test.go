@@ -0,0 +1,110 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aliasedKeys(weights ...float64) []models.ProviderAPIKey {
+	keys := make([]models.ProviderAPIKey, len(weights))
+	for i, w := range weights {
+		keys[i] = models.ProviderAPIKey{Alias: string(rune('a' + i)), Weight: w, IsActive: true}
+		keys[i].ID = uuid.New()
+	}
+	return keys
+}
+
+func TestKeyLoadTracker_SpreadsByWeight(t *testing.T) {
+	keys := aliasedKeys(1, 1, 2)
+	tr := newKeyLoadTracker()
+	now := time.Now()
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		counts[tr.pick(fixedRandom{}, keys, now).Alias]++
+	}
+	assert.Equal(t, map[string]int{"a": 100, "b": 100, "c": 200}, counts, "selections follow weight exactly, not by chance")
+}
+
+func TestKeyLoadTracker_RecentLoadDecays(t *testing.T) {
+	keys := aliasedKeys(1, 1)
+	tr := newKeyLoadTracker()
+	now := time.Now()
+
+	tr.loads[keys[0].ID] = keyLoad{count: 10, at: now.Add(-10 * keyLoadHalfLife)}
+	tr.loads[keys[1].ID] = keyLoad{count: 1, at: now}
+	assert.Equal(t, "a", tr.pick(fixedRandom{}, keys, now).Alias, "load from ten half-lives ago barely counts")
+}
+
+func TestKeyLoadTracker_SkipsZeroWeightKeys(t *testing.T) {
+	tr := newKeyLoadTracker()
+	now := time.Now()
+
+	keys := aliasedKeys(0, 1)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "b", tr.pick(fixedRandom{}, keys, now).Alias, "a weight-0 key takes no traffic")
+	}
+
+	keys = aliasedKeys(0, 0)
+	counts := map[string]int{}
+	for i := 0; i < 4; i++ {
+		counts[tr.pick(fixedRandom{}, keys, now).Alias]++
+	}
+	assert.Equal(t, map[string]int{"a": 2, "b": 2}, counts, "when every key has weight 0 they share the load")
+}
+
+func TestKeyLoadTracker_PruneForgetsRemovedKeys(t *testing.T) {
+	keys := aliasedKeys(1, 1)
+	providerID, otherID := uuid.New(), uuid.New()
+	for i := range keys {
+		keys[i].ProviderID = providerID
+	}
+	other := aliasedKeys(1)
+	other[0].ProviderID = otherID
+	stale := aliasedKeys(1)
+	tr := newKeyLoadTracker()
+	now := time.Now()
+
+	tr.pick(fixedRandom{}, keys, now)
+	tr.pick(fixedRandom{}, keys, now)
+	tr.pick(fixedRandom{}, other, now)
+	tr.loads[stale[0].ID] = keyLoad{providerID: otherID, count: 1, at: now.Add(-20 * keyLoadHalfLife)}
+
+	tr.prune(providerID, keys[:1], now)
+	assert.Contains(t, tr.loads, keys[0].ID)
+	assert.NotContains(t, tr.loads, keys[1].ID, "a key no longer active is forgotten")
+	assert.Contains(t, tr.loads, other[0].ID, "other providers' keys are kept")
+	assert.NotContains(t, tr.loads, stale[0].ID, "a load that has decayed away is forgotten")
+}
+
+func TestSelectAPIKey_LeastUsedMode(t *testing.T) {
+	keys := aliasedKeys(1, 1, 1)
+	keys[2].Priority = 2 // backup key, only used when the others are unavailable
+	p := models.Provider{Name: "openai", IsActive: true, RequiresAPIKey: true}
+	p.ID = uuid.New()
+	for i := range keys {
+		keys[i].ProviderID = p.ID
+	}
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{p}}, &mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{p.ID: keys}})
+	r.SetRandomSource(fixedRandom{})
+	r.SetKeySelection(KeySelectionLeastUsed)
+	ctx := context.Background()
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		k, err := r.selectAPIKey(ctx, p.ID)
+		require.NoError(t, err)
+		got = append(got, k.Alias)
+	}
+	assert.Equal(t, []string{"b", "a", "b", "a"}, got, "alternates within the best priority")
+
+	r.SetKeySelection("")
+	assert.Equal(t, KeySelectionWeighted, r.keySelection)
+}
@@ -0,0 +1,52 @@
+// Package router provides LLM request routing logic.
+// This file lets the health scheduler shed weighted traffic from providers
+// that breach their latency SLA.
+package router
+
+import (
+	"sync"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// latencyPenalties holds the weight factor of each provider breaching its
+// latency SLA. Penalties are tracked per instance.
+type latencyPenalties struct {
+	mu      sync.RWMutex
+	factors map[uuid.UUID]float64
+}
+
+// SetLatencyPenalty scales the weighted-routing share of a provider by factor
+// while its average latency breaches its SLA. A factor of 1 or more lifts the
+// penalty.
+func (r *Router) SetLatencyPenalty(providerID uuid.UUID, factor float64) {
+	r.penalties.mu.Lock()
+	defer r.penalties.mu.Unlock()
+	if factor >= 1 {
+		delete(r.penalties.factors, providerID)
+		return
+	}
+	if r.penalties.factors == nil {
+		r.penalties.factors = make(map[uuid.UUID]float64)
+	}
+	r.penalties.factors[providerID] = max(factor, 0)
+	r.logger.Info("provider weight reduced for latency SLA breach",
+		zap.String("provider_id", providerID.String()), zap.Float64("factor", factor))
+}
+
+// applyLatencyPenalties scales the traffic shares of penalized providers.
+func (r *Router) applyLatencyPenalties(providers []models.Provider, shares []float64) {
+	r.penalties.mu.RLock()
+	defer r.penalties.mu.RUnlock()
+	if len(r.penalties.factors) == 0 {
+		return
+	}
+	for i := range providers {
+		if factor, ok := r.penalties.factors[providers[i].ID]; ok {
+			shares[i] *= factor
+		}
+	}
+}
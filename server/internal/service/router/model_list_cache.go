@@ -0,0 +1,160 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// modelListKeyPrefix is the Redis key prefix for upstream /models lists.
+	modelListKeyPrefix = "router:models:"
+	// defaultModelListTTL is how long an upstream model list is cached.
+	defaultModelListTTL = 5 * time.Minute
+	// modelListL1MaxAge bounds how long an instance serves a list from memory
+	// while Redis is shared, so an invalidation on one replica reaches the
+	// others quickly.
+	modelListL1MaxAge = 30 * time.Second
+)
+
+// modelListCache caches the model lists fetched from provider /models
+// endpoints. An in-memory map (L1) sits in front of Redis (L2), so replicas
+// share fetched lists and a restarted instance starts warm. Without Redis, or
+// with sharing disabled, only L1 is used.
+type modelListCache struct {
+	mu       sync.RWMutex
+	entries  map[string]modelListEntry
+	ttl      time.Duration
+	useRedis bool
+}
+
+type modelListEntry struct {
+	models    []provider.ModelInfo
+	expiresAt time.Time
+}
+
+// cachedModelInfo is the Redis form of provider.ModelInfo; it keeps the
+// upstream extra fields, which ModelInfo does not marshal.
+type cachedModelInfo struct {
+	ID      string                     `json:"id"`
+	Name    string                     `json:"name,omitempty"`
+	Created int64                      `json:"created,omitempty"`
+	Extra   map[string]json.RawMessage `json:"extra,omitempty"`
+}
+
+func newModelListCache() *modelListCache {
+	return &modelListCache{
+		entries:  make(map[string]modelListEntry),
+		ttl:      defaultModelListTTL,
+		useRedis: true,
+	}
+}
+
+// SetModelListCache configures the upstream model list cache: ttl is how long
+// a fetched list is served (zero keeps the 5 minute default) and useRedis
+// shares lists across instances when a Redis client is set. Call before the
+// router starts serving requests.
+func (r *Router) SetModelListCache(ttl time.Duration, useRedis bool) {
+	if ttl > 0 {
+		r.modelLists.ttl = ttl
+	}
+	r.modelLists.useRedis = useRedis
+}
+
+// CachedModelList returns the cached upstream model list of a provider,
+// checking memory first and then Redis.
+func (r *Router) CachedModelList(ctx context.Context, providerName string) ([]provider.ModelInfo, bool) {
+	c := r.modelLists
+	c.mu.RLock()
+	entry, ok := c.entries[providerName]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.models, true
+	}
+
+	if !c.shared(r) {
+		return nil, false
+	}
+	key := modelListKeyPrefix + providerName
+	pipe := r.redisClient.Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	ttlCmd := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		if !errors.Is(err, redis.Nil) {
+			r.logger.Debug("redis failed for model list read", zap.String("provider", providerName), zap.Error(err))
+		}
+		return nil, false
+	}
+	var stored []cachedModelInfo
+	if err := json.Unmarshal([]byte(getCmd.Val()), &stored); err != nil {
+		return nil, false
+	}
+	mdls := make([]provider.ModelInfo, len(stored))
+	for i, m := range stored {
+		mdls[i] = provider.ModelInfo{ID: m.ID, Name: m.Name, Created: m.Created, Extra: m.Extra}
+	}
+	c.storeL1(providerName, mdls, ttlCmd.Val(), true)
+	return mdls, true
+}
+
+// CacheModelList stores a freshly fetched upstream model list in memory and,
+// when shared, in Redis.
+func (r *Router) CacheModelList(ctx context.Context, providerName string, mdls []provider.ModelInfo) {
+	c := r.modelLists
+	shared := c.shared(r)
+	c.storeL1(providerName, mdls, c.ttl, shared)
+	if !shared {
+		return
+	}
+	stored := make([]cachedModelInfo, len(mdls))
+	for i, m := range mdls {
+		stored[i] = cachedModelInfo{ID: m.ID, Name: m.Name, Created: m.Created, Extra: m.Extra}
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return
+	}
+	if err := r.redisClient.Set(ctx, modelListKeyPrefix+providerName, data, c.ttl).Err(); err != nil {
+		r.logger.Debug("redis failed for model list write", zap.String("provider", providerName), zap.Error(err))
+	}
+}
+
+// InvalidateModelList drops a provider's cached model list, e.g. after its
+// models were synced. Other instances drop their in-memory copy within 30s.
+func (r *Router) InvalidateModelList(ctx context.Context, providerName string) {
+	c := r.modelLists
+	c.mu.Lock()
+	delete(c.entries, providerName)
+	c.mu.Unlock()
+	if c.shared(r) {
+		if err := r.redisClient.Del(ctx, modelListKeyPrefix+providerName).Err(); err != nil {
+			r.logger.Debug("redis failed for model list invalidation", zap.String("provider", providerName), zap.Error(err))
+		}
+	}
+}
+
+// shared reports whether lists are shared through Redis.
+func (c *modelListCache) shared(r *Router) bool {
+	return c.useRedis && r.redisClient != nil
+}
+
+// storeL1 keeps mdls in memory for at most maxAge, and never longer than
+// modelListL1MaxAge while lists are shared.
+func (c *modelListCache) storeL1(providerName string, mdls []provider.ModelInfo, maxAge time.Duration, shared bool) {
+	if maxAge <= 0 || maxAge > c.ttl {
+		maxAge = c.ttl
+	}
+	if shared && maxAge > modelListL1MaxAge {
+		maxAge = modelListL1MaxAge
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[providerName] = modelListEntry{models: mdls, expiresAt: time.Now().Add(maxAge)}
+}
@@ -0,0 +1,56 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelListCache_SharedThroughRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	newReplica := func() *Router {
+		r := newTestRouter(&mockProviderRepo{}, nil)
+		r.SetRedisClient(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+		r.SetModelListCache(time.Minute, true)
+		return r
+	}
+	a, b := newReplica(), newReplica()
+
+	mdls := []provider.ModelInfo{{ID: "gpt-4o", Extra: map[string]json.RawMessage{"owned_by": json.RawMessage(`"openai"`)}}}
+	a.CacheModelList(ctx, "openai", mdls)
+	assert.Equal(t, time.Minute, mr.TTL(modelListKeyPrefix+"openai"))
+
+	got, ok := b.CachedModelList(ctx, "openai")
+	require.True(t, ok, "second replica should read the list from Redis")
+	assert.Equal(t, mdls, got)
+
+	// Invalidation drops the Redis copy; b's memory copy expires with the L1 cap.
+	a.InvalidateModelList(ctx, "openai")
+	_, ok = a.CachedModelList(ctx, "openai")
+	assert.False(t, ok)
+	assert.False(t, mr.Exists(modelListKeyPrefix+"openai"))
+	assert.LessOrEqual(t, time.Until(b.modelLists.entries["openai"].expiresAt), modelListL1MaxAge)
+}
+
+func TestModelListCache_RedisDisabled(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	r.SetRedisClient(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	r.SetModelListCache(0, false)
+
+	r.CacheModelList(ctx, "openai", []provider.ModelInfo{{ID: "gpt-4o"}})
+	assert.False(t, mr.Exists(modelListKeyPrefix+"openai"))
+	_, ok := r.CachedModelList(ctx, "openai")
+	assert.True(t, ok, "memory cache still serves the list")
+	assert.Greater(t, time.Until(r.modelLists.entries["openai"].expiresAt), modelListL1MaxAge)
+}
@@ -0,0 +1,170 @@
+// Package router provides LLM request routing logic.
+// This file implements model tier aliases such as "fast" and "smart" that
+// clients may request instead of a concrete model.
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router-platform/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// ModelTierStore persists the admin-configured tier mapping so every
+// instance serves the same one. The system config service implements it.
+type ModelTierStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value, description, category string, isSecret bool) error
+}
+
+// ErrInvalidModelTiers is returned when a tier mapping fails validation.
+var ErrInvalidModelTiers = errors.New("invalid model tiers")
+
+const (
+	modelTiersConfigKey = "routing.model_tiers"
+	// modelTiersRefresh bounds how long an instance serves a stale mapping
+	// after an admin saved a new one elsewhere.
+	modelTiersRefresh = 30 * time.Second
+)
+
+// SetModelTiers enables model tier aliases. defaults maps tier names to
+// models until an admin saves a mapping to store, which then replaces them.
+// A nil store serves the defaults only. Call before the router starts serving
+// requests.
+func (r *Router) SetModelTiers(defaults map[string]string, store ModelTierStore) {
+	if defaults == nil {
+		defaults = map[string]string{}
+	}
+	r.tiers = &modelTiers{defaults: defaults, store: store, logger: r.logger}
+}
+
+// ResolveModelTier returns the model the tier alias modelName maps to, or
+// modelName unchanged when it is not a tier.
+func (r *Router) ResolveModelTier(ctx context.Context, modelName string) string {
+	if r.tiers == nil {
+		return modelName
+	}
+	tiers, _ := r.tiers.get(ctx)
+	if model, ok := tiers[modelName]; ok {
+		return model
+	}
+	return modelName
+}
+
+// ModelTiers returns the tier mapping in effect and whether an admin
+// configured it rather than the server defaults.
+func (r *Router) ModelTiers(ctx context.Context) (map[string]string, bool) {
+	if r.tiers == nil {
+		return map[string]string{}, false
+	}
+	tiers, custom := r.tiers.get(ctx)
+	out := make(map[string]string, len(tiers))
+	for tier, model := range tiers {
+		out[tier] = model
+	}
+	return out, custom
+}
+
+// SaveModelTiers validates and stores tiers, replacing the server defaults on
+// every instance. An empty mapping restores the defaults.
+func (r *Router) SaveModelTiers(ctx context.Context, tiers map[string]string) error {
+	if r.tiers == nil || r.tiers.store == nil {
+		return errors.New("model tier aliases are not enabled")
+	}
+	normalized, err := NormalizeModelTiers(tiers)
+	if err != nil {
+		return err
+	}
+	value := ""
+	if len(normalized) > 0 {
+		data, err := json.Marshal(normalized)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	if err := r.tiers.store.Set(ctx, modelTiersConfigKey, value, "model tier aliases", "routing", false); err != nil {
+		return err
+	}
+	r.tiers.invalidate()
+	return nil
+}
+
+// NormalizeModelTiers trims tier names and models and rejects empty names,
+// names containing whitespace, empty models and tiers that map to themselves.
+func NormalizeModelTiers(tiers map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(tiers))
+	for tier, model := range tiers {
+		tier, model = strings.TrimSpace(tier), strings.TrimSpace(model)
+		switch {
+		case tier == "" || strings.ContainsAny(tier, " \t\n"):
+			return nil, fmt.Errorf("%w: tier name %q must be a single word", ErrInvalidModelTiers, tier)
+		case model == "":
+			return nil, fmt.Errorf("%w: tier %q has no model", ErrInvalidModelTiers, tier)
+		case model == tier:
+			return nil, fmt.Errorf("%w: tier %q maps to itself", ErrInvalidModelTiers, tier)
+		}
+		out[tier] = model
+	}
+	return out, nil
+}
+
+// modelTiers caches the stored mapping for modelTiersRefresh.
+type modelTiers struct {
+	defaults map[string]string
+	store    ModelTierStore // nil = defaults only
+	logger   *zap.Logger
+
+	mu       sync.Mutex
+	current  map[string]string
+	custom   bool
+	loadedAt time.Time
+}
+
+func (t *modelTiers) get(ctx context.Context) (map[string]string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current != nil && time.Since(t.loadedAt) < modelTiersRefresh {
+		return t.current, t.custom
+	}
+	t.loadedAt = time.Now()
+	if t.current == nil {
+		t.current = t.defaults
+	}
+	if t.store == nil {
+		return t.current, t.custom
+	}
+
+	raw, err := t.store.Get(ctx, modelTiersConfigKey)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		// Keep serving the last known mapping until the next refresh.
+		t.logger.Warn("failed to load model tiers", zap.Error(err))
+		return t.current, t.custom
+	}
+	if raw == "" {
+		t.current, t.custom = t.defaults, false
+		return t.current, t.custom
+	}
+	var stored map[string]string
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		t.logger.Warn("stored model tiers are malformed, using defaults", zap.Error(err))
+		t.current, t.custom = t.defaults, false
+		return t.current, t.custom
+	}
+	t.current, t.custom = stored, true
+	return t.current, t.custom
+}
+
+// invalidate makes the next lookup reload the stored mapping.
+func (t *modelTiers) invalidate() {
+	t.mu.Lock()
+	t.loadedAt = time.Time{}
+	t.mu.Unlock()
+}
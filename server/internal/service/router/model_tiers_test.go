@@ -0,0 +1,73 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"llm-router-platform/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTierStore is a ModelTierStore backed by a map.
+type memoryTierStore struct {
+	values map[string]string
+	gets   int
+}
+
+func (s *memoryTierStore) Get(_ context.Context, key string) (string, error) {
+	s.gets++
+	v, ok := s.values[key]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *memoryTierStore) Set(_ context.Context, key, value, _, _ string, _ bool) error {
+	s.values[key] = value
+	return nil
+}
+
+func TestResolveModelTier(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, &mockProviderAPIKeyRepo{})
+	ctx := context.Background()
+	assert.Equal(t, "fast", r.ResolveModelTier(ctx, "fast"), "tiers disabled")
+
+	store := &memoryTierStore{values: map[string]string{}}
+	r.SetModelTiers(map[string]string{"fast": "gpt-3.5-turbo", "smart": "gpt-4"}, store)
+	assert.Equal(t, "gpt-3.5-turbo", r.ResolveModelTier(ctx, "fast"))
+	assert.Equal(t, "gpt-4", r.ResolveModelTier(ctx, "smart"))
+	assert.Equal(t, "gpt-4o", r.ResolveModelTier(ctx, "gpt-4o"), "concrete models pass through")
+	r.ResolveModelTier(ctx, "fast")
+	assert.Equal(t, 1, store.gets, "the mapping is cached between requests")
+
+	tiers, custom := r.ModelTiers(ctx)
+	assert.False(t, custom)
+	assert.Equal(t, map[string]string{"fast": "gpt-3.5-turbo", "smart": "gpt-4"}, tiers)
+}
+
+func TestSaveModelTiers(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, &mockProviderAPIKeyRepo{})
+	ctx := context.Background()
+	store := &memoryTierStore{values: map[string]string{}}
+	r.SetModelTiers(map[string]string{"fast": "gpt-3.5-turbo", "smart": "gpt-4"}, store)
+	r.ResolveModelTier(ctx, "fast")
+
+	require.NoError(t, r.SaveModelTiers(ctx, map[string]string{" fast ": " claude-3-haiku ", "balanced": "gpt-4o"}))
+	assert.Equal(t, "claude-3-haiku", r.ResolveModelTier(ctx, "fast"), "saving takes effect immediately")
+	assert.Equal(t, "smart", r.ResolveModelTier(ctx, "smart"), "the saved mapping replaces the defaults")
+	tiers, custom := r.ModelTiers(ctx)
+	assert.True(t, custom)
+	assert.Equal(t, map[string]string{"fast": "claude-3-haiku", "balanced": "gpt-4o"}, tiers)
+
+	for _, bad := range []map[string]string{{"": "gpt-4"}, {"two words": "gpt-4"}, {"fast": ""}, {"gpt-4": "gpt-4"}} {
+		assert.ErrorIs(t, r.SaveModelTiers(ctx, bad), ErrInvalidModelTiers, bad)
+	}
+
+	require.NoError(t, r.SaveModelTiers(ctx, nil))
+	assert.Equal(t, "gpt-4", r.ResolveModelTier(ctx, "smart"), "an empty mapping restores the defaults")
+	_, custom = r.ModelTiers(ctx)
+	assert.False(t, custom)
+}
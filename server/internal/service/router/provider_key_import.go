@@ -0,0 +1,129 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"llm-router-platform/internal/crypto"
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxProviderKeyImport bounds the keys accepted by one bulk import.
+const MaxProviderKeyImport = 500
+
+// Outcomes of one key in a bulk import.
+const (
+	KeyImportCreated   = "created"
+	KeyImportDuplicate = "duplicate" // already stored for the provider, or repeated in the batch
+	KeyImportFailed    = "failed"
+)
+
+// ProviderKeyImport is one key of a bulk import.
+type ProviderKeyImport struct {
+	APIKey string  `json:"api_key"`
+	Alias  string  `json:"alias"`
+	Weight float64 `json:"weight"` // 0 = 1.0
+}
+
+// ProviderKeyImportResult reports what happened to one imported key. The
+// secret is never echoed back.
+type ProviderKeyImportResult struct {
+	Index     int        `json:"index"`
+	Alias     string     `json:"alias,omitempty"`
+	KeyPrefix string     `json:"key_prefix,omitempty"`
+	Status    string     `json:"status"`
+	ID        *uuid.UUID `json:"id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// ProviderKeyPrefix returns the displayable prefix stored with a provider key.
+func ProviderKeyPrefix(apiKey string) string {
+	if len(apiKey) > 10 {
+		return apiKey[:8] + "..."
+	}
+	return apiKey
+}
+
+// ImportProviderAPIKeys encrypts and stores keys for a provider, one result
+// per item in input order. Keys the provider already has, and keys repeated
+// within the batch, are reported as duplicates rather than failing the
+// import. Existing keys are compared by prefix first and decrypted only on a
+// prefix match.
+func (r *Router) ImportProviderAPIKeys(ctx context.Context, providerID uuid.UUID, items []ProviderKeyImport) ([]ProviderKeyImportResult, error) {
+	if _, err := r.GetProviderByID(ctx, providerID); err != nil {
+		return nil, err
+	}
+	existing, err := r.providerKeyRepo.GetByProvider(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	byPrefix := make(map[string][]string, len(existing))
+	for _, k := range existing {
+		byPrefix[k.KeyPrefix] = append(byPrefix[k.KeyPrefix], k.EncryptedAPIKey)
+	}
+	seen := make(map[string]bool, len(items))
+
+	results := make([]ProviderKeyImportResult, len(items))
+	for i, item := range items {
+		apiKey := strings.TrimSpace(item.APIKey)
+		res := ProviderKeyImportResult{Index: i, Alias: item.Alias, KeyPrefix: ProviderKeyPrefix(apiKey)}
+		switch {
+		case apiKey == "":
+			res.Status, res.Error = KeyImportFailed, "api_key is required"
+		case item.Weight < 0:
+			res.Status, res.Error = KeyImportFailed, "weight must not be negative"
+		case seen[apiKey] || storedKey(byPrefix[res.KeyPrefix], apiKey):
+			res.Status = KeyImportDuplicate
+		default:
+			seen[apiKey] = true
+			id, err := r.importProviderKey(ctx, providerID, apiKey, item)
+			if err != nil {
+				res.Status, res.Error = KeyImportFailed, err.Error()
+				break
+			}
+			res.Status, res.ID = KeyImportCreated, &id
+		}
+		results[i] = res
+	}
+	return results, nil
+}
+
+// importProviderKey encrypts and stores one key.
+func (r *Router) importProviderKey(ctx context.Context, providerID uuid.UUID, apiKey string, item ProviderKeyImport) (uuid.UUID, error) {
+	encrypted, err := crypto.Encrypt(apiKey)
+	if err != nil {
+		return uuid.Nil, errors.New("failed to encrypt API key")
+	}
+	weight := item.Weight
+	if weight == 0 {
+		weight = 1.0
+	}
+	key := &models.ProviderAPIKey{
+		ProviderID:      providerID,
+		Alias:           item.Alias,
+		EncryptedAPIKey: encrypted,
+		KeyPrefix:       ProviderKeyPrefix(apiKey),
+		IsActive:        true,
+		Priority:        1,
+		Weight:          weight,
+	}
+	if err := r.providerKeyRepo.Create(ctx, key); err != nil {
+		r.logger.Error("failed to store imported provider key", zap.String("provider_id", providerID.String()), zap.Error(err))
+		return uuid.Nil, errors.New("failed to store API key")
+	}
+	return key.ID, nil
+}
+
+// storedKey reports whether any of the encrypted keys decrypts to apiKey.
+func storedKey(encrypted []string, apiKey string) bool {
+	for _, enc := range encrypted {
+		if plain, err := crypto.Decrypt(enc); err == nil && plain == apiKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"llm-router-platform/internal/crypto"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProviderAPIKeys(t *testing.T) {
+	r, p, existing := newKeyedProvider(t, "http://unused", 1) // the existing key is "sk-test"
+	existing[0].KeyPrefix = ProviderKeyPrefix("sk-test")
+	ctx := context.Background()
+
+	results, err := r.ImportProviderAPIKeys(ctx, p.ID, []ProviderKeyImport{
+		{APIKey: "  sk-first-imported-key  ", Alias: "a", Weight: 2},
+		{APIKey: "sk-test", Alias: "already stored"},
+		{APIKey: "sk-first-imported-key", Alias: "repeated in batch"},
+		{APIKey: "", Alias: "empty"},
+		{APIKey: "sk-negative", Weight: -1},
+		{APIKey: "sk-second-imported-key"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+
+	statuses := make([]string, len(results))
+	for i, res := range results {
+		assert.Equal(t, i, res.Index)
+		statuses[i] = res.Status
+	}
+	assert.Equal(t, []string{KeyImportCreated, KeyImportDuplicate, KeyImportDuplicate, KeyImportFailed, KeyImportFailed, KeyImportCreated}, statuses)
+	assert.Equal(t, "sk-first...", results[0].KeyPrefix)
+	require.NotNil(t, results[0].ID)
+
+	keys, err := r.GetAllProviderAPIKeys(ctx, p.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	imported := keys[1]
+	assert.Equal(t, *results[0].ID, imported.ID)
+	assert.Equal(t, "a", imported.Alias)
+	assert.Equal(t, 2.0, imported.Weight)
+	assert.True(t, imported.IsActive)
+	plain, err := crypto.Decrypt(imported.EncryptedAPIKey)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-first-imported-key", plain, "keys are trimmed and stored encrypted")
+	assert.Equal(t, 1.0, keys[2].Weight, "weight defaults to 1")
+
+	_, err = r.ImportProviderAPIKeys(ctx, uuid.New(), []ProviderKeyImport{{APIKey: "sk-x"}})
+	assert.ErrorIs(t, err, ErrProviderNotFound)
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -14,6 +15,8 @@ import (
 	"llm-router-platform/internal/config"
 	"llm-router-platform/internal/crypto"
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/observability"
 	"llm-router-platform/internal/service/provider"
 	"llm-router-platform/pkg/sanitize"
 
@@ -28,11 +31,13 @@ type ChatResult struct {
 	FinalMessages []provider.Message     // Final list of messages after tool call loops
 	MCPCallCount  int
 	MCPErrorCount int
+	Provider      *models.Provider // Provider that served the request; set by ExecuteChatWithFallback
 }
 
 // ExecuteChat sends a chat request to the given provider with automatic key-rotation retry.
 // For providers that don't require API keys, it makes a single attempt.
-// For providers that require API keys, it retries with different keys on failure (up to maxRetries).
+// For providers that require API keys, it retries with different keys on failure (up to maxRetries),
+// pausing with a jittered backoff between keys (see KeyRetryBackoff).
 // This centralizes the retry/key-failure logic that was previously in the HTTP handler.
 func (r *Router) ExecuteChat(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, maxRetries int) (*ChatResult, error) {
 	if !r.IsProviderHealthy(p.ID) {
@@ -44,6 +49,7 @@ func (r *Router) ExecuteChat(ctx context.Context, p *models.Provider, apiKey *mo
 
 	if !p.RequiresAPIKey {
 		res, err := r.executeChatWithMCP(ctx, p, nil, req)
+		recordAttempt(ctx, p, nil, err)
 		if err != nil && isProviderLevelError(err.Error()) {
 			r.MarkProviderFailure(p.ID)
 		} else if err == nil {
@@ -54,15 +60,30 @@ func (r *Router) ExecuteChat(ctx context.Context, p *models.Provider, apiKey *mo
 
 	currentKey := apiKey
 	var lastErr error
+	backoff := r.newKeyRetryWaiter()
 
 	for attempt := 0; attempt < maxRetries && currentKey != nil; attempt++ {
+		if attempt > 0 && !backoff.wait(ctx, attempt) {
+			break
+		}
+		// Stop rotating keys once the client has gone away.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
 		result, err := r.executeChatWithMCP(ctx, p, currentKey, req)
+		recordAttempt(ctx, p, currentKey, err)
 		if err == nil {
 			r.ClearKeyFailure(currentKey.ID)
 			r.MarkProviderSuccess(p.ID)
 			return result, nil
 		}
 
+		// A cancellation is not the key's or provider's fault.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
 		lastErr = err
 		r.logger.Warn("chat request failed, trying next API key",
 			zap.Error(err),
@@ -71,7 +92,7 @@ func (r *Router) ExecuteChat(ctx context.Context, p *models.Provider, apiKey *mo
 		)
 
 		// Mark key as failed if it's a quota/rate-limit error
-		if isQuotaOrRateLimitError(err.Error()) {
+		if r.isQuotaError(p, err) {
 			r.MarkKeyFailed(currentKey.ID, err.Error())
 		} else if isProviderLevelError(err.Error()) {
 			r.MarkProviderFailure(p.ID)
@@ -165,16 +186,8 @@ func (r *Router) handleMCPToolCalls(ctx context.Context, resp *provider.ChatResp
 		return false, 0, 0, nil
 	}
 
-	var toolCalls []struct {
-		ID       string `json:"id"`
-		Type     string `json:"type"`
-		Function struct {
-			Name      string          `json:"name"`
-			Arguments json.RawMessage `json:"arguments"`
-		} `json:"function"`
-	}
-
-	if err := json.Unmarshal(choice.Message.ToolCalls, &toolCalls); err != nil {
+	toolCalls, err := provider.ParseToolCalls(choice.Message.ToolCalls)
+	if err != nil {
 		return false, 0, 0, err
 	}
 
@@ -198,7 +211,7 @@ func (r *Router) handleMCPToolCalls(ctx context.Context, resp *provider.ChatResp
 		serverName, toolName := parts[0], parts[1]
 		
 		var args map[string]json.RawMessage
-		_ = json.Unmarshal(tc.Function.Arguments, &args)
+		_ = json.Unmarshal([]byte(tc.Function.Arguments), &args)
 
 		r.logger.Info("executing MCP tool", zap.String("server", serverName), zap.String("tool", toolName))
 		mcpCalls++
@@ -246,7 +259,10 @@ func (r *Router) executeChatOnce(ctx context.Context, p *models.Provider, apiKey
 		return nil, err
 	}
 
+	ctx, span := observability.StartSpan(ctx, "provider.chat",
+		observability.AttrProvider.String(p.Name), observability.AttrModel.String(req.Model))
 	resp, err := client.Chat(ctx, req)
+	observability.EndSpan(span, err)
 	if err != nil {
 		return nil, err
 	}
@@ -254,17 +270,45 @@ func (r *Router) executeChatOnce(ctx context.Context, p *models.Provider, apiKey
 	return &ChatResult{Response: resp, UsedKey: apiKey}, nil
 }
 
+// defaultQuotaKeywords are the error-message fragments that mark a quota or
+// rate-limit failure when no keywords are configured.
+var defaultQuotaKeywords = []string{
+	"quota", "rate limit", "rate_limit", "ratelimit",
+	"too many requests", "429", "insufficient_quota",
+	"billing", "exceeded", "limit reached",
+	"resource exhausted", "resourceexhausted",
+}
+
 // isQuotaOrRateLimitError checks if an error message indicates a quota or rate limit issue.
 func isQuotaOrRateLimitError(errMsg string) bool {
-	errLower := strings.ToLower(errMsg)
-	quotaKeywords := []string{
-		"quota", "rate limit", "rate_limit", "ratelimit",
-		"too many requests", "429", "insufficient_quota",
-		"billing", "exceeded", "limit reached",
-		"resource exhausted", "resourceexhausted",
+	return containsAnyKeyword(errMsg, defaultQuotaKeywords)
+}
+
+// isQuotaError classifies err using the configured quota keywords plus any
+// extra keywords configured for provider p. For a provider error the keywords
+// are matched against its message and raw body, since Error only summarizes
+// the body.
+func (r *Router) isQuotaError(p *models.Provider, err error) bool {
+	errMsg := err.Error()
+	var pe *provider.ProviderError
+	if errors.As(err, &pe) {
+		errMsg += "\n" + pe.Message + "\n" + string(pe.Body)
 	}
-	for _, keyword := range quotaKeywords {
-		if strings.Contains(errLower, keyword) {
+	keywords := r.quotaKeywords
+	if keywords == nil {
+		keywords = defaultQuotaKeywords
+	}
+	if containsAnyKeyword(errMsg, keywords) {
+		return true
+	}
+	return p != nil && containsAnyKeyword(errMsg, r.quotaByProvider[strings.ToLower(p.Name)])
+}
+
+// containsAnyKeyword reports whether errMsg contains any keyword, case-insensitively.
+func containsAnyKeyword(errMsg string, keywords []string) bool {
+	errLower := strings.ToLower(errMsg)
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(errLower, strings.ToLower(keyword)) {
 			return true
 		}
 	}
@@ -285,8 +329,16 @@ func (r *Router) executeWithKeyRetry(ctx context.Context, p *models.Provider, ap
 
 	currentKey := apiKey
 	var lastErr error
+	backoff := r.newKeyRetryWaiter()
 
 	for attempt := 0; attempt < maxRetries && currentKey != nil; attempt++ {
+		if attempt > 0 && !backoff.wait(ctx, attempt) {
+			break
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
 		client, err := r.GetProviderClientWithKey(ctx, p, currentKey)
 		if err != nil {
 			lastErr = err
@@ -295,13 +347,16 @@ func (r *Router) executeWithKeyRetry(ctx context.Context, p *models.Provider, ap
 		}
 
 		if err := fn(client); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			lastErr = err
 			r.logger.Warn("request failed, trying next API key",
 				zap.Error(err),
 				zap.Int("attempt", attempt+1),
 				zap.String("provider", p.Name),
 			)
-			if isQuotaOrRateLimitError(err.Error()) {
+			if r.isQuotaError(p, err) {
 				r.MarkKeyFailed(currentKey.ID, err.Error())
 			}
 			currentKey, _ = r.SelectNextAPIKey(ctx, p.ID, currentKey.ID)
@@ -400,9 +455,20 @@ func (r *Router) ExecuteSpeech(ctx context.Context, p *models.Provider, apiKey *
 
 // StreamResult contains the result of an ExecuteStreamChat call.
 type StreamResult struct {
-	Client  provider.Client
-	Stream  <-chan provider.StreamChunk
-	UsedKey *models.ProviderAPIKey
+	Client   provider.Client
+	Stream   <-chan provider.StreamChunk
+	UsedKey  *models.ProviderAPIKey
+	Provider *models.Provider // set by ExecuteStreamChatWithFallback
+
+	release func() // frees the provider queue slot held for the stream
+}
+
+// Release frees the provider queue slot held for the stream, if any. It is
+// safe to call more than once.
+func (s *StreamResult) Release() {
+	if s != nil && s.release != nil {
+		s.release()
+	}
 }
 
 // ExecuteStreamChat obtains a streaming connection with automatic key-rotation retry.
@@ -410,6 +476,14 @@ type StreamResult struct {
 // Once a stream channel is successfully obtained, it returns the client and stream for
 // the handler to consume. After SSE headers are sent, retries are no longer possible.
 func (r *Router) ExecuteStreamChat(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, maxRetries int) (*StreamResult, error) {
+	ctx, span := observability.StartSpan(ctx, "provider.stream_chat",
+		observability.AttrProvider.String(p.Name), observability.AttrModel.String(req.Model))
+	result, err := r.executeStreamChat(ctx, p, apiKey, req, maxRetries)
+	observability.EndSpan(span, err)
+	return result, err
+}
+
+func (r *Router) executeStreamChat(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, maxRetries int) (*StreamResult, error) {
 	if !r.IsProviderHealthy(p.ID) {
 		return nil, errors.New("provider is temporarily unavailable (circuit-breaker)")
 	}
@@ -420,9 +494,11 @@ func (r *Router) ExecuteStreamChat(ctx context.Context, p *models.Provider, apiK
 	if !p.RequiresAPIKey {
 		client, err := r.GetProviderClientWithKey(ctx, p, nil)
 		if err != nil {
+			recordAttempt(ctx, p, nil, err)
 			return nil, err
 		}
 		stream, err := client.StreamChat(ctx, req)
+		recordAttempt(ctx, p, nil, err)
 		if err != nil {
 			if isProviderLevelError(err.Error()) {
 				r.MarkProviderFailure(p.ID)
@@ -435,10 +511,19 @@ func (r *Router) ExecuteStreamChat(ctx context.Context, p *models.Provider, apiK
 
 	currentKey := apiKey
 	var lastErr error
+	backoff := r.newKeyRetryWaiter()
 
 	for attempt := 0; attempt < maxRetries && currentKey != nil; attempt++ {
+		if attempt > 0 && !backoff.wait(ctx, attempt) {
+			break
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
 		client, err := r.GetProviderClientWithKey(ctx, p, currentKey)
 		if err != nil {
+			recordAttempt(ctx, p, currentKey, err)
 			lastErr = err
 			r.logger.Warn("stream: failed to create provider client, trying next key",
 				zap.Error(err),
@@ -450,14 +535,18 @@ func (r *Router) ExecuteStreamChat(ctx context.Context, p *models.Provider, apiK
 		}
 
 		stream, err := client.StreamChat(ctx, req)
+		recordAttempt(ctx, p, currentKey, err)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			lastErr = err
 			r.logger.Warn("stream: connection failed, trying next key",
 				zap.Error(err),
 				zap.Int("attempt", attempt+1),
 				zap.String("provider", p.Name),
 			)
-			if isQuotaOrRateLimitError(err.Error()) {
+			if r.isQuotaError(p, err) {
 				r.MarkKeyFailed(currentKey.ID, err.Error())
 			} else if isProviderLevelError(err.Error()) {
 				r.MarkProviderFailure(p.ID)
@@ -487,16 +576,20 @@ func (r *Router) GetProviderClient(name string) (provider.Client, bool) {
 func (r *Router) GetProviderClientWithKey(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey) (provider.Client, error) {
 	// For providers that don't require API keys
 	if !p.RequiresAPIKey || apiKey == nil {
-		// Try to get from registry first (for local providers like Ollama, LM Studio)
-		if client, ok := r.registry.Get(p.Name); ok {
-			return client, nil
+		// Try to get from registry first (for local providers like Ollama, LM Studio).
+		// Registry clients dial directly, so proxied providers always get a fresh one.
+		if !p.UseProxy {
+			if client, ok := r.registry.Get(p.Name); ok {
+				return client, nil
+			}
 		}
 		// Create a client without API key
 		cfg := &config.ProviderConfig{
-			BaseURL:    p.BaseURL,
-			HTTPClient: r.getHTTPClientProvider(ctx, p),
+			BaseURL:       p.BaseURL,
+			HTTPClient:    r.getHTTPClientProvider(ctx, p),
+			PromptCaching: p.PromptCaching,
 		}
-		return r.createProviderClientWithRetry(p.Name, cfg, p.MaxRetries, p.Timeout)
+		return r.createProviderClientWithRetry(p.ClientType(), cfg, p.MaxRetries, p.Timeout)
 	}
 
 	// Decrypt the API key
@@ -506,84 +599,159 @@ func (r *Router) GetProviderClientWithKey(ctx context.Context, p *models.Provide
 	}
 
 	cfg := &config.ProviderConfig{
-		APIKey:     decryptedKey,
-		BaseURL:    p.BaseURL,
-		HTTPClient: r.getHTTPClientProvider(ctx, p),
+		APIKey:        decryptedKey,
+		BaseURL:       p.BaseURL,
+		HTTPClient:    r.getHTTPClientProvider(ctx, p),
+		PromptCaching: p.PromptCaching,
 	}
 
-	return r.createProviderClientWithRetry(p.Name, cfg, p.MaxRetries, p.Timeout)
+	return r.createProviderClientWithRetry(p.ClientType(), cfg, p.MaxRetries, p.Timeout)
 }
 
-// getHTTPClientProvider returns a function that creates an HTTP client with
-// SSRF dial-time protection, plus optional proxy when the provider is so
-// configured. Always returns a non-nil provider so every provider client
-// picks up SafeTransport — never a bare &http.Client{}.
+// getHTTPClientProvider returns a function that yields the pooled HTTP client
+// for p, with SSRF dial-time protection plus optional proxy when the provider
+// is so configured. Always returns a non-nil provider so every provider client
+// picks up SafeTransport — never a bare &http.Client{}. The provider's TLS
+// settings are applied to the transport, and each upstream call is traced with
+// the provider name.
 func (r *Router) getHTTPClientProvider(ctx context.Context, p *models.Provider) config.HTTPClientProvider {
+	return func() *http.Client {
+		route, version, build := r.providerEgress(ctx, p)
+		key := httpPoolKey(p.ID, p.Name, route)
+		tlsOpts := ProviderTLSOptions(p)
+		version += fmt.Sprintf("|tls:%t,%s,%s", tlsOpts.InsecureSkipVerify, tlsOpts.MinVersion, tlsOpts.CABundlePath)
+		client := r.httpPool.get(key, version, func() *http.Client {
+			client := build()
+			r.applyProviderTLS(client, p.Name, tlsOpts)
+			return client
+		})
+		// The pool keeps the bare *http.Transport so its keep-alive tuning
+		// and CloseIdleConnections reach it; callers get a traced copy.
+		traced := *client
+		traced.Transport = observability.NewTracingTransport(client.Transport, p.Name)
+		return &traced
+	}
+}
+
+// ProviderTLSOptions returns the TLS settings configured on p.
+func ProviderTLSOptions(p *models.Provider) provider.TLSOptions {
+	return provider.TLSOptions{
+		InsecureSkipVerify: p.TLSInsecureSkipVerify,
+		MinVersion:         p.TLSMinVersion,
+		CABundlePath:       p.TLSCABundlePath,
+	}
+}
+
+// ValidateProviderTLS checks that p's TLS settings can be applied: the
+// minimum version is known and the CA bundle, if any, loads.
+func (r *Router) ValidateProviderTLS(p *models.Provider) error {
+	if _, err := ProviderTLSOptions(p).Config(); err != nil {
+		return fmt.Errorf("invalid TLS settings: %w", err)
+	}
+	return nil
+}
+
+// applyProviderTLS installs opts on the client's transport. Settings that fail
+// to load are logged and the transport keeps full default verification, so a
+// broken CA bundle never weakens security.
+func (r *Router) applyProviderTLS(client *http.Client, providerName string, opts provider.TLSOptions) {
+	cfg, err := opts.Config()
+	if err != nil {
+		r.logger.Error("provider TLS settings not applied, using defaults",
+			zap.String("provider", providerName), zap.Error(err))
+		return
+	}
+	if cfg == nil {
+		return
+	}
+	t, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+	if cfg.InsecureSkipVerify {
+		r.logger.Warn("TLS CERTIFICATE VERIFICATION DISABLED for provider; connections are open to interception",
+			zap.String("provider", providerName))
+	}
+	t.TLSClientConfig = cfg
+}
+
+// providerEgress resolves how requests to p leave the process: directly, or
+// through the provider's proxy. It returns the route's pool key, a version
+// string that changes when the proxy settings change, and an untraced client
+// constructor for the route.
+func (r *Router) providerEgress(ctx context.Context, p *models.Provider) (string, string, func() *http.Client) {
+	direct := func() *http.Client {
+		return sanitize.SafeHTTPClient(r.allowLocal, 600*time.Second)
+	}
 	if !p.UseProxy {
-		return func() *http.Client {
-			return sanitize.SafeHTTPClient(r.allowLocal, 600*time.Second)
-		}
+		return "direct", "", direct
 	}
 
-	return func() *http.Client {
-		var proxyInfo *models.Proxy
+	var proxyInfo *models.Proxy
 
-		// Use provider's default proxy if set
-		if p.DefaultProxyID != nil {
-			proxy, err := r.proxyRepo.GetByID(ctx, *p.DefaultProxyID)
-			if err == nil && proxy.IsActive {
-				proxyInfo = proxy
-			}
+	// Use provider's default proxy if set
+	if p.DefaultProxyID != nil {
+		proxy, err := r.proxyRepo.GetByID(ctx, *p.DefaultProxyID)
+		if err == nil && proxy.IsActive {
+			proxyInfo = proxy
 		}
+	}
 
-		// If no default proxy or it's inactive, get any active proxy
-		if proxyInfo == nil {
-			proxies, err := r.proxyRepo.GetActive(ctx)
-			if err != nil || len(proxies) == 0 {
-				// Fall through to a direct SafeTransport client.
-				return sanitize.SafeHTTPClient(r.allowLocal, 600*time.Second)
-			}
-			proxyInfo = &proxies[0]
+	// If no default proxy or it's inactive, get any active proxy
+	if proxyInfo == nil {
+		proxies, err := r.proxyRepo.GetActive(ctx)
+		if err != nil || len(proxies) == 0 {
+			// Fall through to a direct SafeTransport client.
+			return "direct", "", direct
 		}
+		proxyInfo = &proxies[0]
+	}
 
-		proxyURL, err := url.Parse(proxyInfo.URL)
-		if err != nil {
-			r.logger.Warn("proxy URL parse failed, falling back to direct SafeTransport", zap.Error(err))
-			return sanitize.SafeHTTPClient(r.allowLocal, 600*time.Second)
-		}
-
-		// Add authentication if available. Propagate decrypt errors so we do
-		// not silently send a half-authenticated request to the proxy.
-		if proxyInfo.Username != "" && proxyInfo.Password != "" {
-			password, decErr := crypto.Decrypt(proxyInfo.Password)
-			if decErr != nil {
-				r.logger.Error("proxy password decryption failed, falling back to direct client",
-					zap.String("proxy_id", proxyInfo.ID.String()),
-					zap.Error(decErr))
-				return sanitize.SafeHTTPClient(r.allowLocal, 600*time.Second)
-			}
-			proxyURL.User = url.UserPassword(proxyInfo.Username, password)
+	proxyURL, err := url.Parse(proxyInfo.URL)
+	if err != nil {
+		r.logger.Warn("proxy URL parse failed, falling back to direct SafeTransport", zap.Error(err))
+		return "direct", "", direct
+	}
+
+	// Add authentication if available. Propagate decrypt errors so we do
+	// not silently send a half-authenticated request to the proxy.
+	if proxyInfo.Username != "" && proxyInfo.Password != "" {
+		password, decErr := crypto.Decrypt(proxyInfo.Password)
+		if decErr != nil {
+			r.logger.Error("proxy password decryption failed, falling back to direct client",
+				zap.String("proxy_id", proxyInfo.ID.String()),
+				zap.Error(decErr))
+			return "direct", "", direct
 		}
+		proxyURL.User = url.UserPassword(proxyInfo.Username, password)
+	}
 
+	route := proxyRoute(proxyInfo.ID)
+	version := proxyInfo.URL + "|" + proxyInfo.Username + "|" + proxyInfo.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	return route, version, func() *http.Client {
 		r.logger.Debug("using proxy for provider",
 			zap.String("provider", p.Name),
 			zap.String("proxy_url", proxyInfo.URL))
-
 		return sanitize.SafeHTTPClientWithProxy(r.allowLocal, 60*time.Second, proxyURL)
 	}
 }
 
-// createProviderClient creates a provider client based on provider name.
+// proxyRoute is the egress route of requests sent through the proxy.
+func proxyRoute(proxyID uuid.UUID) string {
+	return "proxy:" + proxyID.String()
+}
+
+// createProviderClient creates a provider client based on provider type.
 // Delegates to the shared factory in the provider package.
 // Uses per-provider retry config when maxRetries > 0 or timeout > 0.
-func (r *Router) createProviderClient(name string, cfg *config.ProviderConfig) (provider.Client, error) {
-	return provider.NewClientByName(name, cfg, r.logger)
+func (r *Router) createProviderClient(providerType string, cfg *config.ProviderConfig) (provider.Client, error) {
+	return provider.NewClientByName(providerType, cfg, r.logger)
 }
 
 // createProviderClientWithRetry creates a provider client with per-provider retry overrides.
-func (r *Router) createProviderClientWithRetry(name string, cfg *config.ProviderConfig, maxRetries, timeout int) (provider.Client, error) {
+func (r *Router) createProviderClientWithRetry(providerType string, cfg *config.ProviderConfig, maxRetries, timeout int) (provider.Client, error) {
 	retryCfg := provider.RetryConfigFromProvider(maxRetries, timeout)
-	return provider.NewClientByNameWithRetry(name, cfg, retryCfg, r.logger)
+	return provider.NewClientByNameWithRetry(providerType, cfg, retryCfg, r.logger)
 }
 
 // ─── Provider CRUD Operations ──────────────────────────────────────────────
@@ -595,7 +763,11 @@ func (r *Router) GetAllProviders(ctx context.Context) ([]models.Provider, error)
 
 // GetProviderByID returns a provider by ID.
 func (r *Router) GetProviderByID(ctx context.Context, id uuid.UUID) (*models.Provider, error) {
-	return r.providerRepo.GetByID(ctx, id)
+	p, err := r.providerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, providerLookupError(err)
+	}
+	return p, nil
 }
 
 // GetProviderByName returns a provider by name.
@@ -718,24 +890,130 @@ func (r *Router) SyncProviderModels(ctx context.Context, providerID uuid.UUID, h
 		}
 		_ = r.modelRepo.Create(ctx, &m)
 	}
+	r.InvalidateModelList(ctx, prov.Name)
 
 	// Return full model list
 	return r.modelRepo.GetByProviderSorted(ctx, providerID)
 }
 
+// ErrProviderNameExists is returned when a provider is created or renamed to
+// a name another provider already uses.
+var ErrProviderNameExists = errors.New("provider name already exists")
+
+// NewProvider returns an unsaved provider with the defaults shared by the REST
+// and GraphQL create paths: inactive until an admin enables it, priority 5,
+// weight 1, 3 retries, a 30s timeout and an API key required.
+func NewProvider(name, providerType, baseURL string) *models.Provider {
+	return &models.Provider{
+		Name:           name,
+		Type:           providerType,
+		BaseURL:        baseURL,
+		IsActive:       false,
+		Priority:       5,
+		Weight:         1.0,
+		MaxRetries:     3,
+		Timeout:        30,
+		RequiresAPIKey: true,
+	}
+}
+
 // CreateProvider creates a new LLM provider.
 func (r *Router) CreateProvider(ctx context.Context, provider *models.Provider) error {
-	return r.providerRepo.Create(ctx, provider)
+	if err := resolveProviderType(provider); err != nil {
+		return err
+	}
+	if existing, err := r.providerRepo.GetByName(ctx, provider.Name); err == nil && existing != nil {
+		return ErrProviderNameExists
+	}
+	return providerWriteError(r.providerRepo.Create(ctx, provider))
+}
+
+// resolveProviderType sets Type from Name for providers named after a built-in
+// type and rejects providers of an unknown type, so a misspelled name is not
+// silently served by the OpenAI client.
+func resolveProviderType(p *models.Provider) error {
+	p.Type = strings.TrimSpace(strings.ToLower(p.Type))
+	if p.Type == "" && !provider.IsKnownType(p.Name) {
+		return fmt.Errorf("%w: %q is not a provider type, set type (e.g. %q for any OpenAI-compatible API)",
+			provider.ErrUnknownProviderType, p.Name, provider.TypeOpenAICompatible)
+	}
+	if !provider.IsKnownType(p.ClientType()) {
+		return fmt.Errorf("%w %q", provider.ErrUnknownProviderType, p.Type)
+	}
+	p.Type = p.ClientType()
+	return nil
+}
+
+// providerWriteError reports a unique-index violation, e.g. from a create
+// racing the name check, as ErrProviderNameExists.
+func providerWriteError(err error) error {
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return ErrProviderNameExists
+	}
+	return err
+}
+
+// ErrInvalidDefaultProxy is returned when a provider's default proxy does not
+// reference an existing, active proxy.
+var ErrInvalidDefaultProxy = errors.New("default proxy must reference an existing active proxy")
+
+// ValidateDefaultProxy checks that the proxy id can be pinned as a provider's
+// egress. If the proxy is deactivated later, requests fall back to the pool.
+func (r *Router) ValidateDefaultProxy(ctx context.Context, id uuid.UUID) error {
+	proxy, err := r.proxyRepo.GetByID(ctx, id)
+	if err != nil || proxy == nil || !proxy.IsActive {
+		return fmt.Errorf("%w: %s", ErrInvalidDefaultProxy, id)
+	}
+	return nil
 }
 
 // UpdateProvider updates a provider.
 func (r *Router) UpdateProvider(ctx context.Context, provider *models.Provider) error {
-	return r.providerRepo.Update(ctx, provider)
+	if err := resolveProviderType(provider); err != nil {
+		return err
+	}
+	if existing, err := r.providerRepo.GetByName(ctx, provider.Name); err == nil && existing != nil && existing.ID != provider.ID {
+		return ErrProviderNameExists
+	}
+	if err := r.providerRepo.Update(ctx, provider); err != nil {
+		return providerWriteError(err)
+	}
+	// The egress route or TLS settings may have changed.
+	r.evictProviderClients(provider.ID)
+	return nil
+}
+
+// SetProviderDraining starts or ends draining a provider for maintenance.
+// A draining provider gets no new requests while requests already routed to
+// it finish normally; it stays health-checked throughout.
+func (r *Router) SetProviderDraining(ctx context.Context, id uuid.UUID, draining bool) (*models.Provider, error) {
+	p, err := r.providerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, providerLookupError(err)
+	}
+	p.Draining = draining
+	if err := r.providerRepo.Update(ctx, p); err != nil {
+		return nil, providerWriteError(err)
+	}
+	r.logger.Info("provider drain state changed", zap.String("provider", p.Name), zap.Bool("draining", draining))
+	return p, nil
 }
 
-// DeleteProvider removes a provider by ID.
+// DeleteProvider removes a provider by ID and drops its registered and pooled
+// clients.
 func (r *Router) DeleteProvider(ctx context.Context, id uuid.UUID) error {
-	return r.providerRepo.Delete(ctx, id)
+	p, err := r.providerRepo.GetByID(ctx, id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if err := r.providerRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.evictProviderClients(id)
+	if p != nil {
+		r.registry.Unregister(p.Name)
+	}
+	return nil
 }
 
 // ToggleProviderAPIKey toggles a provider API key's active status.
@@ -756,7 +1034,13 @@ func (r *Router) GetAllProviderAPIKeys(ctx context.Context, providerID uuid.UUID
 	return r.providerKeyRepo.GetByProvider(ctx, providerID)
 }
 
-// GetProviderAPIKeys returns all API keys for a provider.
+// ListProviderAPIKeys returns the API keys of every provider, with their
+// provider loaded.
+func (r *Router) ListProviderAPIKeys(ctx context.Context) ([]models.ProviderAPIKey, error) {
+	return r.providerKeyRepo.GetAll(ctx)
+}
+
+// GetProviderAPIKeys returns the active API keys for a provider.
 func (r *Router) GetProviderAPIKeys(ctx context.Context, providerID uuid.UUID) ([]models.ProviderAPIKey, error) {
 	return r.providerKeyRepo.GetActiveByProvider(ctx, providerID)
 }
@@ -792,6 +1076,87 @@ type HealthStatus struct {
 	LastChecked  time.Time     `json:"last_checked"`
 }
 
+// maxTestedModels caps the model list returned by TestProviderConfig.
+const maxTestedModels = 100
+
+// ValidateProviderBaseURL applies the SSRF guard to a provider base URL.
+// HTTP is allowed since local providers (Ollama, vLLM) commonly use it.
+func (r *Router) ValidateProviderBaseURL(baseURL string) error {
+	if err := sanitize.ValidateWebhookURL(baseURL, true, r.allowLocal); err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	return nil
+}
+
+// ProviderTestResult reports whether an unsaved provider configuration works.
+type ProviderTestResult struct {
+	Healthy     bool     `json:"healthy"`
+	LatencyMs   int64    `json:"latency_ms"`
+	Models      []string `json:"models"`
+	ModelCount  int      `json:"model_count"`
+	Error       string   `json:"error,omitempty"`
+	ModelsError string   `json:"models_error,omitempty"`
+}
+
+// TestProviderConfig runs a health check and a model listing against a
+// provider configuration that has not been saved. The client is built for
+// this call only, without retries, and apiKey is never stored or echoed
+// back in error messages. providerType may be empty when name is a type.
+func (r *Router) TestProviderConfig(ctx context.Context, name, providerType, baseURL, apiKey string) (*ProviderTestResult, error) {
+	p := &models.Provider{Name: name, Type: providerType}
+	if err := resolveProviderType(p); err != nil {
+		return nil, err
+	}
+	if err := r.ValidateProviderBaseURL(baseURL); err != nil {
+		return nil, err
+	}
+
+	cfg := &config.ProviderConfig{
+		APIKey:  apiKey,
+		BaseURL: baseURL,
+		HTTPClient: func() *http.Client {
+			return sanitize.SafeHTTPClient(r.allowLocal, 30*time.Second)
+		},
+	}
+	client, err := provider.NewClientByNameWithRetry(p.Type, cfg, provider.RetryConfig{}, r.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redact := func(err error) string {
+		msg := err.Error()
+		if apiKey != "" {
+			msg = strings.ReplaceAll(msg, apiKey, "[REDACTED]")
+		}
+		return msg
+	}
+
+	result := &ProviderTestResult{Models: []string{}}
+	healthy, latency, err := client.CheckHealth(ctx)
+	result.Healthy = healthy && err == nil
+	result.LatencyMs = latency.Milliseconds()
+	switch {
+	case err != nil:
+		result.Error = redact(err)
+	case !healthy:
+		result.Error = "health check failed: check the base URL and API key"
+	}
+
+	models, err := client.ListModels(ctx)
+	if err != nil {
+		result.ModelsError = redact(err)
+		return result, nil
+	}
+	result.ModelCount = len(models)
+	for i, m := range models {
+		if i == maxTestedModels {
+			break
+		}
+		result.Models = append(result.Models, m.ID)
+	}
+	return result, nil
+}
+
 // CheckProviderHealth checks health of a specific provider.
 func (r *Router) CheckProviderHealth(ctx context.Context, providerName string) (*HealthStatus, error) {
 	// Get provider from database to check settings
@@ -800,39 +1165,23 @@ func (r *Router) CheckProviderHealth(ctx context.Context, providerName string) (
 		return nil, errors.New("provider not found")
 	}
 
-	// First try to get from registry (for local providers like Ollama, LM Studio)
-	client, ok := r.registry.Get(providerName)
-	if !ok {
-		if p.RequiresAPIKey {
-			// Get an active API key for this provider
-			apiKey, err := r.selectAPIKey(ctx, p.ID)
-			if err != nil {
-				return nil, errors.New("no active API keys for provider")
-			}
-
-			client, err = r.GetProviderClientWithKey(ctx, p, apiKey)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			// Create client without API key
-			cfg := &config.ProviderConfig{
-				BaseURL: p.BaseURL,
-			}
-			client, err = r.createProviderClient(providerName, cfg)
-			if err != nil {
-				return nil, err
-			}
+	// Build the client the way live traffic does, so proxied providers are
+	// probed through their proxy.
+	var apiKey *models.ProviderAPIKey
+	if p.RequiresAPIKey {
+		apiKey, err = r.selectAPIKey(ctx, p.ID)
+		if err != nil {
+			return nil, errors.New("no active API keys for provider")
 		}
 	}
-
-	// If provider requires proxy, we need to use proxy for health check
-	if p.UseProxy {
-		r.logger.Info("provider requires proxy for health check", zap.String("provider", providerName))
+	client, err := r.GetProviderClientWithKey(ctx, p, apiKey)
+	if err != nil {
+		return nil, err
 	}
 
 	healthy, latency, err := client.CheckHealth(ctx)
 	return &HealthStatus{
+		ProviderID:   p.ID,
 		ProviderName: providerName,
 		IsHealthy:    healthy,
 		Latency:      latency,
@@ -0,0 +1,453 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/config"
+	"llm-router-platform/internal/crypto"
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newKeyedProvider returns a router backed by an OpenAI-compatible provider at
+// baseURL with n active API keys.
+func newKeyedProvider(t *testing.T, baseURL string, n int) (*Router, *models.Provider, []models.ProviderAPIKey) {
+	t.Helper()
+	require.NoError(t, crypto.Initialize("0123456789abcdef0123456789abcdef"))
+
+	p := models.Provider{Name: "openai", BaseURL: baseURL, IsActive: true, RequiresAPIKey: true, MaxRetries: 1}
+	p.ID = uuid.New()
+
+	keys := make([]models.ProviderAPIKey, n)
+	for i := range keys {
+		enc, err := crypto.Encrypt("sk-test")
+		require.NoError(t, err)
+		keys[i] = models.ProviderAPIKey{ProviderID: p.ID, EncryptedAPIKey: enc, IsActive: true, Weight: 1}
+		keys[i].ID = uuid.New()
+	}
+
+	keyRepo := &mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{p.ID: keys}}
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{p}}, keyRepo)
+	return r, &p, keys
+}
+
+func TestExecuteChat_StopsRotatingKeysWhenClientCancels(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		cancel() // client disconnects while the upstream call is in flight
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	r, p, keys := newKeyedProvider(t, srv.URL, 3)
+	req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+
+	_, err := r.ExecuteChat(ctx, p, &keys[0], req, 3)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), calls.Load(), "no further keys should be tried after cancellation")
+	assert.False(t, r.isKeyTemporarilyFailed(keys[0].ID), "cancellation must not be blamed on the key")
+}
+
+func TestExecuteChat_CanceledBeforeFirstAttempt(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	r, p, keys := newKeyedProvider(t, srv.URL, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := r.ExecuteChat(ctx, p, &keys[0], &provider.ChatRequest{Model: "gpt-4o"}, 3)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, calls.Load())
+}
+
+func TestGetProviderClientWithKey_ReusesConnectionsWithoutSharingKeys(t *testing.T) {
+	var mu sync.Mutex
+	var auths, remotes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		auths = append(auths, req.Header.Get("Authorization"))
+		remotes = append(remotes, req.RemoteAddr)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	r, p, keys := newKeyedProvider(t, srv.URL, 2)
+	enc, err := crypto.Encrypt("sk-second")
+	require.NoError(t, err)
+	keys[1].EncryptedAPIKey = enc
+
+	req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+	for _, k := range []models.ProviderAPIKey{keys[0], keys[1], keys[0]} {
+		client, err := r.GetProviderClientWithKey(context.Background(), p, &k)
+		require.NoError(t, err)
+		_, err = client.Chat(context.Background(), req)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"Bearer sk-test", "Bearer sk-second", "Bearer sk-test"}, auths)
+	assert.Equal(t, remotes[0], remotes[1], "second request should reuse the pooled connection")
+	assert.Equal(t, remotes[0], remotes[2])
+}
+
+func TestProviderHTTPPool_RebuildsWhenRouteSettingsChange(t *testing.T) {
+	pool := newProviderHTTPPool()
+	builds := 0
+	build := func() *http.Client {
+		builds++
+		return &http.Client{Transport: &http.Transport{MaxIdleConns: 10}}
+	}
+
+	a := pool.get("p/proxy:1", "v1", build)
+	assert.Same(t, a, pool.get("p/proxy:1", "v1", build))
+	assert.Equal(t, 1, builds)
+	assert.Equal(t, defaultProviderMaxIdleConnsPerHost, a.Transport.(*http.Transport).MaxIdleConnsPerHost)
+	assert.Equal(t, defaultProviderMaxIdleConnsPerHost, a.Transport.(*http.Transport).MaxIdleConns)
+
+	b := pool.get("p/proxy:1", "v2", build)
+	assert.NotSame(t, a, b)
+	c := pool.get("p/direct", "", build)
+	assert.NotSame(t, b, c)
+	assert.Equal(t, 3, builds)
+}
+
+func TestValidateDefaultProxy(t *testing.T) {
+	active := models.Proxy{URL: "http://egress:3128", IsActive: true}
+	active.ID = uuid.New()
+	inactive := models.Proxy{URL: "http://old:3128"}
+	inactive.ID = uuid.New()
+
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	r.proxyRepo = &mockProxyRepo{proxies: []models.Proxy{active, inactive}}
+
+	assert.NoError(t, r.ValidateDefaultProxy(context.Background(), active.ID))
+	assert.ErrorIs(t, r.ValidateDefaultProxy(context.Background(), inactive.ID), ErrInvalidDefaultProxy)
+	assert.ErrorIs(t, r.ValidateDefaultProxy(context.Background(), uuid.New()), ErrInvalidDefaultProxy)
+}
+
+func TestProviderEgress_PrefersDefaultProxyThenPool(t *testing.T) {
+	pooled := models.Proxy{URL: "http://pool:3128", IsActive: true}
+	pooled.ID = uuid.New()
+	pinned := models.Proxy{URL: "http://pinned:3128", IsActive: true}
+	pinned.ID = uuid.New()
+	repo := &mockProxyRepo{proxies: []models.Proxy{pooled, pinned}}
+
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	r.proxyRepo = repo
+	p := &models.Provider{Name: "openai", UseProxy: true, DefaultProxyID: &pinned.ID}
+
+	route, _, _ := r.providerEgress(context.Background(), p)
+	assert.Equal(t, "proxy:"+pinned.ID.String(), route)
+
+	repo.proxies[1].IsActive = false
+	route, _, _ = r.providerEgress(context.Background(), p)
+	assert.Equal(t, "proxy:"+pooled.ID.String(), route, "inactive default proxy falls back to the pool")
+}
+
+func TestTestProviderConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer sk-good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key provided"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"},{"id":"gpt-4o-mini"}]}`))
+	}))
+	defer srv.Close()
+
+	r := newTestRouter(&mockProviderRepo{}, nil)
+
+	ok, err := r.TestProviderConfig(context.Background(), "openai", "", srv.URL, "sk-good")
+	require.NoError(t, err)
+	assert.True(t, ok.Healthy)
+	assert.Equal(t, []string{"gpt-4o", "gpt-4o-mini"}, ok.Models)
+	assert.Equal(t, 2, ok.ModelCount)
+	assert.Empty(t, ok.Error)
+
+	bad, err := r.TestProviderConfig(context.Background(), "openai", "", srv.URL, "sk-bad")
+	require.NoError(t, err)
+	assert.False(t, bad.Healthy)
+	assert.Contains(t, bad.Error, "Incorrect API key")
+	assert.NotEmpty(t, bad.ModelsError)
+	assert.Empty(t, bad.Models)
+
+	_, err = r.TestProviderConfig(context.Background(), "openai", "", "ftp://example.com", "sk-good")
+	assert.Error(t, err)
+}
+
+func TestCreateProvider_DuplicateName(t *testing.T) {
+	existing := models.Provider{Name: "openai"}
+	existing.ID = uuid.New()
+	repo := &mockProviderRepo{providers: []models.Provider{existing}}
+	r := newTestRouter(repo, nil)
+	ctx := context.Background()
+
+	err := r.CreateProvider(ctx, &models.Provider{Name: "openai"})
+	assert.ErrorIs(t, err, ErrProviderNameExists)
+
+	// A concurrent create that slips past the name check hits the unique index.
+	repo.writeErr = fmt.Errorf("%w: idx_providers_name", repository.ErrDuplicateKey)
+	err = r.CreateProvider(ctx, &models.Provider{Name: "anthropic"})
+	assert.ErrorIs(t, err, ErrProviderNameExists)
+
+	repo.writeErr = nil
+	assert.NoError(t, r.CreateProvider(ctx, &models.Provider{Name: "anthropic"}))
+}
+
+func TestUpdateProvider_RenameToTakenName(t *testing.T) {
+	openai := models.Provider{Name: "openai"}
+	openai.ID = uuid.New()
+	repo := &mockProviderRepo{providers: []models.Provider{openai}}
+	r := newTestRouter(repo, nil)
+
+	other := &models.Provider{Name: "openai"}
+	other.ID = uuid.New()
+	assert.ErrorIs(t, r.UpdateProvider(context.Background(), other), ErrProviderNameExists)
+	assert.NoError(t, r.UpdateProvider(context.Background(), &openai), "keeping its own name is not a conflict")
+}
+
+func TestCreateProvider_RequiresKnownType(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	ctx := context.Background()
+
+	named := &models.Provider{Name: "anthropic"}
+	require.NoError(t, r.CreateProvider(ctx, named))
+	assert.Equal(t, "anthropic", named.Type, "a built-in name doubles as the type")
+
+	assert.ErrorIs(t, r.CreateProvider(ctx, &models.Provider{Name: "opneai"}), provider.ErrUnknownProviderType,
+		"a misspelled name is not served by the OpenAI client")
+	assert.ErrorIs(t, r.CreateProvider(ctx, &models.Provider{Name: "gateway", Type: "grpc"}), provider.ErrUnknownProviderType)
+
+	gateway := &models.Provider{Name: "gateway", Type: " OpenAI-Compatible "}
+	require.NoError(t, r.CreateProvider(ctx, gateway))
+	assert.Equal(t, provider.TypeOpenAICompatible, gateway.Type)
+
+	_, err := r.createProviderClient(gateway.ClientType(), &config.ProviderConfig{BaseURL: "https://gateway.example.com/v1"})
+	assert.NoError(t, err)
+	_, err = r.createProviderClient("gateway", &config.ProviderConfig{BaseURL: "https://gateway.example.com/v1"})
+	assert.ErrorIs(t, err, provider.ErrUnknownProviderType)
+}
+
+func TestSelectAPIKey_AllKeysFailedDoesNotHotLoop(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantHits int32
+		wantErr  error
+	}{
+		// One probe after both keys hit the rate limit, then fail fast.
+		{"rate limited", `{"error":{"message":"Rate limit reached for requests","type":"requests","code":"rate_limit_exceeded"}}`, 3, ErrAllKeysRateLimited},
+		// Exhausted quota is not retried until the failures expire.
+		{"quota exhausted", `{"error":{"message":"You exceeded your current quota","type":"insufficient_quota","code":"insufficient_quota"}}`, 2, ErrAllKeysFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				hits.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			r, p, _ := newKeyedProvider(t, srv.URL, 2)
+			req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+
+			// A client retrying as fast as it can, as the chat handler would.
+			var lastErr error
+			for i := 0; i < 20; i++ {
+				routed, key, err := r.Route(context.Background(), "gpt-4o")
+				if err != nil {
+					lastErr = err
+					continue
+				}
+				_, err = r.ExecuteChat(context.Background(), routed, key, req, 3)
+				require.Error(t, err)
+			}
+
+			assert.Equal(t, tt.wantHits, hits.Load(), "upstream must not be hammered")
+			assert.ErrorIs(t, lastErr, tt.wantErr)
+
+			// Once the probe interval has passed, rate-limited keys get another try.
+			r.failedKeysMu.Lock()
+			r.keyProbes[p.ID] = time.Now().Add(-allKeysProbeInterval)
+			r.failedKeysMu.Unlock()
+			_, _, err := r.Route(context.Background(), "gpt-4o")
+			if tt.wantErr == ErrAllKeysRateLimited {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrAllKeysFailed)
+			}
+		})
+	}
+}
+
+func TestExecuteChat_BacksOffBetweenKeys(t *testing.T) {
+	backoff := KeyRetryBackoff{Initial: 40 * time.Millisecond, Max: 60 * time.Millisecond}
+	tests := []struct {
+		name     string
+		budget   time.Duration
+		deadline time.Duration
+		wantHits int
+	}{
+		{"every key tried", 0, 0, 3},
+		// The second pause (>= 30ms) does not fit after the first (>= 20ms).
+		{"budget spent", 45 * time.Millisecond, 0, 2},
+		// The first pause (>= 20ms) would outlast the request deadline.
+		{"deadline too close", 0, 15 * time.Millisecond, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var hits []time.Time
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				mu.Lock()
+				hits = append(hits, time.Now())
+				mu.Unlock()
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+			defer srv.Close()
+
+			r, p, keys := newKeyedProvider(t, srv.URL, 3)
+			b := backoff
+			b.Budget = tt.budget
+			r.SetKeyRetryBackoff(b)
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+			req := &provider.ChatRequest{Model: "gpt-4o", Messages: []provider.Message{{Role: "user", Content: provider.StringContent("hi")}}}
+
+			_, err := r.ExecuteChat(ctx, p, &keys[0], req, 3)
+
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, context.DeadlineExceeded, "the upstream error is reported, not the deadline")
+			mu.Lock()
+			defer mu.Unlock()
+			require.Len(t, hits, tt.wantHits)
+			// Jittered delays are at least half of 40ms, then of 80ms capped to 60ms.
+			minGaps := []time.Duration{20 * time.Millisecond, 30 * time.Millisecond}
+			for i := 1; i < len(hits); i++ {
+				assert.GreaterOrEqual(t, hits[i].Sub(hits[i-1]), minGaps[i-1], "pause before key %d", i+1)
+			}
+		})
+	}
+}
+
+func TestCheckProviderHealth_GoesThroughProxy(t *testing.T) {
+	var proxied atomic.Int32
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// A forward proxy sees the absolute upstream URL.
+		if req.URL.Host == "upstream.invalid" && req.URL.Path == "/v1/models" {
+			proxied.Add(1)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"}]}`))
+	}))
+	defer proxySrv.Close()
+
+	proxy := models.Proxy{URL: proxySrv.URL, IsActive: true}
+	proxy.ID = uuid.New()
+	p := models.Provider{Name: "openai", BaseURL: "http://upstream.invalid/v1", IsActive: true, UseProxy: true, DefaultProxyID: &proxy.ID}
+	p.ID = uuid.New()
+
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{p}}, nil)
+	r.proxyRepo = &mockProxyRepo{proxies: []models.Proxy{proxy}}
+
+	status, err := r.CheckProviderHealth(context.Background(), "openai")
+	require.NoError(t, err)
+	assert.True(t, status.IsHealthy)
+	assert.Equal(t, p.ID, status.ProviderID)
+	assert.Equal(t, int32(1), proxied.Load(), "the probe must reach the upstream through the proxy")
+}
+
+func TestGetHTTPClientProvider_PoolsTunedTransportBehindTracing(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	r.SetHTTPPoolLimits(7, 5*time.Second)
+	p := &models.Provider{Name: "openai"}
+	p.ID = uuid.New()
+
+	client := r.getHTTPClientProvider(context.Background(), p)()
+	_, isRaw := client.Transport.(*http.Transport)
+	assert.False(t, isRaw, "provider clients are traced")
+
+	require.Len(t, r.httpPool.clients, 1)
+	for _, entry := range r.httpPool.clients {
+		pooled, ok := entry.client.Transport.(*http.Transport)
+		require.True(t, ok, "the pool keeps the bare transport")
+		assert.Equal(t, 7, pooled.MaxIdleConnsPerHost)
+		assert.Equal(t, 5*time.Second, pooled.IdleConnTimeout)
+	}
+}
+
+func TestHTTPPool_EvictsOnProviderAndProxyChanges(t *testing.T) {
+	proxy := models.Proxy{URL: "http://proxy.invalid:3128", IsActive: true}
+	proxy.ID = uuid.New()
+	direct := models.Provider{Name: "openai", Type: "openai"}
+	direct.ID = uuid.New()
+	proxied := models.Provider{Name: "anthropic", Type: "anthropic", UseProxy: true, DefaultProxyID: &proxy.ID}
+	proxied.ID = uuid.New()
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{direct, proxied}}, nil)
+	r.proxyRepo = &mockProxyRepo{proxies: []models.Proxy{proxy}}
+	ctx := context.Background()
+	warm := func() {
+		r.getHTTPClientProvider(ctx, &direct)()
+		r.getHTTPClientProvider(ctx, &proxied)()
+	}
+	pooled := func(p models.Provider, route string) bool {
+		r.httpPool.mu.Lock()
+		defer r.httpPool.mu.Unlock()
+		_, ok := r.httpPool.clients[httpPoolKey(p.ID, p.Name, route)]
+		return ok
+	}
+
+	warm()
+	require.True(t, pooled(proxied, proxyRoute(proxy.ID)))
+	r.InvalidateProxyClients(proxy.ID)
+	assert.False(t, pooled(proxied, proxyRoute(proxy.ID)), "a changed proxy's clients are dropped")
+	assert.True(t, pooled(direct, "direct"), "clients on other routes are kept")
+
+	warm()
+	require.NoError(t, r.UpdateProvider(ctx, &proxied))
+	assert.False(t, pooled(proxied, proxyRoute(proxy.ID)), "an updated provider's clients are dropped")
+	assert.True(t, pooled(direct, "direct"))
+
+	require.NoError(t, r.DeleteProvider(ctx, direct.ID))
+	assert.False(t, pooled(direct, "direct"), "a deleted provider's clients are dropped")
+}
+
+func TestNewProvider_InactiveUntilEnabled(t *testing.T) {
+	p := NewProvider("gateway", provider.TypeOpenAICompatible, "https://gateway.example.com/v1")
+	assert.False(t, p.IsActive, "new providers do not take traffic until an admin enables them")
+	assert.True(t, p.RequiresAPIKey)
+	assert.Equal(t, 5, p.Priority)
+	assert.Equal(t, 3, p.MaxRetries)
+	assert.Equal(t, 30, p.Timeout)
+
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	require.NoError(t, r.CreateProvider(context.Background(), p))
+	assert.False(t, p.IsActive)
+}
@@ -0,0 +1,236 @@
+// Package router provides LLM request routing logic.
+// This file implements the optional per-provider request queue that smooths
+// bursts into a bounded number of concurrent upstream requests.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ProviderQueueConfig bounds the requests sent to each provider at once.
+type ProviderQueueConfig struct {
+	// MaxConcurrent is the number of requests a provider serves at once;
+	// 0 disables queuing.
+	MaxConcurrent int
+	// Depth is the number of requests that may wait for a slot; beyond it
+	// requests are rejected immediately.
+	Depth int
+	// MaxWait is how long a request waits for a slot before it is rejected.
+	MaxWait time.Duration
+}
+
+// Reasons a request is refused a provider slot.
+const (
+	QueueRejectFull    = "queue_full"
+	QueueRejectTimeout = "wait_timeout"
+)
+
+// ProviderQueueError reports that a provider is saturated. RetryAfter is a
+// hint for when the client may retry.
+type ProviderQueueError struct {
+	Provider   string
+	Reason     string // QueueRejectFull or QueueRejectTimeout
+	RetryAfter time.Duration
+}
+
+func (e *ProviderQueueError) Error() string {
+	if e.Reason == QueueRejectFull {
+		return fmt.Sprintf("provider %s is saturated: request queue is full", e.Provider)
+	}
+	return fmt.Sprintf("provider %s is saturated: timed out waiting for a free slot", e.Provider)
+}
+
+var (
+	providerQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "llm_router",
+			Name:      "provider_queue_depth",
+			Help:      "Requests waiting for a provider slot.",
+		},
+		[]string{"provider"},
+	)
+	providerQueueActive = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "llm_router",
+			Name:      "provider_queue_active",
+			Help:      "Requests holding a provider slot.",
+		},
+		[]string{"provider"},
+	)
+	providerQueueRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "llm_router",
+			Name:      "provider_queue_rejections_total",
+			Help:      "Requests refused a provider slot.",
+		},
+		[]string{"provider", "reason"}, // reason: "queue_full" | "wait_timeout"
+	)
+)
+
+// SetProviderQueue enables per-provider request queuing. Slots are counted
+// per instance. Call before the router starts serving requests.
+func (r *Router) SetProviderQueue(cfg ProviderQueueConfig) {
+	if cfg.MaxConcurrent <= 0 {
+		r.queues = nil
+		return
+	}
+	r.queues = &providerQueues{cfg: cfg, byProvider: make(map[uuid.UUID]*providerQueue)}
+}
+
+// AcquireProviderSlot waits until p can take another request and returns the
+// function that frees the slot. When the queue is full or the wait exceeds
+// the queue's MaxWait or the context deadline, it returns a
+// *ProviderQueueError. A canceled context returns its error. Without a
+// configured queue it returns immediately.
+func (r *Router) AcquireProviderSlot(ctx context.Context, p *models.Provider) (func(), error) {
+	if r.queues == nil {
+		return func() {}, nil
+	}
+	return r.queues.get(p).acquire(ctx, r.queues.cfg)
+}
+
+// ExecuteChatInSlot runs ExecuteChat on p while holding one of p's queue
+// slots, freeing it as soon as the call returns. Saturation is reported the
+// same way as by AcquireProviderSlot.
+func (r *Router) ExecuteChatInSlot(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, maxRetries int) (*ChatResult, error) {
+	release, err := r.AcquireProviderSlot(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return r.ExecuteChat(ctx, p, apiKey, req, maxRetries)
+}
+
+// ExecuteStreamChatInSlot runs ExecuteStreamChat on p while holding one of
+// p's queue slots. The slot is freed right away when no stream is opened and
+// otherwise stays held until StreamResult.Release.
+func (r *Router) ExecuteStreamChatInSlot(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey, req *provider.ChatRequest, maxRetries int) (*StreamResult, error) {
+	release, err := r.AcquireProviderSlot(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.ExecuteStreamChat(ctx, p, apiKey, req, maxRetries)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	res.release = release
+	return res, nil
+}
+
+// ProviderQueueStat is the queue state of one provider.
+type ProviderQueueStat struct {
+	ProviderName string `json:"provider_name"`
+	Active       int64  `json:"active"`
+	Waiting      int64  `json:"waiting"`
+}
+
+// ProviderQueueStats returns the queue state of every provider that has
+// received traffic since startup, sorted by name. It is empty when queuing
+// is disabled.
+func (r *Router) ProviderQueueStats() []ProviderQueueStat {
+	if r.queues == nil {
+		return []ProviderQueueStat{}
+	}
+	r.queues.mu.Lock()
+	stats := make([]ProviderQueueStat, 0, len(r.queues.byProvider))
+	for _, q := range r.queues.byProvider {
+		stats = append(stats, ProviderQueueStat{ProviderName: q.name, Active: int64(len(q.slots)), Waiting: q.waiting.Load()})
+	}
+	r.queues.mu.Unlock()
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ProviderName < stats[j].ProviderName })
+	return stats
+}
+
+// providerQueues holds one queue per provider.
+type providerQueues struct {
+	cfg        ProviderQueueConfig
+	mu         sync.Mutex
+	byProvider map[uuid.UUID]*providerQueue
+}
+
+func (qs *providerQueues) get(p *models.Provider) *providerQueue {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	q, ok := qs.byProvider[p.ID]
+	if !ok {
+		q = &providerQueue{name: p.Name, slots: make(chan struct{}, qs.cfg.MaxConcurrent)}
+		qs.byProvider[p.ID] = q
+	}
+	return q
+}
+
+// providerQueue is a semaphore of MaxConcurrent slots. Blocked channel
+// senders are woken in arrival order, so waiting requests are served FIFO.
+type providerQueue struct {
+	name    string
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+func (q *providerQueue) acquire(ctx context.Context, cfg ProviderQueueConfig) (func(), error) {
+	select {
+	case q.slots <- struct{}{}:
+		return q.releaser(), nil
+	default:
+	}
+
+	if q.waiting.Add(1) > int64(cfg.Depth) {
+		q.waiting.Add(-1)
+		return nil, q.reject(QueueRejectFull, cfg)
+	}
+	providerQueueDepth.WithLabelValues(q.name).Inc()
+	defer func() {
+		q.waiting.Add(-1)
+		providerQueueDepth.WithLabelValues(q.name).Dec()
+	}()
+
+	timer := time.NewTimer(cfg.MaxWait)
+	defer timer.Stop()
+	select {
+	case q.slots <- struct{}{}:
+		return q.releaser(), nil
+	case <-timer.C:
+		return nil, q.reject(QueueRejectTimeout, cfg)
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, q.reject(QueueRejectTimeout, cfg)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// releaser returns the function that frees a held slot; extra calls are
+// no-ops.
+func (q *providerQueue) releaser() func() {
+	providerQueueActive.WithLabelValues(q.name).Inc()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-q.slots
+			providerQueueActive.WithLabelValues(q.name).Dec()
+		})
+	}
+}
+
+func (q *providerQueue) reject(reason string, cfg ProviderQueueConfig) error {
+	providerQueueRejections.WithLabelValues(q.name, reason).Inc()
+	retryAfter := cfg.MaxWait
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	return &ProviderQueueError{Provider: q.name, Reason: reason, RetryAfter: retryAfter}
+}
@@ -0,0 +1,94 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queuedProvider() *models.Provider {
+	p := &models.Provider{Name: "openai"}
+	p.ID = uuid.New()
+	return p
+}
+
+func TestAcquireProviderSlot_Disabled(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, &mockProviderAPIKeyRepo{})
+	release, err := r.AcquireProviderSlot(context.Background(), queuedProvider())
+	require.NoError(t, err)
+	release()
+	assert.Empty(t, r.ProviderQueueStats())
+}
+
+func TestAcquireProviderSlot_QueuesThenRejects(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, &mockProviderAPIKeyRepo{})
+	r.SetProviderQueue(ProviderQueueConfig{MaxConcurrent: 1, Depth: 1, MaxWait: time.Minute})
+	p := queuedProvider()
+	ctx := context.Background()
+
+	release, err := r.AcquireProviderSlot(ctx, p)
+	require.NoError(t, err)
+
+	acquired := make(chan func())
+	go func() {
+		next, err := r.AcquireProviderSlot(ctx, p)
+		assert.NoError(t, err)
+		acquired <- next
+	}()
+	require.Eventually(t, func() bool {
+		stats := r.ProviderQueueStats()
+		return len(stats) == 1 && stats[0].Waiting == 1
+	}, time.Second, time.Millisecond)
+
+	_, err = r.AcquireProviderSlot(ctx, p)
+	var queueErr *ProviderQueueError
+	require.ErrorAs(t, err, &queueErr)
+	assert.Equal(t, QueueRejectFull, queueErr.Reason)
+	assert.Equal(t, time.Minute, queueErr.RetryAfter)
+
+	release()
+	release() // extra calls must not free a slot held by another request
+	next := <-acquired
+	assert.Equal(t, []ProviderQueueStat{{ProviderName: "openai", Active: 1}}, r.ProviderQueueStats())
+	next()
+	assert.Equal(t, []ProviderQueueStat{{ProviderName: "openai"}}, r.ProviderQueueStats())
+}
+
+func TestAcquireProviderSlot_WaitLimits(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, &mockProviderAPIKeyRepo{})
+	r.SetProviderQueue(ProviderQueueConfig{MaxConcurrent: 1, Depth: 10, MaxWait: 10 * time.Millisecond})
+	p := queuedProvider()
+
+	release, err := r.AcquireProviderSlot(context.Background(), p)
+	require.NoError(t, err)
+	defer release()
+
+	_, err = r.AcquireProviderSlot(context.Background(), p)
+	var queueErr *ProviderQueueError
+	require.ErrorAs(t, err, &queueErr)
+	assert.Equal(t, QueueRejectTimeout, queueErr.Reason)
+	assert.Equal(t, time.Second, queueErr.RetryAfter, "hint is at least one second")
+
+	r.SetProviderQueue(ProviderQueueConfig{MaxConcurrent: 1, Depth: 10, MaxWait: time.Minute})
+	release2, err := r.AcquireProviderSlot(context.Background(), p)
+	require.NoError(t, err)
+	defer release2()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = r.AcquireProviderSlot(ctx, p)
+	require.ErrorAs(t, err, &queueErr, "the request deadline ends the wait")
+	assert.Equal(t, QueueRejectTimeout, queueErr.Reason)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = r.AcquireProviderSlot(ctx, p)
+	assert.True(t, errors.Is(err, context.Canceled), "a client that left is not told to retry")
+}
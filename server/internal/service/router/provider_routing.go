@@ -0,0 +1,45 @@
+package router
+
+import (
+	"context"
+
+	"llm-router-platform/internal/models"
+)
+
+// ProviderRouting is the live routing state of one provider on this instance.
+type ProviderRouting struct {
+	CircuitState      string `json:"circuit_state"`
+	ConsecutiveErrors int    `json:"consecutive_errors"`
+	AvgLatencyMs      int64  `json:"avg_latency_ms"` // EWMA of served requests; 0 = none since startup
+	// RoutableKeys counts active keys under their monthly cap and out of
+	// cooldown. HasRoutableKey is always set for providers without keys.
+	RoutableKeys   int  `json:"routable_keys"`
+	HasRoutableKey bool `json:"has_routable_key"`
+}
+
+// ProviderRouting reports the circuit breaker state, observed latency and
+// routable keys of p, as the router sees them when selecting a provider.
+func (r *Router) ProviderRouting(ctx context.Context, p *models.Provider) (*ProviderRouting, error) {
+	state, consecutive := r.GetProviderCircuitState(p.ID)
+	out := &ProviderRouting{CircuitState: state.String(), ConsecutiveErrors: consecutive}
+
+	r.latencyMu.RLock()
+	out.AvgLatencyMs = r.providerLatency[p.ID]
+	r.latencyMu.RUnlock()
+
+	if !p.RequiresAPIKey {
+		out.HasRoutableKey = true
+		return out, nil
+	}
+	keys, err := r.providerKeyRepo.GetActiveByProvider(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range r.filterCappedKeys(ctx, keys) {
+		if !r.isKeyTemporarilyFailed(k.ID) {
+			out.RoutableKeys++
+		}
+	}
+	out.HasRoutableKey = out.RoutableKeys > 0
+	return out, nil
+}
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderRouting_CountsRoutableKeys(t *testing.T) {
+	r, p, keys := newKeyedProvider(t, "http://127.0.0.1:1", 2)
+	ctx := context.Background()
+
+	routing, err := r.ProviderRouting(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, "closed", routing.CircuitState)
+	assert.Equal(t, 2, routing.RoutableKeys)
+	assert.True(t, routing.HasRoutableKey)
+
+	r.MarkKeyFailed(keys[0].ID, "429 rate limit")
+	r.MarkKeyFailed(keys[1].ID, "insufficient_quota")
+	r.RecordLatency(p.ID, 120)
+	routing, err = r.ProviderRouting(ctx, p)
+	require.NoError(t, err)
+	assert.Zero(t, routing.RoutableKeys)
+	assert.False(t, routing.HasRoutableKey, "keys in cooldown are not routable")
+	assert.Equal(t, int64(120), routing.AvgLatencyMs)
+
+	keyless := &models.Provider{Name: "ollama", RequiresAPIKey: false}
+	keyless.ID = uuid.New()
+	routing, err = r.ProviderRouting(ctx, keyless)
+	require.NoError(t, err)
+	assert.True(t, routing.HasRoutableKey, "providers without keys are always routable")
+}
@@ -0,0 +1,77 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mrand "math/rand/v2"
+	"sync"
+)
+
+// RandomSource supplies the randomness behind weighted provider and API key
+// selection. Production routers use a crypto/rand backed source; tests can
+// install NewSeededRandom via SetRandomSource to assert exact outcomes.
+type RandomSource interface {
+	// Intn returns a uniform int in [0, n), or 0 when n <= 0.
+	Intn(n int) int
+	// Float64 returns a uniform float64 in [0, 1).
+	Float64() float64
+}
+
+// SetRandomSource replaces the randomness used for weighted selection.
+// Call before the router starts serving requests.
+func (r *Router) SetRandomSource(rs RandomSource) {
+	r.rng = rs
+}
+
+// cryptoRandom is the default RandomSource.
+type cryptoRandom struct{}
+
+func (cryptoRandom) Intn(n int) int   { return secureRandomInt(n) }
+func (cryptoRandom) Float64() float64 { return secureRandomFloat64() }
+
+// seededRandom is a deterministic RandomSource, safe for concurrent use.
+type seededRandom struct {
+	mu  sync.Mutex
+	rng *mrand.Rand
+}
+
+// NewSeededRandom returns a deterministic RandomSource: routers given the
+// same seed make the same sequence of selections. Intended for tests only.
+func NewSeededRandom(seed uint64) RandomSource {
+	return &seededRandom{rng: mrand.New(mrand.NewPCG(seed, seed))} // #nosec G404 -- reproducible test randomness
+}
+
+func (s *seededRandom) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.IntN(n)
+}
+
+func (s *seededRandom) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
+
+// ─── Cryptographic Random Utilities ────────────────────────────────────────
+
+// secureRandomInt returns a cryptographically secure random int in [0, n).
+func secureRandomInt(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	// #nosec G115 - n is guaranteed to be positive and within bounds for array indexing
+	return int(binary.LittleEndian.Uint32(b[:]) % uint32(n))
+}
+
+// secureRandomFloat64 returns a cryptographically secure random float64 in [0, 1).
+func secureRandomFloat64() float64 {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return float64(binary.LittleEndian.Uint64(b[:])>>11) / (1 << 53)
+}
@@ -0,0 +1,114 @@
+package router
+
+import (
+	"testing"
+
+	"llm-router-platform/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedRandom returns the same draw every time.
+type fixedRandom struct {
+	i int
+	f float64
+}
+
+func (f fixedRandom) Intn(n int) int   { return f.i % n }
+func (f fixedRandom) Float64() float64 { return f.f }
+
+func weightedProviders(weights ...float64) []models.Provider {
+	ps := make([]models.Provider, len(weights))
+	for i, w := range weights {
+		ps[i] = models.Provider{Name: string(rune('a' + i)), Weight: w}
+		ps[i].ID = uuid.New()
+	}
+	return ps
+}
+
+func TestSelectWeighted_PicksByCumulativeWeight(t *testing.T) {
+	providers := weightedProviders(1, 3)
+	r := newTestRouter(&mockProviderRepo{}, nil)
+
+	tests := []struct {
+		draw float64
+		want string
+	}{
+		{0, "a"},
+		{0.2, "a"},  // 0.8 of 4
+		{0.25, "a"}, // exactly on the boundary stays with the lower bucket
+		{0.26, "b"},
+		{0.999, "b"},
+	}
+	for _, tt := range tests {
+		r.SetRandomSource(fixedRandom{f: tt.draw})
+		assert.Equal(t, tt.want, r.selectWeighted(providers).Name, "draw %v", tt.draw)
+	}
+}
+
+func TestSelectWeighted_ZeroWeightsPickUniformly(t *testing.T) {
+	providers := weightedProviders(0, 0, 0)
+	r := newTestRouter(&mockProviderRepo{}, nil)
+
+	r.SetRandomSource(fixedRandom{i: 2})
+	assert.Equal(t, "c", r.selectWeighted(providers).Name)
+}
+
+func TestSelectWeightedKey_BestPriorityThenWeight(t *testing.T) {
+	keys := []models.ProviderAPIKey{
+		{Alias: "backup", Priority: 2, Weight: 100},
+		{Alias: "small", Priority: 1, Weight: 1},
+		{Alias: "large", Priority: 0, Weight: 3}, // 0 counts as priority 1
+	}
+
+	k, err := selectWeightedKey(fixedRandom{f: 0.2}, keys)
+	require.NoError(t, err)
+	assert.Equal(t, "small", k.Alias)
+
+	k, err = selectWeightedKey(fixedRandom{f: 0.5}, keys)
+	require.NoError(t, err)
+	assert.Equal(t, "large", k.Alias, "lower-priority keys are never drawn while better ones exist")
+
+	_, err = selectWeightedKey(fixedRandom{}, nil)
+	assert.Error(t, err)
+}
+
+func TestSeededRandom_IsReproducible(t *testing.T) {
+	a, b := NewSeededRandom(42), NewSeededRandom(42)
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, a.Float64(), b.Float64())
+		assert.Equal(t, a.Intn(10), b.Intn(10))
+	}
+	assert.Zero(t, a.Intn(0))
+}
+
+func TestSelectWeighted_SeededDistributionFollowsWeights(t *testing.T) {
+	providers := weightedProviders(1, 2, 7)
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	r.SetRandomSource(NewSeededRandom(7))
+
+	const draws = 10000
+	counts := map[string]int{}
+	for i := 0; i < draws; i++ {
+		counts[r.selectWeighted(providers).Name]++
+	}
+
+	assert.InDelta(t, 0.1, float64(counts["a"])/draws, 0.02)
+	assert.InDelta(t, 0.2, float64(counts["b"])/draws, 0.02)
+	assert.InDelta(t, 0.7, float64(counts["c"])/draws, 0.02)
+}
+
+func TestSelectWeighted_LatencyPenaltyShedsTraffic(t *testing.T) {
+	providers := weightedProviders(1, 1)
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	r.SetRandomSource(fixedRandom{f: 0.4})
+	assert.Equal(t, "a", r.selectWeighted(providers).Name)
+
+	r.SetLatencyPenalty(providers[0].ID, 0.5)
+	assert.Equal(t, "b", r.selectWeighted(providers).Name, "a now holds 1/3 of the traffic")
+
+	r.SetLatencyPenalty(providers[0].ID, 1)
+	assert.Equal(t, "a", r.selectWeighted(providers).Name, "the penalty is lifted")
+}
@@ -0,0 +1,271 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	// ErrRouteOverrideUnavailable is returned by Route when a model's override
+	// pins a provider or key that cannot serve right now and the override does
+	// not allow fallback.
+	ErrRouteOverrideUnavailable = errors.New("pinned provider or key is unavailable")
+	// ErrInvalidRouteOverride is returned when an override fails validation.
+	ErrInvalidRouteOverride = errors.New("invalid route override")
+	// ErrRouteOverrideNotFound is returned when the override does not exist.
+	ErrRouteOverrideNotFound = errors.New("route override not found")
+	// ErrRouteOverrideExists is returned when the model already has an override.
+	ErrRouteOverrideExists = errors.New("route override already exists for this model")
+)
+
+// routeOverridesRefresh bounds how long an instance routes with a stale
+// override after another instance changes it.
+const routeOverridesRefresh = 30 * time.Second
+
+// SetRouteOverrideRepo enables per-model routing overrides. Without it Route
+// never consults overrides. Call before the router starts serving requests.
+func (r *Router) SetRouteOverrideRepo(repo repository.ModelRouteOverrideRepo) {
+	r.overrideRepo = repo
+	r.overrides = &routeOverrides{repo: repo, logger: r.logger}
+}
+
+// routeOverride resolves the enabled override for modelName. applied is false
+// when there is none, or when its target is unavailable and the override
+// allows fallback; the caller then routes normally. Lookup errors fail open.
+func (r *Router) routeOverride(ctx context.Context, modelName string) (p *models.Provider, key *models.ProviderAPIKey, applied bool, err error) {
+	if r.overrideRepo == nil {
+		return nil, nil, false, nil
+	}
+	o, ok := r.overrides.get(ctx, modelName)
+	if !ok {
+		return nil, nil, false, nil
+	}
+
+	p, key, err = r.resolveOverride(ctx, o)
+	if err == nil {
+		return p, key, true, nil
+	}
+	if o.AllowFallback {
+		r.logger.Warn("route override unavailable, routing normally",
+			zap.String("model", modelName), zap.Error(err))
+		return nil, nil, false, nil
+	}
+	return nil, nil, true, err
+}
+
+// resolveOverride returns the provider and key an override pins, or an
+// ErrRouteOverrideUnavailable explaining why they cannot serve.
+func (r *Router) resolveOverride(ctx context.Context, o *models.ModelRouteOverride) (*models.Provider, *models.ProviderAPIKey, error) {
+	p, err := r.providerRepo.GetByID(ctx, o.ProviderID)
+	if err != nil || p == nil || !p.IsActive {
+		return nil, nil, fmt.Errorf("%w: provider %s is missing or inactive", ErrRouteOverrideUnavailable, o.ProviderID)
+	}
+	if p.Draining {
+		return nil, nil, fmt.Errorf("%w: provider %s is draining", ErrRouteOverrideUnavailable, p.Name)
+	}
+	if !r.IsProviderHealthy(p.ID) {
+		return nil, nil, fmt.Errorf("%w: provider %s circuit is open", ErrRouteOverrideUnavailable, p.Name)
+	}
+
+	if o.APIKeyID == nil {
+		if !p.RequiresAPIKey {
+			return p, nil, nil
+		}
+		key, err := r.selectAPIKey(ctx, p.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrRouteOverrideUnavailable, err)
+		}
+		return p, key, nil
+	}
+
+	key, err := r.providerKeyRepo.GetByID(ctx, *o.APIKeyID)
+	if err != nil || key == nil || key.ProviderID != p.ID || !key.IsActive {
+		return nil, nil, fmt.Errorf("%w: key %s is missing or inactive", ErrRouteOverrideUnavailable, *o.APIKeyID)
+	}
+	if r.isKeyTemporarilyFailed(key.ID) {
+		return nil, nil, fmt.Errorf("%w: key %s is cooling down after a failure", ErrRouteOverrideUnavailable, key.ID)
+	}
+	if len(r.filterCappedKeys(ctx, []models.ProviderAPIKey{*key})) == 0 {
+		return nil, nil, fmt.Errorf("%w: key %s has reached its monthly cap", ErrRouteOverrideUnavailable, key.ID)
+	}
+	return p, key, nil
+}
+
+// ─── Override CRUD ──────────────────────────────────────────────────────────
+
+// ListRouteOverrides returns every override, enabled or not.
+func (r *Router) ListRouteOverrides(ctx context.Context) ([]models.ModelRouteOverride, error) {
+	if r.overrideRepo == nil {
+		return []models.ModelRouteOverride{}, nil
+	}
+	return r.overrideRepo.GetAll(ctx)
+}
+
+// GetRouteOverride returns one override by ID.
+func (r *Router) GetRouteOverride(ctx context.Context, id uuid.UUID) (*models.ModelRouteOverride, error) {
+	if r.overrideRepo == nil {
+		return nil, ErrRouteOverrideNotFound
+	}
+	o, err := r.overrideRepo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrRouteOverrideNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// CreateRouteOverride validates and stores a new override.
+func (r *Router) CreateRouteOverride(ctx context.Context, o *models.ModelRouteOverride) error {
+	if r.overrideRepo == nil {
+		return errors.New("route overrides are not enabled")
+	}
+	if err := r.validateRouteOverride(ctx, o); err != nil {
+		return err
+	}
+	if err := r.overrideRepo.Create(ctx, o); err != nil {
+		return routeOverrideWriteError(err)
+	}
+	r.overrides.invalidate()
+	return nil
+}
+
+// UpdateRouteOverride validates and saves an existing override.
+func (r *Router) UpdateRouteOverride(ctx context.Context, o *models.ModelRouteOverride) error {
+	if r.overrideRepo == nil {
+		return ErrRouteOverrideNotFound
+	}
+	if err := r.validateRouteOverride(ctx, o); err != nil {
+		return err
+	}
+	if err := r.overrideRepo.Update(ctx, o); err != nil {
+		return routeOverrideWriteError(err)
+	}
+	r.overrides.invalidate()
+	return nil
+}
+
+// DeleteRouteOverride removes an override; its model routes normally again.
+func (r *Router) DeleteRouteOverride(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.GetRouteOverride(ctx, id); err != nil {
+		return err
+	}
+	if err := r.overrideRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.overrides.invalidate()
+	return nil
+}
+
+// validateRouteOverride checks that the model is named and that the pinned
+// key, if any, belongs to the pinned provider.
+func (r *Router) validateRouteOverride(ctx context.Context, o *models.ModelRouteOverride) error {
+	if o.ModelName == "" {
+		return fmt.Errorf("%w: model_name is required", ErrInvalidRouteOverride)
+	}
+	if p, err := r.providerRepo.GetByID(ctx, o.ProviderID); err != nil || p == nil {
+		return fmt.Errorf("%w: provider %s does not exist", ErrInvalidRouteOverride, o.ProviderID)
+	}
+	if o.APIKeyID != nil {
+		key, err := r.providerKeyRepo.GetByID(ctx, *o.APIKeyID)
+		if err != nil || key == nil || key.ProviderID != o.ProviderID {
+			return fmt.Errorf("%w: key %s does not belong to provider %s", ErrInvalidRouteOverride, *o.APIKeyID, o.ProviderID)
+		}
+	}
+	return nil
+}
+
+// routeOverrideWriteError reports a second override for the same model as
+// ErrRouteOverrideExists.
+func routeOverrideWriteError(err error) error {
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return ErrRouteOverrideExists
+	}
+	return err
+}
+
+// routeOverrides caches the enabled overrides for routeOverridesRefresh so
+// routing does not query the database on every request. One lookup reloads a
+// stale cache while the others wait for it; the mutex only guards swapping
+// the map, never the query.
+type routeOverrides struct {
+	repo   repository.ModelRouteOverrideRepo
+	logger *zap.Logger
+	sf     singleflight.Group
+
+	mu       sync.Mutex
+	byModel  map[string]models.ModelRouteOverride
+	loadedAt time.Time
+	gen      uint64 // bumped by invalidate; a load that raced it stays stale
+}
+
+// get returns the enabled override for modelName, if any.
+func (c *routeOverrides) get(ctx context.Context, modelName string) (*models.ModelRouteOverride, bool) {
+	if c.stale() {
+		_, _, _ = c.sf.Do("load", func() (interface{}, error) {
+			c.load(context.WithoutCancel(ctx))
+			return nil, nil
+		})
+	}
+	c.mu.Lock()
+	o, ok := c.byModel[modelName]
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return &o, true
+}
+
+func (c *routeOverrides) stale() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.byModel == nil || time.Since(c.loadedAt) >= routeOverridesRefresh
+}
+
+func (c *routeOverrides) load(ctx context.Context) {
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+
+	all, err := c.repo.GetAll(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.loadedAt = time.Now()
+	}
+	if err != nil {
+		// Keep routing with the last known overrides until the next refresh.
+		c.logger.Warn("failed to load route overrides", zap.Error(err))
+		if c.byModel == nil {
+			c.byModel = map[string]models.ModelRouteOverride{}
+		}
+		return
+	}
+	byModel := make(map[string]models.ModelRouteOverride, len(all))
+	for _, o := range all {
+		if o.IsEnabled {
+			byModel[o.ModelName] = o
+		}
+	}
+	c.byModel = byModel
+}
+
+// invalidate makes the next lookup reload the overrides.
+func (c *routeOverrides) invalidate() {
+	c.mu.Lock()
+	c.loadedAt = time.Time{}
+	c.gen++
+	c.mu.Unlock()
+}
@@ -0,0 +1,196 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockRouteOverrideRepo struct {
+	overrides []models.ModelRouteOverride
+	loads     int
+}
+
+func (m *mockRouteOverrideRepo) Create(_ context.Context, o *models.ModelRouteOverride) error {
+	m.overrides = append(m.overrides, *o)
+	return nil
+}
+func (m *mockRouteOverrideRepo) GetByID(_ context.Context, id uuid.UUID) (*models.ModelRouteOverride, error) {
+	for i := range m.overrides {
+		if m.overrides[i].ID == id {
+			return &m.overrides[i], nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *mockRouteOverrideRepo) GetAll(_ context.Context) ([]models.ModelRouteOverride, error) {
+	m.loads++
+	return m.overrides, nil
+}
+func (m *mockRouteOverrideRepo) Update(_ context.Context, o *models.ModelRouteOverride) error {
+	for i := range m.overrides {
+		if m.overrides[i].ID == o.ID {
+			m.overrides[i] = *o
+		}
+	}
+	return nil
+}
+func (m *mockRouteOverrideRepo) Delete(_ context.Context, _ uuid.UUID) error { return nil }
+
+// overrideRouter returns a router with two active keyed providers, "openai"
+// and "azure", and an override pinning gpt-4 to azure's second key.
+func overrideRouter(t *testing.T) (*Router, *mockRouteOverrideRepo, models.Provider, []models.ProviderAPIKey) {
+	t.Helper()
+	openai := models.Provider{Name: "openai", IsActive: true, RequiresAPIKey: true, Weight: 1}
+	openai.ID = uuid.New()
+	azure := models.Provider{Name: "azure", IsActive: true, RequiresAPIKey: true, Weight: 1}
+	azure.ID = uuid.New()
+
+	keys := []models.ProviderAPIKey{
+		{ProviderID: azure.ID, IsActive: true, Weight: 1, Alias: "shared"},
+		{ProviderID: azure.ID, IsActive: true, Weight: 1, Alias: "dedicated"},
+	}
+	keys[0].ID, keys[1].ID = uuid.New(), uuid.New()
+	openaiKey := models.ProviderAPIKey{ProviderID: openai.ID, IsActive: true, Weight: 1}
+	openaiKey.ID = uuid.New()
+
+	r := newTestRouter(
+		&mockProviderRepo{providers: []models.Provider{openai, azure}},
+		&mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{azure.ID: keys, openai.ID: {openaiKey}}},
+	)
+	repo := &mockRouteOverrideRepo{}
+	o := models.ModelRouteOverride{ModelName: "gpt-4", ProviderID: azure.ID, APIKeyID: &keys[1].ID, IsEnabled: true}
+	o.ID = uuid.New()
+	repo.overrides = append(repo.overrides, o)
+	r.SetRouteOverrideRepo(repo)
+	return r, repo, azure, keys
+}
+
+func TestRoute_OverridePinsProviderAndKey(t *testing.T) {
+	r, _, azure, keys := overrideRouter(t)
+
+	for i := 0; i < 20; i++ {
+		p, key, pinned, err := r.RoutePinned(context.Background(), "gpt-4")
+		require.NoError(t, err)
+		assert.True(t, pinned)
+		assert.Equal(t, azure.ID, p.ID, "heuristics would have picked openai")
+		assert.Equal(t, keys[1].ID, key.ID)
+	}
+
+	p, _, pinned, err := r.RoutePinned(context.Background(), "gpt-4o")
+	require.NoError(t, err)
+	assert.False(t, pinned, "overrides match model names exactly")
+	assert.Equal(t, "openai", p.Name)
+}
+
+func TestRoute_OverrideKeyInCooldown(t *testing.T) {
+	r, repo, _, keys := overrideRouter(t)
+	r.MarkKeyFailed(keys[1].ID, "rate limited")
+
+	_, _, err := r.Route(context.Background(), "gpt-4")
+	assert.ErrorIs(t, err, ErrRouteOverrideUnavailable, "fails fast instead of using another key")
+
+	o := repo.overrides[0]
+	o.AllowFallback = true
+	require.NoError(t, r.UpdateRouteOverride(context.Background(), &o))
+	p, _, pinned, err := r.RoutePinned(context.Background(), "gpt-4")
+	require.NoError(t, err)
+	assert.False(t, pinned)
+	assert.Equal(t, "openai", p.Name, "falls back to normal routing")
+}
+
+func TestRoute_OverrideWithoutKeySelectsProviderKey(t *testing.T) {
+	r, repo, azure, _ := overrideRouter(t)
+	repo.overrides[0].APIKeyID = nil
+
+	p, key, pinned, err := r.RoutePinned(context.Background(), "gpt-4")
+	require.NoError(t, err)
+	assert.True(t, pinned)
+	assert.Equal(t, azure.ID, p.ID)
+	assert.Equal(t, azure.ID, key.ProviderID)
+}
+
+func TestCreateRouteOverride_Validation(t *testing.T) {
+	r, _, azure, keys := overrideRouter(t)
+	ctx := context.Background()
+
+	foreignKey := uuid.New()
+	for _, o := range []models.ModelRouteOverride{
+		{ProviderID: azure.ID},
+		{ModelName: "claude-3", ProviderID: uuid.New()},
+		{ModelName: "claude-3", ProviderID: azure.ID, APIKeyID: &foreignKey},
+	} {
+		assert.ErrorIs(t, r.CreateRouteOverride(ctx, &o), ErrInvalidRouteOverride)
+	}
+	assert.NoError(t, r.CreateRouteOverride(ctx, &models.ModelRouteOverride{ModelName: "claude-3", ProviderID: azure.ID, APIKeyID: &keys[0].ID}))
+}
+
+func TestRoute_OverridesAreCachedUntilChanged(t *testing.T) {
+	r, repo, azure, _ := overrideRouter(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		p, _, err := r.Route(ctx, "gpt-4")
+		require.NoError(t, err)
+		assert.Equal(t, azure.ID, p.ID)
+	}
+	assert.Equal(t, 1, repo.loads, "overrides are loaded once, not per request")
+
+	o := repo.overrides[0]
+	o.IsEnabled = false
+	require.NoError(t, r.UpdateRouteOverride(ctx, &o))
+	p, _, pinned, err := r.RoutePinned(ctx, "gpt-4")
+	require.NoError(t, err)
+	assert.False(t, pinned, "a disabled override stops pinning at once")
+	assert.Equal(t, "openai", p.Name)
+	assert.Equal(t, 2, repo.loads)
+}
+
+// blockingRouteOverrideRepo holds GetAll until release is closed.
+type blockingRouteOverrideRepo struct {
+	mockRouteOverrideRepo
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingRouteOverrideRepo) GetAll(context.Context) ([]models.ModelRouteOverride, error) {
+	m.entered <- struct{}{}
+	<-m.release
+	return m.overrides, nil
+}
+
+func TestRouteOverrides_ReloadRunsOutsideTheLock(t *testing.T) {
+	repo := &blockingRouteOverrideRepo{entered: make(chan struct{}, 2), release: make(chan struct{})}
+	repo.overrides = []models.ModelRouteOverride{{ModelName: "gpt-4", IsEnabled: true}}
+	c := &routeOverrides{repo: repo, logger: zap.NewNop()}
+
+	found := make(chan bool)
+	go func() {
+		_, ok := c.get(context.Background(), "gpt-4")
+		found <- ok
+	}()
+	<-repo.entered
+
+	invalidated := make(chan struct{})
+	go func() {
+		c.invalidate()
+		close(invalidated)
+	}()
+	select {
+	case <-invalidated:
+	case <-time.After(time.Second):
+		t.Fatal("invalidate waited for the reload's database query")
+	}
+
+	close(repo.release)
+	assert.True(t, <-found)
+	assert.True(t, c.stale(), "a reload that raced an invalidation does not count as fresh")
+}
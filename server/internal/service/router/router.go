@@ -4,10 +4,9 @@ package router
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/binary"
 	"errors"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -17,6 +16,7 @@ import (
 	"llm-router-platform/internal/models"
 	"llm-router-platform/internal/repository"
 	"llm-router-platform/internal/service/mcp"
+	"llm-router-platform/internal/service/observability"
 	"llm-router-platform/internal/service/provider"
 
 	"github.com/redis/go-redis/v9"
@@ -44,15 +44,17 @@ type FailedKeyInfo struct {
 
 // modelDiscoveryCache caches discovered model→provider mappings.
 type modelDiscoveryCache struct {
-	// modelToProvider maps model name (lowercase) → provider name.
-	modelToProvider map[string]string
+	// modelToProvider maps model name (lowercase) → names of the providers
+	// listing it.
+	modelToProvider map[string][]string
 	fetchedAt       time.Time
 }
 
 // modelProviderCache caches DB model→provider-index mappings.
 type modelProviderCache struct {
-	// modelToProviderIdx maps model name (lowercase) → index into providers slice.
-	modelToProviderIdx map[string]int
+	// modelToProviderIdx maps model name (lowercase) → indexes into the
+	// providers slice of every provider serving it.
+	modelToProviderIdx map[string][]int
 	fetchedAt          time.Time
 }
 
@@ -61,6 +63,9 @@ const (
 	failedKeyTTL = 5 * time.Minute
 	// failedKeyPrefix is the Redis key prefix for failed API keys.
 	failedKeyPrefix = "router:failed_key:"
+	// allKeysProbeInterval spaces out requests to a provider whose keys are
+	// all in rate-limit cooldown: one probe is let through per interval.
+	allKeysProbeInterval = 10 * time.Second
 	// cacheTTL is the TTL for model caches.
 	cacheTTL = 5 * time.Minute
 )
@@ -72,13 +77,16 @@ type Router struct {
 	proxyRepo        repository.ProxyRepo
 	modelRepo        repository.ModelRepo
 	routingRuleRepo  repository.RoutingRuleRepo
+	fallbackRepo     repository.FallbackChainRepo
+	fallbackChains   fallbackChainCache // Enabled chains from fallbackRepo
 	registry         *provider.Registry
 	mcpService       *mcp.Service
 	strategy         Strategy
-	roundRobinIndex  int
+	roundRobinLast   uuid.UUID              // ID of the provider served last by round-robin
 	redisClient      *redis.Client          // nil = use in-memory fallback
 	failedKeys       map[uuid.UUID]*FailedKeyInfo // In-memory fallback when Redis unavailable
 	failedKeysMu     sync.RWMutex
+	keyProbes        map[uuid.UUID]time.Time // Last all-keys-rate-limited probe per provider; guarded by failedKeysMu
 	providerLatency  map[uuid.UUID]int64    // EWMA latency per provider (ms)
 	latencyMu        sync.RWMutex
 	modelCache       *modelProviderCache    // Cached DB model→provider map
@@ -89,6 +97,25 @@ type Router struct {
 	cacheSF          singleflight.Group      // Dedup concurrent model-provider cache refreshes
 	circuitBreaker   *CircuitBreaker         // Provider-level circuit breaker (3-state)
 	retryCfg         RetryConfig             // Exponential backoff config
+	keyBackoff       KeyRetryBackoff         // Delay between key rotation attempts
+	keySelection     KeySelection            // How a key is picked among eligible keys
+	keyLoads         *keyLoadTracker         // Recent selections per key, for KeySelectionLeastUsed
+	quotaKeywords    []string                // nil = defaultQuotaKeywords
+	quotaByProvider  map[string][]string     // Extra quota keywords keyed by lowercase provider name
+	usageRepo        repository.UsageLogRepo // nil = provider key monthly caps not enforced
+	overrideRepo     repository.ModelRouteOverrideRepo // nil = per-model overrides disabled
+	overrides        *routeOverrides                   // Enabled overrides by model; set with overrideRepo
+	unknownPolicy    UnknownModelPolicy      // "" = UnknownModelStrategy
+	catchAll         string                  // Provider name used by UnknownModelCatchAll
+	keyUsage         map[uuid.UUID]keyUsageEntry
+	keyUsageMu       sync.RWMutex
+	httpPool         *providerHTTPPool // Reused provider HTTP clients (keep-alive)
+	modelLists       *modelListCache   // Upstream /models lists, shared via Redis
+	failover         *failoverAlerts   // nil = fallback chain failovers are not alerted
+	queues           *providerQueues   // nil = requests are not queued per provider
+	tiers            *modelTiers       // nil = model tier aliases disabled
+	penalties        latencyPenalties  // Weight factors of providers breaching their latency SLA
+	rng              RandomSource      // Weighted provider/key selection; cryptoRandom outside tests
 	logger           *zap.Logger
 	allowLocal       bool // SSRF gate for provider/model-discovery HTTP clients
 }
@@ -102,6 +129,7 @@ func NewRouter(
 	proxyRepo repository.ProxyRepo,
 	modelRepo repository.ModelRepo,
 	routingRuleRepo repository.RoutingRuleRepo,
+	fallbackRepo repository.FallbackChainRepo,
 	registry *provider.Registry,
 	mcpService *mcp.Service,
 	logger *zap.Logger,
@@ -113,12 +141,20 @@ func NewRouter(
 		proxyRepo:       proxyRepo,
 		modelRepo:       modelRepo,
 		routingRuleRepo: routingRuleRepo,
+		fallbackRepo:    fallbackRepo,
 		registry:        registry,
 		mcpService:      mcpService,
 		strategy:        StrategyWeighted,
 		failedKeys:      make(map[uuid.UUID]*FailedKeyInfo),
+		keyProbes:       make(map[uuid.UUID]time.Time),
 		circuitBreaker:  NewCircuitBreaker(DefaultCircuitBreakerConfig(), logger),
 		retryCfg:        DefaultRetryConfig(),
+		keyBackoff:      DefaultKeyRetryBackoff(),
+		keySelection:    KeySelectionWeighted,
+		keyLoads:        newKeyLoadTracker(),
+		httpPool:        newProviderHTTPPool(),
+		modelLists:      newModelListCache(),
+		rng:             cryptoRandom{},
 		logger:          logger,
 		allowLocal:      allowLocal,
 	}
@@ -154,6 +190,12 @@ func (r *Router) GetProviderCircuitState(providerID uuid.UUID) (CircuitState, in
 	return r.circuitBreaker.GetState(providerID)
 }
 
+// CircuitStates returns the circuit breaker state of every provider that has
+// received traffic since startup.
+func (r *Router) CircuitStates() []CircuitSnapshot {
+	return r.circuitBreaker.Snapshot()
+}
+
 // resolveProviderName does a best-effort lookup of a provider's name by ID.
 // Used for Prometheus labels — must not block on DB.
 func (r *Router) resolveProviderName(providerID uuid.UUID) string {
@@ -169,10 +211,10 @@ func (r *Router) SetRedisClient(client *redis.Client) {
 	r.redisClient = client
 }
 
-// getModelProviderCache returns a cached map of model name (lowercase) → provider index.
+// getModelProviderCache returns a cached map of model name (lowercase) → provider indexes.
 // Refreshes from DB every 5 minutes. Uses singleflight to prevent thundering herd
 // when multiple goroutines hit an expired cache simultaneously.
-func (r *Router) getModelProviderCache(providers []models.Provider) map[string]int {
+func (r *Router) getModelProviderCache(providers []models.Provider) map[string][]int {
 	r.modelCacheMu.RLock()
 	if r.modelCache != nil && time.Since(r.modelCache.fetchedAt) < cacheTTL {
 		result := r.modelCache.modelToProviderIdx
@@ -191,7 +233,7 @@ func (r *Router) getModelProviderCache(providers []models.Provider) map[string]i
 		}
 		r.modelCacheMu.RUnlock()
 
-		result := make(map[string]int)
+		result := make(map[string][]int)
 		for i := range providers {
 			dbModels, err := r.modelRepo.GetByProvider(context.Background(), providers[i].ID)
 			if err != nil {
@@ -199,7 +241,8 @@ func (r *Router) getModelProviderCache(providers []models.Provider) map[string]i
 			}
 			for _, m := range dbModels {
 				if m.IsActive {
-					result[strings.ToLower(m.Name)] = i
+					name := strings.ToLower(m.Name)
+					result[name] = append(result[name], i)
 				}
 			}
 		}
@@ -215,11 +258,11 @@ func (r *Router) getModelProviderCache(providers []models.Provider) map[string]i
 		return result, nil
 	})
 
-	return v.(map[string]int)
+	return v.(map[string][]int)
 }
 
 // getDiscoveryCache returns the cached model→provider map if still valid.
-func (r *Router) getDiscoveryCache() map[string]string {
+func (r *Router) getDiscoveryCache() map[string][]string {
 	r.discoveryCacheMu.RLock()
 	defer r.discoveryCacheMu.RUnlock()
 	if r.discoveryCache == nil || time.Since(r.discoveryCache.fetchedAt) > 5*time.Minute {
@@ -229,15 +272,15 @@ func (r *Router) getDiscoveryCache() map[string]string {
 }
 
 // refreshDiscoveryCache rebuilds the model→provider cache by querying upstreams.
-func (r *Router) refreshDiscoveryCache(providers []models.Provider) map[string]string {
-	result := make(map[string]string)
+func (r *Router) refreshDiscoveryCache(providers []models.Provider) map[string][]string {
+	result := make(map[string][]string)
 	for i := range providers {
 		p := &providers[i]
 		client, ok := r.registry.Get(p.Name)
 		if !ok && !p.RequiresAPIKey {
 			cfg := &config.ProviderConfig{BaseURL: p.BaseURL}
 			var err error
-			client, err = r.createProviderClient(p.Name, cfg)
+			client, err = r.createProviderClient(p.ClientType(), cfg)
 			if err != nil || client == nil {
 				continue
 			}
@@ -251,7 +294,10 @@ func (r *Router) refreshDiscoveryCache(providers []models.Provider) map[string]s
 			continue
 		}
 		for _, m := range upstreamModels {
-			result[strings.ToLower(m.ID)] = p.Name
+			id := strings.ToLower(m.ID)
+			if !slices.Contains(result[id], p.Name) {
+				result[id] = append(result[id], p.Name)
+			}
 		}
 	}
 
@@ -266,6 +312,20 @@ func (r *Router) refreshDiscoveryCache(providers []models.Provider) map[string]s
 	return result
 }
 
+// SetQuotaKeywords overrides the error-message keywords that classify an
+// upstream failure as a quota or rate-limit error. An empty keywords list keeps
+// the defaults; perProvider adds extra keywords for the named providers.
+// Call before the router starts serving requests.
+func (r *Router) SetQuotaKeywords(keywords []string, perProvider map[string][]string) {
+	if len(keywords) > 0 {
+		r.quotaKeywords = keywords
+	}
+	r.quotaByProvider = make(map[string][]string, len(perProvider))
+	for name, extra := range perProvider {
+		r.quotaByProvider[strings.ToLower(name)] = extra
+	}
+}
+
 // SetStrategy sets the routing strategy.
 func (r *Router) SetStrategy(strategy Strategy) {
 	r.mu.Lock()
@@ -273,9 +333,49 @@ func (r *Router) SetStrategy(strategy Strategy) {
 	r.strategy = strategy
 }
 
-// Route selects a provider and API key for a request.
+// Strategy returns the configured routing strategy.
+func (r *Router) Strategy() Strategy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.strategy
+}
+
+// Route selects a provider and API key for a request. A model route
+// override, when one is configured for modelName, takes precedence.
 func (r *Router) Route(ctx context.Context, modelName string) (*models.Provider, *models.ProviderAPIKey, error) {
-	providers, err := r.providerRepo.GetActive(ctx)
+	p, key, _, err := r.RoutePinned(ctx, modelName)
+	return p, key, err
+}
+
+// RoutePinned is Route that also reports whether a model route override
+// chose the provider and key. Pinned requests should not fall back to other
+// providers.
+func (r *Router) RoutePinned(ctx context.Context, modelName string) (*models.Provider, *models.ProviderAPIKey, bool, error) {
+	ctx, span := observability.StartSpan(ctx, "router.route", observability.AttrModel.String(modelName))
+	p, key, pinned, err := r.route(ctx, modelName)
+	if p != nil {
+		span.SetAttributes(observability.AttrProvider.String(p.Name))
+	}
+	observability.EndSpan(span, err)
+	return p, key, pinned, err
+}
+
+func (r *Router) route(ctx context.Context, modelName string) (*models.Provider, *models.ProviderAPIKey, bool, error) {
+	// 0. A model route override bypasses rules, heuristics and strategy
+	if p, key, applied, err := r.routeOverride(ctx, modelName); applied {
+		return p, key, err == nil, err
+	}
+
+	p, key, err := r.routeByRules(ctx, modelName)
+	return p, key, false, err
+}
+
+// routeByRules selects a provider through routing rules, model heuristics
+// and the unknown-model policy, then picks one of its keys. A provider whose
+// keys are all cooling down is skipped for the next candidate; when none is
+// left the key error is returned.
+func (r *Router) routeByRules(ctx context.Context, modelName string) (*models.Provider, *models.ProviderAPIKey, error) {
+	providers, err := r.routableProviders(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -284,7 +384,53 @@ func (r *Router) Route(ctx context.Context, modelName string) (*models.Provider,
 		return nil, nil, errors.New("no active providers available")
 	}
 
+	var keysErr error
+	for len(providers) > 0 {
+		selectedProvider, err := r.selectProviderByRules(ctx, modelName, providers)
+		if err != nil {
+			if keysErr != nil {
+				return nil, nil, keysErr
+			}
+			return nil, nil, err
+		}
+
+		// For providers that don't require API keys (e.g., Ollama, LM Studio), return nil for apiKey
+		if !selectedProvider.RequiresAPIKey {
+			return selectedProvider, nil, nil
+		}
+
+		apiKey, err := r.selectAPIKey(ctx, selectedProvider.ID)
+		if err == nil {
+			return selectedProvider, apiKey, nil
+		}
+		if !IsKeysExhausted(err) {
+			return nil, nil, err
+		}
+		// Rate-limited keys recover soonest, so that is what the client hears.
+		if keysErr == nil || errors.Is(err, ErrAllKeysRateLimited) {
+			keysErr = err
+		}
+		providers = withoutProvider(providers, selectedProvider.ID)
+	}
+	return nil, nil, keysErr
+}
+
+// withoutProvider returns a copy of providers without the one with id.
+func withoutProvider(providers []models.Provider, id uuid.UUID) []models.Provider {
+	out := make([]models.Provider, 0, len(providers))
+	for _, p := range providers {
+		if p.ID != id {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// selectProviderByRules picks the provider for modelName among providers
+// through routing rules, model heuristics and the unknown-model policy.
+func (r *Router) selectProviderByRules(ctx context.Context, modelName string, providers []models.Provider) (*models.Provider, error) {
 	var selectedProvider *models.Provider
+	var err error
 
 	// 1. Evaluate explicit Routing Rules
 	selectedProvider = r.evaluateRoutingRules(ctx, modelName, providers)
@@ -294,22 +440,51 @@ func (r *Router) Route(ctx context.Context, modelName string) (*models.Provider,
 		selectedProvider = r.findProviderForModel(modelName, providers)
 	}
 
-	// 3. If no specific provider found, use strategy selection
+	// 3. No provider claims the model: apply the unknown-model policy
 	if selectedProvider == nil {
-		selectedProvider = r.selectByStrategy(ctx, modelName, providers)
+		selectedProvider, err = r.routeUnknownModel(ctx, modelName, providers)
 	}
+	return selectedProvider, err
+}
 
-	// For providers that don't require API keys (e.g., Ollama, LM Studio), return nil for apiKey
-	if !selectedProvider.RequiresAPIKey {
-		return selectedProvider, nil, nil
+// routableProviders returns the active providers that accept new requests:
+// providers being drained are left out.
+func (r *Router) routableProviders(ctx context.Context) ([]models.Provider, error) {
+	providers, err := r.providerRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := providers[:0]
+	for _, p := range providers {
+		if !p.Draining {
+			out = append(out, p)
+		}
 	}
+	return out, nil
+}
 
-	apiKey, err := r.selectAPIKey(ctx, selectedProvider.ID)
+// ErrProviderUnavailable is returned by RouteToProvider when the named provider
+// does not exist, is inactive or is being drained.
+var ErrProviderUnavailable = errors.New("provider not found, inactive or draining")
+
+// RouteToProvider pins a request to the provider with the given name, bypassing
+// routing rules, model heuristics and strategy selection. An API key is still
+// selected normally.
+func (r *Router) RouteToProvider(ctx context.Context, name string) (*models.Provider, *models.ProviderAPIKey, error) {
+	p, err := r.providerRepo.GetByName(ctx, name)
+	if err != nil || p == nil || !p.IsActive || p.Draining {
+		return nil, nil, ErrProviderUnavailable
+	}
+
+	if !p.RequiresAPIKey {
+		return p, nil, nil
+	}
+
+	apiKey, err := r.selectAPIKey(ctx, p.ID)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	return selectedProvider, apiKey, nil
+	return p, apiKey, nil
 }
 
 // evaluateRoutingRules checks explicit routing rules and returns a matching provider, or nil.
@@ -382,9 +557,10 @@ func (r *Router) selectByStrategy(ctx context.Context, modelName string, provide
 	}
 }
 
-// RouteWithFallback attempts routing with fallback providers.
+// RouteWithFallback attempts routing with fallback providers. Providers are
+// tried in the order of a matching fallback chain, or by priority otherwise.
 func (r *Router) RouteWithFallback(ctx context.Context, modelName string, maxRetries int) (*models.Provider, *models.ProviderAPIKey, error) {
-	providers, err := r.providerRepo.GetActive(ctx)
+	providers, err := r.routableProviders(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -393,7 +569,7 @@ func (r *Router) RouteWithFallback(ctx context.Context, modelName string, maxRet
 		return nil, nil, errors.New("no active providers available")
 	}
 
-	sortByPriority(providers)
+	providers = r.fallbackOrder(ctx, modelName, providers)
 
 	for i := 0; i < len(providers) && i < maxRetries; i++ {
 		apiKey, err := r.selectAPIKey(ctx, providers[i].ID)
@@ -464,6 +640,11 @@ func (r *Router) ClearKeyFailure(keyID uuid.UUID) {
 	delete(r.failedKeys, keyID)
 }
 
+// SelectAPIKey selects an API key for the provider the way chat requests do.
+func (r *Router) SelectAPIKey(ctx context.Context, providerID uuid.UUID) (*models.ProviderAPIKey, error) {
+	return r.selectAPIKey(ctx, providerID)
+}
+
 // selectAPIKey selects an API key for the provider, excluding temporarily failed keys.
 func (r *Router) selectAPIKey(ctx context.Context, providerID uuid.UUID) (*models.ProviderAPIKey, error) {
 	keys, err := r.providerKeyRepo.GetActiveByProvider(ctx, providerID)
@@ -471,10 +652,16 @@ func (r *Router) selectAPIKey(ctx context.Context, providerID uuid.UUID) (*model
 		return nil, err
 	}
 
+	r.keysReloaded(providerID, keys)
 	if len(keys) == 0 {
 		return nil, errors.New("no active API keys for provider")
 	}
 
+	keys = r.filterCappedKeys(ctx, keys)
+	if len(keys) == 0 {
+		return nil, errors.New("all API keys for provider have reached their monthly cap")
+	}
+
 	// Filter out temporarily failed keys
 	availableKeys := make([]models.ProviderAPIKey, 0, len(keys))
 	for _, k := range keys {
@@ -483,19 +670,121 @@ func (r *Router) selectAPIKey(ctx context.Context, providerID uuid.UUID) (*model
 		}
 	}
 
-	// If all keys are failed, use all keys (reset and try again)
 	if len(availableKeys) == 0 {
-		r.logger.Warn("all API keys are temporarily failed, resetting", zap.Int("total_keys", len(keys)))
-		availableKeys = keys
-		// Clear all failed keys for this provider
-		r.failedKeysMu.Lock()
-		for _, k := range keys {
-			delete(r.failedKeys, k.ID)
+		return r.selectFailedKey(ctx, providerID, keys)
+	}
+
+	return r.pickKey(availableKeys)
+}
+
+var (
+	// ErrAllKeysFailed is returned when every key of a provider failed with a
+	// non-transient error (e.g. exhausted quota) within failedKeyTTL.
+	ErrAllKeysFailed = errors.New("all API keys for provider failed recently")
+	// ErrAllKeysRateLimited is returned when every key of a provider is in
+	// rate-limit cooldown and the provider was probed less than
+	// allKeysProbeInterval ago.
+	ErrAllKeysRateLimited = errors.New("all API keys for provider are rate limited")
+)
+
+// IsKeysExhausted reports whether err means every key of a provider is
+// cooling down, as opposed to the provider or model being unavailable.
+func IsKeysExhausted(err error) bool {
+	return errors.Is(err, ErrAllKeysRateLimited) || errors.Is(err, ErrAllKeysFailed)
+}
+
+// KeysRetryAfter is how long a client should wait before retrying a request
+// that failed with a key-exhaustion error: the next rate-limit probe, or the
+// expiry of the key failures.
+func KeysRetryAfter(err error) time.Duration {
+	if errors.Is(err, ErrAllKeysRateLimited) {
+		return allKeysProbeInterval
+	}
+	return failedKeyTTL
+}
+
+// rateLimitKeywords mark a key failure as a short-lived rate limit rather
+// than an exhausted quota or billing problem.
+var rateLimitKeywords = []string{"rate limit", "rate_limit", "ratelimit", "too many requests", "429"}
+
+// isRateLimitReason reports whether a key failure reason is a transient rate
+// limit. OpenAI reports exhausted quota as a 429 too, so quota and billing
+// reasons never count as rate limits.
+func isRateLimitReason(reason string) bool {
+	return containsAnyKeyword(reason, rateLimitKeywords) &&
+		!containsAnyKeyword(reason, []string{"insufficient_quota", "billing"})
+}
+
+// selectFailedKey handles a provider whose keys are all temporarily failed.
+// Keys that are only rate limited may recover any moment, so one request per
+// allKeysProbeInterval is let through on one of them; the rest fail fast with
+// ErrAllKeysRateLimited. When no key is merely rate limited the provider fails
+// fast with ErrAllKeysFailed until the failures expire. Failures are never
+// cleared here, so a retry loop cannot re-fail and reset them in a hot loop.
+func (r *Router) selectFailedKey(ctx context.Context, providerID uuid.UUID, keys []models.ProviderAPIKey) (*models.ProviderAPIKey, error) {
+	ids := make([]uuid.UUID, len(keys))
+	for i, k := range keys {
+		ids[i] = k.ID
+	}
+	reasons := r.KeyCooldowns(ctx, ids)
+	limited := make([]models.ProviderAPIKey, 0, len(keys))
+	for _, k := range keys {
+		if reason, ok := reasons[k.ID]; !ok || isRateLimitReason(reason) {
+			limited = append(limited, k)
+		}
+	}
+	if len(limited) == 0 {
+		r.logger.Warn("all API keys failed recently, failing fast",
+			zap.String("provider_id", providerID.String()), zap.Int("total_keys", len(keys)))
+		return nil, ErrAllKeysFailed
+	}
+
+	r.failedKeysMu.Lock()
+	probe := time.Since(r.keyProbes[providerID]) >= allKeysProbeInterval
+	if probe {
+		r.keyProbes[providerID] = time.Now()
+	}
+	r.failedKeysMu.Unlock()
+	if !probe {
+		return nil, ErrAllKeysRateLimited
+	}
+	r.logger.Info("all API keys rate limited, probing one",
+		zap.String("provider_id", providerID.String()), zap.Int("rate_limited_keys", len(limited)))
+	return selectWeightedKey(r.rng, limited)
+}
+
+// KeyCooldowns returns the keys among ids that are temporarily failed, i.e.
+// skipped by key selection, with the recorded failure reason of each.
+func (r *Router) KeyCooldowns(ctx context.Context, ids []uuid.UUID) map[uuid.UUID]string {
+	reasons := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return reasons
+	}
+	if r.redisClient != nil {
+		redisKeys := make([]string, len(ids))
+		for i, id := range ids {
+			redisKeys[i] = failedKeyPrefix + id.String()
+		}
+		vals, err := r.redisClient.MGet(ctx, redisKeys...).Result()
+		if err == nil {
+			for i, v := range vals {
+				if reason, ok := v.(string); ok {
+					reasons[ids[i]] = reason
+				}
+			}
+			return reasons
 		}
-		r.failedKeysMu.Unlock()
+		r.logger.Debug("redis failed for key cooldowns, using in-memory fallback", zap.Error(err))
 	}
 
-	return selectWeightedKey(availableKeys)
+	r.failedKeysMu.RLock()
+	defer r.failedKeysMu.RUnlock()
+	for _, id := range ids {
+		if info, ok := r.failedKeys[id]; ok && time.Since(info.FailedAt) <= failedKeyTTL {
+			reasons[id] = info.Reason
+		}
+	}
+	return reasons
 }
 
 // SelectNextAPIKey selects the next available API key, excluding the current one.
@@ -506,9 +795,11 @@ func (r *Router) SelectNextAPIKey(ctx context.Context, providerID uuid.UUID, exc
 		return nil, err
 	}
 
-	// Filter out the excluded key and temporarily failed keys
+	r.keysReloaded(providerID, keys)
+
+	// Filter out the excluded key, temporarily failed keys and capped keys
 	availableKeys := make([]models.ProviderAPIKey, 0, len(keys))
-	for _, k := range keys {
+	for _, k := range r.filterCappedKeys(ctx, keys) {
 		if k.ID != excludeKeyID && !r.isKeyTemporarilyFailed(k.ID) {
 			availableKeys = append(availableKeys, k)
 		}
@@ -518,17 +809,43 @@ func (r *Router) SelectNextAPIKey(ctx context.Context, providerID uuid.UUID, exc
 		return nil, errors.New("no alternative API keys available")
 	}
 
-	return selectWeightedKey(availableKeys)
+	return r.pickKey(availableKeys)
 }
 
 // selectWeightedKey selects a key from the given slice using priority-then-weighted-random.
 // Keys with the lowest (best) priority value are considered first, then weighted
-// random selection is applied among those keys.
-func selectWeightedKey(keys []models.ProviderAPIKey) (*models.ProviderAPIKey, error) {
+// random selection is applied among those keys, drawing from rng.
+func selectWeightedKey(rng RandomSource, keys []models.ProviderAPIKey) (*models.ProviderAPIKey, error) {
 	if len(keys) == 0 {
 		return nil, errors.New("no keys available")
 	}
+	priorityKeys := bestPriorityKeys(keys)
+
+	// Weighted random selection
+	var totalWeight float64
+	for _, k := range priorityKeys {
+		totalWeight += k.Weight
+	}
+
+	if totalWeight == 0 {
+		return &priorityKeys[rng.Intn(len(priorityKeys))], nil
+	}
+
+	random := rng.Float64() * totalWeight
+	var cumulative float64
+	for i := range priorityKeys {
+		cumulative += priorityKeys[i].Weight
+		if random <= cumulative {
+			return &priorityKeys[i], nil
+		}
+	}
+
+	return &priorityKeys[len(priorityKeys)-1], nil
+}
 
+// bestPriorityKeys returns the keys with the lowest (best) priority value; an
+// unset priority counts as 1.
+func bestPriorityKeys(keys []models.ProviderAPIKey) []models.ProviderAPIKey {
 	// Find the best (lowest) priority among keys
 	bestPriority := math.MaxInt32
 	for _, k := range keys {
@@ -552,45 +869,5 @@ func selectWeightedKey(keys []models.ProviderAPIKey) (*models.ProviderAPIKey, er
 			priorityKeys = append(priorityKeys, k)
 		}
 	}
-
-	// Weighted random selection
-	var totalWeight float64
-	for _, k := range priorityKeys {
-		totalWeight += k.Weight
-	}
-
-	if totalWeight == 0 {
-		return &priorityKeys[secureRandomInt(len(priorityKeys))], nil
-	}
-
-	random := secureRandomFloat64() * totalWeight
-	var cumulative float64
-	for i := range priorityKeys {
-		cumulative += priorityKeys[i].Weight
-		if random <= cumulative {
-			return &priorityKeys[i], nil
-		}
-	}
-
-	return &priorityKeys[len(priorityKeys)-1], nil
-}
-
-// ─── Cryptographic Random Utilities ────────────────────────────────────────
-
-// secureRandomInt returns a cryptographically secure random int in [0, n).
-func secureRandomInt(n int) int {
-	if n <= 0 {
-		return 0
-	}
-	var b [4]byte
-	_, _ = rand.Read(b[:])
-	// #nosec G115 - n is guaranteed to be positive and within bounds for array indexing
-	return int(binary.LittleEndian.Uint32(b[:]) % uint32(n))
-}
-
-// secureRandomFloat64 returns a cryptographically secure random float64 in [0, 1).
-func secureRandomFloat64() float64 {
-	var b [8]byte
-	_, _ = rand.Read(b[:])
-	return float64(binary.LittleEndian.Uint64(b[:])>>11) / (1 << 53)
+	return priorityKeys
 }
@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
 	"llm-router-platform/internal/service/provider"
 
 	"github.com/google/uuid"
@@ -20,16 +24,17 @@ import (
 type mockProviderRepo struct {
 	providers []models.Provider
 	err       error
+	writeErr  error // returned by Create and Update
 }
 
-func (m *mockProviderRepo) Create(_ context.Context, _ *models.Provider) error { return nil }
+func (m *mockProviderRepo) Create(_ context.Context, _ *models.Provider) error { return m.writeErr }
 func (m *mockProviderRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Provider, error) {
 	for i := range m.providers {
 		if m.providers[i].ID == id {
 			return &m.providers[i], nil
 		}
 	}
-	return nil, errors.New("not found")
+	return nil, repository.ErrNotFound
 }
 func (m *mockProviderRepo) GetByName(_ context.Context, name string) (*models.Provider, error) {
 	for i := range m.providers {
@@ -37,7 +42,7 @@ func (m *mockProviderRepo) GetByName(_ context.Context, name string) (*models.Pr
 			return &m.providers[i], nil
 		}
 	}
-	return nil, errors.New("not found")
+	return nil, repository.ErrNotFound
 }
 func (m *mockProviderRepo) GetActive(_ context.Context) ([]models.Provider, error) {
 	if m.err != nil {
@@ -54,15 +59,19 @@ func (m *mockProviderRepo) GetActive(_ context.Context) ([]models.Provider, erro
 func (m *mockProviderRepo) GetAll(_ context.Context) ([]models.Provider, error) {
 	return m.providers, m.err
 }
-func (m *mockProviderRepo) Update(_ context.Context, _ *models.Provider) error { return nil }
-func (m *mockProviderRepo) Delete(_ context.Context, _ uuid.UUID) error         { return nil }
+func (m *mockProviderRepo) Update(_ context.Context, _ *models.Provider) error { return m.writeErr }
+func (m *mockProviderRepo) Delete(_ context.Context, _ uuid.UUID) error        { return nil }
 
 type mockProviderAPIKeyRepo struct {
 	keys map[uuid.UUID][]models.ProviderAPIKey // providerID -> keys
 	err  error
 }
 
-func (m *mockProviderAPIKeyRepo) Create(_ context.Context, _ *models.ProviderAPIKey) error {
+func (m *mockProviderAPIKeyRepo) Create(_ context.Context, key *models.ProviderAPIKey) error {
+	if m.keys != nil {
+		key.ID = uuid.New()
+		m.keys[key.ProviderID] = append(m.keys[key.ProviderID], *key)
+	}
 	return nil
 }
 func (m *mockProviderAPIKeyRepo) GetActiveByProvider(_ context.Context, providerID uuid.UUID) ([]models.ProviderAPIKey, error) {
@@ -88,7 +97,7 @@ func (m *mockProviderAPIKeyRepo) GetByID(_ context.Context, id uuid.UUID) (*mode
 			}
 		}
 	}
-	return nil, errors.New("not found")
+	return nil, repository.ErrNotFound
 }
 func (m *mockProviderAPIKeyRepo) GetAll(_ context.Context) ([]models.ProviderAPIKey, error) {
 	var all []models.ProviderAPIKey
@@ -102,14 +111,34 @@ func (m *mockProviderAPIKeyRepo) Update(_ context.Context, _ *models.ProviderAPI
 }
 func (m *mockProviderAPIKeyRepo) Delete(_ context.Context, _ uuid.UUID) error { return nil }
 
-type mockProxyRepo struct{}
+type mockProxyRepo struct {
+	proxies []models.Proxy
+}
 
-func (m *mockProxyRepo) Create(_ context.Context, _ *models.Proxy) error                   { return nil }
-func (m *mockProxyRepo) GetByID(_ context.Context, _ uuid.UUID) (*models.Proxy, error)      { return nil, errors.New("not found") }
-func (m *mockProxyRepo) GetActive(_ context.Context) ([]models.Proxy, error)                { return nil, nil }
-func (m *mockProxyRepo) GetAll(_ context.Context) ([]models.Proxy, error)                   { return nil, nil }
-func (m *mockProxyRepo) Update(_ context.Context, _ *models.Proxy) error                    { return nil }
-func (m *mockProxyRepo) Delete(_ context.Context, _ uuid.UUID) error                        { return nil }
+func (m *mockProxyRepo) Create(_ context.Context, _ *models.Proxy) error { return nil }
+func (m *mockProxyRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Proxy, error) {
+	for i := range m.proxies {
+		if m.proxies[i].ID == id {
+			return &m.proxies[i], nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (m *mockProxyRepo) GetActive(_ context.Context) ([]models.Proxy, error) {
+	var active []models.Proxy
+	for _, p := range m.proxies {
+		if p.IsActive {
+			active = append(active, p)
+		}
+	}
+	return active, nil
+}
+func (m *mockProxyRepo) GetAll(_ context.Context) ([]models.Proxy, error) { return nil, nil }
+func (m *mockProxyRepo) Update(_ context.Context, _ *models.Proxy) error  { return nil }
+func (m *mockProxyRepo) ResetStats(_ context.Context, _ []uuid.UUID) (int64, error) {
+	return 0, nil
+}
+func (m *mockProxyRepo) Delete(_ context.Context, _ uuid.UUID) error { return nil }
 
 type mockModelRepo struct {
 	models map[uuid.UUID][]models.Model // providerID -> models
@@ -123,7 +152,7 @@ func (m *mockModelRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Model,
 			}
 		}
 	}
-	return nil, errors.New("not found")
+	return nil, repository.ErrNotFound
 }
 func (m *mockModelRepo) GetByName(_ context.Context, name string) (*models.Model, error) {
 	for _, mods := range m.models {
@@ -133,7 +162,7 @@ func (m *mockModelRepo) GetByName(_ context.Context, name string) (*models.Model
 			}
 		}
 	}
-	return nil, errors.New("not found")
+	return nil, repository.ErrNotFound
 }
 func (m *mockModelRepo) GetByProvider(_ context.Context, providerID uuid.UUID) ([]models.Model, error) {
 	return m.models[providerID], nil
@@ -151,7 +180,7 @@ type mockRoutingRuleRepo struct {
 
 func (m *mockRoutingRuleRepo) Create(_ context.Context, _ *models.RoutingRule) error { return nil }
 func (m *mockRoutingRuleRepo) GetByID(_ context.Context, _ uuid.UUID) (*models.RoutingRule, error) {
-	return nil, errors.New("not found")
+	return nil, repository.ErrNotFound
 }
 func (m *mockRoutingRuleRepo) GetAll(_ context.Context) ([]models.RoutingRule, error) {
 	return m.rules, nil
@@ -168,6 +197,43 @@ func (m *mockRoutingRuleRepo) GetActive(_ context.Context) ([]models.RoutingRule
 func (m *mockRoutingRuleRepo) Update(_ context.Context, _ *models.RoutingRule) error { return nil }
 func (m *mockRoutingRuleRepo) Delete(_ context.Context, _ uuid.UUID) error           { return nil }
 
+type mockFallbackChainRepo struct {
+	chains []models.FallbackChain
+	loads  int
+}
+
+func (m *mockFallbackChainRepo) Create(_ context.Context, _ *models.FallbackChain) error { return nil }
+func (m *mockFallbackChainRepo) GetByID(_ context.Context, _ uuid.UUID) (*models.FallbackChain, error) {
+	return nil, repository.ErrNotFound
+}
+func (m *mockFallbackChainRepo) GetAll(_ context.Context) ([]models.FallbackChain, error) {
+	return m.chains, nil
+}
+func (m *mockFallbackChainRepo) GetActive(_ context.Context) ([]models.FallbackChain, error) {
+	m.loads++
+	var active []models.FallbackChain
+	for _, c := range m.chains {
+		if c.IsEnabled {
+			active = append(active, c)
+		}
+	}
+	return active, nil
+}
+func (m *mockFallbackChainRepo) Update(_ context.Context, _ *models.FallbackChain) error { return nil }
+func (m *mockFallbackChainRepo) Delete(_ context.Context, _ uuid.UUID) error             { return nil }
+
+// mockUsageLogRepo serves per-key aggregates; other UsageLogRepo methods are unused.
+type mockUsageLogRepo struct {
+	repository.UsageLogRepo
+	rows    []repository.ProviderKeyUsageRow
+	queries int
+}
+
+func (m *mockUsageLogRepo) AggregateByProviderKeySince(_ context.Context, _ []uuid.UUID, _ time.Time) ([]repository.ProviderKeyUsageRow, error) {
+	m.queries++
+	return m.rows, nil
+}
+
 // --- Helper to create a test router ---
 
 func newTestRouter(providerRepo *mockProviderRepo, keyRepo *mockProviderAPIKeyRepo) *Router {
@@ -181,6 +247,7 @@ func newTestRouter(providerRepo *mockProviderRepo, keyRepo *mockProviderAPIKeyRe
 		&mockProxyRepo{},
 		&mockModelRepo{models: make(map[uuid.UUID][]models.Model)},
 		&mockRoutingRuleRepo{rules: []models.RoutingRule{}},
+		&mockFallbackChainRepo{},
 		provider.NewRegistry(logger),
 		nil,  // mcpService
 		logger,
@@ -414,6 +481,53 @@ func TestRoute_MultipleProviders_WeightedStrategy(t *testing.T) {
 		"openai (weight 0.7) should be selected more than anthropic (weight 0.3)")
 }
 
+func TestRoute_RoundRobin_EvenDistribution(t *testing.T) {
+	repo := &mockProviderRepo{
+		providers: []models.Provider{
+			{Name: "alpha", IsActive: true, RequiresAPIKey: false},
+			{Name: "beta", IsActive: true, RequiresAPIKey: false},
+			{Name: "gamma", IsActive: true, RequiresAPIKey: false},
+		},
+	}
+	for i := range repo.providers {
+		repo.providers[i].ID = uuid.New()
+	}
+
+	r := newTestRouter(repo, nil)
+	r.SetStrategy(StrategyRoundRobin)
+
+	counts := map[string]int{}
+	for i := 0; i < 6; i++ {
+		p, _, err := r.Route(context.Background(), "some-model")
+		require.NoError(t, err)
+		counts[p.Name]++
+	}
+
+	assert.Equal(t, 2, counts["alpha"])
+	assert.Equal(t, 2, counts["beta"])
+	assert.Equal(t, 2, counts["gamma"])
+}
+
+func TestSelectRoundRobin_StableAcrossProviderChanges(t *testing.T) {
+	providers := []models.Provider{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	providers[0].ID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	providers[1].ID = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	providers[2].ID = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	r := newTestRouter(&mockProviderRepo{}, nil)
+
+	assert.Equal(t, "a", r.selectRoundRobin(providers).Name)
+	assert.Equal(t, "b", r.selectRoundRobin(providers).Name)
+
+	// Removing the provider just served must not skip its successor.
+	withoutB := []models.Provider{providers[0], providers[2]}
+	assert.Equal(t, "c", r.selectRoundRobin(withoutB).Name)
+
+	// Order of the input slice must not affect the rotation.
+	reversed := []models.Provider{providers[2], providers[1], providers[0]}
+	assert.Equal(t, "a", r.selectRoundRobin(reversed).Name)
+	assert.Equal(t, "b", r.selectRoundRobin(reversed).Name)
+}
+
 func TestRouteWithFallback_PicksHighestPriority(t *testing.T) {
 	pid1 := uuid.New()
 	pid2 := uuid.New()
@@ -449,6 +563,57 @@ func TestRouteWithFallback_PicksHighestPriority(t *testing.T) {
 	}
 }
 
+func TestRouteToProvider(t *testing.T) {
+	active := models.Provider{Name: "openai", IsActive: true, RequiresAPIKey: true, Priority: 1}
+	active.ID = uuid.New()
+	inactive := models.Provider{Name: "anthropic", IsActive: false}
+	inactive.ID = uuid.New()
+	keyRepo := &mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{
+		active.ID: {{ProviderID: active.ID, IsActive: true, Weight: 1}},
+	}}
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{active, inactive}}, keyRepo)
+
+	p, key, err := r.RouteToProvider(context.Background(), "openai")
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, p.ID)
+	assert.NotNil(t, key)
+
+	_, _, err = r.RouteToProvider(context.Background(), "anthropic")
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+
+	_, _, err = r.RouteToProvider(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+}
+
+func TestSetProviderDraining(t *testing.T) {
+	openai := models.Provider{Name: "openai", IsActive: true, RequiresAPIKey: true, Priority: 1}
+	openai.ID = uuid.New()
+	keyRepo := &mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{
+		openai.ID: {{ProviderID: openai.ID, IsActive: true, Weight: 1}},
+	}}
+	r := newTestRouter(&mockProviderRepo{providers: []models.Provider{openai}}, keyRepo)
+	ctx := context.Background()
+
+	p, err := r.SetProviderDraining(ctx, openai.ID, true)
+	require.NoError(t, err)
+	assert.True(t, p.Draining)
+	assert.True(t, p.IsActive, "draining is distinct from disabling")
+
+	_, _, err = r.Route(ctx, "gpt-4o")
+	assert.Error(t, err, "a draining provider gets no new requests")
+	_, _, err = r.RouteToProvider(ctx, "openai")
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+
+	_, err = r.SetProviderDraining(ctx, openai.ID, false)
+	require.NoError(t, err)
+	routed, _, err := r.Route(ctx, "gpt-4o")
+	require.NoError(t, err)
+	assert.Equal(t, openai.ID, routed.ID)
+
+	_, err = r.SetProviderDraining(ctx, uuid.New(), true)
+	assert.ErrorIs(t, err, ErrProviderNotFound)
+}
+
 func TestIsQuotaOrRateLimitError(t *testing.T) {
 	tests := []struct {
 		msg    string
@@ -471,6 +636,31 @@ func TestIsQuotaOrRateLimitError(t *testing.T) {
 	}
 }
 
+func TestIsQuotaError_ConfiguredKeywords(t *testing.T) {
+	r := newTestRouter(&mockProviderRepo{}, nil)
+	azure := &models.Provider{Name: "Azure"}
+	openai := &models.Provider{Name: "openai"}
+
+	// Defaults apply until keywords are configured.
+	assert.True(t, r.isQuotaError(openai, errors.New("429 Too Many Requests")))
+	assert.False(t, r.isQuotaError(azure, errors.New("server busy")))
+
+	r.SetQuotaKeywords(nil, map[string][]string{"azure": {"Server Busy"}})
+	assert.True(t, r.isQuotaError(azure, errors.New("upstream server busy, retry later")))
+	assert.False(t, r.isQuotaError(openai, errors.New("upstream server busy, retry later")))
+	assert.True(t, r.isQuotaError(openai, errors.New("quota exceeded")), "empty global list keeps defaults")
+
+	// Keywords match the provider's message and raw body, not just the summary.
+	busy := &provider.ProviderError{StatusCode: 503, Body: []byte(`{"error":{"code":"unavailable","message":"try again later","details":"Server Busy"}}`)}
+	assert.True(t, r.isQuotaError(azure, fmt.Errorf("chat: %w", busy)))
+	assert.True(t, r.isQuotaError(azure, &provider.ProviderError{StatusCode: 503, Message: "server busy"}))
+	assert.False(t, r.isQuotaError(openai, busy))
+
+	r.SetQuotaKeywords([]string{"throttled"}, nil)
+	assert.True(t, r.isQuotaError(openai, errors.New("request throttled")))
+	assert.False(t, r.isQuotaError(openai, errors.New("429 Too Many Requests")))
+}
+
 func TestMatchesGlobPattern(t *testing.T) {
 	tests := []struct {
 		model   string
@@ -550,3 +740,168 @@ func TestRoute_ModelPatterns_NoMatch_FallsBackToHeuristic(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "anthropic", p.Name)
 }
+
+func TestRoute_HeuristicMatchesProviderType(t *testing.T) {
+	repo := &mockProviderRepo{
+		providers: []models.Provider{
+			{Name: "gateway", Type: "openai-compatible", IsActive: true, RequiresAPIKey: false, Priority: 10, Weight: 1.0},
+			{Name: "claude-eu", Type: "anthropic", IsActive: true, RequiresAPIKey: false, Priority: 10, Weight: 1.0},
+		},
+	}
+	repo.providers[0].ID = uuid.New()
+	repo.providers[1].ID = uuid.New()
+
+	r := newTestRouter(repo, nil)
+
+	// No provider is named "anthropic"; the instance of that type serves Claude.
+	p, _, err := r.Route(context.Background(), "claude-3-opus")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-eu", p.Name)
+}
+
+func TestRoute_SkipsProviderWhoseKeysAreCoolingDown(t *testing.T) {
+	patterns, _ := json.Marshal([]string{"gpt-*"})
+	repo := &mockProviderRepo{
+		providers: []models.Provider{
+			{Name: "gateway", Type: "openai-compatible", IsActive: true, RequiresAPIKey: true, Priority: 10, Weight: 1.0, ModelPatterns: patterns},
+			{Name: "openai", IsActive: true, RequiresAPIKey: true, Priority: 10, Weight: 1.0},
+		},
+	}
+	gateway, openai := uuid.New(), uuid.New()
+	repo.providers[0].ID = gateway
+	repo.providers[1].ID = openai
+	gatewayKeys, openaiKeys := aliasedKeys(1), aliasedKeys(1)
+	keyRepo := &mockProviderAPIKeyRepo{keys: map[uuid.UUID][]models.ProviderAPIKey{gateway: gatewayKeys, openai: openaiKeys}}
+	r := newTestRouter(repo, keyRepo)
+	ctx := context.Background()
+
+	r.MarkKeyFailed(gatewayKeys[0].ID, "insufficient_quota")
+	p, key, err := r.Route(ctx, "gpt-4")
+	require.NoError(t, err)
+	assert.Equal(t, "openai", p.Name, "the next provider serving the model takes the request")
+	assert.Equal(t, openaiKeys[0].ID, key.ID)
+
+	r.MarkKeyFailed(openaiKeys[0].ID, "429 rate limit exceeded")
+	r.keyProbes[openai] = time.Now()
+	_, _, err = r.Route(ctx, "gpt-4")
+	assert.ErrorIs(t, err, ErrAllKeysRateLimited, "rate limits recover first, so they are reported over failures")
+	assert.Equal(t, allKeysProbeInterval, KeysRetryAfter(err))
+}
+
+func TestRoute_UnknownModel_Reject(t *testing.T) {
+	pid := uuid.New()
+	repo := &mockProviderRepo{
+		providers: []models.Provider{
+			{Name: "custom-provider", IsActive: true, RequiresAPIKey: false, Priority: 10, Weight: 1.0},
+		},
+	}
+	repo.providers[0].ID = pid
+
+	r := newTestRouter(repo, nil)
+	r.modelRepo = &mockModelRepo{models: map[uuid.UUID][]models.Model{
+		pid: {{ProviderID: pid, Name: "house-model", IsActive: true}},
+	}}
+	r.SetUnknownModelPolicy(UnknownModelReject, "")
+
+	_, _, err := r.Route(context.Background(), "mystery-model")
+	var unsupported *ModelNotSupportedError
+	require.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, "mystery-model", unsupported.Model)
+	assert.Equal(t, []string{"house-model"}, unsupported.KnownModels)
+	assert.Contains(t, err.Error(), "known models: house-model")
+
+	// Known models still route normally.
+	p, _, err := r.Route(context.Background(), "house-model")
+	require.NoError(t, err)
+	assert.Equal(t, "custom-provider", p.Name)
+}
+
+func TestRoute_UnknownModel_CatchAll(t *testing.T) {
+	repo := &mockProviderRepo{
+		providers: []models.Provider{
+			{Name: "custom-provider", IsActive: true, RequiresAPIKey: false, Priority: 100, Weight: 100},
+			{Name: "openrouter", IsActive: true, RequiresAPIKey: false, Priority: 1, Weight: 1},
+		},
+	}
+	repo.providers[0].ID = uuid.New()
+	repo.providers[1].ID = uuid.New()
+
+	r := newTestRouter(repo, nil)
+	r.SetUnknownModelPolicy(UnknownModelCatchAll, "OpenRouter")
+
+	for i := 0; i < 5; i++ {
+		p, _, err := r.Route(context.Background(), "mystery-model")
+		require.NoError(t, err)
+		assert.Equal(t, "openrouter", p.Name)
+	}
+}
+
+func TestRoute_UnknownModel_CatchAllInactive(t *testing.T) {
+	repo := &mockProviderRepo{
+		providers: []models.Provider{
+			{Name: "custom-provider", IsActive: true, RequiresAPIKey: false, Priority: 10, Weight: 1.0},
+		},
+	}
+	repo.providers[0].ID = uuid.New()
+
+	r := newTestRouter(repo, nil)
+	r.SetUnknownModelPolicy(UnknownModelCatchAll, "openrouter")
+
+	_, _, err := r.Route(context.Background(), "mystery-model")
+	var unsupported *ModelNotSupportedError
+	require.ErrorAs(t, err, &unsupported)
+}
+
+func TestModelNotSupportedError_TruncatesKnownModels(t *testing.T) {
+	known := make([]string, maxListedModels+3)
+	for i := range known {
+		known[i] = fmt.Sprintf("m%d", i)
+	}
+	err := &ModelNotSupportedError{Model: "x", KnownModels: known}
+	assert.Contains(t, err.Error(), "(and 3 more)")
+	assert.NotContains(t, err.Error(), fmt.Sprintf("m%d", maxListedModels))
+}
+
+func TestRoute_UnknownModel_RejectSuggestsSimilarModels(t *testing.T) {
+	pid := uuid.New()
+	repo := &mockProviderRepo{
+		providers: []models.Provider{
+			{Name: "custom-provider", IsActive: true, RequiresAPIKey: false, Priority: 10, Weight: 1.0},
+		},
+	}
+	repo.providers[0].ID = pid
+
+	r := newTestRouter(repo, nil)
+	r.modelRepo = &mockModelRepo{models: map[uuid.UUID][]models.Model{
+		pid: {
+			{ProviderID: pid, Name: "house-model-v2", IsActive: true},
+			{ProviderID: pid, Name: "embed-small", IsActive: true},
+		},
+	}}
+	r.SetUnknownModelPolicy(UnknownModelReject, "")
+
+	_, _, err := r.Route(context.Background(), "house-modelv2")
+	var unsupported *ModelNotSupportedError
+	require.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, []string{"house-model-v2"}, unsupported.Suggestions)
+	assert.Contains(t, err.Error(), "did you mean house-model-v2?")
+}
+
+func TestSuggestModels(t *testing.T) {
+	known := []string{"gpt-4", "gpt-4o", "gpt-3.5-turbo", "claude-3-haiku", "llama3"}
+
+	assert.Equal(t, []string{"gpt-4", "gpt-4o"}, suggestModels("gpt4", known))
+	assert.Equal(t, []string{"gpt-4", "gpt-4o"}, suggestModels("openai/GPT-4", known), "vendor prefix and case are ignored")
+	assert.Equal(t, []string{"claude-3-haiku"}, suggestModels("claude-3-haiku-x", known))
+	assert.Empty(t, suggestModels("mistral-large", known))
+	assert.Empty(t, suggestModels("", known))
+	assert.Equal(t, []string{"gpt-4o"}, suggestModels("gpt-4", known), "a model is not suggested for itself")
+	assert.Empty(t, suggestModels(strings.Repeat("gpt4", maxSuggestedModelLen), known), "oversized names are not compared")
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("gpt4", "gpt4"))
+	assert.Equal(t, 1, levenshtein("gpt4", "gpt4o"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "abcd"))
+}
@@ -17,7 +17,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// heuristicPrefixes maps provider name to model name prefixes for last-resort heuristic matching.
+// heuristicPrefixes maps provider type to model name prefixes for last-resort heuristic matching.
 var heuristicPrefixes = map[string][]string{
 	"google":    {"gemini", "gemma", "embedding", "text-embedding", "imagen", "veo", "aqa"},
 	"openai":    {"gpt-", "o1", "o3", "o4", "chatgpt", "text-davinci", "dall-e", "whisper", "tts"},
@@ -26,7 +26,7 @@ var heuristicPrefixes = map[string][]string{
 	"mistral":   {"mistral", "mixtral", "codestral", "pixtral", "open-mistral", "open-mixtral"},
 }
 
-// heuristicContains maps provider name to model name substrings for local/self-hosted providers.
+// heuristicContains maps provider type to model name substrings for local/self-hosted providers.
 var heuristicContains = map[string][]string{
 	"ollama":   {"llama", "codellama", "vicuna", "phi", "yi-", "qwen", "mistral", "cosyvoice", "fish-speech", "chattts", "bark"},
 	"lmstudio": {"llama", "codellama", "vicuna", "phi", "yi-", "qwen", "mistral", "cosyvoice", "fish-speech", "chattts", "bark"},
@@ -46,12 +46,13 @@ func (r *Router) findProviderForModel(modelName string, providers []models.Provi
 	// 1. Check cached DB model assignments (refreshed every 5 minutes).
 	if r.modelRepo != nil {
 		modelMap := r.getModelProviderCache(providers)
-		if providerIdx, ok := modelMap[strings.ToLower(actualModel)]; ok {
+		if idxs, ok := modelMap[strings.ToLower(actualModel)]; ok {
+			p := r.selectAmongServing(providers, idxs)
 			r.logger.Debug("model matched via database cache",
 				zap.String("model", sanitize.LogValue(modelName)),
-				zap.String("provider", providers[providerIdx].Name),
+				zap.String("provider", p.Name),
 			)
-			return &providers[providerIdx]
+			return p
 		}
 	}
 
@@ -60,69 +61,87 @@ func (r *Router) findProviderForModel(modelName string, providers []models.Provi
 	if discoveryMap == nil {
 		discoveryMap = r.refreshDiscoveryCache(providers)
 	}
-	if providerName, ok := discoveryMap[strings.ToLower(actualModel)]; ok {
+	if names, ok := discoveryMap[strings.ToLower(actualModel)]; ok {
+		var idxs []int
 		for i := range providers {
-			if strings.EqualFold(providers[i].Name, providerName) {
-				r.logger.Debug("model matched via upstream discovery cache",
-					zap.String("model", sanitize.LogValue(modelName)),
-					zap.String("provider", providerName),
-				)
-				return &providers[i]
+			if slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(providers[i].Name, name) }) {
+				idxs = append(idxs, i)
 			}
 		}
+		if len(idxs) > 0 {
+			p := r.selectAmongServing(providers, idxs)
+			r.logger.Debug("model matched via upstream discovery cache",
+				zap.String("model", sanitize.LogValue(modelName)),
+				zap.String("provider", p.Name),
+			)
+			return p
+		}
 	}
 
 	modelLower := strings.ToLower(actualModel)
 
 	// 3. Configurable model patterns from Provider.ModelPatterns.
-	for i := range providers {
-		patterns := providers[i].GetModelPatterns()
-		if len(patterns) == 0 {
-			continue
-		}
-		for _, pattern := range patterns {
-			if matchesGlobPattern(modelLower, strings.ToLower(pattern)) {
-				r.logger.Debug("model matched via configured patterns",
-					zap.String("model", sanitize.LogValue(modelName)),
-					zap.String("provider", providers[i].Name),
-					zap.String("pattern", pattern),
-				)
-				return &providers[i]
-			}
-		}
+	if idxs := matchModelPatterns(modelLower, providers); len(idxs) > 0 {
+		p := r.selectAmongServing(providers, idxs)
+		r.logger.Debug("model matched via configured patterns",
+			zap.String("model", sanitize.LogValue(modelName)),
+			zap.String("provider", p.Name),
+		)
+		return p
 	}
 
 	// 4. Heuristic fallback (data-driven).
-	if p := r.matchHeuristicFallback(modelLower, providers); p != nil {
-		return p
+	if idxs := matchHeuristicFallback(modelLower, providers); len(idxs) > 0 {
+		return r.selectAmongServing(providers, idxs)
 	}
 
 	return nil
 }
 
-// matchHeuristicFallback uses data-driven maps to match a model name to a provider
-// via prefix or substring matching. This replaces the former switch-case block.
-func (r *Router) matchHeuristicFallback(modelLower string, providers []models.Provider) *models.Provider {
+// matchModelPatterns returns the indexes of the providers with a configured
+// model pattern matching modelLower.
+func matchModelPatterns(modelLower string, providers []models.Provider) []int {
+	var idxs []int
 	for i := range providers {
-		p := &providers[i]
-		// Check prefix-based matching
-		if prefixes, ok := heuristicPrefixes[p.Name]; ok {
-			for _, prefix := range prefixes {
-				if strings.HasPrefix(modelLower, prefix) {
-					return p
-				}
+		for _, pattern := range providers[i].GetModelPatterns() {
+			if matchesGlobPattern(modelLower, strings.ToLower(pattern)) {
+				idxs = append(idxs, i)
+				break
 			}
 		}
-		// Check substring-based matching (local providers)
-		if substrings, ok := heuristicContains[p.Name]; ok {
-			for _, substr := range substrings {
-				if strings.Contains(modelLower, substr) {
-					return p
-				}
-			}
+	}
+	return idxs
+}
+
+// matchHeuristicFallback uses data-driven maps to match a model name to
+// providers via prefix or substring matching on their type, so every
+// instance of a protocol (e.g. "openai-eu" of type openai) is a candidate.
+// It returns the indexes of all matching providers.
+func matchHeuristicFallback(modelLower string, providers []models.Provider) []int {
+	var idxs []int
+	for i := range providers {
+		if heuristicMatches(modelLower, providers[i].ClientType()) {
+			idxs = append(idxs, i)
 		}
 	}
-	return nil
+	return idxs
+}
+
+// heuristicMatches reports whether the heuristics for providerType claim
+// modelLower: a known prefix for hosted APIs, or a known substring for
+// local providers.
+func heuristicMatches(modelLower, providerType string) bool {
+	for _, prefix := range heuristicPrefixes[providerType] {
+		if strings.HasPrefix(modelLower, prefix) {
+			return true
+		}
+	}
+	for _, substr := range heuristicContains[providerType] {
+		if strings.Contains(modelLower, substr) {
+			return true
+		}
+	}
+	return false
 }
 
 // matchesGlobPattern checks if a model name matches a glob-style pattern.
@@ -138,29 +157,56 @@ func matchesGlobPattern(modelName, pattern string) bool {
 }
 
 // selectRoundRobin selects provider using round-robin.
+// The rotation is keyed on provider IDs rather than slice positions: providers
+// are ordered by ID and the cursor remembers the last ID served, so the next
+// pick is the first provider whose ID sorts after it. Adding or removing a
+// provider therefore never causes another provider to be skipped or repeated.
 func (r *Router) selectRoundRobin(providers []models.Provider) *models.Provider {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.roundRobinIndex = (r.roundRobinIndex + 1) % len(providers)
-	return &providers[r.roundRobinIndex]
+	ordered := make([]*models.Provider, len(providers))
+	for i := range providers {
+		ordered[i] = &providers[i]
+	}
+	slices.SortFunc(ordered, func(a, b *models.Provider) int {
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+
+	next := ordered[0]
+	if r.roundRobinLast != uuid.Nil {
+		last := r.roundRobinLast.String()
+		for _, p := range ordered {
+			if p.ID.String() > last {
+				next = p
+				break
+			}
+		}
+	}
+
+	r.roundRobinLast = next.ID
+	return next
 }
 
-// selectWeighted selects provider based on weights.
+// selectWeighted selects provider based on weights, limiting canary
+// providers to their TrafficPercentage (see trafficShares) and scaling down
+// providers that breach their latency SLA (see SetLatencyPenalty).
 func (r *Router) selectWeighted(providers []models.Provider) *models.Provider {
-	var totalWeight float64
-	for _, p := range providers {
-		totalWeight += p.Weight
+	shares := trafficShares(providers)
+	r.applyLatencyPenalties(providers, shares)
+	var total float64
+	for _, s := range shares {
+		total += s
 	}
 
-	if totalWeight == 0 {
-		return &providers[secureRandomInt(len(providers))]
+	if total == 0 {
+		return &providers[r.rng.Intn(len(providers))]
 	}
 
-	random := secureRandomFloat64() * totalWeight
+	random := r.rng.Float64() * total
 	var cumulative float64
 	for i := range providers {
-		cumulative += providers[i].Weight
+		cumulative += shares[i]
 		if random <= cumulative {
 			return &providers[i]
 		}
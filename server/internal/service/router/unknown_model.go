@@ -0,0 +1,225 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"llm-router-platform/internal/models"
+	"llm-router-platform/pkg/sanitize"
+
+	"go.uber.org/zap"
+)
+
+// UnknownModelPolicy decides how Route handles a model that no routing rule,
+// model assignment, discovery result or heuristic maps to a provider.
+type UnknownModelPolicy string
+
+const (
+	// UnknownModelStrategy picks a provider with the routing strategy, as for
+	// any other request. The provider may not serve the model.
+	UnknownModelStrategy UnknownModelPolicy = "strategy"
+	// UnknownModelReject fails with a ModelNotSupportedError.
+	UnknownModelReject UnknownModelPolicy = "reject"
+	// UnknownModelCatchAll sends the request to a configured catch-all
+	// provider, such as an OpenRouter gateway.
+	UnknownModelCatchAll UnknownModelPolicy = "catch_all"
+)
+
+const (
+	// maxListedModels caps the known models included in a ModelNotSupportedError.
+	maxListedModels = 50
+	// maxSuggestions caps the similar models suggested for an unknown model.
+	maxSuggestions = 3
+	// maxSuggestedModelLen is the longest client-supplied model name that
+	// gets suggestions; the edit distance is quadratic in its length.
+	maxSuggestedModelLen = 128
+)
+
+// ModelNotSupportedError is returned by Route when no provider serves the
+// requested model and the unknown-model policy does not pick one.
+type ModelNotSupportedError struct {
+	Model       string
+	KnownModels []string
+	Suggestions []string // known models with names close to Model, closest first
+}
+
+// newModelNotSupportedError builds the error for modelName, suggesting known
+// models that look like a typo of it.
+func newModelNotSupportedError(modelName string, known []string) *ModelNotSupportedError {
+	return &ModelNotSupportedError{Model: modelName, KnownModels: known, Suggestions: suggestModels(modelName, known)}
+}
+
+// Error implements the error interface.
+func (e *ModelNotSupportedError) Error() string {
+	msg := fmt.Sprintf("model %q is not supported", e.Model)
+	if len(e.Suggestions) > 0 {
+		msg += "; did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+	if len(e.KnownModels) == 0 {
+		return msg
+	}
+	listed := e.KnownModels
+	if len(listed) > maxListedModels {
+		listed = listed[:maxListedModels]
+	}
+	msg += "; known models: " + strings.Join(listed, ", ")
+	if more := len(e.KnownModels) - len(listed); more > 0 {
+		msg += fmt.Sprintf(" (and %d more)", more)
+	}
+	return msg
+}
+
+// SetUnknownModelPolicy configures how unknown models are routed. catchAll
+// names the provider used by UnknownModelCatchAll. An empty policy keeps
+// UnknownModelStrategy. Call before the router starts serving requests.
+func (r *Router) SetUnknownModelPolicy(policy UnknownModelPolicy, catchAll string) {
+	if policy == "" {
+		policy = UnknownModelStrategy
+	}
+	r.unknownPolicy = policy
+	r.catchAll = strings.TrimSpace(catchAll)
+}
+
+// routeUnknownModel applies the unknown-model policy to modelName.
+func (r *Router) routeUnknownModel(ctx context.Context, modelName string, providers []models.Provider) (*models.Provider, error) {
+	switch r.unknownPolicy {
+	case UnknownModelReject:
+		return nil, newModelNotSupportedError(modelName, r.knownModels(providers))
+	case UnknownModelCatchAll:
+		for i := range providers {
+			if strings.EqualFold(providers[i].Name, r.catchAll) && r.IsProviderHealthy(providers[i].ID) {
+				r.logger.Debug("unknown model routed to catch-all provider",
+					zap.String("model", sanitize.LogValue(modelName)),
+					zap.String("provider", providers[i].Name),
+				)
+				return &providers[i], nil
+			}
+		}
+		r.logger.Warn("catch-all provider is not active or healthy",
+			zap.String("provider", r.catchAll),
+			zap.String("model", sanitize.LogValue(modelName)),
+		)
+		return nil, newModelNotSupportedError(modelName, r.knownModels(providers))
+	default:
+		return r.selectByStrategy(ctx, modelName, providers), nil
+	}
+}
+
+// SuggestModels returns known models with names close to modelName. The
+// default strategy routes an unknown model to some provider; when that
+// provider rejects it, the error response can still point at the likely
+// intended model.
+func (r *Router) SuggestModels(ctx context.Context, modelName string) []string {
+	providers, err := r.routableProviders(ctx)
+	if err != nil {
+		return nil
+	}
+	return suggestModels(modelName, r.knownModels(providers))
+}
+
+// knownModels lists the models the router can place, from DB model
+// assignments and upstream discovery, sorted and deduplicated.
+func (r *Router) knownModels(providers []models.Provider) []string {
+	seen := make(map[string]struct{})
+	if r.modelRepo != nil {
+		for name := range r.getModelProviderCache(providers) {
+			seen[name] = struct{}{}
+		}
+	}
+	for name := range r.getDiscoveryCache() {
+		seen[name] = struct{}{}
+	}
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// suggestModels returns up to maxSuggestions known models whose names are
+// within a small edit distance of modelName, ignoring case, a "vendor/"
+// prefix and separators, so "gpt4" suggests "gpt-4". A model is never
+// suggested for itself, and names longer than maxSuggestedModelLen get none.
+func suggestModels(modelName string, known []string) []string {
+	if len(modelName) > maxSuggestedModelLen {
+		return nil
+	}
+	target := normalizeModelName(modelName)
+	if target == "" {
+		return nil
+	}
+	targetLen := utf8.RuneCountInString(target)
+	maxDist := len(target) / 3
+	if maxDist < 1 {
+		maxDist = 1
+	}
+
+	type candidate struct {
+		name string
+		dist int
+	}
+	var candidates []candidate
+	for _, name := range known {
+		if name == modelName {
+			continue
+		}
+		normalized := normalizeModelName(name)
+		// The length difference bounds the distance from below.
+		if diff := utf8.RuneCountInString(normalized) - targetLen; diff > maxDist || -diff > maxDist {
+			continue
+		}
+		if d := levenshtein(target, normalized); d <= maxDist {
+			candidates = append(candidates, candidate{name, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].dist < candidates[j].dist })
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+	out := make([]string, len(candidates))
+	for i, c := range candidates {
+		out[i] = c.name
+	}
+	return out
+}
+
+// normalizeModelName lowercases name, drops a "vendor/" prefix and removes
+// separators that are commonly mistyped.
+func normalizeModelName(name string) string {
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.', ' ', ':':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,217 @@
+// Package shadow mirrors a sample of chat traffic to a shadow provider so a
+// provider under evaluation can be compared against production offline.
+package shadow
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"llm-router-platform/internal/config"
+	"llm-router-platform/internal/crypto"
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/provider"
+	"llm-router-platform/pkg/sanitize"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxInFlight bounds concurrent shadow requests; samples beyond it are
+	// dropped rather than queued so a slow shadow provider cannot pile up
+	// goroutines.
+	maxInFlight = 16
+	// requestTimeout bounds a single shadow request.
+	requestTimeout = 2 * time.Minute
+	// maxStoredResponse caps each response text stored on a ShadowLog,
+	// before encryption.
+	maxStoredResponse = 32 * 1024
+)
+
+// Upstream is the part of the router the shadow needs. Shadow requests go
+// straight to the provider client: no fallback, key rotation, MCP tool
+// execution or circuit-breaker accounting.
+type Upstream interface {
+	RouteToProvider(ctx context.Context, name string) (*models.Provider, *models.ProviderAPIKey, error)
+	GetProviderClientWithKey(ctx context.Context, p *models.Provider, apiKey *models.ProviderAPIKey) (provider.Client, error)
+}
+
+// Primary describes the response the client was served.
+type Primary struct {
+	UsageLogID uuid.UUID
+	ProviderID uuid.UUID
+	Model      string
+	Streamed   bool
+	Latency    time.Duration
+	Response   string
+}
+
+// Service mirrors sampled chat requests to the configured shadow provider.
+// A nil *Service is valid and never mirrors.
+type Service struct {
+	upstream  Upstream
+	repo      repository.ShadowLogRepo
+	modelRepo repository.ModelRepo
+	cfg       config.ShadowConfig
+	inFlight  chan struct{}
+	sample    func() float64
+	logger    *zap.Logger
+}
+
+// NewService creates a shadow service. It returns nil when shadowing is not
+// configured, which callers treat as disabled.
+func NewService(upstream Upstream, repo repository.ShadowLogRepo, modelRepo repository.ModelRepo, cfg config.ShadowConfig, logger *zap.Logger) *Service {
+	if cfg.Provider == "" || cfg.SampleRate <= 0 {
+		return nil
+	}
+	logger.Info("shadow traffic enabled",
+		zap.String("provider", cfg.Provider),
+		zap.String("model", cfg.Model),
+		zap.Float64("sample_rate", cfg.SampleRate))
+	if !crypto.IsInitialized() {
+		logger.Warn("shadow traffic needs an encryption key to store response texts; only metrics will be recorded")
+	}
+	return &Service{
+		upstream:  upstream,
+		repo:      repo,
+		modelRepo: modelRepo,
+		cfg:       cfg,
+		inFlight:  make(chan struct{}, maxInFlight),
+		sample:    rand.Float64, // #nosec G404 -- traffic sampling, not security sensitive
+		logger:    logger,
+	}
+}
+
+// Sample decides whether this request is mirrored. When it is, it returns a
+// copy of req taken before the primary call can modify it (MCP tool injection
+// and tool-call loops mutate the request); otherwise it returns nil.
+func (s *Service) Sample(req *provider.ChatRequest) *provider.ChatRequest {
+	if s == nil || s.sample() >= s.cfg.SampleRate {
+		return nil
+	}
+	cp := *req
+	cp.Messages = append([]provider.Message(nil), req.Messages...)
+	cp.Stop = append(provider.StopSequences(nil), req.Stop...)
+	cp.Tools = append(json.RawMessage(nil), req.Tools...)
+	cp.ToolChoice = append(json.RawMessage(nil), req.ToolChoice...)
+	cp.Stream = false
+	cp.StreamOptions = nil
+	if s.cfg.Model != "" {
+		cp.Model = s.cfg.Model
+	}
+	return &cp
+}
+
+// Mirror replays req, as returned by Sample, to the shadow provider in the
+// background and records the result. It never blocks: when too many shadow
+// requests are already running the sample is dropped.
+func (s *Service) Mirror(req *provider.ChatRequest, primary Primary) {
+	if s == nil || req == nil {
+		return
+	}
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		s.logger.Debug("shadow request dropped: too many in flight")
+		return
+	}
+	go func() {
+		defer func() { <-s.inFlight }()
+		s.run(req, primary)
+	}()
+}
+
+func (s *Service) run(req *provider.ChatRequest, primary Primary) {
+	// Detached from the client request, which has already been answered.
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	p, key, err := s.upstream.RouteToProvider(ctx, s.cfg.Provider)
+	if err != nil {
+		s.logger.Debug("shadow provider unavailable", zap.String("provider", s.cfg.Provider), zap.Error(err))
+		return
+	}
+	if p.ID == primary.ProviderID {
+		return // nothing to compare
+	}
+
+	log := &models.ShadowLog{
+		UsageLogID:        primary.UsageLogID,
+		PrimaryProviderID: primary.ProviderID,
+		ShadowProviderID:  p.ID,
+		ModelName:         primary.Model,
+		ShadowModelName:   req.Model,
+		Streamed:          primary.Streamed,
+		PrimaryLatency:    primary.Latency.Milliseconds(),
+		PrimaryResponse:   s.seal(primary.Response),
+	}
+
+	start := time.Now()
+	resp, err := s.chat(ctx, p, key, req)
+	log.ShadowLatency = time.Since(start).Milliseconds()
+	if err != nil {
+		log.StatusCode = http.StatusBadGateway
+		log.ErrorMessage = sanitize.TruncateErrorMessage(err.Error())
+	} else {
+		log.StatusCode = http.StatusOK
+		log.PromptTokens = resp.Usage.PromptTokens
+		log.CompletionTokens = resp.Usage.CompletionTokens
+		log.Cost = s.cost(ctx, p.ID, req.Model, resp.Usage)
+		if len(resp.Choices) > 0 {
+			log.ShadowResponse = s.seal(resp.Choices[0].Message.Content.Text)
+		}
+	}
+
+	if err := s.repo.Create(ctx, log); err != nil {
+		s.logger.Warn("failed to record shadow log", zap.Error(err))
+	}
+}
+
+func (s *Service) chat(ctx context.Context, p *models.Provider, key *models.ProviderAPIKey, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	client, err := s.upstream.GetProviderClientWithKey(ctx, p, key)
+	if err != nil {
+		return nil, err
+	}
+	return client.Chat(ctx, req)
+}
+
+// cost prices the shadow usage with the shadow provider's model pricing;
+// unknown models cost zero.
+func (s *Service) cost(ctx context.Context, providerID uuid.UUID, model string, usage provider.Usage) float64 {
+	list, err := s.modelRepo.GetByProvider(ctx, providerID)
+	if err != nil {
+		return 0
+	}
+	for _, m := range list {
+		if strings.EqualFold(m.Name, model) {
+			return float64(usage.PromptTokens)/1000*m.InputPricePer1K + float64(usage.CompletionTokens)/1000*m.OutputPricePer1K
+		}
+	}
+	return 0
+}
+
+// seal truncates a response text and encrypts it for storage. Without an
+// encryption key, or when encryption fails, the text is not stored.
+func (s *Service) seal(text string) string {
+	if text == "" || !crypto.IsInitialized() {
+		return ""
+	}
+	enc, err := crypto.Encrypt(truncate(text))
+	if err != nil {
+		s.logger.Warn("failed to encrypt shadow response", zap.Error(err))
+		return ""
+	}
+	return enc
+}
+
+func truncate(s string) string {
+	if len(s) <= maxStoredResponse {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxStoredResponse], "")
+}
@@ -0,0 +1,177 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"llm-router-platform/internal/config"
+	"llm-router-platform/internal/crypto"
+	"llm-router-platform/internal/models"
+	"llm-router-platform/internal/repository"
+	"llm-router-platform/internal/service/provider"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeUpstream struct {
+	provider *models.Provider
+	client   provider.Client
+}
+
+func (f *fakeUpstream) RouteToProvider(_ context.Context, _ string) (*models.Provider, *models.ProviderAPIKey, error) {
+	return f.provider, nil, nil
+}
+
+func (f *fakeUpstream) GetProviderClientWithKey(_ context.Context, _ *models.Provider, _ *models.ProviderAPIKey) (provider.Client, error) {
+	return f.client, nil
+}
+
+type fakeClient struct {
+	provider.Client
+	resp *provider.ChatResponse
+	err  error
+	got  *provider.ChatRequest
+}
+
+func (f *fakeClient) Chat(_ context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	f.got = req
+	return f.resp, f.err
+}
+
+type fakeShadowRepo struct {
+	logs chan *models.ShadowLog
+}
+
+func (f *fakeShadowRepo) Create(_ context.Context, log *models.ShadowLog) error {
+	f.logs <- log
+	return nil
+}
+
+func (f *fakeShadowRepo) DeleteOlderThan(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakeModelRepo struct {
+	repository.ModelRepo
+	models []models.Model
+}
+
+func (f *fakeModelRepo) GetByProvider(_ context.Context, _ uuid.UUID) ([]models.Model, error) {
+	return f.models, nil
+}
+
+func newTestService(t *testing.T, client *fakeClient, shadowProvider *models.Provider) (*Service, *fakeShadowRepo) {
+	t.Helper()
+	repo := &fakeShadowRepo{logs: make(chan *models.ShadowLog, 1)}
+	modelRepo := &fakeModelRepo{models: []models.Model{{Name: "candidate-model", InputPricePer1K: 0.01, OutputPricePer1K: 0.02}}}
+	svc := NewService(&fakeUpstream{provider: shadowProvider, client: client}, repo, modelRepo,
+		config.ShadowConfig{Provider: "candidate", Model: "candidate-model", SampleRate: 1}, zap.NewNop())
+	require.NotNil(t, svc)
+	return svc, repo
+}
+
+func TestNewServiceDisabled(t *testing.T) {
+	assert.Nil(t, NewService(nil, nil, nil, config.ShadowConfig{SampleRate: 1}, zap.NewNop()))
+	assert.Nil(t, NewService(nil, nil, nil, config.ShadowConfig{Provider: "candidate"}, zap.NewNop()))
+
+	var disabled *Service
+	assert.Nil(t, disabled.Sample(&provider.ChatRequest{Model: "gpt-4o"}))
+	disabled.Mirror(&provider.ChatRequest{}, Primary{}) // must not panic
+}
+
+func TestSampleCopiesRequest(t *testing.T) {
+	svc, _ := newTestService(t, &fakeClient{}, &models.Provider{})
+	req := &provider.ChatRequest{
+		Model:         "gpt-4o",
+		Messages:      []provider.Message{{Role: "user", Content: provider.StringContent("hi")}},
+		Stream:        true,
+		StreamOptions: map[string]interface{}{"include_usage": true},
+	}
+
+	cp := svc.Sample(req)
+	require.NotNil(t, cp)
+	req.Messages = append(req.Messages[:0], provider.Message{Role: "tool"})
+
+	assert.Equal(t, "candidate-model", cp.Model)
+	assert.False(t, cp.Stream, "shadow runs non-streaming to get usage")
+	assert.Nil(t, cp.StreamOptions)
+	assert.Equal(t, "user", cp.Messages[0].Role, "primary call cannot rewrite the copy")
+
+	svc.sample = func() float64 { return 0.99 }
+	svc.cfg.SampleRate = 0.5
+	assert.Nil(t, svc.Sample(req), "outside the sample rate")
+}
+
+func TestMirrorRecordsShadowResult(t *testing.T) {
+	require.NoError(t, crypto.Initialize("0123456789abcdef0123456789abcdef"))
+	client := &fakeClient{resp: &provider.ChatResponse{
+		Choices: []provider.Choice{{Message: provider.Message{Content: provider.StringContent("shadow answer")}}},
+		Usage:   provider.Usage{PromptTokens: 1000, CompletionTokens: 500},
+	}}
+	shadowProvider := &models.Provider{BaseModel: models.BaseModel{ID: uuid.New()}}
+	svc, repo := newTestService(t, client, shadowProvider)
+
+	primary := Primary{UsageLogID: uuid.New(), ProviderID: uuid.New(), Model: "gpt-4o", Latency: 1500 * time.Millisecond, Response: "primary answer"}
+	svc.Mirror(svc.Sample(&provider.ChatRequest{Model: "gpt-4o"}), primary)
+
+	var log *models.ShadowLog
+	select {
+	case log = <-repo.logs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shadow log not recorded")
+	}
+	assert.Equal(t, primary.UsageLogID, log.UsageLogID)
+	assert.Equal(t, shadowProvider.ID, log.ShadowProviderID)
+	assert.Equal(t, "candidate-model", log.ShadowModelName)
+	assert.Equal(t, int64(1500), log.PrimaryLatency)
+	assert.NotContains(t, log.PrimaryResponse, "primary answer", "responses are stored encrypted")
+	assert.NotContains(t, log.ShadowResponse, "shadow answer", "responses are stored encrypted")
+	primaryText, err := crypto.Decrypt(log.PrimaryResponse)
+	require.NoError(t, err)
+	assert.Equal(t, "primary answer", primaryText)
+	shadowText, err := crypto.Decrypt(log.ShadowResponse)
+	require.NoError(t, err)
+	assert.Equal(t, "shadow answer", shadowText)
+	assert.Equal(t, http.StatusOK, log.StatusCode)
+	assert.InDelta(t, 0.02, log.Cost, 1e-9)
+}
+
+func TestMirrorRecordsShadowFailure(t *testing.T) {
+	client := &fakeClient{err: errors.New("upstream 500")}
+	svc, repo := newTestService(t, client, &models.Provider{BaseModel: models.BaseModel{ID: uuid.New()}})
+
+	svc.run(svc.Sample(&provider.ChatRequest{Model: "gpt-4o"}), Primary{ProviderID: uuid.New()})
+
+	log := <-repo.logs
+	assert.Equal(t, http.StatusBadGateway, log.StatusCode)
+	assert.Contains(t, log.ErrorMessage, "upstream 500")
+}
+
+func TestMirrorSkipsWhenShadowIsPrimary(t *testing.T) {
+	client := &fakeClient{}
+	id := uuid.New()
+	svc, repo := newTestService(t, client, &models.Provider{BaseModel: models.BaseModel{ID: id}})
+
+	svc.run(svc.Sample(&provider.ChatRequest{Model: "gpt-4o"}), Primary{ProviderID: id})
+
+	assert.Nil(t, client.got)
+	assert.Empty(t, repo.logs)
+}
+
+func TestMirrorDropsWhenSaturated(t *testing.T) {
+	svc, repo := newTestService(t, &fakeClient{}, &models.Provider{BaseModel: models.BaseModel{ID: uuid.New()}})
+	for i := 0; i < maxInFlight; i++ {
+		svc.inFlight <- struct{}{}
+	}
+
+	svc.Mirror(svc.Sample(&provider.ChatRequest{Model: "gpt-4o"}), Primary{ProviderID: uuid.New()})
+
+	assert.Len(t, svc.inFlight, maxInFlight)
+	assert.Empty(t, repo.logs)
+}
@@ -7,6 +7,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"math"
+	"net"
+	"sort"
 	"strings"
 	"time"
 
@@ -112,7 +115,10 @@ func (s *Service) Register(ctx context.Context, email, password, name string) (*
 		return nil, err
 	}
 
-	existing, _ := s.userRepo.GetByEmail(ctx, email)
+	existing, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
 	if existing != nil {
 		return nil, errors.New("registration failed") // generic to prevent user enumeration
 	}
@@ -140,9 +146,12 @@ func (s *Service) Register(ctx context.Context, email, password, name string) (*
 // Authenticate validates user credentials and returns the user.
 func (s *Service) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
 	user, err := s.userRepo.GetByEmail(ctx, email)
-	if err != nil {
+	if errors.Is(err, repository.ErrNotFound) {
 		return nil, errors.New("account not found")
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	if !user.IsActive {
 		return nil, errors.New("invalid credentials") // Generic to prevent user enumeration
@@ -260,6 +269,12 @@ func (s *Service) CountActiveUsers(ctx context.Context, since time.Time) (int64,
 	return s.userRepo.CountActiveUsers(ctx, since)
 }
 
+// CountActiveOrgUsers returns users who made API calls in an organization
+// since a given time.
+func (s *Service) CountActiveOrgUsers(ctx context.Context, orgID uuid.UUID, since time.Time) (int64, error) {
+	return s.userRepo.CountActiveUsersInOrg(ctx, orgID, since)
+}
+
 // UpdateQuota updates a user's quota limits (admin only).
 func (s *Service) UpdateQuota(ctx context.Context, id uuid.UUID, tokenLimit *int64, budgetLimit *float64) (*models.User, error) {
 	user, err := s.userRepo.GetByID(ctx, id)
@@ -347,6 +362,7 @@ func (s *Service) CreateAPIKey(ctx context.Context, userID uuid.UUID, projectID
 		tl = *tokenLimit
 	}
 
+	expiresAt := time.Now().AddDate(1, 0, 0) // M5: default 1-year expiry
 	apiKey := &models.APIKey{
 		UserID:     userID,
 		ProjectID:  projectID,
@@ -358,7 +374,7 @@ func (s *Service) CreateAPIKey(ctx context.Context, userID uuid.UUID, projectID
 		RateLimit:  rl,
 		TokenLimit: int64(tl),
 		DailyLimit: 10000,
-		ExpiresAt:  time.Now().AddDate(1, 0, 0), // M5: default 1-year expiry
+		ExpiresAt:  &expiresAt,
 	}
 
 	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
@@ -398,6 +414,164 @@ func (s *Service) UpdateAPIKey(ctx context.Context, keyID uuid.UUID, name *strin
 	return key, nil
 }
 
+// SetAPIKeyAllowedCIDRs replaces the source-IP allowlist of an API key.
+// Bare IPs are stored as single-host CIDRs; an empty list removes the restriction.
+func (s *Service) SetAPIKeyAllowedCIDRs(ctx context.Context, keyID uuid.UUID, cidrs []string) (*models.APIKey, error) {
+	normalized, err := NormalizeCIDRs(cidrs)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	key.AllowedCIDRs = normalized
+	if err := s.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// NormalizeCIDRs validates a list of CIDRs or bare IPs and returns them in
+// canonical CIDR form, dropping blanks and duplicates.
+func NormalizeCIDRs(entries []string) (models.StringArray, error) {
+	out := models.StringArray{}
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if !strings.Contains(e, "/") {
+			ip := net.ParseIP(e)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP or CIDR: %q", e)
+			}
+			if ip.To4() != nil {
+				e += "/32"
+			} else {
+				e += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP or CIDR: %q", e)
+		}
+		if cidr := ipNet.String(); !seen[cidr] {
+			seen[cidr] = true
+			out = append(out, cidr)
+		}
+	}
+	return out, nil
+}
+
+// maxSpendThresholds caps the spend thresholds configurable on one API key.
+const maxSpendThresholds = 10
+
+// SetAPIKeySpendAlerts replaces the spend thresholds of an API key and the
+// webhook they notify. An empty threshold list disables spend alerts.
+// The caller is responsible for validating webhookURL against SSRF rules.
+func (s *Service) SetAPIKeySpendAlerts(ctx context.Context, keyID uuid.UUID, thresholds []float64, webhookURL string) (*models.APIKey, error) {
+	normalized, err := NormalizeSpendThresholds(thresholds)
+	if err != nil {
+		return nil, err
+	}
+	webhookURL = strings.TrimSpace(webhookURL)
+	if len(normalized) > 0 && webhookURL == "" {
+		return nil, errors.New("a webhook URL is required when spend thresholds are set")
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	key.SpendThresholds = normalized
+	key.SpendWebhookURL = webhookURL
+	if err := s.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SetAPIKeyMaxRequestCost sets the worst-case USD cost a single request made
+// with the key may reach. Zero removes the limit.
+func (s *Service) SetAPIKeyMaxRequestCost(ctx context.Context, keyID uuid.UUID, maxCostUSD float64) (*models.APIKey, error) {
+	if maxCostUSD < 0 || math.IsNaN(maxCostUSD) || math.IsInf(maxCostUSD, 0) {
+		return nil, fmt.Errorf("invalid max request cost: %v", maxCostUSD)
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	key.MaxRequestCostUSD = maxCostUSD
+	if err := s.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SetAPIKeyMessageLimits overrides the server-wide limits on messages per
+// chat request and characters per message for the key. Zero keeps the server
+// limit and -1 removes the limit.
+func (s *Service) SetAPIKeyMessageLimits(ctx context.Context, keyID uuid.UUID, maxMessages, maxMessageChars int) (*models.APIKey, error) {
+	if maxMessages < -1 || maxMessageChars < -1 {
+		return nil, fmt.Errorf("invalid message limits: %d, %d", maxMessages, maxMessageChars)
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	key.MaxMessages = maxMessages
+	key.MaxMessageChars = maxMessageChars
+	if err := s.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SetAPIKeyStreamBudgetCutoff turns mid-stream budget enforcement on or off
+// for the key.
+func (s *Service) SetAPIKeyStreamBudgetCutoff(ctx context.Context, keyID uuid.UUID, enabled bool) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	key.StreamBudgetCutoff = enabled
+	if err := s.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// NormalizeSpendThresholds validates USD spend thresholds and returns them
+// sorted ascending with duplicates removed.
+func NormalizeSpendThresholds(thresholds []float64) (models.Float64Array, error) {
+	out := models.Float64Array{}
+	seen := make(map[float64]bool, len(thresholds))
+	for _, t := range thresholds {
+		if t <= 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, fmt.Errorf("invalid spend threshold: %v", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	if len(out) > maxSpendThresholds {
+		return nil, fmt.Errorf("at most %d spend thresholds are allowed", maxSpendThresholds)
+	}
+	sort.Float64s(out)
+	return out, nil
+}
+
 // GetAPIKeys returns all API keys for a project.
 func (s *Service) GetAPIKeys(ctx context.Context, projectID uuid.UUID) ([]models.APIKey, error) {
 	return s.apiKeyRepo.GetByProjectID(ctx, projectID)
@@ -430,7 +604,7 @@ func (s *Service) ValidateAPIKey(ctx context.Context, rawKey string) (*models.Pr
 		return nil, nil, errors.New("API key is disabled")
 	}
 
-	if apiKey.ExpiresAt.Before(time.Now()) && !apiKey.ExpiresAt.IsZero() {
+	if apiKey.IsExpired(time.Now()) {
 		return nil, nil, errors.New("API key has expired")
 	}
 
@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -143,7 +144,17 @@ func TestAPIKeyWithExpiration(t *testing.T) {
 		IsActive: true,
 	}
 
-	assert.True(t, apiKey.ExpiresAt.IsZero())
+	assert.Nil(t, apiKey.ExpiresAt, "no expiry is explicit")
+	assert.False(t, apiKey.IsExpired(time.Now()), "keys without an expiry never expire")
+
+	past := time.Now().Add(-time.Minute)
+	apiKey.ExpiresAt = &past
+	assert.True(t, apiKey.IsExpired(time.Now()))
+
+	future := time.Now().Add(time.Hour)
+	apiKey.ExpiresAt = &future
+	assert.False(t, apiKey.IsExpired(time.Now()))
+	assert.True(t, apiKey.IsExpired(future), "a key is expired from its expiry time on")
 }
 
 func TestMultipleAPIKeys(t *testing.T) {
@@ -169,3 +180,32 @@ func TestPasswordHashLength(t *testing.T) {
 
 	assert.True(t, len(hash) >= 60)
 }
+
+func TestNormalizeCIDRs(t *testing.T) {
+	cidrs, err := NormalizeCIDRs([]string{" 10.1.2.3 ", "192.168.0.0/16", "", "2001:db8::1", "10.1.2.3/32", "172.16.5.4/12"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StringArray{"10.1.2.3/32", "192.168.0.0/16", "2001:db8::1/128", "172.16.0.0/12"}, cidrs)
+
+	_, err = NormalizeCIDRs([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = NormalizeCIDRs([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
+
+func TestNormalizeSpendThresholds(t *testing.T) {
+	got, err := NormalizeSpendThresholds([]float64{50, 10, 50, 100.5})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Float64Array{10, 50, 100.5}, got)
+
+	_, err = NormalizeSpendThresholds([]float64{10, 0})
+	assert.Error(t, err)
+	_, err = NormalizeSpendThresholds([]float64{-5})
+	assert.Error(t, err)
+
+	many := make([]float64, maxSpendThresholds+1)
+	for i := range many {
+		many[i] = float64(i + 1)
+	}
+	_, err = NormalizeSpendThresholds(many)
+	assert.Error(t, err)
+}
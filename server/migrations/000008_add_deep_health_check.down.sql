@@ -0,0 +1,3 @@
+ALTER TABLE health_histories DROP COLUMN IF EXISTS check_mode;
+ALTER TABLE providers DROP COLUMN IF EXISTS health_check_model;
+ALTER TABLE providers DROP COLUMN IF EXISTS deep_health_check;
@@ -0,0 +1,4 @@
+-- Migration 000008: Opt-in deep (1-token completion) provider health checks
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS deep_health_check BOOLEAN DEFAULT false;
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS health_check_model TEXT;
+ALTER TABLE health_histories ADD COLUMN IF NOT EXISTS check_mode TEXT DEFAULT 'shallow';
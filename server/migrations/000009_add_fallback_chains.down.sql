@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.fallback_chains;
@@ -0,0 +1,16 @@
+-- Migration 000009: Explicit per-model-pattern provider fallback chains
+CREATE TABLE IF NOT EXISTS public.fallback_chains (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name character varying(255) NOT NULL,
+    description text,
+    model_pattern character varying(255) NOT NULL,
+    provider_ids jsonb DEFAULT '[]'::jsonb NOT NULL,
+    priority integer DEFAULT 0 NOT NULL,
+    is_enabled boolean DEFAULT true NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    CONSTRAINT fallback_chains_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fallback_chains_deleted_at ON public.fallback_chains USING btree (deleted_at);
@@ -0,0 +1 @@
+ALTER TABLE usage_logs DROP COLUMN IF EXISTS provider_forced;
@@ -0,0 +1,2 @@
+-- Migration 000010: Record providers pinned via the X-LLM-Provider header
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS provider_forced BOOLEAN DEFAULT false;
@@ -0,0 +1,2 @@
+-- Migration 000011: Record streaming requests served by a non-streaming fallback
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS stream_downgraded BOOLEAN DEFAULT false;
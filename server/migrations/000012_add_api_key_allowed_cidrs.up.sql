@@ -0,0 +1,2 @@
+-- Migration 000012: Per-API-key source IP allowlist
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS allowed_cidrs JSONB NOT NULL DEFAULT '[]';
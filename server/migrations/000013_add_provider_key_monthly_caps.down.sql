@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_usage_logs_provider_key_id;
+ALTER TABLE usage_logs DROP COLUMN IF EXISTS provider_key_id;
+ALTER TABLE provider_api_keys DROP COLUMN IF EXISTS monthly_budget_usd;
+ALTER TABLE provider_api_keys DROP COLUMN IF EXISTS monthly_request_limit;
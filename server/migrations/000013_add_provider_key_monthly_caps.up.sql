@@ -0,0 +1,7 @@
+-- Migration 000013: Monthly request/cost caps per provider API key
+ALTER TABLE provider_api_keys ADD COLUMN IF NOT EXISTS monthly_request_limit BIGINT DEFAULT 0;
+ALTER TABLE provider_api_keys ADD COLUMN IF NOT EXISTS monthly_budget_usd DOUBLE PRECISION DEFAULT 0;
+
+-- Attribute usage to the provider key that served it
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS provider_key_id UUID;
+CREATE INDEX IF NOT EXISTS idx_usage_logs_provider_key_id ON usage_logs(provider_key_id);
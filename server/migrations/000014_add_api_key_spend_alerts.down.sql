@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS api_key_spend_alerts;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS spend_webhook_url;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS spend_thresholds;
@@ -0,0 +1,15 @@
+-- Migration 000014: Per-API-key spend threshold webhooks
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS spend_thresholds JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS spend_webhook_url TEXT NOT NULL DEFAULT '';
+
+-- Thresholds already notified per billing period (YYYY-MM)
+CREATE TABLE IF NOT EXISTS api_key_spend_alerts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    api_key_id UUID NOT NULL,
+    period VARCHAR(7) NOT NULL,
+    threshold_usd DECIMAL(20,4) NOT NULL,
+    spend_usd DECIMAL(20,6) NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_spend_alerts_key_period_threshold
+    ON api_key_spend_alerts(api_key_id, period, threshold_usd);
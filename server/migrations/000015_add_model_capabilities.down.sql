@@ -0,0 +1,4 @@
+ALTER TABLE models DROP COLUMN IF EXISTS supports_tools;
+ALTER TABLE models DROP COLUMN IF EXISTS supports_vision;
+ALTER TABLE models DROP COLUMN IF EXISTS supports_streaming;
+ALTER TABLE models DROP COLUMN IF EXISTS context_window;
@@ -0,0 +1,5 @@
+-- Migration 000015: Context window and capability flags on models, editable by admins
+ALTER TABLE models ADD COLUMN IF NOT EXISTS context_window INTEGER DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS supports_streaming BOOLEAN DEFAULT true;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS supports_vision BOOLEAN DEFAULT false;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS supports_tools BOOLEAN DEFAULT false;
@@ -0,0 +1,23 @@
+-- Migration 000016: Shadow traffic comparison logs
+CREATE TABLE IF NOT EXISTS shadow_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    usage_log_id UUID,
+    primary_provider_id UUID,
+    shadow_provider_id UUID,
+    model_name VARCHAR(255),
+    shadow_model_name VARCHAR(255),
+    streamed BOOLEAN DEFAULT false,
+    primary_latency BIGINT DEFAULT 0,
+    primary_response TEXT,
+    shadow_latency BIGINT DEFAULT 0,
+    prompt_tokens INTEGER DEFAULT 0,
+    completion_tokens INTEGER DEFAULT 0,
+    cost DECIMAL(20,6) DEFAULT 0,
+    shadow_response TEXT,
+    status_code INTEGER DEFAULT 0,
+    error_message TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_shadow_logs_created_at ON shadow_logs(created_at);
+CREATE INDEX IF NOT EXISTS idx_shadow_logs_usage_log_id ON shadow_logs(usage_log_id);
+CREATE INDEX IF NOT EXISTS idx_shadow_logs_shadow_provider_id ON shadow_logs(shadow_provider_id);
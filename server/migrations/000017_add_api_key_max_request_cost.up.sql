@@ -0,0 +1,2 @@
+-- Migration 000017: Per-API-key worst-case cost limit per request (0 = unlimited)
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS max_request_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE providers DROP COLUMN IF EXISTS max_output_tokens;
+ALTER TABLE providers DROP COLUMN IF EXISTS default_max_tokens;
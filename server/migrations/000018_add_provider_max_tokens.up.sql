@@ -0,0 +1,3 @@
+-- Migration 000018: Per-provider output token default and ceiling (0 = none)
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS default_max_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS max_output_tokens INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+-- Migration 000019: Per-model routing overrides (model -> provider + optional key)
+CREATE TABLE IF NOT EXISTS model_route_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    model_name VARCHAR(255) NOT NULL,
+    provider_id UUID NOT NULL,
+    api_key_id UUID,
+    description TEXT,
+    is_enabled BOOLEAN NOT NULL DEFAULT true,
+    allow_fallback BOOLEAN NOT NULL DEFAULT false
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_model_route_overrides_model_name ON model_route_overrides(model_name);
+CREATE INDEX IF NOT EXISTS idx_model_route_overrides_provider_id ON model_route_overrides(provider_id);
+CREATE INDEX IF NOT EXISTS idx_model_route_overrides_deleted_at ON model_route_overrides(deleted_at);
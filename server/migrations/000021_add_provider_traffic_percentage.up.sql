@@ -0,0 +1,2 @@
+-- Migration 000021: Per-provider canary traffic cap (0 or 100 = uncapped)
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS traffic_percentage INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE providers DROP COLUMN IF EXISTS tls_ca_bundle_path;
+ALTER TABLE providers DROP COLUMN IF EXISTS tls_min_version;
+ALTER TABLE providers DROP COLUMN IF EXISTS tls_insecure_skip_verify;
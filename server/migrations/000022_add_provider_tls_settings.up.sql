@@ -0,0 +1,4 @@
+-- Migration 000022: Per-provider TLS settings for self-hosted endpoints
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS tls_insecure_skip_verify BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS tls_min_version VARCHAR(8) NOT NULL DEFAULT '';
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS tls_ca_bundle_path TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE usage_logs DROP COLUMN IF EXISTS cache_read_tokens;
+ALTER TABLE usage_logs DROP COLUMN IF EXISTS cache_creation_tokens;
+ALTER TABLE providers DROP COLUMN IF EXISTS prompt_caching;
@@ -0,0 +1,4 @@
+-- Migration 000023: Anthropic prompt caching toggle and cache token usage
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS prompt_caching BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS cache_creation_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS cache_read_tokens INTEGER NOT NULL DEFAULT 0;
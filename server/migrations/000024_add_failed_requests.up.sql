@@ -0,0 +1,17 @@
+-- Migration 000024: Dead-letter log of chat requests that failed on every provider
+CREATE TABLE IF NOT EXISTS failed_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    request_id VARCHAR(64),
+    project_id UUID,
+    api_key_id UUID,
+    model_name VARCHAR(255),
+    stream BOOLEAN NOT NULL DEFAULT FALSE,
+    attempt_count INTEGER NOT NULL DEFAULT 0,
+    attempts JSONB,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_failed_requests_created_at ON failed_requests(created_at);
+CREATE INDEX IF NOT EXISTS idx_failed_requests_project_id ON failed_requests(project_id);
+CREATE INDEX IF NOT EXISTS idx_failed_requests_model_name ON failed_requests(model_name);
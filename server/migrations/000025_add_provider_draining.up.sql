@@ -0,0 +1,2 @@
+-- Migration 000025: provider drain state for maintenance
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS draining BOOLEAN NOT NULL DEFAULT FALSE;
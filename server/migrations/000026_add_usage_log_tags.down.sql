@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_usage_logs_tags;
+ALTER TABLE usage_logs DROP COLUMN IF EXISTS tags;
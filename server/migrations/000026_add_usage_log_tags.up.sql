@@ -0,0 +1,3 @@
+-- Migration 000026: client-supplied attribution tags on usage logs
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS tags JSONB;
+CREATE INDEX IF NOT EXISTS idx_usage_logs_tags ON usage_logs USING GIN (tags);
@@ -0,0 +1,2 @@
+ALTER TABLE api_keys DROP COLUMN IF EXISTS max_message_chars;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS max_messages;
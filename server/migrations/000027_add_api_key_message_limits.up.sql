@@ -0,0 +1,3 @@
+-- Migration 000027: Per-API-key overrides of the message count and length limits (0 = server default, -1 = unlimited)
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS max_messages INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS max_message_chars INTEGER NOT NULL DEFAULT 0;
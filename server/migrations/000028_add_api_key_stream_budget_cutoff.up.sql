@@ -0,0 +1,2 @@
+-- Migration 000028: Opt-in per-API-key cutoff of streamed responses that exceed the key's cost cap or the organization's budget
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS stream_budget_cutoff BOOLEAN NOT NULL DEFAULT false;
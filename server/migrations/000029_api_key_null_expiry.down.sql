@@ -0,0 +1 @@
+UPDATE api_keys SET expires_at = '0001-01-01 00:00:00+00' WHERE expires_at IS NULL;
@@ -0,0 +1,2 @@
+-- Migration 000029: API keys without an expiry store NULL rather than the zero timestamp
+UPDATE api_keys SET expires_at = NULL WHERE expires_at < '0002-01-01';
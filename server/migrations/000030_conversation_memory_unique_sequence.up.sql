@@ -0,0 +1,27 @@
+-- Migration 000030: one message per sequence number in a conversation
+-- Concurrent writes to a conversation could store the same sequence twice.
+
+-- Tables created from the SQL migrations alone predate project/API key scoping.
+ALTER TABLE conversation_memories ADD COLUMN IF NOT EXISTS project_id UUID;
+ALTER TABLE conversation_memories ADD COLUMN IF NOT EXISTS api_key_id UUID;
+
+-- Renumber conversations holding duplicates, keeping their order.
+WITH counted AS (
+    SELECT id, project_id, conversation_id, sequence, created_at,
+           COUNT(*) OVER (PARTITION BY project_id, api_key_id, conversation_id, sequence) AS copies
+    FROM conversation_memories
+    WHERE deleted_at IS NULL
+), renumbered AS (
+    SELECT id,
+           ROW_NUMBER() OVER (PARTITION BY project_id, conversation_id ORDER BY sequence, created_at, id) AS seq,
+           MAX(copies) OVER (PARTITION BY project_id, conversation_id) AS max_copies
+    FROM counted
+)
+UPDATE conversation_memories m
+SET sequence = r.seq
+FROM renumbered r
+WHERE m.id = r.id AND r.max_copies > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversation_memories_sequence
+    ON conversation_memories (project_id, conversation_id, COALESCE(api_key_id, '00000000-0000-0000-0000-000000000000'::uuid), sequence)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE failed_requests DROP COLUMN IF EXISTS error_bodies;
+ALTER TABLE failed_requests DROP COLUMN IF EXISTS payload;
+ALTER TABLE failed_requests DROP COLUMN IF EXISTS captured;
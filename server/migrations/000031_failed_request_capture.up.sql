@@ -0,0 +1,4 @@
+-- Migration 000031: Encrypted request payload and upstream error bodies of failed requests (capture mode)
+ALTER TABLE failed_requests ADD COLUMN IF NOT EXISTS captured BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE failed_requests ADD COLUMN IF NOT EXISTS payload TEXT;
+ALTER TABLE failed_requests ADD COLUMN IF NOT EXISTS error_bodies TEXT;
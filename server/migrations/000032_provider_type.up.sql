@@ -0,0 +1,8 @@
+-- Migration 000032: Provider type (client protocol) separate from the provider name
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS type VARCHAR(50) NOT NULL DEFAULT '';
+
+-- Providers named after a built-in type keep that type; any other name was
+-- served by the OpenAI client, so it keeps that behavior explicitly.
+UPDATE providers SET type = name
+WHERE type = '' AND name IN ('openai', 'anthropic', 'google', 'ollama', 'lmstudio', 'deepseek', 'mistral', 'vllm');
+UPDATE providers SET type = 'openai-compatible' WHERE type = '';
@@ -34,6 +34,7 @@ const alertTypeLabels: Record<string, string> = {
   circuit_open: 'Circuit Breaker Open',
   sla_breach: 'SLA Breach',
   health_check_failed: 'Health Check Failed',
+  provider_failover: 'Provider Failover',
 };
 
 const targetTypeLabels: Record<string, string> = {
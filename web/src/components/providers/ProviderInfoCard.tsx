@@ -49,6 +49,11 @@ export default function ProviderInfoCard({
             >
               {provider.is_active ? 'Enabled' : 'Disabled'}
             </span>
+            {provider.is_active && provider.draining && (
+              <span className="badge-warning" title="No new requests are routed here; health checks continue">
+                Draining
+              </span>
+            )}
             <span className="text-sm text-apple-gray-500">
               Timeout: {provider.timeout}s
             </span>
@@ -53,7 +53,7 @@ export default function ProviderList({
                   ? 'hover:bg-white/20'
                   : 'hover:bg-apple-gray-200'
                   }`}
-                title={provider.is_active ? 'Disable provider' : 'Enable provider'}
+                title={provider.is_active ? (provider.draining ? 'Draining — disable provider' : 'Disable provider') : 'Enable provider'}
               >
                 {provider.is_active ? (
                   <CheckCircleIcon
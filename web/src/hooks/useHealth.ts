@@ -39,7 +39,8 @@ function mapProviderHealth(d: any) {
     id: d.id, name: d.name, base_url: d.baseUrl,
     is_active: d.isActive, is_healthy: d.isHealthy, use_proxy: d.useProxy,
     response_time: d.responseTime, last_check: d.lastCheck, success_rate: d.successRate,
-    error_message: d.errorMessage,
+    error_message: d.errorMessage, avg_latency_ms: d.avgLatencyMs ?? 0,
+    latency_threshold_ms: d.latencyThresholdMs ?? 0, latency_sla_breached: d.latencySlaBreached ?? false,
   };
 }
 function mapAlert(d: any) {
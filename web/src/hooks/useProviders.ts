@@ -22,11 +22,11 @@ import {
 // Map GraphQL camelCase → snake_case for backward compat
 function mapProvider(d: any): Provider {
   return {
-    id: d.id, name: d.name, base_url: d.baseUrl,
+    id: d.id, name: d.name, type: d.type, base_url: d.baseUrl,
     is_active: d.isActive, priority: d.priority, weight: d.weight,
     max_retries: d.maxRetries, timeout: d.timeout, use_proxy: d.useProxy,
     default_proxy_id: d.defaultProxyId, requires_api_key: d.requiresApiKey,
-    created_at: d.createdAt,
+    draining: d.draining, created_at: d.createdAt,
   };
 }
 function mapApiKey(d: any): ProviderApiKey {
@@ -115,11 +115,12 @@ export function useProviders() {
     finally { setSavingProxy(false); }
   }, [selectedProvider, updateProviderMut, refetchProviders]);
 
-  const handleCreateProvider = useCallback(async (data: { name: string; baseUrl: string; requiresApiKey?: boolean }) => {
+  const handleCreateProvider = useCallback(async (data: { name: string; type: string; baseUrl: string; requiresApiKey?: boolean }) => {
     const { data: result } = await createProviderMut({
       variables: {
         input: {
           name: data.name,
+          type: data.type,
           baseUrl: data.baseUrl,
           requiresApiKey: data.requiresApiKey ?? true,
         },
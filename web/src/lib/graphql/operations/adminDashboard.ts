@@ -27,6 +27,11 @@ export const ADMIN_DASHBOARD_QUERY = gql`
       apiKeysHealthy
       mcpCallCount
       mcpErrorCount
+      misconfiguredProviders {
+        id
+        name
+        issue
+      }
     }
     usageChart(days: 7) {
       date
@@ -41,6 +46,7 @@ export const ADMIN_DASHBOARD_QUERY = gql`
       totalCost
       successRate
       avgLatencyMs
+      trafficShare
     }
     modelStats {
       modelName
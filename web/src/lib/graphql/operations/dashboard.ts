@@ -40,6 +40,7 @@ export const DASHBOARD_QUERY = gql`
       totalCost
       successRate
       avgLatencyMs
+      trafficShare
     }
     modelStats(projectId: $projectId, channel: $channel) {
       modelName
@@ -6,7 +6,7 @@ export const HEALTH_OVERVIEW_QUERY = gql`
   query HealthOverview {
     healthApiKeys { id providerId providerName keyPrefix isActive isHealthy lastCheck responseTime successRate }
     healthProxies { id url type region isActive isHealthy responseTime lastCheck successRate }
-    healthProviders { id name baseUrl isActive isHealthy useProxy responseTime lastCheck successRate errorMessage }
+    healthProviders { id name baseUrl isActive isHealthy useProxy responseTime lastCheck successRate errorMessage avgLatencyMs latencyThresholdMs latencySlaBreached }
     healthHistory { id targetType targetId status message createdAt }
   }
 `;
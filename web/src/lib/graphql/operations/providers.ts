@@ -5,7 +5,7 @@ import { gql } from '@apollo/client';
 export const PROVIDERS_QUERY = gql`
   query Providers {
     providers {
-      id name baseUrl isActive priority weight maxRetries timeout useProxy requiresApiKey createdAt
+      id name type baseUrl isActive priority weight maxRetries timeout useProxy requiresApiKey draining createdAt
     }
   }
 `;
@@ -13,7 +13,7 @@ export const PROVIDERS_QUERY = gql`
 export const CREATE_PROVIDER = gql`
   mutation CreateProvider($input: CreateProviderInput!) {
     createProvider(input: $input) {
-      id name baseUrl isActive priority weight maxRetries timeout useProxy requiresApiKey createdAt
+      id name type baseUrl isActive priority weight maxRetries timeout useProxy requiresApiKey createdAt
     }
   }
 `;
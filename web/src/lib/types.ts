@@ -160,6 +160,9 @@ export interface ProviderHealth {
   last_check: string;
   success_rate: number;
   error_message?: string;
+  avg_latency_ms: number;
+  latency_threshold_ms: number;
+  latency_sla_breached: boolean;
 }
 
 export interface Alert {
@@ -187,6 +190,7 @@ export interface AlertConfig {
 export interface Provider {
   id: string;
   name: string;
+  type?: string; // protocol selecting the client, e.g. anthropic or openai-compatible
   base_url: string;
   is_active: boolean;
   priority: number;
@@ -196,6 +200,7 @@ export interface Provider {
   use_proxy: boolean;
   default_proxy_id?: string | null;
   requires_api_key: boolean;
+  draining?: boolean;
   created_at: string;
 }
 
@@ -16,6 +16,7 @@ import {
   UsersIcon,
   BanknotesIcon,
   ArrowPathIcon,
+  ExclamationTriangleIcon,
 } from '@heroicons/react/24/outline';
 import {
   LineChart,
@@ -133,6 +134,7 @@ function DashboardPage() {
   const chartData = data?.usageChart || [];
   const providerStats = data?.providerStats || [];
   const modelStats = data?.modelStats || [];
+  const misconfigured: any[] = d?.misconfiguredProviders || [];
 
   if (loading && !d) {
     return (
@@ -168,6 +170,23 @@ function DashboardPage() {
         </div>
       </div>
 
+      {/* Misconfiguration warnings */}
+      {misconfigured.length > 0 && (
+        <motion.div initial={{ opacity: 0, y: 10 }} animate={{ opacity: 1, y: 0 }} className="card border border-orange-200 bg-orange-50">
+          <div className="flex items-start gap-3">
+            <ExclamationTriangleIcon className="w-6 h-6 text-apple-orange flex-shrink-0" />
+            <div>
+              <h2 className="text-base font-semibold text-apple-gray-900">{t('admin.dashboard.misconfigured_title')}</h2>
+              <ul className="mt-1 space-y-1 text-sm text-apple-gray-700">
+                {misconfigured.map((p) => (
+                  <li key={p.id}>{t('admin.dashboard.misconfigured_no_keys', { name: p.name })}</li>
+                ))}
+              </ul>
+            </div>
+          </div>
+        </motion.div>
+      )}
+
       {/* Row 1: Platform KPIs */}
       <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-4 gap-6">
         <StatCard title={t('admin.dashboard.total_users')} value={fmtNum(d?.totalUsers || 0)} subtitle={`${d?.activeUsersToday || 0} ${t('admin.dashboard.active_today')}`} icon={UsersIcon} color="indigo" />
@@ -276,7 +295,7 @@ function DashboardPage() {
                   </div>
                   <div className="flex items-center gap-4">
                     <div className="text-right">
-                      <p className="text-sm font-medium text-apple-gray-900">{fmtNum(p.requests)} req</p>
+                      <p className="text-sm font-medium text-apple-gray-900">{fmtNum(p.requests)} req · {(p.trafficShare ?? 0).toFixed(1)}%</p>
                       <p className="text-xs text-apple-gray-500">{fmtCurrency(p.totalCost)}</p>
                     </div>
                     <div className={`px-2 py-0.5 rounded text-xs font-medium ${p.successRate >= 95 ? 'bg-green-100 text-apple-green' : p.successRate >= 80 ? 'bg-orange-100 text-apple-orange' : 'bg-red-100 text-apple-red'}`}>
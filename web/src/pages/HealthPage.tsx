@@ -126,6 +126,14 @@ function HealthPage() {
                       <p className="text-sm font-medium text-apple-gray-900">{provider.response_time > 0 ? `${provider.response_time}ms` : '-'}</p>
                       <p className="text-xs text-apple-gray-500">Latency</p>
                     </div>
+                    {provider.latency_threshold_ms > 0 && (
+                      <div className="text-right">
+                        <p className={`text-sm font-medium ${provider.latency_sla_breached ? 'text-apple-red' : 'text-apple-gray-900'}`}>
+                          {provider.avg_latency_ms > 0 ? `${provider.avg_latency_ms}ms` : '-'} / {provider.latency_threshold_ms}ms
+                        </p>
+                        <p className="text-xs text-apple-gray-500">Avg vs SLA</p>
+                      </div>
+                    )}
                     <div className="text-right">
                       <p className="text-sm font-medium text-apple-gray-900">{(provider.success_rate * 100).toFixed(1)}%</p>
                       <p className="text-xs text-apple-gray-500">Success rate</p>
@@ -23,6 +23,9 @@ const PROVIDER_PRESETS: { name: string; label: string; baseUrl: string; requires
   { name: 'vllm',      label: 'vLLM',           baseUrl: 'http://localhost:8000/v1',         requiresApiKey: false },
 ];
 
+/** Custom providers speak the OpenAI API; presets use their own client */
+const CUSTOM_PROVIDER_TYPE = 'openai-compatible';
+
 /** Generate a unique provider name by appending a numeric suffix if needed */
 function uniqueProviderName(baseName: string, existingNames: string[]): string {
   if (!existingNames.includes(baseName)) return baseName;
@@ -31,11 +34,12 @@ function uniqueProviderName(baseName: string, existingNames: string[]): string {
   return `${baseName}-${i}`;
 }
 
-/** Known local-inference provider types (matched by prefix) */
+/** Known local-inference provider types */
 const LOCAL_PROVIDER_TYPES = ['ollama', 'lmstudio', 'vllm'];
 
-function isLocalProviderType(name: string): boolean {
-  return LOCAL_PROVIDER_TYPES.some(t => name === t || name.startsWith(`${t}-`));
+function isLocalProviderType(provider: { name: string; type?: string }): boolean {
+  if (provider.type) return LOCAL_PROVIDER_TYPES.includes(provider.type);
+  return LOCAL_PROVIDER_TYPES.some(t => provider.name === t || provider.name.startsWith(`${t}-`));
 }
 
 function AddProviderModal({
@@ -46,7 +50,7 @@ function AddProviderModal({
 }: {
   open: boolean;
   onClose: () => void;
-  onSubmit: (data: { name: string; baseUrl: string; requiresApiKey: boolean }) => Promise<void>;
+  onSubmit: (data: { name: string; type: string; baseUrl: string; requiresApiKey: boolean }) => Promise<void>;
   existingNames: string[];
 }) {
   const { t } = useTranslation();
@@ -83,7 +87,12 @@ function AddProviderModal({
     if (!canSubmit) return;
     setSubmitting(true);
     try {
-      await onSubmit({ name: finalName, baseUrl: baseUrl.trim(), requiresApiKey });
+      await onSubmit({
+        name: finalName,
+        type: isCustom ? CUSTOM_PROVIDER_TYPE : selected,
+        baseUrl: baseUrl.trim(),
+        requiresApiKey,
+      });
       setSelected('');
       setCustomName('');
       setBaseUrl('');
@@ -338,7 +347,7 @@ function ProvidersPage() {
                   onDeleteProvider={handleDeleteProvider}
                 />
 
-                {isLocalProviderType(selectedProvider.name) && (
+                {isLocalProviderType(selectedProvider) && (
                   <LocalProviderCard
                     provider={selectedProvider}
                     onToggleRequiresApiKey={handleToggleRequiresApiKey}